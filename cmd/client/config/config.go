@@ -2,6 +2,7 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
@@ -16,21 +17,67 @@ const (
 	readTimeout       = 10
 	writeTimeout      = 20
 	idleTimeout       = 30
+	scoreMinVotes     = 0
+	uploadMaxSizeMB   = 20
+	uploadDirPerm     = 0o750
 )
 
 var (
 	errMissingClientHost    = errors.New("missing CLIENT_HOST in config")
 	errClientPortNotInteger = errors.New("invalid CLIENT_PORT: must be integer")
+	errUploadMaxSize        = errors.New("invalid CLIENT_UPLOAD_MAX_SIZE_MB: must be positive")
 )
 
 type Client struct {
-	Host         string
-	Port         string
-	Environment  string
-	BackendURL   string
-	TLSCertFile  string
-	TLSKeyFile   string
+	Host        string
+	Port        string
+	Environment string
+	BackendURL  string
+	TLSCertFile string
+	TLSKeyFile  string
+	// SpoilerOpen and SpoilerClose mark spoiler text in posts, e.g. "||" and
+	// "||" or ">!" and "!<". An empty SpoilerOpen turns spoilers off.
+	SpoilerOpen  string
+	SpoilerClose string
+	// CSRFSecret keys the HMAC that derives CSRF tokens. Left empty, a random
+	// key is generated at startup.
+	CSRFSecret   string
+	Uploads      Uploads
 	HTTPTimeouts HTTPTimeouts
+	// ScoreMinVotes hides a vote score from non-staff viewers until it rests
+	// on at least this many votes; 0 always shows it.
+	ScoreMinVotes int
+	// SecureCookies marks every cookie the client sets as Secure. It defaults
+	// to on in production and whenever the client serves TLS itself.
+	SecureCookies bool
+	// CommentAnchors sends the author straight to their new comment after
+	// posting it instead of to the top of the topic.
+	CommentAnchors bool
+}
+
+// Uploads says where topic images and avatars are stored and how large an
+// uploaded file may be.
+type Uploads struct {
+	Dir     string
+	MaxSize int64
+}
+
+// CheckWritable creates Dir if needed and makes sure a file can be written to
+// it, so a misconfigured directory fails at startup rather than on the first
+// upload.
+func (u Uploads) CheckWritable() error {
+	err := os.MkdirAll(u.Dir, uploadDirPerm)
+	if err != nil {
+		return fmt.Errorf("upload directory %s: %w", u.Dir, err)
+	}
+
+	probe, err := os.CreateTemp(u.Dir, ".write-check-*")
+	if err != nil {
+		return fmt.Errorf("upload directory %s is not writable: %w", u.Dir, err)
+	}
+	probe.Close()
+
+	return os.Remove(probe.Name())
 }
 
 type HTTPTimeouts struct {
@@ -59,19 +106,31 @@ func LoadClientConfig() (*Client, error) {
 		}
 	}
 
+	environment := helpers.GetEnv("CLIENT_ENVIRONMENT", envMap, "development")
+
 	client := &Client{
-		Host:        helpers.GetEnv("CLIENT_HOST", envMap, "localhost"),
-		Port:        helpers.GetEnv("CLIENT_PORT", envMap, "3001"),
-		Environment: helpers.GetEnv("CLIENT_ENVIRONMENT", envMap, "development"),
-		BackendURL:  helpers.GetEnv("BACKEND_URL", envMap, defaultBackendURL),
-		TLSCertFile: tlsCertFile,
-		TLSKeyFile:  tlsKeyFile,
+		Host:           helpers.GetEnv("CLIENT_HOST", envMap, "localhost"),
+		Port:           helpers.GetEnv("CLIENT_PORT", envMap, "3001"),
+		Environment:    environment,
+		BackendURL:     helpers.GetEnv("BACKEND_URL", envMap, defaultBackendURL),
+		TLSCertFile:    tlsCertFile,
+		TLSKeyFile:     tlsKeyFile,
+		ScoreMinVotes:  helpers.GetEnvInt("CLIENT_SCORE_MIN_VOTES", envMap, scoreMinVotes),
+		SpoilerOpen:    helpers.GetEnv("CLIENT_SPOILER_OPEN", envMap, "||"),
+		SpoilerClose:   helpers.GetEnv("CLIENT_SPOILER_CLOSE", envMap, "||"),
+		CommentAnchors: helpers.GetEnvBool("CLIENT_COMMENT_ANCHORS", envMap, true),
+		CSRFSecret:     helpers.GetEnv("CLIENT_CSRF_SECRET", envMap, ""),
+		SecureCookies:  helpers.GetEnvBool("COOKIE_SECURE", envMap, environment == "production" || tlsCertFile != ""),
 		HTTPTimeouts: HTTPTimeouts{
 			ReadHeader: helpers.GetEnvDuration("CLIENT_READ_HEADER_TIMEOUT", envMap, readHeaderTimeout),
 			Read:       helpers.GetEnvDuration("CLIENT_READ_TIMEOUT", envMap, readTimeout),
 			Write:      helpers.GetEnvDuration("CLIENT_WRITE_TIMEOUT", envMap, writeTimeout),
 			Idle:       helpers.GetEnvDuration("CLIENT_IDLE_TIMEOUT", envMap, idleTimeout),
 		},
+		Uploads: Uploads{
+			Dir:     helpers.GetEnv("CLIENT_UPLOAD_DIR", envMap, "frontend/static/images/uploads"),
+			MaxSize: int64(helpers.GetEnvInt("CLIENT_UPLOAD_MAX_SIZE_MB", envMap, uploadMaxSizeMB)) << 20,
+		},
 	}
 
 	if client.Host == "" {
@@ -81,6 +140,9 @@ func LoadClientConfig() (*Client, error) {
 	if err != nil {
 		return nil, errClientPortNotInteger
 	}
+	if client.Uploads.MaxSize <= 0 {
+		return nil, errUploadMaxSize
+	}
 
 	return client, nil
 }
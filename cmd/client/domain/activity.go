@@ -11,6 +11,15 @@ type ActivityData struct {
 	UserComments     []ActivityComment     `json:"userComments"`
 }
 
+// SubmissionsData is the "my posts" page: the user's comments grouped by
+// moderation status.
+type SubmissionsData struct {
+	User     *LoggedInUser
+	Pending  []ActivityComment `json:"pending"`
+	Approved []ActivityComment `json:"approved"`
+	Rejected []ActivityComment `json:"rejected"`
+}
+
 // ActivityTopic represents a topic in the activity feed.
 type ActivityTopic struct {
 	Title     string
@@ -23,8 +32,11 @@ type ActivityComment struct {
 	Content    string
 	TopicTitle string
 	CreatedAt  string
-	ID         int
-	TopicID    int
+	Status     string
+	// RejectionReason is set on rejected submissions that were given one.
+	RejectionReason string
+	ID              int
+	TopicID         int
 }
 
 // ActivityCommentVote represents a comment the user liked/disliked.
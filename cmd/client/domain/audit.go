@@ -0,0 +1,22 @@
+package domain
+
+type AuditEntry struct {
+	AdminID       string `json:"adminId"`
+	AdminUsername string `json:"adminUsername"`
+	Action        string `json:"action"`
+	TargetType    string `json:"targetType"`
+	TargetID      string `json:"targetId"`
+	Details       string `json:"details"`
+	CreatedAt     string `json:"createdAt"`
+	ID            int    `json:"id"`
+}
+
+// AuditLogPageData is the data for the admin audit log page. PrevPage and
+// NextPage are 0 when there is no such page.
+type AuditLogPageData struct {
+	User       *LoggedInUser
+	Entries    []AuditEntry `json:"entries"`
+	Pagination Pagination   `json:"pagination"`
+	PrevPage   int
+	NextPage   int
+}
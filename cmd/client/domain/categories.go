@@ -7,14 +7,15 @@ type CategoryData struct {
 }
 
 type Category struct {
-	Name        string  `json:"name"`
-	Color       string  `json:"color"`
-	Slug        string  `json:"slug,omitzero"`
-	Description string  `json:"description,omitzero"`
-	ImagePath   string  `json:"imagePath"`
-	Topics      []Topic `json:"topics,omitzero"`
-	ID          int     `json:"id"`
-	TopicCount  int     `json:"topicsCount,omitzero"`
+	Name          string  `json:"name"`
+	Color         string  `json:"color"`
+	Slug          string  `json:"slug,omitzero"`
+	Description   string  `json:"description,omitzero"`
+	ImagePath     string  `json:"imagePath"`
+	Topics        []Topic `json:"topics,omitzero"`
+	ID            int     `json:"id"`
+	TopicCount    int     `json:"topicsCount,omitzero"`
+	RequiresImage bool    `json:"requiresImage,omitzero"`
 }
 
 type Pagination struct {
@@ -23,6 +24,10 @@ type Pagination struct {
 	TotalItems int `json:"totalItems"`
 	NextPage   int `json:"nextPage"`
 	PrevPage   int `json:"prevPage"`
+	// NextBefore is the cursor for the next page of a newest-first topic
+	// listing; it is only meaningful when HasMore is set.
+	NextBefore int  `json:"next_before"`
+	HasMore    bool `json:"has_more"`
 }
 
 type Logo struct {
@@ -33,22 +38,31 @@ type Logo struct {
 }
 
 type Topic struct {
-	UserVote       *int      `json:"userVote,omitempty"`
-	UserID         string    `json:"userId"`
-	Content        string    `json:"content"`
-	ImagePath      string    `json:"imagePath"`
-	Title          string    `json:"title"`
-	CategoryColors []string  `json:"categoryColors"`
-	CategoryNames  []string  `json:"categoryNames"`
-	CreatedAt      string    `json:"createdAt"`
-	UpdatedAt      string    `json:"updatedAt"`
-	OwnerUsername  string    `json:"ownerUsername"`
-	Comments       []Comment `json:"comments"`
-	CategoryIDs    []int     `json:"categoryIds"`
-	VoteScore      int       `json:"voteScore"`
-	DownvoteCount  int       `json:"downvoteCount"`
-	UpvoteCount    int       `json:"upvoteCount"`
-	ID             int       `json:"id"`
+	UserVote            *int      `json:"userVote,omitempty"`
+	UserID              string    `json:"userId"`
+	Content             string    `json:"content"`
+	Summary             string    `json:"summary"`
+	ImagePath           string    `json:"imagePath"`
+	Title               string    `json:"title"`
+	Slug                string    `json:"slug"`
+	CategoryColors      []string  `json:"categoryColors"`
+	CategoryNames       []string  `json:"categoryNames"`
+	CreatedAt           string    `json:"createdAt"`
+	UpdatedAt           string    `json:"updatedAt"`
+	OwnerUsername       string    `json:"ownerUsername"`
+	Comments            []Comment `json:"comments"`
+	CategoryIDs         []int     `json:"categoryIds"`
+	VoteScore           int       `json:"voteScore"`
+	DownvoteCount       int       `json:"downvoteCount"`
+	UpvoteCount         int       `json:"upvoteCount"`
+	ViewCount           int       `json:"viewCount"`
+	ID                  int       `json:"id"`
+	CanonicalCategoryID int       `json:"canonicalCategoryId"`
+	AcceptedCommentID   int       `json:"acceptedCommentId,omitempty"`
+	CommentCount        int       `json:"commentCount"`
+	Removed             bool      `json:"removed,omitempty"`
+	IsQuestion          bool      `json:"isQuestion"`
+	IsEdited            bool      `json:"isEdited"`
 }
 
 type Comment struct {
@@ -58,9 +72,13 @@ type Comment struct {
 	CreatedAt     string `json:"createdAt"`
 	UpdatedAt     string `json:"updatedAt"`
 	OwnerUsername string `json:"ownerUsername"`
+	Status        string `json:"status"`
 	ID            int    `json:"id"`
 	TopicID       int    `json:"topicId"`
 	UpvoteCount   int    `json:"upvoteCount"`
 	DownvoteCount int    `json:"downvoteCount"`
+	ReportCount   int    `json:"reportCount"`
 	VoteScore     int    `json:"voteScore"`
+	Collapsed     bool   `json:"collapsed"`
+	IsEdited      bool   `json:"isEdited"`
 }
@@ -20,6 +20,7 @@ type Notification struct {
 	RelatedType string           `json:"relatedType,omitempty"`
 	RelatedID   string           `json:"relatedId,omitempty"`
 	ID          int              `json:"id"`
+	Count       int              `json:"count"`
 	IsRead      bool             `json:"isRead"`
 }
 
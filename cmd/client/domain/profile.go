@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// ProfileData represents the data structure for a user's profile page.
+type ProfileData struct {
+	JoinedAt  time.Time         `json:"joinedAt"`
+	User      *LoggedInUser     `json:"-"`
+	AvatarURL *string           `json:"avatarUrl"`
+	Username  string            `json:"username"`
+	Topics    []ActivityTopic   `json:"topics"`
+	Comments  []ActivityComment `json:"comments"`
+	Stats     ProfileStats      `json:"stats"`
+	IsOwner   bool              `json:"isOwner"`
+}
+
+// ProfileStats holds the totals shown at the top of a profile.
+type ProfileStats struct {
+	Posts         int `json:"posts"`
+	Comments      int `json:"comments"`
+	LikesReceived int `json:"likesReceived"`
+}
@@ -0,0 +1,15 @@
+package domain
+
+type ReportReason struct {
+	Label     string `json:"label"`
+	CreatedAt string `json:"createdAt"`
+	ID        int    `json:"id"`
+	Active    bool   `json:"active"`
+}
+
+// ReportReasonsPageData is the data for the admin report reasons page.
+type ReportReasonsPageData struct {
+	User    *LoggedInUser
+	Error   string
+	Reasons []ReportReason `json:"reasons"`
+}
@@ -2,7 +2,9 @@ package domain
 
 // BackendErrorResponse - handles backend validation errors (shared across all features).
 type BackendErrorResponse struct {
-	Message string `json:"message"`
+	Fields  map[string]string `json:"fields,omitempty"`
+	Message string            `json:"message"`
+	Error   string            `json:"error"`
 }
 
 // BackendMeResponse - response from backend /me endpoint.
@@ -11,6 +13,7 @@ type BackendMeResponse struct {
 	Username  string `json:"username"`
 	Email     string `json:"email"`
 	AvatarURL string `json:"avatarUrl"`
+	Role      string `json:"role"`
 }
 
 // LoggedInUser - user data to pass to templates and store in session.
@@ -19,4 +22,15 @@ type LoggedInUser struct {
 	Username  string
 	Email     string
 	AvatarURL string // For future navbar avatar display.
+	Role      string
+}
+
+// IsStaff reports whether the user is a moderator or an admin.
+func (u *LoggedInUser) IsStaff() bool {
+	return u != nil && (u.Role == "moderator" || u.Role == "admin")
+}
+
+// IsAdmin reports whether the user may manage site-wide settings.
+func (u *LoggedInUser) IsAdmin() bool {
+	return u != nil && u.Role == "admin"
 }
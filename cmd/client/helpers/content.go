@@ -0,0 +1,46 @@
+package helpers
+
+import (
+	"html"
+	"strings"
+)
+
+// spoilerOpenTag keeps the hidden text in the DOM; topic.css obscures it and
+// topic.js reveals it on click.
+const (
+	spoilerOpenTag  = `<span class="spoiler" tabindex="0" role="button" aria-label="Spoiler, click to reveal">`
+	spoilerCloseTag = `</span>`
+)
+
+// RenderContent escapes user content for the page and then turns text
+// between the open and close markers into spoiler blocks. Escaping happens
+// per segment so the wrapper is the only markup that survives. An empty
+// open marker disables spoilers; an unmatched marker is left as text.
+func RenderContent(content, open, closing string) string {
+	if open == "" || closing == "" {
+		return html.EscapeString(content)
+	}
+
+	var b strings.Builder
+	rest := content
+	for {
+		start := strings.Index(rest, open)
+		if start < 0 {
+			break
+		}
+		inner := rest[start+len(open):]
+		end := strings.Index(inner, closing)
+		if end <= 0 {
+			break
+		}
+
+		b.WriteString(html.EscapeString(rest[:start]))
+		b.WriteString(spoilerOpenTag)
+		b.WriteString(html.EscapeString(inner[:end]))
+		b.WriteString(spoilerCloseTag)
+		rest = inner[end+len(closing):]
+	}
+	b.WriteString(html.EscapeString(rest))
+
+	return b.String()
+}
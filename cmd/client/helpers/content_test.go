@@ -0,0 +1,74 @@
+package helpers
+
+import "testing"
+
+func TestRenderContent(t *testing.T) {
+	t.Run("group: spoiler blocks", func(t *testing.T) {
+		testCases := newRenderContentTestCases()
+		for _, tt := range testCases {
+			t.Run(tt.name, runRenderContentTest(tt))
+		}
+	})
+}
+
+type renderContentTestCase struct {
+	name    string
+	content string
+	open    string
+	closing string
+	want    string
+}
+
+func newRenderContentTestCases() []renderContentTestCase {
+	return []renderContentTestCase{
+		{
+			name:    "pipes spoiler",
+			content: "the butler ||did it|| again",
+			open:    "||",
+			closing: "||",
+			want:    "the butler " + spoilerOpenTag + "did it" + spoilerCloseTag + " again",
+		},
+		{
+			name:    "reddit style spoiler",
+			content: ">!hidden!< shown",
+			open:    ">!",
+			closing: "!<",
+			want:    spoilerOpenTag + "hidden" + spoilerCloseTag + " shown",
+		},
+		{
+			name:    "markup inside spoiler is escaped",
+			content: "||<script>x</script>||",
+			open:    "||",
+			closing: "||",
+			want:    spoilerOpenTag + "&lt;script&gt;x&lt;/script&gt;" + spoilerCloseTag,
+		},
+		{
+			name:    "unmatched marker stays text",
+			content: "a || b",
+			open:    "||",
+			closing: "||",
+			want:    "a || b",
+		},
+		{
+			name:    "empty spoiler stays text",
+			content: "a |||| b",
+			open:    "||",
+			closing: "||",
+			want:    "a |||| b",
+		},
+		{
+			name:    "disabled",
+			content: "<b>||x||</b>",
+			want:    "&lt;b&gt;||x||&lt;/b&gt;",
+		},
+	}
+}
+
+func runRenderContentTest(tt renderContentTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		got := RenderContent(tt.content, tt.open, tt.closing)
+		if got != tt.want {
+			t.Errorf("RenderContent(%q) = %q, want %q", tt.content, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+)
+
+const (
+	sniffLength    = 512
+	jpegQuality    = 90
+	maxImagePixels = 40_000_000
+)
+
+var (
+	ErrUnsupportedImage = errors.New("file is not a JPEG, PNG or GIF image")
+	ErrImageTooLarge    = errors.New("image dimensions are too large")
+)
+
+// CleanImage checks an upload's real type from its leading bytes, ignoring
+// its name and declared Content-Type, and writes a re-encoded copy to dst.
+// Re-encoding keeps the pixels but drops EXIF and every other metadata
+// block, GPS positions included. It returns the extension for the real type.
+func CleanImage(dst io.Writer, src io.ReadSeeker) (string, error) {
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(src, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read image: %w", err)
+	}
+
+	contentType := http.DetectContentType(head[:n])
+	if contentType != "image/jpeg" && contentType != "image/png" && contentType != "image/gif" {
+		return "", ErrUnsupportedImage
+	}
+
+	// Check the size before decoding so a tiny file can't claim huge
+	// dimensions and exhaust memory.
+	_, err = src.Seek(0, io.SeekStart)
+	if err != nil {
+		return "", fmt.Errorf("failed to rewind image: %w", err)
+	}
+	config, _, err := image.DecodeConfig(src)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrUnsupportedImage, err)
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return "", ErrImageTooLarge
+	}
+
+	_, err = src.Seek(0, io.SeekStart)
+	if err != nil {
+		return "", fmt.Errorf("failed to rewind image: %w", err)
+	}
+
+	switch contentType {
+	case "image/jpeg":
+		img, decodeErr := jpeg.Decode(src)
+		if decodeErr != nil {
+			return "", fmt.Errorf("%w: %w", ErrUnsupportedImage, decodeErr)
+		}
+		return ".jpg", jpeg.Encode(dst, img, &jpeg.Options{Quality: jpegQuality})
+	case "image/png":
+		img, decodeErr := png.Decode(src)
+		if decodeErr != nil {
+			return "", fmt.Errorf("%w: %w", ErrUnsupportedImage, decodeErr)
+		}
+		return ".png", png.Encode(dst, img)
+	default:
+		// DecodeAll keeps every frame of an animation.
+		anim, decodeErr := gif.DecodeAll(src)
+		if decodeErr != nil {
+			return "", fmt.Errorf("%w: %w", ErrUnsupportedImage, decodeErr)
+		}
+		return ".gif", gif.EncodeAll(dst, anim)
+	}
+}
@@ -0,0 +1,111 @@
+package helpers
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func testImage() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for x := range 4 {
+		for y := range 4 {
+			img.Set(x, y, color.RGBA{R: uint8(x * 60), G: uint8(y * 60), B: 128, A: 255})
+		}
+	}
+	return img
+}
+
+// jpegWithGPS encodes a JPEG and splices an EXIF APP1 segment carrying a
+// GPS marker in right after the start-of-image marker.
+func jpegWithGPS(t *testing.T) []byte {
+	t.Helper()
+
+	var encoded bytes.Buffer
+	err := jpeg.Encode(&encoded, testImage(), nil)
+	if err != nil {
+		t.Fatalf("failed to encode jpeg: %v", err)
+	}
+
+	payload := []byte("Exif\x00\x00GPSLatitude=51.5074")
+	segmentLength := len(payload) + 2
+	segment := append([]byte{0xFF, 0xE1, byte(segmentLength >> 8), byte(segmentLength)}, payload...)
+
+	raw := encoded.Bytes()
+	withExif := append([]byte{}, raw[:2]...)
+	withExif = append(withExif, segment...)
+	return append(withExif, raw[2:]...)
+}
+
+func TestCleanImage(t *testing.T) {
+	t.Run("renamed text file is rejected", func(t *testing.T) {
+		var out bytes.Buffer
+		_, err := CleanImage(&out, bytes.NewReader([]byte("just some notes, saved as photo.png\n")))
+		if !errors.Is(err, ErrUnsupportedImage) {
+			t.Fatalf("CleanImage() error = %v, want %v", err, ErrUnsupportedImage)
+		}
+		if out.Len() != 0 {
+			t.Errorf("CleanImage() wrote %d bytes for a rejected file", out.Len())
+		}
+	})
+
+	t.Run("truncated image is rejected", func(t *testing.T) {
+		var encoded bytes.Buffer
+		err := png.Encode(&encoded, testImage())
+		if err != nil {
+			t.Fatalf("failed to encode png: %v", err)
+		}
+
+		_, err = CleanImage(&bytes.Buffer{}, bytes.NewReader(encoded.Bytes()[:40]))
+		if !errors.Is(err, ErrUnsupportedImage) {
+			t.Errorf("CleanImage() error = %v, want %v", err, ErrUnsupportedImage)
+		}
+	})
+
+	t.Run("real png is kept", func(t *testing.T) {
+		var encoded bytes.Buffer
+		err := png.Encode(&encoded, testImage())
+		if err != nil {
+			t.Fatalf("failed to encode png: %v", err)
+		}
+
+		var out bytes.Buffer
+		ext, err := CleanImage(&out, bytes.NewReader(encoded.Bytes()))
+		if err != nil {
+			t.Fatalf("CleanImage() error = %v", err)
+		}
+		if ext != ".png" {
+			t.Errorf("CleanImage() ext = %q, want .png", ext)
+		}
+		img, err := png.Decode(&out)
+		if err != nil {
+			t.Fatalf("cleaned png does not decode: %v", err)
+		}
+		if img.Bounds() != testImage().Bounds() {
+			t.Errorf("cleaned bounds = %v, want %v", img.Bounds(), testImage().Bounds())
+		}
+	})
+
+	t.Run("jpeg loses its exif block", func(t *testing.T) {
+		raw := jpegWithGPS(t)
+		if !bytes.Contains(raw, []byte("GPSLatitude")) {
+			t.Fatal("test image is missing its GPS marker")
+		}
+
+		var out bytes.Buffer
+		ext, err := CleanImage(&out, bytes.NewReader(raw))
+		if err != nil {
+			t.Fatalf("CleanImage() error = %v", err)
+		}
+		if ext != ".jpg" {
+			t.Errorf("CleanImage() ext = %q, want .jpg", ext)
+		}
+		if bytes.Contains(out.Bytes(), []byte("Exif")) || bytes.Contains(out.Bytes(), []byte("GPSLatitude")) {
+			t.Error("cleaned jpeg still carries the EXIF block")
+		}
+	})
+}
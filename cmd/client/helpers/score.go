@@ -0,0 +1,17 @@
+package helpers
+
+import "strconv"
+
+// ScorePlaceholder stands in for a score that rests on too few votes to mean
+// much yet.
+const ScorePlaceholder = "new"
+
+// DisplayScore formats a vote score for templates. Below minVotes total votes
+// the score is replaced with ScorePlaceholder unless exact is set, which is
+// how staff always get the real number.
+func DisplayScore(score, upvotes, downvotes, minVotes int, exact bool) string {
+	if !exact && upvotes+downvotes < minVotes {
+		return ScorePlaceholder
+	}
+	return strconv.Itoa(score)
+}
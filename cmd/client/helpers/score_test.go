@@ -0,0 +1,59 @@
+package helpers
+
+import "testing"
+
+func TestDisplayScore(t *testing.T) {
+	t.Run("group: minimum votes to show score", func(t *testing.T) {
+		testCases := newDisplayScoreTestCases()
+		for _, tt := range testCases {
+			t.Run(tt.name, runDisplayScoreTest(tt))
+		}
+	})
+}
+
+type displayScoreTestCase struct {
+	name      string
+	want      string
+	upvotes   int
+	downvotes int
+	minVotes  int
+	exact     bool
+}
+
+func newDisplayScoreTestCases() []displayScoreTestCase {
+	return []displayScoreTestCase{
+		{
+			name:     "one vote short of the threshold is hidden",
+			upvotes:  2,
+			minVotes: 3,
+			want:     ScorePlaceholder,
+		},
+		{
+			name:      "exactly at the threshold is shown",
+			upvotes:   2,
+			downvotes: 1,
+			minVotes:  3,
+			want:      "1",
+		},
+		{
+			name:     "staff see the score below the threshold",
+			upvotes:  1,
+			minVotes: 3,
+			exact:    true,
+			want:     "1",
+		},
+		{
+			name: "zero threshold always shows the score",
+			want: "0",
+		},
+	}
+}
+
+func runDisplayScoreTest(tt displayScoreTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		got := DisplayScore(tt.upvotes-tt.downvotes, tt.upvotes, tt.downvotes, tt.minVotes, tt.exact)
+		if got != tt.want {
+			t.Errorf("DisplayScore() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -5,15 +5,26 @@ import (
 	"log"
 	"net/http"
 
+	"github.com/arnald/forum/cmd/client/middleware"
 	"github.com/arnald/forum/internal/pkg/path"
 )
 
+// FuncMap returns the functions every page can call while rendering r:
+// csrfToken yields the token forms must submit in their csrf_token field.
+func FuncMap(r *http.Request) template.FuncMap {
+	return template.FuncMap{
+		"csrfToken": func() string {
+			return middleware.GetCSRFTokenFromContext(r.Context())
+		},
+	}
+}
+
 // renderTemplate renders a template with the given data.
-func RenderTemplate(w http.ResponseWriter, templateName string, data interface{}) {
+func RenderTemplate(w http.ResponseWriter, r *http.Request, templateName string, data interface{}) {
 	resolver := path.NewResolver()
 	tmplPath := resolver.GetPath("frontend/html/pages/" + templateName + ".html")
 
-	tmpl, err := template.ParseFiles(tmplPath)
+	tmpl, err := template.New(templateName).Funcs(FuncMap(r)).ParseFiles(tmplPath)
 	if err != nil {
 		log.Printf("Error parsing %s: %v", tmplPath, err)
 		http.Error(w, "Failed to load page", http.StatusInternalServerError)
@@ -94,6 +94,7 @@ func getCurrentUser(ctx context.Context, httpClient *http.Client, r *http.Reques
 		Username:  meResp.Username,
 		Email:     meResp.Email,
 		AvatarURL: meResp.AvatarURL,
+		Role:      meResp.Role,
 	}
 
 	return user, nil
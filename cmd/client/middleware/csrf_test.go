@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const testCSRFSessionID = "test-session"
+
+func TestCSRFProtect(t *testing.T) {
+	t.Run("group: state-changing requests", func(t *testing.T) {
+		testCases := newCSRFProtectTestCases()
+		for _, tt := range testCases {
+			t.Run(tt.name, runCSRFProtectTest(tt))
+		}
+	})
+
+	t.Run("safe request issues a session and exposes its token", func(t *testing.T) {
+		csrf := NewCSRF("secret", false)
+		var got string
+		next := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			got = GetCSRFTokenFromContext(r.Context())
+		})
+
+		rec := httptest.NewRecorder()
+		csrf.Protect(next).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/login", nil))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Protect() status = %d, want %d", rec.Code, http.StatusOK)
+		}
+		var sessionID string
+		for _, c := range rec.Result().Cookies() {
+			if c.Name == CSRFCookieName {
+				sessionID = c.Value
+			}
+		}
+		if sessionID == "" {
+			t.Fatal("Protect() did not set a CSRF session cookie")
+		}
+		if got == "" || got != csrf.token(sessionID) {
+			t.Errorf("GetCSRFTokenFromContext() = %q, want the token for the new session", got)
+		}
+	})
+}
+
+type csrfProtectTestCase struct {
+	name       string
+	formToken  string
+	header     string
+	wantStatus int
+	noCookie   bool
+}
+
+func newCSRFProtectTestCases() []csrfProtectTestCase {
+	valid := NewCSRF("secret", false).token(testCSRFSessionID)
+
+	return []csrfProtectTestCase{
+		{
+			name:       "missing token",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "wrong token",
+			formToken:  "not-the-token",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "token from another secret",
+			formToken:  NewCSRF("other", false).token(testCSRFSessionID),
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "valid token without a session cookie",
+			formToken:  valid,
+			noCookie:   true,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "valid form token",
+			formToken:  valid,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "valid header token",
+			header:     valid,
+			wantStatus: http.StatusOK,
+		},
+	}
+}
+
+func runCSRFProtectTest(tt csrfProtectTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		called := false
+		next := http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+			called = true
+		})
+
+		form := url.Values{"content": {"hello"}}
+		if tt.formToken != "" {
+			form.Set(CSRFFieldName, tt.formToken)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/comments/create", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if tt.header != "" {
+			req.Header.Set(CSRFHeaderName, tt.header)
+		}
+		if !tt.noCookie {
+			req.AddCookie(&http.Cookie{Name: CSRFCookieName, Value: testCSRFSessionID})
+		}
+		rec := httptest.NewRecorder()
+
+		NewCSRF("secret", false).Protect(next).ServeHTTP(rec, req)
+
+		if rec.Code != tt.wantStatus {
+			t.Fatalf("Protect() status = %d, want %d", rec.Code, tt.wantStatus)
+		}
+		if called != (tt.wantStatus == http.StatusOK) {
+			t.Errorf("Protect() called next = %v, want %v", called, !called)
+		}
+	}
+}
@@ -61,7 +61,7 @@ func (cs *ClientServer) ActivityPage(w http.ResponseWriter, r *http.Request) {
 
 	activityData.User = user
 
-	tmpl, err := template.ParseFiles(
+	tmpl, err := template.New("base").Funcs(templates.FuncMap(r)).ParseFiles(
 		"frontend/html/layouts/base.html",
 		"frontend/html/pages/activity.html",
 		"frontend/html/partials/navbar.html",
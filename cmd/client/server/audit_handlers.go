@@ -0,0 +1,95 @@
+package server
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/url"
+	"text/template"
+
+	"github.com/arnald/forum/cmd/client/domain"
+	"github.com/arnald/forum/cmd/client/helpers"
+	"github.com/arnald/forum/cmd/client/helpers/templates"
+	"github.com/arnald/forum/cmd/client/middleware"
+)
+
+// AuditLogPage handles GET requests to /admin/audit, where admins page
+// through the moderation actions taken on the forum.
+func (cs *ClientServer) AuditLogPage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if !user.IsAdmin() {
+		templates.NotFoundHandler(w, r, "Admin access required", http.StatusForbidden)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+
+	query := url.Values{}
+	if page := r.URL.Query().Get("page"); page != "" {
+		query.Set("page", page)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, cs.BackendURLs.AuditLogURL()+"?"+query.Encode(), nil)
+	if err != nil {
+		http.Error(w, "Error creating request", http.StatusInternalServerError)
+		return
+	}
+
+	ip := middleware.GetIPFromContext(r)
+	if ip == "" {
+		http.Error(w, "Error no IP found in request", http.StatusInternalServerError)
+		return
+	}
+
+	helpers.SetIPHeaders(httpReq, ip)
+
+	for _, cookie := range r.Cookies() {
+		httpReq.AddCookie(cookie)
+	}
+
+	backendResp, err := cs.HTTPClient.Do(httpReq)
+	if err != nil {
+		log.Printf("Error making request to backend: %v", err)
+		http.Error(w, "Error communicating with backend", http.StatusInternalServerError)
+		return
+	}
+	defer backendResp.Body.Close()
+
+	var pageData domain.AuditLogPageData
+	err = helpers.DecodeBackendResponse(backendResp, &pageData)
+	if err != nil {
+		http.Error(w, "Error decoding the response to json", http.StatusInternalServerError)
+		return
+	}
+
+	pageData.User = user
+	if pageData.Pagination.Page > 1 {
+		pageData.PrevPage = pageData.Pagination.Page - 1
+	}
+	if pageData.Pagination.Page < pageData.Pagination.TotalPages {
+		pageData.NextPage = pageData.Pagination.Page + 1
+	}
+
+	tmpl, err := template.New("base").Funcs(templates.FuncMap(r)).ParseFiles(
+		"frontend/html/layouts/base.html",
+		"frontend/html/pages/audit_log.html",
+		"frontend/html/partials/navbar.html",
+		"frontend/html/partials/footer.html",
+	)
+	if err != nil {
+		templates.NotFoundHandler(w, r, "Failed to load page", http.StatusInternalServerError)
+		return
+	}
+
+	err = tmpl.ExecuteTemplate(w, "base", pageData)
+	if err != nil {
+		log.Println("Error executing template:", err)
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
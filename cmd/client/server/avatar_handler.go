@@ -0,0 +1,151 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/arnald/forum/cmd/client/helpers"
+	"github.com/arnald/forum/cmd/client/helpers/templates"
+	"github.com/arnald/forum/cmd/client/middleware"
+)
+
+const (
+	maxAvatarSize    = 2 << 20 // 2 MB
+	avatarPathPrefix = uploadURLPrefix + "avatars/"
+	// avatarOwnerSeparator follows the uploader's ID in an avatar's file name;
+	// the backend only accepts avatars named after the user setting them.
+	avatarOwnerSeparator = "_"
+)
+
+// ChangeAvatarFormData backs the change avatar page.
+type ChangeAvatarFormData struct {
+	AvatarURL string
+	Message   string
+	FormError string
+}
+
+type backendChangeAvatarRequest struct {
+	AvatarURL string `json:"avatarUrl"`
+}
+
+type backendChangeAvatarResponse struct {
+	Data struct {
+		PreviousAvatarURL string `json:"previousAvatarUrl"`
+	} `json:"data"`
+}
+
+// ChangeAvatarPage handles GET requests to /settings/avatar.
+func (cs *ClientServer) ChangeAvatarPage(w http.ResponseWriter, r *http.Request) {
+	data := ChangeAvatarFormData{}
+	if user := middleware.GetUserFromContext(r.Context()); user != nil {
+		data.AvatarURL = user.AvatarURL
+	}
+	templates.RenderTemplate(w, r, "change_avatar", data)
+}
+
+// ChangeAvatarPost handles POST requests to /settings/avatar. The image is
+// checked and stored the same way topic images are, then the backend is told
+// to use it; the avatar it replaces is removed once the backend agrees.
+func (cs *ClientServer) ChangeAvatarPost(w http.ResponseWriter, r *http.Request) {
+	data := ChangeAvatarFormData{}
+	var owner string
+	if user := middleware.GetUserFromContext(r.Context()); user != nil {
+		data.AvatarURL = user.AvatarURL
+		owner = user.ID
+	}
+
+	err := r.ParseMultipartForm(maxAvatarSize)
+	if err != nil {
+		log.Printf("Error parsing form: %v", err)
+		data.FormError = "Could not read the upload. Please try again."
+		templates.RenderTemplate(w, r, "change_avatar", data)
+		return
+	}
+
+	file, header, err := r.FormFile("avatar")
+	if errors.Is(err, http.ErrMissingFile) {
+		data.FormError = "Choose an image to upload."
+		templates.RenderTemplate(w, r, "change_avatar", data)
+		return
+	}
+	if err != nil {
+		log.Printf("Error reading uploaded file: %v", err)
+		data.FormError = "Could not read the upload. Please try again."
+		templates.RenderTemplate(w, r, "change_avatar", data)
+		return
+	}
+	defer file.Close()
+
+	if header.Size > maxAvatarSize {
+		log.Printf("Avatar too large: %d bytes", header.Size)
+		data.FormError = "Image too large. Maximum size is 2MB."
+		templates.RenderTemplate(w, r, "change_avatar", data)
+		return
+	}
+
+	// The declared type can lie; trust only the decoded bytes.
+	var cleaned bytes.Buffer
+	ext, err := helpers.CleanImage(&cleaned, file)
+	if err != nil {
+		log.Printf("Rejected avatar %q: %v", header.Filename, err)
+		data.FormError = "Invalid file type. Only JPEG, PNG, and GIF are allowed."
+		templates.RenderTemplate(w, r, "change_avatar", data)
+		return
+	}
+
+	avatarURL, err := cs.Avatars.Save(&cleaned, owner, ext)
+	if err != nil {
+		log.Printf("Failed to save avatar: %v", err)
+		data.FormError = "Failed to save image."
+		templates.RenderTemplate(w, r, "change_avatar", data)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+
+	resp, err := cs.newRequestWithCookies(ctx, http.MethodPost, cs.BackendURLs.ChangeAvatarURL(),
+		backendChangeAvatarRequest{AvatarURL: avatarURL}, r)
+	if err != nil {
+		log.Printf("Backend request failed: %v", err)
+		cs.removeAvatar(avatarURL)
+		data.FormError = "Failed to change avatar."
+		templates.RenderTemplate(w, r, "change_avatar", data)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		cs.removeAvatar(avatarURL)
+		data.FormError = backendFormError(resp, "avatarUrl")
+		templates.RenderTemplate(w, r, "change_avatar", data)
+		return
+	}
+
+	var changed backendChangeAvatarResponse
+	err = json.NewDecoder(resp.Body).Decode(&changed)
+	if err != nil {
+		log.Printf("Failed to decode change avatar response: %v", err)
+	}
+	if previous := changed.Data.PreviousAvatarURL; previous != avatarURL {
+		cs.removeAvatar(previous)
+	}
+
+	templates.RenderTemplate(w, r, "change_avatar", ChangeAvatarFormData{
+		AvatarURL: avatarURL,
+		Message:   "Your avatar has been updated.",
+	})
+}
+
+// removeAvatar deletes an uploaded avatar. URLs the avatar store does not
+// own, such as OAuth provider pictures, are left alone.
+func (cs *ClientServer) removeAvatar(avatarURL string) {
+	err := cs.Avatars.Delete(avatarURL)
+	if err != nil {
+		log.Printf("Failed to delete avatar %s: %v", avatarURL, err)
+	}
+}
@@ -0,0 +1,166 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/arnald/forum/cmd/client/config"
+	"github.com/arnald/forum/cmd/client/middleware"
+)
+
+func TestChangeAvatarPost(t *testing.T) {
+	t.Run("stores the upload and removes the avatar it replaces", func(t *testing.T) {
+		uploads, dir := useTempAvatarDir(t)
+		oldPath := filepath.Join(dir, "old.png")
+		err := os.WriteFile(oldPath, []byte("old"), 0o600)
+		if err != nil {
+			t.Fatalf("failed to seed old avatar: %v", err)
+		}
+
+		var sent backendChangeAvatarRequest
+		cs, calls := newAvatarTestServer(t, uploads, func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&sent)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":{"avatarUrl":"` + sent.AvatarURL +
+				`","previousAvatarUrl":"/static/images/uploads/avatars/old.png"}}`))
+		})
+
+		rec := postAvatar(t, cs, pngBytes(t))
+
+		if *calls != 1 {
+			t.Fatalf("backend calls = %d, want 1: %s", *calls, rec.Body.String())
+		}
+		if !strings.HasPrefix(sent.AvatarURL, avatarPathPrefix) || !strings.HasSuffix(sent.AvatarURL, ".png") {
+			t.Fatalf("avatarUrl sent = %q, want a png under %s", sent.AvatarURL, avatarPathPrefix)
+		}
+		newPath := filepath.Join(dir, strings.TrimPrefix(sent.AvatarURL, avatarPathPrefix))
+		if _, err = os.Stat(newPath); err != nil {
+			t.Errorf("new avatar not stored: %v", err)
+		}
+		if _, err = os.Stat(oldPath); !os.IsNotExist(err) {
+			t.Errorf("old avatar still on disk, stat error = %v", err)
+		}
+	})
+
+	t.Run("backend refusal discards the upload", func(t *testing.T) {
+		uploads, dir := useTempAvatarDir(t)
+
+		cs, calls := newAvatarTestServer(t, uploads, func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"validation failed","fields":{"avatarUrl":"must be an uploaded avatar"}}`))
+		})
+
+		rec := postAvatar(t, cs, pngBytes(t))
+
+		if *calls != 1 {
+			t.Fatalf("backend calls = %d, want 1", *calls)
+		}
+		if !strings.Contains(rec.Body.String(), "must be an uploaded avatar") {
+			t.Errorf("page does not show the backend error: %s", rec.Body.String())
+		}
+		assertNoAvatars(t, dir)
+	})
+
+	t.Run("rejects an oversized file", func(t *testing.T) {
+		uploads, dir := useTempAvatarDir(t)
+
+		cs, calls := newAvatarTestServer(t, uploads, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+
+		rec := postAvatar(t, cs, bytes.Repeat([]byte{0}, maxAvatarSize+1))
+
+		if *calls != 0 {
+			t.Errorf("backend calls = %d, want 0", *calls)
+		}
+		if !strings.Contains(rec.Body.String(), "Image too large") {
+			t.Errorf("page does not explain the rejection: %s", rec.Body.String())
+		}
+		assertNoAvatars(t, dir)
+	})
+}
+
+// useTempAvatarDir returns a temporary upload directory and the avatar
+// directory inside it.
+func useTempAvatarDir(t *testing.T) (string, string) {
+	t.Helper()
+
+	uploads := t.TempDir()
+	dir := filepath.Join(uploads, "avatars")
+	err := os.Mkdir(dir, 0o750)
+	if err != nil {
+		t.Fatalf("failed to create avatar dir: %v", err)
+	}
+	return uploads, dir
+}
+
+func newAvatarTestServer(t *testing.T, uploadDir string, handler http.HandlerFunc) (*ClientServer, *int) {
+	t.Helper()
+
+	calls := new(int)
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		handler(w, r)
+	}))
+	t.Cleanup(backend.Close)
+
+	return &ClientServer{
+		Config:      &config.Client{},
+		HTTPClient:  backend.Client(),
+		BackendURLs: NewBackendURLs(backend.URL),
+		Avatars:     NewLocalImageStore(filepath.Join(uploadDir, "avatars"), avatarPathPrefix),
+	}, calls
+}
+
+func postAvatar(t *testing.T, cs *ClientServer, content []byte) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("avatar", "avatar.png")
+	if err != nil {
+		t.Fatalf("failed to build form: %v", err)
+	}
+	_, _ = part.Write(content)
+	_ = form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/settings/avatar", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	rec := httptest.NewRecorder()
+
+	middleware.GetClientIPMiddleware(http.HandlerFunc(cs.ChangeAvatarPost)).ServeHTTP(rec, req)
+	return rec
+}
+
+func pngBytes(t *testing.T) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4)))
+	if err != nil {
+		t.Fatalf("failed to encode png: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func assertNoAvatars(t *testing.T, dir string) {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read avatar dir: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("avatar dir has %d files, want none", len(entries))
+	}
+}
@@ -13,10 +13,16 @@ const (
 	pathLoginEmail           = "/login/email"
 	pathLoginUsername        = "/login/username"
 	pathLogout               = "/logout"
+	pathRefresh              = "/refresh"
+	pathPasswordForgot       = "/password/forgot"
+	pathPasswordReset        = "/password/reset"
+	pathPasswordChange       = "/password/change"
+	pathVerifyEmail          = "/verify-email"
 	pathMe                   = "/me"
 	pathGithubAuth           = "/auth/github/login"
 	pathGoogleAuth           = "/auth/google/login"
 	pathCategoriesAll        = "/categories/all"
+	pathCategoryTree         = "/categories"
 	pathTopicsAll            = "/topics/all"
 	pathTopic                = "/topic"
 	pathTopicsCreate         = "/topics/create"
@@ -25,10 +31,21 @@ const (
 	pathCommentsCreate       = "/comments/create"
 	pathCommentsUpdate       = "/comments/update"
 	pathCommentsDelete       = "/comments/delete"
+	pathAcceptAnswer         = "/accept-answer/"
+	pathReportsDismiss       = "/comments/reports/dismiss"
+	pathReportsResolve       = "/comments/reports/resolve"
+	pathReportReasons        = "/report-reasons"
+	pathReportReasonCreate   = "/admin/report-reasons/create"
+	pathReportReasonRetire   = "/admin/retire-report-reason/"
+	pathReportReasonRestore  = "/admin/restore-report-reason/"
+	pathAuditLog             = "/admin/audit"
+	pathChangeAvatar         = "/settings/avatar"
 	pathVoteCast             = "/vote/cast"
 	pathVoteDelete           = "/vote/delete"
 	pathVoteCounts           = "/vote/counts"
 	pathUserActivity         = "/user/activity"
+	pathUserSubmissions      = "/user/submissions"
+	pathUserProfile          = "/users/"
 	pathNotificationsStream  = "/notifications/stream"
 	pathNotificationsList    = "/notifications"
 	pathNotificationsUnread  = "/notifications/unread-count"
@@ -51,10 +68,16 @@ func (b *BackendURLs) RegisterURL() string            { return b.baseURL + pathR
 func (b *BackendURLs) LoginEmailURL() string          { return b.baseURL + pathLoginEmail }
 func (b *BackendURLs) LoginUsernameURL() string       { return b.baseURL + pathLoginUsername }
 func (b *BackendURLs) LogoutURL() string              { return b.baseURL + pathLogout }
+func (b *BackendURLs) RefreshURL() string             { return b.baseURL + pathRefresh }
+func (b *BackendURLs) ForgotPasswordURL() string      { return b.baseURL + pathPasswordForgot }
+func (b *BackendURLs) ResetPasswordURL() string       { return b.baseURL + pathPasswordReset }
+func (b *BackendURLs) ChangePasswordURL() string      { return b.baseURL + pathPasswordChange }
+func (b *BackendURLs) VerifyEmailURL() string         { return b.baseURL + pathVerifyEmail }
 func (b *BackendURLs) MeURL() string                  { return b.baseURL + pathMe }
 func (b *BackendURLs) GithubRegisterURL() string      { return b.baseURL + pathGithubAuth }
 func (b *BackendURLs) GoogleRegisterURL() string      { return b.baseURL + pathGoogleAuth }
 func (b *BackendURLs) CategoriesAllURL() string       { return b.baseURL + pathCategoriesAll }
+func (b *BackendURLs) CategoryTreeURL() string        { return b.baseURL + pathCategoryTree }
 func (b *BackendURLs) TopicsAllURL() string           { return b.baseURL + pathTopicsAll }
 func (b *BackendURLs) TopicURL() string               { return b.baseURL + pathTopic }
 func (b *BackendURLs) CreateTopicURL() string         { return b.baseURL + pathTopicsCreate }
@@ -63,10 +86,21 @@ func (b *BackendURLs) DeleteTopicURL() string         { return b.baseURL + pathT
 func (b *BackendURLs) CreateCommentURL() string       { return b.baseURL + pathCommentsCreate }
 func (b *BackendURLs) UpdateCommentURL() string       { return b.baseURL + pathCommentsUpdate }
 func (b *BackendURLs) DeleteCommentURL() string       { return b.baseURL + pathCommentsDelete }
+func (b *BackendURLs) AcceptAnswerURL() string        { return b.baseURL + pathAcceptAnswer }
+func (b *BackendURLs) DismissReportsURL() string      { return b.baseURL + pathReportsDismiss }
+func (b *BackendURLs) ResolveReportsURL() string      { return b.baseURL + pathReportsResolve }
+func (b *BackendURLs) ReportReasonsURL() string       { return b.baseURL + pathReportReasons }
+func (b *BackendURLs) CreateReportReasonURL() string  { return b.baseURL + pathReportReasonCreate }
+func (b *BackendURLs) RetireReportReasonURL() string  { return b.baseURL + pathReportReasonRetire }
+func (b *BackendURLs) RestoreReportReasonURL() string { return b.baseURL + pathReportReasonRestore }
+func (b *BackendURLs) AuditLogURL() string            { return b.baseURL + pathAuditLog }
+func (b *BackendURLs) ChangeAvatarURL() string        { return b.baseURL + pathChangeAvatar }
 func (b *BackendURLs) CastVoteURL() string            { return b.baseURL + pathVoteCast }
 func (b *BackendURLs) DeleteVoteURL() string          { return b.baseURL + pathVoteDelete }
 func (b *BackendURLs) VoteCountsURL() string          { return b.baseURL + pathVoteCounts }
 func (b *BackendURLs) UserActivityURL() string        { return b.baseURL + pathUserActivity }
+func (b *BackendURLs) UserSubmissionsURL() string     { return b.baseURL + pathUserSubmissions }
+func (b *BackendURLs) UserProfileURL() string         { return b.baseURL + pathUserProfile }
 func (b *BackendURLs) NotificationsStreamURL() string { return b.baseURL + pathNotificationsStream }
 func (b *BackendURLs) NotificationsListURL() string   { return b.baseURL + pathNotificationsList }
 func (b *BackendURLs) UnreadCountURL() string         { return b.baseURL + pathNotificationsUnread }
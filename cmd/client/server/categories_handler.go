@@ -82,7 +82,7 @@ func (cs *ClientServer) CategoriesPage(w http.ResponseWriter, r *http.Request) {
 
 	categoryData.User = user
 
-	tmpl, err := template.ParseFiles(
+	tmpl, err := template.New("base").Funcs(templates.FuncMap(r)).ParseFiles(
 		"frontend/html/layouts/base.html",
 		"frontend/html/pages/all_categories.html",
 		"frontend/html/partials/navbar.html",
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/arnald/forum/cmd/client/domain"
+	"github.com/arnald/forum/cmd/client/helpers/templates"
+	"github.com/arnald/forum/cmd/client/helpers/validation"
+)
+
+// ChangePasswordFormData backs the change password page.
+type ChangePasswordFormData struct {
+	Message              string
+	FormError            string
+	CurrentPasswordError string
+	NewPasswordError     string
+	ConfirmPasswordError string
+}
+
+type backendChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword"`
+	NewPassword     string `json:"newPassword"`
+	ConfirmPassword string `json:"confirmPassword"`
+}
+
+// ChangePasswordPage handles GET requests to /change-password.
+func (cs *ClientServer) ChangePasswordPage(w http.ResponseWriter, r *http.Request) {
+	templates.RenderTemplate(w, r, "change_password", ChangePasswordFormData{})
+}
+
+// ChangePasswordPost handles POST requests to /change-password.
+func (cs *ClientServer) ChangePasswordPost(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	req := backendChangePasswordRequest{
+		CurrentPassword: r.FormValue("currentPassword"),
+		NewPassword:     strings.TrimSpace(r.FormValue("newPassword")),
+		ConfirmPassword: strings.TrimSpace(r.FormValue("confirmPassword")),
+	}
+
+	var data ChangePasswordFormData
+	if req.CurrentPassword == "" {
+		data.CurrentPasswordError = "Current password is required."
+	}
+	data.NewPasswordError = validation.ValidatePassword(req.NewPassword)
+	if data.NewPasswordError == "" && req.NewPassword != req.ConfirmPassword {
+		data.ConfirmPasswordError = "Passwords do not match."
+	}
+	if data.CurrentPasswordError != "" || data.NewPasswordError != "" || data.ConfirmPasswordError != "" {
+		templates.RenderTemplate(w, r, "change_password", data)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+
+	resp, err := cs.newRequestWithCookies(ctx, http.MethodPost, cs.BackendURLs.ChangePasswordURL(), req, r)
+	if err != nil {
+		data.FormError = err.Error()
+		templates.RenderTemplate(w, r, "change_password", data)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		data = changePasswordErrors(resp)
+		templates.RenderTemplate(w, r, "change_password", data)
+		return
+	}
+
+	templates.RenderTemplate(w, r, "change_password", ChangePasswordFormData{
+		Message: "Your password has been changed and your other sessions have been signed out.",
+	})
+}
+
+// changePasswordErrors attaches the backend's field errors to their inputs,
+// falling back to a form-level message.
+func changePasswordErrors(resp *http.Response) ChangePasswordFormData {
+	var errResp domain.BackendErrorResponse
+	err := json.NewDecoder(resp.Body).Decode(&errResp)
+	if err != nil {
+		return ChangePasswordFormData{FormError: "Something went wrong. Please try again."}
+	}
+
+	data := ChangePasswordFormData{
+		CurrentPasswordError: errResp.Fields["currentPassword"],
+		NewPasswordError:     errResp.Fields["newPassword"],
+		ConfirmPasswordError: errResp.Fields["confirmPassword"],
+	}
+	if data.CurrentPasswordError == "" && data.NewPasswordError == "" && data.ConfirmPasswordError == "" {
+		data.FormError = errResp.Error
+		if data.FormError == "" {
+			data.FormError = "Something went wrong. Please try again."
+		}
+	}
+
+	return data
+}
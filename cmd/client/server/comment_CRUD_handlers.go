@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"log"
 	"net/http"
@@ -17,6 +18,12 @@ type createCommentRequest struct {
 	TopicID int    `json:"topicId"`
 }
 
+type createCommentResponse struct {
+	Data struct {
+		CommentID int `json:"commentId"`
+	} `json:"data"`
+}
+
 type updateCommentRequest struct {
 	Content string `json:"content"`
 	ID      int    `json:"id"`
@@ -75,7 +82,25 @@ func (cs *ClientServer) CreateCommentPost(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	http.Redirect(w, r, "/topic/"+topicIDStr, http.StatusSeeOther)
+	// The comment exists by now, so a response we can't read only costs the
+	// anchor, not the redirect.
+	var created createCommentResponse
+	err = json.NewDecoder(resp.Body).Decode(&created)
+	if err != nil {
+		log.Printf("Failed to decode create comment response: %v", err)
+	}
+
+	http.Redirect(w, r, cs.commentRedirectURL(topicIDStr, created.Data.CommentID), http.StatusSeeOther)
+}
+
+// commentRedirectURL points at the new comment on its topic page. Comments
+// are shown oldest first, so the new one is on the last page of them.
+func (cs *ClientServer) commentRedirectURL(topicID string, commentID int) string {
+	target := "/topic/" + topicID
+	if cs.Config.CommentAnchors && commentID > 0 {
+		target += "?comments=" + lastCommentsPage + "#comment-" + strconv.Itoa(commentID)
+	}
+	return target
 }
 
 // UpdateCommentPost handles POST requests to /comments/edit.
@@ -209,3 +234,152 @@ func (cs *ClientServer) DeleteCommentPost(w http.ResponseWriter, r *http.Request
 	}
 	http.Redirect(w, r, "/topic/"+topicIDStr, http.StatusSeeOther)
 }
+
+// AcceptAnswerPost handles POST requests to /comments/accept, marking a
+// comment as the accepted answer to its question topic.
+func (cs *ClientServer) AcceptAnswerPost(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	err := r.ParseForm()
+	if err != nil {
+		log.Printf("Error parsing form: %v", err)
+		http.Error(w, "Error parsing form", http.StatusBadRequest)
+		return
+	}
+
+	commentIDStr := r.FormValue("comment_id")
+	topicIDStr := r.FormValue("topic_id")
+
+	_, err = strconv.Atoi(commentIDStr)
+	if err != nil {
+		log.Printf("Invalid comment ID: %v", err)
+		http.Error(w, "Invalid comment ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, cs.BackendURLs.AcceptAnswerURL()+commentIDStr, nil)
+	if err != nil {
+		log.Printf("Error creating request: %v", err)
+		http.Error(w, "Error creating request", http.StatusInternalServerError)
+		return
+	}
+
+	ip := middleware.GetIPFromContext(r)
+	if ip == "" {
+		http.Error(w, "Error no IP found in request", http.StatusInternalServerError)
+		return
+	}
+
+	helpers.SetIPHeaders(httpReq, ip)
+
+	for _, cookie := range r.Cookies() {
+		httpReq.AddCookie(cookie)
+	}
+
+	resp, err := cs.HTTPClient.Do(httpReq)
+	if err != nil {
+		log.Printf("Backend request failed: %v", err)
+		templates.NotFoundHandler(w, r, "Failed to accept answer", http.StatusInternalServerError)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		log.Printf("Backend returned error: %s", string(body))
+		templates.NotFoundHandler(w, r, "Failed to accept answer", resp.StatusCode)
+		return
+	}
+
+	if topicIDStr == "" {
+		http.Redirect(w, r, "/topics", http.StatusSeeOther)
+		return
+	}
+	http.Redirect(w, r, "/topic/"+topicIDStr, http.StatusSeeOther)
+}
+
+// ResolveCommentReportsPost handles POST requests to /comments/reports,
+// letting a moderator dismiss or uphold the reports on a collapsed comment.
+func (cs *ClientServer) ResolveCommentReportsPost(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	err := r.ParseForm()
+	if err != nil {
+		log.Printf("Error parsing form: %v", err)
+		http.Error(w, "Error parsing form", http.StatusBadRequest)
+		return
+	}
+
+	commentIDStr := r.FormValue("comment_id")
+	topicIDStr := r.FormValue("topic_id")
+
+	_, err = strconv.Atoi(commentIDStr)
+	if err != nil {
+		log.Printf("Invalid comment ID: %v", err)
+		http.Error(w, "Invalid comment ID", http.StatusBadRequest)
+		return
+	}
+
+	var backendURL string
+	switch r.FormValue("decision") {
+	case "dismiss":
+		backendURL = cs.BackendURLs.DismissReportsURL()
+	case "resolve":
+		backendURL = cs.BackendURLs.ResolveReportsURL()
+	default:
+		http.Error(w, "Invalid decision", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, backendURL+"?id="+commentIDStr, nil)
+	if err != nil {
+		log.Printf("Error creating request: %v", err)
+		http.Error(w, "Error creating request", http.StatusInternalServerError)
+		return
+	}
+
+	ip := middleware.GetIPFromContext(r)
+	if ip == "" {
+		http.Error(w, "Error no IP found in request", http.StatusInternalServerError)
+		return
+	}
+
+	helpers.SetIPHeaders(httpReq, ip)
+
+	for _, cookie := range r.Cookies() {
+		httpReq.AddCookie(cookie)
+	}
+
+	resp, err := cs.HTTPClient.Do(httpReq)
+	if err != nil {
+		log.Printf("Backend request failed: %v", err)
+		templates.NotFoundHandler(w, r, "Failed to resolve reports", http.StatusInternalServerError)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		log.Printf("Backend returned error: %s", string(body))
+		templates.NotFoundHandler(w, r, "Failed to resolve reports", resp.StatusCode)
+		return
+	}
+
+	if topicIDStr == "" {
+		http.Redirect(w, r, "/topics", http.StatusSeeOther)
+		return
+	}
+	http.Redirect(w, r, "/topic/"+topicIDStr, http.StatusSeeOther)
+}
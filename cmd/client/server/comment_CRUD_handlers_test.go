@@ -0,0 +1,82 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/arnald/forum/cmd/client/config"
+	"github.com/arnald/forum/cmd/client/middleware"
+)
+
+func TestCreateCommentPost(t *testing.T) {
+	t.Run("group: redirect to the new comment", func(t *testing.T) {
+		testCases := newCreateCommentPostTestCases()
+		for _, tt := range testCases {
+			t.Run(tt.name, runCreateCommentPostTest(tt))
+		}
+	})
+}
+
+type createCommentPostTestCase struct {
+	name         string
+	backendBody  string
+	wantLocation string
+	anchors      bool
+}
+
+func newCreateCommentPostTestCases() []createCommentPostTestCase {
+	return []createCommentPostTestCase{
+		{
+			name:         "redirect includes the new comment id",
+			backendBody:  `{"data":{"commentId":42,"status":"approved","message":"Comment created successfully"}}`,
+			anchors:      true,
+			wantLocation: "/topic/7?comments=last#comment-42",
+		},
+		{
+			name:         "anchors turned off",
+			backendBody:  `{"data":{"commentId":42,"status":"approved","message":"Comment created successfully"}}`,
+			anchors:      false,
+			wantLocation: "/topic/7",
+		},
+		{
+			name:         "unreadable response still redirects to the topic",
+			backendBody:  `not json`,
+			anchors:      true,
+			wantLocation: "/topic/7",
+		},
+	}
+}
+
+func runCreateCommentPostTest(tt createCommentPostTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(tt.backendBody))
+		}))
+		defer backend.Close()
+
+		cs := &ClientServer{
+			Config:      &config.Client{CommentAnchors: tt.anchors},
+			HTTPClient:  backend.Client(),
+			BackendURLs: NewBackendURLs(backend.URL),
+		}
+
+		form := url.Values{"topic_id": {"7"}, "content": {"A thoughtful reply"}}
+		req := httptest.NewRequest(http.MethodPost, "/comments/create", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+
+		middleware.GetClientIPMiddleware(http.HandlerFunc(cs.CreateCommentPost)).ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusSeeOther {
+			t.Fatalf("CreateCommentPost() status = %d, want %d: %s", rec.Code, http.StatusSeeOther, rec.Body.String())
+		}
+		if got := rec.Header().Get("Location"); got != tt.wantLocation {
+			t.Errorf("CreateCommentPost() Location = %q, want %q", got, tt.wantLocation)
+		}
+	}
+}
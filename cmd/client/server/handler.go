@@ -2,11 +2,13 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"net/url"
+	"os"
 	"reflect"
 	"strings"
 	"text/template"
@@ -33,6 +35,10 @@ type response struct {
 	Pagination domain.Pagination    `json:"pagination"`
 }
 
+// fallbackCategoriesFile is served on the homepage only when the backend
+// cannot be reached at all.
+const fallbackCategoriesFile = "cmd/client/data/categories.json"
+
 // backendError is a custom error type for backend errors.
 type backendError string
 
@@ -47,17 +53,7 @@ func (cs *ClientServer) HomePage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	defaultCategoriesOptions := &categoriesRequest{
-		OrderBy: "created_at",
-		Order:   "desc",
-		Search:  "",
-	}
-
-	backendURL, err := createURLWithParams(cs.BackendURLs.CategoriesAllURL(), defaultCategoriesOptions)
-	if err != nil {
-		http.Error(w, "Error creating URL params", http.StatusInternalServerError)
-		return
-	}
+	backendURL := cs.BackendURLs.CategoryTreeURL()
 
 	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
 	defer cancel()
@@ -75,19 +71,25 @@ func (cs *ClientServer) HomePage(w http.ResponseWriter, r *http.Request) {
 
 	helpers.SetIPHeaders(httpReq, ip)
 
-	backendResp, err := cs.HTTPClient.Do(httpReq)
-	if err != nil {
-		http.Error(w, "Error with the response", http.StatusInternalServerError)
-		return
-	}
-	defer backendResp.Body.Close()
-
 	var categoryData response
-	err = helpers.DecodeBackendResponse(backendResp, &categoryData)
+
+	backendResp, err := cs.HTTPClient.Do(httpReq)
 	if err != nil {
-		log.Printf("Decode error: %v, Status: %d, URL: %s", err, backendResp.StatusCode, backendURL)
-		http.Error(w, "Error with decoding response into data struct", http.StatusInternalServerError)
-		return
+		log.Printf("Backend unreachable, serving static categories: %v", err)
+		categoryData.Categories, err = loadFallbackCategories()
+		if err != nil {
+			http.Error(w, "Error with the response", http.StatusInternalServerError)
+			return
+		}
+	} else {
+		defer backendResp.Body.Close()
+
+		err = helpers.DecodeBackendResponse(backendResp, &categoryData)
+		if err != nil {
+			log.Printf("Decode error: %v, Status: %d, URL: %s", err, backendResp.StatusCode, backendURL)
+			http.Error(w, "Error with decoding response into data struct", http.StatusInternalServerError)
+			return
+		}
 	}
 
 	categoryData.Categories = helpers.PrepareCategories(categoryData.Categories)
@@ -96,7 +98,7 @@ func (cs *ClientServer) HomePage(w http.ResponseWriter, r *http.Request) {
 
 	categoryData.User = user
 
-	tmpl, err := template.ParseFiles(
+	tmpl, err := template.New("base").Funcs(templates.FuncMap(r)).ParseFiles(
 		"frontend/html/layouts/base.html",
 		"frontend/html/pages/home.html",
 		"frontend/html/partials/navbar.html",
@@ -116,6 +118,21 @@ func (cs *ClientServer) HomePage(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func loadFallbackCategories() ([]domain.Category, error) {
+	raw, err := os.ReadFile(fallbackCategoriesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var data domain.CategoryData
+	err = json.Unmarshal(raw, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Data.Categories, nil
+}
+
 var ErrFailedToCreateURL = errors.New("failed to create url with params")
 
 func createURLWithParams(domainURL string, params any) (string, error) {
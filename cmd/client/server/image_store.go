@@ -0,0 +1,79 @@
+package server
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ImageStore keeps uploaded images and hands back the URL path each one is
+// served from. The local backend writes to disk; other backends, such as an
+// object store shared between instances, only need to satisfy this interface.
+type ImageStore interface {
+	// Save stores the image read from r under a fresh name ending in ext and
+	// returns its URL path. A non-empty owner, the uploader's user ID, starts
+	// the name so the backend can tell who stored it.
+	Save(r io.Reader, owner, ext string) (string, error)
+	// Delete removes an image previously returned by Save. Paths the store
+	// does not own, such as OAuth avatar URLs, and images already gone are
+	// ignored.
+	Delete(path string) error
+}
+
+// localImageStore keeps images in a directory served under urlPrefix.
+type localImageStore struct {
+	dir       string
+	urlPrefix string
+}
+
+// NewLocalImageStore returns an ImageStore writing to dir, whose files are
+// served under urlPrefix.
+func NewLocalImageStore(dir, urlPrefix string) ImageStore {
+	return &localImageStore{dir: dir, urlPrefix: urlPrefix}
+}
+
+func (s *localImageStore) Save(r io.Reader, owner, ext string) (string, error) {
+	err := os.MkdirAll(s.dir, uploadDirPerm)
+	if err != nil {
+		return "", err
+	}
+
+	filename := uuid.New().String() + ext
+	if owner != "" {
+		filename = owner + avatarOwnerSeparator + filename
+	}
+	destFile, err := os.Create(filepath.Join(s.dir, filename))
+	if err != nil {
+		return "", err
+	}
+	defer destFile.Close()
+
+	_, err = io.Copy(destFile, r)
+	if err != nil {
+		_ = os.Remove(destFile.Name())
+		return "", err
+	}
+
+	return s.urlPrefix + filename, nil
+}
+
+func (s *localImageStore) Delete(path string) error {
+	filename, found := strings.CutPrefix(path, s.urlPrefix)
+	if !found || filename == "" {
+		return nil
+	}
+
+	filePath := filepath.Clean(filepath.Join(s.dir, filename))
+	if !strings.HasPrefix(filePath, filepath.Clean(s.dir)+string(os.PathSeparator)) {
+		return nil
+	}
+
+	err := os.Remove(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
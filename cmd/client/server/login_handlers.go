@@ -28,6 +28,7 @@ type LoginFormErrors struct {
 	UsernameError string               `json:"username,omitempty"`
 	EmailError    string               `json:"email,omitempty"`
 	PasswordError string               `json:"password,omitempty"`
+	Remember      bool                 `json:"-"`
 }
 
 // BackendLoginRequest - sent to backend.
@@ -35,14 +36,17 @@ type BackendLoginRequest struct {
 	Email    string `json:"email,omitempty"`
 	Username string `json:"username,omitempty"`
 	Password string `json:"password"`
+	Remember bool   `json:"remember,omitempty"`
 }
 
 // BackendLoginResponse - response from backend.
 type BackendLoginResponse struct {
-	UserID       string `json:"userId"`
-	Username     string `json:"username"`
-	AccessToken  string `json:"accessToken"`
-	RefreshToken string `json:"refreshToken"`
+	ExpiresAt        time.Time `json:"expiresAt"`
+	RefreshExpiresAt time.Time `json:"refreshExpiresAt"`
+	UserID           string    `json:"userId"`
+	Username         string    `json:"username"`
+	AccessToken      string    `json:"accessToken"`
+	RefreshToken     string    `json:"refreshToken"`
 }
 
 // LoginPage handles GET requests to /login.
@@ -54,7 +58,7 @@ func (cs *ClientServer) LoginPage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	templates.RenderTemplate(w, "login", LoginFormErrors{})
+	templates.RenderTemplate(w, r, "login", LoginFormErrors{})
 }
 
 // LoginPost handles POST requests to /login.
@@ -70,6 +74,7 @@ func (cs *ClientServer) LoginPost(w http.ResponseWriter, r *http.Request) {
 
 	data := LoginFormErrors{
 		Password: password,
+		Remember: r.FormValue("remember") == "on",
 	}
 
 	data.PasswordError = validation.ValidatePassword(password)
@@ -99,16 +104,16 @@ func (cs *ClientServer) handleEmailLogin(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
-	backendResp, backendErr := cs.loginWithBackendEmail(ctx, email, password, ip)
+	backendResp, backendErr := cs.loginWithBackendEmail(ctx, email, password, data.Remember, ip)
 	if backendErr != nil {
 		// Backend validation/login failed
 		data.EmailError = ""
 		data.PasswordError = backendErr.Error()
-		templates.RenderTemplate(w, "login", data)
+		templates.RenderTemplate(w, r, "login", data)
 		return
 	}
 
-	cs.setSessionCookies(w, backendResp.AccessToken, backendResp.RefreshToken)
+	cs.setLoginCookies(w, backendResp, data.Remember)
 
 	// SUCCESS - User logged in, redirect to homepage
 	log.Printf("User logged in successfully with email: %s (ID: %s)", backendResp.Username, backendResp.UserID)
@@ -131,17 +136,17 @@ func (cs *ClientServer) handleUsernameLogin(w http.ResponseWriter, r *http.Reque
 		http.Error(w, "Error no IP found in request", http.StatusInternalServerError)
 	}
 
-	backendResp, backendErr := cs.loginWithBackendUsername(ctx, username, password, ip)
+	backendResp, backendErr := cs.loginWithBackendUsername(ctx, username, password, data.Remember, ip)
 	if backendErr != nil {
 		// Backend validation/login failed
 		data.UsernameError = ""
 		data.PasswordError = backendErr.Error()
-		templates.RenderTemplate(w, "login", data)
+		templates.RenderTemplate(w, r, "login", data)
 		return
 	}
 
 	// Set cookies for session persistence
-	cs.setSessionCookies(w, backendResp.AccessToken, backendResp.RefreshToken)
+	cs.setLoginCookies(w, backendResp, data.Remember)
 
 	// SUCCESS - User logged in, redirect to homepage
 	log.Printf("User logged in successfully with username: %s (ID: %s)", backendResp.Username, backendResp.UserID)
@@ -149,19 +154,21 @@ func (cs *ClientServer) handleUsernameLogin(w http.ResponseWriter, r *http.Reque
 }
 
 // loginWithBackendEmail sends login request to backend email endpoint.
-func (cs *ClientServer) loginWithBackendEmail(ctx context.Context, email string, password string, ip string) (*BackendLoginResponse, error) {
+func (cs *ClientServer) loginWithBackendEmail(ctx context.Context, email string, password string, remember bool, ip string) (*BackendLoginResponse, error) {
 	req := BackendLoginRequest{
 		Email:    email,
 		Password: password,
+		Remember: remember,
 	}
 	return cs.sendLoginRequest(ctx, cs.BackendURLs.LoginEmailURL(), req, ip)
 }
 
 // loginWithBackendUsername sends login request to backend username endpoint.
-func (cs *ClientServer) loginWithBackendUsername(ctx context.Context, username string, password string, ip string) (*BackendLoginResponse, error) {
+func (cs *ClientServer) loginWithBackendUsername(ctx context.Context, username string, password string, remember bool, ip string) (*BackendLoginResponse, error) {
 	req := BackendLoginRequest{
 		Username: username,
 		Password: password,
+		Remember: remember,
 	}
 
 	return cs.sendLoginRequest(ctx, cs.BackendURLs.LoginUsernameURL(), req, ip)
@@ -192,6 +199,10 @@ func (cs *ClientServer) sendLoginRequest(ctx context.Context, backendURL string,
 		if errResp.Message != "" {
 			return nil, backendError(errResp.Message)
 		}
+		// A locked account says so, rather than looking like a wrong password.
+		if resp.StatusCode == http.StatusTooManyRequests && errResp.Error != "" {
+			return nil, backendError(errResp.Error)
+		}
 		return nil, backendError("Login failed. Please try again.")
 	}
 
@@ -205,12 +216,35 @@ func (cs *ClientServer) sendLoginRequest(ctx context.Context, backendURL string,
 	return &target, nil
 }
 
+// setLoginCookies sets the session cookies after a password login. A
+// remembered login keeps its cookies for as long as the backend session
+// lasts, so they survive the browser being closed.
+func (cs *ClientServer) setLoginCookies(w http.ResponseWriter, resp *BackendLoginResponse, remember bool) {
+	if !remember {
+		cs.setSessionCookies(w, resp.AccessToken, resp.RefreshToken)
+		return
+	}
+
+	cs.setSessionCookiesWithMaxAge(w, resp.AccessToken, resp.RefreshToken,
+		int(time.Until(resp.ExpiresAt).Seconds()),
+		int(time.Until(resp.RefreshExpiresAt).Seconds()),
+	)
+}
+
 // setSessionCookies sets the access and refresh tokens as cookies.
 func (cs *ClientServer) setSessionCookies(w http.ResponseWriter, accessToken, refreshToken string) {
-	// Use secure cookies when in production or when using HTTPS
-	isSecure := cs.Config.Environment == "production" || cs.Config.TLSCertFile != ""
+	cs.setSessionCookiesWithMaxAge(w, accessToken, refreshToken,
+		int(float64(accessTokenMaxAge)*time.Minute.Seconds()),
+		int(float64(refreshTokenMaxAge)*time.Hour.Seconds()),
+	)
+}
+
+// setSessionCookiesWithMaxAge sets the access and refresh tokens as cookies
+// that expire after the given number of seconds.
+func (cs *ClientServer) setSessionCookiesWithMaxAge(w http.ResponseWriter, accessToken, refreshToken string, accessMaxAge, refreshMaxAge int) {
+	isSecure := cs.Config.SecureCookies
 
-	log.Printf("Setting session cookies - isSecure: %v, TLSCertFile: %v", isSecure, cs.Config.TLSCertFile)
+	log.Printf("Setting session cookies - isSecure: %v", isSecure)
 
 	accessCookie := &http.Cookie{
 		Name:     "access_token",
@@ -219,7 +253,7 @@ func (cs *ClientServer) setSessionCookies(w http.ResponseWriter, accessToken, re
 		HttpOnly: true,
 		Secure:   isSecure,
 		SameSite: http.SameSiteLaxMode,
-		MaxAge:   int(float64(accessTokenMaxAge) * time.Minute.Seconds()),
+		MaxAge:   accessMaxAge,
 	}
 
 	refreshCookie := &http.Cookie{
@@ -229,7 +263,7 @@ func (cs *ClientServer) setSessionCookies(w http.ResponseWriter, accessToken, re
 		HttpOnly: true,
 		Secure:   isSecure,
 		SameSite: http.SameSiteLaxMode,
-		MaxAge:   int(float64(refreshTokenMaxAge) * time.Hour.Seconds()),
+		MaxAge:   refreshMaxAge,
 	}
 
 	http.SetCookie(w, accessCookie)
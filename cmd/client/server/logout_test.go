@@ -0,0 +1,69 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/arnald/forum/cmd/client/config"
+)
+
+func TestLogout(t *testing.T) {
+	testCases := []struct {
+		name        string
+		backendCode int
+	}{
+		{name: "backend revokes the session", backendCode: http.StatusOK},
+		{name: "cookies are cleared even if the backend fails", backendCode: http.StatusInternalServerError},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			revoked := false
+			backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != pathLogout || r.Method != http.MethodPost {
+					t.Errorf("backend got %s %s, want POST %s", r.Method, r.URL.Path, pathLogout)
+				}
+				for name, want := range map[string]string{"access_token": "access", "refresh_token": "refresh"} {
+					cookie, err := r.Cookie(name)
+					if err != nil || cookie.Value != want {
+						t.Errorf("backend %s cookie = %v, %v; want %q", name, cookie, err, want)
+					}
+				}
+				revoked = true
+				w.WriteHeader(tt.backendCode)
+			}))
+			t.Cleanup(backend.Close)
+
+			cs := &ClientServer{
+				Config:      &config.Client{},
+				HTTPClient:  backend.Client(),
+				BackendURLs: NewBackendURLs(backend.URL),
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/logout", nil)
+			req.AddCookie(&http.Cookie{Name: "access_token", Value: "access"})
+			req.AddCookie(&http.Cookie{Name: "refresh_token", Value: "refresh"})
+			rec := httptest.NewRecorder()
+
+			cs.Logout(rec, req)
+
+			if !revoked {
+				t.Error("Logout() did not call the backend logout endpoint")
+			}
+			if rec.Code != http.StatusSeeOther || rec.Header().Get("Location") != "/" {
+				t.Errorf("Logout() = %d %q, want %d to /", rec.Code, rec.Header().Get("Location"), http.StatusSeeOther)
+			}
+
+			cleared := make(map[string]bool)
+			for _, cookie := range rec.Result().Cookies() {
+				cleared[cookie.Name] = cookie.MaxAge < 0 && cookie.Value == ""
+			}
+			for _, name := range []string{"access_token", "refresh_token"} {
+				if !cleared[name] {
+					t.Errorf("Logout() did not expire %s", name)
+				}
+			}
+		})
+	}
+}
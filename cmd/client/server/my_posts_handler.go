@@ -0,0 +1,83 @@
+package server
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"text/template"
+
+	"github.com/arnald/forum/cmd/client/domain"
+	"github.com/arnald/forum/cmd/client/helpers"
+	"github.com/arnald/forum/cmd/client/helpers/templates"
+	"github.com/arnald/forum/cmd/client/middleware"
+)
+
+// MyPostsPage handles GET requests to /my-posts, where users follow the
+// moderation of their own comments.
+func (cs *ClientServer) MyPostsPage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, cs.BackendURLs.UserSubmissionsURL(), nil)
+	if err != nil {
+		http.Error(w, "Error creating request", http.StatusInternalServerError)
+		return
+	}
+
+	ip := middleware.GetIPFromContext(r)
+	if ip == "" {
+		http.Error(w, "Error no IP found in request", http.StatusInternalServerError)
+		return
+	}
+
+	helpers.SetIPHeaders(httpReq, ip)
+
+	for _, cookie := range r.Cookies() {
+		httpReq.AddCookie(cookie)
+	}
+
+	backendResp, err := cs.HTTPClient.Do(httpReq)
+	if err != nil {
+		log.Printf("Error making request to backend: %v", err)
+		http.Error(w, "Error communicating with backend", http.StatusInternalServerError)
+		return
+	}
+	defer backendResp.Body.Close()
+
+	if backendResp.StatusCode != http.StatusOK {
+		log.Printf("Backend returned status: %d", backendResp.StatusCode)
+		templates.NotFoundHandler(w, r, "Failed to load your posts", http.StatusInternalServerError)
+		return
+	}
+
+	var pageData domain.SubmissionsData
+	err = helpers.DecodeBackendResponse(backendResp, &pageData)
+	if err != nil {
+		http.Error(w, "Error decoding the response to json", http.StatusInternalServerError)
+		return
+	}
+
+	pageData.User = middleware.GetUserFromContext(r.Context())
+
+	tmpl, err := template.New("base").Funcs(templates.FuncMap(r)).ParseFiles(
+		"frontend/html/layouts/base.html",
+		"frontend/html/pages/my_posts.html",
+		"frontend/html/partials/navbar.html",
+		"frontend/html/partials/footer.html",
+	)
+	if err != nil {
+		templates.NotFoundHandler(w, r, "Failed to load page", http.StatusInternalServerError)
+		return
+	}
+
+	err = tmpl.ExecuteTemplate(w, "base", pageData)
+	if err != nil {
+		log.Println("Error executing template:", err)
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/arnald/forum/cmd/client/domain"
+	"github.com/arnald/forum/cmd/client/helpers/templates"
+	"github.com/arnald/forum/cmd/client/helpers/validation"
+	"github.com/arnald/forum/cmd/client/middleware"
+)
+
+// PasswordResetFormData backs both the forgot and reset password pages.
+type PasswordResetFormData struct {
+	Email         string
+	Token         string
+	Message       string
+	EmailError    string
+	PasswordError string
+}
+
+type backendForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+type backendResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// ForgotPasswordPage handles GET requests to /forgot-password.
+func (cs *ClientServer) ForgotPasswordPage(w http.ResponseWriter, r *http.Request) {
+	templates.RenderTemplate(w, r, "forgot_password", PasswordResetFormData{})
+}
+
+// ForgotPasswordPost handles POST requests to /forgot-password.
+func (cs *ClientServer) ForgotPasswordPost(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	data := PasswordResetFormData{
+		Email: strings.TrimSpace(r.FormValue("email")),
+	}
+
+	if data.Email == "" {
+		data.EmailError = "Email is required."
+		templates.RenderTemplate(w, r, "forgot_password", data)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+
+	resp, err := cs.newRequest(ctx, http.MethodPost, cs.BackendURLs.ForgotPasswordURL(),
+		backendForgotPasswordRequest{Email: data.Email}, middleware.GetIPFromContext(r))
+	if err != nil {
+		data.EmailError = err.Error()
+		templates.RenderTemplate(w, r, "forgot_password", data)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		data.EmailError = backendFormError(resp, "email")
+		templates.RenderTemplate(w, r, "forgot_password", data)
+		return
+	}
+
+	data.Message = "If an account uses that email, a reset link is on its way. It expires in one hour."
+	templates.RenderTemplate(w, r, "forgot_password", data)
+}
+
+// ResetPasswordPage handles GET requests to /reset-password.
+func (cs *ClientServer) ResetPasswordPage(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		http.Redirect(w, r, "/forgot-password", http.StatusSeeOther)
+		return
+	}
+
+	templates.RenderTemplate(w, r, "reset_password", PasswordResetFormData{Token: token})
+}
+
+// ResetPasswordPost handles POST requests to /reset-password.
+func (cs *ClientServer) ResetPasswordPost(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	password := strings.TrimSpace(r.FormValue("password"))
+	data := PasswordResetFormData{
+		Token:         r.FormValue("token"),
+		PasswordError: validation.ValidatePassword(password),
+	}
+
+	if data.PasswordError == "" && password != strings.TrimSpace(r.FormValue("confirmPassword")) {
+		data.PasswordError = "Passwords do not match."
+	}
+	if data.PasswordError != "" {
+		templates.RenderTemplate(w, r, "reset_password", data)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+
+	resp, err := cs.newRequest(ctx, http.MethodPost, cs.BackendURLs.ResetPasswordURL(),
+		backendResetPasswordRequest{Token: data.Token, Password: password}, middleware.GetIPFromContext(r))
+	if err != nil {
+		data.PasswordError = err.Error()
+		templates.RenderTemplate(w, r, "reset_password", data)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		data.PasswordError = backendFormError(resp, "password")
+		templates.RenderTemplate(w, r, "reset_password", data)
+		return
+	}
+
+	data.Token = ""
+	data.Message = "Your password has been reset and you have been signed out everywhere."
+	templates.RenderTemplate(w, r, "reset_password", data)
+}
+
+// backendFormError picks the message to show for a failed backend call on an
+// account form, preferring the error for the form's own field.
+func backendFormError(resp *http.Response, field string) string {
+	var errResp domain.BackendErrorResponse
+	err := json.NewDecoder(resp.Body).Decode(&errResp)
+	if err != nil {
+		return "Something went wrong. Please try again."
+	}
+
+	if msg := errResp.Fields[field]; msg != "" {
+		return msg
+	}
+	if errResp.Error != "" {
+		return errResp.Error
+	}
+	return "Something went wrong. Please try again."
+}
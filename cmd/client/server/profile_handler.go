@@ -0,0 +1,95 @@
+package server
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+
+	"github.com/arnald/forum/cmd/client/domain"
+	"github.com/arnald/forum/cmd/client/helpers"
+	"github.com/arnald/forum/cmd/client/helpers/templates"
+	"github.com/arnald/forum/cmd/client/middleware"
+)
+
+// ProfilePage handles requests to a user's public profile at /user/{username}.
+func (cs *ClientServer) ProfilePage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	username := strings.TrimPrefix(r.URL.Path, "/user/")
+	if username == "" || strings.Contains(username, "/") {
+		templates.NotFoundHandler(w, r, notFoundMessage, http.StatusNotFound)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, cs.BackendURLs.UserProfileURL()+url.PathEscape(username), nil)
+	if err != nil {
+		http.Error(w, "Error creating request", http.StatusInternalServerError)
+		return
+	}
+
+	ip := middleware.GetIPFromContext(r)
+	if ip == "" {
+		http.Error(w, "Error no IP found in request", http.StatusInternalServerError)
+	}
+
+	helpers.SetIPHeaders(httpReq, ip)
+
+	// Forward the session so the owner also sees their held comments.
+	for _, cookie := range r.Cookies() {
+		httpReq.AddCookie(cookie)
+	}
+
+	backendResp, err := cs.HTTPClient.Do(httpReq)
+	if err != nil {
+		log.Printf("Error making request to backend: %v", err)
+		http.Error(w, "Error communicating with backend", http.StatusInternalServerError)
+		return
+	}
+	defer backendResp.Body.Close()
+
+	if backendResp.StatusCode == http.StatusNotFound {
+		templates.NotFoundHandler(w, r, "User not found", http.StatusNotFound)
+		return
+	}
+
+	if backendResp.StatusCode != http.StatusOK {
+		log.Printf("Backend returned status: %d", backendResp.StatusCode)
+		templates.NotFoundHandler(w, r, "Error loading profile", http.StatusInternalServerError)
+		return
+	}
+
+	var profileData domain.ProfileData
+	err = helpers.DecodeBackendResponse(backendResp, &profileData)
+	if err != nil {
+		http.Error(w, "Error decoding the response to json", http.StatusInternalServerError)
+		return
+	}
+
+	profileData.User = middleware.GetUserFromContext(r.Context())
+
+	tmpl, err := template.New("base").Funcs(templates.FuncMap(r)).ParseFiles(
+		"frontend/html/layouts/base.html",
+		"frontend/html/pages/profile.html",
+		"frontend/html/partials/navbar.html",
+		"frontend/html/partials/footer.html",
+	)
+	if err != nil {
+		templates.NotFoundHandler(w, r, "Failed to load page", http.StatusInternalServerError)
+		return
+	}
+
+	err = tmpl.ExecuteTemplate(w, "base", profileData)
+	if err != nil {
+		log.Println("Error executing template:", err)
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/arnald/forum/cmd/client/helpers"
+)
+
+// RefreshSession trades the refresh_token cookie for a new backend session,
+// rotates both session cookies and sends the user on to the local path in
+// the next query parameter. A refresh token the backend no longer accepts
+// signs the user out. Rotating the tokens changes state, so only a POST,
+// which carries a CSRF token, is accepted.
+func (cs *ClientServer) RefreshSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), contextTimeout)
+	defer cancel()
+
+	resp, err := cs.newRequestWithCookies(ctx, http.MethodPost, cs.BackendURLs.RefreshURL(), nil, r)
+	if err != nil {
+		log.Printf("Failed to refresh session: %v", err)
+		http.Error(w, "Failed to refresh session", http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		cs.clearSessionCookies(w)
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("Backend refresh returned status: %d", resp.StatusCode)
+		http.Error(w, "Failed to refresh session", http.StatusBadGateway)
+		return
+	}
+
+	var refreshed BackendLoginResponse
+	err = helpers.DecodeBackendResponse(resp, &refreshed)
+	if err != nil {
+		log.Printf("Failed to decode refresh response: %v", err)
+		http.Error(w, "Failed to refresh session", http.StatusBadGateway)
+		return
+	}
+
+	cs.setSessionCookiesWithMaxAge(w, refreshed.AccessToken, refreshed.RefreshToken,
+		int(time.Until(refreshed.ExpiresAt).Seconds()),
+		int(time.Until(refreshed.RefreshExpiresAt).Seconds()),
+	)
+
+	http.Redirect(w, r, localRedirect(r.URL.Query().Get("next")), http.StatusSeeOther)
+}
+
+// localRedirect returns target when it is a path on this site and "/"
+// otherwise, so a crafted next parameter cannot send users elsewhere.
+func localRedirect(target string) string {
+	if !strings.HasPrefix(target, "/") || strings.HasPrefix(target, "//") || strings.HasPrefix(target, "/\\") {
+		return "/"
+	}
+	return target
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/arnald/forum/cmd/client/config"
+	"github.com/arnald/forum/cmd/client/middleware"
+)
+
+func TestRefreshSession(t *testing.T) {
+	testCases := []struct {
+		name         string
+		next         string
+		backendBody  string
+		backendCode  int
+		wantLocation string
+		wantAccess   string
+		wantRefresh  string
+		wantCleared  bool
+	}{
+		{
+			name:        "rotates both cookies and returns to the page",
+			next:        "/topic/7",
+			backendCode: http.StatusOK,
+			backendBody: `{"data":{"userId":"alice","accessToken":"new-access","refreshToken":"new-refresh",` +
+				`"expiresAt":"2999-01-01T00:00:00Z","refreshExpiresAt":"2999-01-02T00:00:00Z"}}`,
+			wantLocation: "/topic/7",
+			wantAccess:   "new-access",
+			wantRefresh:  "new-refresh",
+		},
+		{
+			name:        "does not follow a next parameter off the site",
+			next:        "//evil.example",
+			backendCode: http.StatusOK,
+			backendBody: `{"data":{"userId":"alice","accessToken":"new-access","refreshToken":"new-refresh",` +
+				`"expiresAt":"2999-01-01T00:00:00Z","refreshExpiresAt":"2999-01-02T00:00:00Z"}}`,
+			wantLocation: "/",
+			wantAccess:   "new-access",
+			wantRefresh:  "new-refresh",
+		},
+		{
+			name:         "rejected refresh token signs the user out",
+			next:         "/topic/7",
+			backendCode:  http.StatusUnauthorized,
+			backendBody:  `{"error":"Refresh token expired or revoked"}`,
+			wantLocation: "/login",
+			wantCleared:  true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != pathRefresh || r.Method != http.MethodPost {
+					t.Errorf("backend got %s %s, want POST %s", r.Method, r.URL.Path, pathRefresh)
+				}
+				cookie, err := r.Cookie("refresh_token")
+				if err != nil || cookie.Value != "old-refresh" {
+					t.Errorf("backend refresh_token cookie = %v, %v; want old-refresh", cookie, err)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.backendCode)
+				_, _ = w.Write([]byte(tt.backendBody))
+			}))
+			t.Cleanup(backend.Close)
+
+			cs := &ClientServer{
+				Config:      &config.Client{},
+				HTTPClient:  backend.Client(),
+				BackendURLs: NewBackendURLs(backend.URL),
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/refresh?next="+tt.next, nil)
+			req.AddCookie(&http.Cookie{Name: "refresh_token", Value: "old-refresh"})
+			rec := httptest.NewRecorder()
+
+			middleware.GetClientIPMiddleware(http.HandlerFunc(cs.RefreshSession)).ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusSeeOther {
+				t.Fatalf("RefreshSession() status = %d, want %d: %s", rec.Code, http.StatusSeeOther, rec.Body.String())
+			}
+			if got := rec.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("RefreshSession() Location = %q, want %q", got, tt.wantLocation)
+			}
+
+			cookies := make(map[string]*http.Cookie)
+			for _, cookie := range rec.Result().Cookies() {
+				cookies[cookie.Name] = cookie
+			}
+			for name, want := range map[string]string{"access_token": tt.wantAccess, "refresh_token": tt.wantRefresh} {
+				cookie, ok := cookies[name]
+				if !ok {
+					t.Errorf("RefreshSession() did not set %s", name)
+					continue
+				}
+				if tt.wantCleared {
+					if cookie.MaxAge >= 0 {
+						t.Errorf("%s MaxAge = %d, want it cleared", name, cookie.MaxAge)
+					}
+					continue
+				}
+				if cookie.Value != want || cookie.MaxAge <= 0 {
+					t.Errorf("%s = %q (max-age %d), want %q kept alive", name, cookie.Value, cookie.MaxAge, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRefreshSession_RequiresCSRFPost(t *testing.T) {
+	backendCalled := false
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		backendCalled = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(backend.Close)
+
+	cs := &ClientServer{
+		Config:      &config.Client{},
+		HTTPClient:  backend.Client(),
+		BackendURLs: NewBackendURLs(backend.URL),
+	}
+	handler := middleware.NewCSRF("secret", false).Protect(http.HandlerFunc(cs.RefreshSession))
+
+	testCases := []struct {
+		name       string
+		method     string
+		wantStatus int
+	}{
+		{name: "GET is refused", method: http.MethodGet, wantStatus: http.StatusMethodNotAllowed},
+		{name: "POST without a CSRF token is refused", method: http.MethodPost, wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/refresh?next=/", nil)
+			req.AddCookie(&http.Cookie{Name: "refresh_token", Value: "old-refresh"})
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("RefreshSession() status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+	if backendCalled {
+		t.Error("RefreshSession() rotated the session without a CSRF-checked POST")
+	}
+}
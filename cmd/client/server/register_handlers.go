@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strings"
@@ -36,6 +37,15 @@ type BackendRegisterResponse struct {
 	Message string `json:"message"`
 }
 
+// registerFieldErrors carries the field-keyed messages returned by the backend.
+type registerFieldErrors struct {
+	Fields map[string]string
+}
+
+func (e *registerFieldErrors) Error() string {
+	return "registration failed"
+}
+
 // RegisterPage handles GET requests to /register.
 func (cs *ClientServer) RegisterPage(w http.ResponseWriter, r *http.Request) {
 	user := middleware.GetUserFromContext(r.Context())
@@ -44,7 +54,7 @@ func (cs *ClientServer) RegisterPage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	templates.RenderTemplate(w, "register", RegisterFormErrors{})
+	templates.RenderTemplate(w, r, "register", RegisterFormErrors{})
 }
 
 // RegisterPost handles POST requests to /register.
@@ -72,7 +82,7 @@ func (cs *ClientServer) RegisterPost(w http.ResponseWriter, r *http.Request) {
 		data.UsernameError = validator.Errors["Username"]
 		data.EmailError = validator.Errors["Email"]
 		data.PasswordError = validator.Errors["Password"]
-		templates.RenderTemplate(w, "register", data)
+		templates.RenderTemplate(w, r, "register", data)
 		return
 	}
 
@@ -95,21 +105,18 @@ func (cs *ClientServer) RegisterPost(w http.ResponseWriter, r *http.Request) {
 	backendResp, backendErr := cs.registerWithBackend(ctx, backendReq, ip)
 	if backendErr != nil {
 		// Backend validation/registration failed
-		data.UsernameError = ""
-		data.EmailError = ""
 		data.Password = ""
 
-		errorMsg := backendErr.Error()
-
-		if strings.Contains(strings.ToLower(errorMsg), "username") ||
-			strings.Contains(strings.ToLower(errorMsg), "email") {
-			data.PasswordError = "This username or email is already taken. Please try another one."
+		var fieldErr *registerFieldErrors
+		if errors.As(backendErr, &fieldErr) {
+			data.UsernameError = fieldErr.Fields["username"]
+			data.EmailError = fieldErr.Fields["email"]
+			data.PasswordError = fieldErr.Fields["password"]
 		} else {
-			// For other errors, show the actual backend error
-			data.PasswordError = errorMsg
+			data.PasswordError = backendErr.Error()
 		}
 
-		templates.RenderTemplate(w, "register", data)
+		templates.RenderTemplate(w, r, "register", data)
 		return
 	}
 
@@ -142,8 +149,11 @@ func (cs *ClientServer) registerWithBackend(ctx context.Context, req BackendRegi
 			return nil, backendError("Registration failed. Please try again.")
 		}
 
-		if errResp.Message != "" {
-			return nil, backendError(errResp.Message)
+		if len(errResp.Fields) > 0 {
+			return nil, &registerFieldErrors{Fields: errResp.Fields}
+		}
+		if errResp.Error != "" {
+			return nil, backendError(errResp.Error)
 		}
 		return nil, backendError("Registration failed. Please try again.")
 	}
@@ -0,0 +1,225 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"text/template"
+
+	"github.com/arnald/forum/cmd/client/domain"
+	"github.com/arnald/forum/cmd/client/helpers"
+	"github.com/arnald/forum/cmd/client/helpers/templates"
+	"github.com/arnald/forum/cmd/client/middleware"
+)
+
+const reportReasonsPage = "/admin/report-reasons"
+
+// reportReasonErrors maps the error code a failed change redirects with to
+// the message shown on the page, so no request text is echoed back.
+var reportReasonErrors = map[string]string{
+	"invalid": "Reasons must be between 3 and 50 characters.",
+	"exists":  "That reason already exists. Restore it instead.",
+	"failed":  "The change could not be saved. Please try again.",
+}
+
+// ReportReasonsPage handles GET requests to /admin/report-reasons, where
+// admins add, retire and restore the reasons offered on the report form.
+func (cs *ClientServer) ReportReasonsPage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if !user.IsAdmin() {
+		templates.NotFoundHandler(w, r, "Admin access required", http.StatusForbidden)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, cs.BackendURLs.ReportReasonsURL()+"?all=true", nil)
+	if err != nil {
+		http.Error(w, "Error creating request", http.StatusInternalServerError)
+		return
+	}
+
+	ip := middleware.GetIPFromContext(r)
+	if ip == "" {
+		http.Error(w, "Error no IP found in request", http.StatusInternalServerError)
+		return
+	}
+
+	helpers.SetIPHeaders(httpReq, ip)
+
+	for _, cookie := range r.Cookies() {
+		httpReq.AddCookie(cookie)
+	}
+
+	backendResp, err := cs.HTTPClient.Do(httpReq)
+	if err != nil {
+		log.Printf("Error making request to backend: %v", err)
+		http.Error(w, "Error communicating with backend", http.StatusInternalServerError)
+		return
+	}
+	defer backendResp.Body.Close()
+
+	var pageData domain.ReportReasonsPageData
+	err = helpers.DecodeBackendResponse(backendResp, &pageData)
+	if err != nil {
+		http.Error(w, "Error decoding the response to json", http.StatusInternalServerError)
+		return
+	}
+
+	pageData.User = user
+	pageData.Error = reportReasonErrors[r.URL.Query().Get("error")]
+
+	tmpl, err := template.New("base").Funcs(templates.FuncMap(r)).ParseFiles(
+		"frontend/html/layouts/base.html",
+		"frontend/html/pages/report_reasons.html",
+		"frontend/html/partials/navbar.html",
+		"frontend/html/partials/footer.html",
+	)
+	if err != nil {
+		templates.NotFoundHandler(w, r, "Failed to load page", http.StatusInternalServerError)
+		return
+	}
+
+	err = tmpl.ExecuteTemplate(w, "base", pageData)
+	if err != nil {
+		log.Println("Error executing template:", err)
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+// CreateReportReasonPost handles POST requests to /admin/report-reasons/create.
+func (cs *ClientServer) CreateReportReasonPost(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	err := r.ParseForm()
+	if err != nil {
+		log.Printf("Error parsing form: %v", err)
+		http.Error(w, "Error parsing form", http.StatusBadRequest)
+		return
+	}
+
+	body, err := json.Marshal(struct {
+		Label string `json:"label"`
+	}{
+		Label: r.FormValue("label"),
+	})
+	if err != nil {
+		http.Error(w, "Error encoding request", http.StatusInternalServerError)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+
+	resp, ok := cs.forwardReportReason(ctx, w, r, cs.BackendURLs.CreateReportReasonURL(), bytes.NewReader(body))
+	if !ok {
+		return
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusCreated:
+		http.Redirect(w, r, reportReasonsPage, http.StatusSeeOther)
+	case http.StatusBadRequest:
+		http.Redirect(w, r, reportReasonsPage+"?error=invalid", http.StatusSeeOther)
+	case http.StatusConflict:
+		http.Redirect(w, r, reportReasonsPage+"?error=exists", http.StatusSeeOther)
+	default:
+		respBody, _ := io.ReadAll(resp.Body)
+		log.Printf("Backend returned error: %s", string(respBody))
+		http.Redirect(w, r, reportReasonsPage+"?error=failed", http.StatusSeeOther)
+	}
+}
+
+// UpdateReportReasonPost handles POST requests to /admin/report-reasons/update,
+// retiring or restoring the reason named by reason_id.
+func (cs *ClientServer) UpdateReportReasonPost(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	err := r.ParseForm()
+	if err != nil {
+		log.Printf("Error parsing form: %v", err)
+		http.Error(w, "Error parsing form", http.StatusBadRequest)
+		return
+	}
+
+	reasonIDStr := r.FormValue("reason_id")
+	_, err = strconv.Atoi(reasonIDStr)
+	if err != nil {
+		log.Printf("Invalid report reason ID: %v", err)
+		http.Error(w, "Invalid report reason ID", http.StatusBadRequest)
+		return
+	}
+
+	backendURL := cs.BackendURLs.RetireReportReasonURL() + reasonIDStr
+	if r.FormValue("action") == "restore" {
+		backendURL = cs.BackendURLs.RestoreReportReasonURL() + reasonIDStr
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+
+	resp, ok := cs.forwardReportReason(ctx, w, r, backendURL, nil)
+	if !ok {
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		log.Printf("Backend returned error: %s", string(respBody))
+		http.Redirect(w, r, reportReasonsPage+"?error=failed", http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, reportReasonsPage, http.StatusSeeOther)
+}
+
+// forwardReportReason posts body to the backend on behalf of the admin. When
+// it returns false an error response has already been written.
+func (cs *ClientServer) forwardReportReason(ctx context.Context, w http.ResponseWriter, r *http.Request, backendURL string, body io.Reader) (*http.Response, bool) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, backendURL, body)
+	if err != nil {
+		log.Printf("Error creating request: %v", err)
+		http.Error(w, "Error creating request", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	ip := middleware.GetIPFromContext(r)
+	if ip == "" {
+		http.Error(w, "Error no IP found in request", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	helpers.SetIPHeaders(httpReq, ip)
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	for _, cookie := range r.Cookies() {
+		httpReq.AddCookie(cookie)
+	}
+
+	resp, err := cs.HTTPClient.Do(httpReq)
+	if err != nil {
+		log.Printf("Backend request failed: %v", err)
+		templates.NotFoundHandler(w, r, "Failed to update report reasons", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	return resp, true
+}
@@ -8,6 +8,7 @@ import (
 	"log"
 	"net/http"
 	"net/http/cookiejar"
+	"path/filepath"
 
 	"github.com/arnald/forum/cmd/client/config"
 	"github.com/arnald/forum/cmd/client/helpers"
@@ -22,6 +23,9 @@ type ClientServer struct {
 	HTTPClient  *http.Client
 	SseClient   *http.Client
 	BackendURLs *BackendURLs
+	// Images stores topic images and Avatars stores profile pictures.
+	Images  ImageStore
+	Avatars ImageStore
 }
 
 // getSecureTLSConfig returns a TLS configuration with explicit cipher suites.
@@ -67,6 +71,11 @@ func getSecureTLSConfig(isDevelopment bool) *tls.Config {
 
 // NewClientServer creates and initializes a new ClientServer.
 func NewClientServer(cfg *config.Client) (*ClientServer, error) {
+	err := cfg.Uploads.CheckWritable()
+	if err != nil {
+		return nil, err
+	}
+
 	// Create a cookie jar to persist cookies between requests
 	jar, err := cookiejar.New(nil)
 	if err != nil {
@@ -110,6 +119,8 @@ func NewClientServer(cfg *config.Client) (*ClientServer, error) {
 		HTTPClient:  httpClient,
 		SseClient:   sseClient,
 		BackendURLs: backendURLs,
+		Images:      NewLocalImageStore(cfg.Uploads.Dir, uploadURLPrefix),
+		Avatars:     NewLocalImageStore(filepath.Join(cfg.Uploads.Dir, "avatars"), avatarPathPrefix),
 	}, nil
 }
 
@@ -122,6 +133,11 @@ func (cs *ClientServer) SetupRoutes() {
 		"/static/",
 		http.StripPrefix("/static/", http.FileServer(http.Dir(resolver.GetPath("frontend/static/")))),
 	)
+	// Uploads are served from wherever they are configured to be stored.
+	cs.Router.Handle(
+		uploadURLPrefix,
+		http.StripPrefix(uploadURLPrefix, http.FileServer(http.Dir(cs.Config.Uploads.Dir))),
+	)
 
 	// Create auth middleware
 	authMiddleware := middleware.AuthMiddleware(cs.HTTPClient, cs.BackendURLs.MeURL())
@@ -138,6 +154,7 @@ func (cs *ClientServer) SetupRoutes() {
 
 	// Topic detail page
 	cs.Router.HandleFunc("/topic/", applyMiddleware(cs.TopicPage, authMiddleware))
+	cs.Router.HandleFunc("/user/", applyMiddleware(cs.ProfilePage, authMiddleware))
 
 	// Topic CRUD routes
 	cs.Router.HandleFunc("/topics/create", applyMiddleware(func(w http.ResponseWriter, r *http.Request) {
@@ -157,6 +174,14 @@ func (cs *ClientServer) SetupRoutes() {
 	cs.Router.HandleFunc("/comments/create", applyMiddleware(cs.CreateCommentPost, middleware.RequireAuth, authMiddleware))
 	cs.Router.HandleFunc("/comments/edit", applyMiddleware(cs.UpdateCommentPost, middleware.RequireAuth, authMiddleware))
 	cs.Router.HandleFunc("/comments/delete", applyMiddleware(cs.DeleteCommentPost, middleware.RequireAuth, authMiddleware))
+	cs.Router.HandleFunc("/comments/accept", applyMiddleware(cs.AcceptAnswerPost, middleware.RequireAuth, authMiddleware))
+	cs.Router.HandleFunc("/comments/reports", applyMiddleware(cs.ResolveCommentReportsPost, middleware.RequireAuth, authMiddleware))
+
+	// Admin routes
+	cs.Router.HandleFunc("/admin/report-reasons", applyMiddleware(cs.ReportReasonsPage, middleware.RequireAuth, authMiddleware))
+	cs.Router.HandleFunc("/admin/report-reasons/create", applyMiddleware(cs.CreateReportReasonPost, middleware.RequireAuth, authMiddleware))
+	cs.Router.HandleFunc("/admin/report-reasons/update", applyMiddleware(cs.UpdateReportReasonPost, middleware.RequireAuth, authMiddleware))
+	cs.Router.HandleFunc("/admin/audit", applyMiddleware(cs.AuditLogPage, middleware.RequireAuth, authMiddleware))
 
 	// Vote API routes (these are API endpoints, not pages)
 	cs.Router.HandleFunc("/api/vote/cast", applyMiddleware(cs.CastVote, middleware.RequireAuth, authMiddleware))
@@ -189,6 +214,32 @@ func (cs *ClientServer) SetupRoutes() {
 			}
 		}, authMiddleware))
 
+	// Password reset pages
+	cs.Router.HandleFunc("/forgot-password",
+		applyMiddleware(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet:
+				cs.ForgotPasswordPage(w, r)
+			case http.MethodPost:
+				cs.ForgotPasswordPost(w, r)
+			default:
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+		}, authMiddleware))
+	cs.Router.HandleFunc("/reset-password",
+		applyMiddleware(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet:
+				cs.ResetPasswordPage(w, r)
+			case http.MethodPost:
+				cs.ResetPasswordPost(w, r)
+			default:
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+		}, authMiddleware))
+
+	cs.Router.HandleFunc("/verify-email", applyMiddleware(cs.VerifyEmailPage, authMiddleware))
+
 	// OAuth Register
 	cs.Router.HandleFunc("/auth/github/login", applyMiddleware(cs.GitHubRegister, authMiddleware))
 	cs.Router.HandleFunc("/auth/google/login", applyMiddleware(cs.GoogleRegister, authMiddleware))
@@ -197,19 +248,48 @@ func (cs *ClientServer) SetupRoutes() {
 	// Protected Routes (require authentication).
 	// Activity page
 	cs.Router.HandleFunc("/activity", applyMiddleware(cs.ActivityPage, middleware.RequireAuth, authMiddleware))
+	cs.Router.HandleFunc("/my-posts", applyMiddleware(cs.MyPostsPage, middleware.RequireAuth, authMiddleware))
+	// Change password page
+	cs.Router.HandleFunc("/change-password",
+		applyMiddleware(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet:
+				cs.ChangePasswordPage(w, r)
+			case http.MethodPost:
+				cs.ChangePasswordPost(w, r)
+			default:
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+		}, middleware.RequireAuth, authMiddleware))
+	// Change avatar page
+	cs.Router.HandleFunc("/settings/avatar",
+		applyMiddleware(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet:
+				cs.ChangeAvatarPage(w, r)
+			case http.MethodPost:
+				cs.ChangeAvatarPost(w, r)
+			default:
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			}
+		}, middleware.RequireAuth, authMiddleware))
 	// Notification routes
 	cs.Router.HandleFunc("/api/notifications/stream", applyMiddleware(cs.StreamNotifications, middleware.RequireAuth, authMiddleware))
 	cs.Router.HandleFunc("/api/notifications", applyMiddleware(cs.GetNotifications, middleware.RequireAuth, authMiddleware))
 	cs.Router.HandleFunc("/api/notifications/unread-count", applyMiddleware((cs.GetUnreadCount), middleware.RequireAuth, authMiddleware))
 	cs.Router.HandleFunc("/api/notifications/mark-read", applyMiddleware(cs.MarkNotificationAsRead, middleware.RequireAuth, authMiddleware))
 	cs.Router.HandleFunc("/api/notifications/mark-all-read", applyMiddleware(cs.MarkAllNotificationsAsRead, middleware.RequireAuth, authMiddleware))
+	// Refresh route - rotates the session cookies using the refresh token
+	cs.Router.HandleFunc("/refresh", cs.RefreshSession)
+
 	// Logout route - clears cookies
 	cs.Router.HandleFunc("/logout", applyMiddleware(cs.Logout, middleware.RequireAuth, authMiddleware))
 }
 
 // ListenAndServe starts the HTTP server.
 func (cs *ClientServer) ListenAndServe() error {
-	handler := middleware.GetClientIPMiddleware(cs.Router)
+	csrf := middleware.NewCSRF(cs.Config.CSRFSecret, cs.Config.SecureCookies)
+	handler := middleware.GetClientIPMiddleware(csrf.Protect(cs.Router))
 
 	// Get TLS configuration for the server
 	var tlsConfig *tls.Config
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/arnald/forum/cmd/client/config"
+)
+
+func TestSessionCookiesSecure(t *testing.T) {
+	tests := []struct {
+		name         string
+		environment  string
+		cookieSecure string
+		want         bool
+	}{
+		{name: "development", environment: "development", want: false},
+		{name: "production", environment: "production", want: true},
+		{name: "forced on in development", environment: "development", cookieSecure: "true", want: true},
+		{name: "forced off in production", environment: "production", cookieSecure: "false", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CLIENT_ENVIRONMENT", tt.environment)
+			t.Setenv("CLIENT_TLS_CERT_FILE", "")
+			t.Setenv("COOKIE_SECURE", tt.cookieSecure)
+
+			cfg, err := config.LoadClientConfig()
+			if err != nil {
+				t.Fatalf("LoadClientConfig() error = %v", err)
+			}
+			cs := &ClientServer{Config: cfg}
+
+			set := httptest.NewRecorder()
+			cs.setSessionCookiesWithMaxAge(set, "access", "refresh", 60, 120)
+			cleared := httptest.NewRecorder()
+			cs.clearSessionCookies(cleared)
+
+			cookies := append(set.Result().Cookies(), cleared.Result().Cookies()...)
+			if len(cookies) != 4 {
+				t.Fatalf("got %d cookies, want 4", len(cookies))
+			}
+			for _, cookie := range cookies {
+				if cookie.Secure != tt.want {
+					t.Errorf("cookie %s (max-age %d) Secure = %v, want %v", cookie.Name, cookie.MaxAge, cookie.Secure, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -1,17 +1,14 @@
 package server
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"log"
 	"net/http"
-	"os"
-	"path/filepath"
 	"strconv"
-	"strings"
-
-	"github.com/google/uuid"
 
 	"github.com/arnald/forum/cmd/client/domain"
 	"github.com/arnald/forum/cmd/client/helpers"
@@ -20,32 +17,89 @@ import (
 )
 
 const (
-	maxUploadSize = 20 << 20 // 20 MB
-	uploadDir     = "frontend/static/images/uploads"
-	uploadDirPerm = 0o750
+	// uploadURLPrefix is where files in the configured upload directory are
+	// served from.
+	uploadURLPrefix = "/static/images/uploads/"
+	uploadDirPerm   = 0o750
 )
 
 type createTopicRequest struct {
 	Title       string `json:"title"`
 	Content     string `json:"content"`
+	Summary     string `json:"summary"`
 	ImagePath   string `json:"imagePath"`
 	CategoryIDs []int  `json:"categoryIds"`
+	// CanonicalCategoryID is the primary category picked on the form.
+	CanonicalCategoryID int  `json:"canonicalCategoryId"`
+	IsQuestion          bool `json:"isQuestion"`
+	// Draft is set by the form's save draft button.
+	Draft bool `json:"draft"`
+}
+
+// backendDuplicateTopicResponse is the backend's answer to a topic that was
+// just submitted already.
+type backendDuplicateTopicResponse struct {
+	Status  string `json:"status"`
+	TopicID int    `json:"topicId"`
 }
 
 type updateTopicRequest struct {
-	Title       string `json:"title"`
-	Content     string `json:"content"`
-	ImagePath   string `json:"imagePath"`
-	CategoryIDs []int  `json:"categoryIds"`
-	TopicID     int    `json:"topicId"`
+	Title               string `json:"title"`
+	Content             string `json:"content"`
+	Summary             string `json:"summary"`
+	ImagePath           string `json:"imagePath"`
+	CategoryIDs         []int  `json:"categoryIds"`
+	TopicID             int    `json:"topicId"`
+	CanonicalCategoryID int    `json:"canonicalCategoryId"`
+	IsQuestion          bool   `json:"isQuestion"`
 }
 
 type createPostData struct {
+	// Form holds the submitted values when the backend turned them down.
+	Form       *createTopicRequest
+	Errors     topicFormErrors
 	Categories []domain.Category
 }
 
+// topicFormErrors holds the backend's message for each input of the topic
+// create and edit forms.
+type topicFormErrors struct {
+	Categories        string
+	CanonicalCategory string
+	Title             string
+	Content           string
+	Summary           string
+	Image             string
+}
+
+// topicValidationErrors reads the per-field messages of a 422 answer to a
+// topic create or edit. It reports false for any other answer, or for one
+// that names no input of the form.
+func topicValidationErrors(statusCode int, body []byte) (topicFormErrors, bool) {
+	var errResp domain.BackendErrorResponse
+	if statusCode != http.StatusUnprocessableEntity || json.Unmarshal(body, &errResp) != nil {
+		return topicFormErrors{}, false
+	}
+
+	errs := topicFormErrors{
+		Categories:        errResp.Fields["categoryIds"],
+		CanonicalCategory: errResp.Fields["canonicalCategoryId"],
+		Title:             errResp.Fields["title"],
+		Content:           errResp.Fields["content"],
+		Summary:           errResp.Fields["summary"],
+		Image:             errResp.Fields["imagePath"],
+	}
+	return errs, errs != topicFormErrors{}
+}
+
 // CreateTopicPage handles GET requests to /topics/create - shows the form.
 func (cs *ClientServer) CreateTopicPage(w http.ResponseWriter, r *http.Request) {
+	cs.renderCreateTopicForm(w, r, createPostData{})
+}
+
+// renderCreateTopicForm shows the create form with the categories to pick
+// from, filled in with data's values and errors when there are any.
+func (cs *ClientServer) renderCreateTopicForm(w http.ResponseWriter, r *http.Request, data createPostData) {
 	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
 	defer cancel()
 
@@ -97,11 +151,9 @@ func (cs *ClientServer) CreateTopicPage(w http.ResponseWriter, r *http.Request)
 		categoriesData.Categories[i].Color = helpers.NormalizeColor(categoriesData.Categories[i].Color)
 	}
 
-	data := createPostData{
-		Categories: categoriesData.Categories,
-	}
+	data.Categories = categoriesData.Categories
 
-	templates.RenderTemplate(w, "create_post", data)
+	templates.RenderTemplate(w, r, "create_post", data)
 }
 
 // CreateTopicPost handles POST requests to /topics/create.
@@ -111,10 +163,10 @@ func (cs *ClientServer) CreateTopicPost(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err := r.ParseMultipartForm(maxUploadSize)
+	err := r.ParseMultipartForm(cs.Config.Uploads.MaxSize)
 	if err != nil {
 		log.Printf("Error parsing form: %v", err)
-		http.Error(w, "Error parsing form. File may be too large (max 20MB)", http.StatusBadRequest)
+		http.Error(w, "Error parsing form. File may be too large (max "+cs.uploadLimit()+")", http.StatusBadRequest)
 		return
 	}
 
@@ -147,6 +199,13 @@ func (cs *ClientServer) CreateTopicPost(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	canonicalCategoryID, err := parseCanonicalCategory(r.FormValue("canonical_category"))
+	if err != nil {
+		log.Printf("Invalid canonical category ID: %v", err)
+		http.Error(w, "Invalid primary category", http.StatusBadRequest)
+		return
+	}
+
 	// Handle optional image upload
 	imagePath := ""
 	file, header, err := r.FormFile("image_path")
@@ -170,55 +229,39 @@ func (cs *ClientServer) CreateTopicPost(w http.ResponseWriter, r *http.Request)
 			return
 		}
 
-		if header.Size > maxUploadSize {
+		if header.Size > cs.Config.Uploads.MaxSize {
 			log.Printf("File too large: %d bytes", header.Size)
-			http.Error(w, "File too large. Maximum size is 20MB", http.StatusBadRequest)
-			return
-		}
-
-		ext := filepath.Ext(header.Filename)
-		uniqueFilename := uuid.New().String() + ext
-
-		err = os.MkdirAll(uploadDir, uploadDirPerm)
-		if err != nil {
-			log.Printf("Failed to create upload directory: %v", err)
-			http.Error(w, "Failed to save image", http.StatusInternalServerError)
-			return
-		}
-
-		destPath := filepath.Join(uploadDir, uniqueFilename)
-		destPath = filepath.Clean(destPath)
-
-		if !strings.HasPrefix(destPath, filepath.Clean(uploadDir)+string(os.PathSeparator)) {
-			log.Printf("Invalid file path: %s", destPath)
-			http.Error(w, "Invalid file path", http.StatusBadRequest)
+			http.Error(w, "File too large. Maximum size is "+cs.uploadLimit(), http.StatusBadRequest)
 			return
 		}
 
-		var destFile *os.File
-		destFile, err = os.Create(destPath)
+		// The declared type can lie; trust only the decoded bytes.
+		var cleaned bytes.Buffer
+		var ext string
+		ext, err = helpers.CleanImage(&cleaned, file)
 		if err != nil {
-			log.Printf("Failed to create destination file: %v", err)
-			http.Error(w, "Failed to save image", http.StatusInternalServerError)
+			log.Printf("Rejected upload %q: %v", header.Filename, err)
+			http.Error(w, "Invalid file type. Only JPEG, PNG, and GIF are allowed", http.StatusBadRequest)
 			return
 		}
-		defer destFile.Close()
 
-		_, err = io.Copy(destFile, file)
+		imagePath, err = cs.Images.Save(&cleaned, "", ext)
 		if err != nil {
 			log.Printf("Failed to save image: %v", err)
 			http.Error(w, "Failed to save image", http.StatusInternalServerError)
 			return
 		}
-
-		imagePath = "/static/images/uploads/" + uniqueFilename
 	}
 
 	createRequest := &createTopicRequest{
-		CategoryIDs: categoryIDs,
-		Title:       title,
-		Content:     content,
-		ImagePath:   imagePath,
+		CategoryIDs:         categoryIDs,
+		CanonicalCategoryID: canonicalCategoryID,
+		IsQuestion:          r.FormValue("is_question") != "",
+		Draft:               r.FormValue("save_draft") != "",
+		Title:               title,
+		Content:             content,
+		Summary:             r.FormValue("summary"),
+		ImagePath:           imagePath,
 	}
 
 	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
@@ -229,25 +272,59 @@ func (cs *ClientServer) CreateTopicPost(w http.ResponseWriter, r *http.Request)
 		log.Printf("Backend request failed: %v", err)
 		// If image was uploaded, clean it up since topic creation failed
 		if imagePath != "" {
-			cleanupImage(imagePath)
+			cs.cleanupImage(imagePath)
 		}
 		templates.NotFoundHandler(w, r, "Failed to create topic", http.StatusInternalServerError)
 		return
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusConflict {
+		// A double submission: drop this copy's image and show the topic
+		// the first submission created.
+		cs.cleanupImage(imagePath)
+		var duplicate backendDuplicateTopicResponse
+		err = json.NewDecoder(resp.Body).Decode(&duplicate)
+		switch {
+		case err != nil || duplicate.TopicID == 0:
+			http.Redirect(w, r, "/topics", http.StatusSeeOther)
+		case duplicate.Status == "draft":
+			http.Redirect(w, r, "/topics?filter=drafts", http.StatusSeeOther)
+		default:
+			http.Redirect(w, r, "/topic/"+strconv.Itoa(duplicate.TopicID), http.StatusSeeOther)
+		}
+		return
+	}
+
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		body, _ := io.ReadAll(resp.Body)
 		log.Printf("Backend returned error: %s", string(body))
 		// If image was uploaded, clean it up since topic creation failed
 		if imagePath != "" {
-			cleanupImage(imagePath)
+			cs.cleanupImage(imagePath)
+		}
+		if fieldErrs, ok := topicValidationErrors(resp.StatusCode, body); ok {
+			// The image is gone, so the form cannot offer it again.
+			createRequest.ImagePath = ""
+			cs.renderCreateTopicForm(w, r, createPostData{Form: createRequest, Errors: fieldErrs})
+			return
 		}
-		templates.NotFoundHandler(w, r, "Failed to create topic", resp.StatusCode)
+		message := "Failed to create topic"
+		// A plain 400 carries a message meant for the user, such as the
+		// request to verify their email first.
+		var errResp domain.BackendErrorResponse
+		if resp.StatusCode == http.StatusBadRequest && json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+			message = errResp.Error
+		}
+		templates.NotFoundHandler(w, r, message, resp.StatusCode)
 		return
 	}
 
-	// Success! Redirect to topics list
+	// Success! Redirect to topics list, or to the author's drafts
+	if createRequest.Draft {
+		http.Redirect(w, r, "/topics?filter=drafts", http.StatusSeeOther)
+		return
+	}
 	http.Redirect(w, r, "/topics", http.StatusSeeOther)
 }
 
@@ -258,10 +335,10 @@ func (cs *ClientServer) UpdateTopicPost(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err := r.ParseMultipartForm(maxUploadSize)
+	err := r.ParseMultipartForm(cs.Config.Uploads.MaxSize)
 	if err != nil {
 		log.Printf("Error parsing form: %v", err)
-		http.Error(w, "Error parsing form. File may be too large (max 20MB)", http.StatusBadRequest)
+		http.Error(w, "Error parsing form. File may be too large (max "+cs.uploadLimit()+")", http.StatusBadRequest)
 		return
 	}
 
@@ -300,6 +377,13 @@ func (cs *ClientServer) UpdateTopicPost(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	canonicalCategoryID, err := parseCanonicalCategory(r.FormValue("canonical_category"))
+	if err != nil {
+		log.Printf("Invalid canonical category ID: %v", err)
+		http.Error(w, "Invalid primary category", http.StatusBadRequest)
+		return
+	}
+
 	// Use current image path by default
 	imagePath := currentImagePath
 
@@ -323,67 +407,43 @@ func (cs *ClientServer) UpdateTopicPost(w http.ResponseWriter, r *http.Request)
 			return
 		}
 
-		if header.Size > maxUploadSize {
+		if header.Size > cs.Config.Uploads.MaxSize {
 			log.Printf("File too large: %d bytes", header.Size)
-			http.Error(w, "File too large. Maximum size is 20MB", http.StatusBadRequest)
-			return
-		}
-
-		ext := filepath.Ext(header.Filename)
-		uniqueFilename := uuid.New().String() + ext
-
-		err = os.MkdirAll(uploadDir, uploadDirPerm)
-		if err != nil {
-			log.Printf("Failed to create upload directory: %v", err)
-			http.Error(w, "Failed to save image", http.StatusInternalServerError)
-			return
-		}
-
-		destPath := filepath.Join(uploadDir, uniqueFilename)
-		destPath = filepath.Clean(destPath)
-
-		if !strings.HasPrefix(destPath, filepath.Clean(uploadDir)+string(os.PathSeparator)) {
-			log.Printf("Invalid file path: %s", destPath)
-			http.Error(w, "Invalid file path", http.StatusBadRequest)
+			http.Error(w, "File too large. Maximum size is "+cs.uploadLimit(), http.StatusBadRequest)
 			return
 		}
 
-		var destFile *os.File
-		destFile, err = os.Create(destPath)
+		// The declared type can lie; trust only the decoded bytes.
+		var cleaned bytes.Buffer
+		var ext string
+		ext, err = helpers.CleanImage(&cleaned, file)
 		if err != nil {
-			log.Printf("Failed to create destination file: %v", err)
-			http.Error(w, "Failed to save image", http.StatusInternalServerError)
+			log.Printf("Rejected upload %q: %v", header.Filename, err)
+			http.Error(w, "Invalid file type. Only JPEG, PNG, and GIF are allowed", http.StatusBadRequest)
 			return
 		}
-		defer destFile.Close()
 
-		_, err = io.Copy(destFile, file)
+		imagePath, err = cs.Images.Save(&cleaned, "", ext)
 		if err != nil {
 			log.Printf("Failed to save image: %v", err)
 			http.Error(w, "Failed to save image", http.StatusInternalServerError)
 			return
 		}
 
-		imagePath = "/static/images/uploads/" + uniqueFilename
-
-		if currentImagePath != "" && currentImagePath != imagePath &&
-			strings.HasPrefix(currentImagePath, "/static/images/uploads/") {
-			oldFilename := strings.TrimPrefix(currentImagePath, "/static/images/uploads/")
-			oldFilePath := filepath.Join(uploadDir, oldFilename)
-			oldFilePath = filepath.Clean(oldFilePath)
-
-			if strings.HasPrefix(oldFilePath, filepath.Clean(uploadDir)+string(os.PathSeparator)) {
-				_ = os.Remove(oldFilePath)
-			}
+		if currentImagePath != "" && currentImagePath != imagePath {
+			cs.cleanupImage(currentImagePath)
 		}
 	}
 
 	updateRequest := &updateTopicRequest{
-		TopicID:     topicID,
-		CategoryIDs: categoryIDs,
-		Title:       title,
-		Content:     content,
-		ImagePath:   imagePath,
+		TopicID:             topicID,
+		CategoryIDs:         categoryIDs,
+		CanonicalCategoryID: canonicalCategoryID,
+		IsQuestion:          r.FormValue("is_question") != "",
+		Title:               title,
+		Content:             content,
+		Summary:             r.FormValue("summary"),
+		ImagePath:           imagePath,
 	}
 
 	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
@@ -400,6 +460,10 @@ func (cs *ClientServer) UpdateTopicPost(w http.ResponseWriter, r *http.Request)
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		body, _ := io.ReadAll(resp.Body)
 		log.Printf("Backend returned error: %s", string(body))
+		if fieldErrs, ok := topicValidationErrors(resp.StatusCode, body); ok {
+			cs.renderTopicPage(w, r, topicIDStr, &rejectedTopicEdit{Errors: fieldErrs, Values: *updateRequest})
+			return
+		}
 		templates.NotFoundHandler(w, r, "Failed to update topic", resp.StatusCode)
 		return
 	}
@@ -507,30 +571,34 @@ func (cs *ClientServer) DeleteTopicPost(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Delete image file locally
-	if topicResp.ImagePath != "" &&
-		strings.HasPrefix(topicResp.ImagePath, "/static/images/uploads/") {
-		filename := strings.TrimPrefix(topicResp.ImagePath, "/static/images/uploads/")
-		filePath := filepath.Join(uploadDir, filename)
-
-		err = os.Remove(filePath)
-		if err != nil && !os.IsNotExist(err) {
-			log.Printf("Failed to delete image file %s: %v", filePath, err)
-		}
+	if topicResp.ImagePath != "" {
+		cs.cleanupImage(topicResp.ImagePath)
 	}
 
 	http.Redirect(w, r, "/topics", http.StatusSeeOther)
 }
 
-// Helper function to clean up uploaded image if topic creation fails.
-func cleanupImage(imagePath string) {
-	if imagePath != "" && strings.HasPrefix(imagePath, "/static/images/uploads/") {
-		filename := strings.TrimPrefix(imagePath, "/static/images/uploads/")
-		filePath := filepath.Join(uploadDir, filename)
-		filePath = filepath.Clean(filePath)
+// parseCanonicalCategory reads the optional primary category radio; an empty
+// value leaves the choice to the backend, which uses the first category.
+func parseCanonicalCategory(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
 
-		if strings.HasPrefix(filePath, filepath.Clean(uploadDir)+string(os.PathSeparator)) {
-			_ = os.Remove(filePath)
-		}
+// cleanupImage removes an uploaded topic image that is no longer used.
+func (cs *ClientServer) cleanupImage(imagePath string) {
+	if imagePath == "" {
+		return
 	}
+	err := cs.Images.Delete(imagePath)
+	if err != nil {
+		log.Printf("Failed to delete image %s: %v", imagePath, err)
+	}
+}
+
+// uploadLimit describes the configured upload size limit for error messages.
+func (cs *ClientServer) uploadLimit() string {
+	return strconv.FormatInt(cs.Config.Uploads.MaxSize>>20, 10) + "MB"
 }
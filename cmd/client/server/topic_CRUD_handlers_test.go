@@ -0,0 +1,344 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"html"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/arnald/forum/cmd/client/config"
+	"github.com/arnald/forum/cmd/client/middleware"
+	"github.com/arnald/forum/internal/pkg/path"
+)
+
+// memoryImageStore is an ImageStore that keeps images in a map.
+type memoryImageStore struct {
+	images map[string][]byte
+	saved  int
+}
+
+func newMemoryImageStore() *memoryImageStore {
+	return &memoryImageStore{images: make(map[string][]byte)}
+}
+
+func (s *memoryImageStore) Save(r io.Reader, _, ext string) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	s.saved++
+	path := uploadURLPrefix + "image-" + strconv.Itoa(s.saved) + ext
+	s.images[path] = data
+	return path, nil
+}
+
+func (s *memoryImageStore) Delete(path string) error {
+	delete(s.images, path)
+	return nil
+}
+
+func TestCreateTopicPost_Images(t *testing.T) {
+	const limit = 1 << 20
+
+	testCases := []struct {
+		name        string
+		image       []byte
+		backendCode int
+		wantCode    int
+		wantCalls   int
+		wantStored  int
+	}{
+		{
+			name:        "image within the limit is stored",
+			image:       pngBytes(t),
+			backendCode: http.StatusCreated,
+			wantCode:    http.StatusSeeOther,
+			wantCalls:   1,
+			wantStored:  1,
+		},
+		{
+			name:     "oversized image is rejected",
+			image:    bytes.Repeat([]byte{0}, limit+1),
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:        "image is removed when the backend refuses the topic",
+			image:       pngBytes(t),
+			backendCode: http.StatusInternalServerError,
+			wantCode:    http.StatusInternalServerError,
+			wantCalls:   1,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent createTopicRequest
+			calls := 0
+			backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				_ = json.NewDecoder(r.Body).Decode(&sent)
+				w.WriteHeader(tt.backendCode)
+			}))
+			t.Cleanup(backend.Close)
+
+			images := newMemoryImageStore()
+			cs := &ClientServer{
+				Config:      &config.Client{Uploads: config.Uploads{MaxSize: limit}},
+				HTTPClient:  backend.Client(),
+				BackendURLs: NewBackendURLs(backend.URL),
+				Images:      images,
+			}
+
+			rec := postTopicWithImage(t, cs, tt.image)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("CreateTopicPost() status = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("backend calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if tt.wantCode == http.StatusBadRequest && !strings.Contains(rec.Body.String(), "Maximum size is 1MB") {
+				t.Errorf("response does not state the configured limit: %s", rec.Body.String())
+			}
+			if len(images.images) != tt.wantStored {
+				t.Fatalf("store holds %d images, want %d", len(images.images), tt.wantStored)
+			}
+			if tt.wantStored > 0 {
+				if _, ok := images.images[sent.ImagePath]; !ok {
+					t.Errorf("imagePath sent = %q, want the stored image", sent.ImagePath)
+				}
+			}
+		})
+	}
+}
+
+func TestTopicForms_BackendFieldErrors(t *testing.T) {
+	// The topic page loads its layout relative to the project root.
+	t.Chdir(path.NewResolver().GetPath(""))
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, pathCategoriesAll):
+			_, _ = io.WriteString(w, `{"data":{"categories":[{"id":1,"name":"General"}]}}`)
+		case strings.HasSuffix(r.URL.Path, pathTopic):
+			_, _ = io.WriteString(w, `{"data":{"topicId":7,"title":"Stored title","content":"Stored content","categoryIds":[1],"categoryNames":["General"],"categoryColors":["#336699"]}}`)
+		default:
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = io.WriteString(w, `{"error":"Validation failed","fields":{"summary":"must be at most 10 characters"}}`)
+		}
+	}))
+	t.Cleanup(backend.Close)
+
+	cs := &ClientServer{
+		Config:      &config.Client{Uploads: config.Uploads{MaxSize: 1 << 20}},
+		HTTPClient:  backend.Client(),
+		BackendURLs: NewBackendURLs(backend.URL),
+		Images:      newMemoryImageStore(),
+	}
+
+	testCases := []struct {
+		handler http.HandlerFunc
+		name    string
+		target  string
+	}{
+		{name: "create form", handler: cs.CreateTopicPost, target: "/topics/create"},
+		{name: "edit form", handler: cs.UpdateTopicPost, target: "/topics/edit"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := postTopicForm(t, tt.handler, tt.target, url.Values{
+				"topic_id":   {"7"},
+				"categories": {"1"},
+				"title":      {"Submitted title"},
+				"content":    {"Submitted content"},
+				"summary":    {"A summary that is far too long"},
+			})
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want the form shown again: %s", rec.Code, rec.Body.String())
+			}
+			body := rec.Body.String()
+			for _, want := range []string{"must be at most 10 characters", "Submitted title", "A summary that is far too long"} {
+				if !strings.Contains(body, want) {
+					t.Errorf("form does not show %q", want)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateTopicPost_ShoutingTitle(t *testing.T) {
+	const message = "please don't write the title in all caps"
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, pathCategoriesAll) {
+			_, _ = io.WriteString(w, `{"data":{"categories":[{"id":1,"name":"General"}]}}`)
+			return
+		}
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"error":  "Validation failed",
+			"fields": map[string]string{"title": message},
+		})
+	}))
+	t.Cleanup(backend.Close)
+
+	cs := &ClientServer{
+		Config:      &config.Client{Uploads: config.Uploads{MaxSize: 1 << 20}},
+		HTTPClient:  backend.Client(),
+		BackendURLs: NewBackendURLs(backend.URL),
+		Images:      newMemoryImageStore(),
+	}
+
+	rec := postTopicForm(t, cs.CreateTopicPost, "/topics/create", url.Values{
+		"categories": {"1"},
+		"title":      {"BUY THIS NOW"},
+		"content":    {"Long enough content"},
+	})
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("CreateTopicPost() status = %d, want the form shown again", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), html.EscapeString(message)) {
+		t.Errorf("CreateTopicPost() page does not show %q", message)
+	}
+}
+
+func TestLocalImageStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "uploads")
+	store := NewLocalImageStore(dir, uploadURLPrefix)
+
+	path, err := store.Save(strings.NewReader("image"), "", ".png")
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	filename, found := strings.CutPrefix(path, uploadURLPrefix)
+	if !found || !strings.HasSuffix(filename, ".png") {
+		t.Fatalf("Save() = %q, want a png under %s", path, uploadURLPrefix)
+	}
+
+	owned, err := store.Save(strings.NewReader("avatar"), "alice", ".png")
+	if err != nil {
+		t.Fatalf("Save() with an owner error = %v", err)
+	}
+	if !strings.HasPrefix(owned, uploadURLPrefix+"alice"+avatarOwnerSeparator) {
+		t.Errorf("Save() with an owner = %q, want a name starting with the owner", owned)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, filename))
+	if err != nil || string(data) != "image" {
+		t.Fatalf("stored file = %q, %v; want the image bytes", data, err)
+	}
+
+	// Paths the store does not own are left alone.
+	outside := filepath.Join(t.TempDir(), "keep.png")
+	err = os.WriteFile(outside, []byte("keep"), 0o600)
+	if err != nil {
+		t.Fatalf("failed to seed file: %v", err)
+	}
+	for _, foreign := range []string{"https://example.com/avatar.png", uploadURLPrefix + "../../" + outside} {
+		err = store.Delete(foreign)
+		if err != nil {
+			t.Errorf("Delete(%q) error = %v", foreign, err)
+		}
+	}
+	if _, err = os.Stat(outside); err != nil {
+		t.Errorf("Delete() removed a file outside the store: %v", err)
+	}
+
+	err = store.Delete(path)
+	if err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err = os.Stat(filepath.Join(dir, filename)); !os.IsNotExist(err) {
+		t.Errorf("image still on disk after Delete(), stat error = %v", err)
+	}
+	err = store.Delete(path)
+	if err != nil {
+		t.Errorf("Delete() of a missing image error = %v, want nil", err)
+	}
+}
+
+func TestUploadsCheckWritable(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "uploads")
+
+	err := config.Uploads{Dir: dir}.CheckWritable()
+	if err != nil {
+		t.Fatalf("CheckWritable() error = %v", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("CheckWritable() did not create the directory: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("CheckWritable() left %d files behind", len(entries))
+	}
+
+	// A path under a regular file can never be created.
+	blocker := filepath.Join(t.TempDir(), "file")
+	err = os.WriteFile(blocker, nil, 0o600)
+	if err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	err = config.Uploads{Dir: filepath.Join(blocker, "uploads")}.CheckWritable()
+	if err == nil {
+		t.Error("CheckWritable() under a file succeeded, want an error")
+	}
+}
+
+func postTopicWithImage(t *testing.T, cs *ClientServer, image []byte) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	_ = form.WriteField("title", "Topic with an image")
+	_ = form.WriteField("content", "content")
+	_ = form.WriteField("categories", "1")
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="image_path"; filename="image.png"`)
+	header.Set("Content-Type", "image/png")
+	part, err := form.CreatePart(header)
+	if err != nil {
+		t.Fatalf("failed to build form: %v", err)
+	}
+	_, _ = part.Write(image)
+	_ = form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/topics/create", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	rec := httptest.NewRecorder()
+
+	middleware.GetClientIPMiddleware(http.HandlerFunc(cs.CreateTopicPost)).ServeHTTP(rec, req)
+	return rec
+}
+
+func postTopicForm(t *testing.T, handler http.HandlerFunc, target string, fields url.Values) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for name, values := range fields {
+		for _, value := range values {
+			_ = form.WriteField(name, value)
+		}
+	}
+	_ = form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, target, &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	rec := httptest.NewRecorder()
+
+	middleware.GetClientIPMiddleware(handler).ServeHTTP(rec, req)
+	return rec
+}
@@ -14,35 +14,69 @@ import (
 	"github.com/arnald/forum/cmd/client/middleware"
 )
 
-const minURLPathLength = 2
+const (
+	minURLPathLength = 2
+	// commentsPerPage is how many comments the topic page shows at a time.
+	commentsPerPage = 50
+	// lastCommentsPage is the ?comments value for the page holding the
+	// newest comments, where a freshly posted comment ends up.
+	lastCommentsPage = "last"
+)
 
 type topicPageResponse struct {
-	UserVote       *int             `json:"userVote"`
-	ImagePath      string           `json:"imagePath"`
-	OwnerUsername  string           `json:"ownerUsername"`
-	Content        string           `json:"content"`
-	UserID         string           `json:"userId"`
-	CreatedAt      string           `json:"createdAt"`
-	Title          string           `json:"title"`
-	UpdatedAt      string           `json:"updatedAt"`
-	CategoryColors []string         `json:"categoryColors"`
-	CategoryNames  []string         `json:"categoryNames"`
-	Comments       []domain.Comment `json:"comments"`
-	CategoryIDs    []int            `json:"categoryIds"`
-	Upvotes        int              `json:"upvotes"`
-	Downvotes      int              `json:"downvotes"`
-	Score          int              `json:"score"`
-	TopicID        int              `json:"topicId"`
+	UserVote            *int             `json:"userVote"`
+	ImagePath           string           `json:"imagePath"`
+	OwnerUsername       string           `json:"ownerUsername"`
+	Content             string           `json:"content"`
+	Summary             string           `json:"summary"`
+	UserID              string           `json:"userId"`
+	CreatedAt           string           `json:"createdAt"`
+	Title               string           `json:"title"`
+	UpdatedAt           string           `json:"updatedAt"`
+	CategoryColors      []string         `json:"categoryColors"`
+	CategoryNames       []string         `json:"categoryNames"`
+	Comments            []domain.Comment `json:"comments"`
+	CategoryIDs         []int            `json:"categoryIds"`
+	Upvotes             int              `json:"upvotes"`
+	Downvotes           int              `json:"downvotes"`
+	Score               int              `json:"score"`
+	ViewCount           int              `json:"viewCount"`
+	TopicID             int              `json:"topicId"`
+	CanonicalCategoryID int              `json:"canonicalCategoryId"`
+	AcceptedCommentID   int              `json:"acceptedCommentId"`
+	CommentCount        int              `json:"commentCount"`
+	Removed             bool             `json:"removed"`
+	IsQuestion          bool             `json:"isQuestion"`
+	IsEdited            bool             `json:"isEdited"`
 }
 
 type topicPageRequest struct {
-	TopicID string `url:"id"`
+	TopicID        string `url:"id"`
+	CommentsLimit  int    `url:"comments_limit"`
+	CommentsOffset int    `url:"comments_offset"`
 }
 
 type topicPageData struct {
-	User       *domain.LoggedInUser `json:"user"`
-	Categories []domain.Category    `json:"categories"`
-	Topic      domain.Topic         `json:"topic"`
+	User *domain.LoggedInUser `json:"user"`
+	// EditErrors is set when the backend turned down an edit; the edit form
+	// then opens again with EditForm holding what was sent.
+	EditErrors *topicFormErrors   `json:"-"`
+	Categories []domain.Category  `json:"categories"`
+	EditForm   updateTopicRequest `json:"-"`
+	Topic      domain.Topic       `json:"topic"`
+	// ScoreMinVotes lets the vote script apply the same threshold as "score".
+	ScoreMinVotes int `json:"-"`
+	// CommentsPrevPage and CommentsNextPage are the ?comments values of the
+	// neighbouring comment pages, or 0 when there is none.
+	CommentsPrevPage int `json:"-"`
+	CommentsNextPage int `json:"-"`
+}
+
+// rejectedTopicEdit is an edit the backend refused, shown again with the
+// reasons next to each input.
+type rejectedTopicEdit struct {
+	Errors topicFormErrors
+	Values updateTopicRequest
 }
 
 // TopicPage handles GET requests to /topic/{id}.
@@ -65,63 +99,50 @@ func (cs *ClientServer) TopicPage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	cs.renderTopicPage(w, r, topicIDStr, nil)
+}
+
+// renderTopicPage shows the topic with one page of comments. A rejected edit
+// reopens the edit form with the submitted values and the backend's errors.
+func (cs *ClientServer) renderTopicPage(w http.ResponseWriter, r *http.Request, topicIDStr string, rejected *rejectedTopicEdit) {
 	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
 	defer cancel()
 
-	topicReq := &topicPageRequest{
-		TopicID: topicIDStr,
-	}
-
-	topicURL, err := createURLWithParams(cs.BackendURLs.TopicURL(), topicReq)
-	if err != nil {
-		log.Printf("Error creating topic URL: %v", err)
-		http.Error(w, "Error creating URL params", http.StatusInternalServerError)
-		return
-	}
-
-	topicHTTPReq, err := http.NewRequestWithContext(ctx, http.MethodGet, topicURL, nil)
-	if err != nil {
-		log.Printf("Error creating topic request: %v", err)
-		http.Error(w, "Error creating request", http.StatusInternalServerError)
-		return
-	}
-
-	ip := middleware.GetIPFromContext(r)
-	if ip == "" {
-		http.Error(w, "Error no IP found in request", http.StatusInternalServerError)
+	commentsParam := r.URL.Query().Get("comments")
+	commentsPage, err := strconv.Atoi(commentsParam)
+	if err != nil || commentsPage < 1 {
+		commentsPage = 1
 	}
 
-	helpers.SetIPHeaders(topicHTTPReq, ip)
-
-	for _, cookie := range r.Cookies() {
-		topicHTTPReq.AddCookie(cookie)
+	topicReq := &topicPageRequest{
+		TopicID:        topicIDStr,
+		CommentsLimit:  commentsPerPage,
+		CommentsOffset: (commentsPage - 1) * commentsPerPage,
 	}
 
-	topicResp, err := cs.HTTPClient.Do(topicHTTPReq)
-	if err != nil {
-		log.Printf("Error fetching topic: %v", err)
-		http.Error(w, "Error with the response", http.StatusInternalServerError)
+	topicData, ok := cs.fetchTopicPage(ctx, w, r, topicReq)
+	if !ok {
 		return
 	}
-	defer topicResp.Body.Close()
 
-	if topicResp.StatusCode == http.StatusNotFound {
-		templates.NotFoundHandler(w, r, "Topic not found", http.StatusNotFound)
-		return
+	// The last page is only known once the first response gives the count.
+	if commentsParam == lastCommentsPage {
+		commentsPage = max(1, (topicData.CommentCount+commentsPerPage-1)/commentsPerPage)
+		if commentsPage > 1 {
+			topicReq.CommentsOffset = (commentsPage - 1) * commentsPerPage
+			topicData, ok = cs.fetchTopicPage(ctx, w, r, topicReq)
+			if !ok {
+				return
+			}
+		}
 	}
 
-	if topicResp.StatusCode != http.StatusOK {
-		log.Printf("Backend returned status: %d", topicResp.StatusCode)
-		templates.NotFoundHandler(w, r, "Error loading topic", http.StatusInternalServerError)
-		return
+	var commentsPrevPage, commentsNextPage int
+	if commentsPage > 1 {
+		commentsPrevPage = commentsPage - 1
 	}
-
-	var topicData topicPageResponse
-	err = helpers.DecodeBackendResponse(topicResp, &topicData)
-	if err != nil {
-		log.Printf("Error decoding topic response: %v", err)
-		http.Error(w, "Error with decoding response into data struct", http.StatusInternalServerError)
-		return
+	if commentsPage*commentsPerPage < topicData.CommentCount {
+		commentsNextPage = commentsPage + 1
 	}
 
 	// Fetch categories for the edit form
@@ -132,7 +153,7 @@ func (cs *ClientServer) TopicPage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ip = middleware.GetIPFromContext(r)
+	ip := middleware.GetIPFromContext(r)
 	if ip == "" {
 		http.Error(w, "Error no IP found in request", http.StatusInternalServerError)
 	}
@@ -169,33 +190,67 @@ func (cs *ClientServer) TopicPage(w http.ResponseWriter, r *http.Request) {
 	}
 
 	topic := domain.Topic{
-		ID:             topicData.TopicID,
-		CategoryIDs:    topicData.CategoryIDs,
-		Title:          topicData.Title,
-		Content:        topicData.Content,
-		ImagePath:      topicData.ImagePath,
-		UserID:         topicData.UserID,
-		CreatedAt:      topicData.CreatedAt,
-		UpdatedAt:      topicData.UpdatedAt,
-		UpvoteCount:    topicData.Upvotes,
-		DownvoteCount:  topicData.Downvotes,
-		VoteScore:      topicData.Score,
-		UserVote:       topicData.UserVote,
-		OwnerUsername:  topicData.OwnerUsername,
-		Comments:       topicData.Comments,
-		CategoryNames:  topicData.CategoryNames,
-		CategoryColors: normalizedColors,
+		ID:                  topicData.TopicID,
+		CategoryIDs:         topicData.CategoryIDs,
+		CanonicalCategoryID: topicData.CanonicalCategoryID,
+		Title:               topicData.Title,
+		Content:             topicData.Content,
+		Summary:             topicData.Summary,
+		ImagePath:           topicData.ImagePath,
+		UserID:              topicData.UserID,
+		CreatedAt:           topicData.CreatedAt,
+		UpdatedAt:           topicData.UpdatedAt,
+		UpvoteCount:         topicData.Upvotes,
+		DownvoteCount:       topicData.Downvotes,
+		VoteScore:           topicData.Score,
+		ViewCount:           topicData.ViewCount,
+		UserVote:            topicData.UserVote,
+		OwnerUsername:       topicData.OwnerUsername,
+		Comments:            pinAcceptedAnswer(topicData.Comments, topicData.AcceptedCommentID),
+		CategoryNames:       topicData.CategoryNames,
+		CategoryColors:      normalizedColors,
+		Removed:             topicData.Removed,
+		IsQuestion:          topicData.IsQuestion,
+		IsEdited:            topicData.IsEdited,
+		AcceptedCommentID:   topicData.AcceptedCommentID,
+		CommentCount:        topicData.CommentCount,
 	}
 
 	pageData := topicPageData{
 		User:       middleware.GetUserFromContext(r.Context()),
 		Topic:      topic,
 		Categories: categoriesData.Categories,
+		EditForm: updateTopicRequest{
+			TopicID:             topic.ID,
+			CategoryIDs:         topic.CategoryIDs,
+			CanonicalCategoryID: topic.CanonicalCategoryID,
+			IsQuestion:          topic.IsQuestion,
+			Title:               topic.Title,
+			Content:             topic.Content,
+			Summary:             topic.Summary,
+			ImagePath:           topic.ImagePath,
+		},
+
+		ScoreMinVotes:    cs.Config.ScoreMinVotes,
+		CommentsPrevPage: commentsPrevPage,
+		CommentsNextPage: commentsNextPage,
+	}
+
+	if rejected != nil {
+		pageData.EditForm = rejected.Values
+		pageData.EditErrors = &rejected.Errors
 	}
 
 	tmpl, err := template.New("base").
+		Funcs(templates.FuncMap(r)).
 		Funcs(template.FuncMap{
 			"hasID": hasID,
+			"score": func(score, upvotes, downvotes int) string {
+				return helpers.DisplayScore(score, upvotes, downvotes, cs.Config.ScoreMinVotes, pageData.User.IsStaff())
+			},
+			"render": func(content string) string {
+				return helpers.RenderContent(content, cs.Config.SpoilerOpen, cs.Config.SpoilerClose)
+			},
 		}).
 		ParseFiles(
 			"frontend/html/layouts/base.html",
@@ -216,6 +271,86 @@ func (cs *ClientServer) TopicPage(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// fetchTopicPage asks the backend for the topic with one page of its
+// comments. When it fails it has already written the error page and reports
+// false.
+func (cs *ClientServer) fetchTopicPage(ctx context.Context, w http.ResponseWriter, r *http.Request, topicReq *topicPageRequest) (*topicPageResponse, bool) {
+	topicURL, err := createURLWithParams(cs.BackendURLs.TopicURL(), topicReq)
+	if err != nil {
+		log.Printf("Error creating topic URL: %v", err)
+		http.Error(w, "Error creating URL params", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	topicHTTPReq, err := http.NewRequestWithContext(ctx, http.MethodGet, topicURL, nil)
+	if err != nil {
+		log.Printf("Error creating topic request: %v", err)
+		http.Error(w, "Error creating request", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	ip := middleware.GetIPFromContext(r)
+	if ip == "" {
+		http.Error(w, "Error no IP found in request", http.StatusInternalServerError)
+	}
+
+	helpers.SetIPHeaders(topicHTTPReq, ip)
+
+	for _, cookie := range r.Cookies() {
+		topicHTTPReq.AddCookie(cookie)
+	}
+
+	topicResp, err := cs.HTTPClient.Do(topicHTTPReq)
+	if err != nil {
+		log.Printf("Error fetching topic: %v", err)
+		http.Error(w, "Error with the response", http.StatusInternalServerError)
+		return nil, false
+	}
+	defer topicResp.Body.Close()
+
+	if topicResp.StatusCode == http.StatusNotFound {
+		templates.NotFoundHandler(w, r, "Topic not found", http.StatusNotFound)
+		return nil, false
+	}
+
+	if topicResp.StatusCode != http.StatusOK {
+		log.Printf("Backend returned status: %d", topicResp.StatusCode)
+		templates.NotFoundHandler(w, r, "Error loading topic", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	var topicData topicPageResponse
+	err = helpers.DecodeBackendResponse(topicResp, &topicData)
+	if err != nil {
+		log.Printf("Error decoding topic response: %v", err)
+		http.Error(w, "Error with decoding response into data struct", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	return &topicData, true
+}
+
+// pinAcceptedAnswer moves the accepted answer, if any, to the top of the
+// comments while keeping the rest in their original order.
+func pinAcceptedAnswer(comments []domain.Comment, acceptedID int) []domain.Comment {
+	if acceptedID == 0 {
+		return comments
+	}
+
+	pinned := make([]domain.Comment, 0, len(comments))
+	for _, c := range comments {
+		if c.ID == acceptedID {
+			pinned = append(pinned, c)
+		}
+	}
+	for _, c := range comments {
+		if c.ID != acceptedID {
+			pinned = append(pinned, c)
+		}
+	}
+	return pinned
+}
+
 func hasID(ids []int, id int) bool {
 	for _, v := range ids {
 		if v == id {
@@ -18,9 +18,12 @@ type topicsRequest struct {
 	OrderBy  string `url:"order_by"`
 	Order    string `url:"order"`
 	Search   string `url:"search"`
+	Sort     string `url:"sort"`
+	Filter   string `url:"filter"`
 	Category int    `url:"category"`
 	Page     int    `url:"page"`
 	PageSize int    `url:"page_size"`
+	Before   int    `url:"before"`
 }
 
 type topicsResponse struct {
@@ -29,6 +32,7 @@ type topicsResponse struct {
 	Topics     []domain.Topic         `json:"topics"`
 	Categories []domain.Category      `json:"categories"`
 	Pagination domain.Pagination      `json:"pagination"`
+	Category   int                    `json:"-"`
 }
 
 // TopicsPage handles GET requests to /topics.
@@ -42,16 +46,22 @@ func (cs *ClientServer) TopicsPage(w http.ResponseWriter, r *http.Request) {
 	search := getQueryStringOr(r, "search", "")
 	orderBy := getQueryStringOr(r, "order_by", "created_at")
 	order := getQueryStringOr(r, "order", "desc")
+	sort := getQueryStringOr(r, "sort", "")
+	filter := getQueryStringOr(r, "filter", "")
 	category := getQueryIntOr(r, "category", 0)
 	pageSize := getQueryIntOr(r, "page_size", defaultPageSize)
+	before := getQueryIntOr(r, "before", 0)
 
 	topicsReq := &topicsRequest{
 		OrderBy:  orderBy,
 		Order:    order,
 		Search:   search,
+		Sort:     sort,
+		Filter:   filter,
 		Category: category,
 		Page:     page,
 		PageSize: pageSize,
+		Before:   before,
 	}
 
 	backendURL, err := createURLWithParams(cs.BackendURLs.TopicsAllURL(), topicsReq)
@@ -106,9 +116,10 @@ func (cs *ClientServer) TopicsPage(w http.ResponseWriter, r *http.Request) {
 		// }
 	}
 	pageData.User = middleware.GetUserFromContext(r.Context())
+	pageData.Category = category
 
 	// Create template with custom functions
-	tmpl := template.New("base").Funcs(template.FuncMap{
+	tmpl := template.New("base").Funcs(templates.FuncMap(r)).Funcs(template.FuncMap{
 		"truncate": func(s string, length int) string {
 			if len(s) <= length {
 				return s
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/arnald/forum/cmd/client/helpers/templates"
+	"github.com/arnald/forum/cmd/client/middleware"
+)
+
+type verifyEmailPageData struct {
+	Message string
+	Error   string
+}
+
+type backendVerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+// VerifyEmailPage handles GET requests to /verify-email, the target of the
+// link issued at registration.
+func (cs *ClientServer) VerifyEmailPage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		templates.RenderTemplate(w, r, "verify_email", verifyEmailPageData{Error: "This verification link is incomplete."})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+	defer cancel()
+
+	resp, err := cs.newRequest(ctx, http.MethodPost, cs.BackendURLs.VerifyEmailURL(),
+		backendVerifyEmailRequest{Token: token}, middleware.GetIPFromContext(r))
+	if err != nil {
+		templates.RenderTemplate(w, r, "verify_email", verifyEmailPageData{Error: err.Error()})
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		templates.RenderTemplate(w, r, "verify_email", verifyEmailPageData{Error: backendFormError(resp, "token")})
+		return
+	}
+
+	templates.RenderTemplate(w, r, "verify_email", verifyEmailPageData{
+		Message: "Your email address is verified. You can now start topics.",
+	})
+}
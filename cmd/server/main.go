@@ -23,7 +23,6 @@ func main() {
 	if err != nil {
 		log.Fatalf("Database error: %v", err)
 	}
-	defer db.Close()
 
 	// 3. Create repository with injected DB
 	logger := logger.New(os.Stdout, logger.LevelInfo)
@@ -36,7 +35,13 @@ func main() {
 		infraProviders.Repositories.VoteRepo,
 		infraProviders.Repositories.OauthRepo,
 		infraProviders.Repositories.ActivityRepo,
+		infraProviders.Repositories.ReportRepo,
+		infraProviders.Repositories.ImportRepo,
+		infraProviders.Repositories.AuditRepo,
+		infraProviders.Repositories.RoleRequestRepo,
+		cfg.Accounts.StripEmailAliases,
 	)
 	infraHTTPServer := infra.NewHTTPServer(cfg, db, logger, appServices)
+	// ListenAndServe closes db once the server has shut down.
 	infraHTTPServer.ListenAndServe()
 }
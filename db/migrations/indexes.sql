@@ -5,4 +5,19 @@ CREATE INDEX IF NOT EXISTS idx_users_created ON users(created_at);
 
 -- Sessions indexes
 CREATE INDEX IF NOT EXISTS idx_sessions_user ON sessions(user_id);
-CREATE INDEX IF NOT EXISTS idx_sessions_expiry ON sessions(expires_at);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_sessions_expiry ON sessions(expires_at);
+-- Comments indexes
+CREATE INDEX IF NOT EXISTS idx_comments_parent ON comments(parent_id);
+-- Serves the moderation queue in order without sorting; it supersedes the
+-- plain status index.
+DROP INDEX IF EXISTS idx_comments_status;
+CREATE INDEX IF NOT EXISTS idx_comments_status_created ON comments(status, created_at, id);
+-- Approved comment counts per topic in listings
+CREATE INDEX IF NOT EXISTS idx_comments_topic_status ON comments(topic_id, status);
+
+-- Thread watches indexes
+CREATE INDEX IF NOT EXISTS idx_thread_watches_topic ON thread_watches(topic_id);
+
+-- Topics indexes
+CREATE INDEX IF NOT EXISTS idx_topics_slug ON topics(slug);
+CREATE INDEX IF NOT EXISTS idx_topics_drafts ON topics(user_id) WHERE status = 'draft';
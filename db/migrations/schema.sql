@@ -6,7 +6,18 @@ CREATE TABLE IF NOT EXISTS users (
     password_hash TEXT,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-    avatar_url TEXT
+    avatar_url TEXT,
+    role TEXT NOT NULL DEFAULT 'user',
+    email_verified BOOLEAN NOT NULL DEFAULT 0
+);
+
+-- Names a user went by before renaming themselves, so old @mentions and
+-- moderation records can still be traced to the account.
+CREATE TABLE IF NOT EXISTS username_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    old_username TEXT NOT NULL,
+    changed_at DATETIME DEFAULT CURRENT_TIMESTAMP
 );
 
 -- OAuth
@@ -32,6 +43,36 @@ CREATE TABLE IF NOT EXISTS sessions (
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     refresh_token TEXT,
     refresh_token_expires_at DATETIME NOT NULL,
+    authenticated_at DATETIME,
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Only a hash of each reset or verification token is kept; used_at marks it
+-- spent.
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    token_hash TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS email_verification_tokens (
+    token_hash TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Consecutive failed logins per account; reaching the limit sets locked_until
+-- and starts the count again.
+CREATE TABLE IF NOT EXISTS failed_logins (
+    user_id TEXT PRIMARY KEY,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    locked_until DATETIME,
     FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
 );
 
@@ -43,10 +84,21 @@ CREATE TABLE IF NOT EXISTS categories (
     image_path TEXT DEFAULT 'static/images/categories/default_category.png',
     color TEXT DEFAULT '#CCCCCC',
     slug TEXT DEFAULT 'default-slug',
+    requires_image BOOLEAN NOT NULL DEFAULT 0,
+    archived BOOLEAN NOT NULL DEFAULT 0,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     created_by TEXT NOT NULL REFERENCES users(id)
 );
 
+-- Moderators assigned to a category may settle pending comments on topics
+-- filed under it; admins moderate every category.
+CREATE TABLE IF NOT EXISTS category_moderators (
+    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (category_id, user_id)
+);
+
 -- Topics
 CREATE TABLE IF NOT EXISTS topics (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -55,7 +107,21 @@ CREATE TABLE IF NOT EXISTS topics (
     content TEXT NOT NULL,
     image_path TEXT DEFAULT '',
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    bumped_at DATETIME,
+    canonical_category_id INTEGER REFERENCES categories(id) ON DELETE SET NULL,
+    is_question BOOLEAN NOT NULL DEFAULT 0,
+    accepted_comment_id INTEGER REFERENCES comments(id) ON DELETE SET NULL,
+    summary TEXT NOT NULL DEFAULT '',
+    deleted_at DATETIME,
+    view_count INTEGER NOT NULL DEFAULT 0,
+    -- Kept in step with the topic's rows in votes by the votes repository.
+    upvote_count INTEGER NOT NULL DEFAULT 0,
+    downvote_count INTEGER NOT NULL DEFAULT 0,
+    -- URL segment derived from the title; the id alone still finds the topic.
+    slug TEXT NOT NULL DEFAULT '',
+    -- 'draft' until the author publishes it; drafts are hidden from others.
+    status TEXT NOT NULL DEFAULT 'published'
 );
 
 -- Topic/Category junction
@@ -73,9 +139,82 @@ CREATE TABLE IF NOT EXISTS comments (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
     topic_id INTEGER NOT NULL REFERENCES topics(id) ON DELETE CASCADE,
+    parent_id INTEGER REFERENCES comments(id) ON DELETE CASCADE,
     content TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'approved',
+    moderated_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    report_count INTEGER NOT NULL DEFAULT 0,
+    collapsed BOOLEAN NOT NULL DEFAULT 0,
+    rejection_reason TEXT NOT NULL DEFAULT ''
+);
+
+-- Thread watches
+CREATE TABLE IF NOT EXISTS thread_watches (
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    topic_id INTEGER NOT NULL REFERENCES topics(id) ON DELETE CASCADE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    last_notified_at DATETIME,
+    PRIMARY KEY (user_id, topic_id)
+);
+
+-- Topics users saved for later
+CREATE TABLE IF NOT EXISTS bookmarks (
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    topic_id INTEGER NOT NULL REFERENCES topics(id) ON DELETE CASCADE,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+    PRIMARY KEY (user_id, topic_id)
+);
+
+-- Last counted view of a topic per viewer, used to debounce view counts
+CREATE TABLE IF NOT EXISTS topic_views (
+    topic_id INTEGER NOT NULL REFERENCES topics(id) ON DELETE CASCADE,
+    viewer_key TEXT NOT NULL,
+    viewed_at DATETIME NOT NULL,
+    PRIMARY KEY (topic_id, viewer_key)
+);
+
+-- Each edit keeps the title and content the topic had before it
+CREATE TABLE IF NOT EXISTS topic_revisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    topic_id INTEGER NOT NULL REFERENCES topics(id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    content TEXT NOT NULL,
+    editor_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Report reasons, managed by admins
+CREATE TABLE IF NOT EXISTS report_reasons (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    label TEXT NOT NULL UNIQUE,
+    active BOOLEAN NOT NULL DEFAULT 1,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Default report reasons. OR IGNORE keeps a retired default retired.
+INSERT OR IGNORE INTO report_reasons (label) VALUES
+    ('Spam'),
+    ('Harassment or abuse'),
+    ('Off-topic'),
+    ('Inappropriate content'),
+    ('Misinformation'),
+    ('Other');
+
+-- Reports filed against topics or comments. reason keeps the label as it was
+-- when the report was filed.
+CREATE TABLE IF NOT EXISTS reports (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    reporter_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    topic_id INTEGER REFERENCES topics(id) ON DELETE CASCADE,
+    comment_id INTEGER REFERENCES comments(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending',
+    resolved_by TEXT REFERENCES users(id) ON DELETE SET NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    resolved_at DATETIME
 );
 
 -- Votes
@@ -90,6 +229,14 @@ CREATE TABLE IF NOT EXISTS votes (
     UNIQUE (user_id, comment_id)
 );
 
+-- One row per vote cast or switched, kept after the vote itself is removed,
+-- so the daily cap counts casts rather than net votes.
+CREATE TABLE IF NOT EXISTS vote_casts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
 -- Notifications
 CREATE TABLE IF NOT EXISTS notifications (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -100,16 +247,53 @@ CREATE TABLE IF NOT EXISTS notifications (
     related_type TEXT,
     related_id INTEGER,
     is_read BOOLEAN DEFAULT 0,
+    count INTEGER NOT NULL DEFAULT 1,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
 );
 
+-- Notification types a user has switched on or off; a type without a row is on
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    PRIMARY KEY (user_id, type)
+);
+
+-- Staff actions. admin_id is deliberately not a foreign key so the trail
+-- outlives the accounts that left it.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    admin_id TEXT NOT NULL,
+    action TEXT NOT NULL,
+    target_type TEXT NOT NULL,
+    target_id TEXT NOT NULL,
+    details TEXT NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Users asking for a staff role. admin_id and admin_notes are filled in by
+-- the admin who decides the request.
+CREATE TABLE IF NOT EXISTS role_requests (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    requested_role TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    admin_id TEXT REFERENCES users(id) ON DELETE SET NULL,
+    admin_notes TEXT NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    reviewed_at DATETIME
+);
+
 --Topic/category junction table indexes
 CREATE INDEX IF NOT EXISTS idx_topic_categories_topic_id ON topic_categories(topic_id);
 CREATE INDEX IF NOT EXISTS idx_topic_categories_category_id ON topic_categories(category_id);
 -- Users table indexes
 CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
 CREATE INDEX IF NOT EXISTS idx_users_username ON users(username);
+CREATE INDEX IF NOT EXISTS idx_username_history_user ON username_history(user_id);
+CREATE INDEX IF NOT EXISTS idx_username_history_name ON username_history(old_username);
 
 -- Sessions table indexes  
 CREATE INDEX IF NOT EXISTS idx_sessions_user ON sessions(user_id);
@@ -125,6 +309,7 @@ CREATE INDEX IF NOT EXISTS idx_topics_created ON topics(created_at DESC);
 -- Comments table indexes
 CREATE INDEX IF NOT EXISTS idx_comments_topic ON comments(topic_id);
 CREATE INDEX IF NOT EXISTS idx_comments_user ON comments(user_id);
+CREATE INDEX IF NOT EXISTS idx_topic_revisions_topic ON topic_revisions(topic_id, id);
 
 -- Votes table indexes
 -- Prevent duplicate votes
@@ -143,7 +328,19 @@ ON votes(comment_id, reaction_type) WHERE comment_id IS NOT NULL;
 
 -- User activity lookup
 CREATE INDEX IF NOT EXISTS idx_votes_user ON votes(user_id);
+CREATE INDEX IF NOT EXISTS idx_vote_casts_user ON vote_casts(user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user ON password_reset_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_email_verification_tokens_user ON email_verification_tokens(user_id);
 
 -- Notifications table indexes
 CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications(user_id);
-CREATE INDEX IF NOT EXISTS idx_notifications_is_read ON notifications(is_read);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_notifications_is_read ON notifications(is_read);
+-- Keeps the header badge's unread count to a scan of the unread rows alone
+CREATE INDEX IF NOT EXISTS idx_notifications_user_unread ON notifications(user_id) WHERE is_read = 0;
+-- Reports table indexes
+CREATE INDEX IF NOT EXISTS idx_reports_comment ON reports(comment_id, status);
+CREATE INDEX IF NOT EXISTS idx_reports_topic ON reports(topic_id, status);
+-- Role requests table indexes
+-- One open request per user at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_role_requests_one_pending
+ON role_requests(user_id) WHERE status = 'pending';
@@ -1,8 +1,8 @@
 -- Users
-INSERT OR IGNORE INTO users (id, email, username, password_hash) VALUES
-('df16d238-e4dd-4645-9101-54aed9c0fbf4','dev1@forum.test', 'dev_user1', '150000$ZGV2c2FsdDEyMw==$bXzDzL8hQN1qV7z6X0Xj3a8l6y1wY0s3J7xKt8fHfE4='),
-('000dec3a-51af-4e7c-ae0c-21436a0a2395','dev2@forum.test', 'dev_user2', '150000$ZGV2c2FsdDEyMw==$bXzDzL8hQN1qV7z6X0Xj3a8l6y1wY0s3J7xKt8fHfE4='),
-('f1433622-9c10-44e5-94b1-1f6a148c9131','admin@forum.test', 'forum_admin', '150000$YWRtaW5zYWx0$c2VjcmV0YWRtaW5oYXNo');
+INSERT OR IGNORE INTO users (id, email, username, password_hash, email_verified) VALUES
+('df16d238-e4dd-4645-9101-54aed9c0fbf4','dev1@forum.test', 'dev_user1', '150000$ZGV2c2FsdDEyMw==$bXzDzL8hQN1qV7z6X0Xj3a8l6y1wY0s3J7xKt8fHfE4=', 1),
+('000dec3a-51af-4e7c-ae0c-21436a0a2395','dev2@forum.test', 'dev_user2', '150000$ZGV2c2FsdDEyMw==$bXzDzL8hQN1qV7z6X0Xj3a8l6y1wY0s3J7xKt8fHfE4=', 1),
+('f1433622-9c10-44e5-94b1-1f6a148c9131','admin@forum.test', 'forum_admin', '150000$YWRtaW5zYWx0$c2VjcmV0YWRtaW5oYXNo', 1);
 
 -- Sessions
 INSERT OR IGNORE INTO sessions (token, user_id, expires_at, refresh_token, refresh_token_expires_at) VALUES
@@ -42,4 +42,9 @@ INSERT OR IGNORE INTO votes (user_id, topic_id, comment_id, reaction_type) VALUE
 ('000dec3a-51af-4e7c-ae0c-21436a0a2395', 2, NULL, 1),
 ('f1433622-9c10-44e5-94b1-1f6a148c9131', 3, NULL, -1),
 ('000dec3a-51af-4e7c-ae0c-21436a0a2395', 1, NULL, 1),
-('f1433622-9c10-44e5-94b1-1f6a148c9131', 1, NULL, -1);
\ No newline at end of file
+('f1433622-9c10-44e5-94b1-1f6a148c9131', 1, NULL, -1);
+
+-- The seed inserts votes directly, so bring the stored topic counters in line.
+UPDATE topics SET
+    upvote_count = (SELECT COUNT(*) FROM votes WHERE votes.topic_id = topics.id AND votes.comment_id IS NULL AND votes.reaction_type = 1),
+    downvote_count = (SELECT COUNT(*) FROM votes WHERE votes.topic_id = topics.id AND votes.comment_id IS NULL AND votes.reaction_type = -1);
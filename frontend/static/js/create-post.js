@@ -68,15 +68,51 @@ document.addEventListener("DOMContentLoaded", () => {
       });
     }
 
+    // some categories only accept topics with an image
+    function updateImageRequirement() {
+      const requirement = document.getElementById("image-requirement");
+      if (!requirement) return;
+
+      const required = ms.querySelector(
+        'input[type="checkbox"][data-requires-image="true"]:checked'
+      );
+      requirement.textContent = required
+        ? "(required by the selected category)"
+        : "(optional)";
+    }
+
+    // the primary category can only be one of the selected ones
+    function rebuildCanonicalOptions() {
+      const select = document.getElementById("canonicalCategory");
+      if (!select) return;
+
+      const previous = select.value;
+      select.innerHTML = "";
+      ms.querySelectorAll('input[type="checkbox"]:checked').forEach((cb) => {
+        const option = document.createElement("option");
+        option.value = cb.value;
+        option.textContent =
+          cb.parentElement.querySelector(".option-label")?.textContent ||
+          cb.value;
+        option.selected = cb.value === previous;
+        select.appendChild(option);
+      });
+      select.disabled = select.options.length === 0;
+    }
+
     const checkboxes = ms.querySelectorAll('input[type="checkbox"]');
     checkboxes.forEach((cb) => {
       cb.addEventListener("change", () => {
         rebuildChips();
+        rebuildCanonicalOptions();
+        updateImageRequirement();
       });
     });
 
     // initialize
     rebuildChips();
+    rebuildCanonicalOptions();
+    updateImageRequirement();
 
     ms.addEventListener("keydown", (e) => {
       if (e.key === "Enter" || e.key === " ") {
@@ -178,6 +214,16 @@ document.addEventListener("DOMContentLoaded", () => {
         hasError = true;
       }
 
+      const requiresImage = document.querySelector(
+        '#categorySelect input[type="checkbox"][data-requires-image="true"]:checked'
+      );
+      const imageInput = document.getElementById("image-upload");
+      if (requiresImage && imageInput && imageInput.files.length === 0) {
+        document.getElementById("error-image").textContent =
+          "An image is required for the selected category";
+        hasError = true;
+      }
+
       const title = document.getElementById("title").value.trim();
       if (!title) {
         document.getElementById("error-title").textContent =
@@ -115,7 +115,9 @@
           <div class="notification-icon ${n.type}">${icon}</div>
           <div class="notification-content">
             <div class="notification-title">${escapeHtml(n.title)}</div>
-            <div class="notification-message">${escapeHtml(n.message)}</div>
+            <div class="notification-message">${escapeHtml(n.message)}${
+              n.count > 1 ? ` (+${n.count - 1} more)` : ""
+            }</div>
             <div class="notification-time">${timeAgo}</div>
           </div>
           ${!n.isRead ? '<div class="notification-unread-dot"></div>' : ""}
@@ -160,6 +162,7 @@
     try {
       await fetch(`/api/notifications/mark-read?id=${id}`, {
         method: "POST",
+        headers: { "X-CSRF-Token": csrfToken() },
       });
     } catch (error) {
       console.error("Error marking as read:", error);
@@ -184,6 +187,7 @@
       // Send single request to backend
       const response = await fetch("/api/notifications/mark-all-read", {
         method: "POST",
+        headers: { "X-CSRF-Token": csrfToken() },
       });
 
       if (!response.ok) {
@@ -1,3 +1,10 @@
+// ==== CSRF ==== //
+// The token state-changing requests must send in the X-CSRF-Token header.
+function csrfToken() {
+  const meta = document.querySelector('meta[name="csrf-token"]');
+  return meta ? meta.content : "";
+}
+
 // ==== Homepage ==== //
 // Close the details when clicking outside
 document.addEventListener("click", function (event) {
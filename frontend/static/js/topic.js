@@ -38,6 +38,29 @@ if (closeCommentBtn && addCommentForm) {
   });
 }
 
+////// Spoilers - reveal on click or keyboard //////
+document.addEventListener("click", (e) => {
+  const spoiler = e.target.closest(".spoiler");
+  if (spoiler) spoiler.classList.add("revealed");
+});
+
+document.addEventListener("keydown", (e) => {
+  if (e.key !== "Enter" && e.key !== " ") return;
+  const spoiler = e.target.closest(".spoiler");
+  if (!spoiler) return;
+  e.preventDefault();
+  spoiler.classList.add("revealed");
+});
+
+////// Reported comments - reveal the collapsed body on request //////
+document.addEventListener("click", (e) => {
+  const button = e.target.closest(".btn-show-collapsed");
+  if (!button) return;
+  const container = button.closest(".comment-body-container");
+  container?.querySelector(".comment-collapsed")?.classList.add("revealed");
+  button.closest(".comment-collapsed-warning")?.remove();
+});
+
 ////// Edit button handlers - Show edit forms //////
 document.addEventListener("click", (e) => {
   const target = e.target;
@@ -84,6 +84,7 @@ async function castVote(targetId, targetType, reactionType, buttonElement) {
     method: "POST",
     headers: {
       "Content-Type": "application/json",
+      "X-CSRF-Token": csrfToken(),
     },
     credentials: "include",
     body: JSON.stringify(payload),
@@ -94,6 +95,13 @@ async function castVote(targetId, targetType, reactionType, buttonElement) {
     return;
   }
 
+  // Daily vote cap reached; the server explains when to come back.
+  if (response.status === 429) {
+    const errorData = await response.json().catch(() => ({}));
+    alert(errorData.error || "You have reached the daily vote limit.");
+    return;
+  }
+
   if (!response.ok) {
     const errorData = await response.json().catch(() => ({}));
     throw new Error(errorData.message || "Failed to cast vote");
@@ -119,6 +127,7 @@ async function deleteVote(targetId, targetType, buttonElement) {
     method: "DELETE",
     headers: {
       "Content-Type": "application/json",
+      "X-CSRF-Token": csrfToken(),
     },
     credentials: "include",
     body: JSON.stringify(payload),
@@ -175,7 +184,12 @@ async function updateVoteUI(targetId, targetType, clickedButton) {
     .closest(".topic-body-container")
     ?.querySelector(".views-count");
   if (voteScoreElement) {
-    voteScoreElement.textContent = counts.score;
+    // Mirror the server-side threshold: too few votes shows "new" instead
+    const minVotes = parseInt(voteScoreElement.dataset.minVotes, 10) || 0;
+    const exact = voteScoreElement.dataset.exact === "true";
+    const total = counts.upvotes + counts.downvotes;
+    voteScoreElement.textContent =
+      exact || total >= minVotes ? counts.score : "new";
   }
 
   // Toggle button states - if clicking active button, toggle it off
@@ -0,0 +1,32 @@
+package activityqueries
+
+import (
+	"context"
+
+	"github.com/arnald/forum/internal/domain/activity"
+)
+
+// GetActivityFeedRequest asks for a page of one of UserID's activity feeds;
+// Type is one of activity.FeedPosts, FeedComments, FeedLikes or FeedReceived.
+type GetActivityFeedRequest struct {
+	UserID string
+	Type   string
+	Limit  int
+	Offset int
+}
+
+type GetActivityFeedHandler interface {
+	Handle(ctx context.Context, req GetActivityFeedRequest) ([]activity.FeedItem, int, error)
+}
+
+type getActivityFeedHandler struct {
+	repo activity.Repository
+}
+
+func NewGetActivityFeedHandler(repo activity.Repository) GetActivityFeedHandler {
+	return &getActivityFeedHandler{repo: repo}
+}
+
+func (h *getActivityFeedHandler) Handle(ctx context.Context, req GetActivityFeedRequest) ([]activity.FeedItem, int, error) {
+	return h.repo.GetActivityFeed(ctx, req.UserID, req.Type, req.Limit, req.Offset)
+}
@@ -0,0 +1,30 @@
+package activityqueries
+
+import (
+	"context"
+
+	"github.com/arnald/forum/internal/domain/activity"
+)
+
+type GetUserProfileRequest struct {
+	UserID string
+	// IncludePending lists the user's held comments as well; set it only
+	// when the owner is viewing their own profile.
+	IncludePending bool
+}
+
+type GetUserProfileHandler interface {
+	Handle(ctx context.Context, req GetUserProfileRequest) (*activity.Profile, error)
+}
+
+type getUserProfileHandler struct {
+	repo activity.Repository
+}
+
+func NewGetUserProfileHandler(repo activity.Repository) GetUserProfileHandler {
+	return &getUserProfileHandler{repo: repo}
+}
+
+func (h *getUserProfileHandler) Handle(ctx context.Context, req GetUserProfileRequest) (*activity.Profile, error) {
+	return h.repo.GetUserProfile(ctx, req.UserID, req.IncludePending)
+}
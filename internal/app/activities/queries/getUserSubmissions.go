@@ -0,0 +1,27 @@
+package activityqueries
+
+import (
+	"context"
+
+	"github.com/arnald/forum/internal/domain/activity"
+)
+
+type GetUserSubmissionsRequest struct {
+	UserID string
+}
+
+type GetUserSubmissionsHandler interface {
+	Handle(ctx context.Context, req GetUserSubmissionsRequest) (*activity.Submissions, error)
+}
+
+type getUserSubmissionsHandler struct {
+	repo activity.Repository
+}
+
+func NewGetUserSubmissionsHandler(repo activity.Repository) GetUserSubmissionsHandler {
+	return &getUserSubmissionsHandler{repo: repo}
+}
+
+func (h *getUserSubmissionsHandler) Handle(ctx context.Context, req GetUserSubmissionsRequest) (*activity.Submissions, error) {
+	return h.repo.GetUserSubmissions(ctx, req.UserID)
+}
@@ -0,0 +1,30 @@
+package auditqueries
+
+import (
+	"context"
+
+	"github.com/arnald/forum/internal/domain/audit"
+)
+
+type GetAuditLogRequest struct {
+	Limit  int
+	Offset int
+}
+
+type GetAuditLogRequestHandler interface {
+	Handle(ctx context.Context, req GetAuditLogRequest) ([]audit.Entry, int, error)
+}
+
+type getAuditLogRequestHandler struct {
+	repo audit.Repository
+}
+
+func NewGetAuditLogHandler(repo audit.Repository) GetAuditLogRequestHandler {
+	return &getAuditLogRequestHandler{
+		repo: repo,
+	}
+}
+
+func (h *getAuditLogRequestHandler) Handle(ctx context.Context, req GetAuditLogRequest) ([]audit.Entry, int, error) {
+	return h.repo.GetAuditLog(ctx, req.Limit, req.Offset)
+}
@@ -0,0 +1,32 @@
+package categorycommands
+
+import (
+	"context"
+
+	"github.com/arnald/forum/internal/domain/category"
+)
+
+// ArchiveCategoryRequest hides a category, or brings it back when Archived
+// is false. Topics filed under it keep the association either way.
+type ArchiveCategoryRequest struct {
+	CategoryID int
+	Archived   bool
+}
+
+type ArchiveCategoryRequestHandler interface {
+	Handle(ctx context.Context, req ArchiveCategoryRequest) error
+}
+
+type archiveCategoryRequestHandler struct {
+	repo category.Repository
+}
+
+func NewArchiveCategoryHandler(repo category.Repository) ArchiveCategoryRequestHandler {
+	return &archiveCategoryRequestHandler{
+		repo: repo,
+	}
+}
+
+func (h *archiveCategoryRequestHandler) Handle(ctx context.Context, req ArchiveCategoryRequest) error {
+	return h.repo.SetCategoryArchived(ctx, req.CategoryID, req.Archived)
+}
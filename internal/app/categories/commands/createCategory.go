@@ -9,7 +9,11 @@ import (
 type CreateCategoryRequest struct {
 	Name        string
 	Description string
-	CreatedBy   string
+	// Color is the hex color of the category's chip; empty picks the
+	// neutral default.
+	Color         string
+	CreatedBy     string
+	RequiresImage bool
 }
 
 type CreateCategoryRequestHandler interface {
@@ -28,9 +32,11 @@ func NewCreateCategoryHandler(repo category.Repository) CreateCategoryRequestHan
 
 func (h *createCategoryRequestHandler) Handle(ctx context.Context, req CreateCategoryRequest) error {
 	category := &category.Category{
-		Name:        req.Name,
-		Description: req.Description,
-		CreatedBy:   req.CreatedBy,
+		Name:          req.Name,
+		Description:   req.Description,
+		Color:         req.Color,
+		CreatedBy:     req.CreatedBy,
+		RequiresImage: req.RequiresImage,
 	}
 
 	err := h.repo.CreateCategory(ctx, category)
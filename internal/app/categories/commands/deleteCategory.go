@@ -2,13 +2,17 @@ package categorycommands
 
 import (
 	"context"
+	"strconv"
 
+	"github.com/arnald/forum/internal/domain/audit"
 	"github.com/arnald/forum/internal/domain/category"
 )
 
 type DeleteCategoryRequest struct {
 	UserID     string
 	CategoryID int
+	// Force deletes the category even while topics are filed under it.
+	Force bool
 }
 
 type DeleteCategoryRequestHandler interface {
@@ -16,19 +20,32 @@ type DeleteCategoryRequestHandler interface {
 }
 
 type deleteCategoryRequestHandler struct {
-	repo category.Repository
+	repo  category.Repository
+	audit audit.Repository
 }
 
-func NewDeleteCategoryHandler(repo category.Repository) DeleteCategoryRequestHandler {
+func NewDeleteCategoryHandler(repo category.Repository, auditRepo audit.Repository) DeleteCategoryRequestHandler {
 	return &deleteCategoryRequestHandler{
-		repo: repo,
+		repo:  repo,
+		audit: auditRepo,
 	}
 }
 
 func (h *deleteCategoryRequestHandler) Handle(ctx context.Context, req DeleteCategoryRequest) error {
-	err := h.repo.DeleteCategory(ctx, req.CategoryID, req.UserID)
+	err := h.repo.DeleteCategory(ctx, req.CategoryID, req.UserID, req.Force)
 	if err != nil {
 		return err
 	}
-	return nil
+
+	entry := &audit.Entry{
+		AdminID:    req.UserID,
+		Action:     audit.ActionDeleteCategory,
+		TargetType: audit.TargetCategory,
+		TargetID:   strconv.Itoa(req.CategoryID),
+	}
+	if req.Force {
+		entry.Details = "forced"
+	}
+
+	return h.audit.LogAudit(ctx, entry)
 }
@@ -0,0 +1,37 @@
+package categorycommands
+
+import (
+	"context"
+
+	"github.com/arnald/forum/internal/domain/category"
+)
+
+// SetCategoryModeratorRequest lets UserID moderate comments in the category,
+// or stops them when Assigned is false. Admins moderate every category
+// without an assignment.
+type SetCategoryModeratorRequest struct {
+	UserID     string
+	CategoryID int
+	Assigned   bool
+}
+
+type SetCategoryModeratorRequestHandler interface {
+	Handle(ctx context.Context, req SetCategoryModeratorRequest) error
+}
+
+type setCategoryModeratorRequestHandler struct {
+	repo category.Repository
+}
+
+func NewSetCategoryModeratorHandler(repo category.Repository) SetCategoryModeratorRequestHandler {
+	return &setCategoryModeratorRequestHandler{
+		repo: repo,
+	}
+}
+
+func (h *setCategoryModeratorRequestHandler) Handle(ctx context.Context, req SetCategoryModeratorRequest) error {
+	if req.Assigned {
+		return h.repo.AssignModerator(ctx, req.CategoryID, req.UserID)
+	}
+	return h.repo.UnassignModerator(ctx, req.CategoryID, req.UserID)
+}
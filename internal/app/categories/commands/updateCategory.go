@@ -6,10 +6,13 @@ import (
 	"github.com/arnald/forum/internal/domain/category"
 )
 
+// UpdateCategoryRequest replaces a category's name and description. A nil
+// RequiresImage keeps the category's current setting.
 type UpdateCategoryRequest struct {
-	Name        string
-	Description string
-	ID          int
+	RequiresImage *bool
+	Name          string
+	Description   string
+	ID            int
 }
 
 type UpdateCategoryRequestHandler interface {
@@ -32,6 +35,17 @@ func (h *updateCategoryRequestHandler) Handle(ctx context.Context, req UpdateCat
 		Name:        req.Name,
 		Description: req.Description,
 	}
+
+	if req.RequiresImage != nil {
+		category.RequiresImage = *req.RequiresImage
+	} else {
+		current, err := h.repo.GetCategoryByID(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+		category.RequiresImage = current.RequiresImage
+	}
+
 	err := h.repo.UpdateCategory(ctx, category)
 	if err != nil {
 		return err
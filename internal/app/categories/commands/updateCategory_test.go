@@ -0,0 +1,88 @@
+package categorycommands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/arnald/forum/internal/domain/category"
+)
+
+// storedCategoryRepo holds a single category and applies updates to it.
+type storedCategoryRepo struct {
+	category.Repository
+	stored category.Category
+}
+
+func (s *storedCategoryRepo) GetCategoryByID(_ context.Context, _ int) (*category.Category, error) {
+	current := s.stored
+	return &current, nil
+}
+
+func (s *storedCategoryRepo) UpdateCategory(_ context.Context, c *category.Category) error {
+	s.stored = *c
+	return nil
+}
+
+func TestUpdateCategoryHandler_RequiresImage(t *testing.T) {
+	t.Run("group: requires image", func(t *testing.T) {
+		testCases := newRequiresImageTestCases()
+		for _, tt := range testCases {
+			t.Run(tt.name, runRequiresImageTest(tt))
+		}
+	})
+}
+
+type requiresImageTestCase struct {
+	sent   *bool
+	name   string
+	stored bool
+	want   bool
+}
+
+func newRequiresImageTestCases() []requiresImageTestCase {
+	on, off := true, false
+
+	return []requiresImageTestCase{
+		{
+			name:   "omitted keeps the requirement",
+			stored: true,
+			want:   true,
+		},
+		{
+			name: "omitted keeps no requirement",
+		},
+		{
+			name:   "false clears the requirement",
+			sent:   &off,
+			stored: true,
+		},
+		{
+			name: "true sets the requirement",
+			sent: &on,
+			want: true,
+		},
+	}
+}
+
+func runRequiresImageTest(tt requiresImageTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		repo := &storedCategoryRepo{stored: category.Category{ID: 1, Name: "Photos", RequiresImage: tt.stored}}
+
+		err := NewUpdateCategoryHandler(repo).Handle(context.Background(), UpdateCategoryRequest{
+			ID:            1,
+			Name:          "Pictures",
+			Description:   "Show us yours",
+			RequiresImage: tt.sent,
+		})
+		if err != nil {
+			t.Fatalf("Handle() error = %v", err)
+		}
+
+		if repo.stored.RequiresImage != tt.want {
+			t.Errorf("RequiresImage = %v, want %v", repo.stored.RequiresImage, tt.want)
+		}
+		if repo.stored.Name != "Pictures" {
+			t.Errorf("Name = %q, want %q", repo.stored.Name, "Pictures")
+		}
+	}
+}
@@ -13,6 +13,8 @@ type GetAllCategoriesRequest struct {
 	Page    int    `json:"page"`
 	Size    int    `json:"size"`
 	Offset  int    `json:"offset"`
+	// CanonicalOnly lists each topic under its canonical category only.
+	CanonicalOnly bool `json:"-"`
 }
 
 type GetAllCategoriesRequestHandler interface {
@@ -42,7 +44,7 @@ func (h getAllCategoriesRequestHandler) Handle(ctx context.Context, req GetAllCa
 		return nil, 0, err
 	}
 
-	categories, err = h.repo.PopulateCategoriesWithTopics(ctx, categories)
+	categories, err = h.repo.PopulateCategoriesWithTopics(ctx, categories, req.CanonicalOnly)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -0,0 +1,80 @@
+package commentcommands
+
+import (
+	"context"
+
+	"github.com/arnald/forum/internal/domain/audit"
+	"github.com/arnald/forum/internal/domain/comment"
+	"github.com/arnald/forum/internal/domain/user"
+)
+
+// MaxBulkComments caps how many comments one bulk decision may cover.
+const MaxBulkComments = 100
+
+// BulkModerateCommentsRequest settles a selection of pending comments with
+// the same Decision, comment.StatusApproved or comment.StatusRejected.
+type BulkModerateCommentsRequest struct {
+	Moderator  *user.User
+	Decision   string
+	CommentIDs []int
+}
+
+type BulkModerateCommentsRequestHandler interface {
+	// Handle returns the comments that were settled. Selected comments that
+	// are no longer pending, or do not exist, are skipped. A moderator who is
+	// not an admin settles nothing if any selected comment lies outside their
+	// categories.
+	Handle(ctx context.Context, req BulkModerateCommentsRequest) ([]*comment.Comment, error)
+}
+
+type bulkModerateCommentsRequestHandler struct {
+	repo comment.Repository
+}
+
+func NewBulkModerateCommentsHandler(repo comment.Repository) BulkModerateCommentsRequestHandler {
+	return &bulkModerateCommentsRequestHandler{
+		repo: repo,
+	}
+}
+
+func (h *bulkModerateCommentsRequestHandler) Handle(ctx context.Context, req BulkModerateCommentsRequest) ([]*comment.Comment, error) {
+	if !req.Moderator.IsModerator() {
+		return nil, ErrNotModerator
+	}
+	if req.Decision != comment.StatusApproved && req.Decision != comment.StatusRejected {
+		return nil, ErrInvalidDecision
+	}
+	if len(req.CommentIDs) == 0 {
+		return nil, ErrNoCommentsSelected
+	}
+	if len(req.CommentIDs) > MaxBulkComments {
+		return nil, ErrTooManyComments
+	}
+
+	err := checkModeratorScope(ctx, h.repo, req.Moderator, req.CommentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// The audit entries are written with the decision, so a failed log
+	// leaves the comments pending rather than settled without a record.
+	ids, err := h.repo.SetCommentsStatus(ctx, req.CommentIDs, req.Decision, req.Moderator.ID,
+		func(settled *comment.Comment) *audit.Entry {
+			return moderationEntry(req.Moderator, settled)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	moderated := make([]*comment.Comment, 0, len(ids))
+	for _, id := range ids {
+		var c *comment.Comment
+		c, err = h.repo.GetCommentByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		moderated = append(moderated, c)
+	}
+
+	return moderated, nil
+}
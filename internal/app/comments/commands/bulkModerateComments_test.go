@@ -0,0 +1,199 @@
+package commentcommands
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/arnald/forum/internal/domain/audit"
+	"github.com/arnald/forum/internal/domain/comment"
+	"github.com/arnald/forum/internal/domain/user"
+)
+
+// queueCommentRepo holds a handful of comments by ID and settles the pending
+// ones the way the real repository does. Comments on foreignTopics lie outside
+// the moderator's categories. The audit entries written alongside each
+// decision are kept in logged.
+type queueCommentRepo struct {
+	comment.Repository
+	stored        map[int]*comment.Comment
+	logged        []*audit.Entry
+	foreignTopics []int
+}
+
+func (q *queueCommentRepo) CommentsOutsideModeration(_ context.Context, _ string, ids []int) ([]int, error) {
+	outside := make([]int, 0)
+	for _, id := range ids {
+		c, ok := q.stored[id]
+		if ok && slices.Contains(q.foreignTopics, c.TopicID) {
+			outside = append(outside, id)
+		}
+	}
+	slices.Sort(outside)
+	return outside, nil
+}
+
+func (q *queueCommentRepo) SetCommentsStatus(_ context.Context, ids []int, status, _ string, auditEntry func(*comment.Comment) *audit.Entry) ([]int, error) {
+	settled := make([]int, 0, len(ids))
+	for _, id := range ids {
+		c, ok := q.stored[id]
+		if !ok || c.Status != comment.StatusPending {
+			continue
+		}
+		c.Status = status
+		settled = append(settled, id)
+	}
+	slices.Sort(settled)
+	for _, id := range settled {
+		q.logged = append(q.logged, auditEntry(q.stored[id]))
+	}
+	return settled, nil
+}
+
+func (q *queueCommentRepo) GetCommentByID(_ context.Context, id int) (*comment.Comment, error) {
+	return q.stored[id], nil
+}
+
+func newQueueCommentRepo() *queueCommentRepo {
+	return &queueCommentRepo{stored: map[int]*comment.Comment{
+		1: {ID: 1, TopicID: 10, Status: comment.StatusPending},
+		2: {ID: 2, TopicID: 10, Status: comment.StatusApproved},
+		3: {ID: 3, TopicID: 11, Status: comment.StatusPending},
+		4: {ID: 4, TopicID: 11, Status: comment.StatusPending},
+	}}
+}
+
+func TestBulkModerateCommentsHandler_Handle(t *testing.T) {
+	t.Run("group: bulk moderation", func(t *testing.T) {
+		testCases := newBulkModerateTestCases()
+		for _, tt := range testCases {
+			t.Run(tt.name, runBulkModerateTest(tt))
+		}
+	})
+}
+
+type bulkModerateTestCase struct {
+	wantError     error
+	moderator     *user.User
+	name          string
+	decision      string
+	wantAction    string
+	selected      []int
+	wantIDs       []int
+	foreignTopics []int
+}
+
+func newBulkModerateTestCases() []bulkModerateTestCase {
+	mod := &user.User{ID: "mod", Role: user.RoleModerator}
+
+	return []bulkModerateTestCase{
+		{
+			name:       "mixed selection approves only the pending comments",
+			moderator:  mod,
+			decision:   comment.StatusApproved,
+			selected:   []int{3, 2, 42, 1},
+			wantIDs:    []int{1, 3},
+			wantAction: audit.ActionApproveComment,
+		},
+		{
+			name:       "rejection",
+			moderator:  mod,
+			decision:   comment.StatusRejected,
+			selected:   []int{4},
+			wantIDs:    []int{4},
+			wantAction: audit.ActionRejectComment,
+		},
+		{
+			name:      "selection with nothing pending",
+			moderator: mod,
+			decision:  comment.StatusApproved,
+			selected:  []int{2, 42},
+			wantIDs:   []int{},
+		},
+		{
+			name:      "empty selection",
+			moderator: mod,
+			decision:  comment.StatusApproved,
+			wantError: ErrNoCommentsSelected,
+		},
+		{
+			name:      "oversized selection",
+			moderator: mod,
+			decision:  comment.StatusApproved,
+			selected:  make([]int, MaxBulkComments+1),
+			wantError: ErrTooManyComments,
+		},
+		{
+			name:          "selection reaching outside the moderator's categories",
+			moderator:     mod,
+			decision:      comment.StatusApproved,
+			selected:      []int{1, 3},
+			foreignTopics: []int{11},
+			wantError:     ErrOutsideModeratorScope,
+		},
+		{
+			name:          "admins moderate every category",
+			moderator:     &user.User{ID: "mod", Role: user.RoleAdmin},
+			decision:      comment.StatusApproved,
+			selected:      []int{1, 3},
+			foreignTopics: []int{11},
+			wantIDs:       []int{1, 3},
+			wantAction:    audit.ActionApproveComment,
+		},
+		{
+			name:      "regular users cannot moderate",
+			moderator: &user.User{ID: "someone", Role: user.RoleUser},
+			decision:  comment.StatusApproved,
+			selected:  []int{1},
+			wantError: ErrNotModerator,
+		},
+	}
+}
+
+func runBulkModerateTest(tt bulkModerateTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		comments := newQueueCommentRepo()
+		comments.foreignTopics = tt.foreignTopics
+		moderated, err := NewBulkModerateCommentsHandler(comments).Handle(context.Background(), BulkModerateCommentsRequest{
+			Moderator:  tt.moderator,
+			Decision:   tt.decision,
+			CommentIDs: tt.selected,
+		})
+		if !errors.Is(err, tt.wantError) {
+			t.Fatalf("Handle() error = %v, want %v", err, tt.wantError)
+		}
+		if tt.wantError != nil {
+			if len(comments.logged) != 0 {
+				t.Errorf("Handle() logged %+v, want nothing", comments.logged)
+			}
+			if comments.stored[1].Status != comment.StatusPending {
+				t.Errorf("refused selection settled comment 1 as %q", comments.stored[1].Status)
+			}
+			return
+		}
+
+		gotIDs := make([]int, 0, len(moderated))
+		for _, c := range moderated {
+			gotIDs = append(gotIDs, c.ID)
+			if c.Status != tt.decision {
+				t.Errorf("comment %d Status = %q, want %q", c.ID, c.Status, tt.decision)
+			}
+		}
+		if !slices.Equal(gotIDs, tt.wantIDs) {
+			t.Errorf("Handle() moderated %v, want %v", gotIDs, tt.wantIDs)
+		}
+
+		if len(comments.logged) != len(tt.wantIDs) {
+			t.Fatalf("Handle() logged %d entries, want %d", len(comments.logged), len(tt.wantIDs))
+		}
+		for i, entry := range comments.logged {
+			if entry.Action != tt.wantAction || entry.AdminID != "mod" || entry.TargetType != audit.TargetComment {
+				t.Errorf("audit entry %d = %+v, want a %s by mod", i, entry, tt.wantAction)
+			}
+		}
+		if comments.stored[2].Status != comment.StatusApproved {
+			t.Errorf("already approved comment changed to %q", comments.stored[2].Status)
+		}
+	}
+}
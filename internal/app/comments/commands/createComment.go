@@ -2,15 +2,29 @@ package commentcommands
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/arnald/forum/internal/domain/comment"
+	"github.com/arnald/forum/internal/domain/topic"
 	"github.com/arnald/forum/internal/domain/user"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/topics"
 )
 
+// ReviewPolicy holds comments by young accounts for moderation. It is off
+// unless the server enables it, and then every comment is published at once;
+// moderators are never held.
+type ReviewPolicy struct {
+	MinAccountAge time.Duration
+	Enabled       bool
+}
+
 type CreateCommentRequest struct {
-	User    *user.User
-	Content string `json:"content"`
-	TopicID int    `json:"topicId"`
+	User     *user.User
+	ParentID *int   `json:"parentId"`
+	Content  string `json:"content"`
+	TopicID  int    `json:"topicId"`
+	Review   ReviewPolicy
 }
 
 type CreateCommentRequestHandler interface {
@@ -18,25 +32,65 @@ type CreateCommentRequestHandler interface {
 }
 
 type createCommentRequestHandler struct {
-	repo comment.Repository
+	repo      comment.Repository
+	topicRepo topic.Repository
 }
 
-func NewCreateCommentRequestHandler(repo comment.Repository) CreateCommentRequestHandler {
+func NewCreateCommentRequestHandler(repo comment.Repository, topicRepo topic.Repository) CreateCommentRequestHandler {
 	return &createCommentRequestHandler{
-		repo: repo,
+		repo:      repo,
+		topicRepo: topicRepo,
 	}
 }
 
+// Handle adds the comment to a topic the author can see; deleted topics and
+// other users' drafts are reported as not found.
 func (h *createCommentRequestHandler) Handle(ctx context.Context, req CreateCommentRequest) (*comment.Comment, error) {
+	visible, err := h.topicRepo.IsTopicVisible(ctx, req.TopicID, &req.User.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, fmt.Errorf("topic with ID %d not found: %w", req.TopicID, topics.ErrTopicNotFound)
+	}
+
+	if req.ParentID != nil {
+		parent, err := h.repo.GetCommentByID(ctx, *req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.TopicID != req.TopicID {
+			return nil, ErrParentCommentMismatch
+		}
+		if parent.Status != comment.StatusApproved && parent.UserID != req.User.ID {
+			return nil, ErrParentCommentMismatch
+		}
+	}
+
+	status := comment.StatusApproved
+	if needsReview(req.User, req.Review, time.Now()) {
+		status = comment.StatusPending
+	}
+
 	comment := &comment.Comment{
-		UserID:  req.User.ID,
-		TopicID: req.TopicID,
-		Content: req.Content,
+		UserID:   req.User.ID,
+		TopicID:  req.TopicID,
+		ParentID: req.ParentID,
+		Content:  req.Content,
+		Status:   status,
 	}
 
-	err := h.repo.CreateComment(ctx, comment)
+	err = h.repo.CreateComment(ctx, comment)
 	if err != nil {
 		return nil, err
 	}
 	return comment, nil
 }
+
+// needsReview reports whether a comment by author has to wait for a moderator.
+func needsReview(author *user.User, policy ReviewPolicy, now time.Time) bool {
+	if !policy.Enabled || author.IsModerator() {
+		return false
+	}
+	return now.Sub(author.CreatedAt) < policy.MinAccountAge
+}
@@ -0,0 +1,121 @@
+package commentcommands
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/arnald/forum/internal/domain/comment"
+	"github.com/arnald/forum/internal/domain/user"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/topics"
+	testhelpers "github.com/arnald/forum/internal/pkg/testing"
+)
+
+func (s *stubCommentRepo) CreateComment(_ context.Context, created *comment.Comment) error {
+	s.stored = created
+	return nil
+}
+
+func TestCreateCommentHandler_TopicVisibility(t *testing.T) {
+	testCases := []struct {
+		wantErr error
+		name    string
+		visible bool
+	}{
+		{name: "visible topic takes the comment", visible: true},
+		{name: "hidden topic is not found", wantErr: topics.ErrTopicNotFound},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			comments := &stubCommentRepo{}
+			topicRepo := &testhelpers.MockRepository{
+				IsTopicVisibleFunc: func(_ context.Context, _ int, userID *string) (bool, error) {
+					if userID == nil || *userID != "author" {
+						t.Errorf("IsTopicVisible() userID = %v, want the author", userID)
+					}
+					return tt.visible, nil
+				},
+			}
+			handler := NewCreateCommentRequestHandler(comments, topicRepo)
+
+			_, err := handler.Handle(context.Background(), CreateCommentRequest{
+				User:    &user.User{ID: "author"},
+				Content: "comment",
+				TopicID: 3,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Handle() error = %v, want %v", err, tt.wantErr)
+			}
+			if created := comments.stored != nil; created != tt.visible {
+				t.Errorf("comment created = %v, want %v", created, tt.visible)
+			}
+		})
+	}
+}
+
+func TestNeedsReview(t *testing.T) {
+	t.Run("group: new account review", func(t *testing.T) {
+		testCases := newNeedsReviewTestCases()
+		for _, tt := range testCases {
+			t.Run(tt.name, runNeedsReviewTest(tt))
+		}
+	})
+}
+
+type needsReviewTestCase struct {
+	name       string
+	role       string
+	accountAge time.Duration
+	policy     ReviewPolicy
+	want       bool
+}
+
+func newNeedsReviewTestCases() []needsReviewTestCase {
+	enabled := ReviewPolicy{Enabled: true, MinAccountAge: 24 * time.Hour}
+
+	return []needsReviewTestCase{
+		{
+			name:       "young account is held when review is on",
+			role:       user.RoleUser,
+			accountAge: time.Hour,
+			policy:     enabled,
+			want:       true,
+		},
+		{
+			name:       "account past the threshold posts directly",
+			role:       user.RoleUser,
+			accountAge: 48 * time.Hour,
+			policy:     enabled,
+		},
+		{
+			name:       "moderators are never held",
+			role:       user.RoleModerator,
+			accountAge: time.Hour,
+			policy:     enabled,
+		},
+		{
+			name:       "review is off by default",
+			role:       user.RoleUser,
+			accountAge: time.Hour,
+		},
+		{
+			name:       "review off lets moderators post directly",
+			role:       user.RoleModerator,
+			accountAge: time.Hour,
+		},
+	}
+}
+
+func runNeedsReviewTest(tt needsReviewTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		now := time.Now()
+		author := &user.User{Role: tt.role, CreatedAt: now.Add(-tt.accountAge)}
+
+		got := needsReview(author, tt.policy, now)
+		if got != tt.want {
+			t.Errorf("needsReview() = %v, want %v", got, tt.want)
+		}
+	}
+}
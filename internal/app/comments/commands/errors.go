@@ -0,0 +1,13 @@
+package commentcommands
+
+import "errors"
+
+var (
+	ErrParentCommentMismatch  = errors.New("parent comment does not belong to this topic")
+	ErrNotModerator           = errors.New("user is not a moderator")
+	ErrInvalidDecision        = errors.New("moderation decision must be approved or rejected")
+	ErrNoCommentsSelected     = errors.New("no comments selected")
+	ErrTooManyComments        = errors.New("too many comments selected")
+	ErrInvalidRejectionReason = errors.New("rejection reason is not valid")
+	ErrOutsideModeratorScope  = errors.New("comment is outside the moderator's categories")
+)
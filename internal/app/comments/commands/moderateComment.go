@@ -0,0 +1,173 @@
+package commentcommands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/arnald/forum/internal/domain/audit"
+	"github.com/arnald/forum/internal/domain/comment"
+	"github.com/arnald/forum/internal/domain/notification"
+	"github.com/arnald/forum/internal/domain/user"
+)
+
+// ModerateCommentRequest settles a pending comment. Decision is either
+// comment.StatusApproved or comment.StatusRejected. A rejection may carry
+// Reason, the code of one of comment.RejectionReasons or
+// comment.RejectionReasonOther, and ReasonNote, the moderator's own words,
+// which RejectionReasonOther requires. Notice, when set, names who the author
+// is told decided; it is worded here, addressed by the repository and stored
+// with the decision.
+type ModerateCommentRequest struct {
+	Moderator  *user.User
+	Notice     *notification.Notification
+	Decision   string
+	Reason     string `json:"reason"`
+	ReasonNote string `json:"reasonNote"`
+	CommentID  int    `json:"commentId"`
+}
+
+type ModerateCommentRequestHandler interface {
+	Handle(ctx context.Context, req ModerateCommentRequest) (*comment.Comment, error)
+}
+
+type moderateCommentRequestHandler struct {
+	repo  comment.Repository
+	audit audit.Repository
+}
+
+func NewModerateCommentHandler(repo comment.Repository, auditRepo audit.Repository) ModerateCommentRequestHandler {
+	return &moderateCommentRequestHandler{
+		repo:  repo,
+		audit: auditRepo,
+	}
+}
+
+func (h *moderateCommentRequestHandler) Handle(ctx context.Context, req ModerateCommentRequest) (*comment.Comment, error) {
+	if !req.Moderator.IsModerator() {
+		return nil, ErrNotModerator
+	}
+	if req.Decision != comment.StatusApproved && req.Decision != comment.StatusRejected {
+		return nil, ErrInvalidDecision
+	}
+
+	err := checkModeratorScope(ctx, h.repo, req.Moderator, []int{req.CommentID})
+	if err != nil {
+		return nil, err
+	}
+
+	var reason string
+	if req.Decision == comment.StatusRejected {
+		var err error
+		reason, err = RejectionReasonText(req.Reason, req.ReasonNote)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Notice != nil {
+		ComposeModerationNotice(req.Notice, req.Decision, reason)
+	}
+
+	err = h.repo.SetCommentStatus(ctx, req.CommentID, req.Decision, req.Moderator.ID, reason, req.Notice)
+	if err != nil {
+		return nil, err
+	}
+
+	moderated, err := h.repo.GetCommentByID(ctx, req.CommentID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.audit.LogAudit(ctx, moderationEntry(req.Moderator, moderated))
+	if err != nil {
+		return nil, err
+	}
+
+	return moderated, nil
+}
+
+// checkModeratorScope fails with ErrOutsideModeratorScope when any of
+// commentIDs sits outside the categories the moderator is assigned to.
+// Admins moderate everywhere.
+func checkModeratorScope(ctx context.Context, repo comment.Repository, moderator *user.User, commentIDs []int) error {
+	if moderator.IsAdmin() {
+		return nil
+	}
+
+	outside, err := repo.CommentsOutsideModeration(ctx, moderator.ID, commentIDs)
+	if err != nil {
+		return err
+	}
+	if len(outside) > 0 {
+		return fmt.Errorf("comment %d: %w", outside[0], ErrOutsideModeratorScope)
+	}
+	return nil
+}
+
+// RejectionReasonText turns a reason code and the moderator's note into the
+// explanation shown to the author. A stock reason keeps any note after it;
+// comment.RejectionReasonOther needs the note. No code and no note gives no
+// reason.
+func RejectionReasonText(code, note string) (string, error) {
+	note = strings.TrimSpace(note)
+	if len(note) > comment.MaxRejectionNoteLength {
+		return "", ErrInvalidRejectionReason
+	}
+
+	switch code {
+	case "":
+		if note != "" {
+			return "", ErrInvalidRejectionReason
+		}
+		return "", nil
+	case comment.RejectionReasonOther:
+		if note == "" {
+			return "", ErrInvalidRejectionReason
+		}
+		return note, nil
+	}
+
+	for _, stock := range comment.RejectionReasons {
+		if stock.Code != code {
+			continue
+		}
+		if note == "" {
+			return stock.Text, nil
+		}
+		return stock.Text + ": " + note, nil
+	}
+
+	return "", ErrInvalidRejectionReason
+}
+
+// ComposeModerationNotice words the notice telling an author what became of
+// their comment and, for a rejection with a reason, why. The notice's
+// ActorName says who decided.
+func ComposeModerationNotice(notice *notification.Notification, status, reason string) {
+	notice.Type = notification.NotificationTypeModeration
+	notice.RelatedType = "topic"
+	notice.Title = "Comment " + status
+	notice.Message = fmt.Sprintf("Your comment was %s by %s", status, notice.ActorName)
+	if status == comment.StatusRejected && reason != "" {
+		notice.Message += ". Reason: " + reason
+	}
+}
+
+// moderationEntry describes the decision a moderator just made on a comment
+// for the audit log.
+func moderationEntry(moderator *user.User, moderated *comment.Comment) *audit.Entry {
+	action := audit.ActionApproveComment
+	if moderated.Status == comment.StatusRejected {
+		action = audit.ActionRejectComment
+	}
+
+	return &audit.Entry{
+		AdminID:    moderator.ID,
+		Action:     action,
+		TargetType: audit.TargetComment,
+		TargetID:   strconv.Itoa(moderated.ID),
+		Details:    fmt.Sprintf("topic %d", moderated.TopicID),
+	}
+}
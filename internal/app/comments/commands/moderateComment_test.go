@@ -0,0 +1,319 @@
+package commentcommands
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/arnald/forum/internal/domain/audit"
+	"github.com/arnald/forum/internal/domain/comment"
+	"github.com/arnald/forum/internal/domain/notification"
+	"github.com/arnald/forum/internal/domain/user"
+)
+
+// stubCommentRepo holds a single comment, within the moderator's categories
+// unless outOfScope is set; the other methods are unused here.
+type stubCommentRepo struct {
+	comment.Repository
+	stored     *comment.Comment
+	notice     *notification.Notification
+	outOfScope bool
+}
+
+func (s *stubCommentRepo) CommentsOutsideModeration(_ context.Context, _ string, ids []int) ([]int, error) {
+	if s.outOfScope {
+		return ids, nil
+	}
+	return []int{}, nil
+}
+
+func (s *stubCommentRepo) SetCommentStatus(_ context.Context, _ int, status, _, reason string, notice *notification.Notification) error {
+	s.stored.Status = status
+	s.stored.RejectionReason = reason
+	s.notice = notice
+	return nil
+}
+
+func (s *stubCommentRepo) GetCommentByID(_ context.Context, _ int) (*comment.Comment, error) {
+	return s.stored, nil
+}
+
+// recordingAuditRepo keeps every entry it is asked to log.
+type recordingAuditRepo struct {
+	audit.Repository
+	entries []audit.Entry
+}
+
+func (r *recordingAuditRepo) LogAudit(_ context.Context, entry *audit.Entry) error {
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+func TestModerateCommentHandler_Audit(t *testing.T) {
+	t.Run("group: moderation audit", func(t *testing.T) {
+		testCases := newModerateAuditTestCases()
+		for _, tt := range testCases {
+			t.Run(tt.name, runModerateAuditTest(tt))
+		}
+	})
+}
+
+type moderateAuditTestCase struct {
+	wantError  error
+	moderator  *user.User
+	name       string
+	decision   string
+	wantAction string
+}
+
+func newModerateAuditTestCases() []moderateAuditTestCase {
+	return []moderateAuditTestCase{
+		{
+			name:       "approval is logged",
+			moderator:  &user.User{ID: "mod", Role: user.RoleModerator},
+			decision:   comment.StatusApproved,
+			wantAction: audit.ActionApproveComment,
+		},
+		{
+			name:       "rejection is logged",
+			moderator:  &user.User{ID: "mod", Role: user.RoleModerator},
+			decision:   comment.StatusRejected,
+			wantAction: audit.ActionRejectComment,
+		},
+		{
+			name:      "refused decisions leave no trail",
+			moderator: &user.User{ID: "someone", Role: user.RoleUser},
+			decision:  comment.StatusApproved,
+			wantError: ErrNotModerator,
+		},
+	}
+}
+
+func runModerateAuditTest(tt moderateAuditTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		comments := &stubCommentRepo{stored: &comment.Comment{ID: 7, TopicID: 3, Status: comment.StatusPending}}
+		auditLog := &recordingAuditRepo{}
+
+		_, err := NewModerateCommentHandler(comments, auditLog).Handle(context.Background(), ModerateCommentRequest{
+			Moderator: tt.moderator,
+			Decision:  tt.decision,
+			CommentID: 7,
+		})
+		if !errors.Is(err, tt.wantError) {
+			t.Fatalf("Handle() error = %v, want %v", err, tt.wantError)
+		}
+
+		if tt.wantError != nil {
+			if len(auditLog.entries) != 0 {
+				t.Errorf("Handle() logged %+v, want nothing", auditLog.entries)
+			}
+			return
+		}
+
+		if len(auditLog.entries) != 1 {
+			t.Fatalf("Handle() logged %d entries, want 1", len(auditLog.entries))
+		}
+		want := audit.Entry{
+			AdminID:    "mod",
+			Action:     tt.wantAction,
+			TargetType: audit.TargetComment,
+			TargetID:   "7",
+			Details:    "topic 3",
+		}
+		if got := auditLog.entries[0]; got != want {
+			t.Errorf("Handle() logged %+v, want %+v", got, want)
+		}
+	}
+}
+
+func TestModerateCommentHandler_Scope(t *testing.T) {
+	testCases := []struct {
+		wantError  error
+		moderator  *user.User
+		name       string
+		wantStatus string
+		outOfScope bool
+	}{
+		{
+			name:       "moderator assigned to the category",
+			moderator:  &user.User{ID: "mod", Role: user.RoleModerator},
+			wantStatus: comment.StatusApproved,
+		},
+		{
+			name:       "moderator outside the category",
+			moderator:  &user.User{ID: "mod", Role: user.RoleModerator},
+			outOfScope: true,
+			wantError:  ErrOutsideModeratorScope,
+			wantStatus: comment.StatusPending,
+		},
+		{
+			name:       "admins moderate every category",
+			moderator:  &user.User{ID: "admin", Role: user.RoleAdmin},
+			outOfScope: true,
+			wantStatus: comment.StatusApproved,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			comments := &stubCommentRepo{
+				stored:     &comment.Comment{ID: 7, TopicID: 3, Status: comment.StatusPending},
+				outOfScope: tt.outOfScope,
+			}
+
+			_, err := NewModerateCommentHandler(comments, &recordingAuditRepo{}).Handle(context.Background(), ModerateCommentRequest{
+				Moderator: tt.moderator,
+				Decision:  comment.StatusApproved,
+				CommentID: 7,
+			})
+			if !errors.Is(err, tt.wantError) {
+				t.Fatalf("Handle() error = %v, want %v", err, tt.wantError)
+			}
+			if comments.stored.Status != tt.wantStatus {
+				t.Errorf("comment Status = %q, want %q", comments.stored.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestModerateCommentHandler_RejectionReason(t *testing.T) {
+	testCases := []struct {
+		wantError  error
+		name       string
+		decision   string
+		reason     string
+		note       string
+		wantReason string
+	}{
+		{
+			name:       "stock reason",
+			decision:   comment.StatusRejected,
+			reason:     "spam",
+			wantReason: "Spam or advertising",
+		},
+		{
+			name:       "stock reason with a note",
+			decision:   comment.StatusRejected,
+			reason:     "off_topic",
+			note:       "  try the General category  ",
+			wantReason: "Off-topic for this discussion: try the General category",
+		},
+		{
+			name:       "free text",
+			decision:   comment.StatusRejected,
+			reason:     comment.RejectionReasonOther,
+			note:       "Please remove the personal details",
+			wantReason: "Please remove the personal details",
+		},
+		{
+			name:     "rejection without a reason",
+			decision: comment.StatusRejected,
+		},
+		{
+			name:      "free text left empty",
+			decision:  comment.StatusRejected,
+			reason:    comment.RejectionReasonOther,
+			wantError: ErrInvalidRejectionReason,
+		},
+		{
+			name:      "unknown reason",
+			decision:  comment.StatusRejected,
+			reason:    "because",
+			wantError: ErrInvalidRejectionReason,
+		},
+		{
+			name:     "approval ignores the reason",
+			decision: comment.StatusApproved,
+			reason:   "spam",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			comments := &stubCommentRepo{stored: &comment.Comment{ID: 7, TopicID: 3, Status: comment.StatusPending}}
+
+			got, err := NewModerateCommentHandler(comments, &recordingAuditRepo{}).Handle(context.Background(), ModerateCommentRequest{
+				Moderator:  &user.User{ID: "mod", Role: user.RoleModerator},
+				Decision:   tt.decision,
+				Reason:     tt.reason,
+				ReasonNote: tt.note,
+				CommentID:  7,
+			})
+			if !errors.Is(err, tt.wantError) {
+				t.Fatalf("Handle() error = %v, want %v", err, tt.wantError)
+			}
+			if tt.wantError != nil {
+				if comments.stored.Status != comment.StatusPending {
+					t.Errorf("Status = %q, want the comment left pending", comments.stored.Status)
+				}
+				return
+			}
+
+			if got.RejectionReason != tt.wantReason {
+				t.Errorf("RejectionReason = %q, want %q", got.RejectionReason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestModerateCommentHandler_Notice(t *testing.T) {
+	comments := &stubCommentRepo{stored: &comment.Comment{ID: 7, TopicID: 3, Status: comment.StatusPending}}
+
+	notice := &notification.Notification{ActorID: "mod", ActorName: "mod"}
+	_, err := NewModerateCommentHandler(comments, &recordingAuditRepo{}).Handle(context.Background(), ModerateCommentRequest{
+		Moderator: &user.User{ID: "mod", Role: user.RoleModerator},
+		Notice:    notice,
+		Decision:  comment.StatusRejected,
+		Reason:    "spam",
+		CommentID: 7,
+	})
+	if err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	if comments.notice != notice {
+		t.Fatal("Handle() did not pass the notice to the repository")
+	}
+	if notice.Type != notification.NotificationTypeModeration || notice.Title != "Comment rejected" {
+		t.Errorf("notice = %+v, want a moderation notice titled %q", notice, "Comment rejected")
+	}
+	if want := "Your comment was rejected by mod. Reason: Spam or advertising"; notice.Message != want {
+		t.Errorf("notice message = %q, want %q", notice.Message, want)
+	}
+}
+
+func TestComposeModerationNotice(t *testing.T) {
+	testCases := []struct {
+		name   string
+		status string
+		reason string
+		want   string
+	}{
+		{
+			name:   "approval",
+			status: comment.StatusApproved,
+			want:   "Your comment was approved by mod",
+		},
+		{
+			name:   "rejection with a reason",
+			status: comment.StatusRejected,
+			reason: "Spam or advertising",
+			want:   "Your comment was rejected by mod. Reason: Spam or advertising",
+		},
+		{
+			name:   "rejection without a reason",
+			status: comment.StatusRejected,
+			want:   "Your comment was rejected by mod",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			notice := &notification.Notification{ActorName: "mod"}
+			ComposeModerationNotice(notice, tt.status, tt.reason)
+			if notice.Message != tt.want {
+				t.Errorf("ComposeModerationNotice() message = %q, want %q", notice.Message, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package commentqueries
+
+import (
+	"sort"
+
+	"github.com/arnald/forum/internal/domain/comment"
+)
+
+const (
+	OrderTop    = "top"
+	OrderNewest = "newest"
+	OrderOldest = "oldest"
+)
+
+// BuildCommentTree nests replies under their parents and orders every sibling
+// group independently, so sorting never moves a reply away from its parent.
+// Comments whose parent is missing from the list are treated as top level.
+func BuildCommentTree(comments []comment.Comment, order string) []comment.Comment {
+	known := make(map[int]bool, len(comments))
+	for _, c := range comments {
+		known[c.ID] = true
+	}
+
+	children := make(map[int][]comment.Comment)
+	roots := make([]comment.Comment, 0)
+	for _, c := range comments {
+		if c.ParentID != nil && known[*c.ParentID] && *c.ParentID != c.ID {
+			children[*c.ParentID] = append(children[*c.ParentID], c)
+			continue
+		}
+		roots = append(roots, c)
+	}
+
+	return attachReplies(roots, children, order)
+}
+
+func attachReplies(siblings []comment.Comment, children map[int][]comment.Comment, order string) []comment.Comment {
+	sortSiblings(siblings, order)
+
+	for i := range siblings {
+		replies, ok := children[siblings[i].ID]
+		if !ok {
+			continue
+		}
+		// Drop the entry before descending so a malformed cycle cannot recurse forever.
+		delete(children, siblings[i].ID)
+		siblings[i].Replies = attachReplies(replies, children, order)
+	}
+
+	return siblings
+}
+
+// sortSiblings relies on ids growing with insertion time, which is more
+// precise than the display-formatted CreatedAt.
+func sortSiblings(siblings []comment.Comment, order string) {
+	sort.SliceStable(siblings, func(i, j int) bool {
+		switch order {
+		case OrderTop:
+			if siblings[i].VoteScore != siblings[j].VoteScore {
+				return siblings[i].VoteScore > siblings[j].VoteScore
+			}
+			return siblings[i].ID < siblings[j].ID
+		case OrderNewest:
+			return siblings[i].ID > siblings[j].ID
+		default:
+			return siblings[i].ID < siblings[j].ID
+		}
+	})
+}
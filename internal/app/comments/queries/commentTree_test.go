@@ -0,0 +1,107 @@
+package commentqueries
+
+import (
+	"testing"
+
+	"github.com/arnald/forum/internal/domain/comment"
+)
+
+func TestBuildCommentTree(t *testing.T) {
+	t.Run("group: comment tree ordering", func(t *testing.T) {
+		testCases := newCommentTreeTestCases()
+		for _, tt := range testCases {
+			t.Run(tt.name, runCommentTreeTest(tt))
+		}
+	})
+}
+
+type commentTreeTestCase struct {
+	want  map[int][]int
+	name  string
+	order string
+}
+
+// threadFixture builds the following thread (id:score):
+//
+//	1:0
+//	├── 3:5
+//	│   └── 6:0
+//	└── 4:-1
+//	2:9
+//	└── 5:2
+//	7:1 (parent 99 is missing, so it is promoted to the top level)
+func threadFixture() []comment.Comment {
+	parent := func(id int) *int { return &id }
+
+	return []comment.Comment{
+		{ID: 1, VoteScore: 0},
+		{ID: 2, VoteScore: 9},
+		{ID: 3, VoteScore: 5, ParentID: parent(1)},
+		{ID: 4, VoteScore: -1, ParentID: parent(1)},
+		{ID: 5, VoteScore: 2, ParentID: parent(2)},
+		{ID: 6, VoteScore: 0, ParentID: parent(3)},
+		{ID: 7, VoteScore: 1, ParentID: parent(99)},
+	}
+}
+
+// The want map lists, for each parent id (0 for the top level), the ids of its
+// replies in the expected order.
+func newCommentTreeTestCases() []commentTreeTestCase {
+	return []commentTreeTestCase{
+		{
+			name:  "oldest orders each level by creation",
+			order: OrderOldest,
+			want:  map[int][]int{0: {1, 2, 7}, 1: {3, 4}, 2: {5}, 3: {6}},
+		},
+		{
+			name:  "newest orders each level by creation descending",
+			order: OrderNewest,
+			want:  map[int][]int{0: {7, 2, 1}, 1: {4, 3}, 2: {5}, 3: {6}},
+		},
+		{
+			name:  "top orders each level by score",
+			order: OrderTop,
+			want:  map[int][]int{0: {2, 7, 1}, 1: {3, 4}, 2: {5}, 3: {6}},
+		},
+		{
+			name:  "unknown order falls back to oldest",
+			order: "",
+			want:  map[int][]int{0: {1, 2, 7}, 1: {3, 4}, 2: {5}, 3: {6}},
+		},
+	}
+}
+
+func runCommentTreeTest(tt commentTreeTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		tree := BuildCommentTree(threadFixture(), tt.order)
+
+		got := make(map[int][]int)
+		collectLevels(0, tree, got)
+
+		if len(got) != len(tt.want) {
+			t.Fatalf("BuildCommentTree() levels = %v, want %v", got, tt.want)
+		}
+		for parentID, wantIDs := range tt.want {
+			gotIDs := got[parentID]
+			if len(gotIDs) != len(wantIDs) {
+				t.Errorf("replies of %d = %v, want %v", parentID, gotIDs, wantIDs)
+				continue
+			}
+			for i := range wantIDs {
+				if gotIDs[i] != wantIDs[i] {
+					t.Errorf("replies of %d = %v, want %v", parentID, gotIDs, wantIDs)
+					break
+				}
+			}
+		}
+	}
+}
+
+func collectLevels(parentID int, siblings []comment.Comment, levels map[int][]int) {
+	for _, c := range siblings {
+		levels[parentID] = append(levels[parentID], c.ID)
+		if len(c.Replies) > 0 {
+			collectLevels(c.ID, c.Replies, levels)
+		}
+	}
+}
@@ -7,6 +7,9 @@ import (
 )
 
 type GetCommentRequest struct {
+	// UserID is the viewer, if signed in; only the author sees a comment
+	// that is not approved yet.
+	UserID    *string
 	CommentID int `json:"commentId"`
 }
 
@@ -25,10 +28,14 @@ func NewGetCommentHandler(repo comment.Repository) GetCommentRequestHandler {
 }
 
 func (h *getCommentRequestHandler) Handle(ctx context.Context, req GetCommentRequest) (*comment.Comment, error) {
-	comment, err := h.repo.GetCommentByID(ctx, req.CommentID)
+	found, err := h.repo.GetCommentByID(ctx, req.CommentID)
 	if err != nil {
 		return nil, err
 	}
 
-	return comment, nil
+	if found.Status != comment.StatusApproved && (req.UserID == nil || *req.UserID != found.UserID) {
+		return nil, ErrCommentNotFound
+	}
+
+	return found, nil
 }
@@ -2,12 +2,19 @@ package commentqueries
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/arnald/forum/internal/domain/comment"
+	"github.com/arnald/forum/internal/domain/topic"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/topics"
 )
 
+// GetCommentsByTopicRequest lists a topic's comments. UserID is the viewer,
+// nil for guests; it decides whether a draft topic can be read.
 type GetCommentsByTopicRequest struct {
-	TopicID int `json:"topicId"`
+	UserID  *string `json:"-"`
+	Order   string  `json:"order"`
+	TopicID int     `json:"topicId"`
 }
 
 type GetCommentsByTopicRequestHandler interface {
@@ -15,20 +22,30 @@ type GetCommentsByTopicRequestHandler interface {
 }
 
 type getCommentsByTopicRequestHandler struct {
-	repo comment.Repository
+	repo      comment.Repository
+	topicRepo topic.Repository
 }
 
-func NewGetCommentsByTopicRequestHandler(repo comment.Repository) GetCommentsByTopicRequestHandler {
+func NewGetCommentsByTopicRequestHandler(repo comment.Repository, topicRepo topic.Repository) GetCommentsByTopicRequestHandler {
 	return &getCommentsByTopicRequestHandler{
-		repo: repo,
+		repo:      repo,
+		topicRepo: topicRepo,
 	}
 }
 
 func (h *getCommentsByTopicRequestHandler) Handle(ctx context.Context, req GetCommentsByTopicRequest) ([]comment.Comment, error) {
-	comments, err := h.repo.GetCommentsByTopicID(ctx, req.TopicID)
+	visible, err := h.topicRepo.IsTopicVisible(ctx, req.TopicID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, fmt.Errorf("topic with ID %d not found: %w", req.TopicID, topics.ErrTopicNotFound)
+	}
+
+	comments, err := h.repo.GetCommentsWithVotes(ctx, req.TopicID, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	return comments, nil
+	return BuildCommentTree(comments, req.Order), nil
 }
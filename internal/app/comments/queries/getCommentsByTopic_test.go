@@ -0,0 +1,58 @@
+package commentqueries
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/arnald/forum/internal/domain/comment"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/topics"
+	testhelpers "github.com/arnald/forum/internal/pkg/testing"
+)
+
+// stubCommentRepo returns a fixed thread; the other methods are unused here.
+type stubCommentRepo struct {
+	comment.Repository
+	thread []comment.Comment
+}
+
+func (s stubCommentRepo) GetCommentsWithVotes(_ context.Context, _ int, _ *string) ([]comment.Comment, error) {
+	return s.thread, nil
+}
+
+func TestGetCommentsByTopicHandler_TopicVisibility(t *testing.T) {
+	viewer := "author"
+	testCases := []struct {
+		wantErr   error
+		viewer    *string
+		name      string
+		wantCount int
+		visible   bool
+	}{
+		{name: "visible topic lists its comments", visible: true, wantCount: 1},
+		{name: "draft for its author", viewer: &viewer, visible: true, wantCount: 1},
+		{name: "hidden topic is not found", viewer: &viewer, wantErr: topics.ErrTopicNotFound},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			topicRepo := &testhelpers.MockRepository{
+				IsTopicVisibleFunc: func(_ context.Context, _ int, userID *string) (bool, error) {
+					if userID != tt.viewer {
+						t.Errorf("IsTopicVisible() userID = %v, want %v", userID, tt.viewer)
+					}
+					return tt.visible, nil
+				},
+			}
+			handler := NewGetCommentsByTopicRequestHandler(stubCommentRepo{thread: []comment.Comment{{ID: 1}}}, topicRepo)
+
+			got, err := handler.Handle(context.Background(), GetCommentsByTopicRequest{UserID: tt.viewer, TopicID: 3})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Handle() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(got) != tt.wantCount {
+				t.Errorf("Handle() = %d comments, want %d", len(got), tt.wantCount)
+			}
+		})
+	}
+}
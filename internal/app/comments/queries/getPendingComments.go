@@ -0,0 +1,31 @@
+package commentqueries
+
+import (
+	"context"
+
+	"github.com/arnald/forum/internal/domain/comment"
+	"github.com/arnald/forum/internal/domain/user"
+)
+
+type GetPendingCommentsRequestHandler interface {
+	// Handle lists the pending comments the moderator may settle: all of
+	// them for an admin, otherwise those in the moderator's categories.
+	Handle(ctx context.Context, moderator *user.User) ([]comment.Comment, error)
+}
+
+type getPendingCommentsRequestHandler struct {
+	repo comment.Repository
+}
+
+func NewGetPendingCommentsHandler(repo comment.Repository) GetPendingCommentsRequestHandler {
+	return &getPendingCommentsRequestHandler{
+		repo: repo,
+	}
+}
+
+func (h *getPendingCommentsRequestHandler) Handle(ctx context.Context, moderator *user.User) ([]comment.Comment, error) {
+	if moderator.IsAdmin() {
+		return h.repo.GetPendingComments(ctx, "")
+	}
+	return h.repo.GetPendingComments(ctx, moderator.ID)
+}
@@ -0,0 +1,203 @@
+package importcommands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/arnald/forum/internal/domain/dataimport"
+	"github.com/arnald/forum/internal/pkg/uuid"
+)
+
+// acceptedTimeLayouts are the created_at formats an export may use.
+var acceptedTimeLayouts = []string{time.RFC3339, time.DateTime, time.DateOnly}
+
+// ImportContentRequest imports a forum export on behalf of ImportedBy, who is
+// recorded as the creator of the imported categories.
+type ImportContentRequest struct {
+	Document   *dataimport.Document
+	ImportedBy string
+}
+
+type ImportContentRequestHandler interface {
+	Handle(ctx context.Context, req ImportContentRequest) (*dataimport.Summary, error)
+}
+
+type importContentRequestHandler struct {
+	repo         dataimport.Repository
+	uuidProvider uuid.Provider
+}
+
+func NewImportContentHandler(repo dataimport.Repository, uuidProvider uuid.Provider) ImportContentRequestHandler {
+	return &importContentRequestHandler{
+		repo:         repo,
+		uuidProvider: uuidProvider,
+	}
+}
+
+// Handle checks the whole document before writing anything: required
+// fields, duplicate source ids, dates and every reference between entities.
+// Only a document that passes goes to the repository. The error return is
+// kept for storage failures; rejected entities are reported in the summary.
+func (h *importContentRequestHandler) Handle(ctx context.Context, req ImportContentRequest) (*dataimport.Summary, error) {
+	summary := &dataimport.Summary{}
+	doc := req.Document
+
+	users := validateUsers(doc.Users, &summary.Users)
+	categories := validateCategories(doc.Categories, &summary.Categories)
+	topics := validateTopics(doc.Topics, users, categories, &summary.Topics)
+	validateComments(doc.Comments, users, topics, &summary.Comments)
+
+	if summary.HasFailures() {
+		return summary, nil
+	}
+
+	for i := range doc.Users {
+		doc.Users[i].NewID = h.uuidProvider.NewUUID()
+	}
+
+	err := h.repo.Import(ctx, doc, req.ImportedBy, summary)
+	if err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+func validateUsers(users []dataimport.User, summary *dataimport.EntitySummary) map[string]bool {
+	ids := make(map[string]bool, len(users))
+	usernames := make(map[string]bool, len(users))
+	emails := make(map[string]bool, len(users))
+
+	for i := range users {
+		u := &users[i]
+		reason := checkSourceID(u.ID, ids)
+		switch {
+		case reason != "":
+		case strings.TrimSpace(u.Username) == "":
+			reason = "username is required"
+		case !strings.Contains(u.Email, "@"):
+			reason = "email is invalid"
+		case usernames[strings.ToLower(u.Username)]:
+			reason = fmt.Sprintf("username %q appears twice", u.Username)
+		case emails[strings.ToLower(u.Email)]:
+			reason = fmt.Sprintf("email %q appears twice", u.Email)
+		default:
+			reason = normalizeCreatedAt(&u.CreatedAt)
+		}
+
+		ids[u.ID] = true
+		usernames[strings.ToLower(u.Username)] = true
+		emails[strings.ToLower(u.Email)] = true
+		if reason != "" {
+			summary.Fail(u.ID, reason)
+		}
+	}
+	return ids
+}
+
+func validateCategories(categories []dataimport.Category, summary *dataimport.EntitySummary) map[string]bool {
+	ids := make(map[string]bool, len(categories))
+	for i := range categories {
+		c := &categories[i]
+		reason := checkSourceID(c.ID, ids)
+		switch {
+		case reason != "":
+		case strings.TrimSpace(c.Name) == "":
+			reason = "name is required"
+		default:
+			reason = normalizeCreatedAt(&c.CreatedAt)
+		}
+
+		ids[c.ID] = true
+		if reason != "" {
+			summary.Fail(c.ID, reason)
+		}
+	}
+	return ids
+}
+
+func validateTopics(topics []dataimport.Topic, users, categories map[string]bool, summary *dataimport.EntitySummary) map[string]bool {
+	ids := make(map[string]bool, len(topics))
+	for i := range topics {
+		t := &topics[i]
+		reason := checkSourceID(t.ID, ids)
+		switch {
+		case reason != "":
+		case !users[t.UserID]:
+			reason = fmt.Sprintf("author %q is not in the document", t.UserID)
+		case strings.TrimSpace(t.Title) == "" || strings.TrimSpace(t.Content) == "":
+			reason = "title and content are required"
+		default:
+			reason = normalizeCreatedAt(&t.CreatedAt)
+		}
+		for _, categoryID := range t.CategoryIDs {
+			if reason == "" && !categories[categoryID] {
+				reason = fmt.Sprintf("category %q is not in the document", categoryID)
+			}
+		}
+
+		ids[t.ID] = true
+		if reason != "" {
+			summary.Fail(t.ID, reason)
+		}
+	}
+	return ids
+}
+
+func validateComments(comments []dataimport.Comment, users, topics map[string]bool, summary *dataimport.EntitySummary) {
+	ids := make(map[string]bool, len(comments))
+	topicOf := make(map[string]string, len(comments))
+	for i := range comments {
+		c := &comments[i]
+		reason := checkSourceID(c.ID, ids)
+		switch {
+		case reason != "":
+		case !users[c.UserID]:
+			reason = fmt.Sprintf("author %q is not in the document", c.UserID)
+		case !topics[c.TopicID]:
+			reason = fmt.Sprintf("topic %q is not in the document", c.TopicID)
+		case c.ParentID != "" && !ids[c.ParentID]:
+			reason = fmt.Sprintf("parent comment %q is not listed before it", c.ParentID)
+		case c.ParentID != "" && topicOf[c.ParentID] != c.TopicID:
+			reason = fmt.Sprintf("parent comment %q is on another topic", c.ParentID)
+		case strings.TrimSpace(c.Content) == "":
+			reason = "content is required"
+		default:
+			reason = normalizeCreatedAt(&c.CreatedAt)
+		}
+
+		ids[c.ID] = true
+		topicOf[c.ID] = c.TopicID
+		if reason != "" {
+			summary.Fail(c.ID, reason)
+		}
+	}
+}
+
+func checkSourceID(id string, seen map[string]bool) string {
+	if id == "" {
+		return "id is required"
+	}
+	if seen[id] {
+		return fmt.Sprintf("id %q appears twice", id)
+	}
+	return ""
+}
+
+// normalizeCreatedAt rewrites a given created_at in the stored layout, in
+// UTC. An empty one is left for the database to fill in.
+func normalizeCreatedAt(createdAt *string) string {
+	if *createdAt == "" {
+		return ""
+	}
+	for _, layout := range acceptedTimeLayouts {
+		parsed, err := time.Parse(layout, *createdAt)
+		if err == nil {
+			*createdAt = parsed.UTC().Format(time.DateTime)
+			return ""
+		}
+	}
+	return fmt.Sprintf("createdAt %q is not a valid date", *createdAt)
+}
@@ -0,0 +1,133 @@
+package importcommands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/arnald/forum/internal/domain/dataimport"
+)
+
+// stubImportRepo records whether the import reached storage.
+type stubImportRepo struct {
+	called *bool
+}
+
+func (s stubImportRepo) Import(_ context.Context, _ *dataimport.Document, _ string, summary *dataimport.Summary) error {
+	*s.called = true
+	summary.Committed = true
+	return nil
+}
+
+type stubUUIDProvider struct{}
+
+func (stubUUIDProvider) NewUUID() string { return "new-id" }
+
+func TestImportContentHandler_Handle(t *testing.T) {
+	t.Run("group: document validation", func(t *testing.T) {
+		testCases := newImportContentTestCases()
+		for _, tt := range testCases {
+			t.Run(tt.name, runImportContentTest(tt))
+		}
+	})
+}
+
+type importContentTestCase struct {
+	doc          func() *dataimport.Document
+	name         string
+	wantFailures int
+	wantStored   bool
+}
+
+func validDocument() *dataimport.Document {
+	return &dataimport.Document{
+		Users:      []dataimport.User{{ID: "u1", Username: "alice", Email: "alice@example.com", CreatedAt: "2019-03-01T10:00:00+02:00"}},
+		Categories: []dataimport.Category{{ID: "c1", Name: "General"}},
+		Topics:     []dataimport.Topic{{ID: "t1", UserID: "u1", Title: "Hello", Content: "World", CategoryIDs: []string{"c1"}}},
+		Comments: []dataimport.Comment{
+			{ID: "m1", TopicID: "t1", UserID: "u1", Content: "First"},
+			{ID: "m2", TopicID: "t1", UserID: "u1", ParentID: "m1", Content: "Reply"},
+		},
+	}
+}
+
+func newImportContentTestCases() []importContentTestCase {
+	return []importContentTestCase{
+		{
+			name:       "valid document is stored",
+			doc:        validDocument,
+			wantStored: true,
+		},
+		{
+			name: "topic by an unknown author",
+			doc: func() *dataimport.Document {
+				doc := validDocument()
+				doc.Topics[0].UserID = "ghost"
+				return doc
+			},
+			wantFailures: 1,
+		},
+		{
+			name: "topic in an unknown category",
+			doc: func() *dataimport.Document {
+				doc := validDocument()
+				doc.Topics[0].CategoryIDs = []string{"c9"}
+				return doc
+			},
+			wantFailures: 1,
+		},
+		{
+			name: "reply listed before its parent",
+			doc: func() *dataimport.Document {
+				doc := validDocument()
+				doc.Comments[0], doc.Comments[1] = doc.Comments[1], doc.Comments[0]
+				return doc
+			},
+			wantFailures: 1,
+		},
+		{
+			name: "duplicate source id",
+			doc: func() *dataimport.Document {
+				doc := validDocument()
+				doc.Categories = append(doc.Categories, dataimport.Category{ID: "c1", Name: "Again"})
+				return doc
+			},
+			wantFailures: 1,
+		},
+		{
+			name: "unparseable date",
+			doc: func() *dataimport.Document {
+				doc := validDocument()
+				doc.Comments[0].CreatedAt = "last tuesday"
+				return doc
+			},
+			wantFailures: 1,
+		},
+	}
+}
+
+func runImportContentTest(tt importContentTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		called := false
+		handler := NewImportContentHandler(stubImportRepo{called: &called}, stubUUIDProvider{})
+		doc := tt.doc()
+
+		summary, err := handler.Handle(context.Background(), ImportContentRequest{
+			Document:   doc,
+			ImportedBy: "admin",
+		})
+		if err != nil {
+			t.Fatalf("Handle() error = %v", err)
+		}
+
+		failures := summary.Users.Failed + summary.Categories.Failed + summary.Topics.Failed + summary.Comments.Failed
+		if failures != tt.wantFailures {
+			t.Errorf("Handle() failures = %d, want %d (%+v)", failures, tt.wantFailures, summary)
+		}
+		if called != tt.wantStored {
+			t.Errorf("repository called = %v, want %v", called, tt.wantStored)
+		}
+		if tt.wantStored && doc.Users[0].CreatedAt != "2019-03-01 08:00:00" {
+			t.Errorf("normalized createdAt = %q, want %q", doc.Users[0].CreatedAt, "2019-03-01 08:00:00")
+		}
+	}
+}
@@ -8,19 +8,31 @@ import (
 	"github.com/arnald/forum/internal/domain/oauth"
 	"github.com/arnald/forum/internal/domain/user"
 	oauthrepo "github.com/arnald/forum/internal/infra/storage/sqlite/oauth"
+	"github.com/arnald/forum/internal/pkg/helpers"
 	oauthpkg "github.com/arnald/forum/internal/pkg/oAuth"
 	"github.com/arnald/forum/internal/pkg/uuid"
 )
 
+// ErrLinkNeedsConfirmation is returned when a provider login matches the email
+// of a local account that has not proved it owns that email through a
+// verification link. Linking it automatically would hand the account to
+// whoever controls the provider identity, so the owner has to sign in and
+// verify the email first.
+var ErrLinkNeedsConfirmation = errors.New("account with this email must be verified before linking")
+
 type OAuthService struct {
 	oauthRepo    oauth.Repository
 	uuidProvider uuid.Provider
+	// stripEmailAliases normalizes provider emails the way registration and
+	// login do, so an aliased address finds the account it belongs to.
+	stripEmailAliases bool
 }
 
-func NewOAuthService(oauthRepo oauth.Repository, uuidProvider uuid.Provider) *OAuthService {
+func NewOAuthService(oauthRepo oauth.Repository, uuidProvider uuid.Provider, stripEmailAliases bool) *OAuthService {
 	return &OAuthService{
-		oauthRepo:    oauthRepo,
-		uuidProvider: uuidProvider,
+		oauthRepo:         oauthRepo,
+		uuidProvider:      uuidProvider,
+		stripEmailAliases: stripEmailAliases,
 	}
 }
 
@@ -52,12 +64,30 @@ func (s *OAuthService) Login(ctx context.Context, code string, provider oauthpkg
 		UserID:     s.uuidProvider.NewUUID(),
 		ProviderID: providerID,
 		Provider:   providerName,
-		Email:      providerUserInfo.Email,
+		Email:      helpers.NormalizeEmail(providerUserInfo.Email, s.stripEmailAliases),
 		Username:   providerUserInfo.Username,
 		AvatarURL:  providerUserInfo.AvatarURL,
 		Name:       providerUserInfo.Name,
 	}
 
+	emailUser, err := s.oauthRepo.GetUserByEmail(ctx, oauthUser.Email)
+	if err != nil && !errors.Is(err, oauthrepo.ErrUserNotFound) {
+		return nil, fmt.Errorf("failed to check existing email: %w", err)
+	}
+
+	if emailUser != nil {
+		if !emailUser.EmailVerified {
+			return nil, ErrLinkNeedsConfirmation
+		}
+
+		oauthUser.UserID = emailUser.ID
+		err = s.oauthRepo.LinkOAuthProvider(ctx, emailUser.ID, oauthUser)
+		if err != nil {
+			return nil, fmt.Errorf("failed to link provider: %w", err)
+		}
+		return emailUser, nil
+	}
+
 	newUser, err := s.oauthRepo.CreateOAuthUser(ctx, oauthUser)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
@@ -0,0 +1,134 @@
+package oauthservice
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/arnald/forum/internal/domain/oauth"
+	"github.com/arnald/forum/internal/domain/user"
+	oauthrepo "github.com/arnald/forum/internal/infra/storage/sqlite/oauth"
+	oauthpkg "github.com/arnald/forum/internal/pkg/oAuth"
+	testhelpers "github.com/arnald/forum/internal/pkg/testing"
+)
+
+type fakeProvider struct {
+	info *oauthpkg.ProviderUserInfo
+}
+
+func (p fakeProvider) Name() string { return "google" }
+func (p fakeProvider) GetAuthURL(state string) string {
+	return "https://example.com/auth?state=" + state
+}
+
+func (p fakeProvider) ExchangeCode(_ context.Context, _ string) (string, error) {
+	return "access-token", nil
+}
+
+func (p fakeProvider) GetUserInfo(_ context.Context, _ string) (*oauthpkg.ProviderUserInfo, error) {
+	return p.info, nil
+}
+
+// fakeRepo knows a single local account by email and records what Login
+// asks it to link or create.
+type fakeRepo struct {
+	byEmail *user.User
+	linked  *oauth.User
+	created *oauth.User
+}
+
+func (r *fakeRepo) GetUserByProviderID(_ context.Context, _ oauth.Provider, _ string) (*user.User, error) {
+	return nil, oauthrepo.ErrUserNotFound
+}
+
+func (r *fakeRepo) GetUserByEmail(_ context.Context, email string) (*user.User, error) {
+	if r.byEmail == nil || r.byEmail.Email != email {
+		return nil, oauthrepo.ErrUserNotFound
+	}
+	return r.byEmail, nil
+}
+
+func (r *fakeRepo) CreateOAuthUser(_ context.Context, oauthUser *oauth.User) (*user.User, error) {
+	r.created = oauthUser
+	return &user.User{ID: oauthUser.UserID, Username: oauthUser.Username, Email: oauthUser.Email}, nil
+}
+
+func (r *fakeRepo) LinkOAuthProvider(_ context.Context, _ string, oauthUser *oauth.User) error {
+	r.linked = oauthUser
+	return nil
+}
+
+func (r *fakeRepo) GetOAuthProvider(_ context.Context, _ string, _ oauth.Provider) (*oauth.User, error) {
+	return nil, oauthrepo.ErrUserNotFound
+}
+
+func TestOAuthService_Login(t *testing.T) {
+	provider := fakeProvider{info: &oauthpkg.ProviderUserInfo{
+		ProviderID: "google-123",
+		Email:      "alice@example.com",
+		Username:   "alice_g",
+	}}
+	uuidProvider := &testhelpers.MockUUIDProvider{NewUUIDFunc: func() string { return "new-user" }}
+
+	t.Run("links the provider to a verified account with the same email", func(t *testing.T) {
+		repo := &fakeRepo{byEmail: &user.User{ID: "alice", Email: "alice@example.com", EmailVerified: true}}
+
+		got, err := NewOAuthService(repo, uuidProvider, false).Login(context.Background(), "code", provider)
+		if err != nil {
+			t.Fatalf("Login() error = %v", err)
+		}
+		if got.ID != "alice" {
+			t.Errorf("Login() user = %q, want the existing account", got.ID)
+		}
+		if repo.created != nil {
+			t.Error("Login() created a duplicate account")
+		}
+		if repo.linked == nil || repo.linked.UserID != "alice" || repo.linked.ProviderID != "google-123" {
+			t.Errorf("Login() linked = %+v, want google-123 linked to alice", repo.linked)
+		}
+	})
+
+	t.Run("does not link to an unverified account", func(t *testing.T) {
+		repo := &fakeRepo{byEmail: &user.User{ID: "alice", Email: "alice@example.com"}}
+
+		_, err := NewOAuthService(repo, uuidProvider, false).Login(context.Background(), "code", provider)
+		if !errors.Is(err, ErrLinkNeedsConfirmation) {
+			t.Fatalf("Login() error = %v, want %v", err, ErrLinkNeedsConfirmation)
+		}
+		if repo.linked != nil || repo.created != nil {
+			t.Errorf("Login() linked = %+v, created = %+v, want neither", repo.linked, repo.created)
+		}
+	})
+
+	t.Run("strips aliases from the provider email when configured", func(t *testing.T) {
+		aliased := fakeProvider{info: &oauthpkg.ProviderUserInfo{
+			ProviderID: "google-456",
+			Email:      "J.Doe+x@gmail.com",
+			Username:   "jdoe_g",
+		}}
+		repo := &fakeRepo{byEmail: &user.User{ID: "jdoe", Email: "jdoe@gmail.com", EmailVerified: true}}
+
+		got, err := NewOAuthService(repo, uuidProvider, true).Login(context.Background(), "code", aliased)
+		if err != nil {
+			t.Fatalf("Login() error = %v", err)
+		}
+		if got.ID != "jdoe" || repo.created != nil {
+			t.Errorf("Login() user = %q, created = %v, want the existing jdoe account", got.ID, repo.created != nil)
+		}
+		if repo.linked == nil || repo.linked.Email != "jdoe@gmail.com" {
+			t.Errorf("Login() linked = %+v, want the normalized email", repo.linked)
+		}
+	})
+
+	t.Run("creates an account when no email matches", func(t *testing.T) {
+		repo := &fakeRepo{}
+
+		got, err := NewOAuthService(repo, uuidProvider, false).Login(context.Background(), "code", provider)
+		if err != nil {
+			t.Fatalf("Login() error = %v", err)
+		}
+		if got.ID != "new-user" || repo.created == nil {
+			t.Errorf("Login() user = %q, created = %v, want a new account", got.ID, repo.created != nil)
+		}
+	})
+}
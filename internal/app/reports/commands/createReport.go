@@ -0,0 +1,55 @@
+package reportcommands
+
+import (
+	"context"
+
+	"github.com/arnald/forum/internal/domain/report"
+)
+
+// CreateReportRequest files a report against a topic or a comment; exactly
+// one of TopicID and CommentID must be set. CollapseThreshold is the
+// configured number of pending reports that collapses a comment.
+type CreateReportRequest struct {
+	ReporterID        string
+	Reason            string
+	Description       string
+	TopicID           int
+	CommentID         int
+	CollapseThreshold int
+}
+
+type CreateReportRequestHandler interface {
+	// Handle files the report and returns whether the reported comment is
+	// now collapsed; topic reports never collapse anything.
+	Handle(ctx context.Context, req CreateReportRequest) (bool, error)
+}
+
+type createReportRequestHandler struct {
+	repo report.Repository
+}
+
+func NewCreateReportHandler(repo report.Repository) CreateReportRequestHandler {
+	return &createReportRequestHandler{
+		repo: repo,
+	}
+}
+
+func (h *createReportRequestHandler) Handle(ctx context.Context, req CreateReportRequest) (bool, error) {
+	if (req.TopicID > 0) == (req.CommentID > 0) {
+		return false, ErrReportTargetRequired
+	}
+
+	rep := &report.Report{
+		ReporterID:  req.ReporterID,
+		TopicID:     req.TopicID,
+		CommentID:   req.CommentID,
+		Reason:      req.Reason,
+		Description: req.Description,
+	}
+
+	if req.CommentID > 0 {
+		return h.repo.CreateCommentReport(ctx, rep, req.CollapseThreshold)
+	}
+
+	return false, h.repo.CreateTopicReport(ctx, rep)
+}
@@ -0,0 +1,30 @@
+package reportcommands
+
+import (
+	"context"
+	"strings"
+
+	"github.com/arnald/forum/internal/domain/report"
+)
+
+type CreateReportReasonRequest struct {
+	Label string
+}
+
+type CreateReportReasonRequestHandler interface {
+	Handle(ctx context.Context, req CreateReportReasonRequest) (*report.Reason, error)
+}
+
+type createReportReasonRequestHandler struct {
+	repo report.Repository
+}
+
+func NewCreateReportReasonHandler(repo report.Repository) CreateReportReasonRequestHandler {
+	return &createReportReasonRequestHandler{
+		repo: repo,
+	}
+}
+
+func (h *createReportReasonRequestHandler) Handle(ctx context.Context, req CreateReportReasonRequest) (*report.Reason, error) {
+	return h.repo.CreateReason(ctx, strings.TrimSpace(req.Label))
+}
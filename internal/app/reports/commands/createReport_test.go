@@ -0,0 +1,101 @@
+package reportcommands
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/arnald/forum/internal/domain/report"
+)
+
+// recordingReportRepo remembers which create method was called; the other
+// methods are unused here.
+type recordingReportRepo struct {
+	report.Repository
+	filed     *report.Report
+	method    string
+	threshold int
+}
+
+func (r *recordingReportRepo) CreateCommentReport(_ context.Context, rep *report.Report, collapseThreshold int) (bool, error) {
+	r.method = "comment"
+	r.filed = rep
+	r.threshold = collapseThreshold
+	return true, nil
+}
+
+func (r *recordingReportRepo) CreateTopicReport(_ context.Context, rep *report.Report) error {
+	r.method = "topic"
+	r.filed = rep
+	return nil
+}
+
+func TestCreateReportHandler_Handle(t *testing.T) {
+	t.Run("group: report target", func(t *testing.T) {
+		testCases := newCreateReportTestCases()
+		for _, tt := range testCases {
+			t.Run(tt.name, runCreateReportTest(tt))
+		}
+	})
+}
+
+type createReportTestCase struct {
+	wantErr       error
+	name          string
+	wantMethod    string
+	request       CreateReportRequest
+	wantCollapsed bool
+}
+
+func newCreateReportTestCases() []createReportTestCase {
+	return []createReportTestCase{
+		{
+			name:       "topic report goes to the topic branch",
+			request:    CreateReportRequest{ReporterID: "alice", Reason: "Spam", TopicID: 7, CollapseThreshold: 3},
+			wantMethod: "topic",
+		},
+		{
+			name:          "comment report goes to the comment branch",
+			request:       CreateReportRequest{ReporterID: "alice", Reason: "Spam", CommentID: 9, CollapseThreshold: 3},
+			wantMethod:    "comment",
+			wantCollapsed: true,
+		},
+		{
+			name:    "both targets are rejected",
+			request: CreateReportRequest{ReporterID: "alice", Reason: "Spam", TopicID: 7, CommentID: 9},
+			wantErr: ErrReportTargetRequired,
+		},
+		{
+			name:    "no target is rejected",
+			request: CreateReportRequest{ReporterID: "alice", Reason: "Spam"},
+			wantErr: ErrReportTargetRequired,
+		},
+	}
+}
+
+func runCreateReportTest(tt createReportTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		repo := &recordingReportRepo{}
+
+		collapsed, err := NewCreateReportHandler(repo).Handle(context.Background(), tt.request)
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("Handle() error = %v, want %v", err, tt.wantErr)
+		}
+		if repo.method != tt.wantMethod {
+			t.Errorf("Handle() called %q, want %q", repo.method, tt.wantMethod)
+		}
+		if collapsed != tt.wantCollapsed {
+			t.Errorf("Handle() collapsed = %v, want %v", collapsed, tt.wantCollapsed)
+		}
+		if tt.wantMethod == "" {
+			return
+		}
+		if repo.filed.TopicID != tt.request.TopicID || repo.filed.CommentID != tt.request.CommentID {
+			t.Errorf("filed report targets topic %d comment %d, want topic %d comment %d",
+				repo.filed.TopicID, repo.filed.CommentID, tt.request.TopicID, tt.request.CommentID)
+		}
+		if tt.wantMethod == "comment" && repo.threshold != tt.request.CollapseThreshold {
+			t.Errorf("collapse threshold = %d, want %d", repo.threshold, tt.request.CollapseThreshold)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package reportcommands
+
+import "errors"
+
+var (
+	ErrNotModerator          = errors.New("user is not a moderator")
+	ErrInvalidReportStatus   = errors.New("reports can only be dismissed or resolved")
+	ErrReportTargetRequired  = errors.New("a report must name exactly one topic or comment")
+	ErrOutsideModeratorScope = errors.New("comment is outside the moderator's categories")
+)
@@ -0,0 +1,75 @@
+package reportcommands
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/arnald/forum/internal/domain/audit"
+	"github.com/arnald/forum/internal/domain/comment"
+	"github.com/arnald/forum/internal/domain/report"
+	"github.com/arnald/forum/internal/domain/user"
+)
+
+// ResolveCommentReportsRequest settles the pending reports on a comment.
+// Status is report.StatusDismissed, which also uncollapses the comment, or
+// report.StatusResolved, which upholds them and keeps it collapsed.
+type ResolveCommentReportsRequest struct {
+	Moderator *user.User
+	Status    string
+	CommentID int
+}
+
+type ResolveCommentReportsRequestHandler interface {
+	Handle(ctx context.Context, req ResolveCommentReportsRequest) error
+}
+
+type resolveCommentReportsRequestHandler struct {
+	repo     report.Repository
+	comments comment.Repository
+	audit    audit.Repository
+}
+
+func NewResolveCommentReportsHandler(repo report.Repository, commentRepo comment.Repository, auditRepo audit.Repository) ResolveCommentReportsRequestHandler {
+	return &resolveCommentReportsRequestHandler{
+		repo:     repo,
+		comments: commentRepo,
+		audit:    auditRepo,
+	}
+}
+
+func (h *resolveCommentReportsRequestHandler) Handle(ctx context.Context, req ResolveCommentReportsRequest) error {
+	if !req.Moderator.IsModerator() {
+		return ErrNotModerator
+	}
+	if req.Status != report.StatusDismissed && req.Status != report.StatusResolved {
+		return ErrInvalidReportStatus
+	}
+
+	// Settling reports collapses or restores the comment, so it is held to
+	// the same categories as approving or rejecting it.
+	if !req.Moderator.IsAdmin() {
+		outside, err := h.comments.CommentsOutsideModeration(ctx, req.Moderator.ID, []int{req.CommentID})
+		if err != nil {
+			return err
+		}
+		if len(outside) > 0 {
+			return ErrOutsideModeratorScope
+		}
+	}
+
+	err := h.repo.ResolveCommentReports(ctx, req.CommentID, req.Status, req.Moderator.ID)
+	if err != nil {
+		return err
+	}
+
+	action := audit.ActionResolveReports
+	if req.Status == report.StatusDismissed {
+		action = audit.ActionDismissReports
+	}
+	return h.audit.LogAudit(ctx, &audit.Entry{
+		AdminID:    req.Moderator.ID,
+		Action:     action,
+		TargetType: audit.TargetComment,
+		TargetID:   strconv.Itoa(req.CommentID),
+	})
+}
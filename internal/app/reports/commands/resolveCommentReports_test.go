@@ -0,0 +1,92 @@
+package reportcommands
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/arnald/forum/internal/domain/audit"
+	"github.com/arnald/forum/internal/domain/comment"
+	"github.com/arnald/forum/internal/domain/report"
+	"github.com/arnald/forum/internal/domain/user"
+)
+
+// resolvingReportRepo records whether reports were settled.
+type resolvingReportRepo struct {
+	report.Repository
+	resolved bool
+}
+
+func (r *resolvingReportRepo) ResolveCommentReports(_ context.Context, _ int, _, _ string) error {
+	r.resolved = true
+	return nil
+}
+
+// scopeCommentRepo puts every comment inside or outside the moderator's
+// categories.
+type scopeCommentRepo struct {
+	comment.Repository
+	outOfScope bool
+}
+
+func (r scopeCommentRepo) CommentsOutsideModeration(_ context.Context, _ string, ids []int) ([]int, error) {
+	if r.outOfScope {
+		return ids, nil
+	}
+	return []int{}, nil
+}
+
+type discardAuditRepo struct {
+	audit.Repository
+}
+
+func (discardAuditRepo) LogAudit(_ context.Context, _ *audit.Entry) error {
+	return nil
+}
+
+func TestResolveCommentReportsHandler_Scope(t *testing.T) {
+	testCases := []struct {
+		wantError    error
+		moderator    *user.User
+		name         string
+		outOfScope   bool
+		wantResolved bool
+	}{
+		{
+			name:         "moderator assigned to the category",
+			moderator:    &user.User{ID: "mod", Role: user.RoleModerator},
+			wantResolved: true,
+		},
+		{
+			name:       "moderator outside the category",
+			moderator:  &user.User{ID: "mod", Role: user.RoleModerator},
+			outOfScope: true,
+			wantError:  ErrOutsideModeratorScope,
+		},
+		{
+			name:         "admins settle reports everywhere",
+			moderator:    &user.User{ID: "admin", Role: user.RoleAdmin},
+			outOfScope:   true,
+			wantResolved: true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			reports := &resolvingReportRepo{}
+			h := NewResolveCommentReportsHandler(reports, scopeCommentRepo{outOfScope: tt.outOfScope}, discardAuditRepo{})
+
+			err := h.Handle(context.Background(), ResolveCommentReportsRequest{
+				Moderator: tt.moderator,
+				Status:    report.StatusDismissed,
+				CommentID: 7,
+			})
+			if !errors.Is(err, tt.wantError) {
+				t.Fatalf("Handle() error = %v, want %v", err, tt.wantError)
+			}
+			if reports.resolved != tt.wantResolved {
+				t.Errorf("reports resolved = %v, want %v", reports.resolved, tt.wantResolved)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package reportcommands
+
+import (
+	"context"
+
+	"github.com/arnald/forum/internal/domain/report"
+)
+
+// RetireReportReasonRequest takes a reason off the report form, or puts it
+// back when Active is true.
+type RetireReportReasonRequest struct {
+	ReasonID int
+	Active   bool
+}
+
+type RetireReportReasonRequestHandler interface {
+	Handle(ctx context.Context, req RetireReportReasonRequest) error
+}
+
+type retireReportReasonRequestHandler struct {
+	repo report.Repository
+}
+
+func NewRetireReportReasonHandler(repo report.Repository) RetireReportReasonRequestHandler {
+	return &retireReportReasonRequestHandler{
+		repo: repo,
+	}
+}
+
+func (h *retireReportReasonRequestHandler) Handle(ctx context.Context, req RetireReportReasonRequest) error {
+	return h.repo.SetReasonActive(ctx, req.ReasonID, req.Active)
+}
@@ -0,0 +1,40 @@
+package reportqueries
+
+import (
+	"context"
+	"strings"
+
+	"github.com/arnald/forum/internal/domain/report"
+)
+
+// CheckReportReasonRequest validates the reason given for a new report
+// against the active list. Reports filed earlier are never re-checked, so
+// retiring a reason leaves their text intact.
+type CheckReportReasonRequest struct {
+	Reason string
+}
+
+type CheckReportReasonRequestHandler interface {
+	Handle(ctx context.Context, req CheckReportReasonRequest) error
+}
+
+type checkReportReasonRequestHandler struct {
+	repo report.Repository
+}
+
+func NewCheckReportReasonHandler(repo report.Repository) CheckReportReasonRequestHandler {
+	return &checkReportReasonRequestHandler{
+		repo: repo,
+	}
+}
+
+func (h *checkReportReasonRequestHandler) Handle(ctx context.Context, req CheckReportReasonRequest) error {
+	active, err := h.repo.IsActiveReason(ctx, strings.TrimSpace(req.Reason))
+	if err != nil {
+		return err
+	}
+	if !active {
+		return ErrInactiveReportReason
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package reportqueries
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/arnald/forum/internal/domain/report"
+)
+
+// stubReasonRepo knows a fixed set of active labels; the other methods are
+// unused here.
+type stubReasonRepo struct {
+	report.Repository
+	active map[string]bool
+}
+
+func (s stubReasonRepo) IsActiveReason(_ context.Context, label string) (bool, error) {
+	return s.active[label], nil
+}
+
+func TestCheckReportReasonHandler_Handle(t *testing.T) {
+	t.Run("group: report reason validation", func(t *testing.T) {
+		testCases := newCheckReportReasonTestCases()
+		for _, tt := range testCases {
+			t.Run(tt.name, runCheckReportReasonTest(tt))
+		}
+	})
+}
+
+type checkReportReasonTestCase struct {
+	wantError error
+	name      string
+	reason    string
+}
+
+func newCheckReportReasonTestCases() []checkReportReasonTestCase {
+	return []checkReportReasonTestCase{
+		{
+			name:   "active reason is accepted",
+			reason: "Spam",
+		},
+		{
+			name:   "surrounding whitespace is ignored",
+			reason: "  Spam ",
+		},
+		{
+			name:      "retired reason is rejected",
+			reason:    "Off-topic",
+			wantError: ErrInactiveReportReason,
+		},
+		{
+			name:      "unknown reason is rejected",
+			reason:    "I just don't like it",
+			wantError: ErrInactiveReportReason,
+		},
+	}
+}
+
+func runCheckReportReasonTest(tt checkReportReasonTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		repo := stubReasonRepo{active: map[string]bool{"Spam": true}}
+
+		err := NewCheckReportReasonHandler(repo).Handle(context.Background(), CheckReportReasonRequest{
+			Reason: tt.reason,
+		})
+		if !errors.Is(err, tt.wantError) {
+			t.Errorf("Handle() error = %v, want %v", err, tt.wantError)
+		}
+	}
+}
@@ -0,0 +1,5 @@
+package reportqueries
+
+import "errors"
+
+var ErrInactiveReportReason = errors.New("report reason is not one of the active reasons")
@@ -0,0 +1,31 @@
+package reportqueries
+
+import (
+	"context"
+
+	"github.com/arnald/forum/internal/domain/report"
+)
+
+// GetReportReasonsRequest lists the reasons offered on the report form.
+// IncludeRetired adds the retired ones for the admin page.
+type GetReportReasonsRequest struct {
+	IncludeRetired bool
+}
+
+type GetReportReasonsRequestHandler interface {
+	Handle(ctx context.Context, req GetReportReasonsRequest) ([]report.Reason, error)
+}
+
+type getReportReasonsRequestHandler struct {
+	repo report.Repository
+}
+
+func NewGetReportReasonsHandler(repo report.Repository) GetReportReasonsRequestHandler {
+	return &getReportReasonsRequestHandler{
+		repo: repo,
+	}
+}
+
+func (h *getReportReasonsRequestHandler) Handle(ctx context.Context, req GetReportReasonsRequest) ([]report.Reason, error) {
+	return h.repo.GetReasons(ctx, req.IncludeRetired)
+}
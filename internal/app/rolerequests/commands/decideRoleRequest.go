@@ -0,0 +1,90 @@
+package rolerequestcommands
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/arnald/forum/internal/domain/audit"
+	"github.com/arnald/forum/internal/domain/rolerequest"
+	"github.com/arnald/forum/internal/domain/user"
+)
+
+// DecideRoleRequestRequest settles a pending role request. Decision is
+// rolerequest.StatusApproved, which also grants the requested role, or
+// rolerequest.StatusRejected.
+type DecideRoleRequestRequest struct {
+	Admin      *user.User
+	Decision   string
+	AdminNotes string
+	RequestID  int
+}
+
+type DecideRoleRequestRequestHandler interface {
+	// Handle returns the request as decided.
+	Handle(ctx context.Context, req DecideRoleRequestRequest) (*rolerequest.RoleRequest, error)
+}
+
+type decideRoleRequestRequestHandler struct {
+	repo  rolerequest.Repository
+	users user.Repository
+	audit audit.Repository
+}
+
+func NewDecideRoleRequestHandler(repo rolerequest.Repository, userRepo user.Repository, auditRepo audit.Repository) DecideRoleRequestRequestHandler {
+	return &decideRoleRequestRequestHandler{
+		repo:  repo,
+		users: userRepo,
+		audit: auditRepo,
+	}
+}
+
+func (h *decideRoleRequestRequestHandler) Handle(ctx context.Context, req DecideRoleRequestRequest) (*rolerequest.RoleRequest, error) {
+	if !req.Admin.IsAdmin() {
+		return nil, ErrNotAdmin
+	}
+	if req.Decision != rolerequest.StatusApproved && req.Decision != rolerequest.StatusRejected {
+		return nil, ErrInvalidDecision
+	}
+
+	request, err := h.repo.GetRoleRequestByID(ctx, req.RequestID)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != rolerequest.StatusPending {
+		return nil, ErrRequestNotPending
+	}
+
+	// The role is granted before the request is closed: if closing fails the
+	// request stays pending and approving it again is harmless.
+	if req.Decision == rolerequest.StatusApproved {
+		err = h.users.UpdateUserRole(ctx, request.UserID, request.RequestedRole)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = h.repo.DecideRoleRequest(ctx, request.ID, req.Decision, req.Admin.ID, req.AdminNotes)
+	if err != nil {
+		return nil, err
+	}
+	request.Status = req.Decision
+	request.AdminID = req.Admin.ID
+	request.AdminNotes = req.AdminNotes
+
+	action := audit.ActionApproveRole
+	if req.Decision == rolerequest.StatusRejected {
+		action = audit.ActionRejectRole
+	}
+	err = h.audit.LogAudit(ctx, &audit.Entry{
+		AdminID:    req.Admin.ID,
+		Action:     action,
+		TargetType: audit.TargetRoleRequest,
+		TargetID:   strconv.Itoa(request.ID),
+		Details:    req.AdminNotes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
@@ -0,0 +1,167 @@
+package rolerequestcommands
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/arnald/forum/internal/domain/audit"
+	"github.com/arnald/forum/internal/domain/rolerequest"
+	"github.com/arnald/forum/internal/domain/user"
+	testhelpers "github.com/arnald/forum/internal/pkg/testing"
+)
+
+// memoryRoleRequestRepo keeps role requests in a map.
+type memoryRoleRequestRepo struct {
+	requests map[int]*rolerequest.RoleRequest
+	nextID   int
+}
+
+func newMemoryRoleRequestRepo() *memoryRoleRequestRepo {
+	return &memoryRoleRequestRepo{requests: make(map[int]*rolerequest.RoleRequest)}
+}
+
+func (r *memoryRoleRequestRepo) CreateRoleRequest(_ context.Context, request *rolerequest.RoleRequest) error {
+	r.nextID++
+	request.ID = r.nextID
+	request.Status = rolerequest.StatusPending
+	stored := *request
+	r.requests[request.ID] = &stored
+	return nil
+}
+
+func (r *memoryRoleRequestRepo) GetRoleRequestByID(_ context.Context, id int) (*rolerequest.RoleRequest, error) {
+	stored, ok := r.requests[id]
+	if !ok {
+		return nil, testhelpers.ErrTest
+	}
+	request := *stored
+	return &request, nil
+}
+
+func (r *memoryRoleRequestRepo) DecideRoleRequest(_ context.Context, id int, status, adminID, adminNotes string) error {
+	stored := r.requests[id]
+	stored.Status = status
+	stored.AdminID = adminID
+	stored.AdminNotes = adminNotes
+	return nil
+}
+
+type recordingAuditRepo struct {
+	audit.Repository
+	entries []audit.Entry
+}
+
+func (r *recordingAuditRepo) LogAudit(_ context.Context, entry *audit.Entry) error {
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+func TestRoleRequestFlow(t *testing.T) {
+	ctx := context.Background()
+	admin := &user.User{ID: "root", Role: user.RoleAdmin}
+
+	run := func(t *testing.T, decision string) (*user.User, *rolerequest.RoleRequest, *recordingAuditRepo) {
+		t.Helper()
+
+		alice := &user.User{ID: "alice", Role: user.RoleUser}
+		users := &testhelpers.MockRepository{
+			UpdateUserRoleFunc: func(_ context.Context, userID, role string) error {
+				if userID != alice.ID {
+					t.Errorf("UpdateUserRole() userID = %q, want %q", userID, alice.ID)
+				}
+				alice.Role = role
+				return nil
+			},
+		}
+		requests := newMemoryRoleRequestRepo()
+		audits := &recordingAuditRepo{}
+
+		filed, err := NewRequestRoleHandler(requests).Handle(ctx, RequestRoleRequest{
+			User:   alice,
+			Reason: "I answer most questions in the Go category",
+		})
+		if err != nil {
+			t.Fatalf("RequestRole error = %v", err)
+		}
+		if filed.RequestedRole != user.RoleModerator || filed.Status != rolerequest.StatusPending {
+			t.Fatalf("RequestRole = %+v, want a pending moderator request", filed)
+		}
+
+		decided, err := NewDecideRoleRequestHandler(requests, users, audits).Handle(ctx, DecideRoleRequestRequest{
+			Admin:      admin,
+			Decision:   decision,
+			AdminNotes: "Thanks for helping out",
+			RequestID:  filed.ID,
+		})
+		if err != nil {
+			t.Fatalf("DecideRoleRequest error = %v", err)
+		}
+		if decided.Status != decision || decided.AdminID != admin.ID || decided.AdminNotes != "Thanks for helping out" {
+			t.Errorf("DecideRoleRequest = %+v, want %s by %s with notes", decided, decision, admin.ID)
+		}
+
+		_, err = NewDecideRoleRequestHandler(requests, users, audits).Handle(ctx, DecideRoleRequestRequest{
+			Admin:     admin,
+			Decision:  rolerequest.StatusApproved,
+			RequestID: filed.ID,
+		})
+		if !errors.Is(err, ErrRequestNotPending) {
+			t.Errorf("deciding twice error = %v, want ErrRequestNotPending", err)
+		}
+
+		return alice, decided, audits
+	}
+
+	t.Run("approval grants the role", func(t *testing.T) {
+		alice, _, audits := run(t, rolerequest.StatusApproved)
+		if alice.Role != user.RoleModerator {
+			t.Errorf("role after approval = %q, want %q", alice.Role, user.RoleModerator)
+		}
+		if len(audits.entries) != 1 || audits.entries[0].Action != audit.ActionApproveRole {
+			t.Errorf("audit entries = %+v, want one %s", audits.entries, audit.ActionApproveRole)
+		}
+	})
+
+	t.Run("rejection leaves the role alone", func(t *testing.T) {
+		alice, _, audits := run(t, rolerequest.StatusRejected)
+		if alice.Role != user.RoleUser {
+			t.Errorf("role after rejection = %q, want %q", alice.Role, user.RoleUser)
+		}
+		if len(audits.entries) != 1 || audits.entries[0].Action != audit.ActionRejectRole {
+			t.Errorf("audit entries = %+v, want one %s", audits.entries, audit.ActionRejectRole)
+		}
+	})
+}
+
+func TestRoleRequestGuards(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := NewRequestRoleHandler(newMemoryRoleRequestRepo()).Handle(ctx, RequestRoleRequest{
+		User:   &user.User{ID: "mod", Role: user.RoleModerator},
+		Reason: "More power",
+	})
+	if !errors.Is(err, ErrAlreadyStaff) {
+		t.Errorf("moderator requesting error = %v, want ErrAlreadyStaff", err)
+	}
+
+	decide := NewDecideRoleRequestHandler(newMemoryRoleRequestRepo(), &testhelpers.MockRepository{}, &recordingAuditRepo{})
+
+	_, err = decide.Handle(ctx, DecideRoleRequestRequest{
+		Admin:     &user.User{ID: "mod", Role: user.RoleModerator},
+		Decision:  rolerequest.StatusApproved,
+		RequestID: 1,
+	})
+	if !errors.Is(err, ErrNotAdmin) {
+		t.Errorf("moderator deciding error = %v, want ErrNotAdmin", err)
+	}
+
+	_, err = decide.Handle(ctx, DecideRoleRequestRequest{
+		Admin:     &user.User{ID: "root", Role: user.RoleAdmin},
+		Decision:  rolerequest.StatusPending,
+		RequestID: 1,
+	})
+	if !errors.Is(err, ErrInvalidDecision) {
+		t.Errorf("pending decision error = %v, want ErrInvalidDecision", err)
+	}
+}
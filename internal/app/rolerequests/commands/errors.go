@@ -0,0 +1,10 @@
+package rolerequestcommands
+
+import "errors"
+
+var (
+	ErrNotAdmin          = errors.New("user is not an admin")
+	ErrAlreadyStaff      = errors.New("user already holds a staff role")
+	ErrInvalidDecision   = errors.New("role requests can only be approved or rejected")
+	ErrRequestNotPending = errors.New("role request has already been decided")
+)
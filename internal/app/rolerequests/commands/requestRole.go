@@ -0,0 +1,46 @@
+package rolerequestcommands
+
+import (
+	"context"
+
+	"github.com/arnald/forum/internal/domain/rolerequest"
+	"github.com/arnald/forum/internal/domain/user"
+)
+
+// RequestRoleRequest asks for moderator status on behalf of User.
+type RequestRoleRequest struct {
+	User   *user.User
+	Reason string
+}
+
+type RequestRoleRequestHandler interface {
+	Handle(ctx context.Context, req RequestRoleRequest) (*rolerequest.RoleRequest, error)
+}
+
+type requestRoleRequestHandler struct {
+	repo rolerequest.Repository
+}
+
+func NewRequestRoleHandler(repo rolerequest.Repository) RequestRoleRequestHandler {
+	return &requestRoleRequestHandler{
+		repo: repo,
+	}
+}
+
+func (h *requestRoleRequestHandler) Handle(ctx context.Context, req RequestRoleRequest) (*rolerequest.RoleRequest, error) {
+	if req.User.IsModerator() {
+		return nil, ErrAlreadyStaff
+	}
+
+	request := &rolerequest.RoleRequest{
+		UserID:        req.User.ID,
+		RequestedRole: user.RoleModerator,
+		Reason:        req.Reason,
+	}
+	err := h.repo.CreateRoleRequest(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
@@ -2,11 +2,16 @@ package app
 
 import (
 	activityQueries "github.com/arnald/forum/internal/app/activities/queries"
+	auditQueries "github.com/arnald/forum/internal/app/audit/queries"
 	categoryCommands "github.com/arnald/forum/internal/app/categories/commands"
 	categoryQueries "github.com/arnald/forum/internal/app/categories/queries"
 	commentCommands "github.com/arnald/forum/internal/app/comments/commands"
 	commentQueries "github.com/arnald/forum/internal/app/comments/queries"
+	importCommands "github.com/arnald/forum/internal/app/imports/commands"
 	oauthservice "github.com/arnald/forum/internal/app/oauth"
+	reportCommands "github.com/arnald/forum/internal/app/reports/commands"
+	reportQueries "github.com/arnald/forum/internal/app/reports/queries"
+	roleRequestCommands "github.com/arnald/forum/internal/app/rolerequests/commands"
 	topicCommands "github.com/arnald/forum/internal/app/topics/commands"
 	topicQueries "github.com/arnald/forum/internal/app/topics/queries"
 	userCommands "github.com/arnald/forum/internal/app/user/commands"
@@ -14,9 +19,13 @@ import (
 	votecommands "github.com/arnald/forum/internal/app/votes/commands"
 	voteQueries "github.com/arnald/forum/internal/app/votes/queries"
 	"github.com/arnald/forum/internal/domain/activity"
+	"github.com/arnald/forum/internal/domain/audit"
 	"github.com/arnald/forum/internal/domain/category"
 	"github.com/arnald/forum/internal/domain/comment"
+	"github.com/arnald/forum/internal/domain/dataimport"
 	"github.com/arnald/forum/internal/domain/oauth"
+	"github.com/arnald/forum/internal/domain/report"
+	"github.com/arnald/forum/internal/domain/rolerequest"
 	"github.com/arnald/forum/internal/domain/topic"
 	"github.com/arnald/forum/internal/domain/user"
 	"github.com/arnald/forum/internal/domain/vote"
@@ -28,29 +37,66 @@ type Queries struct {
 	UserLoginGithub    oauthservice.OAuthService
 	GetTopic           topicQueries.GetTopicRequestHandler
 	GetAllTopics       topicQueries.GetAllTopicsRequestHandler
+	GetTopicWatchers   topicQueries.GetTopicWatchersRequestHandler
+	GetTopicHistory    topicQueries.GetTopicHistoryRequestHandler
 	GetComment         commentQueries.GetCommentRequestHandler
 	GetCommentsByTopic commentQueries.GetCommentsByTopicRequestHandler
+	GetPendingComments commentQueries.GetPendingCommentsRequestHandler
 	UserLoginEmail     userQueries.UserLoginEmailRequestHandler
 	UserLoginUsername  userQueries.UserLoginUsernameRequestHandler
+	VerifyPassword     userQueries.VerifyPasswordRequestHandler
+	GetUserByUsername  userQueries.GetUserByUsernameRequestHandler
+	GetAllUsers        userQueries.GetAllUsersRequestHandler
+	ResolveMentions    userQueries.ResolveMentionsRequestHandler
 	GetCategoryByID    categoryQueries.GetCategoryByIDHandler
 	GetAllCategories   categoryQueries.GetAllCategoriesRequestHandler
 	GetCounts          voteQueries.GetCountsRequestHandler
 	GetUserActivity    activityQueries.GetUserActivityHandler
+	GetActivityFeed    activityQueries.GetActivityFeedHandler
+	GetUserProfile     activityQueries.GetUserProfileHandler
+	GetUserSubmissions activityQueries.GetUserSubmissionsHandler
+	GetReportReasons   reportQueries.GetReportReasonsRequestHandler
+	CheckReportReason  reportQueries.CheckReportReasonRequestHandler
+	GetAuditLog        auditQueries.GetAuditLogRequestHandler
 }
 
 type Commands struct {
-	UserRegister   userCommands.UserRegisterRequestHandler
-	CreateTopic    topicCommands.CreateTopicRequestHandler
-	UpdateTopic    topicCommands.UpdateTopicRequestHandler
-	DeleteTopic    topicCommands.DeleteTopicRequestHandler
-	CreateComment  commentCommands.CreateCommentRequestHandler
-	UpdateComment  commentCommands.UpdateCommentRequestHandler
-	DeleteComment  commentCommands.DeleteCommentRequestHandler
-	CreateCategory categoryCommands.CreateCategoryRequestHandler
-	UpdateCategory categoryCommands.UpdateCategoryRequestHandler
-	DeleteCategory categoryCommands.DeleteCategoryRequestHandler
-	CastVote       votecommands.CastVoteRequestHandler
-	DeleteVote     votecommands.DeleteVoteRequestHandler
+	UserRegister    userCommands.UserRegisterRequestHandler
+	ForgotPassword  userCommands.ForgotPasswordRequestHandler
+	ResetPassword   userCommands.ResetPasswordRequestHandler
+	ChangePassword  userCommands.ChangePasswordRequestHandler
+	ChangeUsername  userCommands.ChangeUsernameRequestHandler
+	ChangeAvatar    userCommands.ChangeAvatarRequestHandler
+	SendVerify      userCommands.SendVerificationEmailRequestHandler
+	VerifyEmail     userCommands.VerifyEmailRequestHandler
+	CreateTopic     topicCommands.CreateTopicRequestHandler
+	UpdateTopic     topicCommands.UpdateTopicRequestHandler
+	DeleteTopic     topicCommands.DeleteTopicRequestHandler
+	RestoreTopic    topicCommands.RestoreTopicRequestHandler
+	PublishTopic    topicCommands.PublishTopicRequestHandler
+	RecordView      topicCommands.RecordTopicViewRequestHandler
+	WatchTopic      topicCommands.WatchTopicRequestHandler
+	ToggleBookmark  topicCommands.ToggleBookmarkRequestHandler
+	AcceptAnswer    topicCommands.AcceptAnswerRequestHandler
+	CreateComment   commentCommands.CreateCommentRequestHandler
+	UpdateComment   commentCommands.UpdateCommentRequestHandler
+	DeleteComment   commentCommands.DeleteCommentRequestHandler
+	ModerateComment commentCommands.ModerateCommentRequestHandler
+	BulkModerate    commentCommands.BulkModerateCommentsRequestHandler
+	CreateCategory  categoryCommands.CreateCategoryRequestHandler
+	UpdateCategory  categoryCommands.UpdateCategoryRequestHandler
+	DeleteCategory  categoryCommands.DeleteCategoryRequestHandler
+	ArchiveCategory categoryCommands.ArchiveCategoryRequestHandler
+	SetCategoryMod  categoryCommands.SetCategoryModeratorRequestHandler
+	CastVote        votecommands.CastVoteRequestHandler
+	DeleteVote      votecommands.DeleteVoteRequestHandler
+	CreateReason    reportCommands.CreateReportReasonRequestHandler
+	RetireReason    reportCommands.RetireReportReasonRequestHandler
+	CreateReport    reportCommands.CreateReportRequestHandler
+	ResolveReports  reportCommands.ResolveCommentReportsRequestHandler
+	ImportContent   importCommands.ImportContentRequestHandler
+	RequestRole     roleRequestCommands.RequestRoleRequestHandler
+	DecideRole      roleRequestCommands.DecideRoleRequestRequestHandler
 }
 
 type UserServices struct {
@@ -62,37 +108,74 @@ type Services struct {
 	UserServices UserServices
 }
 
-func NewServices(userRepo user.Repository, categoryRepo category.Repository, topicRepo topic.Repository, commentRepo comment.Repository, voteRepo vote.Repository, oauthRepo oauth.Repository, activityRepo activity.Repository) Services {
+func NewServices(userRepo user.Repository, categoryRepo category.Repository, topicRepo topic.Repository, commentRepo comment.Repository, voteRepo vote.Repository, oauthRepo oauth.Repository, activityRepo activity.Repository, reportRepo report.Repository, importRepo dataimport.Repository, auditRepo audit.Repository, roleRequestRepo rolerequest.Repository, stripEmailAliases bool) Services {
 	uuidProvider := uuid.NewProvider()
 	encryption := bcrypt.NewProvider()
 	return Services{
 		UserServices: UserServices{
 			Queries: Queries{
-				*oauthservice.NewOAuthService(oauthRepo, uuidProvider),
+				*oauthservice.NewOAuthService(oauthRepo, uuidProvider, stripEmailAliases),
 				topicQueries.NewGetTopicHandler(topicRepo, commentRepo),
 				topicQueries.NewGetAllTopicsHandler(topicRepo, categoryRepo),
+				topicQueries.NewGetTopicWatchersHandler(topicRepo),
+				topicQueries.NewGetTopicHistoryHandler(topicRepo),
 				commentQueries.NewGetCommentHandler(commentRepo),
-				commentQueries.NewGetCommentsByTopicRequestHandler(commentRepo),
+				commentQueries.NewGetCommentsByTopicRequestHandler(commentRepo, topicRepo),
+				commentQueries.NewGetPendingCommentsHandler(commentRepo),
 				userQueries.NewUserLoginEmailHandler(userRepo, encryption),
 				userQueries.NewUserLoginUsernameHandler(userRepo, encryption),
+				userQueries.NewVerifyPasswordHandler(userRepo, encryption),
+				userQueries.NewGetUserByUsernameHandler(userRepo),
+				userQueries.NewGetAllUsersRequestHandler(userRepo),
+				userQueries.NewResolveMentionsHandler(userRepo),
 				categoryQueries.NewGetCategoryByIDHandler(categoryRepo),
 				categoryQueries.NewGetAllCategoriesHandler(categoryRepo),
 				voteQueries.NewGetCountsRequestHandler(voteRepo),
 				activityQueries.NewGetUserActivityHandler(activityRepo),
+				activityQueries.NewGetActivityFeedHandler(activityRepo),
+				activityQueries.NewGetUserProfileHandler(activityRepo),
+				activityQueries.NewGetUserSubmissionsHandler(activityRepo),
+				reportQueries.NewGetReportReasonsHandler(reportRepo),
+				reportQueries.NewCheckReportReasonHandler(reportRepo),
+				auditQueries.NewGetAuditLogHandler(auditRepo),
 			},
 			Commands: Commands{
 				userCommands.NewUserRegisterHandler(userRepo, uuidProvider, encryption),
+				userCommands.NewForgotPasswordHandler(userRepo, uuidProvider),
+				userCommands.NewResetPasswordHandler(userRepo, encryption),
+				userCommands.NewChangePasswordHandler(userRepo, encryption),
+				userCommands.NewChangeUsernameHandler(userRepo),
+				userCommands.NewChangeAvatarHandler(userRepo),
+				userCommands.NewSendVerificationEmailHandler(userRepo, uuidProvider),
+				userCommands.NewVerifyEmailHandler(userRepo),
 				topicCommands.NewCreateTopicHandler(topicRepo),
 				topicCommands.NewUpdateTopicHandler(topicRepo),
 				topicCommands.NewDeleteTopicHandler(topicRepo),
-				commentCommands.NewCreateCommentRequestHandler(commentRepo),
+				topicCommands.NewRestoreTopicHandler(topicRepo),
+				topicCommands.NewPublishTopicHandler(topicRepo),
+				topicCommands.NewRecordTopicViewHandler(topicRepo),
+				topicCommands.NewWatchTopicHandler(topicRepo),
+				topicCommands.NewToggleBookmarkHandler(topicRepo),
+				topicCommands.NewAcceptAnswerHandler(topicRepo, commentRepo),
+				commentCommands.NewCreateCommentRequestHandler(commentRepo, topicRepo),
 				commentCommands.NewUpdateCommentRequestHandler(commentRepo),
 				commentCommands.NewDeleteCommentHandler(commentRepo),
+				commentCommands.NewModerateCommentHandler(commentRepo, auditRepo),
+				commentCommands.NewBulkModerateCommentsHandler(commentRepo),
 				categoryCommands.NewCreateCategoryHandler(categoryRepo),
 				categoryCommands.NewUpdateCategoryHandler(categoryRepo),
-				categoryCommands.NewDeleteCategoryHandler(categoryRepo),
+				categoryCommands.NewDeleteCategoryHandler(categoryRepo, auditRepo),
+				categoryCommands.NewArchiveCategoryHandler(categoryRepo),
+				categoryCommands.NewSetCategoryModeratorHandler(categoryRepo),
 				votecommands.NewCastVoteHandler(voteRepo),
 				votecommands.NewDeleteVoteHandler(voteRepo),
+				reportCommands.NewCreateReportReasonHandler(reportRepo),
+				reportCommands.NewRetireReportReasonHandler(reportRepo),
+				reportCommands.NewCreateReportHandler(reportRepo),
+				reportCommands.NewResolveCommentReportsHandler(reportRepo, commentRepo, auditRepo),
+				importCommands.NewImportContentHandler(importRepo, uuidProvider),
+				roleRequestCommands.NewRequestRoleHandler(roleRequestRepo),
+				roleRequestCommands.NewDecideRoleRequestHandler(roleRequestRepo, userRepo, auditRepo),
 			},
 		},
 	}
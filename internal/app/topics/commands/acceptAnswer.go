@@ -0,0 +1,63 @@
+package topiccommands
+
+import (
+	"context"
+
+	"github.com/arnald/forum/internal/domain/comment"
+	"github.com/arnald/forum/internal/domain/topic"
+	"github.com/arnald/forum/internal/domain/user"
+)
+
+// AcceptAnswerRequest marks a comment as the accepted answer to the question
+// topic it was posted on.
+type AcceptAnswerRequest struct {
+	User      *user.User
+	CommentID int
+}
+
+type AcceptAnswerRequestHandler interface {
+	Handle(ctx context.Context, req AcceptAnswerRequest) (*topic.Topic, *comment.Comment, error)
+}
+
+type acceptAnswerRequestHandler struct {
+	topicRepo   topic.Repository
+	commentRepo comment.Repository
+}
+
+func NewAcceptAnswerHandler(topicRepo topic.Repository, commentRepo comment.Repository) AcceptAnswerRequestHandler {
+	return &acceptAnswerRequestHandler{
+		topicRepo:   topicRepo,
+		commentRepo: commentRepo,
+	}
+}
+
+// Handle returns the question and its newly accepted answer. Only the
+// question's author or staff may accept, and only an approved comment.
+func (h *acceptAnswerRequestHandler) Handle(ctx context.Context, req AcceptAnswerRequest) (*topic.Topic, *comment.Comment, error) {
+	answer, err := h.commentRepo.GetCommentByID(ctx, req.CommentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if answer.Status != comment.StatusApproved {
+		return nil, nil, ErrAnswerNotApproved
+	}
+
+	question, err := h.topicRepo.GetTopicByID(ctx, answer.TopicID, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !question.IsQuestion {
+		return nil, nil, ErrNotQuestion
+	}
+	if question.UserID != req.User.ID && !req.User.IsModerator() {
+		return nil, nil, ErrNotQuestionAuthor
+	}
+
+	err = h.topicRepo.SetAcceptedAnswer(ctx, question.ID, answer.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	question.AcceptedCommentID = answer.ID
+
+	return question, answer, nil
+}
@@ -0,0 +1,111 @@
+package topiccommands
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/arnald/forum/internal/domain/comment"
+	"github.com/arnald/forum/internal/domain/topic"
+	"github.com/arnald/forum/internal/domain/user"
+	testhelpers "github.com/arnald/forum/internal/pkg/testing"
+)
+
+// stubCommentRepo serves a single comment; the other methods are unused here.
+type stubCommentRepo struct {
+	comment.Repository
+	answer *comment.Comment
+}
+
+func (s stubCommentRepo) GetCommentByID(_ context.Context, _ int) (*comment.Comment, error) {
+	return s.answer, nil
+}
+
+func TestAcceptAnswerHandler_Handle(t *testing.T) {
+	t.Run("group: accept answer", func(t *testing.T) {
+		testCases := newAcceptAnswerTestCases()
+		for _, tt := range testCases {
+			t.Run(tt.name, runAcceptAnswerTest(tt))
+		}
+	})
+}
+
+type acceptAnswerTestCase struct {
+	wantError  error
+	user       *user.User
+	name       string
+	status     string
+	isQuestion bool
+}
+
+func newAcceptAnswerTestCases() []acceptAnswerTestCase {
+	return []acceptAnswerTestCase{
+		{
+			name:       "question author accepts",
+			user:       &user.User{ID: "asker"},
+			status:     comment.StatusApproved,
+			isQuestion: true,
+		},
+		{
+			name:       "staff accept on the author's behalf",
+			user:       &user.User{ID: "mod", Role: user.RoleModerator},
+			status:     comment.StatusApproved,
+			isQuestion: true,
+		},
+		{
+			name:       "other users cannot accept",
+			user:       &user.User{ID: "stranger"},
+			status:     comment.StatusApproved,
+			isQuestion: true,
+			wantError:  ErrNotQuestionAuthor,
+		},
+		{
+			name:      "topic is not a question",
+			user:      &user.User{ID: "asker"},
+			status:    comment.StatusApproved,
+			wantError: ErrNotQuestion,
+		},
+		{
+			name:       "pending comments cannot be accepted",
+			user:       &user.User{ID: "asker"},
+			status:     comment.StatusPending,
+			isQuestion: true,
+			wantError:  ErrAnswerNotApproved,
+		},
+	}
+}
+
+func runAcceptAnswerTest(tt acceptAnswerTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		accepted := 0
+		topicRepo := &testhelpers.MockRepository{
+			GetTopicByIDFunc: func(_ context.Context, topicID int, _ *string) (*topic.Topic, error) {
+				return &topic.Topic{ID: topicID, UserID: "asker", IsQuestion: tt.isQuestion}, nil
+			},
+			SetAcceptedAnswerFunc: func(_ context.Context, _, commentID int) error {
+				accepted = commentID
+				return nil
+			},
+		}
+		commentRepo := stubCommentRepo{
+			answer: &comment.Comment{ID: 7, TopicID: 3, UserID: "answerer", Status: tt.status},
+		}
+
+		question, _, err := NewAcceptAnswerHandler(topicRepo, commentRepo).Handle(context.Background(), AcceptAnswerRequest{
+			User:      tt.user,
+			CommentID: 7,
+		})
+		if !errors.Is(err, tt.wantError) {
+			t.Fatalf("Handle() error = %v, want %v", err, tt.wantError)
+		}
+		if tt.wantError != nil {
+			if accepted != 0 {
+				t.Error("Handle() accepted an answer despite the error")
+			}
+			return
+		}
+		if accepted != 7 || question.AcceptedCommentID != 7 {
+			t.Errorf("accepted comment = %d (topic says %d), want 7", accepted, question.AcceptedCommentID)
+		}
+	}
+}
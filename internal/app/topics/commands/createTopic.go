@@ -2,17 +2,42 @@ package topiccommands
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/arnald/forum/internal/domain/topic"
 	"github.com/arnald/forum/internal/domain/user"
+	"github.com/arnald/forum/internal/pkg/helpers"
 )
 
 type CreateTopicRequest struct {
-	User        *user.User
+	User *user.User
+	// FieldErrors are format problems the caller already found, keyed like
+	// ValidationError.Fields; Handle reports them along with its own rules.
+	FieldErrors map[string]string
 	Title       string `json:"title"`
 	Content     string `json:"content"`
+	Summary     string `json:"summary"`
 	ImagePath   string `json:"imagePath"`
 	CategoryIDs []int  `json:"categoryIds"`
+	// CanonicalCategoryID picks the primary category; 0 means the first one.
+	CanonicalCategoryID int  `json:"canonicalCategoryId"`
+	IsQuestion          bool `json:"isQuestion"`
+	// Draft saves the topic for its author only until it is published.
+	Draft bool `json:"draft"`
+	// TitleQuality is the configured shouting check; staff are exempt.
+	TitleQuality TitleQuality
+	// MinCategories is the configured lower bound on len(CategoryIDs).
+	MinCategories int
+	// SummaryMaxLength is the configured cap on Summary; 0 drops it.
+	SummaryMaxLength int
+	// DuplicateWindow is how far back an identical topic by the same author
+	// counts as a double submission; 0 turns the check off.
+	DuplicateWindow time.Duration
 }
 
 type CreateTopicRequestHandler interface {
@@ -30,17 +55,138 @@ func NewCreateTopicHandler(repo topic.Repository) CreateTopicRequestHandler {
 }
 
 func (h *createTopicRequestHandler) Handle(ctx context.Context, req CreateTopicRequest) (*topic.Topic, error) {
+	err := h.validate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := prepareSummary(req.Summary, req.SummaryMaxLength)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.DuplicateWindow > 0 {
+		existing, err := h.repo.FindRecentDuplicate(ctx, req.User.ID, req.Title, req.Content, req.DuplicateWindow)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return nil, &DuplicateTopicError{Existing: existing}
+		}
+	}
+
+	status := topic.StatusPublished
+	if req.Draft {
+		status = topic.StatusDraft
+	}
+
 	topic := &topic.Topic{
-		UserID:      req.User.ID,
-		CategoryIDs: req.CategoryIDs,
-		Title:       req.Title,
-		Content:     req.Content,
-		ImagePath:   req.ImagePath,
+		UserID:              req.User.ID,
+		CategoryIDs:         req.CategoryIDs,
+		CanonicalCategoryID: canonicalCategory(req.CategoryIDs, req.CanonicalCategoryID),
+		Title:               req.Title,
+		Slug:                helpers.Slugify(req.Title),
+		Content:             req.Content,
+		Summary:             summary,
+		ImagePath:           req.ImagePath,
+		IsQuestion:          req.IsQuestion,
+		Status:              status,
 	}
 
-	err := h.repo.CreateTopic(ctx, topic)
+	err = h.repo.CreateTopic(ctx, topic)
 	if err != nil {
 		return nil, err
 	}
 	return topic, nil
 }
+
+// validate checks the title quality, summary length and category rules of a
+// request: how many categories are selected, whether they exist and whether
+// any of them requires an image. All failures are collected into a single
+// *ValidationError, together with req.FieldErrors.
+func (h *createTopicRequestHandler) validate(ctx context.Context, req CreateTopicRequest) error {
+	validationErr := &ValidationError{}
+
+	for field, message := range req.FieldErrors {
+		validationErr.add(field, ErrInvalidField, message)
+	}
+
+	if req.User == nil || !req.User.IsModerator() {
+		err := checkTitle(req.Title, req.TitleQuality)
+		switch {
+		case errors.Is(err, ErrTitleShouting):
+			validationErr.add("title", err, "please don't write the title in all caps")
+		case errors.Is(err, ErrTitlePunctuationRun):
+			validationErr.add("title", err, "please don't repeat punctuation like \"!!!!\"")
+		}
+	}
+
+	_, err := prepareSummary(req.Summary, req.SummaryMaxLength)
+	if errors.Is(err, ErrSummaryTooLong) {
+		validationErr.add("summary", err,
+			fmt.Sprintf("must be at most %d characters", req.SummaryMaxLength))
+	}
+
+	if len(req.CategoryIDs) < req.MinCategories {
+		validationErr.add("categoryIds", ErrTooFewCategories,
+			fmt.Sprintf("must select at least %d categories", req.MinCategories))
+	}
+
+	if len(req.CategoryIDs) > 0 {
+		existing, err := h.repo.GetExistingCategoryIDs(ctx, req.CategoryIDs)
+		if err != nil {
+			return err
+		}
+		missing := missingIDs(req.CategoryIDs, existing)
+		if len(missing) > 0 {
+			validationErr.add("categoryIds", ErrUnknownCategory,
+				"unknown categories: "+strings.Join(missing, ", "))
+		}
+	}
+
+	if req.CanonicalCategoryID != 0 && !slices.Contains(req.CategoryIDs, req.CanonicalCategoryID) {
+		validationErr.add("canonicalCategoryId", ErrCanonicalNotSelected,
+			"must be one of the selected categories")
+	}
+
+	if req.ImagePath == "" && len(req.CategoryIDs) > 0 {
+		names, err := h.repo.GetCategoriesRequiringImage(ctx, req.CategoryIDs)
+		if err != nil {
+			return err
+		}
+		if len(names) > 0 {
+			validationErr.add("imagePath", ErrImageRequired,
+				fmt.Sprintf("%s: %s", ErrImageRequired, strings.Join(names, ", ")))
+		}
+	}
+
+	if len(validationErr.Fields) > 0 {
+		return validationErr
+	}
+	return nil
+}
+
+// canonicalCategory returns the chosen primary category, defaulting to the
+// first selected one.
+func canonicalCategory(categoryIDs []int, chosen int) int {
+	if chosen != 0 || len(categoryIDs) == 0 {
+		return chosen
+	}
+	return categoryIDs[0]
+}
+
+func missingIDs(requested, existing []int) []string {
+	found := make(map[int]bool, len(existing))
+	for _, id := range existing {
+		found[id] = true
+	}
+
+	missing := make([]string, 0)
+	for _, id := range requested {
+		if !found[id] {
+			missing = append(missing, strconv.Itoa(id))
+			found[id] = true
+		}
+	}
+	return missing
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/arnald/forum/internal/domain/topic"
 	"github.com/arnald/forum/internal/domain/user"
@@ -76,9 +77,211 @@ func newCreateTopicTestCases() []createTopicTestCase {
 			wantTopic: nil,
 			wantError: testhelpers.ErrTest,
 		},
+		{
+			name: "category requires image",
+			request: CreateTopicRequest{
+				User: &user.User{
+					ID:       "test-user-id",
+					Username: "testuser",
+				},
+				Title:       "Test Title",
+				Content:     "Test Content",
+				ImagePath:   "",
+				CategoryIDs: []int{1, 2},
+			},
+			setupMocks: func(repo *testhelpers.MockRepository) {
+				repo.GetExistingCategoryIDsFunc = func(ctx context.Context, categoryIDs []int) ([]int, error) {
+					return categoryIDs, nil
+				}
+				repo.GetCategoriesRequiringImageFunc = func(ctx context.Context, categoryIDs []int) ([]string, error) {
+					return []string{"Gallery"}, nil
+				}
+				repo.CreateTopicFunc = func(ctx context.Context, topic *topic.Topic) error {
+					return nil
+				}
+			},
+			wantTopic: nil,
+			wantError: ErrImageRequired,
+		},
+		{
+			name: "image satisfies category requirement",
+			request: CreateTopicRequest{
+				User: &user.User{
+					ID:       "test-user-id",
+					Username: "testuser",
+				},
+				Title:       "Test Title",
+				Content:     "Test Content",
+				ImagePath:   "/static/images/uploads/test.png",
+				CategoryIDs: []int{1},
+			},
+			setupMocks: func(repo *testhelpers.MockRepository) {
+				repo.GetExistingCategoryIDsFunc = func(ctx context.Context, categoryIDs []int) ([]int, error) {
+					return categoryIDs, nil
+				}
+				repo.CreateTopicFunc = func(ctx context.Context, topic *topic.Topic) error {
+					return nil
+				}
+			},
+			wantTopic: &topic.Topic{
+				UserID:    "test-user-id",
+				Title:     "Test Title",
+				Content:   "Test Content",
+				ImagePath: "/static/images/uploads/test.png",
+			},
+			wantError: nil,
+		},
 	}
 }
 
+func TestCreateTopicHandler_Validate(t *testing.T) {
+	t.Run("reports every broken category rule at once", func(t *testing.T) {
+		repo := &testhelpers.MockRepository{
+			GetExistingCategoryIDsFunc: func(ctx context.Context, categoryIDs []int) ([]int, error) {
+				return []int{1}, nil
+			},
+			GetCategoriesRequiringImageFunc: func(ctx context.Context, categoryIDs []int) ([]string, error) {
+				return []string{"Gallery"}, nil
+			},
+		}
+
+		err := (&createTopicRequestHandler{repo: repo}).validate(context.Background(), CreateTopicRequest{
+			User:          &user.User{ID: "test-user-id"},
+			Title:         "Test Title",
+			Content:       "Test Content",
+			CategoryIDs:   []int{1, 9},
+			MinCategories: 3,
+		})
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("validate() error = %v, want *ValidationError", err)
+		}
+		for _, want := range []error{ErrTooFewCategories, ErrUnknownCategory, ErrImageRequired} {
+			if !errors.Is(err, want) {
+				t.Errorf("validate() error does not wrap %v", want)
+			}
+		}
+		if _, ok := validationErr.Fields["categoryIds"]; !ok {
+			t.Error("validate() missing categoryIds field error")
+		}
+		if _, ok := validationErr.Fields["imagePath"]; !ok {
+			t.Error("validate() missing imagePath field error")
+		}
+	})
+
+	t.Run("caller's field errors are reported with the rules", func(t *testing.T) {
+		var created bool
+		repo := &testhelpers.MockRepository{
+			GetExistingCategoryIDsFunc: func(ctx context.Context, categoryIDs []int) ([]int, error) {
+				return nil, nil
+			},
+			GetCategoriesRequiringImageFunc: func(ctx context.Context, categoryIDs []int) ([]string, error) {
+				return nil, nil
+			},
+			CreateTopicFunc: func(ctx context.Context, topic *topic.Topic) error {
+				created = true
+				return nil
+			},
+		}
+
+		_, err := NewCreateTopicHandler(repo).Handle(context.Background(), CreateTopicRequest{
+			User:        &user.User{ID: "test-user-id"},
+			CategoryIDs: []int{9},
+			FieldErrors: map[string]string{"title": "must be provided"},
+		})
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || !errors.Is(err, ErrInvalidField) || !errors.Is(err, ErrUnknownCategory) {
+			t.Fatalf("Handle() error = %v, want the title and category failures", err)
+		}
+		if validationErr.Fields["title"] != "must be provided" {
+			t.Errorf("Handle() title error = %q, want the caller's message", validationErr.Fields["title"])
+		}
+		if created {
+			t.Error("Handle() created a topic despite the field errors")
+		}
+	})
+
+	t.Run("repository failure is not a validation error", func(t *testing.T) {
+		repo := &testhelpers.MockRepository{}
+
+		err := (&createTopicRequestHandler{repo: repo}).validate(context.Background(), CreateTopicRequest{
+			CategoryIDs: []int{1},
+		})
+
+		var validationErr *ValidationError
+		if errors.As(err, &validationErr) || !errors.Is(err, testhelpers.ErrTest) {
+			t.Errorf("validate() error = %v, want %v", err, testhelpers.ErrTest)
+		}
+	})
+}
+
+func TestCreateTopicHandler_CanonicalCategory(t *testing.T) {
+	newRepo := func(created **topic.Topic) *testhelpers.MockRepository {
+		return &testhelpers.MockRepository{
+			GetExistingCategoryIDsFunc: func(ctx context.Context, categoryIDs []int) ([]int, error) {
+				return categoryIDs, nil
+			},
+			GetCategoriesRequiringImageFunc: func(ctx context.Context, categoryIDs []int) ([]string, error) {
+				return nil, nil
+			},
+			CreateTopicFunc: func(ctx context.Context, topic *topic.Topic) error {
+				*created = topic
+				return nil
+			},
+		}
+	}
+
+	t.Run("defaults to the first selected category", func(t *testing.T) {
+		var created *topic.Topic
+		_, err := NewCreateTopicHandler(newRepo(&created)).Handle(context.Background(), CreateTopicRequest{
+			User:        &user.User{ID: "test-user-id"},
+			CategoryIDs: []int{4, 2},
+		})
+		if err != nil {
+			t.Fatalf("Handle() error = %v", err)
+		}
+		if created.CanonicalCategoryID != 4 {
+			t.Errorf("CanonicalCategoryID = %d, want 4", created.CanonicalCategoryID)
+		}
+	})
+
+	t.Run("keeps the category the author picked", func(t *testing.T) {
+		var created *topic.Topic
+		_, err := NewCreateTopicHandler(newRepo(&created)).Handle(context.Background(), CreateTopicRequest{
+			User:                &user.User{ID: "test-user-id"},
+			CategoryIDs:         []int{4, 2},
+			CanonicalCategoryID: 2,
+		})
+		if err != nil {
+			t.Fatalf("Handle() error = %v", err)
+		}
+		if created.CanonicalCategoryID != 2 {
+			t.Errorf("CanonicalCategoryID = %d, want 2", created.CanonicalCategoryID)
+		}
+	})
+
+	t.Run("rejects a category that was not selected", func(t *testing.T) {
+		var created *topic.Topic
+		_, err := NewCreateTopicHandler(newRepo(&created)).Handle(context.Background(), CreateTopicRequest{
+			User:                &user.User{ID: "test-user-id"},
+			CategoryIDs:         []int{4, 2},
+			CanonicalCategoryID: 7,
+		})
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || !errors.Is(err, ErrCanonicalNotSelected) {
+			t.Fatalf("Handle() error = %v, want %v", err, ErrCanonicalNotSelected)
+		}
+		if _, ok := validationErr.Fields["canonicalCategoryId"]; !ok {
+			t.Error("Handle() missing canonicalCategoryId field error")
+		}
+		if created != nil {
+			t.Error("Handle() created a topic despite the validation error")
+		}
+	})
+}
+
 func runCreateTopicTest(tt createTopicTestCase) func(t *testing.T) {
 	return func(t *testing.T) {
 		repo := &testhelpers.MockRepository{}
@@ -103,3 +306,63 @@ func TestNewTopicHandler(t *testing.T) {
 		t.Fatal("expected non-nil handler")
 	}
 }
+
+func TestCreateTopicHandler_Duplicate(t *testing.T) {
+	author := &user.User{ID: "author-id", Username: "author"}
+
+	t.Run("identical topic within the window is refused", func(t *testing.T) {
+		created := false
+		repo := &testhelpers.MockRepository{
+			FindRecentDuplicateFunc: func(_ context.Context, userID, title, content string, _ time.Duration) (*topic.Topic, error) {
+				if userID != author.ID || title != "Same title" || content != "Same content" {
+					t.Errorf("FindRecentDuplicate(%q, %q, %q), want the request's author, title and content", userID, title, content)
+				}
+				return &topic.Topic{ID: 42, Slug: "same-title"}, nil
+			},
+			CreateTopicFunc: func(_ context.Context, _ *topic.Topic) error {
+				created = true
+				return nil
+			},
+		}
+
+		_, err := NewCreateTopicHandler(repo).Handle(context.Background(), CreateTopicRequest{
+			User:            author,
+			Title:           "Same title",
+			Content:         "Same content",
+			DuplicateWindow: 30 * time.Second,
+		})
+
+		var dupErr *DuplicateTopicError
+		if !errors.As(err, &dupErr) || dupErr.Existing.ID != 42 {
+			t.Fatalf("Handle() error = %v, want a DuplicateTopicError for topic 42", err)
+		}
+		if !errors.Is(err, ErrDuplicateTopic) {
+			t.Errorf("Handle() error does not match ErrDuplicateTopic")
+		}
+		if created {
+			t.Error("Handle() created the duplicate")
+		}
+	})
+
+	t.Run("no recent duplicate creates the topic", func(t *testing.T) {
+		repo := &testhelpers.MockRepository{
+			FindRecentDuplicateFunc: func(_ context.Context, _, _, _ string, _ time.Duration) (*topic.Topic, error) {
+				return nil, nil
+			},
+			CreateTopicFunc: func(_ context.Context, created *topic.Topic) error {
+				created.ID = 43
+				return nil
+			},
+		}
+
+		got, err := NewCreateTopicHandler(repo).Handle(context.Background(), CreateTopicRequest{
+			User:            author,
+			Title:           "Same title",
+			Content:         "Same content",
+			DuplicateWindow: 30 * time.Second,
+		})
+		if err != nil || got.ID != 43 {
+			t.Fatalf("Handle() = %+v, %v, want topic 43", got, err)
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package topiccommands
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/arnald/forum/internal/domain/topic"
+)
+
+var (
+	ErrImageRequired        = errors.New("an image is required by the selected category")
+	ErrTooFewCategories     = errors.New("too few categories selected")
+	ErrUnknownCategory      = errors.New("category does not exist")
+	ErrCanonicalNotSelected = errors.New("canonical category is not one of the selected categories")
+	ErrTitleShouting        = errors.New("title is mostly uppercase")
+	ErrTitlePunctuationRun  = errors.New("title repeats punctuation")
+	ErrNotQuestion          = errors.New("topic is not a question")
+	ErrNotQuestionAuthor    = errors.New("only the question's author or staff can accept an answer")
+	ErrAnswerNotApproved    = errors.New("only approved comments can be accepted")
+	ErrSummaryTooLong       = errors.New("summary is too long")
+	ErrDuplicateTopic       = errors.New("this topic was just posted")
+	ErrInvalidField         = errors.New("field is invalid")
+)
+
+// DuplicateTopicError refuses a topic its author already created moments ago,
+// typically by submitting the form twice. Existing is the topic to send them
+// to instead.
+type DuplicateTopicError struct {
+	Existing *topic.Topic
+}
+
+func (e *DuplicateTopicError) Error() string {
+	return fmt.Sprintf("%s as topic %d", ErrDuplicateTopic, e.Existing.ID)
+}
+
+func (e *DuplicateTopicError) Unwrap() error {
+	return ErrDuplicateTopic
+}
+
+// ValidationError reports every rule a topic request breaks at once, keyed by
+// the json name of the offending field. It unwraps to the sentinel errors
+// above so callers can still match a specific failure with errors.Is.
+type ValidationError struct {
+	Fields map[string]string
+	errs   []error
+}
+
+func (e *ValidationError) add(field string, err error, message string) {
+	if e.Fields == nil {
+		e.Fields = make(map[string]string)
+	}
+	if _, exists := e.Fields[field]; !exists {
+		e.Fields[field] = message
+	}
+	e.errs = append(e.errs, err)
+}
+
+func (e *ValidationError) Error() string {
+	keys := make([]string, 0, len(e.Fields))
+	for key := range e.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = key + ": " + e.Fields[key]
+	}
+	return strings.Join(parts, " ")
+}
+
+func (e *ValidationError) Unwrap() []error {
+	return e.errs
+}
@@ -0,0 +1,31 @@
+package topiccommands
+
+import (
+	"context"
+
+	"github.com/arnald/forum/internal/domain/topic"
+)
+
+// PublishTopicRequest makes one of the user's drafts visible to everyone.
+type PublishTopicRequest struct {
+	UserID  string `json:"userId"`
+	TopicID int    `json:"topicId"`
+}
+
+type PublishTopicRequestHandler interface {
+	Handle(ctx context.Context, req PublishTopicRequest) error
+}
+
+type publishTopicRequestHandler struct {
+	repo topic.Repository
+}
+
+func NewPublishTopicHandler(repo topic.Repository) PublishTopicRequestHandler {
+	return &publishTopicRequestHandler{
+		repo: repo,
+	}
+}
+
+func (h *publishTopicRequestHandler) Handle(ctx context.Context, req PublishTopicRequest) error {
+	return h.repo.PublishTopic(ctx, req.UserID, req.TopicID)
+}
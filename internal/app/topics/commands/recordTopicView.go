@@ -0,0 +1,34 @@
+package topiccommands
+
+import (
+	"context"
+	"time"
+
+	"github.com/arnald/forum/internal/domain/topic"
+)
+
+// RecordTopicViewRequest counts a view of a topic. ViewerKey identifies the
+// viewer; their repeat views within Window count once.
+type RecordTopicViewRequest struct {
+	ViewerKey string
+	TopicID   int
+	Window    time.Duration
+}
+
+type RecordTopicViewRequestHandler interface {
+	Handle(ctx context.Context, req RecordTopicViewRequest) (bool, error)
+}
+
+type recordTopicViewRequestHandler struct {
+	repo topic.Repository
+}
+
+func NewRecordTopicViewHandler(repo topic.Repository) RecordTopicViewRequestHandler {
+	return &recordTopicViewRequestHandler{
+		repo: repo,
+	}
+}
+
+func (h *recordTopicViewRequestHandler) Handle(ctx context.Context, req RecordTopicViewRequest) (bool, error) {
+	return h.repo.IncrementTopicView(ctx, req.TopicID, req.ViewerKey, req.Window)
+}
@@ -0,0 +1,30 @@
+package topiccommands
+
+import (
+	"context"
+
+	"github.com/arnald/forum/internal/domain/topic"
+)
+
+// RestoreTopicRequest undoes the soft delete of a topic.
+type RestoreTopicRequest struct {
+	TopicID int `json:"topicId"`
+}
+
+type RestoreTopicRequestHandler interface {
+	Handle(ctx context.Context, req RestoreTopicRequest) error
+}
+
+type restoreTopicRequestHandler struct {
+	repo topic.Repository
+}
+
+func NewRestoreTopicHandler(repo topic.Repository) RestoreTopicRequestHandler {
+	return &restoreTopicRequestHandler{
+		repo: repo,
+	}
+}
+
+func (h *restoreTopicRequestHandler) Handle(ctx context.Context, req RestoreTopicRequest) error {
+	return h.repo.RestoreTopic(ctx, req.TopicID)
+}
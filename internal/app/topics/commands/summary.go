@@ -0,0 +1,31 @@
+package topiccommands
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// summaryMarkup matches anything tag-like. Summaries are shown as plain text
+// in listings, so markup is dropped rather than escaped.
+var summaryMarkup = regexp.MustCompile(`<[^>]*>`)
+
+// cleanSummary strips markup from a topic summary and collapses whitespace
+// so line breaks don't survive into the one-line excerpt.
+func cleanSummary(summary string) string {
+	return strings.Join(strings.Fields(summaryMarkup.ReplaceAllString(summary, " ")), " ")
+}
+
+// prepareSummary returns the cleaned summary, or ErrSummaryTooLong when it
+// has more than maxLength characters. A maxLength of 0 means summaries are
+// turned off and any submitted one is dropped.
+func prepareSummary(summary string, maxLength int) (string, error) {
+	if maxLength <= 0 {
+		return "", nil
+	}
+	cleaned := cleanSummary(summary)
+	if utf8.RuneCountInString(cleaned) > maxLength {
+		return "", ErrSummaryTooLong
+	}
+	return cleaned, nil
+}
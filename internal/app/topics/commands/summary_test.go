@@ -0,0 +1,49 @@
+package topiccommands
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPrepareSummary(t *testing.T) {
+	t.Run("group: topic summary", func(t *testing.T) {
+		testCases := newPrepareSummaryTestCases()
+		for _, tt := range testCases {
+			t.Run(tt.name, runPrepareSummaryTest(tt))
+		}
+	})
+}
+
+type prepareSummaryTestCase struct {
+	wantError error
+	name      string
+	summary   string
+	want      string
+	maxLength int
+}
+
+func newPrepareSummaryTestCases() []prepareSummaryTestCase {
+	return []prepareSummaryTestCase{
+		{name: "empty summary", summary: "", want: "", maxLength: 300},
+		{name: "plain summary is kept", summary: "Use WAL mode.", want: "Use WAL mode.", maxLength: 300},
+		{name: "markup is stripped", summary: "<b>Use</b> <script>alert(1)</script>WAL", want: "Use alert(1) WAL", maxLength: 300},
+		{name: "whitespace is collapsed", summary: "  line one\n\n line two ", want: "line one line two", maxLength: 300},
+		{name: "length counts characters not bytes", summary: strings.Repeat("é", 10), want: strings.Repeat("é", 10), maxLength: 10},
+		{name: "too long", summary: strings.Repeat("a", 11), maxLength: 10, wantError: ErrSummaryTooLong},
+		{name: "stripped markup does not count", summary: "<em>" + strings.Repeat("a", 10) + "</em>", want: strings.Repeat("a", 10), maxLength: 10},
+		{name: "disabled drops the summary", summary: "ignored", want: "", maxLength: 0},
+	}
+}
+
+func runPrepareSummaryTest(tt prepareSummaryTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		got, err := prepareSummary(tt.summary, tt.maxLength)
+		if !errors.Is(err, tt.wantError) {
+			t.Fatalf("prepareSummary(%q) error = %v, want %v", tt.summary, err, tt.wantError)
+		}
+		if got != tt.want {
+			t.Errorf("prepareSummary(%q) = %q, want %q", tt.summary, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package topiccommands
+
+import (
+	"strings"
+	"unicode"
+)
+
+// minShoutingLetters keeps titles that are little more than an acronym, like
+// "NASA API", from tripping the uppercase check.
+const minShoutingLetters = 8
+
+// TitleQuality holds the configured limits on shouty titles. Zero disables a
+// check.
+type TitleQuality struct {
+	// MaxUppercasePercent is the share of letters allowed to be uppercase.
+	MaxUppercasePercent int
+	// MaxPunctuationRun is the longest run of punctuation allowed, so "..."
+	// passes with 3 while "!!!!" does not.
+	MaxPunctuationRun int
+}
+
+// checkTitle reports the first title-quality rule the title breaks, or nil.
+// Whitespace is collapsed first so "! ! ! !" counts as a run too.
+func checkTitle(title string, limits TitleQuality) error {
+	normalized := strings.Join(strings.Fields(title), "")
+
+	if limits.MaxUppercasePercent > 0 {
+		letters, upper := 0, 0
+		for _, r := range normalized {
+			if !unicode.IsLetter(r) {
+				continue
+			}
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+		if letters >= minShoutingLetters && upper*100 > letters*limits.MaxUppercasePercent {
+			return ErrTitleShouting
+		}
+	}
+
+	if limits.MaxPunctuationRun > 0 {
+		run := 0
+		for _, r := range normalized {
+			if !unicode.IsPunct(r) {
+				run = 0
+				continue
+			}
+			run++
+			if run > limits.MaxPunctuationRun {
+				return ErrTitlePunctuationRun
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package topiccommands
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/arnald/forum/internal/domain/user"
+	testhelpers "github.com/arnald/forum/internal/pkg/testing"
+)
+
+func TestCheckTitle(t *testing.T) {
+	t.Run("group: title quality", func(t *testing.T) {
+		testCases := newCheckTitleTestCases()
+		for _, tt := range testCases {
+			t.Run(tt.name, runCheckTitleTest(tt))
+		}
+	})
+}
+
+type checkTitleTestCase struct {
+	wantError error
+	name      string
+	title     string
+}
+
+func newCheckTitleTestCases() []checkTitleTestCase {
+	return []checkTitleTestCase{
+		{name: "ordinary title", title: "How do I set up the forum locally?"},
+		{name: "all caps", title: "BUY CHEAP WATCHES NOW", wantError: ErrTitleShouting},
+		{name: "a bare acronym is not shouting", title: "NASA API?"},
+		{name: "repeated exclamation marks", title: "Buy now!!!!", wantError: ErrTitlePunctuationRun},
+		{name: "spaced out punctuation is still a run", title: "Buy now ! ! ! !", wantError: ErrTitlePunctuationRun},
+		{name: "ellipsis is allowed", title: "Well... that happened"},
+	}
+}
+
+func runCheckTitleTest(tt checkTitleTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		err := checkTitle(tt.title, TitleQuality{MaxUppercasePercent: 70, MaxPunctuationRun: 3})
+		if !errors.Is(err, tt.wantError) {
+			t.Errorf("checkTitle(%q) error = %v, want %v", tt.title, err, tt.wantError)
+		}
+	}
+}
+
+func TestCreateTopicHandler_TitleQuality(t *testing.T) {
+	limits := TitleQuality{MaxUppercasePercent: 70, MaxPunctuationRun: 3}
+	handler := &createTopicRequestHandler{repo: &testhelpers.MockRepository{}}
+
+	t.Run("rejects a shouty title", func(t *testing.T) {
+		err := handler.validate(context.Background(), CreateTopicRequest{
+			User:         &user.User{ID: "test-user-id"},
+			Title:        "BUY NOW!!!!",
+			TitleQuality: limits,
+		})
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("validate() error = %v, want a *ValidationError", err)
+		}
+		if _, ok := validationErr.Fields["title"]; !ok {
+			t.Error("validate() missing title field error")
+		}
+	})
+
+	t.Run("staff are exempt", func(t *testing.T) {
+		err := handler.validate(context.Background(), CreateTopicRequest{
+			User:         &user.User{ID: "mod-id", Role: user.RoleModerator},
+			Title:        "READ BEFORE POSTING!!!!",
+			TitleQuality: limits,
+		})
+		if err != nil {
+			t.Errorf("validate() error = %v, want nil", err)
+		}
+	})
+}
@@ -0,0 +1,32 @@
+package topiccommands
+
+import (
+	"context"
+
+	"github.com/arnald/forum/internal/domain/topic"
+)
+
+// ToggleBookmarkRequest saves a topic for later, or unsaves it if the user
+// already bookmarked it.
+type ToggleBookmarkRequest struct {
+	UserID  string
+	TopicID int
+}
+
+type ToggleBookmarkRequestHandler interface {
+	Handle(ctx context.Context, req ToggleBookmarkRequest) (bool, error)
+}
+
+type toggleBookmarkRequestHandler struct {
+	repo topic.Repository
+}
+
+func NewToggleBookmarkHandler(repo topic.Repository) ToggleBookmarkRequestHandler {
+	return &toggleBookmarkRequestHandler{
+		repo: repo,
+	}
+}
+
+func (h *toggleBookmarkRequestHandler) Handle(ctx context.Context, req ToggleBookmarkRequest) (bool, error) {
+	return h.repo.ToggleBookmark(ctx, req.UserID, req.TopicID)
+}
@@ -2,18 +2,35 @@ package topiccommands
 
 import (
 	"context"
+	"slices"
+	"time"
 
 	"github.com/arnald/forum/internal/domain/topic"
 	"github.com/arnald/forum/internal/domain/user"
+	"github.com/arnald/forum/internal/pkg/helpers"
 )
 
+// BumpPolicy decides whether an edit moves the topic back to the top of
+// listings. It is off unless the server enables it.
+type BumpPolicy struct {
+	MinEditChars int
+	Enabled      bool
+}
+
 type UpdateTopicRequest struct {
 	User        *user.User
 	Title       string `json:"title"`
 	Content     string `json:"content"`
+	Summary     string `json:"summary"`
 	ImagePath   string `json:"imagePath"`
 	CategoryIDs []int  `json:"categoryIds"`
 	TopicID     int    `json:"topicId"`
+	// CanonicalCategoryID picks the primary category; 0 means the first one.
+	CanonicalCategoryID int `json:"canonicalCategoryId"`
+	// SummaryMaxLength is the configured cap on Summary; 0 drops it.
+	SummaryMaxLength int
+	Bump             BumpPolicy
+	IsQuestion       bool `json:"isQuestion"`
 }
 
 type UpdateTopicRequestHandler interface {
@@ -31,18 +48,71 @@ func NewUpdateTopicHandler(repo topic.Repository) UpdateTopicRequestHandler {
 }
 
 func (h *updateTopicRequestHandler) Handle(ctx context.Context, req UpdateTopicRequest) (*topic.Topic, error) {
+	if req.CanonicalCategoryID != 0 && !slices.Contains(req.CategoryIDs, req.CanonicalCategoryID) {
+		return nil, ErrCanonicalNotSelected
+	}
+
+	summary, err := prepareSummary(req.Summary, req.SummaryMaxLength)
+	if err != nil {
+		return nil, err
+	}
+
 	topic := &topic.Topic{
-		UserID:      req.User.ID,
-		CategoryIDs: req.CategoryIDs,
-		ID:          req.TopicID,
-		Title:       req.Title,
-		Content:     req.Content,
-		ImagePath:   req.ImagePath,
+		UserID:              req.User.ID,
+		CategoryIDs:         req.CategoryIDs,
+		CanonicalCategoryID: canonicalCategory(req.CategoryIDs, req.CanonicalCategoryID),
+		ID:                  req.TopicID,
+		Title:               req.Title,
+		Slug:                helpers.Slugify(req.Title),
+		Content:             req.Content,
+		Summary:             summary,
+		ImagePath:           req.ImagePath,
+		IsQuestion:          req.IsQuestion,
 	}
 
-	err := h.repo.UpdateTopic(ctx, topic)
+	if req.Bump.Enabled {
+		existing, err := h.repo.GetTopicByID(ctx, req.TopicID, nil)
+		if err != nil {
+			return nil, err
+		}
+		if isSignificantEdit(existing, topic, req.Bump.MinEditChars) {
+			topic.BumpedAt = time.Now().UTC().Format(time.DateTime)
+		}
+	}
+
+	err = h.repo.UpdateTopic(ctx, topic)
 	if err != nil {
 		return nil, err
 	}
 	return topic, nil
 }
+
+// isSignificantEdit treats any title change as significant; content edits
+// only count once they touch at least minChars characters.
+func isSignificantEdit(before, after *topic.Topic, minChars int) bool {
+	if before.Title != after.Title {
+		return true
+	}
+	if before.Content == after.Content {
+		return false
+	}
+	return editedSpan(before.Content, after.Content) >= minChars
+}
+
+// editedSpan returns the length of the changed region between two strings,
+// ignoring the prefix and suffix they share.
+func editedSpan(before, after string) int {
+	a, b := []rune(before), []rune(after)
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	return max(len(a), len(b)) - prefix - suffix
+}
@@ -105,3 +105,51 @@ func TestNewUpdateTopicHandler(t *testing.T) {
 		t.Fatal("expected non-nil handler")
 	}
 }
+
+func TestUpdateTopicHandler_Bump(t *testing.T) {
+	existing := &topic.Topic{ID: 1, Title: "Title", Content: "Some content here"}
+
+	testCases := []struct {
+		name     string
+		title    string
+		content  string
+		policy   BumpPolicy
+		wantBump bool
+	}{
+		{name: "disabled never bumps", title: "New title", content: "Other", policy: BumpPolicy{}, wantBump: false},
+		{name: "trivial edit does not bump", title: "Title", content: "Some content here.", policy: BumpPolicy{Enabled: true, MinEditChars: 5}, wantBump: false},
+		{name: "large edit bumps", title: "Title", content: "Some rewritten content here", policy: BumpPolicy{Enabled: true, MinEditChars: 5}, wantBump: true},
+		{name: "title change bumps", title: "Title!", content: "Some content here", policy: BumpPolicy{Enabled: true, MinEditChars: 5}, wantBump: true},
+		{name: "unchanged topic does not bump", title: "Title", content: "Some content here", policy: BumpPolicy{Enabled: true}, wantBump: false},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved *topic.Topic
+			repo := &testhelpers.MockRepository{
+				GetTopicByIDFunc: func(ctx context.Context, topicID int, userID *string) (*topic.Topic, error) {
+					return existing, nil
+				},
+				UpdateTopicFunc: func(ctx context.Context, topic *topic.Topic) error {
+					saved = topic
+					return nil
+				},
+			}
+
+			_, err := NewUpdateTopicHandler(repo).Handle(context.Background(), UpdateTopicRequest{
+				User:    &user.User{ID: "test-user-id"},
+				TopicID: 1,
+				Title:   tt.title,
+				Content: tt.content,
+				Bump:    tt.policy,
+			})
+			if err != nil {
+				t.Fatalf("Handle() unexpected error: %v", err)
+			}
+
+			if gotBump := saved.BumpedAt != ""; gotBump != tt.wantBump {
+				t.Errorf("bumped = %v, want %v", gotBump, tt.wantBump)
+			}
+		})
+	}
+}
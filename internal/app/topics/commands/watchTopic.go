@@ -0,0 +1,36 @@
+package topiccommands
+
+import (
+	"context"
+
+	"github.com/arnald/forum/internal/domain/topic"
+)
+
+// WatchTopicRequest subscribes a user to new comments on a topic, or drops
+// the subscription when Watch is false.
+type WatchTopicRequest struct {
+	UserID  string
+	TopicID int
+	Watch   bool
+}
+
+type WatchTopicRequestHandler interface {
+	Handle(ctx context.Context, req WatchTopicRequest) error
+}
+
+type watchTopicRequestHandler struct {
+	repo topic.Repository
+}
+
+func NewWatchTopicHandler(repo topic.Repository) WatchTopicRequestHandler {
+	return &watchTopicRequestHandler{
+		repo: repo,
+	}
+}
+
+func (h *watchTopicRequestHandler) Handle(ctx context.Context, req WatchTopicRequest) error {
+	if req.Watch {
+		return h.repo.WatchTopic(ctx, req.UserID, req.TopicID)
+	}
+	return h.repo.UnwatchTopic(ctx, req.UserID, req.TopicID)
+}
@@ -2,4 +2,10 @@ package topicqueries
 
 import "errors"
 
-var ErrTopicNotFound = errors.New("topic not found")
+var (
+	ErrTopicNotFound = errors.New("topic not found")
+	// ErrListNeedsUser is returned when bookmarked or draft topics are
+	// requested without a user to look them up for.
+	ErrListNeedsUser     = errors.New("listing bookmarks or drafts requires a user")
+	ErrHistoryNotAllowed = errors.New("only the topic's author or staff can view its history")
+)
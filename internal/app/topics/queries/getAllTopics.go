@@ -8,20 +8,34 @@ import (
 )
 
 type GetAllTopicsRequest struct {
-	UserID     *string `json:"userId,omitempty"`
-	OrderBy    string  `json:"orderBy"`
-	Order      string  `json:"order"`
-	Filter     string  `json:"filter"`
-	Page       int     `json:"page"`
-	Size       int     `json:"size"`
-	Offset     int     `json:"offset"`
-	CategoryID int     `json:"categoryId"`
+	UserID      *string                  `json:"userId,omitempty"`
+	OrderBy     string                   `json:"orderBy"`
+	Order       string                   `json:"order"`
+	Filter      string                   `json:"filter"`
+	Controversy topic.ControversyWeights `json:"-"`
+	Page        int                      `json:"page"`
+	Size        int                      `json:"size"`
+	Offset      int                      `json:"offset"`
+	CategoryID  int                      `json:"categoryId"`
+	// Before is an optional topic id cursor. When set, Page is ignored and
+	// the listing continues with topics older than that id.
+	Before int `json:"before"`
+	// Bookmarked pages through the topics UserID bookmarked, newest bookmark
+	// first, in place of the regular listing.
+	Bookmarked bool `json:"bookmarked"`
+	// Drafts pages through UserID's unpublished topics, most recently edited
+	// first, in place of the regular listing.
+	Drafts bool `json:"drafts"`
 }
 
 type GetAllTopicsResponse struct {
 	Topics     []topic.Topic
 	Categories []category.Category
 	Count      int
+	// NextBefore is the cursor for the following page, or zero when HasMore
+	// is false.
+	NextBefore int
+	HasMore    bool
 }
 type GetAllTopicsRequestHandler interface {
 	Handle(ctx context.Context, req GetAllTopicsRequest) (*GetAllTopicsResponse, error)
@@ -40,17 +54,40 @@ func NewGetAllTopicsHandler(topicRepo topic.Repository, categoryRepo category.Re
 }
 
 func (h getAllTopicsRequestHandler) Handle(ctx context.Context, req GetAllTopicsRequest) (*GetAllTopicsResponse, error) {
+	if req.Bookmarked {
+		return h.getUserTopics(ctx, req, h.topicRepo.GetBookmarkedTopics)
+	}
+	if req.Drafts {
+		return h.getUserTopics(ctx, req, h.topicRepo.GetDraftTopics)
+	}
+
 	count, err := h.topicRepo.GetTotalTopicsCount(ctx, req.Filter, req.CategoryID)
 	if err != nil {
 		return nil, err
 	}
 
-	topics, err := h.topicRepo.GetAllTopics(ctx, req.Page, req.Size, req.CategoryID, req.OrderBy, req.Order, req.Filter, req.UserID)
-	if err != nil {
-		return nil, err
+	var topics []topic.Topic
+	var hasMore bool
+	if req.Before > 0 {
+		// Ask for one extra row so the next page is known to exist without
+		// counting what is left.
+		topics, err = h.topicRepo.GetAllTopics(ctx, 1, req.Size+1, req.Before, req.CategoryID, req.OrderBy, req.Order, req.Filter, req.UserID, req.Controversy)
+		if err != nil {
+			return nil, err
+		}
+		hasMore = len(topics) > req.Size
+		if hasMore {
+			topics = topics[:req.Size]
+		}
+	} else {
+		topics, err = h.topicRepo.GetAllTopics(ctx, req.Page, req.Size, 0, req.CategoryID, req.OrderBy, req.Order, req.Filter, req.UserID, req.Controversy)
+		if err != nil {
+			return nil, err
+		}
+		hasMore = req.Page*req.Size < count
 	}
 
-	categories, err := h.categoryRepo.GetAllCategorieNamesAndIDs(ctx)
+	categories, err := h.categoryRepo.GetCategoriesWithCounts(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -59,7 +96,42 @@ func (h getAllTopicsRequestHandler) Handle(ctx context.Context, req GetAllTopics
 		Topics:     topics,
 		Count:      count,
 		Categories: categories,
+		HasMore:    hasMore && len(topics) > 0,
+	}
+	if response.HasMore {
+		response.NextBefore = topics[len(topics)-1].ID
 	}
 
 	return response, nil
 }
+
+// getUserTopics pages through a per-user list, such as bookmarks or drafts,
+// that list returns in full.
+func (h getAllTopicsRequestHandler) getUserTopics(
+	ctx context.Context,
+	req GetAllTopicsRequest,
+	list func(ctx context.Context, userID string) ([]topic.Topic, error),
+) (*GetAllTopicsResponse, error) {
+	if req.UserID == nil {
+		return nil, ErrListNeedsUser
+	}
+
+	topics, err := list(ctx, *req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := h.categoryRepo.GetCategoriesWithCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	start := min(max(req.Page-1, 0)*req.Size, len(topics))
+	end := min(start+req.Size, len(topics))
+
+	return &GetAllTopicsResponse{
+		Topics:     topics[start:end],
+		Count:      len(topics),
+		Categories: categories,
+	}, nil
+}
@@ -0,0 +1,121 @@
+package topicqueries
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/arnald/forum/internal/domain/category"
+	"github.com/arnald/forum/internal/domain/topic"
+	testhelpers "github.com/arnald/forum/internal/pkg/testing"
+)
+
+// stubCategoryRepo satisfies category.Repository for handlers that only list
+// category names.
+type stubCategoryRepo struct {
+	category.Repository
+}
+
+func (stubCategoryRepo) GetCategoriesWithCounts(_ context.Context) ([]category.Category, error) {
+	return nil, nil
+}
+
+func TestGetAllTopicsHandler_Cursor(t *testing.T) {
+	t.Run("group: cursor pagination", func(t *testing.T) {
+		testCases := newGetAllTopicsCursorTestCases()
+		for _, tt := range testCases {
+			t.Run(tt.name, runGetAllTopicsCursorTest(tt))
+		}
+	})
+}
+
+type getAllTopicsCursorTestCase struct {
+	name           string
+	wantIDs        []int
+	page           int
+	before         int
+	wantNextBefore int
+	wantHasMore    bool
+}
+
+func newGetAllTopicsCursorTestCases() []getAllTopicsCursorTestCase {
+	return []getAllTopicsCursorTestCase{
+		{
+			name:           "first page",
+			page:           1,
+			wantIDs:        []int{5, 4},
+			wantNextBefore: 4,
+			wantHasMore:    true,
+		},
+		{
+			name:           "middle page",
+			before:         4,
+			wantIDs:        []int{3, 2},
+			wantNextBefore: 2,
+			wantHasMore:    true,
+		},
+		{
+			name:    "final page",
+			before:  2,
+			wantIDs: []int{1},
+		},
+		{
+			name:    "final offset page",
+			page:    3,
+			wantIDs: []int{1},
+		},
+	}
+}
+
+// newestFirstTopics serves topic ids 5 down to 1 the way the repository would
+// for a newest-first listing.
+func newestFirstTopics(_ context.Context, page, size, beforeID, _ int, _, _, _ string, _ *string, _ topic.ControversyWeights) ([]topic.Topic, error) {
+	all := make([]topic.Topic, 0, 5)
+	for id := 5; id >= 1; id-- {
+		if beforeID > 0 && id >= beforeID {
+			continue
+		}
+		all = append(all, topic.Topic{ID: id})
+	}
+
+	start := min((page-1)*size, len(all))
+	end := min(start+size, len(all))
+	return all[start:end], nil
+}
+
+func runGetAllTopicsCursorTest(tt getAllTopicsCursorTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		repo := &testhelpers.MockRepository{
+			GetAllTopicsFunc: newestFirstTopics,
+			GetTotalTopicsCountFunc: func(_ context.Context, _ string, _ int) (int, error) {
+				return 5, nil
+			},
+		}
+		handler := NewGetAllTopicsHandler(repo, stubCategoryRepo{})
+
+		got, err := handler.Handle(context.Background(), GetAllTopicsRequest{
+			OrderBy: "created_at",
+			Order:   "desc",
+			Page:    tt.page,
+			Size:    2,
+			Before:  tt.before,
+		})
+		if err != nil {
+			t.Fatalf("Handle() error = %v", err)
+		}
+
+		ids := make([]int, 0, len(got.Topics))
+		for _, tp := range got.Topics {
+			ids = append(ids, tp.ID)
+		}
+		if !slices.Equal(ids, tt.wantIDs) {
+			t.Errorf("Handle() ids = %v, want %v", ids, tt.wantIDs)
+		}
+		if got.HasMore != tt.wantHasMore {
+			t.Errorf("Handle() hasMore = %v, want %v", got.HasMore, tt.wantHasMore)
+		}
+		if got.NextBefore != tt.wantNextBefore {
+			t.Errorf("Handle() nextBefore = %d, want %d", got.NextBefore, tt.wantNextBefore)
+		}
+	}
+}
@@ -2,14 +2,21 @@ package topicqueries
 
 import (
 	"context"
+	"errors"
+	"slices"
 
 	"github.com/arnald/forum/internal/domain/comment"
 	"github.com/arnald/forum/internal/domain/topic"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/comments"
 )
 
 type GetTopicRequest struct {
 	UserID  *string `json:"userId"`
 	TopicID int     `json:"topicId"`
+	// CommentLimit caps how many comments are loaded, starting at
+	// CommentOffset; zero loads them all.
+	CommentLimit  int `json:"commentLimit"`
+	CommentOffset int `json:"commentOffset"`
 }
 
 type GetTopicRequestHandler interface {
@@ -29,17 +36,49 @@ func NewGetTopicHandler(topicRepo topic.Repository, commentRepo comment.Reposito
 }
 
 func (h *getTopicRequestHandler) Handle(ctx context.Context, req GetTopicRequest) (*topic.Topic, error) {
-	topic, err := h.topicRepo.GetTopicByID(ctx, req.TopicID, req.UserID)
+	topic, err := ResolveTopicReference(ctx, h.topicRepo, req.TopicID, req.UserID)
 	if err != nil {
 		return nil, err
 	}
+	if topic.Removed {
+		return topic, nil
+	}
+
+	if req.CommentLimit <= 0 && req.CommentOffset <= 0 {
+		all, err := h.commentRepo.GetCommentsWithVotes(ctx, req.TopicID, req.UserID)
+		if err != nil {
+			return nil, err
+		}
+		topic.Comments = all
+		topic.CommentCount = len(all)
 
-	comments, err := h.commentRepo.GetCommentsWithVotes(ctx, req.TopicID, req.UserID)
+		return topic, nil
+	}
+
+	page, err := h.commentRepo.GetCommentsWithVotesPage(ctx, req.TopicID, req.UserID, req.CommentLimit, req.CommentOffset)
 	if err != nil {
 		return nil, err
 	}
+	count, err := h.commentRepo.CountVisibleComments(ctx, req.TopicID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The accepted answer always opens the first page, even when it was
+	// posted too late to fall on it.
+	if req.CommentOffset <= 0 && topic.AcceptedCommentID != 0 &&
+		!slices.ContainsFunc(page, func(c comment.Comment) bool { return c.ID == topic.AcceptedCommentID }) {
+		accepted, acceptedErr := h.commentRepo.GetCommentWithVotes(ctx, req.TopicID, topic.AcceptedCommentID, req.UserID)
+		switch {
+		case acceptedErr == nil:
+			page = append([]comment.Comment{*accepted}, page...)
+		case !errors.Is(acceptedErr, comments.ErrCommentNotFound):
+			return nil, acceptedErr
+		}
+	}
 
-	topic.Comments = comments
+	topic.Comments = page
+	topic.CommentCount = count
 
 	return topic, nil
 }
@@ -0,0 +1,42 @@
+package topicqueries
+
+import (
+	"context"
+
+	"github.com/arnald/forum/internal/domain/topic"
+	"github.com/arnald/forum/internal/domain/user"
+)
+
+// GetTopicHistoryRequest asks for the earlier versions of a topic.
+type GetTopicHistoryRequest struct {
+	User    *user.User
+	TopicID int
+}
+
+type GetTopicHistoryRequestHandler interface {
+	Handle(ctx context.Context, req GetTopicHistoryRequest) ([]topic.Revision, error)
+}
+
+type getTopicHistoryRequestHandler struct {
+	repo topic.Repository
+}
+
+func NewGetTopicHistoryHandler(repo topic.Repository) GetTopicHistoryRequestHandler {
+	return &getTopicHistoryRequestHandler{
+		repo: repo,
+	}
+}
+
+// Handle lists the topic's revisions, newest first. Only the topic's author
+// or staff may read them.
+func (h *getTopicHistoryRequestHandler) Handle(ctx context.Context, req GetTopicHistoryRequest) ([]topic.Revision, error) {
+	existing, err := h.repo.GetTopicByID(ctx, req.TopicID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if existing.UserID != req.User.ID && !req.User.IsModerator() {
+		return nil, ErrHistoryNotAllowed
+	}
+
+	return h.repo.GetTopicRevisions(ctx, req.TopicID)
+}
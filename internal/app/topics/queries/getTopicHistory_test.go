@@ -0,0 +1,91 @@
+package topicqueries
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/arnald/forum/internal/domain/topic"
+	"github.com/arnald/forum/internal/domain/user"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/topics"
+	testhelpers "github.com/arnald/forum/internal/pkg/testing"
+)
+
+func TestGetTopicHistory(t *testing.T) {
+	t.Run("group: topic history access", func(t *testing.T) {
+		testCases := newGetTopicHistoryTestCases()
+		for _, tt := range testCases {
+			t.Run(tt.name, runGetTopicHistoryTest(tt))
+		}
+	})
+}
+
+type getTopicHistoryTestCase struct {
+	user      *user.User
+	wantError error
+	name      string
+	wantCount int
+	topicID   int
+}
+
+func newGetTopicHistoryTestCases() []getTopicHistoryTestCase {
+	return []getTopicHistoryTestCase{
+		{
+			name:      "author sees the history",
+			user:      &user.User{ID: "author"},
+			topicID:   1,
+			wantCount: 2,
+		},
+		{
+			name:      "moderator sees the history",
+			user:      &user.User{ID: "mod", Role: user.RoleModerator},
+			topicID:   1,
+			wantCount: 2,
+		},
+		{
+			name:      "other users are refused",
+			user:      &user.User{ID: "other"},
+			topicID:   1,
+			wantError: ErrHistoryNotAllowed,
+		},
+		{
+			name:      "missing topic",
+			user:      &user.User{ID: "author"},
+			topicID:   99,
+			wantError: topics.ErrTopicNotFound,
+		},
+	}
+}
+
+func runGetTopicHistoryTest(tt getTopicHistoryTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		repo := &testhelpers.MockRepository{
+			GetTopicByIDFunc: func(ctx context.Context, topicID int, userID *string) (*topic.Topic, error) {
+				if topicID != 1 {
+					return notFound(ctx, topicID, userID)
+				}
+				return &topic.Topic{ID: 1, UserID: "author"}, nil
+			},
+			GetTopicRevisionsFunc: func(_ context.Context, topicID int) ([]topic.Revision, error) {
+				return []topic.Revision{{ID: 2, TopicID: topicID}, {ID: 1, TopicID: topicID}}, nil
+			},
+		}
+
+		got, err := NewGetTopicHistoryHandler(repo).Handle(context.Background(), GetTopicHistoryRequest{
+			User:    tt.user,
+			TopicID: tt.topicID,
+		})
+		if tt.wantError != nil {
+			if !errors.Is(err, tt.wantError) {
+				t.Fatalf("Handle() error = %v, want %v", err, tt.wantError)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("Handle() error = %v", err)
+		}
+		if len(got) != tt.wantCount {
+			t.Errorf("Handle() returned %d revisions, want %d", len(got), tt.wantCount)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package topicqueries
+
+import (
+	"context"
+
+	"github.com/arnald/forum/internal/domain/topic"
+)
+
+type GetTopicWatchersRequest struct {
+	TopicID int
+}
+
+type GetTopicWatchersRequestHandler interface {
+	Handle(ctx context.Context, req GetTopicWatchersRequest) ([]string, error)
+}
+
+type getTopicWatchersRequestHandler struct {
+	repo topic.Repository
+}
+
+func NewGetTopicWatchersHandler(repo topic.Repository) GetTopicWatchersRequestHandler {
+	return &getTopicWatchersRequestHandler{
+		repo: repo,
+	}
+}
+
+func (h *getTopicWatchersRequestHandler) Handle(ctx context.Context, req GetTopicWatchersRequest) ([]string, error) {
+	return h.repo.GetTopicWatchers(ctx, req.TopicID)
+}
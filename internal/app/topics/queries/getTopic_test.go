@@ -0,0 +1,88 @@
+package topicqueries
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/arnald/forum/internal/domain/comment"
+	"github.com/arnald/forum/internal/domain/topic"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/comments"
+	testhelpers "github.com/arnald/forum/internal/pkg/testing"
+)
+
+// stubCommentRepo pages over a fixed thread; the other methods are unused here.
+type stubCommentRepo struct {
+	comment.Repository
+	thread []comment.Comment
+}
+
+func (s stubCommentRepo) GetCommentsWithVotesPage(_ context.Context, _ int, _ *string, limit, offset int) ([]comment.Comment, error) {
+	end := min(offset+limit, len(s.thread))
+	return slices.Clone(s.thread[min(offset, end):end]), nil
+}
+
+func (s stubCommentRepo) CountVisibleComments(_ context.Context, _ int, _ *string) (int, error) {
+	return len(s.thread), nil
+}
+
+func (s stubCommentRepo) GetCommentWithVotes(_ context.Context, _, commentID int, _ *string) (*comment.Comment, error) {
+	for _, c := range s.thread {
+		if c.ID == commentID {
+			return &c, nil
+		}
+	}
+	return nil, fmt.Errorf("comment with ID %d not found: %w", commentID, comments.ErrCommentNotFound)
+}
+
+func TestGetTopicHandler_AcceptedAnswer(t *testing.T) {
+	thread := make([]comment.Comment, 0, 5)
+	for id := 1; id <= 5; id++ {
+		thread = append(thread, comment.Comment{ID: id})
+	}
+
+	testCases := []struct {
+		name       string
+		want       []int
+		acceptedID int
+		offset     int
+	}{
+		{name: "answer beyond the first page is added to it", acceptedID: 5, offset: 0, want: []int{5, 1, 2}},
+		{name: "answer already on the first page is not repeated", acceptedID: 2, offset: 0, want: []int{1, 2}},
+		{name: "later pages are left alone", acceptedID: 5, offset: 2, want: []int{3, 4}},
+		{name: "hidden answer is skipped", acceptedID: 9, offset: 0, want: []int{1, 2}},
+		{name: "no accepted answer", offset: 0, want: []int{1, 2}},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			topicRepo := &testhelpers.MockRepository{
+				GetTopicByIDFunc: func(_ context.Context, topicID int, _ *string) (*topic.Topic, error) {
+					return &topic.Topic{ID: topicID, AcceptedCommentID: tt.acceptedID}, nil
+				},
+			}
+			handler := NewGetTopicHandler(topicRepo, stubCommentRepo{thread: thread})
+
+			got, err := handler.Handle(context.Background(), GetTopicRequest{
+				TopicID:       7,
+				CommentLimit:  2,
+				CommentOffset: tt.offset,
+			})
+			if err != nil {
+				t.Fatalf("Handle() error = %v", err)
+			}
+
+			ids := make([]int, 0, len(got.Comments))
+			for _, c := range got.Comments {
+				ids = append(ids, c.ID)
+			}
+			if !slices.Equal(ids, tt.want) {
+				t.Errorf("Handle() comments = %v, want %v", ids, tt.want)
+			}
+			if got.CommentCount != len(thread) {
+				t.Errorf("Handle() CommentCount = %d, want %d", got.CommentCount, len(thread))
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package topicqueries
+
+import (
+	"context"
+	"errors"
+
+	"github.com/arnald/forum/internal/domain/topic"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/topics"
+)
+
+// RemovedContent replaces the title and body of a deleted topic that is still
+// linked from notifications or other content.
+const RemovedContent = "[content removed]"
+
+// ResolveTopicReference loads a topic that something else points at. When the
+// topic has been soft-deleted it returns a Removed placeholder instead of an
+// error, so pages reached through a stale link still render. Topics that never
+// existed, and drafts hidden from userID, keep failing with the repository's
+// not-found error.
+func ResolveTopicReference(ctx context.Context, repo topic.Repository, topicID int, userID *string) (*topic.Topic, error) {
+	found, err := repo.GetTopicByID(ctx, topicID, userID)
+	if err == nil {
+		return found, nil
+	}
+	if !errors.Is(err, topics.ErrTopicNotFound) {
+		return nil, err
+	}
+
+	removed, removedErr := repo.IsTopicRemoved(ctx, topicID, userID)
+	if removedErr != nil {
+		return nil, removedErr
+	}
+	if !removed {
+		return nil, err
+	}
+
+	return &topic.Topic{
+		ID:      topicID,
+		Title:   RemovedContent,
+		Content: RemovedContent,
+		Removed: true,
+	}, nil
+}
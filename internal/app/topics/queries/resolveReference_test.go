@@ -0,0 +1,102 @@
+package topicqueries
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/arnald/forum/internal/domain/topic"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/topics"
+	testhelpers "github.com/arnald/forum/internal/pkg/testing"
+)
+
+func TestResolveTopicReference(t *testing.T) {
+	t.Run("group: resolve topic reference", func(t *testing.T) {
+		testCases := newResolveReferenceTestCases()
+		for _, tt := range testCases {
+			t.Run(tt.name, runResolveReferenceTest(tt))
+		}
+	})
+}
+
+type resolveReferenceTestCase struct {
+	setupMocks  func(*testhelpers.MockRepository)
+	wantError   error
+	name        string
+	wantTitle   string
+	wantRemoved bool
+}
+
+func notFound(_ context.Context, topicID int, _ *string) (*topic.Topic, error) {
+	return nil, fmt.Errorf("topic with ID %d not found: %w", topicID, topics.ErrTopicNotFound)
+}
+
+func newResolveReferenceTestCases() []resolveReferenceTestCase {
+	return []resolveReferenceTestCase{
+		{
+			name: "existing topic is returned as is",
+			setupMocks: func(repo *testhelpers.MockRepository) {
+				repo.GetTopicByIDFunc = func(_ context.Context, topicID int, _ *string) (*topic.Topic, error) {
+					return &topic.Topic{ID: topicID, Title: "Still here"}, nil
+				}
+			},
+			wantTitle: "Still here",
+		},
+		{
+			name: "deleted topic resolves to a placeholder",
+			setupMocks: func(repo *testhelpers.MockRepository) {
+				repo.GetTopicByIDFunc = notFound
+				repo.IsTopicRemovedFunc = func(_ context.Context, _ int, _ *string) (bool, error) {
+					return true, nil
+				}
+			},
+			wantTitle:   RemovedContent,
+			wantRemoved: true,
+		},
+		{
+			name: "hidden or missing topic stays not found",
+			setupMocks: func(repo *testhelpers.MockRepository) {
+				repo.GetTopicByIDFunc = notFound
+				repo.IsTopicRemovedFunc = func(_ context.Context, _ int, _ *string) (bool, error) {
+					return false, nil
+				}
+			},
+			wantError: topics.ErrTopicNotFound,
+		},
+		{
+			name: "other repository errors are passed through",
+			setupMocks: func(repo *testhelpers.MockRepository) {
+				repo.GetTopicByIDFunc = func(_ context.Context, _ int, _ *string) (*topic.Topic, error) {
+					return nil, testhelpers.ErrTest
+				}
+			},
+			wantError: testhelpers.ErrTest,
+		},
+	}
+}
+
+func runResolveReferenceTest(tt resolveReferenceTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		repo := &testhelpers.MockRepository{}
+		tt.setupMocks(repo)
+
+		got, err := ResolveTopicReference(context.Background(), repo, 7, nil)
+
+		if !errors.Is(err, tt.wantError) {
+			t.Fatalf("ResolveTopicReference() error = %v, want %v", err, tt.wantError)
+		}
+		if tt.wantError != nil {
+			return
+		}
+		if got.Title != tt.wantTitle {
+			t.Errorf("ResolveTopicReference() title = %q, want %q", got.Title, tt.wantTitle)
+		}
+		if got.Removed != tt.wantRemoved {
+			t.Errorf("ResolveTopicReference() removed = %v, want %v", got.Removed, tt.wantRemoved)
+		}
+		if got.ID != 7 {
+			t.Errorf("ResolveTopicReference() id = %d, want 7", got.ID)
+		}
+	}
+}
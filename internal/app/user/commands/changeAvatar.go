@@ -0,0 +1,42 @@
+package usercommands
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/arnald/forum/internal/domain/user"
+)
+
+type ChangeAvatarRequest struct {
+	UserID    string
+	AvatarURL string
+}
+
+type ChangeAvatarRequestHandler interface {
+	Handle(ctx context.Context, req ChangeAvatarRequest) (string, error)
+}
+
+type changeAvatarRequestHandler struct {
+	repo user.Repository
+}
+
+func NewChangeAvatarHandler(repo user.Repository) ChangeAvatarRequestHandler {
+	return changeAvatarRequestHandler{
+		repo: repo,
+	}
+}
+
+// AvatarOwnerSeparator joins the uploader's user ID to the rest of an avatar's
+// file name, so the backend can tell who stored it.
+const AvatarOwnerSeparator = "_"
+
+// Handle points the user's avatar at an image they stored themselves and
+// returns the avatar URL it replaced, empty if there was none or another user
+// still shows it.
+func (h changeAvatarRequestHandler) Handle(ctx context.Context, req ChangeAvatarRequest) (string, error) {
+	if !strings.HasPrefix(path.Base(req.AvatarURL), req.UserID+AvatarOwnerSeparator) {
+		return "", ErrAvatarNotOwned
+	}
+	return h.repo.UpdateAvatar(ctx, req.UserID, req.AvatarURL)
+}
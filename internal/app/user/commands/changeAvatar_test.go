@@ -0,0 +1,69 @@
+package usercommands
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	testhelpers "github.com/arnald/forum/internal/pkg/testing"
+)
+
+func TestChangeAvatarHandler_Handle(t *testing.T) {
+	const alicesAvatar = "/static/images/uploads/avatars/alice_0b7e.png"
+
+	testCases := []struct {
+		wantErr    error
+		name       string
+		userID     string
+		avatarURL  string
+		wantStored bool
+	}{
+		{
+			name:       "uploader claims their avatar",
+			userID:     "alice",
+			avatarURL:  alicesAvatar,
+			wantStored: true,
+		},
+		{
+			name:      "another user cannot claim it",
+			userID:    "bob",
+			avatarURL: alicesAvatar,
+			wantErr:   ErrAvatarNotOwned,
+		},
+		{
+			name:      "a user ID that only starts the same is not the uploader",
+			userID:    "ali",
+			avatarURL: "/static/images/uploads/avatars/alice_0b7e.png",
+			wantErr:   ErrAvatarNotOwned,
+		},
+		{
+			name:      "avatars without an owner are refused",
+			userID:    "alice",
+			avatarURL: "/static/images/uploads/avatars/0b7e.png",
+			wantErr:   ErrAvatarNotOwned,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			var stored bool
+			repo := &testhelpers.MockRepository{
+				UpdateAvatarFunc: func(_ context.Context, _, _ string) (string, error) {
+					stored = true
+					return "", nil
+				},
+			}
+
+			_, err := NewChangeAvatarHandler(repo).Handle(context.Background(), ChangeAvatarRequest{
+				UserID:    tt.userID,
+				AvatarURL: tt.avatarURL,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Handle() error = %v, want %v", err, tt.wantErr)
+			}
+			if stored != tt.wantStored {
+				t.Errorf("avatar stored = %v, want %v", stored, tt.wantStored)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package usercommands
+
+import (
+	"context"
+
+	"github.com/arnald/forum/internal/domain/user"
+	"github.com/arnald/forum/internal/pkg/bcrypt"
+)
+
+type ChangePasswordRequest struct {
+	UserID   string
+	Password string
+}
+
+type ChangePasswordRequestHandler interface {
+	Handle(ctx context.Context, req ChangePasswordRequest) error
+}
+
+type changePasswordRequestHandler struct {
+	encryptionProvider bcrypt.Provider
+	repo               user.Repository
+}
+
+func NewChangePasswordHandler(repo user.Repository, en bcrypt.Provider) ChangePasswordRequestHandler {
+	return changePasswordRequestHandler{
+		repo:               repo,
+		encryptionProvider: en,
+	}
+}
+
+// Handle stores a new password for a user whose current password the caller
+// has already checked.
+func (h changePasswordRequestHandler) Handle(ctx context.Context, req ChangePasswordRequest) error {
+	encryptedPass, err := h.encryptionProvider.Generate(req.Password)
+	if err != nil {
+		return err
+	}
+
+	return h.repo.UpdatePassword(ctx, req.UserID, encryptedPass)
+}
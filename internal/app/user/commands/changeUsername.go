@@ -0,0 +1,33 @@
+package usercommands
+
+import (
+	"context"
+
+	"github.com/arnald/forum/internal/domain/user"
+)
+
+type ChangeUsernameRequest struct {
+	UserID   string
+	Username string
+}
+
+type ChangeUsernameRequestHandler interface {
+	Handle(ctx context.Context, req ChangeUsernameRequest) error
+}
+
+type changeUsernameRequestHandler struct {
+	repo user.Repository
+}
+
+func NewChangeUsernameHandler(repo user.Repository) ChangeUsernameRequestHandler {
+	return changeUsernameRequestHandler{
+		repo: repo,
+	}
+}
+
+// Handle renames a user to a name the caller has already validated. The
+// repository refuses names another account holds and keeps the old one in
+// the user's username history.
+func (h changeUsernameRequestHandler) Handle(ctx context.Context, req ChangeUsernameRequest) error {
+	return h.repo.UpdateUsername(ctx, req.UserID, req.Username)
+}
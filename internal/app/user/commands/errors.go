@@ -0,0 +1,5 @@
+package usercommands
+
+import "errors"
+
+var ErrAvatarNotOwned = errors.New("avatar was uploaded by another user")
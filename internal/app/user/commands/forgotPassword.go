@@ -0,0 +1,55 @@
+package usercommands
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/arnald/forum/internal/domain/user"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/users"
+	"github.com/arnald/forum/internal/pkg/uuid"
+)
+
+// passwordResetTTL is how long a reset link stays usable.
+const passwordResetTTL = time.Hour
+
+type ForgotPasswordRequest struct {
+	Email string
+}
+
+type ForgotPasswordRequestHandler interface {
+	// Handle returns the reset token for the account, or an empty token when
+	// no account uses the email so callers can answer the same either way.
+	Handle(ctx context.Context, req ForgotPasswordRequest) (string, error)
+}
+
+type forgotPasswordRequestHandler struct {
+	uuidProvider uuid.Provider
+	repo         user.Repository
+}
+
+func NewForgotPasswordHandler(repo user.Repository, uuidProvider uuid.Provider) ForgotPasswordRequestHandler {
+	return forgotPasswordRequestHandler{
+		repo:         repo,
+		uuidProvider: uuidProvider,
+	}
+}
+
+func (h forgotPasswordRequestHandler) Handle(ctx context.Context, req ForgotPasswordRequest) (string, error) {
+	account, err := h.repo.GetUserByEmail(ctx, req.Email)
+	if errors.Is(err, users.ErrUserNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	token := h.uuidProvider.NewUUID()
+
+	err = h.repo.CreatePasswordResetToken(ctx, account.ID, hashToken(token), time.Now().Add(passwordResetTTL))
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
@@ -0,0 +1,41 @@
+package usercommands
+
+import (
+	"context"
+
+	"github.com/arnald/forum/internal/domain/user"
+	"github.com/arnald/forum/internal/pkg/bcrypt"
+)
+
+type ResetPasswordRequest struct {
+	Token    string
+	Password string
+}
+
+type ResetPasswordRequestHandler interface {
+	// Handle sets the new password and returns the id of the account it
+	// belongs to.
+	Handle(ctx context.Context, req ResetPasswordRequest) (string, error)
+}
+
+type resetPasswordRequestHandler struct {
+	encryptionProvider bcrypt.Provider
+	repo               user.Repository
+}
+
+func NewResetPasswordHandler(repo user.Repository, en bcrypt.Provider) ResetPasswordRequestHandler {
+	return resetPasswordRequestHandler{
+		repo:               repo,
+		encryptionProvider: en,
+	}
+}
+
+func (h resetPasswordRequestHandler) Handle(ctx context.Context, req ResetPasswordRequest) (string, error) {
+	// Hash first so a bcrypt failure cannot burn the token.
+	encryptedPass, err := h.encryptionProvider.Generate(req.Password)
+	if err != nil {
+		return "", err
+	}
+
+	return h.repo.ConsumePasswordResetToken(ctx, hashToken(req.Token), encryptedPass)
+}
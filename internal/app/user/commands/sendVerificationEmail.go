@@ -0,0 +1,44 @@
+package usercommands
+
+import (
+	"context"
+	"time"
+
+	"github.com/arnald/forum/internal/domain/user"
+	"github.com/arnald/forum/internal/pkg/uuid"
+)
+
+// emailVerificationTTL is how long a verification link stays usable.
+const emailVerificationTTL = 24 * time.Hour
+
+type SendVerificationEmailRequest struct {
+	UserID string
+}
+
+type SendVerificationEmailRequestHandler interface {
+	// Handle issues a verification token for the user and returns it.
+	Handle(ctx context.Context, req SendVerificationEmailRequest) (string, error)
+}
+
+type sendVerificationEmailRequestHandler struct {
+	uuidProvider uuid.Provider
+	repo         user.Repository
+}
+
+func NewSendVerificationEmailHandler(repo user.Repository, uuidProvider uuid.Provider) SendVerificationEmailRequestHandler {
+	return sendVerificationEmailRequestHandler{
+		repo:         repo,
+		uuidProvider: uuidProvider,
+	}
+}
+
+func (h sendVerificationEmailRequestHandler) Handle(ctx context.Context, req SendVerificationEmailRequest) (string, error) {
+	token := h.uuidProvider.NewUUID()
+
+	err := h.repo.CreateEmailVerificationToken(ctx, req.UserID, hashToken(token), time.Now().Add(emailVerificationTTL))
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
@@ -0,0 +1,13 @@
+package usercommands
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// hashToken is what gets stored in place of an emailed token, so a leaked
+// database cannot be used to reset passwords or verify addresses.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
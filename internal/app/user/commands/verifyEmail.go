@@ -0,0 +1,30 @@
+package usercommands
+
+import (
+	"context"
+
+	"github.com/arnald/forum/internal/domain/user"
+)
+
+type VerifyEmailRequest struct {
+	Token string
+}
+
+type VerifyEmailRequestHandler interface {
+	// Handle marks the token owner's email as verified and returns their id.
+	Handle(ctx context.Context, req VerifyEmailRequest) (string, error)
+}
+
+type verifyEmailRequestHandler struct {
+	repo user.Repository
+}
+
+func NewVerifyEmailHandler(repo user.Repository) VerifyEmailRequestHandler {
+	return verifyEmailRequestHandler{
+		repo: repo,
+	}
+}
+
+func (h verifyEmailRequestHandler) Handle(ctx context.Context, req VerifyEmailRequest) (string, error) {
+	return h.repo.ConsumeEmailVerificationToken(ctx, hashToken(req.Token))
+}
@@ -2,4 +2,8 @@ package userqueries
 
 import "errors"
 
-var ErrPasswordMismatch = errors.New("password is not correct")
+var (
+	ErrPasswordMismatch = errors.New("password is not correct")
+	ErrPasswordNotSet   = errors.New("account has no password, confirm through your OAuth provider")
+	ErrAccountLocked    = errors.New("account temporarily locked after too many failed logins, try again in 15 minutes")
+)
@@ -0,0 +1,27 @@
+package userqueries
+
+import (
+	"context"
+
+	"github.com/arnald/forum/internal/domain/user"
+)
+
+type GetUserByUsernameRequest struct {
+	Username string
+}
+
+type GetUserByUsernameRequestHandler interface {
+	Handle(ctx context.Context, req GetUserByUsernameRequest) (*user.User, error)
+}
+
+type getUserByUsernameRequestHandler struct {
+	repo user.Repository
+}
+
+func NewGetUserByUsernameHandler(repo user.Repository) GetUserByUsernameRequestHandler {
+	return getUserByUsernameRequestHandler{repo: repo}
+}
+
+func (h getUserByUsernameRequestHandler) Handle(ctx context.Context, req GetUserByUsernameRequest) (*user.User, error) {
+	return h.repo.GetUserByUsername(ctx, req.Username)
+}
@@ -7,15 +7,22 @@ import (
 	"github.com/arnald/forum/internal/domain/user"
 )
 
+type GetAllUsersRequest struct {
+	Limit  int
+	Offset int
+}
+
 type GetAllUsersResult struct {
-	CreatedAt time.Time
-	Name      string
-	Role      string
-	ID        string
+	CreatedAt time.Time `json:"createdAt"`
+	AvatarURL *string   `json:"avatarUrl,omitempty"`
+	Name      string    `json:"username"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	ID        string    `json:"id"`
 }
 
 type GetAllUsersRequestHandler interface {
-	Handle(ctx context.Context) ([]GetAllUsersResult, error)
+	Handle(ctx context.Context, req GetAllUsersRequest) ([]GetAllUsersResult, int, error)
 }
 
 type getAllUsersRequestHandler struct {
@@ -26,10 +33,10 @@ func NewGetAllUsersRequestHandler(repo user.Repository) GetAllUsersRequestHandle
 	return getAllUsersRequestHandler{repo: repo}
 }
 
-func (r getAllUsersRequestHandler) Handle(ctx context.Context) ([]GetAllUsersResult, error) {
-	users, err := r.repo.GetAll(ctx)
+func (r getAllUsersRequestHandler) Handle(ctx context.Context, req GetAllUsersRequest) ([]GetAllUsersResult, int, error) {
+	users, total, err := r.repo.GetAll(ctx, req.Limit, req.Offset)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	results := []GetAllUsersResult{}
@@ -37,10 +44,12 @@ func (r getAllUsersRequestHandler) Handle(ctx context.Context) ([]GetAllUsersRes
 		results = append(results, GetAllUsersResult{
 			ID:        u.ID,
 			Name:      u.Username,
+			Email:     u.Email,
 			Role:      u.Role,
+			AvatarURL: u.AvatarURL,
 			CreatedAt: u.CreatedAt,
 		})
 	}
 
-	return results, nil
+	return results, total, nil
 }
@@ -0,0 +1,44 @@
+package userqueries
+
+import (
+	"context"
+	"time"
+
+	"github.com/arnald/forum/internal/domain/user"
+	"github.com/arnald/forum/internal/pkg/bcrypt"
+)
+
+const (
+	// maxFailedLogins is how many wrong passwords in a row lock an account.
+	maxFailedLogins = 5
+	// loginLockoutDuration is how long a locked account refuses to log in.
+	loginLockoutDuration = 15 * time.Minute
+)
+
+// checkLoginPassword verifies a password for u, whether it is offered to log
+// in or to confirm a sensitive action. While the account is
+// locked every attempt is refused, even with the right password; otherwise a
+// wrong password counts towards a lock and a right one clears the count.
+func checkLoginPassword(ctx context.Context, repo user.Repository, enc bcrypt.Provider, u *user.User, password string) error {
+	lockedUntil, err := repo.GetLoginLockout(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+	if lockedUntil.After(time.Now()) {
+		return ErrAccountLocked
+	}
+
+	err = enc.Matches(u.Password, password)
+	if err != nil {
+		lockedUntil, err = repo.RecordFailedLogin(ctx, u.ID, maxFailedLogins, loginLockoutDuration)
+		if err != nil {
+			return err
+		}
+		if lockedUntil.After(time.Now()) {
+			return ErrAccountLocked
+		}
+		return ErrPasswordMismatch
+	}
+
+	return repo.ResetFailedLogins(ctx, u.ID)
+}
@@ -0,0 +1,145 @@
+package userqueries
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/arnald/forum/internal/domain/user"
+	testhelpers "github.com/arnald/forum/internal/pkg/testing"
+)
+
+func TestLoginLockout(t *testing.T) {
+	t.Run("group: login lockout", func(t *testing.T) {
+		testCases := newLoginLockoutTestCases()
+		for _, tt := range testCases {
+			t.Run(tt.name, runLoginLockoutTest(tt))
+		}
+	})
+}
+
+type loginAttempt struct {
+	wantErr  error
+	password string
+}
+
+type loginLockoutTestCase struct {
+	lockedUntil time.Time
+	name        string
+	attempts    []loginAttempt
+}
+
+func repeatAttempt(n int, attempt loginAttempt) []loginAttempt {
+	attempts := make([]loginAttempt, n)
+	for i := range attempts {
+		attempts[i] = attempt
+	}
+	return attempts
+}
+
+func newLoginLockoutTestCases() []loginLockoutTestCase {
+	wrong := loginAttempt{password: "wrong", wantErr: ErrPasswordMismatch}
+	right := loginAttempt{password: "password123"}
+
+	return []loginLockoutTestCase{
+		{
+			name: "fifth failure locks the account",
+			attempts: append(repeatAttempt(maxFailedLogins-1, wrong),
+				loginAttempt{password: "wrong", wantErr: ErrAccountLocked},
+				loginAttempt{password: "password123", wantErr: ErrAccountLocked},
+			),
+		},
+		{
+			name: "successful login resets the count",
+			attempts: append(append(repeatAttempt(maxFailedLogins-1, wrong), right),
+				repeatAttempt(maxFailedLogins-1, wrong)...,
+			),
+		},
+		{
+			name:        "expired lock lets the user back in",
+			lockedUntil: time.Now().Add(-time.Minute),
+			attempts:    []loginAttempt{right},
+		},
+		{
+			name:        "active lock refuses the right password",
+			lockedUntil: time.Now().Add(time.Minute),
+			attempts:    []loginAttempt{{password: "password123", wantErr: ErrAccountLocked}},
+		},
+	}
+}
+
+// lockoutRepo wires a MockRepository to an in-memory failed login counter
+// that behaves like the sqlite implementation.
+func lockoutRepo(lockedUntil time.Time) *testhelpers.MockRepository {
+	attempts := 0
+
+	return &testhelpers.MockRepository{
+		GetUserByEmailFunc: func(_ context.Context, _ string) (*user.User, error) {
+			return &user.User{ID: "test-uuid", Password: "password123"}, nil
+		},
+		GetLoginLockoutFunc: func(_ context.Context, _ string) (time.Time, error) {
+			return lockedUntil, nil
+		},
+		RecordFailedLoginFunc: func(_ context.Context, _ string, maxAttempts int, lockFor time.Duration) (time.Time, error) {
+			attempts++
+			if attempts >= maxAttempts {
+				attempts = 0
+				lockedUntil = time.Now().Add(lockFor)
+			}
+			return lockedUntil, nil
+		},
+		ResetFailedLoginsFunc: func(_ context.Context, _ string) error {
+			attempts = 0
+			return nil
+		},
+	}
+}
+
+func runLoginLockoutTest(tt loginLockoutTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		enc := &testhelpers.MockEncryptionProvider{
+			MatchesFunc: func(hashedPassword, plaintextPassword string) error {
+				if hashedPassword != plaintextPassword {
+					return testhelpers.ErrTest
+				}
+				return nil
+			},
+		}
+		handler := NewUserLoginEmailHandler(lockoutRepo(tt.lockedUntil), enc)
+
+		for i, attempt := range tt.attempts {
+			_, err := handler.Handle(context.Background(), UserLoginEmailRequest{
+				Email:    "test@example.com",
+				Password: attempt.password,
+			})
+			if !errors.Is(err, attempt.wantErr) {
+				t.Fatalf("attempt %d: Handle() error = %v, wantErr %v", i+1, err, attempt.wantErr)
+			}
+		}
+	}
+}
+
+func TestVerifyPassword_Lockout(t *testing.T) {
+	enc := &testhelpers.MockEncryptionProvider{
+		MatchesFunc: func(hashedPassword, plaintextPassword string) error {
+			if hashedPassword != plaintextPassword {
+				return testhelpers.ErrTest
+			}
+			return nil
+		},
+	}
+	handler := NewVerifyPasswordHandler(lockoutRepo(time.Time{}), enc)
+	account := &user.User{ID: "test-uuid", Password: "password123"}
+
+	attempts := append(repeatAttempt(maxFailedLogins-1, loginAttempt{password: "wrong", wantErr: ErrPasswordMismatch}),
+		loginAttempt{password: "wrong", wantErr: ErrAccountLocked},
+		loginAttempt{password: "password123", wantErr: ErrAccountLocked},
+	)
+	for i, attempt := range attempts {
+		err := handler.Handle(context.Background(), VerifyPasswordRequest{User: account, Password: attempt.password})
+		if !errors.Is(err, attempt.wantErr) {
+			t.Fatalf("attempt %d: Handle() error = %v, wantErr %v", i+1, err, attempt.wantErr)
+		}
+	}
+}
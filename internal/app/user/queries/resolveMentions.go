@@ -0,0 +1,73 @@
+package userqueries
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/arnald/forum/internal/domain/user"
+)
+
+// maxMentions caps how many users one piece of content can notify.
+const maxMentions = 10
+
+// mentionPattern matches @username where the @ starts the text or follows a
+// character that cannot be part of an address, so "bob@example.com" is not a
+// mention.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.])@(\w[\w.-]{2,49})`)
+
+type ResolveMentionsRequest struct {
+	Content  string
+	AuthorID string
+}
+
+type ResolveMentionsRequestHandler interface {
+	Handle(ctx context.Context, req ResolveMentionsRequest) []*user.User
+}
+
+type resolveMentionsRequestHandler struct {
+	repo user.Repository
+}
+
+func NewResolveMentionsHandler(repo user.Repository) ResolveMentionsRequestHandler {
+	return resolveMentionsRequestHandler{repo: repo}
+}
+
+// Handle returns the existing users mentioned in req.Content, each once and
+// in order of first mention. Unknown usernames and the author are skipped.
+func (h resolveMentionsRequestHandler) Handle(ctx context.Context, req ResolveMentionsRequest) []*user.User {
+	usernames := ParseMentions(req.Content)
+
+	mentioned := make([]*user.User, 0, len(usernames))
+	seen := make(map[string]bool, len(usernames))
+	for _, username := range usernames {
+		found, err := h.repo.GetUserByUsername(ctx, username)
+		if err != nil || found.ID == req.AuthorID || seen[found.ID] {
+			continue
+		}
+		seen[found.ID] = true
+		mentioned = append(mentioned, found)
+	}
+
+	return mentioned
+}
+
+// ParseMentions extracts the distinct @usernames in content, without the @.
+// Trailing dots and hyphens are dropped so "@alice." names alice.
+func ParseMentions(content string) []string {
+	var usernames []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		username := strings.TrimRight(match[1], ".-")
+		if len(username) < 3 || seen[username] {
+			continue
+		}
+		seen[username] = true
+		usernames = append(usernames, username)
+		if len(usernames) == maxMentions {
+			break
+		}
+	}
+
+	return usernames
+}
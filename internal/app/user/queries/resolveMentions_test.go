@@ -0,0 +1,99 @@
+package userqueries
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/arnald/forum/internal/domain/user"
+	testhelpers "github.com/arnald/forum/internal/pkg/testing"
+)
+
+func TestResolveMentionsHandler_Handle(t *testing.T) {
+	t.Run("group: resolve mentions", func(t *testing.T) {
+		testCases := newResolveMentionsTestCases()
+		for _, tt := range testCases {
+			t.Run(tt.name, runResolveMentionsTest(tt))
+		}
+	})
+}
+
+type resolveMentionsTestCase struct {
+	name    string
+	request ResolveMentionsRequest
+	wantIDs []string
+}
+
+func newResolveMentionsTestCases() []resolveMentionsTestCase {
+	return []resolveMentionsTestCase{
+		{
+			name:    "valid mention",
+			request: ResolveMentionsRequest{Content: "thanks @alice!", AuthorID: "author-id"},
+			wantIDs: []string{"alice-id"},
+		},
+		{
+			name:    "nonexistent username",
+			request: ResolveMentionsRequest{Content: "hi @nobody", AuthorID: "author-id"},
+			wantIDs: []string{},
+		},
+		{
+			name:    "self-mention is ignored",
+			request: ResolveMentionsRequest{Content: "as @author said", AuthorID: "author-id"},
+			wantIDs: []string{},
+		},
+		{
+			name:    "repeated mention notifies once",
+			request: ResolveMentionsRequest{Content: "@alice, @bob and @alice again", AuthorID: "author-id"},
+			wantIDs: []string{"alice-id", "bob-id"},
+		},
+	}
+}
+
+func runResolveMentionsTest(tt resolveMentionsTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		known := map[string]*user.User{
+			"alice":  {ID: "alice-id", Username: "alice"},
+			"bob":    {ID: "bob-id", Username: "bob"},
+			"author": {ID: "author-id", Username: "author"},
+		}
+		repo := &testhelpers.MockRepository{
+			GetUserByUsernameFunc: func(_ context.Context, username string) (*user.User, error) {
+				found, ok := known[username]
+				if !ok {
+					return nil, testhelpers.ErrTest
+				}
+				return found, nil
+			},
+		}
+
+		got := NewResolveMentionsHandler(repo).Handle(context.Background(), tt.request)
+
+		gotIDs := make([]string, 0, len(got))
+		for _, u := range got {
+			gotIDs = append(gotIDs, u.ID)
+		}
+		if !reflect.DeepEqual(gotIDs, tt.wantIDs) {
+			t.Errorf("Handle() = %v, want %v", gotIDs, tt.wantIDs)
+		}
+	}
+}
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		content string
+		want    []string
+	}{
+		{content: "@alice at the start", want: []string{"alice"}},
+		{content: "ask @bob.smith.", want: []string{"bob.smith"}},
+		{content: "mail bob@example.com", want: nil},
+		{content: "@al is too short", want: nil},
+		{content: "(@alice) and @alice", want: []string{"alice"}},
+	}
+
+	for _, tt := range tests {
+		got := ParseMentions(tt.content)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseMentions(%q) = %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
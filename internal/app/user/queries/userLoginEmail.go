@@ -35,9 +35,9 @@ func (h *userLoginEmailRequestHandler) Handle(ctx context.Context, req UserLogin
 		return nil, err
 	}
 
-	err = h.encryptionProvider.Matches(user.Password, req.Password)
+	err = checkLoginPassword(ctx, h.repo, h.encryptionProvider, user, req.Password)
 	if err != nil {
-		return nil, ErrPasswordMismatch
+		return nil, err
 	}
 
 	return user, nil
@@ -0,0 +1,40 @@
+package userqueries
+
+import (
+	"context"
+
+	"github.com/arnald/forum/internal/domain/user"
+	"github.com/arnald/forum/internal/pkg/bcrypt"
+)
+
+type VerifyPasswordRequest struct {
+	User     *user.User
+	Password string
+}
+
+type VerifyPasswordRequestHandler interface {
+	Handle(ctx context.Context, req VerifyPasswordRequest) error
+}
+
+type verifyPasswordRequestHandler struct {
+	repo               user.Repository
+	encryptionProvider bcrypt.Provider
+}
+
+func NewVerifyPasswordHandler(repo user.Repository, encryptionProvider bcrypt.Provider) VerifyPasswordRequestHandler {
+	return &verifyPasswordRequestHandler{
+		repo:               repo,
+		encryptionProvider: encryptionProvider,
+	}
+}
+
+// Handle checks a password against the already loaded user, which is how a
+// signed-in user re-confirms their identity before a sensitive action. Wrong
+// passwords count towards the same lockout as failed logins.
+func (h *verifyPasswordRequestHandler) Handle(ctx context.Context, req VerifyPasswordRequest) error {
+	if req.User.Password == "" {
+		return ErrPasswordNotSet
+	}
+
+	return checkLoginPassword(ctx, h.repo, h.encryptionProvider, req.User, req.Password)
+}
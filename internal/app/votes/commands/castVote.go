@@ -2,14 +2,23 @@ package votecommands
 
 import (
 	"context"
+	"time"
 
 	"github.com/arnald/forum/internal/domain/vote"
 )
 
+// voteCapWindow is the rolling window DailyCap applies to.
+const voteCapWindow = 24 * time.Hour
+
 type CastVoteRequest struct {
 	Target       vote.Target `json:"target"`
 	UserID       string      `json:"userId"`
 	ReactionType int         `json:"reactionType"`
+	// DailyCap is the configured limit on votes cast per rolling 24 hours;
+	// 0 disables it. Taking a vote back never counts against it.
+	DailyCap int
+	// CapExempt lets staff vote past DailyCap.
+	CapExempt bool
 }
 
 type castVoteRequestHandler struct {
@@ -27,6 +36,13 @@ func NewCastVoteHandler(voteRepo vote.Repository) CastVoteRequestHandler {
 }
 
 func (h *castVoteRequestHandler) Handle(ctx context.Context, req CastVoteRequest) error {
+	if req.DailyCap > 0 && !req.CapExempt {
+		err := h.checkDailyCap(ctx, req)
+		if err != nil {
+			return err
+		}
+	}
+
 	err := h.VoteRepo.CastVote(ctx, req.UserID, req.Target, req.ReactionType)
 	if err != nil {
 		return err
@@ -34,3 +50,26 @@ func (h *castVoteRequestHandler) Handle(ctx context.Context, req CastVoteRequest
 
 	return nil
 }
+
+// checkDailyCap returns ErrDailyVoteCapReached when the user has used up
+// their casts. Repeating the current reaction toggles the vote off, which is
+// always allowed.
+func (h *castVoteRequestHandler) checkDailyCap(ctx context.Context, req CastVoteRequest) error {
+	current, err := h.VoteRepo.GetUserReaction(ctx, req.UserID, req.Target)
+	if err != nil {
+		return err
+	}
+	if current == req.ReactionType {
+		return nil
+	}
+
+	casts, err := h.VoteRepo.CountCastsSince(ctx, req.UserID, time.Now().Add(-voteCapWindow))
+	if err != nil {
+		return err
+	}
+	if casts >= req.DailyCap {
+		return ErrDailyVoteCapReached
+	}
+
+	return nil
+}
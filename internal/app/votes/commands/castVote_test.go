@@ -0,0 +1,119 @@
+package votecommands
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/arnald/forum/internal/domain/vote"
+)
+
+// stubVoteRepo keeps one reaction per topic and logs every cast the way the
+// sqlite repository does: repeating a reaction removes it without a cast.
+type stubVoteRepo struct {
+	vote.Repository
+	reactions map[int]int
+	casts     int
+}
+
+func newStubVoteRepo() *stubVoteRepo {
+	return &stubVoteRepo{reactions: make(map[int]int)}
+}
+
+func (s *stubVoteRepo) CastVote(_ context.Context, _ string, target vote.Target, reactionType int) error {
+	if s.reactions[*target.TopicID] == reactionType {
+		delete(s.reactions, *target.TopicID)
+		return nil
+	}
+	s.reactions[*target.TopicID] = reactionType
+	s.casts++
+	return nil
+}
+
+func (s *stubVoteRepo) GetUserReaction(_ context.Context, _ string, target vote.Target) (int, error) {
+	return s.reactions[*target.TopicID], nil
+}
+
+func (s *stubVoteRepo) CountCastsSince(_ context.Context, _ string, _ time.Time) (int, error) {
+	return s.casts, nil
+}
+
+const dailyCap = 3
+
+func castOn(handler CastVoteRequestHandler, topicID, reaction int, exempt bool) error {
+	return handler.Handle(context.Background(), CastVoteRequest{
+		UserID:       "user-id",
+		Target:       vote.Target{TopicID: &topicID},
+		ReactionType: reaction,
+		DailyCap:     dailyCap,
+		CapExempt:    exempt,
+	})
+}
+
+func TestCastVoteHandler_DailyCap(t *testing.T) {
+	t.Run("the cast at the cap is allowed and the next one is not", func(t *testing.T) {
+		handler := NewCastVoteHandler(newStubVoteRepo())
+
+		for topicID := 1; topicID <= dailyCap; topicID++ {
+			err := castOn(handler, topicID, 1, false)
+			if err != nil {
+				t.Fatalf("cast %d error = %v, want nil", topicID, err)
+			}
+		}
+
+		err := castOn(handler, dailyCap+1, 1, false)
+		if !errors.Is(err, ErrDailyVoteCapReached) {
+			t.Errorf("cast past the cap error = %v, want %v", err, ErrDailyVoteCapReached)
+		}
+	})
+
+	t.Run("removing a vote at the cap is free", func(t *testing.T) {
+		repo := newStubVoteRepo()
+		handler := NewCastVoteHandler(repo)
+
+		for topicID := 1; topicID <= dailyCap; topicID++ {
+			_ = castOn(handler, topicID, 1, false)
+		}
+
+		err := castOn(handler, 1, 1, false)
+		if err != nil {
+			t.Fatalf("removing a vote at the cap error = %v, want nil", err)
+		}
+		if _, stillVoted := repo.reactions[1]; stillVoted {
+			t.Error("repeating the reaction did not remove the vote")
+		}
+		if repo.casts != dailyCap {
+			t.Errorf("casts = %d after a removal, want %d", repo.casts, dailyCap)
+		}
+
+		err = castOn(handler, 1, 1, false)
+		if !errors.Is(err, ErrDailyVoteCapReached) {
+			t.Errorf("re-casting a removed vote error = %v, want %v", err, ErrDailyVoteCapReached)
+		}
+	})
+
+	t.Run("switching a vote counts as a cast", func(t *testing.T) {
+		handler := NewCastVoteHandler(newStubVoteRepo())
+
+		for topicID := 1; topicID <= dailyCap; topicID++ {
+			_ = castOn(handler, topicID, 1, false)
+		}
+
+		err := castOn(handler, 1, -1, false)
+		if !errors.Is(err, ErrDailyVoteCapReached) {
+			t.Errorf("switching at the cap error = %v, want %v", err, ErrDailyVoteCapReached)
+		}
+	})
+
+	t.Run("staff are exempt", func(t *testing.T) {
+		handler := NewCastVoteHandler(newStubVoteRepo())
+
+		for topicID := 1; topicID <= dailyCap+1; topicID++ {
+			err := castOn(handler, topicID, 1, true)
+			if err != nil {
+				t.Fatalf("staff cast %d error = %v, want nil", topicID, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,5 @@
+package votecommands
+
+import "errors"
+
+var ErrDailyVoteCapReached = errors.New("daily vote cap reached")
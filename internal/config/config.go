@@ -9,12 +9,14 @@ import (
 
 	"github.com/arnald/forum/internal/pkg/helpers"
 	"github.com/arnald/forum/internal/pkg/path"
+	"github.com/arnald/forum/internal/pkg/validator"
 )
 
 const (
 	readTimeout                     = 5
 	writeTimeout                    = 10
 	idleTimeout                     = 15
+	shutdownTimeout                 = 15
 	configParts                     = 2
 	defaultExpiry                   = 86400
 	cleanupInternal                 = 3600
@@ -22,10 +24,43 @@ const (
 	sessionIDLenght                 = 32
 	userRegisterTimeout             = 15
 	refreshTokenExpiry              = 30
+	defaultRememberMeExpiry         = 2592000
 	userLoginTimeout                = 15
+	defaultReauthWindow             = 600
 	defaultRateLimitCleanupSeconds  = 60
 	defaultRateLimitWindowSeconds   = 60
 	defaultRateLimitRequestCapacity = 100
+	defaultRateLimitMaxClients      = 100000
+	defaultUserRateLimitRequests    = 10
+	defaultUserRateLimitWindow      = 60
+	defaultBumpMinEditChars         = 0
+	defaultTopicMinCategories       = 1
+	defaultTitleMaxUppercasePercent = 70
+	defaultTitleMaxPunctuationRun   = 3
+	defaultTopicSummaryMaxLength    = 300
+	defaultTopicPermalinkMaxAge     = 60
+	defaultTopicViewWindow          = 1800
+	defaultTopicDuplicateWindow     = 30
+	defaultControversyMinVotes      = 4
+	defaultControversyBalanceWeight = 1.0
+	defaultNewAccountReviewAge      = 86400
+	defaultCategoryTreeCacheTTL     = 30
+	defaultWatchNotifyInterval      = 600
+	defaultCollapseReportThreshold  = 3
+	defaultVoteDailyCap             = 500
+	defaultVoteNotifyInterval       = 600
+	defaultImportMaxBytes           = 10 << 20
+	defaultImportRequestsLimit      = 5
+	defaultImportWindowSeconds      = 3600
+)
+
+// EnvironmentProduction is the SERVER_ENVIRONMENT value that turns on
+// production defaults such as secure cookies.
+const EnvironmentProduction = "production"
+
+const (
+	RateLimitBackendSlidingWindow = "sliding_window"
+	RateLimitBackendTokenBucket   = "token_bucket"
 )
 
 var (
@@ -42,19 +77,129 @@ type ServerConfig struct {
 	TLSCertFile    string
 	TLSKeyFile     string
 	Database       DatabaseConfig
+	RateLimit      RateLimitConfig
 	SessionManager SessionManagerConfig
+	Topics         TopicsConfig
+	Comments       CommentsConfig
+	Import         ImportConfig
 	Timeouts       TimeoutsConfig
-	ReadTimeout    time.Duration
-	WriteTimeout   time.Duration
-	IdleTimeout    time.Duration
-	RateLimit      RateLimitConfig
+	Categories     CategoriesConfig
+	Votes          VotesConfig
+	Accounts       AccountsConfig
+	// Passwords is the strength required of every password set through
+	// registration, password reset or password change.
+	Passwords validator.PasswordPolicy
+	// Limits caps the length of topic titles and bodies and of comments.
+	Limits       validator.ContentLimits
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+	// ShutdownTimeout bounds how long in-flight requests may run once the
+	// server has been asked to stop.
+	ShutdownTimeout time.Duration
 }
 
+// RateLimitConfig holds the per-IP limit applied to every request. On top of
+// it, each signed-in user may create UserRequestsLimit topics and comments
+// per UserWindowSeconds, wherever they post from; 0 disables that budget.
 type RateLimitConfig struct {
-	Enabled       bool
-	RequestsLimit int
+	Backend           string
+	RequestsLimit     int
+	WindowSeconds     int64
+	Cleanup           time.Duration
+	MaxClients        int
+	UserRequestsLimit int
+	UserWindowSeconds int64
+	Enabled           bool
+}
+
+// TopicsConfig holds topic rules. EditBumps controls whether editing a topic
+// moves it back to the top of listings; edits that change fewer than
+// MinBumpEditChars characters of the content, and leave the title alone,
+// count as trivial and never bump. MinCategories is the number of categories
+// a new topic must be filed under. The Controversy settings feed the
+// ?sort=controversial ordering. With CanonicalListings on, a topic filed
+// under several categories is only listed under its canonical one on the
+// categories overview. TitleMaxUppercasePercent and TitleMaxPunctuationRun
+// reject shouty titles from non-staff users; 0 disables either check. With
+// Questions on, authors can mark a topic as a question and accept an answer.
+// SummaryMaxLength caps the optional TL;DR shown in listings; 0 turns
+// summaries off. PublicURL is the frontend origin used to build canonical
+// topic links, and PermalinkMaxAge is how long clients may cache a topic's
+// JSON permalink. With RequireVerifiedEmail on, only accounts that have
+// followed their verification link may start topics. Repeat views of a topic
+// by the same viewer within ViewWindow add to its view count only once. A
+// topic identical to one its author created within DuplicateWindow is
+// refused as a double submission; 0 allows them.
+type TopicsConfig struct {
+	PublicURL                string
+	ControversyBalanceWeight float64
+	PermalinkMaxAge          time.Duration
+	ViewWindow               time.Duration
+	DuplicateWindow          time.Duration
+	ControversyMinVotes      int
+	MinBumpEditChars         int
+	MinCategories            int
+	TitleMaxUppercasePercent int
+	TitleMaxPunctuationRun   int
+	SummaryMaxLength         int
+	EditBumps                bool
+	CanonicalListings        bool
+	Questions                bool
+	RequireVerifiedEmail     bool
+}
+
+// CommentsConfig holds comment rules. NewAccountReview is the only approval
+// rule: with it on, comments by accounts younger than NewAccountReviewAge are
+// held for a moderator before anyone but their author can see them. It is off
+// by default, so every comment is published at once whoever writes it. AnonymousModeration leaves the
+// moderator's name out of the notifications their decisions send; the comment
+// itself still records who made the call. With AutoWatch on, commenting on a
+// topic subscribes the commenter to its later comments. Watchers get at most
+// one new unread notification per topic per WatchNotifyInterval; later
+// comments inside it bump that notification's count instead. A comment with
+// CollapseReportThreshold pending reports is collapsed behind a warning until
+// a moderator settles them; 0 never collapses.
+type CommentsConfig struct {
+	NewAccountReviewAge     time.Duration
+	WatchNotifyInterval     time.Duration
+	CollapseReportThreshold int
+	NewAccountReview        bool
+	AnonymousModeration     bool
+	AutoWatch               bool
+}
+
+// CategoriesConfig holds category settings. TreeCacheTTL is how long the
+// site-wide category tree is served from memory before it is rebuilt.
+type CategoriesConfig struct {
+	TreeCacheTTL time.Duration
+}
+
+// VotesConfig holds voting limits. DailyCap is how many votes a user may cast
+// in any rolling 24 hours, counting switches but not removals; 0 disables
+// it, and staff are exempt. Likes or dislikes of the same content within
+// NotifyInterval of an unread notification about it fold into that one.
+type VotesConfig struct {
+	NotifyInterval time.Duration
+	DailyCap       int
+}
+
+// AccountsConfig holds account identity rules. Emails are always compared
+// lowercased; with StripEmailAliases on, Gmail addresses also lose their dots
+// and +tags, so j.doe+forum@gmail.com and jdoe@gmail.com are one account.
+// Stored emails are rewritten to match on each migration.
+type AccountsConfig struct {
+	StripEmailAliases bool
+}
+
+// ImportConfig controls the admin content import. It is off unless Enabled
+// is set. Each admin may run RequestsLimit imports per WindowSeconds, and a
+// document may be at most MaxBytes long.
+type ImportConfig struct {
+	MaxBytes      int64
 	WindowSeconds int64
-	Cleanup       time.Duration
+	RequestsLimit int
+	Enabled       bool
 }
 
 type OAuthConfig struct {
@@ -89,10 +234,14 @@ type DatabaseConfig struct {
 }
 
 type SessionManagerConfig struct {
-	CookieName         string
-	CookiePath         string
-	CookieDomain       string
-	SameSite           string
+	CookieName   string
+	CookiePath   string
+	CookieDomain string
+	SameSite     string
+	// ReauthRoutes are the account routes, relative to API_CONTEXT, that
+	// also need a login within ReauthWindow. Empty by default: the web
+	// client has no way to confirm a login again yet.
+	ReauthRoutes       []string
 	DefaultExpiry      time.Duration
 	CleanupInterval    time.Duration
 	MaxSessionsPerUser int
@@ -102,6 +251,8 @@ type SessionManagerConfig struct {
 	EnablePersistence  bool
 	LogSessions        bool
 	RefreshTokenExpiry time.Duration
+	RememberMeExpiry   time.Duration
+	ReauthWindow       time.Duration
 }
 
 type TimeoutsConfig struct {
@@ -123,27 +274,30 @@ func LoadConfig() (*ServerConfig, error) {
 	envFile, _ := os.ReadFile(resolver.GetPath(".env"))
 	envMap := helpers.ParseEnv(string(envFile))
 
+	environment := helpers.GetEnv("SERVER_ENVIRONMENT", envMap, "development")
+
 	cfg := &ServerConfig{
-		Host:         helpers.GetEnv("SERVER_HOST", envMap, "localhost"),
-		Port:         helpers.GetEnv("SERVER_PORT", envMap, "8080"),
-		Environment:  helpers.GetEnv("SERVER_ENVIRONMENT", envMap, "development"),
-		APIContext:   helpers.GetEnv("API_CONTEXT", envMap, "/api/v1"),
-		TLSCertFile:  helpers.GetEnv("SERVER_TLS_CERT_FILE", envMap, ""),
-		TLSKeyFile:   helpers.GetEnv("SERVER_TLS_KEY_FILE", envMap, ""),
-		ReadTimeout:  helpers.GetEnvDuration("SERVER_READ_TIMEOUT", envMap, readTimeout),
-		WriteTimeout: helpers.GetEnvDuration("SERVER_WRITE_TIMEOUT", envMap, writeTimeout),
-		IdleTimeout:  helpers.GetEnvDuration("SERVER_IDLE_TIMEOUT", envMap, idleTimeout),
+		Host:            helpers.GetEnv("SERVER_HOST", envMap, "localhost"),
+		Port:            helpers.GetEnv("SERVER_PORT", envMap, "8080"),
+		Environment:     environment,
+		APIContext:      helpers.GetEnv("API_CONTEXT", envMap, "/api/v1"),
+		TLSCertFile:     helpers.GetEnv("SERVER_TLS_CERT_FILE", envMap, ""),
+		TLSKeyFile:      helpers.GetEnv("SERVER_TLS_KEY_FILE", envMap, ""),
+		ReadTimeout:     helpers.GetEnvDuration("SERVER_READ_TIMEOUT", envMap, readTimeout),
+		WriteTimeout:    helpers.GetEnvDuration("SERVER_WRITE_TIMEOUT", envMap, writeTimeout),
+		IdleTimeout:     helpers.GetEnvDuration("SERVER_IDLE_TIMEOUT", envMap, idleTimeout),
+		ShutdownTimeout: helpers.GetEnvDuration("SERVER_SHUTDOWN_TIMEOUT", envMap, shutdownTimeout),
 		Database: DatabaseConfig{
 			Driver:         helpers.GetEnv("DB_DRIVER", envMap, "sqlite3"),
 			Path:           resolver.GetPath(helpers.GetEnv("DB_PATH", envMap, "data/forum.db")),
 			MigrateOnStart: helpers.GetEnvBool("DB_MIGRATE_ON_START", envMap, true),
 			SeedOnStart:    helpers.GetEnvBool("DB_SEED_ON_START", envMap, true),
-			Pragma:         helpers.GetEnv("DB_PRAGMA", envMap, "_foreign_keys=on&_journal_mode=WAL"),
+			Pragma:         helpers.GetEnv("DB_PRAGMA", envMap, "_foreign_keys=on&_journal_mode=WAL&_busy_timeout=5000"),
 			OpenConn:       helpers.GetEnvInt("DB_OPEN_CONN", envMap, 1),
 		},
 		SessionManager: SessionManagerConfig{
 			DefaultExpiry:      helpers.GetEnvDuration("SESSION_DEFAULT_EXPIRY", envMap, defaultExpiry),
-			SecureCookie:       helpers.GetEnvBool("SESSION_SECURE_COOKIE", envMap, false),
+			SecureCookie:       helpers.GetEnvBool("SESSION_SECURE_COOKIE", envMap, environment == EnvironmentProduction),
 			CookieName:         helpers.GetEnv("SESSION_COOKIE_NAME", envMap, "session_id"),
 			CookiePath:         helpers.GetEnv("SESSION_COOKIE_PATH", envMap, "/"),
 			CookieDomain:       helpers.GetEnv("SESSION_COOKIE_DOMAIN", envMap, ""),
@@ -155,6 +309,9 @@ func LoadConfig() (*ServerConfig, error) {
 			EnablePersistence:  helpers.GetEnvBool("SESSION_ENABLE_PERSISTENCE", envMap, true),
 			LogSessions:        helpers.GetEnvBool("SESSION_LOG_SESSIONS", envMap, false),
 			RefreshTokenExpiry: helpers.GetEnvDuration("SESSION_REFRESH_TOKEN_EXPIRY", envMap, refreshTokenExpiry),
+			RememberMeExpiry:   helpers.GetEnvDuration("SESSION_REMEMBER_ME_EXPIRY", envMap, defaultRememberMeExpiry),
+			ReauthWindow:       helpers.GetEnvDuration("SESSION_REAUTH_WINDOW", envMap, defaultReauthWindow),
+			ReauthRoutes:       helpers.ParseList(helpers.GetEnv("SESSION_REAUTH_ROUTES", envMap, "")),
 		},
 		Timeouts: TimeoutsConfig{
 			HandlerTimeouts: HandlerTimeoutsConfig{
@@ -181,10 +338,67 @@ func LoadConfig() (*ServerConfig, error) {
 			FrontendCallbackURL: helpers.GetEnv("FRONTEND_CALLBACK_URL", envMap, ""),
 		},
 		RateLimit: RateLimitConfig{
-			Enabled:       helpers.GetEnvBool("RATE_LIMIT_ENABLED", envMap, true),
-			RequestsLimit: helpers.GetEnvInt("RATE_LIMIT_REQUESTS", envMap, defaultRateLimitRequestCapacity),
-			WindowSeconds: int64(helpers.GetEnvInt("RATE_LIMIT_WINDOW_SECONDS", envMap, defaultRateLimitWindowSeconds)),
-			Cleanup:       helpers.GetEnvDuration("RATE_LIMIT_CLEANUP_SECONDS", envMap, defaultRateLimitCleanupSeconds),
+			Enabled:           helpers.GetEnvBool("RATE_LIMIT_ENABLED", envMap, true),
+			RequestsLimit:     helpers.GetEnvInt("RATE_LIMIT_REQUESTS", envMap, defaultRateLimitRequestCapacity),
+			WindowSeconds:     int64(helpers.GetEnvInt("RATE_LIMIT_WINDOW_SECONDS", envMap, defaultRateLimitWindowSeconds)),
+			Cleanup:           helpers.GetEnvDuration("RATE_LIMIT_CLEANUP_SECONDS", envMap, defaultRateLimitCleanupSeconds),
+			Backend:           helpers.GetEnv("RATE_LIMIT_BACKEND", envMap, RateLimitBackendSlidingWindow),
+			MaxClients:        helpers.GetEnvInt("RATE_LIMIT_MAX_CLIENTS", envMap, defaultRateLimitMaxClients),
+			UserRequestsLimit: helpers.GetEnvInt("RATE_LIMIT_USER_REQUESTS", envMap, defaultUserRateLimitRequests),
+			UserWindowSeconds: int64(helpers.GetEnvInt("RATE_LIMIT_USER_WINDOW_SECONDS", envMap, defaultUserRateLimitWindow)),
+		},
+		Topics: TopicsConfig{
+			EditBumps:                helpers.GetEnvBool("TOPIC_EDIT_BUMPS", envMap, false),
+			MinBumpEditChars:         helpers.GetEnvInt("TOPIC_BUMP_MIN_EDIT_CHARS", envMap, defaultBumpMinEditChars),
+			MinCategories:            helpers.GetEnvInt("TOPIC_MIN_CATEGORIES", envMap, defaultTopicMinCategories),
+			ControversyMinVotes:      helpers.GetEnvInt("TOPIC_CONTROVERSY_MIN_VOTES", envMap, defaultControversyMinVotes),
+			ControversyBalanceWeight: helpers.GetEnvFloat("TOPIC_CONTROVERSY_BALANCE_WEIGHT", envMap, defaultControversyBalanceWeight),
+			CanonicalListings:        helpers.GetEnvBool("TOPIC_CANONICAL_LISTINGS", envMap, false),
+			TitleMaxUppercasePercent: helpers.GetEnvInt("TOPIC_TITLE_MAX_UPPERCASE_PERCENT", envMap, defaultTitleMaxUppercasePercent),
+			TitleMaxPunctuationRun:   helpers.GetEnvInt("TOPIC_TITLE_MAX_PUNCTUATION_RUN", envMap, defaultTitleMaxPunctuationRun),
+			Questions:                helpers.GetEnvBool("TOPIC_QUESTIONS", envMap, true),
+			SummaryMaxLength:         helpers.GetEnvInt("TOPIC_SUMMARY_MAX_LENGTH", envMap, defaultTopicSummaryMaxLength),
+			PublicURL:                strings.TrimSuffix(helpers.GetEnv("TOPIC_PUBLIC_URL", envMap, "http://localhost:3001"), "/"),
+			PermalinkMaxAge:          helpers.GetEnvDuration("TOPIC_PERMALINK_MAX_AGE", envMap, defaultTopicPermalinkMaxAge),
+			RequireVerifiedEmail:     helpers.GetEnvBool("TOPIC_REQUIRE_VERIFIED_EMAIL", envMap, true),
+			ViewWindow:               helpers.GetEnvDuration("TOPIC_VIEW_WINDOW", envMap, defaultTopicViewWindow),
+			DuplicateWindow:          helpers.GetEnvDuration("TOPIC_DUPLICATE_WINDOW", envMap, defaultTopicDuplicateWindow),
+		},
+		Comments: CommentsConfig{
+			NewAccountReview:        helpers.GetEnvBool("COMMENT_NEW_ACCOUNT_REVIEW", envMap, false),
+			NewAccountReviewAge:     helpers.GetEnvDuration("COMMENT_NEW_ACCOUNT_REVIEW_AGE", envMap, defaultNewAccountReviewAge),
+			AnonymousModeration:     helpers.GetEnvBool("COMMENT_ANONYMOUS_MODERATION", envMap, true),
+			AutoWatch:               helpers.GetEnvBool("COMMENT_AUTO_WATCH", envMap, true),
+			WatchNotifyInterval:     helpers.GetEnvDuration("COMMENT_WATCH_NOTIFY_INTERVAL", envMap, defaultWatchNotifyInterval),
+			CollapseReportThreshold: helpers.GetEnvInt("COMMENT_COLLAPSE_REPORT_THRESHOLD", envMap, defaultCollapseReportThreshold),
+		},
+		Categories: CategoriesConfig{
+			TreeCacheTTL: helpers.GetEnvDuration("CATEGORY_TREE_CACHE_TTL", envMap, defaultCategoryTreeCacheTTL),
+		},
+		Votes: VotesConfig{
+			DailyCap:       helpers.GetEnvInt("VOTE_DAILY_CAP", envMap, defaultVoteDailyCap),
+			NotifyInterval: helpers.GetEnvDuration("VOTE_NOTIFY_INTERVAL", envMap, defaultVoteNotifyInterval),
+		},
+		Passwords: validator.PasswordPolicy{
+			MinLength:      helpers.GetEnvInt("PASSWORD_MIN_LENGTH", envMap, validator.MinPasswordLength),
+			RequireLower:   helpers.GetEnvBool("PASSWORD_REQUIRE_LOWER", envMap, true),
+			RequireUpper:   helpers.GetEnvBool("PASSWORD_REQUIRE_UPPER", envMap, true),
+			RequireDigit:   helpers.GetEnvBool("PASSWORD_REQUIRE_DIGIT", envMap, true),
+			RequireSpecial: helpers.GetEnvBool("PASSWORD_REQUIRE_SPECIAL", envMap, true),
+		},
+		Limits: validator.ContentLimits{
+			TopicTitle:     helpers.GetEnvInt("TOPIC_TITLE_MAX_LENGTH", envMap, validator.MaxTopicTitleLength),
+			TopicContent:   helpers.GetEnvInt("TOPIC_CONTENT_MAX_LENGTH", envMap, validator.MaxTopicContentLength),
+			CommentContent: helpers.GetEnvInt("COMMENT_CONTENT_MAX_LENGTH", envMap, validator.MaxCommentContentLength),
+		},
+		Accounts: AccountsConfig{
+			StripEmailAliases: helpers.GetEnvBool("ACCOUNT_STRIP_EMAIL_ALIASES", envMap, false),
+		},
+		Import: ImportConfig{
+			Enabled:       helpers.GetEnvBool("IMPORT_ENABLED", envMap, false),
+			MaxBytes:      int64(helpers.GetEnvInt("IMPORT_MAX_BYTES", envMap, defaultImportMaxBytes)),
+			RequestsLimit: helpers.GetEnvInt("IMPORT_RATE_LIMIT_REQUESTS", envMap, defaultImportRequestsLimit),
+			WindowSeconds: int64(helpers.GetEnvInt("IMPORT_RATE_LIMIT_WINDOW_SECONDS", envMap, defaultImportWindowSeconds)),
 		},
 	}
 
@@ -19,8 +19,65 @@ type CommentActivity struct {
 	CreatedAt  string
 	Content    string
 	TopicTitle string
-	ID         int
+	Status     string
+	// RejectionReason is the moderator's explanation for a rejected comment.
+	RejectionReason string
+	ID              int
+	TopicID         int
+}
+
+// Profile is what a user's public page shows: their topics, their most
+// recent comments and the totals across everything they have posted.
+type Profile struct {
+	Topics   []TopicActivity
+	Comments []CommentActivity
+	Stats    Stats
+}
+
+// Stats counts a user's visible contributions and the likes they drew.
+type Stats struct {
+	Posts         int
+	Comments      int
+	LikesReceived int
+}
+
+// Submissions are a user's own comments sorted by moderation status, so they
+// can see what is still waiting for review and what was turned down.
+type Submissions struct {
+	Pending  []CommentActivity
+	Approved []CommentActivity
+	Rejected []CommentActivity
+}
+
+// Feed types pick what a filtered activity feed lists: the user's own topics,
+// their comments, what they liked, or the likes and comments others left on
+// their content.
+const (
+	FeedPosts    = "posts"
+	FeedComments = "comments"
+	FeedLikes    = "likes"
+	FeedReceived = "received"
+)
+
+// Feed item kinds.
+const (
+	ItemTopic       = "topic"
+	ItemComment     = "comment"
+	ItemTopicLike   = "topic_like"
+	ItemCommentLike = "comment_like"
+)
+
+// FeedItem is one entry of a filtered activity feed. CommentID is 0 for
+// entries about a topic, and ActorName, who left a like or comment, is only
+// set in the received feed.
+type FeedItem struct {
+	Kind       string
+	TopicTitle string
+	Content    string
+	ActorName  string
+	CreatedAt  string
 	TopicID    int
+	CommentID  int
 }
 
 type CommentVoteActivity struct {
@@ -4,4 +4,10 @@ import "context"
 
 type Repository interface {
 	GetUserActivity(ctx context.Context, userID string) (*Activity, error)
+	GetUserProfile(ctx context.Context, userID string, includePending bool) (*Profile, error)
+	GetUserStats(ctx context.Context, userID string) (*Stats, error)
+	GetUserSubmissions(ctx context.Context, userID string) (*Submissions, error)
+	// GetActivityFeed returns a page of userID's feed of the given type,
+	// newest first, along with the total number of items in it.
+	GetActivityFeed(ctx context.Context, userID, feedType string, limit, offset int) ([]FeedItem, int, error)
 }
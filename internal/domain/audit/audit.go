@@ -0,0 +1,32 @@
+package audit
+
+// Actions recorded in the audit log.
+const (
+	ActionApproveComment = "approve_comment"
+	ActionRejectComment  = "reject_comment"
+	ActionResolveReports = "resolve_reports"
+	ActionDismissReports = "dismiss_reports"
+	ActionDeleteCategory = "delete_category"
+	ActionApproveRole    = "approve_role_request"
+	ActionRejectRole     = "reject_role_request"
+)
+
+// Kinds of record an audit entry can point at.
+const (
+	TargetComment     = "comment"
+	TargetCategory    = "category"
+	TargetRoleRequest = "role_request"
+)
+
+// Entry is one staff action. AdminID is whoever took it; AdminUsername is
+// filled in when reading the log and is empty once that account is gone.
+type Entry struct {
+	AdminID       string `json:"adminId"`
+	AdminUsername string `json:"adminUsername"`
+	Action        string `json:"action"`
+	TargetType    string `json:"targetType"`
+	TargetID      string `json:"targetId"`
+	Details       string `json:"details"`
+	CreatedAt     string `json:"createdAt"`
+	ID            int    `json:"id"`
+}
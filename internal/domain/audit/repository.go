@@ -0,0 +1,10 @@
+package audit
+
+import "context"
+
+type Repository interface {
+	LogAudit(ctx context.Context, entry *Entry) error
+	// GetAuditLog returns a page of entries, newest first, along with the
+	// total number of entries.
+	GetAuditLog(ctx context.Context, limit, offset int) ([]Entry, int, error)
+}
@@ -3,14 +3,18 @@ package category
 import "github.com/arnald/forum/internal/domain/topic"
 
 type Category struct {
-	Name        string        `json:"name"`
-	Description string        `json:"description"`
-	CreatedAt   string        `json:"createdAt"`
-	CreatedBy   string        `json:"createdBy"`
-	ImagePath   string        `json:"imagePath"`
-	Color       string        `json:"color"`
-	Slug        string        `json:"slug"`
-	Topics      []topic.Topic `json:"topics"`
-	ID          int           `json:"id"`
-	TopicCount  int           `json:"topicsCount"`
+	Name          string        `json:"name"`
+	Description   string        `json:"description"`
+	CreatedAt     string        `json:"createdAt"`
+	CreatedBy     string        `json:"createdBy"`
+	ImagePath     string        `json:"imagePath"`
+	Color         string        `json:"color"`
+	Slug          string        `json:"slug"`
+	Topics        []topic.Topic `json:"topics"`
+	ID            int           `json:"id"`
+	TopicCount    int           `json:"topicsCount"`
+	RequiresImage bool          `json:"requiresImage"`
+	// Archived categories are hidden from listings and cannot take new
+	// topics, but the topics already filed under them stay reachable.
+	Archived bool `json:"archived"`
 }
@@ -6,11 +6,17 @@ import (
 
 type Repository interface {
 	CreateCategory(ctx context.Context, category *Category) error
-	DeleteCategory(ctx context.Context, id int, userID string) error
+	DeleteCategory(ctx context.Context, id int, userID string, force bool) error
 	UpdateCategory(ctx context.Context, category *Category) error
 	GetCategoryByID(ctx context.Context, id int) (*Category, error)
 	GetAllCategories(ctx context.Context, page, size int, orderBy, order, filter string) ([]Category, error)
-	PopulateCategoriesWithTopics(ctx context.Context, categories []Category) ([]Category, error)
+	PopulateCategoriesWithTopics(ctx context.Context, categories []Category, canonicalOnly bool) ([]Category, error)
 	GetTotalCategoriesCount(ctx context.Context, filter string) (int, error)
 	GetAllCategorieNamesAndIDs(ctx context.Context) ([]Category, error)
+	GetCategoriesWithCounts(ctx context.Context) ([]Category, error)
+	SetCategoryArchived(ctx context.Context, id int, archived bool) error
+	// AssignModerator lets userID moderate comments in the category. Assigning
+	// someone already assigned is not an error.
+	AssignModerator(ctx context.Context, categoryID int, userID string) error
+	UnassignModerator(ctx context.Context, categoryID int, userID string) error
 }
@@ -1,15 +1,63 @@
 package comment
 
+// Moderation states of a comment. Only approved comments are shown to users
+// other than the author.
+const (
+	StatusApproved = "approved"
+	StatusPending  = "pending"
+	StatusRejected = "rejected"
+)
+
+// MaxRejectionNoteLength caps the text a moderator writes when rejecting a
+// comment.
+const MaxRejectionNoteLength = 200
+
+// RejectionReasonOther is the reason code for a rejection the moderator
+// explains in their own words.
+const RejectionReasonOther = "other"
+
+// RejectionReason is one of the stock explanations a moderator can pick when
+// rejecting a comment.
+type RejectionReason struct {
+	Code string `json:"code"`
+	Text string `json:"text"`
+}
+
+// RejectionReasons are the stock explanations offered to moderators, in the
+// order they are listed.
+var RejectionReasons = []RejectionReason{
+	{Code: "off_topic", Text: "Off-topic for this discussion"},
+	{Code: "abusive", Text: "Abusive or disrespectful"},
+	{Code: "spam", Text: "Spam or advertising"},
+	{Code: "duplicate", Text: "Repeats an earlier comment"},
+	{Code: "low_effort", Text: "Does not add to the discussion"},
+}
+
 type Comment struct {
 	CreatedAt     string
 	UpdatedAt     string
 	UserVote      *int
+	ParentID      *int
 	UserID        string
 	Content       string
 	OwnerUsername string
-	TopicID       int
-	ID            int
-	UpvoteCount   int
-	DownvoteCount int
-	VoteScore     int
+	Status        string
+	ModeratedBy   string
+	// RejectionReason tells the author why a rejected comment was turned
+	// down; it is empty otherwise.
+	RejectionReason string
+	Replies         []Comment
+	TopicID         int
+	ID              int
+	UpvoteCount     int
+	DownvoteCount   int
+	VoteScore       int
+	// ReportCount is the number of pending reports against the comment.
+	ReportCount int
+	// Collapsed hides the comment behind a warning until a moderator
+	// settles its reports.
+	Collapsed bool
+	// IsEdited is set when the comment was changed some time after it was
+	// posted.
+	IsEdited bool
 }
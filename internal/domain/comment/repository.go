@@ -1,6 +1,11 @@
 package comment
 
-import "context"
+import (
+	"context"
+
+	"github.com/arnald/forum/internal/domain/audit"
+	"github.com/arnald/forum/internal/domain/notification"
+)
 
 type Repository interface {
 	CreateComment(ctx context.Context, comment *Comment) error
@@ -9,4 +14,27 @@ type Repository interface {
 	GetCommentByID(ctx context.Context, commentID int) (*Comment, error)      // TODO: make it return votes
 	GetCommentsByTopicID(ctx context.Context, topicID int) ([]Comment, error) // TODO: clean up (not returning votes)
 	GetCommentsWithVotes(ctx context.Context, topicID int, userID *string) ([]Comment, error)
+	GetCommentsWithVotesPage(ctx context.Context, topicID int, userID *string, limit, offset int) ([]Comment, error)
+	// GetCommentWithVotes returns one comment of the topic if it is visible
+	// to userID.
+	GetCommentWithVotes(ctx context.Context, topicID, commentID int, userID *string) (*Comment, error)
+	CountVisibleComments(ctx context.Context, topicID int, userID *string) (int, error)
+	// GetPendingComments returns the moderation queue. A non-empty
+	// moderatorID limits it to topics in the categories that moderator is
+	// assigned to.
+	GetPendingComments(ctx context.Context, moderatorID string) ([]Comment, error)
+	// SetCommentStatus settles a pending comment. A non-nil notice is
+	// addressed to the comment's author and stored in the same transaction;
+	// it is skipped, keeping ID 0, when the author's account is gone.
+	SetCommentStatus(ctx context.Context, commentID int, status, moderatorID, reason string, notice *notification.Notification) error
+	// SetCommentsStatus settles whichever of commentIDs are still pending and
+	// returns their IDs in ascending order; the rest are skipped. When
+	// auditEntry is non-nil, the entry it builds for each settled comment is
+	// written in the same transaction. It is given the comment's ID, UserID,
+	// TopicID, Status and ModeratedBy.
+	SetCommentsStatus(ctx context.Context, commentIDs []int, status, moderatorID string, auditEntry func(settled *Comment) *audit.Entry) ([]int, error)
+	// CommentsOutsideModeration returns, in ascending order, those of
+	// commentIDs whose topic is in none of the categories moderatorID is
+	// assigned to. IDs that match no comment are left out.
+	CommentsOutsideModeration(ctx context.Context, moderatorID string, commentIDs []int) ([]int, error)
 }
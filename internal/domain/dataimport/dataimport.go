@@ -0,0 +1,82 @@
+package dataimport
+
+// Document is a forum export to import. Every entity carries the id it had
+// in the source forum; references between entities use those source ids and
+// are remapped to fresh ids on import. CreatedAt is kept when given.
+type Document struct {
+	Users      []User     `json:"users"`
+	Categories []Category `json:"categories"`
+	Topics     []Topic    `json:"topics"`
+	Comments   []Comment  `json:"comments"`
+}
+
+// User is an imported account. It gets no password, so its owner has to
+// set one before logging in with it.
+type User struct {
+	ID        string `json:"id"`
+	Username  string `json:"username"`
+	Email     string `json:"email"`
+	CreatedAt string `json:"createdAt"`
+	// NewID is the id the account is stored under, assigned before import.
+	NewID string `json:"-"`
+}
+
+type Category struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	CreatedAt   string `json:"createdAt"`
+}
+
+type Topic struct {
+	ID          string   `json:"id"`
+	UserID      string   `json:"userId"`
+	Title       string   `json:"title"`
+	Content     string   `json:"content"`
+	CreatedAt   string   `json:"createdAt"`
+	CategoryIDs []string `json:"categoryIds"`
+}
+
+// Comment is an imported comment. A reply names its parent, which has to be
+// listed earlier in the document.
+type Comment struct {
+	ID        string `json:"id"`
+	TopicID   string `json:"topicId"`
+	UserID    string `json:"userId"`
+	ParentID  string `json:"parentId"`
+	Content   string `json:"content"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// Summary reports how each kind of entity fared. Nothing is stored unless
+// Committed is set; the counts then still show what would have been imported
+// and what stopped it.
+type Summary struct {
+	Users      EntitySummary `json:"users"`
+	Categories EntitySummary `json:"categories"`
+	Topics     EntitySummary `json:"topics"`
+	Comments   EntitySummary `json:"comments"`
+	Committed  bool          `json:"committed"`
+}
+
+type EntitySummary struct {
+	Failures []Failure `json:"failures,omitempty"`
+	Imported int       `json:"imported"`
+	Failed   int       `json:"failed"`
+}
+
+// Failure names an entity by its source id and says why it was rejected.
+type Failure struct {
+	SourceID string `json:"sourceId"`
+	Reason   string `json:"reason"`
+}
+
+func (s *EntitySummary) Fail(sourceID, reason string) {
+	s.Failed++
+	s.Failures = append(s.Failures, Failure{SourceID: sourceID, Reason: reason})
+}
+
+// HasFailures reports whether any entity was rejected.
+func (s *Summary) HasFailures() bool {
+	return s.Users.Failed+s.Categories.Failed+s.Topics.Failed+s.Comments.Failed > 0
+}
@@ -0,0 +1,9 @@
+package dataimport
+
+import "context"
+
+type Repository interface {
+	// Import stores doc in a single transaction, recording the outcome of
+	// every entity in summary. It commits only if nothing failed.
+	Import(ctx context.Context, doc *Document, importedBy string, summary *Summary) error
+}
@@ -1,25 +1,88 @@
 package notification
 
-import "time"
+import (
+	"fmt"
+	"slices"
+	"time"
+)
 
 type Type string
 
 const (
-	NotificationTypeReply   Type = "reply"
-	NotificationTypeMention Type = "mention"
-	NotificationTypeLike    Type = "like"
-	NotificationTypeDislike Type = "dislike"
+	NotificationTypeReply      Type = "reply"
+	NotificationTypeMention    Type = "mention"
+	NotificationTypeLike       Type = "like"
+	NotificationTypeDislike    Type = "dislike"
+	NotificationTypeModeration Type = "moderation"
+	// NotificationTypeWatchedComment goes to users watching a topic.
+	NotificationTypeWatchedComment Type = "new_comment_on_watched"
+	NotificationTypeAnswerAccepted Type = "answer_accepted"
+	// NotificationTypeRoleRequest tells a user how their role request was
+	// decided.
+	NotificationTypeRoleRequest Type = "role_request"
 )
 
+// OptionalTypes are the types a user may switch off. Moderation notices are
+// left out: they always reach the author, as do role request decisions.
+var OptionalTypes = []Type{
+	NotificationTypeReply,
+	NotificationTypeMention,
+	NotificationTypeLike,
+	NotificationTypeDislike,
+	NotificationTypeWatchedComment,
+	NotificationTypeAnswerAccepted,
+}
+
+// Optional reports whether users may switch t off.
+func (t Type) Optional() bool {
+	return slices.Contains(OptionalTypes, t)
+}
+
+// Preference records whether a user receives notifications of one type.
+type Preference struct {
+	Type    Type `json:"type"`
+	Enabled bool `json:"enabled"`
+}
+
 type Notification struct {
-	CreatedAt   time.Time `json:"createdAt"`
-	UserID      string    `json:"userId"`
-	ActorID     string    `json:"actorId"`
-	Type        Type      `json:"type"`
-	Title       string    `json:"title"`
-	Message     string    `json:"message"`
-	RelatedType string    `json:"relatedType,omitempty"`
-	RelatedID   string    `json:"relatedId,omitempty"`
-	ID          int       `json:"id"`
-	IsRead      bool      `json:"isRead"`
+	CreatedAt time.Time `json:"createdAt"`
+	UserID    string    `json:"userId"`
+	ActorID   string    `json:"actorId"`
+	// ActorName is not stored; it phrases the message of a folded vote
+	// notification after its latest voter.
+	ActorName   string `json:"-"`
+	Type        Type   `json:"type"`
+	Title       string `json:"title"`
+	Message     string `json:"message"`
+	RelatedType string `json:"relatedType,omitempty"`
+	RelatedID   string `json:"relatedId,omitempty"`
+	ID          int    `json:"id"`
+	// Count is how many events a coalesced notification stands for.
+	Count  int  `json:"count"`
+	IsRead bool `json:"isRead"`
+}
+
+// Folds reports whether notifications of t about the same content fold into
+// a single unread one rather than piling up.
+func (t Type) Folds() bool {
+	return t == NotificationTypeLike || t == NotificationTypeDislike
+}
+
+// VoteMessage phrases a like or dislike notification standing for count
+// votes, naming only the latest voter: "alice and 2 others liked your topic".
+func (n *Notification) VoteMessage(count int) string {
+	verb := "liked"
+	if n.Type == NotificationTypeDislike {
+		verb = "disliked"
+	}
+
+	others := count - 1
+	switch {
+	case others < 1:
+		return fmt.Sprintf("%s %s your %s", n.ActorName, verb, n.RelatedType)
+	case others == 1:
+		return fmt.Sprintf("%s and 1 other %s your %s", n.ActorName, verb, n.RelatedType)
+	default:
+		return fmt.Sprintf("%s and %d others %s your %s", n.ActorName, others, verb, n.RelatedType)
+	}
 }
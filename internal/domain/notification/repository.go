@@ -1,11 +1,20 @@
 package notification
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Repository interface {
 	Create(ctx context.Context, notification *Notification) error
+	CreateBatch(ctx context.Context, notifications []*Notification) error
+	CreateCoalescedBatch(ctx context.Context, notifications []*Notification, window time.Duration) ([]*Notification, error)
+	CreateFolded(ctx context.Context, notification *Notification, window time.Duration) (bool, error)
 	GetByUserID(ctx context.Context, userID string, limit int) ([]*Notification, error)
 	GetUnreadCount(ctx context.Context, userID string) (int, error)
 	MarkAsRead(ctx context.Context, notificationID int, userID string) error
 	MarkAllAsRead(ctx context.Context, userID string) error
+	IsEnabled(ctx context.Context, userID string, notificationType Type) (bool, error)
+	GetPreferences(ctx context.Context, userID string) ([]Preference, error)
+	SetPreference(ctx context.Context, userID string, preference Preference) error
 }
@@ -0,0 +1,32 @@
+package report
+
+// Reason is one entry in the admin-managed list of report reasons. Retired
+// reasons are no longer offered or accepted for new reports, but reports
+// already filed keep their reason text as it was.
+type Reason struct {
+	Label     string `json:"label"`
+	CreatedAt string `json:"createdAt"`
+	ID        int    `json:"id"`
+	Active    bool   `json:"active"`
+}
+
+// Lifecycle of a report. A dismissed report was judged unfounded; a resolved
+// one was upheld by a moderator.
+const (
+	StatusPending   = "pending"
+	StatusDismissed = "dismissed"
+	StatusResolved  = "resolved"
+)
+
+// Report is one user's complaint about a topic or a comment. Exactly one of
+// TopicID and CommentID is set; the other is 0.
+type Report struct {
+	ReporterID  string
+	Reason      string
+	Description string
+	Status      string
+	CreatedAt   string
+	ID          int
+	TopicID     int
+	CommentID   int
+}
@@ -0,0 +1,19 @@
+package report
+
+import "context"
+
+type Repository interface {
+	GetReasons(ctx context.Context, includeRetired bool) ([]Reason, error)
+	CreateReason(ctx context.Context, label string) (*Reason, error)
+	SetReasonActive(ctx context.Context, id int, active bool) error
+	IsActiveReason(ctx context.Context, label string) (bool, error)
+	// CreateCommentReport files a pending report against a comment and
+	// collapses the comment once it has collapseThreshold pending reports;
+	// 0 never collapses. It returns whether the comment is now collapsed.
+	CreateCommentReport(ctx context.Context, report *Report, collapseThreshold int) (bool, error)
+	// CreateTopicReport files a pending report against a topic.
+	CreateTopicReport(ctx context.Context, report *Report) error
+	// ResolveCommentReports settles every pending report on a comment with
+	// StatusDismissed or StatusResolved. Dismissing uncollapses the comment.
+	ResolveCommentReports(ctx context.Context, commentID int, status, moderatorID string) error
+}
@@ -0,0 +1,13 @@
+package rolerequest
+
+import "context"
+
+type Repository interface {
+	// CreateRoleRequest files request as pending and fills in its ID,
+	// Status and CreatedAt. A user may only have one pending request.
+	CreateRoleRequest(ctx context.Context, request *RoleRequest) error
+	GetRoleRequestByID(ctx context.Context, id int) (*RoleRequest, error)
+	// DecideRoleRequest settles a pending request with StatusApproved or
+	// StatusRejected on behalf of adminID.
+	DecideRoleRequest(ctx context.Context, id int, status, adminID, adminNotes string) error
+}
@@ -0,0 +1,22 @@
+package rolerequest
+
+// Lifecycle of a role request. Only pending requests can be decided.
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
+// RoleRequest is a user's application for a staff role. AdminID, AdminNotes
+// and ReviewedAt are empty until an admin decides it.
+type RoleRequest struct {
+	UserID        string `json:"userId"`
+	RequestedRole string `json:"requestedRole"`
+	Reason        string `json:"reason"`
+	Status        string `json:"status"`
+	AdminID       string `json:"adminId"`
+	AdminNotes    string `json:"adminNotes"`
+	CreatedAt     string `json:"createdAt"`
+	ReviewedAt    string `json:"reviewedAt"`
+	ID            int    `json:"id"`
+}
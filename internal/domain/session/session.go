@@ -5,6 +5,7 @@ import "time"
 type Session struct {
 	Expiry             time.Time `json:"expiry"`
 	RefreshTokenExpiry time.Time `json:"refreshTokenExpiry"`
+	AuthenticatedAt    time.Time `json:"authenticatedAt,omitzero"`
 	UserID             string    `json:"userId"`
 	AccessToken        string    `json:"accessToken"`
 	RefreshToken       string    `json:"refreshToken,omitzero"`
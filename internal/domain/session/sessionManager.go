@@ -3,17 +3,24 @@ package session
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/arnald/forum/internal/domain/user"
 )
 
 type Manager interface {
 	CreateSession(ctx context.Context, userID string) (*Session, error)
+	CreateSessionWithTTL(ctx context.Context, userID string, ttl time.Duration) (*Session, error)
 	GetSession(sessionID string) (*Session, error)
 	DeleteSession(sessionID string) error
 	GetUserFromSession(sessionID string) (*user.User, error)
 	GetSessionFromSessionTokens(sessionToken, refreshToken string) (*Session, error)
+	// RotateSession replaces the session holding refreshToken with a new one.
+	RotateSession(ctx context.Context, refreshToken string) (*Session, error)
 	ValidateSession(sessionID string) error
 	NewSessionCookie(token string) *http.Cookie
 	DeleteSessionWhenNewCreated(ctx context.Context, sessionID string, userID string) error
+	ConfirmAuthentication(ctx context.Context, sessionID string) error
+	InvalidateUserSessions(ctx context.Context, userID string) error
+	DeleteExpiredSessions(ctx context.Context) (int64, error)
 }
@@ -1,12 +1,37 @@
 package topic
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Repository interface {
 	CreateTopic(ctx context.Context, topic *Topic) error
+	// FindRecentDuplicate returns userID's newest topic with this exact title
+	// and content created within window, or nil.
+	FindRecentDuplicate(ctx context.Context, userID, title, content string, window time.Duration) (*Topic, error)
 	UpdateTopic(ctx context.Context, topic *Topic) error
+	GetTopicRevisions(ctx context.Context, topicID int) ([]Revision, error)
 	DeleteTopic(ctx context.Context, userID string, topicID int) error
+	RestoreTopic(ctx context.Context, topicID int) error
+	PublishTopic(ctx context.Context, userID string, topicID int) error
+	GetDraftTopics(ctx context.Context, userID string) ([]Topic, error)
+	IncrementTopicView(ctx context.Context, topicID int, viewerKey string, window time.Duration) (bool, error)
+	ToggleBookmark(ctx context.Context, userID string, topicID int) (bool, error)
+	GetBookmarkedTopics(ctx context.Context, userID string) ([]Topic, error)
 	GetTopicByID(ctx context.Context, topicID int, userID *string) (*Topic, error)
-	GetAllTopics(ctx context.Context, page, size, categoryID int, orderBy, order, filter string, userID *string) ([]Topic, error)
+	GetAllTopics(ctx context.Context, page, size, beforeID, categoryID int, orderBy, order, filter string, userID *string, controversy ControversyWeights) ([]Topic, error)
 	GetTotalTopicsCount(ctx context.Context, filter string, categoryID int) (int, error)
+	GetCategoriesRequiringImage(ctx context.Context, categoryIDs []int) ([]string, error)
+	GetExistingCategoryIDs(ctx context.Context, categoryIDs []int) ([]int, error)
+	// IsTopicRemoved reports whether the topic was soft-deleted after userID
+	// could see it, so that links to it may show a placeholder.
+	IsTopicRemoved(ctx context.Context, topicID int, userID *string) (bool, error)
+	// IsTopicVisible reports whether the topic is neither deleted nor a draft
+	// hidden from userID.
+	IsTopicVisible(ctx context.Context, topicID int, userID *string) (bool, error)
+	WatchTopic(ctx context.Context, userID string, topicID int) error
+	UnwatchTopic(ctx context.Context, userID string, topicID int) error
+	GetTopicWatchers(ctx context.Context, topicID int) ([]string, error)
+	SetAcceptedAnswer(ctx context.Context, topicID, commentID int) error
 }
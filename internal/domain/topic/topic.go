@@ -2,13 +2,35 @@ package topic
 
 import "github.com/arnald/forum/internal/domain/comment"
 
+// Publication states of a topic. Drafts are shown only to their author.
+const (
+	StatusPublished = "published"
+	StatusDraft     = "draft"
+)
+
+// ControversyWeights tune the "controversial" ordering. A topic needs at least
+// MinVotes votes to rank at all; BalanceWeight, between 0 and 1, sets how much
+// an even up/down split counts next to the raw number of opposing votes.
+type ControversyWeights struct {
+	BalanceWeight float64
+	MinVotes      int
+}
+
 type Topic struct {
-	UserVote       *int
-	UpdatedAt      string
-	Title          string
-	Content        string
+	UserVote  *int
+	UpdatedAt string
+	Title     string
+	// Slug is the URL segment derived from Title, unique among topics; it
+	// may be empty for topics that predate slugs.
+	Slug    string
+	Content string
+	Status  string
+	// Summary is an optional plain-text TL;DR shown in listings in place of
+	// the truncated content.
+	Summary        string
 	ImagePath      string
 	CreatedAt      string
+	BumpedAt       string
 	UserID         string
 	OwnerUsername  string
 	CategoryNames  []string
@@ -16,7 +38,35 @@ type Topic struct {
 	Comments       []comment.Comment
 	CategoryIDs    []int
 	ID             int
-	UpvoteCount    int
-	DownvoteCount  int
-	VoteScore      int
+	// CanonicalCategoryID is the primary category among CategoryIDs, or 0
+	// when the topic has none.
+	CanonicalCategoryID int
+	// AcceptedCommentID is the comment accepted as the answer to a question
+	// topic, or 0.
+	AcceptedCommentID int
+	// CommentCount is the number of comments visible to the viewer; it can
+	// differ from len(Comments) when comments are loaded a page at a time.
+	CommentCount  int
+	UpvoteCount   int
+	DownvoteCount int
+	VoteScore     int
+	ViewCount     int
+	// Removed marks a placeholder for a topic that was deleted while other
+	// content still referenced it.
+	Removed    bool
+	IsQuestion bool
+	// IsEdited is set when the topic was changed some time after it was
+	// posted.
+	IsEdited bool
+}
+
+// Revision is the title and content a topic had before one of its edits.
+type Revision struct {
+	Title          string
+	Content        string
+	EditorID       string
+	EditorUsername string
+	CreatedAt      string
+	ID             int
+	TopicID        int
 }
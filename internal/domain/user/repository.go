@@ -2,11 +2,30 @@ package user
 
 import (
 	"context"
+	"time"
 )
 
 type Repository interface {
-	GetAll(ctx context.Context) ([]User, error)
+	// GetAll returns a page of users, oldest account first, along with the
+	// total number of users.
+	GetAll(ctx context.Context, limit, offset int) ([]User, int, error)
 	UserRegister(ctx context.Context, user *User) error
 	GetUserByUsername(ctx context.Context, username string) (*User, error)
 	GetUserByEmail(ctx context.Context, email string) (*User, error)
+	UsernameExists(ctx context.Context, username string) (bool, error)
+	UpdateUsername(ctx context.Context, userID, newName string) error
+	GetUsernameHistory(ctx context.Context, userID string) ([]string, error)
+	UpdatePassword(ctx context.Context, userID, passwordHash string) error
+	// UpdateUserRole gives the user role, one of RoleUser, RoleModerator and
+	// RoleAdmin.
+	UpdateUserRole(ctx context.Context, userID, role string) error
+	// UpdateAvatar sets the user's avatar and returns the one it replaced.
+	UpdateAvatar(ctx context.Context, userID, avatarURL string) (string, error)
+	CreatePasswordResetToken(ctx context.Context, userID, tokenHash string, expiresAt time.Time) error
+	ConsumePasswordResetToken(ctx context.Context, tokenHash, passwordHash string) (string, error)
+	CreateEmailVerificationToken(ctx context.Context, userID, tokenHash string, expiresAt time.Time) error
+	ConsumeEmailVerificationToken(ctx context.Context, tokenHash string) (string, error)
+	GetLoginLockout(ctx context.Context, userID string) (time.Time, error)
+	RecordFailedLogin(ctx context.Context, userID string, maxAttempts int, lockFor time.Duration) (time.Time, error)
+	ResetFailedLogins(ctx context.Context, userID string) error
 }
@@ -4,6 +4,12 @@ import (
 	"time"
 )
 
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
 type User struct {
 	CreatedAt time.Time
 	Password  string
@@ -12,4 +18,18 @@ type User struct {
 	Email     string
 	Role      string
 	ID        string
+	// EmailVerified is set once the owner follows their verification link;
+	// OAuth accounts start out verified by their provider.
+	EmailVerified bool
+}
+
+// IsModerator reports whether the user may act on the moderation queue.
+func (u *User) IsModerator() bool {
+	return u.Role == RoleModerator || u.Role == RoleAdmin
+}
+
+// IsAdmin reports whether the user may manage site-wide settings such as
+// categories.
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
 }
@@ -1,9 +1,18 @@
 package vote
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Repository interface {
 	CastVote(ctx context.Context, userID string, target Target, reactionType int) error
 	DeleteVote(ctx context.Context, userID string, topicID *int, coommentID *int) error
 	GetCounts(ctx context.Context, target Target) (*Counts, error)
+	// GetUserReaction returns the user's current reaction to target, or 0
+	// when they haven't voted on it.
+	GetUserReaction(ctx context.Context, userID string, target Target) (int, error)
+	// CountCastsSince counts the votes the user has cast or switched since
+	// the given time, including ones they have since removed.
+	CountCastsSince(ctx context.Context, userID string, since time.Time) (int, error)
 }
@@ -2,7 +2,9 @@ package getuseractivity
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/arnald/forum/internal/app"
 	activityQueries "github.com/arnald/forum/internal/app/activities/queries"
@@ -10,6 +12,7 @@ import (
 	"github.com/arnald/forum/internal/domain/activity"
 	"github.com/arnald/forum/internal/infra/logger"
 	"github.com/arnald/forum/internal/infra/middleware"
+	activities "github.com/arnald/forum/internal/infra/storage/sqlite/activity"
 	"github.com/arnald/forum/internal/pkg/helpers"
 )
 
@@ -21,6 +24,15 @@ type ResponseModel struct {
 	DislikedComments []activity.CommentVoteActivity `json:"dislikedComments"`
 	UserComments     []activity.CommentActivity     `json:"userComments"`
 }
+
+// FeedResponseModel is a page of one activity feed, returned when the request
+// names a type.
+type FeedResponseModel struct {
+	Pagination map[string]interface{} `json:"pagination"`
+	Type       string                 `json:"type"`
+	Items      []activity.FeedItem    `json:"items"`
+}
+
 type Handler struct {
 	Services app.Services
 	Config   *config.ServerConfig
@@ -52,6 +64,12 @@ func (h *Handler) GetUserActivity(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	feedType := r.URL.Query().Get("type")
+	if feedType != "" {
+		h.getActivityFeed(ctx, w, r, user.ID, feedType)
+		return
+	}
+
 	activity, err := h.Services.UserServices.Queries.GetUserActivity.Handle(ctx, activityQueries.GetUserActivityRequest{
 		UserID: user.ID,
 	})
@@ -64,3 +82,57 @@ func (h *Handler) GetUserActivity(w http.ResponseWriter, r *http.Request) {
 	helpers.RespondWithJSON(w, http.StatusOK, nil, activity)
 	h.Logger.PrintInfo("User activity retrieved successfully", map[string]string{"userID": user.ID})
 }
+
+// getActivityFeed answers ?type=posts|comments|likes|received with a page of
+// that feed alone.
+func (h *Handler) getActivityFeed(ctx context.Context, w http.ResponseWriter, r *http.Request, userID, feedType string) {
+	pagination := helpers.GetPagination(r)
+
+	items, total, err := h.Services.UserServices.Queries.GetActivityFeed.Handle(ctx, activityQueries.GetActivityFeedRequest{
+		UserID: userID,
+		Type:   feedType,
+		Limit:  pagination.Limit,
+		Offset: pagination.Offset,
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		if errors.Is(err, activities.ErrUnknownFeedType) {
+			helpers.RespondWithError(w, http.StatusBadRequest, "type must be one of posts, comments, likes or received")
+			return
+		}
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to get user activity")
+		return
+	}
+
+	totalPages := (total + pagination.Limit - 1) / pagination.Limit
+
+	paginationMeta := map[string]interface{}{
+		"page":        pagination.Page,
+		"limit":       pagination.Limit,
+		"total":       total,
+		"total_pages": totalPages,
+		"has_next":    pagination.Page < totalPages,
+		"has_prev":    pagination.Page > 1,
+		"next_page":   nil,
+		"prev_page":   nil,
+	}
+
+	if pagination.Page < totalPages {
+		paginationMeta["next_page"] = pagination.Page + 1
+	}
+	// A page past the end links back to the last page that has items.
+	if pagination.Page > 1 {
+		paginationMeta["prev_page"] = min(pagination.Page-1, max(totalPages, 1))
+	}
+
+	helpers.RespondWithJSON(w, http.StatusOK, nil, FeedResponseModel{
+		Type:       feedType,
+		Items:      items,
+		Pagination: paginationMeta,
+	})
+	h.Logger.PrintInfo("User activity feed retrieved successfully", map[string]string{
+		"userID": userID,
+		"type":   feedType,
+		"page":   strconv.Itoa(pagination.Page),
+	})
+}
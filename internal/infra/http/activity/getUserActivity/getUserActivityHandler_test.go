@@ -0,0 +1,194 @@
+package getuseractivity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/arnald/forum/internal/app"
+	activityQueries "github.com/arnald/forum/internal/app/activities/queries"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/domain/activity"
+	"github.com/arnald/forum/internal/domain/user"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/middleware"
+	activities "github.com/arnald/forum/internal/infra/storage/sqlite/activity"
+)
+
+// feedQuery pretends every feed holds total items, hands back one item of the
+// requested type and keeps the request.
+type feedQuery struct {
+	got   *activityQueries.GetActivityFeedRequest
+	total int
+}
+
+func (q feedQuery) Handle(_ context.Context, req activityQueries.GetActivityFeedRequest) ([]activity.FeedItem, int, error) {
+	*q.got = req
+	switch req.Type {
+	case activity.FeedPosts, activity.FeedComments, activity.FeedLikes, activity.FeedReceived:
+		return []activity.FeedItem{{Kind: req.Type}}, q.total, nil
+	}
+	return nil, 0, fmt.Errorf("feed type %q: %w", req.Type, activities.ErrUnknownFeedType)
+}
+
+// activityQuery answers the unfiltered activity page with nothing.
+type activityQuery struct{}
+
+func (activityQuery) Handle(_ context.Context, _ activityQueries.GetUserActivityRequest) (*activity.Activity, error) {
+	return &activity.Activity{}, nil
+}
+
+func newTestHandler(got *activityQueries.GetActivityFeedRequest, total int) *Handler {
+	services := app.Services{
+		UserServices: app.UserServices{
+			Queries: app.Queries{
+				GetUserActivity: activityQuery{},
+				GetActivityFeed: feedQuery{got: got, total: total},
+			},
+		},
+	}
+	cfg := &config.ServerConfig{
+		Timeouts: config.TimeoutsConfig{
+			HandlerTimeouts: config.HandlerTimeoutsConfig{UserRegister: time.Second},
+		},
+	}
+	return NewHandler(services, cfg, logger.New(io.Discard, logger.LevelOff))
+}
+
+func getActivity(h *Handler, query string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/user/activity?"+query, nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.Key("user"), &user.User{ID: "alice"}))
+
+	rec := httptest.NewRecorder()
+	h.GetUserActivity(rec, req)
+	return rec
+}
+
+func TestHandler_GetUserActivity_Type(t *testing.T) {
+	for _, feedType := range []string{activity.FeedPosts, activity.FeedComments, activity.FeedLikes, activity.FeedReceived} {
+		t.Run(feedType, func(t *testing.T) {
+			var got activityQueries.GetActivityFeedRequest
+			rec := getActivity(newTestHandler(&got, 1), "type="+feedType)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("GetUserActivity() status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+			}
+			if got.UserID != "alice" || got.Type != feedType {
+				t.Errorf("feed request = %+v, want alice's %s feed", got, feedType)
+			}
+
+			var body struct {
+				Data struct {
+					Type  string              `json:"type"`
+					Items []activity.FeedItem `json:"items"`
+				} `json:"data"`
+			}
+			err := json.NewDecoder(rec.Body).Decode(&body)
+			if err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if body.Data.Type != feedType || len(body.Data.Items) != 1 || body.Data.Items[0].Kind != feedType {
+				t.Errorf("response = %+v, want the %s feed", body.Data, feedType)
+			}
+		})
+	}
+
+	t.Run("unknown type is rejected", func(t *testing.T) {
+		var got activityQueries.GetActivityFeedRequest
+		rec := getActivity(newTestHandler(&got, 1), "type=everything")
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("GetUserActivity() status = %d, want %d", rec.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("no type keeps the combined activity", func(t *testing.T) {
+		var got activityQueries.GetActivityFeedRequest
+		rec := getActivity(newTestHandler(&got, 1), "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GetUserActivity() status = %d, want %d", rec.Code, http.StatusOK)
+		}
+		if got.Type != "" {
+			t.Errorf("combined activity asked for the %s feed", got.Type)
+		}
+	})
+}
+
+func TestHandler_GetUserActivity_Pagination(t *testing.T) {
+	testCases := []struct {
+		name       string
+		query      string
+		wantOffset int
+		want       map[string]any
+	}{
+		{
+			name:       "first page",
+			query:      "type=posts&page=1&limit=10",
+			wantOffset: 0,
+			want: map[string]any{
+				"page": 1.0, "limit": 10.0, "total": 25.0, "total_pages": 3.0,
+				"has_next": true, "has_prev": false, "next_page": 2.0, "prev_page": nil,
+			},
+		},
+		{
+			name:       "last partial page",
+			query:      "type=posts&page=3&limit=10",
+			wantOffset: 20,
+			want: map[string]any{
+				"page": 3.0, "total_pages": 3.0,
+				"has_next": false, "has_prev": true, "next_page": nil, "prev_page": 2.0,
+			},
+		},
+		{
+			name:       "page past the end links back to the last page",
+			query:      "type=posts&page=7&limit=10",
+			wantOffset: 60,
+			want: map[string]any{
+				"page": 7.0, "total_pages": 3.0,
+				"has_next": false, "has_prev": true, "next_page": nil, "prev_page": 3.0,
+			},
+		},
+		{
+			name:       "invalid page and limit fall back to defaults",
+			query:      "type=posts&page=0&limit=500",
+			wantOffset: 0,
+			want: map[string]any{
+				"page": 1.0, "limit": 20.0, "total_pages": 2.0, "has_prev": false, "prev_page": nil,
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			var got activityQueries.GetActivityFeedRequest
+			rec := getActivity(newTestHandler(&got, 25), tt.query)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("GetUserActivity() status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+			}
+			if got.Offset != tt.wantOffset {
+				t.Errorf("feed request offset = %d, want %d", got.Offset, tt.wantOffset)
+			}
+
+			var body struct {
+				Data struct {
+					Pagination map[string]any `json:"pagination"`
+				} `json:"data"`
+			}
+			err := json.NewDecoder(rec.Body).Decode(&body)
+			if err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			for key, want := range tt.want {
+				if got := body.Data.Pagination[key]; got != want {
+					t.Errorf("pagination[%s] = %v, want %v", key, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package getuserprofile
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/arnald/forum/internal/app"
+	activityQueries "github.com/arnald/forum/internal/app/activities/queries"
+	userQueries "github.com/arnald/forum/internal/app/user/queries"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/domain/activity"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/middleware"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/users"
+	"github.com/arnald/forum/internal/pkg/helpers"
+)
+
+type ResponseModel struct {
+	JoinedAt  time.Time                  `json:"joinedAt"`
+	Username  string                     `json:"username"`
+	AvatarURL *string                    `json:"avatarUrl"`
+	Topics    []activity.TopicActivity   `json:"topics"`
+	Comments  []activity.CommentActivity `json:"comments"`
+	Stats     StatsModel                 `json:"stats"`
+	IsOwner   bool                       `json:"isOwner"`
+}
+
+type StatsModel struct {
+	Posts         int `json:"posts"`
+	Comments      int `json:"comments"`
+	LikesReceived int `json:"likesReceived"`
+}
+
+type Handler struct {
+	Services app.Services
+	Config   *config.ServerConfig
+	Logger   logger.Logger
+}
+
+func NewHandler(services app.Services, config *config.ServerConfig, logger logger.Logger) *Handler {
+	return &Handler{
+		Services: services,
+		Config:   config,
+		Logger:   logger,
+	}
+}
+
+func (h *Handler) GetUserProfile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserRegister)
+	defer cancel()
+
+	profileUser, err := h.Services.UserServices.Queries.GetUserByUsername.Handle(ctx, userQueries.GetUserByUsernameRequest{
+		Username: r.PathValue("username"),
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		if errors.Is(err, users.ErrUserNotFound) {
+			helpers.RespondWithError(w, http.StatusNotFound, "User not found")
+			return
+		}
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to get user profile")
+		return
+	}
+
+	viewer := middleware.GetUserFromContext(r)
+	isOwner := viewer != nil && viewer.ID == profileUser.ID
+
+	profile, err := h.Services.UserServices.Queries.GetUserProfile.Handle(ctx, activityQueries.GetUserProfileRequest{
+		UserID:         profileUser.ID,
+		IncludePending: isOwner,
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to get user profile")
+		return
+	}
+
+	helpers.RespondWithJSON(w, http.StatusOK, nil, ResponseModel{
+		JoinedAt:  profileUser.CreatedAt,
+		Username:  profileUser.Username,
+		AvatarURL: profileUser.AvatarURL,
+		Topics:    profile.Topics,
+		Comments:  profile.Comments,
+		Stats: StatsModel{
+			Posts:         profile.Stats.Posts,
+			Comments:      profile.Stats.Comments,
+			LikesReceived: profile.Stats.LikesReceived,
+		},
+		IsOwner: isOwner,
+	})
+	h.Logger.PrintInfo("User profile retrieved successfully", map[string]string{"userID": profileUser.ID})
+}
@@ -0,0 +1,69 @@
+package getusersubmissions
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/arnald/forum/internal/app"
+	activityQueries "github.com/arnald/forum/internal/app/activities/queries"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/domain/activity"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/middleware"
+	"github.com/arnald/forum/internal/pkg/helpers"
+)
+
+type ResponseModel struct {
+	Pending  []activity.CommentActivity `json:"pending"`
+	Approved []activity.CommentActivity `json:"approved"`
+	Rejected []activity.CommentActivity `json:"rejected"`
+}
+
+type Handler struct {
+	Services app.Services
+	Config   *config.ServerConfig
+	Logger   logger.Logger
+}
+
+func NewHandler(services app.Services, config *config.ServerConfig, logger logger.Logger) *Handler {
+	return &Handler{
+		Services: services,
+		Config:   config,
+		Logger:   logger,
+	}
+}
+
+// GetUserSubmissions lists the signed-in user's own comments by moderation
+// status. Only ever the caller's: there is no user parameter to ask for.
+func (h *Handler) GetUserSubmissions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserRegister)
+	defer cancel()
+
+	user := middleware.GetUserFromContext(r)
+	if user == nil {
+		h.Logger.PrintError(logger.ErrUserNotFoundInContext, nil)
+		helpers.RespondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	submissions, err := h.Services.UserServices.Queries.GetUserSubmissions.Handle(ctx, activityQueries.GetUserSubmissionsRequest{
+		UserID: user.ID,
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to get submissions")
+		return
+	}
+
+	helpers.RespondWithJSON(w, http.StatusOK, nil, ResponseModel{
+		Pending:  submissions.Pending,
+		Approved: submissions.Approved,
+		Rejected: submissions.Rejected,
+	})
+}
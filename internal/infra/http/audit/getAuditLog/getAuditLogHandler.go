@@ -0,0 +1,82 @@
+package getauditlog
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/arnald/forum/internal/app"
+	auditqueries "github.com/arnald/forum/internal/app/audit/queries"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/domain/audit"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/pkg/helpers"
+)
+
+type ResponseModel struct {
+	Pagination map[string]interface{} `json:"pagination"`
+	Entries    []audit.Entry          `json:"entries"`
+}
+
+type Handler struct {
+	UserServices app.Services
+	Config       *config.ServerConfig
+	Logger       logger.Logger
+}
+
+func NewHandler(userServices app.Services, config *config.ServerConfig, logger logger.Logger) *Handler {
+	return &Handler{
+		UserServices: userServices,
+		Config:       config,
+		Logger:       logger,
+	}
+}
+
+// GetAuditLog lists staff actions, newest first, a page at a time. The route
+// is guarded by RequireAdmin.
+func (h *Handler) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserRegister)
+	defer cancel()
+
+	pagination := helpers.GetPagination(r)
+
+	entries, total, err := h.UserServices.UserServices.Queries.GetAuditLog.Handle(ctx, auditqueries.GetAuditLogRequest{
+		Limit:  pagination.Limit,
+		Offset: pagination.Offset,
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to get audit log")
+		return
+	}
+
+	totalPages := (total + pagination.Limit - 1) / pagination.Limit
+
+	paginationMeta := map[string]interface{}{
+		"page":       pagination.Page,
+		"limit":      pagination.Limit,
+		"totalPages": totalPages,
+		"totalItems": total,
+		"has_next":   pagination.Page < totalPages,
+		"has_prev":   pagination.Page > 1,
+		"next_page":  nil,
+		"prev_page":  nil,
+	}
+
+	if pagination.Page < totalPages {
+		paginationMeta["next_page"] = pagination.Page + 1
+	}
+	if pagination.Page > 1 {
+		paginationMeta["prev_page"] = pagination.Page - 1
+	}
+
+	helpers.RespondWithJSON(w, http.StatusOK, nil, ResponseModel{
+		Entries:    entries,
+		Pagination: paginationMeta,
+	})
+}
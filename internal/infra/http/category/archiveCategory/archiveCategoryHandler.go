@@ -0,0 +1,118 @@
+package archivecategory
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/arnald/forum/internal/app"
+	categorycommands "github.com/arnald/forum/internal/app/categories/commands"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/middleware"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/categories"
+	"github.com/arnald/forum/internal/pkg/helpers"
+	"github.com/arnald/forum/internal/pkg/validator"
+)
+
+type ResponseModel struct {
+	Message    string `json:"message"`
+	CategoryID int    `json:"categoryId"`
+	Archived   bool   `json:"archived"`
+}
+
+type Handler struct {
+	UserServices app.Services
+	Config       *config.ServerConfig
+	Logger       logger.Logger
+}
+
+func NewHandler(userServices app.Services, config *config.ServerConfig, logger logger.Logger) *Handler {
+	return &Handler{
+		UserServices: userServices,
+		Config:       config,
+		Logger:       logger,
+	}
+}
+
+func (h *Handler) ArchiveCategory(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, true)
+}
+
+func (h *Handler) UnarchiveCategory(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, false)
+}
+
+func (h *Handler) setArchived(w http.ResponseWriter, r *http.Request, archived bool) {
+	if r.Method != http.MethodPost {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	user := middleware.GetUserFromContext(r)
+	if user == nil {
+		h.Logger.PrintError(logger.ErrUserNotFoundInContext, nil)
+		helpers.RespondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserRegister)
+	defer cancel()
+
+	categoryID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		helpers.RespondWithError(w, http.StatusBadRequest, "Invalid category ID")
+		return
+	}
+
+	val := validator.New()
+	validator.ValidateDeleteCategory(val, &struct {
+		CategoryID int
+	}{
+		CategoryID: categoryID,
+	})
+
+	if !val.Valid() {
+		h.Logger.PrintError(logger.ErrValidationFailed, val.Errors)
+		helpers.RespondWithError(w, http.StatusBadRequest, val.ToStringErrors())
+		return
+	}
+
+	err = h.UserServices.UserServices.Commands.ArchiveCategory.Handle(ctx, categorycommands.ArchiveCategoryRequest{
+		CategoryID: categoryID,
+		Archived:   archived,
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		if errors.Is(err, categories.ErrCategoryNotFound) {
+			helpers.RespondWithError(w, http.StatusNotFound, "Category not found")
+			return
+		}
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Error updating category")
+		return
+	}
+
+	message := "Category unarchived successfully"
+	if archived {
+		message = "Category archived successfully"
+	}
+
+	helpers.RespondWithJSON(w,
+		http.StatusOK,
+		nil,
+		ResponseModel{
+			CategoryID: categoryID,
+			Archived:   archived,
+			Message:    message,
+		})
+
+	h.Logger.PrintInfo(
+		message,
+		map[string]string{
+			"cat_id":  strconv.Itoa(categoryID),
+			"user_id": user.ID,
+		})
+}
@@ -0,0 +1,133 @@
+package categorymoderators
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/arnald/forum/internal/app"
+	categorycommands "github.com/arnald/forum/internal/app/categories/commands"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/middleware"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/categories"
+	"github.com/arnald/forum/internal/pkg/helpers"
+	"github.com/arnald/forum/internal/pkg/validator"
+)
+
+type ResponseModel struct {
+	Message    string `json:"message"`
+	UserID     string `json:"userId"`
+	CategoryID int    `json:"categoryId"`
+	Assigned   bool   `json:"assigned"`
+}
+
+type Handler struct {
+	UserServices app.Services
+	Config       *config.ServerConfig
+	Logger       logger.Logger
+}
+
+func NewHandler(userServices app.Services, config *config.ServerConfig, logger logger.Logger) *Handler {
+	return &Handler{
+		UserServices: userServices,
+		Config:       config,
+		Logger:       logger,
+	}
+}
+
+func (h *Handler) AssignModerator(w http.ResponseWriter, r *http.Request) {
+	h.setModerator(w, r, true)
+}
+
+func (h *Handler) UnassignModerator(w http.ResponseWriter, r *http.Request) {
+	h.setModerator(w, r, false)
+}
+
+// setModerator assigns the user named by the user_id form value to moderate
+// the category in the path, or removes them.
+func (h *Handler) setModerator(w http.ResponseWriter, r *http.Request, assigned bool) {
+	if r.Method != http.MethodPost {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	admin := middleware.GetUserFromContext(r)
+	if admin == nil {
+		h.Logger.PrintError(logger.ErrUserNotFoundInContext, nil)
+		helpers.RespondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserRegister)
+	defer cancel()
+
+	categoryID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		helpers.RespondWithError(w, http.StatusBadRequest, "Invalid category ID")
+		return
+	}
+
+	moderatorModel := &struct {
+		UserID     string
+		CategoryID int
+	}{
+		UserID:     strings.TrimSpace(r.FormValue("user_id")),
+		CategoryID: categoryID,
+	}
+
+	val := validator.New()
+	validator.ValidateSetCategoryModerator(val, moderatorModel)
+	if !val.Valid() {
+		h.Logger.PrintError(logger.ErrValidationFailed, val.Errors)
+		helpers.RespondWithError(w, http.StatusBadRequest, val.ToStringErrors())
+		return
+	}
+
+	err = h.UserServices.UserServices.Commands.SetCategoryMod.Handle(ctx, categorycommands.SetCategoryModeratorRequest{
+		UserID:     moderatorModel.UserID,
+		CategoryID: categoryID,
+		Assigned:   assigned,
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		switch {
+		case errors.Is(err, categories.ErrCategoryNotFound):
+			helpers.RespondWithError(w, http.StatusNotFound, "Category not found")
+		case errors.Is(err, categories.ErrUserNotFound):
+			helpers.RespondWithError(w, http.StatusNotFound, "User not found")
+		case errors.Is(err, categories.ErrModeratorNotAssigned):
+			helpers.RespondWithError(w, http.StatusNotFound, "User does not moderate this category")
+		default:
+			helpers.RespondWithError(w, http.StatusInternalServerError, "Error updating category moderators")
+		}
+		return
+	}
+
+	message := "Moderator unassigned successfully"
+	if assigned {
+		message = "Moderator assigned successfully"
+	}
+
+	helpers.RespondWithJSON(w,
+		http.StatusOK,
+		nil,
+		ResponseModel{
+			UserID:     moderatorModel.UserID,
+			CategoryID: categoryID,
+			Assigned:   assigned,
+			Message:    message,
+		})
+
+	h.Logger.PrintInfo(
+		message,
+		map[string]string{
+			"cat_id":       strconv.Itoa(categoryID),
+			"moderator_id": moderatorModel.UserID,
+			"admin_id":     admin.ID,
+		})
+}
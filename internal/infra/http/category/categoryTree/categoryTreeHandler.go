@@ -0,0 +1,93 @@
+package categorytree
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/arnald/forum/internal/app"
+	categoryqueries "github.com/arnald/forum/internal/app/categories/queries"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/domain/category"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/pkg/helpers"
+)
+
+// maxTreeCategories bounds the tree; a forum has far fewer categories.
+const maxTreeCategories = 1000
+
+// ResponseModel is wrapped in "data" by RespondWithJSON, which gives the
+// client's domain.CategoryData shape.
+type ResponseModel struct {
+	Categories []category.Category `json:"categories"`
+}
+
+type Handler struct {
+	expires      time.Time
+	UserServices app.Services
+	Config       *config.ServerConfig
+	Logger       logger.Logger
+	cached       []category.Category
+	mu           sync.Mutex
+}
+
+func NewHandler(userServices app.Services, config *config.ServerConfig, logger logger.Logger) *Handler {
+	return &Handler{
+		UserServices: userServices,
+		Config:       config,
+		Logger:       logger,
+	}
+}
+
+// GetCategoryTree returns every live category with its colour, description,
+// topic count and latest topics. The result is cached for
+// Config.Categories.TreeCacheTTL.
+func (h *Handler) GetCategoryTree(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserRegister)
+	defer cancel()
+
+	categories, err := h.categories(ctx)
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to get categories")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(h.Config.Categories.TreeCacheTTL.Seconds())))
+	helpers.RespondWithJSON(w, http.StatusOK, nil, ResponseModel{
+		Categories: categories,
+	})
+}
+
+func (h *Handler) categories(ctx context.Context) ([]category.Category, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.cached != nil && time.Now().Before(h.expires) {
+		return h.cached, nil
+	}
+
+	categories, _, err := h.UserServices.UserServices.Queries.GetAllCategories.Handle(ctx, categoryqueries.GetAllCategoriesRequest{
+		OrderBy:       "created_at",
+		Order:         "desc",
+		Page:          1,
+		Size:          maxTreeCategories,
+		CanonicalOnly: h.Config.Topics.CanonicalListings,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	h.cached = categories
+	h.expires = time.Now().Add(h.Config.Categories.TreeCacheTTL)
+
+	return categories, nil
+}
@@ -14,8 +14,10 @@ import (
 )
 
 type RequestModel struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	Color         string `json:"color"`
+	RequiresImage bool   `json:"requiresImage"`
 }
 
 type ResponseModel struct {
@@ -78,9 +80,11 @@ func (h *Handler) CreateCategory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	err = h.UserServices.UserServices.Commands.CreateCategory.Handle(ctx, categorycommands.CreateCategoryRequest{
-		Name:        categoryToCreate.Name,
-		Description: categoryToCreate.Description,
-		CreatedBy:   user.ID,
+		Name:          categoryToCreate.Name,
+		Description:   categoryToCreate.Description,
+		Color:         categoryToCreate.Color,
+		CreatedBy:     user.ID,
+		RequiresImage: categoryToCreate.RequiresImage,
 	})
 	if err != nil {
 		helpers.RespondWithError(w,
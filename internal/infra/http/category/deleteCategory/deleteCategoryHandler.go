@@ -2,6 +2,7 @@ package deletecategory
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -10,15 +11,24 @@ import (
 	"github.com/arnald/forum/internal/config"
 	"github.com/arnald/forum/internal/infra/logger"
 	"github.com/arnald/forum/internal/infra/middleware"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/categories"
 	"github.com/arnald/forum/internal/pkg/helpers"
 	"github.com/arnald/forum/internal/pkg/validator"
 )
 
 type ResponseModel struct {
 	Message    string `json:"message"`
+	Warning    string `json:"warning"`
 	CategoryID int    `json:"categoryId"`
 }
 
+// deleteWarning is returned with every deletion: unlike archiving, deleting
+// a category cannot be undone and removes it from all of its topics.
+const deleteWarning = "Deleting a category is permanent and removes it from every topic filed under it; archive it to hide it instead"
+
+// hasTopicsMessage explains why a category with topics was kept.
+const hasTopicsMessage = "Category still has topics; move them to another category, archive it, or delete with ?force=true"
+
 type Handler struct {
 	UserServices app.Services
 	Config       *config.ServerConfig
@@ -58,6 +68,7 @@ func (h *Handler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
 			http.StatusBadRequest,
 			err.Error(),
 		)
+		return
 	}
 
 	val := validator.New()
@@ -78,10 +89,18 @@ func (h *Handler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
 	err = h.UserServices.UserServices.Commands.DeleteCategory.Handle(ctx, categorycommands.DeleteCategoryRequest{
 		CategoryID: categoryID,
 		UserID:     user.ID,
+		Force:      r.URL.Query().Get("force") == "true",
 	})
 	if err != nil {
-		h.Logger.PrintError(err, nil)
-		helpers.RespondWithError(w, http.StatusInternalServerError, "Error deleting category")
+		switch {
+		case errors.Is(err, categories.ErrCategoryHasTopics):
+			helpers.RespondWithError(w, http.StatusBadRequest, hasTopicsMessage)
+		case errors.Is(err, categories.ErrCategoryNotFound):
+			helpers.RespondWithError(w, http.StatusNotFound, "Category not found")
+		default:
+			h.Logger.PrintError(err, nil)
+			helpers.RespondWithError(w, http.StatusInternalServerError, "Error deleting category")
+		}
 		return
 	}
 
@@ -91,6 +110,7 @@ func (h *Handler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
 		ResponseModel{
 			CategoryID: categoryID,
 			Message:    "Category deleted successfully",
+			Warning:    deleteWarning,
 		})
 
 	h.Logger.PrintInfo(
@@ -52,12 +52,13 @@ func (h *Handler) GetAllCategories(w http.ResponseWriter, r *http.Request) {
 	filter := params.GetQueryStringOr("search", "")
 
 	categories, totalCount, err := h.UserServices.UserServices.Queries.GetAllCategories.Handle(ctx, categoryqueries.GetAllCategoriesRequest{
-		OrderBy: orderBy,
-		Order:   order,
-		Filter:  filter,
-		Page:    pagination.Page,
-		Size:    pagination.Limit,
-		Offset:  pagination.Offset,
+		OrderBy:       orderBy,
+		Order:         order,
+		Filter:        filter,
+		Page:          pagination.Page,
+		Size:          pagination.Limit,
+		Offset:        pagination.Offset,
+		CanonicalOnly: h.Config.Topics.CanonicalListings,
 	})
 	if err != nil {
 		h.Logger.PrintError(err, nil)
@@ -14,10 +14,13 @@ import (
 	"github.com/arnald/forum/internal/pkg/validator"
 )
 
+// RequestModel leaves the category's image requirement alone when
+// requiresImage is omitted.
 type RequestModel struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	ID          int    `json:"id"`
+	RequiresImage *bool  `json:"requiresImage"`
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	ID            int    `json:"id"`
 }
 
 type ResponseModel struct {
@@ -71,7 +74,7 @@ func (h *Handler) UpdateCategory(w http.ResponseWriter, r *http.Request) {
 
 	val := validator.New()
 
-	validator.ValidateUpdateCategory(val, categoryToUpdate)
+	validator.ValidateUpdateCategory(val, &categoryToUpdate)
 	if !val.Valid() {
 		h.Logger.PrintError(logger.ErrValidationFailed, val.Errors)
 		helpers.RespondWithError(w,
@@ -81,9 +84,10 @@ func (h *Handler) UpdateCategory(w http.ResponseWriter, r *http.Request) {
 	}
 
 	err = h.UserServices.UserServices.Commands.UpdateCategory.Handle(ctx, categorycommands.UpdateCategoryRequest{
-		ID:          categoryToUpdate.ID,
-		Name:        categoryToUpdate.Name,
-		Description: categoryToUpdate.Description,
+		ID:            categoryToUpdate.ID,
+		Name:          categoryToUpdate.Name,
+		Description:   categoryToUpdate.Description,
+		RequiresImage: categoryToUpdate.RequiresImage,
 	})
 	if err != nil {
 		h.Logger.PrintError(err, nil)
@@ -2,29 +2,39 @@ package createcomment
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/arnald/forum/internal/app"
 	commentCommands "github.com/arnald/forum/internal/app/comments/commands"
+	topicCommands "github.com/arnald/forum/internal/app/topics/commands"
 	topicqueries "github.com/arnald/forum/internal/app/topics/queries"
+	userqueries "github.com/arnald/forum/internal/app/user/queries"
 	"github.com/arnald/forum/internal/config"
+	domainComment "github.com/arnald/forum/internal/domain/comment"
 	"github.com/arnald/forum/internal/domain/notification"
+	"github.com/arnald/forum/internal/domain/topic"
+	"github.com/arnald/forum/internal/domain/user"
 	"github.com/arnald/forum/internal/infra/logger"
 	"github.com/arnald/forum/internal/infra/middleware"
 	"github.com/arnald/forum/internal/infra/storage/notifications"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/comments"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/topics"
 	"github.com/arnald/forum/internal/pkg/helpers"
 	"github.com/arnald/forum/internal/pkg/validator"
 )
 
 type RequestModel struct {
-	Content string `json:"content"`
-	TopicID int    `json:"topicId"`
+	ParentID *int   `json:"parentId"`
+	Content  string `json:"content"`
+	TopicID  int    `json:"topicId"`
 }
 
 type ResponseModel struct {
 	Message   string `json:"message"`
+	Status    string `json:"status"`
 	CommentID int    `json:"commentId"`
 }
 
@@ -77,7 +87,7 @@ func (h *Handler) CreateComment(w http.ResponseWriter, r *http.Request) {
 
 	v := validator.New()
 
-	validator.ValidateCreateComment(v, commentAny)
+	validator.ValidateCreateComment(v, commentAny, h.Config.Limits)
 
 	if !v.Valid() {
 		helpers.RespondWithError(
@@ -91,11 +101,36 @@ func (h *Handler) CreateComment(w http.ResponseWriter, r *http.Request) {
 	}
 
 	comment, err := h.UserServices.UserServices.Commands.CreateComment.Handle(ctx, commentCommands.CreateCommentRequest{
-		TopicID: commentToCreate.TopicID,
-		Content: commentToCreate.Content,
-		User:    user,
+		TopicID:  commentToCreate.TopicID,
+		ParentID: commentToCreate.ParentID,
+		Content:  commentToCreate.Content,
+		User:     user,
+		Review: commentCommands.ReviewPolicy{
+			Enabled:       h.Config.Comments.NewAccountReview,
+			MinAccountAge: h.Config.Comments.NewAccountReviewAge,
+		},
 	})
 	if err != nil {
+		if errors.Is(err, topics.ErrTopicNotFound) {
+			helpers.RespondWithError(w,
+				http.StatusNotFound,
+				"Topic not found",
+			)
+
+			h.Logger.PrintError(err, nil)
+			return
+		}
+
+		if errors.Is(err, commentCommands.ErrParentCommentMismatch) || errors.Is(err, comments.ErrCommentNotFound) {
+			helpers.RespondWithError(w,
+				http.StatusBadRequest,
+				"Invalid parent comment",
+			)
+
+			h.Logger.PrintError(err, nil)
+			return
+		}
+
 		helpers.RespondWithError(w,
 			http.StatusInternalServerError,
 			"Failed to create comment",
@@ -105,34 +140,29 @@ func (h *Handler) CreateComment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	topic, err := h.UserServices.UserServices.Queries.GetTopic.Handle(ctx, topicqueries.GetTopicRequest{
-		UserID:  &user.ID,
-		TopicID: comment.TopicID,
-	})
-	if err != nil {
-		h.Logger.PrintError(err, nil)
+	commentResponse := ResponseModel{
+		CommentID: comment.ID,
+		Status:    comment.Status,
+		Message:   "Comment created successfully",
 	}
 
-	if user.ID != topic.UserID {
-		notification := &notification.Notification{
-			ActorID:     user.Username,
-			UserID:      topic.UserID,
-			RelatedID:   strconv.Itoa(comment.TopicID),
-			RelatedType: "topic",
-			Type:        notification.NotificationTypeReply,
-			Title:       "New comment",
-			Message:     fmt.Sprintf("%s commented on your Topic %s", user.Username, topic.Title),
-		}
-
-		err = h.Notification.CreateNotification(ctx, notification)
+	if h.Config.Comments.AutoWatch {
+		err = h.UserServices.UserServices.Commands.WatchTopic.Handle(ctx, topicCommands.WatchTopicRequest{
+			UserID:  user.ID,
+			TopicID: comment.TopicID,
+			Watch:   true,
+		})
 		if err != nil {
 			h.Logger.PrintError(err, nil)
 		}
 	}
 
-	commentResponse := ResponseModel{
-		CommentID: comment.ID,
-		Message:   "Comment created successfully",
+	// The topic owner and watchers hear about a held comment once it is
+	// approved.
+	if comment.Status == domainComment.StatusPending {
+		commentResponse.Message = "Comment submitted for review"
+	} else {
+		h.notifyNewComment(ctx, user, comment.TopicID, comment.Content)
 	}
 
 	helpers.RespondWithJSON(
@@ -150,3 +180,100 @@ func (h *Handler) CreateComment(w http.ResponseWriter, r *http.Request) {
 		},
 	)
 }
+
+func (h *Handler) notifyNewComment(ctx context.Context, author *user.User, topicID int, content string) {
+	topic, err := h.UserServices.UserServices.Queries.GetTopic.Handle(ctx, topicqueries.GetTopicRequest{
+		UserID:  &author.ID,
+		TopicID: topicID,
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		return
+	}
+
+	h.notifyTopicOwner(ctx, author, topic)
+	h.notifyWatchers(ctx, author, topic)
+	h.notifyMentioned(ctx, author, topic, content)
+}
+
+func (h *Handler) notifyTopicOwner(ctx context.Context, author *user.User, topic *topic.Topic) {
+	if author.ID == topic.UserID {
+		return
+	}
+
+	notification := &notification.Notification{
+		ActorID:     author.Username,
+		UserID:      topic.UserID,
+		RelatedID:   strconv.Itoa(topic.ID),
+		RelatedType: "topic",
+		Type:        notification.NotificationTypeReply,
+		Title:       "New comment",
+		Message:     fmt.Sprintf("%s commented on your Topic %s", author.Username, topic.Title),
+	}
+
+	err := h.Notification.CreateNotification(ctx, notification)
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+	}
+}
+
+// notifyWatchers tells everyone watching the topic about the new comment in
+// one batch. The author and the topic owner, who already got a reply
+// notification, are skipped.
+func (h *Handler) notifyWatchers(ctx context.Context, author *user.User, topic *topic.Topic) {
+	watchers, err := h.UserServices.UserServices.Queries.GetTopicWatchers.Handle(ctx, topicqueries.GetTopicWatchersRequest{
+		TopicID: topic.ID,
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		return
+	}
+
+	batch := make([]*notification.Notification, 0, len(watchers))
+	for _, watcherID := range watchers {
+		if watcherID == author.ID || watcherID == topic.UserID {
+			continue
+		}
+		batch = append(batch, &notification.Notification{
+			ActorID:     author.Username,
+			UserID:      watcherID,
+			RelatedID:   strconv.Itoa(topic.ID),
+			RelatedType: "topic",
+			Type:        notification.NotificationTypeWatchedComment,
+			Title:       "New comment on a watched topic",
+			Message:     fmt.Sprintf("%s commented on %s", author.Username, topic.Title),
+		})
+	}
+
+	err = h.Notification.CreateCoalescedNotifications(ctx, batch, h.Config.Comments.WatchNotifyInterval)
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+	}
+}
+
+// notifyMentioned tells each existing user @mentioned in the comment, once
+// however often they are named. Authors mentioning themselves are skipped.
+func (h *Handler) notifyMentioned(ctx context.Context, author *user.User, topic *topic.Topic, content string) {
+	mentioned := h.UserServices.UserServices.Queries.ResolveMentions.Handle(ctx, userqueries.ResolveMentionsRequest{
+		Content:  content,
+		AuthorID: author.ID,
+	})
+
+	batch := make([]*notification.Notification, 0, len(mentioned))
+	for _, u := range mentioned {
+		batch = append(batch, &notification.Notification{
+			ActorID:     author.Username,
+			UserID:      u.ID,
+			RelatedID:   strconv.Itoa(topic.ID),
+			RelatedType: "topic",
+			Type:        notification.NotificationTypeMention,
+			Title:       "You were mentioned",
+			Message:     fmt.Sprintf("%s mentioned you in %s", author.Username, topic.Title),
+		})
+	}
+
+	err := h.Notification.CreateNotifications(ctx, batch)
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+	}
+}
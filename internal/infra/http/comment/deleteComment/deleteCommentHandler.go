@@ -2,6 +2,7 @@ package deletecomment
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/arnald/forum/internal/app"
@@ -9,6 +10,7 @@ import (
 	"github.com/arnald/forum/internal/config"
 	"github.com/arnald/forum/internal/infra/logger"
 	"github.com/arnald/forum/internal/infra/middleware"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/comments"
 	"github.com/arnald/forum/internal/pkg/helpers"
 	"github.com/arnald/forum/internal/pkg/validator"
 )
@@ -75,10 +77,14 @@ func (h *Handler) DeleteComment(w http.ResponseWriter, r *http.Request) {
 		User:      user,
 	})
 	if err != nil {
-		helpers.RespondWithError(w,
-			http.StatusInternalServerError,
-			"Failed to delete comment",
-		)
+		switch {
+		case errors.Is(err, comments.ErrCommentNotFound):
+			helpers.RespondWithError(w, http.StatusNotFound, "Comment not found")
+		case errors.Is(err, comments.ErrNotCommentAuthor):
+			helpers.RespondWithError(w, http.StatusForbidden, "You can only delete your own comments")
+		default:
+			helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to delete comment")
+		}
 
 		h.Logger.PrintError(err, nil)
 		return
@@ -9,6 +9,7 @@ import (
 	commentQueries "github.com/arnald/forum/internal/app/comments/queries"
 	"github.com/arnald/forum/internal/config"
 	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/middleware"
 	"github.com/arnald/forum/internal/pkg/helpers"
 	"github.com/arnald/forum/internal/pkg/validator"
 )
@@ -19,6 +20,7 @@ type ResponseModel struct {
 	Content   string `json:"content"`
 	CreatedAt string `json:"createdAt"`
 	UpdatedAt string `json:"updatedAt"`
+	Status    string `json:"status"`
 	ID        int    `json:"id"`
 	TopicID   int    `json:"topicId"`
 }
@@ -72,8 +74,15 @@ func (h *Handler) GetComment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var userID *string
+	user := middleware.GetUserFromContext(r)
+	if user != nil {
+		userID = &user.ID
+	}
+
 	comment, err := h.UserServices.UserServices.Queries.GetComment.Handle(ctx, commentQueries.GetCommentRequest{
 		CommentID: commentIDVal.CommentID,
+		UserID:    userID,
 	})
 	if err != nil {
 		if errors.Is(err, commentQueries.ErrCommentNotFound) {
@@ -94,6 +103,7 @@ func (h *Handler) GetComment(w http.ResponseWriter, r *http.Request) {
 		Content:   comment.Content,
 		CreatedAt: comment.CreatedAt,
 		UpdatedAt: comment.UpdatedAt,
+		Status:    comment.Status,
 	}
 
 	helpers.RespondWithJSON(w, http.StatusOK, nil, response)
@@ -2,6 +2,7 @@ package getcommentsbytopic
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/arnald/forum/internal/app"
@@ -9,6 +10,8 @@ import (
 	"github.com/arnald/forum/internal/config"
 	"github.com/arnald/forum/internal/domain/comment"
 	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/middleware"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/topics"
 	"github.com/arnald/forum/internal/pkg/helpers"
 	"github.com/arnald/forum/internal/pkg/validator"
 )
@@ -48,11 +51,15 @@ func (h *Handler) GetCommentsByTopic(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	order := r.URL.Query().Get("order")
+
 	val := validator.New()
 
 	topicIDVal := &struct {
+		Order   string
 		TopicID int
 	}{
+		Order:   order,
 		TopicID: topicID,
 	}
 	validator.ValidateGetCommentsByTopic(val, topicIDVal)
@@ -66,9 +73,22 @@ func (h *Handler) GetCommentsByTopic(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var userID *string
+	user := middleware.GetUserFromContext(r)
+	if user != nil {
+		userID = &user.ID
+	}
+
 	comments, err := h.UserServices.UserServices.Queries.GetCommentsByTopic.Handle(ctx, commentQueries.GetCommentsByTopicRequest{
+		UserID:  userID,
 		TopicID: topicID,
+		Order:   order,
 	})
+	if errors.Is(err, topics.ErrTopicNotFound) {
+		h.Logger.PrintError(err, nil)
+		helpers.RespondWithError(w, http.StatusNotFound, "Topic not found")
+		return
+	}
 	if err != nil {
 		h.Logger.PrintError(err, nil)
 		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to get comments")
@@ -0,0 +1,437 @@
+package moderatecomment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/arnald/forum/internal/app"
+	commentCommands "github.com/arnald/forum/internal/app/comments/commands"
+	topicqueries "github.com/arnald/forum/internal/app/topics/queries"
+	userqueries "github.com/arnald/forum/internal/app/user/queries"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/domain/comment"
+	"github.com/arnald/forum/internal/domain/notification"
+	"github.com/arnald/forum/internal/domain/topic"
+	"github.com/arnald/forum/internal/domain/user"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/middleware"
+	"github.com/arnald/forum/internal/infra/storage/notifications"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/comments"
+	"github.com/arnald/forum/internal/pkg/helpers"
+	"github.com/arnald/forum/internal/pkg/validator"
+)
+
+type PendingCommentModel struct {
+	ParentID  *int   `json:"parentId,omitempty"`
+	UserID    string `json:"userId"`
+	Username  string `json:"username"`
+	Content   string `json:"content"`
+	CreatedAt string `json:"createdAt"`
+	ID        int    `json:"id"`
+	TopicID   int    `json:"topicId"`
+}
+
+type PendingCommentsResponse struct {
+	Comments []PendingCommentModel `json:"comments"`
+	// RejectionReasons are the stock reasons a rejection can give.
+	RejectionReasons []comment.RejectionReason `json:"rejectionReasons"`
+}
+
+type ResponseModel struct {
+	Message   string `json:"message"`
+	Status    string `json:"status"`
+	CommentID int    `json:"commentId"`
+}
+
+type BulkResponseModel struct {
+	Message    string `json:"message"`
+	Status     string `json:"status"`
+	CommentIDs []int  `json:"commentIds"`
+	Count      int    `json:"count"`
+}
+
+type Handler struct {
+	UserServices app.Services
+	Config       *config.ServerConfig
+	Logger       logger.Logger
+	Notification *notifications.NotificationService
+}
+
+func NewHandler(userServices app.Services, config *config.ServerConfig, logger logger.Logger, notifications *notifications.NotificationService) *Handler {
+	return &Handler{
+		UserServices: userServices,
+		Config:       config,
+		Logger:       logger,
+		Notification: notifications,
+	}
+}
+
+// PendingComments lists the comments waiting for a moderator, limited to the
+// moderator's categories unless they are an admin.
+func (h *Handler) PendingComments(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	moderator := middleware.GetUserFromContext(r)
+	if moderator == nil {
+		h.Logger.PrintError(logger.ErrUserNotFoundInContext, nil)
+		helpers.RespondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserRegister)
+	defer cancel()
+
+	pending, err := h.UserServices.UserServices.Queries.GetPendingComments.Handle(ctx, moderator)
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to get pending comments")
+		return
+	}
+
+	response := PendingCommentsResponse{
+		Comments:         make([]PendingCommentModel, 0, len(pending)),
+		RejectionReasons: comment.RejectionReasons,
+	}
+	for _, c := range pending {
+		response.Comments = append(response.Comments, PendingCommentModel{
+			ID:        c.ID,
+			TopicID:   c.TopicID,
+			ParentID:  c.ParentID,
+			UserID:    c.UserID,
+			Username:  c.OwnerUsername,
+			Content:   c.Content,
+			CreatedAt: c.CreatedAt,
+		})
+	}
+
+	helpers.RespondWithJSON(w, http.StatusOK, nil, response)
+}
+
+func (h *Handler) ApproveComment(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, comment.StatusApproved)
+}
+
+func (h *Handler) RejectComment(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, comment.StatusRejected)
+}
+
+func (h *Handler) moderate(w http.ResponseWriter, r *http.Request, decision string) {
+	if r.Method != http.MethodPost {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	moderator := middleware.GetUserFromContext(r)
+	if moderator == nil {
+		h.Logger.PrintError(logger.ErrUserNotFoundInContext, nil)
+		helpers.RespondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserRegister)
+	defer cancel()
+
+	commentID, err := helpers.GetQueryInt(r, "id")
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		helpers.RespondWithError(w, http.StatusBadRequest, "Invalid comment ID")
+		return
+	}
+
+	val := validator.New()
+
+	commentIDVal := &struct {
+		CommentID int
+	}{
+		CommentID: commentID,
+	}
+	validator.ValidateModerateComment(val, commentIDVal)
+
+	if !val.Valid() {
+		h.Logger.PrintError(logger.ErrValidationFailed, val.Errors)
+		helpers.RespondWithError(w, http.StatusBadRequest, val.ToStringErrors())
+		return
+	}
+
+	// Rejections explain themselves with the reason and reason_text form
+	// values; approvals need neither.
+	notice := h.authorNotice(moderator)
+	moderated, err := h.UserServices.UserServices.Commands.ModerateComment.Handle(ctx, commentCommands.ModerateCommentRequest{
+		Moderator:  moderator,
+		Notice:     notice,
+		Decision:   decision,
+		Reason:     r.FormValue("reason"),
+		ReasonNote: r.FormValue("reason_text"),
+		CommentID:  commentID,
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		switch {
+		case errors.Is(err, commentCommands.ErrNotModerator):
+			helpers.RespondWithError(w, http.StatusForbidden, "Moderator access required")
+		case errors.Is(err, commentCommands.ErrOutsideModeratorScope):
+			helpers.RespondWithError(w, http.StatusForbidden, "Comment is outside your categories")
+		case errors.Is(err, commentCommands.ErrInvalidRejectionReason):
+			helpers.RespondWithError(w, http.StatusBadRequest,
+				fmt.Sprintf("Pick a rejection reason, or choose %q and explain in at most %d characters",
+					comment.RejectionReasonOther, comment.MaxRejectionNoteLength))
+		case errors.Is(err, comments.ErrCommentNotFound):
+			helpers.RespondWithError(w, http.StatusNotFound, "Pending comment not found")
+		default:
+			helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to moderate comment")
+		}
+		return
+	}
+
+	// The notice was stored with the decision; it has no ID when the author's
+	// account is gone.
+	if notice.ID != 0 {
+		h.Notification.Deliver(notice)
+	}
+	if moderated.Status == comment.StatusApproved {
+		h.notifyNewComment(ctx, moderated)
+	}
+
+	helpers.RespondWithJSON(w, http.StatusOK, nil, ResponseModel{
+		CommentID: moderated.ID,
+		Status:    moderated.Status,
+		Message:   "Comment " + moderated.Status,
+	})
+
+	h.Logger.PrintInfo(
+		"Comment moderated",
+		map[string]string{
+			"moderator_id": moderator.ID,
+			"comment_id":   strconv.Itoa(moderated.ID),
+			"status":       moderated.Status,
+		},
+	)
+}
+
+func (h *Handler) BulkApproveComments(w http.ResponseWriter, r *http.Request) {
+	h.moderateSelected(w, r, comment.StatusApproved)
+}
+
+func (h *Handler) BulkRejectComments(w http.ResponseWriter, r *http.Request) {
+	h.moderateSelected(w, r, comment.StatusRejected)
+}
+
+// moderateSelected settles the pending comments ticked on the moderation
+// queue, sent as repeated comment_ids form values. Comments that were already
+// settled are skipped, and the response counts only those this call changed.
+func (h *Handler) moderateSelected(w http.ResponseWriter, r *http.Request, decision string) {
+	if r.Method != http.MethodPost {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	moderator := middleware.GetUserFromContext(r)
+	if moderator == nil {
+		h.Logger.PrintError(logger.ErrUserNotFoundInContext, nil)
+		helpers.RespondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserRegister)
+	defer cancel()
+
+	err := r.ParseForm()
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		helpers.RespondWithError(w, http.StatusBadRequest, "Invalid form")
+		return
+	}
+
+	commentIDs := make([]int, 0, len(r.Form["comment_ids"]))
+	for _, raw := range r.Form["comment_ids"] {
+		id, convErr := strconv.Atoi(raw)
+		if convErr != nil || id < 1 {
+			helpers.RespondWithError(w, http.StatusBadRequest, "Invalid comment ID")
+			return
+		}
+		commentIDs = append(commentIDs, id)
+	}
+
+	moderated, err := h.UserServices.UserServices.Commands.BulkModerate.Handle(ctx, commentCommands.BulkModerateCommentsRequest{
+		Moderator:  moderator,
+		Decision:   decision,
+		CommentIDs: commentIDs,
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		switch {
+		case errors.Is(err, commentCommands.ErrNotModerator):
+			helpers.RespondWithError(w, http.StatusForbidden, "Moderator access required")
+		case errors.Is(err, commentCommands.ErrOutsideModeratorScope):
+			helpers.RespondWithError(w, http.StatusForbidden, "Some comments are outside your categories")
+		case errors.Is(err, commentCommands.ErrNoCommentsSelected):
+			helpers.RespondWithError(w, http.StatusBadRequest, "Select at least one comment")
+		case errors.Is(err, commentCommands.ErrTooManyComments):
+			helpers.RespondWithError(w, http.StatusBadRequest,
+				fmt.Sprintf("Select at most %d comments at a time", commentCommands.MaxBulkComments))
+		default:
+			helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to moderate comments")
+		}
+		return
+	}
+
+	ids := make([]int, 0, len(moderated))
+	for _, c := range moderated {
+		ids = append(ids, c.ID)
+		h.notifyAuthor(ctx, moderator, c)
+		if c.Status == comment.StatusApproved {
+			h.notifyNewComment(ctx, c)
+		}
+	}
+
+	helpers.RespondWithJSON(w, http.StatusOK, nil, BulkResponseModel{
+		CommentIDs: ids,
+		Count:      len(ids),
+		Status:     decision,
+		Message:    fmt.Sprintf("%d comments %s", len(ids), decision),
+	})
+
+	h.Logger.PrintInfo(
+		"Comments moderated",
+		map[string]string{
+			"moderator_id": moderator.ID,
+			"count":        strconv.Itoa(len(ids)),
+			"status":       decision,
+		},
+	)
+}
+
+// moderationTeam stands in for the moderator when decisions are anonymous.
+const moderationTeam = "the moderation team"
+
+// authorNotice starts the moderation notice for a comment's author, naming
+// the moderator unless decisions are anonymous.
+func (h *Handler) authorNotice(moderator *user.User) *notification.Notification {
+	actorID, actorName := moderator.Username, moderator.Username
+	if h.Config.Comments.AnonymousModeration {
+		actorID, actorName = "", moderationTeam
+	}
+
+	return &notification.Notification{
+		ActorID:   actorID,
+		ActorName: actorName,
+	}
+}
+
+func (h *Handler) notifyAuthor(ctx context.Context, moderator *user.User, moderated *comment.Comment) {
+	notice := h.authorNotice(moderator)
+	commentCommands.ComposeModerationNotice(notice, moderated.Status, moderated.RejectionReason)
+	notice.UserID = moderated.UserID
+	notice.RelatedID = strconv.Itoa(moderated.TopicID)
+
+	err := h.Notification.CreateNotification(ctx, notice)
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+	}
+}
+
+// notifyNewComment sends the reply, watcher and mention notifications that
+// were held back while the comment waited for review.
+func (h *Handler) notifyNewComment(ctx context.Context, approved *comment.Comment) {
+	topic, err := h.UserServices.UserServices.Queries.GetTopic.Handle(ctx, topicqueries.GetTopicRequest{
+		TopicID: approved.TopicID,
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		return
+	}
+
+	h.notifyTopicOwner(ctx, approved, topic)
+	h.notifyWatchers(ctx, approved, topic)
+	h.notifyMentioned(ctx, approved, topic)
+}
+
+func (h *Handler) notifyTopicOwner(ctx context.Context, approved *comment.Comment, topic *topic.Topic) {
+	if approved.UserID == topic.UserID {
+		return
+	}
+
+	notification := &notification.Notification{
+		ActorID:     approved.OwnerUsername,
+		UserID:      topic.UserID,
+		RelatedID:   strconv.Itoa(approved.TopicID),
+		RelatedType: "topic",
+		Type:        notification.NotificationTypeReply,
+		Title:       "New comment",
+		Message:     fmt.Sprintf("%s commented on your Topic %s", approved.OwnerUsername, topic.Title),
+	}
+
+	err := h.Notification.CreateNotification(ctx, notification)
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+	}
+}
+
+// notifyWatchers tells everyone watching the topic about the approved comment
+// in one batch, skipping its author and the topic owner.
+func (h *Handler) notifyWatchers(ctx context.Context, approved *comment.Comment, topic *topic.Topic) {
+	watchers, err := h.UserServices.UserServices.Queries.GetTopicWatchers.Handle(ctx, topicqueries.GetTopicWatchersRequest{
+		TopicID: approved.TopicID,
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		return
+	}
+
+	batch := make([]*notification.Notification, 0, len(watchers))
+	for _, watcherID := range watchers {
+		if watcherID == approved.UserID || watcherID == topic.UserID {
+			continue
+		}
+		batch = append(batch, &notification.Notification{
+			ActorID:     approved.OwnerUsername,
+			UserID:      watcherID,
+			RelatedID:   strconv.Itoa(approved.TopicID),
+			RelatedType: "topic",
+			Type:        notification.NotificationTypeWatchedComment,
+			Title:       "New comment on a watched topic",
+			Message:     fmt.Sprintf("%s commented on %s", approved.OwnerUsername, topic.Title),
+		})
+	}
+
+	err = h.Notification.CreateCoalescedNotifications(ctx, batch, h.Config.Comments.WatchNotifyInterval)
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+	}
+}
+
+// notifyMentioned tells each existing user @mentioned in the approved comment,
+// skipping its author.
+func (h *Handler) notifyMentioned(ctx context.Context, approved *comment.Comment, topic *topic.Topic) {
+	mentioned := h.UserServices.UserServices.Queries.ResolveMentions.Handle(ctx, userqueries.ResolveMentionsRequest{
+		Content:  approved.Content,
+		AuthorID: approved.UserID,
+	})
+
+	batch := make([]*notification.Notification, 0, len(mentioned))
+	for _, u := range mentioned {
+		batch = append(batch, &notification.Notification{
+			ActorID:     approved.OwnerUsername,
+			UserID:      u.ID,
+			RelatedID:   strconv.Itoa(approved.TopicID),
+			RelatedType: "topic",
+			Type:        notification.NotificationTypeMention,
+			Title:       "You were mentioned",
+			Message:     fmt.Sprintf("%s mentioned you in %s", approved.OwnerUsername, topic.Title),
+		})
+	}
+
+	err := h.Notification.CreateNotifications(ctx, batch)
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+	}
+}
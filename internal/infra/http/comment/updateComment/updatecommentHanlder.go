@@ -2,6 +2,7 @@ package updatecomment
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/arnald/forum/internal/app"
@@ -9,6 +10,7 @@ import (
 	"github.com/arnald/forum/internal/config"
 	"github.com/arnald/forum/internal/infra/logger"
 	"github.com/arnald/forum/internal/infra/middleware"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/comments"
 	"github.com/arnald/forum/internal/pkg/helpers"
 	"github.com/arnald/forum/internal/pkg/validator"
 )
@@ -69,7 +71,7 @@ func (h *Handler) UpdateComment(w http.ResponseWriter, r *http.Request) {
 
 	v := validator.New()
 
-	validator.ValidateUpdateComment(v, commentAny)
+	validator.ValidateUpdateComment(v, commentAny, h.Config.Limits)
 
 	if !v.Valid() {
 		helpers.RespondWithError(
@@ -88,10 +90,14 @@ func (h *Handler) UpdateComment(w http.ResponseWriter, r *http.Request) {
 		User:      user,
 	})
 	if err != nil {
-		helpers.RespondWithError(w,
-			http.StatusInternalServerError,
-			"Failed to update comment",
-		)
+		switch {
+		case errors.Is(err, comments.ErrCommentNotFound):
+			helpers.RespondWithError(w, http.StatusNotFound, "Comment not found")
+		case errors.Is(err, comments.ErrNotCommentAuthor):
+			helpers.RespondWithError(w, http.StatusForbidden, "You can only edit your own comments")
+		default:
+			helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to update comment")
+		}
 
 		h.Logger.PrintError(err, nil)
 		return
@@ -0,0 +1,118 @@
+package importcontent
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/arnald/forum/internal/app"
+	importcommands "github.com/arnald/forum/internal/app/imports/commands"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/domain/dataimport"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/middleware"
+	"github.com/arnald/forum/internal/infra/middleware/ratelimiter"
+	"github.com/arnald/forum/internal/pkg/helpers"
+)
+
+// importTimeout replaces the usual handler timeout; a large export takes a
+// while to write.
+const importTimeout = 2 * time.Minute
+
+type Handler struct {
+	UserServices app.Services
+	Config       *config.ServerConfig
+	Logger       logger.Logger
+	limiter      ratelimiter.Limiter
+}
+
+func NewHandler(userServices app.Services, config *config.ServerConfig, logger logger.Logger) *Handler {
+	return &Handler{
+		UserServices: userServices,
+		Config:       config,
+		Logger:       logger,
+		limiter: ratelimiter.NewRateLimiter(
+			config.Import.RequestsLimit,
+			config.Import.WindowSeconds,
+			config.RateLimit.Cleanup,
+		),
+	}
+}
+
+// ImportContent imports a forum export of users, categories, topics and
+// comments. It answers 201 with the summary when everything was stored and
+// 422 with the same summary, nothing stored, when anything was rejected.
+func (h *Handler) ImportContent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	if !h.Config.Import.Enabled {
+		helpers.RespondWithError(w, http.StatusNotFound, "Content import is disabled")
+		return
+	}
+
+	user := middleware.GetUserFromContext(r)
+	if user == nil {
+		h.Logger.PrintError(logger.ErrUserNotFoundInContext, nil)
+		helpers.RespondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	allowed, remaining, resetTime := h.limiter.Allow(user.ID)
+	middleware.SetRateLimitHeaders(w, h.limiter, allowed, remaining, resetTime)
+	if !allowed {
+		helpers.RespondWithError(w, http.StatusTooManyRequests, "Import limit reached, try again later")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), importTimeout)
+	defer cancel()
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.Config.Import.MaxBytes)
+
+	var doc dataimport.Document
+	_, err := helpers.ParseBodyRequest(r, &doc)
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			helpers.RespondWithError(w, http.StatusRequestEntityTooLarge, "Import document is too large")
+			return
+		}
+		helpers.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	summary, err := h.UserServices.UserServices.Commands.ImportContent.Handle(ctx, importcommands.ImportContentRequest{
+		Document:   &doc,
+		ImportedBy: user.ID,
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to import content")
+		return
+	}
+
+	if !summary.Committed {
+		helpers.RespondWithJSON(w, http.StatusUnprocessableEntity, nil, summary)
+		return
+	}
+
+	helpers.RespondWithJSON(w, http.StatusCreated, nil, summary)
+
+	h.Logger.PrintInfo(
+		"Content imported successfully",
+		map[string]string{
+			"users":      strconv.Itoa(summary.Users.Imported),
+			"categories": strconv.Itoa(summary.Categories.Imported),
+			"topics":     strconv.Itoa(summary.Topics.Imported),
+			"comments":   strconv.Itoa(summary.Comments.Imported),
+			"user_id":    user.ID,
+		})
+}
@@ -0,0 +1,61 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/pkg/helpers"
+)
+
+const (
+	readyStatus    = "ok"
+	notReadyStatus = "unavailable"
+	pingTimeout    = 2 * time.Second
+)
+
+// Pinger is the part of *sql.DB the readiness check needs.
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+type ReadinessHandler struct {
+	Logger logger.Logger
+	DB     Pinger
+}
+
+func NewReadinessHandler(logger logger.Logger, db Pinger) *ReadinessHandler {
+	return &ReadinessHandler{
+		Logger: logger,
+		DB:     db,
+	}
+}
+
+// Healthz answers liveness and readiness probes: 200 {"status":"ok"} while
+// the database answers a ping, 503 otherwise. It does no other work, so
+// probes can call it as often as they like.
+func (h ReadinessHandler) Healthz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), pingTimeout)
+	defer cancel()
+
+	status, code := readyStatus, http.StatusOK
+	err := h.DB.PingContext(ctx)
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		status, code = notReadyStatus, http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]string{"status": status})
+}
@@ -0,0 +1,55 @@
+package health
+
+import (
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/arnald/forum/internal/infra/logger"
+)
+
+func TestReadinessHandler_Healthz(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	h := NewReadinessHandler(logger.New(io.Discard, logger.LevelOff), db)
+
+	t.Run("database reachable", func(t *testing.T) {
+		assertHealthz(t, h, http.StatusOK, "ok")
+	})
+
+	t.Run("database closed", func(t *testing.T) {
+		err = db.Close()
+		if err != nil {
+			t.Fatalf("failed to close database: %v", err)
+		}
+		assertHealthz(t, h, http.StatusServiceUnavailable, "unavailable")
+	})
+}
+
+func assertHealthz(t *testing.T, h *ReadinessHandler, wantCode int, wantStatus string) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	h.Healthz(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if rec.Code != wantCode {
+		t.Fatalf("Healthz() status = %d, want %d", rec.Code, wantCode)
+	}
+	var body map[string]string
+	err := json.Unmarshal(rec.Body.Bytes(), &body)
+	if err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body["status"] != wantStatus {
+		t.Errorf("Healthz() body = %v, want status %q", body, wantStatus)
+	}
+}
@@ -0,0 +1,94 @@
+package notificationpreferences
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/arnald/forum/internal/domain/notification"
+	"github.com/arnald/forum/internal/infra/middleware"
+	"github.com/arnald/forum/internal/infra/storage/notifications"
+)
+
+type Handler struct {
+	service *notifications.NotificationService
+}
+
+func NewHandler(service *notifications.NotificationService) *Handler {
+	return &Handler{service: service}
+}
+
+// Preferences lists the user's notification settings on GET and switches a
+// single type on or off on POST, answering with the updated list either way.
+func (h *Handler) Preferences(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r)
+	if user == nil || user.ID == "" {
+		http.Error(
+			w,
+			"Unauthorized",
+			http.StatusUnauthorized,
+		)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		var preference notification.Preference
+		err := json.NewDecoder(r.Body).Decode(&preference)
+		if err != nil {
+			http.Error(
+				w,
+				"invalid request payload",
+				http.StatusBadRequest,
+			)
+			return
+		}
+
+		err = h.service.SetPreference(r.Context(), user.ID, preference)
+		if errors.Is(err, notifications.ErrNotOptional) {
+			http.Error(
+				w,
+				"this notification type cannot be switched off",
+				http.StatusBadRequest,
+			)
+			return
+		}
+		if err != nil {
+			http.Error(
+				w,
+				"failed to save notification preference",
+				http.StatusInternalServerError,
+			)
+			return
+		}
+	default:
+		http.Error(
+			w,
+			"method not allowed",
+			http.StatusMethodNotAllowed,
+		)
+		return
+	}
+
+	preferences, err := h.service.GetPreferences(r.Context(), user.ID)
+	if err != nil {
+		http.Error(
+			w,
+			"failed to fetch notification preferences",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(preferences)
+	if err != nil {
+		http.Error(
+			w,
+			"failed to encode notification preferences",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+}
@@ -0,0 +1,124 @@
+package streamnotification
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/arnald/forum/internal/domain/notification"
+	"github.com/arnald/forum/internal/domain/session"
+	"github.com/arnald/forum/internal/domain/user"
+	"github.com/arnald/forum/internal/infra/middleware"
+	"github.com/arnald/forum/internal/infra/storage/notifications"
+	"github.com/arnald/forum/internal/pkg/path"
+	testhelpers "github.com/arnald/forum/internal/pkg/testing"
+)
+
+func newTestService(t *testing.T) *notifications.NotificationService {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	// Every connection to :memory: gets its own database, so keep just one.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	schema, err := os.ReadFile(path.NewResolver().GetPath("db/migrations/schema.sql"))
+	if err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+	_, err = db.Exec(string(schema))
+	if err != nil {
+		t.Fatalf("failed to apply schema: %v", err)
+	}
+	_, err = db.Exec(`INSERT INTO users (id, username, email) VALUES ('reader', 'reader', 'reader@example.com');`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+
+	return notifications.NewNotificationService(db, 0)
+}
+
+func TestStreamNotifications(t *testing.T) {
+	service := newTestService(t)
+	sessions := &testhelpers.MockSessionManager{
+		GetSessionFromSessionTokensFunc: func(_, _ string) (*session.Session, error) {
+			return &session.Session{
+				AccessToken:        "token",
+				Expiry:             time.Now().Add(time.Hour),
+				RefreshTokenExpiry: time.Now().Add(time.Hour),
+			}, nil
+		},
+		GetUserFromSessionFunc: func(_ string) (*user.User, error) {
+			return &user.User{ID: "reader"}, nil
+		},
+	}
+	handler := middleware.NewAuthorizationMiddleware(sessions, time.Minute).Required(NewHandler(service).StreamNotifications)
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: "token"})
+
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("stream request error = %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("stream status = %d, want %d: %s", resp.StatusCode, http.StatusOK, body)
+	}
+
+	events := bufio.NewScanner(resp.Body)
+	nextEvent := func() string {
+		t.Helper()
+		for events.Scan() {
+			if data, ok := strings.CutPrefix(events.Text(), "data: "); ok {
+				return data
+			}
+		}
+		t.Fatalf("stream ended early: %v", events.Err())
+		return ""
+	}
+
+	// The stream opens with a greeting and the unread count; the client is
+	// registered by then, so anything created afterwards must come through.
+	if got := nextEvent(); !strings.Contains(got, `"connected"`) {
+		t.Fatalf("first event = %s, want the connected greeting", got)
+	}
+	if got := nextEvent(); !strings.Contains(got, `"unread_count"`) {
+		t.Fatalf("second event = %s, want the unread count", got)
+	}
+
+	err = service.CreateNotification(ctx, &notification.Notification{
+		UserID:  "reader",
+		Type:    notification.NotificationTypeReply,
+		Title:   "New reply",
+		Message: "someone replied to your comment",
+	})
+	if err != nil {
+		t.Fatalf("CreateNotification() error = %v", err)
+	}
+
+	if got := nextEvent(); !strings.Contains(got, "someone replied to your comment") {
+		t.Errorf("pushed event = %s, want the new notification", got)
+	}
+}
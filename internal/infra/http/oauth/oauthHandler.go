@@ -2,6 +2,7 @@ package oauthlogin
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -42,7 +43,7 @@ func NewOAuthHandler(
 }
 
 func (h *OAuthHandler) Login(w http.ResponseWriter, r *http.Request) {
-	state, err := h.stateManager.Generate()
+	state, err := h.stateManager.Generate(h.provider.Name())
 	if err != nil {
 		h.logger.PrintError(err, nil)
 		helpers.RespondWithError(
@@ -95,7 +96,7 @@ func (h *OAuthHandler) Callback(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.stateManager.Verify(state)
+	err := h.stateManager.Verify(h.provider.Name(), state)
 	if err != nil {
 		h.logger.PrintError(err, nil)
 		http.Error(
@@ -111,6 +112,14 @@ func (h *OAuthHandler) Callback(w http.ResponseWriter, r *http.Request) {
 		code,
 		h.provider,
 	)
+	if errors.Is(err, oauthservice.ErrLinkNeedsConfirmation) {
+		http.Error(
+			w,
+			"An account with this email already exists. Sign in with your password and verify your email, then sign in with "+h.provider.Name()+" again to link it.",
+			http.StatusConflict,
+		)
+		return
+	}
 	if err != nil {
 		h.logger.PrintError(err, map[string]string{
 			"action":   "oauth_login",
@@ -132,6 +141,19 @@ func (h *OAuthHandler) Callback(w http.ResponseWriter, r *http.Request) {
 			"error at creating session",
 			http.StatusInternalServerError,
 		)
+		return
+	}
+
+	// Signing in through the provider counts as confirming the user's identity.
+	err = h.sessionManager.ConfirmAuthentication(r.Context(), session.AccessToken)
+	if err != nil {
+		h.logger.PrintError(err, nil)
+		http.Error(
+			w,
+			"error at creating session",
+			http.StatusInternalServerError,
+		)
+		return
 	}
 
 	params := url.Values{}
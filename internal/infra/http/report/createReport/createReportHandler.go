@@ -0,0 +1,154 @@
+package createreport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/arnald/forum/internal/app"
+	reportcommands "github.com/arnald/forum/internal/app/reports/commands"
+	reportqueries "github.com/arnald/forum/internal/app/reports/queries"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/domain/report"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/middleware"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/reports"
+	"github.com/arnald/forum/internal/pkg/helpers"
+	"github.com/arnald/forum/internal/pkg/validator"
+)
+
+// RequestModel names the reported content with exactly one of TopicID and
+// CommentID. Reason must be one of the active report reasons.
+type RequestModel struct {
+	Reason      string `json:"reason"`
+	Description string `json:"description"`
+	TopicID     int    `json:"topicId"`
+	CommentID   int    `json:"commentId"`
+}
+
+type ResponseModel struct {
+	Message   string `json:"message"`
+	Status    string `json:"status"`
+	TopicID   int    `json:"topicId,omitempty"`
+	CommentID int    `json:"commentId,omitempty"`
+	Collapsed bool   `json:"collapsed"`
+}
+
+type Handler struct {
+	UserServices app.Services
+	Config       *config.ServerConfig
+	Logger       logger.Logger
+}
+
+func NewHandler(userServices app.Services, config *config.ServerConfig, logger logger.Logger) *Handler {
+	return &Handler{
+		UserServices: userServices,
+		Config:       config,
+		Logger:       logger,
+	}
+}
+
+// CreateReport files the signed-in user's report against a topic or a
+// comment. A user may not report their own content, nor report the same
+// content again while their earlier report is still pending.
+func (h *Handler) CreateReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	user := middleware.GetUserFromContext(r)
+	if user == nil {
+		h.Logger.PrintError(logger.ErrUserNotFoundInContext, nil)
+		helpers.RespondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserRegister)
+	defer cancel()
+
+	var reportToCreate RequestModel
+
+	_, err := helpers.ParseBodyRequest(r, &reportToCreate)
+	if err != nil {
+		helpers.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	reportToCreate.Reason = strings.TrimSpace(reportToCreate.Reason)
+	reportToCreate.Description = strings.TrimSpace(reportToCreate.Description)
+
+	val := validator.New()
+	validator.ValidateCreateReport(val, &reportToCreate)
+	val.Check(
+		(reportToCreate.TopicID > 0) != (reportToCreate.CommentID > 0),
+		"TopicID",
+		"exactly one of topicId and commentId is required",
+	)
+	if !val.Valid() {
+		h.Logger.PrintError(logger.ErrValidationFailed, val.Errors)
+		helpers.RespondWithError(w, http.StatusBadRequest, val.ToStringErrors())
+		return
+	}
+
+	err = h.UserServices.UserServices.Queries.CheckReportReason.Handle(ctx, reportqueries.CheckReportReasonRequest{
+		Reason: reportToCreate.Reason,
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		if errors.Is(err, reportqueries.ErrInactiveReportReason) {
+			helpers.RespondWithError(w, http.StatusBadRequest, "Invalid report reason")
+			return
+		}
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to create report")
+		return
+	}
+
+	req := reportcommands.CreateReportRequest{
+		ReporterID:        user.ID,
+		Reason:            reportToCreate.Reason,
+		Description:       reportToCreate.Description,
+		TopicID:           reportToCreate.TopicID,
+		CommentID:         reportToCreate.CommentID,
+		CollapseThreshold: h.Config.Comments.CollapseReportThreshold,
+	}
+	collapsed, err := h.UserServices.UserServices.Commands.CreateReport.Handle(ctx, req)
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		switch {
+		case errors.Is(err, reports.ErrTopicNotFound):
+			helpers.RespondWithError(w, http.StatusNotFound, "Topic not found")
+		case errors.Is(err, reports.ErrCommentNotFound):
+			helpers.RespondWithError(w, http.StatusNotFound, "Comment not found")
+		case errors.Is(err, reports.ErrSelfReport):
+			helpers.RespondWithError(w, http.StatusForbidden, "You cannot report your own content")
+		case errors.Is(err, reports.ErrDuplicateReport):
+			helpers.RespondWithError(w, http.StatusConflict, "You have already reported this")
+		case errors.Is(err, reportcommands.ErrReportTargetRequired):
+			helpers.RespondWithError(w, http.StatusBadRequest, "Exactly one of topicId and commentId is required")
+		default:
+			helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to create report")
+		}
+		return
+	}
+
+	helpers.RespondWithJSON(w, http.StatusCreated, nil, ResponseModel{
+		TopicID:   req.TopicID,
+		CommentID: req.CommentID,
+		Status:    report.StatusPending,
+		Collapsed: collapsed,
+		Message:   "Report filed successfully",
+	})
+
+	h.Logger.PrintInfo(
+		"Report filed",
+		map[string]string{
+			"reporter_id": user.ID,
+			"topic_id":    strconv.Itoa(req.TopicID),
+			"comment_id":  strconv.Itoa(req.CommentID),
+		})
+}
@@ -0,0 +1,204 @@
+package reportreasons
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/arnald/forum/internal/app"
+	reportcommands "github.com/arnald/forum/internal/app/reports/commands"
+	reportqueries "github.com/arnald/forum/internal/app/reports/queries"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/domain/report"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/middleware"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/reports"
+	"github.com/arnald/forum/internal/pkg/helpers"
+	"github.com/arnald/forum/internal/pkg/validator"
+)
+
+type RequestModel struct {
+	Label string `json:"label"`
+}
+
+type ListResponseModel struct {
+	Reasons []report.Reason `json:"reasons"`
+}
+
+type ResponseModel struct {
+	Message  string `json:"message"`
+	ReasonID int    `json:"reasonId"`
+	Active   bool   `json:"active"`
+}
+
+type Handler struct {
+	UserServices app.Services
+	Config       *config.ServerConfig
+	Logger       logger.Logger
+}
+
+func NewHandler(userServices app.Services, config *config.ServerConfig, logger logger.Logger) *Handler {
+	return &Handler{
+		UserServices: userServices,
+		Config:       config,
+		Logger:       logger,
+	}
+}
+
+// GetReportReasons lists the reasons offered on the report form. Admins can
+// pass all=true to include retired reasons.
+func (h *Handler) GetReportReasons(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserRegister)
+	defer cancel()
+
+	user := middleware.GetUserFromContext(r)
+	includeRetired := r.URL.Query().Get("all") == "true" && user != nil && user.IsAdmin()
+
+	reasons, err := h.UserServices.UserServices.Queries.GetReportReasons.Handle(ctx, reportqueries.GetReportReasonsRequest{
+		IncludeRetired: includeRetired,
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to get report reasons")
+		return
+	}
+
+	helpers.RespondWithJSON(w, http.StatusOK, nil, ListResponseModel{
+		Reasons: reasons,
+	})
+}
+
+func (h *Handler) CreateReportReason(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	user := middleware.GetUserFromContext(r)
+	if user == nil {
+		h.Logger.PrintError(logger.ErrUserNotFoundInContext, nil)
+		helpers.RespondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserRegister)
+	defer cancel()
+
+	var reasonToCreate RequestModel
+
+	_, err := helpers.ParseBodyRequest(r, &reasonToCreate)
+	if err != nil {
+		helpers.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	reasonToCreate.Label = strings.TrimSpace(reasonToCreate.Label)
+
+	val := validator.New()
+	validator.ValidateReportReason(val, &reasonToCreate)
+	if !val.Valid() {
+		h.Logger.PrintError(logger.ErrValidationFailed, val.Errors)
+		helpers.RespondWithError(w, http.StatusBadRequest, val.ToStringErrors())
+		return
+	}
+
+	reason, err := h.UserServices.UserServices.Commands.CreateReason.Handle(ctx, reportcommands.CreateReportReasonRequest{
+		Label: reasonToCreate.Label,
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		if errors.Is(err, reports.ErrReasonAlreadyExists) {
+			helpers.RespondWithError(w, http.StatusConflict, "Report reason already exists")
+			return
+		}
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to create report reason")
+		return
+	}
+
+	helpers.RespondWithJSON(w, http.StatusCreated, nil, ResponseModel{
+		ReasonID: reason.ID,
+		Active:   reason.Active,
+		Message:  "Report reason created successfully",
+	})
+
+	h.Logger.PrintInfo(
+		"Report reason created successfully",
+		map[string]string{
+			"reason_id": strconv.Itoa(reason.ID),
+			"user_id":   user.ID,
+		})
+}
+
+func (h *Handler) RetireReportReason(w http.ResponseWriter, r *http.Request) {
+	h.setActive(w, r, false)
+}
+
+func (h *Handler) RestoreReportReason(w http.ResponseWriter, r *http.Request) {
+	h.setActive(w, r, true)
+}
+
+func (h *Handler) setActive(w http.ResponseWriter, r *http.Request, active bool) {
+	if r.Method != http.MethodPost {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	user := middleware.GetUserFromContext(r)
+	if user == nil {
+		h.Logger.PrintError(logger.ErrUserNotFoundInContext, nil)
+		helpers.RespondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserRegister)
+	defer cancel()
+
+	reasonID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || reasonID < 1 {
+		helpers.RespondWithError(w, http.StatusBadRequest, "Invalid report reason ID")
+		return
+	}
+
+	err = h.UserServices.UserServices.Commands.RetireReason.Handle(ctx, reportcommands.RetireReportReasonRequest{
+		ReasonID: reasonID,
+		Active:   active,
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		if errors.Is(err, reports.ErrReasonNotFound) {
+			helpers.RespondWithError(w, http.StatusNotFound, "Report reason not found")
+			return
+		}
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Error updating report reason")
+		return
+	}
+
+	message := "Report reason retired successfully"
+	if active {
+		message = "Report reason restored successfully"
+	}
+
+	helpers.RespondWithJSON(w, http.StatusOK, nil, ResponseModel{
+		ReasonID: reasonID,
+		Active:   active,
+		Message:  message,
+	})
+
+	h.Logger.PrintInfo(
+		message,
+		map[string]string{
+			"reason_id": strconv.Itoa(reasonID),
+			"user_id":   user.ID,
+		})
+}
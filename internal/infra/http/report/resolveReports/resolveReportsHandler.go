@@ -0,0 +1,125 @@
+package resolvereports
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/arnald/forum/internal/app"
+	reportCommands "github.com/arnald/forum/internal/app/reports/commands"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/domain/report"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/middleware"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/reports"
+	"github.com/arnald/forum/internal/pkg/helpers"
+	"github.com/arnald/forum/internal/pkg/validator"
+)
+
+type ResponseModel struct {
+	Message   string `json:"message"`
+	Status    string `json:"status"`
+	CommentID int    `json:"commentId"`
+}
+
+type Handler struct {
+	UserServices app.Services
+	Config       *config.ServerConfig
+	Logger       logger.Logger
+}
+
+func NewHandler(userServices app.Services, config *config.ServerConfig, logger logger.Logger) *Handler {
+	return &Handler{
+		UserServices: userServices,
+		Config:       config,
+		Logger:       logger,
+	}
+}
+
+// DismissReports rejects every pending report on a comment and brings it
+// back out from behind the warning.
+func (h *Handler) DismissReports(w http.ResponseWriter, r *http.Request) {
+	h.resolve(w, r, report.StatusDismissed)
+}
+
+// ResolveReports upholds every pending report on a comment; it stays
+// collapsed.
+func (h *Handler) ResolveReports(w http.ResponseWriter, r *http.Request) {
+	h.resolve(w, r, report.StatusResolved)
+}
+
+func (h *Handler) resolve(w http.ResponseWriter, r *http.Request, status string) {
+	if r.Method != http.MethodPost {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	moderator := middleware.GetUserFromContext(r)
+	if moderator == nil {
+		h.Logger.PrintError(logger.ErrUserNotFoundInContext, nil)
+		helpers.RespondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserRegister)
+	defer cancel()
+
+	commentID, err := helpers.GetQueryInt(r, "id")
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		helpers.RespondWithError(w, http.StatusBadRequest, "Invalid comment ID")
+		return
+	}
+
+	val := validator.New()
+
+	commentIDVal := &struct {
+		CommentID int
+	}{
+		CommentID: commentID,
+	}
+	validator.ValidateModerateComment(val, commentIDVal)
+
+	if !val.Valid() {
+		h.Logger.PrintError(logger.ErrValidationFailed, val.Errors)
+		helpers.RespondWithError(w, http.StatusBadRequest, val.ToStringErrors())
+		return
+	}
+
+	err = h.UserServices.UserServices.Commands.ResolveReports.Handle(ctx, reportCommands.ResolveCommentReportsRequest{
+		Moderator: moderator,
+		Status:    status,
+		CommentID: commentID,
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		switch {
+		case errors.Is(err, reportCommands.ErrNotModerator):
+			helpers.RespondWithError(w, http.StatusForbidden, "Moderator access required")
+		case errors.Is(err, reportCommands.ErrOutsideModeratorScope):
+			helpers.RespondWithError(w, http.StatusForbidden, "Comment is outside your categories")
+		case errors.Is(err, reports.ErrNoPendingReports):
+			helpers.RespondWithError(w, http.StatusNotFound, "No pending reports for this comment")
+		default:
+			helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to resolve reports")
+		}
+		return
+	}
+
+	helpers.RespondWithJSON(w, http.StatusOK, nil, ResponseModel{
+		CommentID: commentID,
+		Status:    status,
+		Message:   "Reports " + status,
+	})
+
+	h.Logger.PrintInfo(
+		"Comment reports resolved",
+		map[string]string{
+			"moderator_id": moderator.ID,
+			"comment_id":   strconv.Itoa(commentID),
+			"status":       status,
+		},
+	)
+}
@@ -1,16 +1,26 @@
 package http
 
 import (
+	"context"
 	"database/sql"
-	"errors"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
 	"time"
 
 	"github.com/arnald/forum/internal/app"
 	"github.com/arnald/forum/internal/config"
 	"github.com/arnald/forum/internal/domain/session"
 	getuseractivity "github.com/arnald/forum/internal/infra/http/activity/getUserActivity"
+	getuserprofile "github.com/arnald/forum/internal/infra/http/activity/getUserProfile"
+	getusersubmissions "github.com/arnald/forum/internal/infra/http/activity/getUserSubmissions"
+	getauditlog "github.com/arnald/forum/internal/infra/http/audit/getAuditLog"
+	archivecategory "github.com/arnald/forum/internal/infra/http/category/archiveCategory"
+	categorymoderators "github.com/arnald/forum/internal/infra/http/category/categoryModerators"
+	categorytree "github.com/arnald/forum/internal/infra/http/category/categoryTree"
 	createcategory "github.com/arnald/forum/internal/infra/http/category/createCategory"
 	deletecategory "github.com/arnald/forum/internal/infra/http/category/deleteCategory"
 	getallcategories "github.com/arnald/forum/internal/infra/http/category/getAllCategories"
@@ -20,23 +30,48 @@ import (
 	deletecomment "github.com/arnald/forum/internal/infra/http/comment/deleteComment"
 	getcomment "github.com/arnald/forum/internal/infra/http/comment/getComment"
 	getcommentsbytopic "github.com/arnald/forum/internal/infra/http/comment/getCommentsByTopic"
+	moderatecomment "github.com/arnald/forum/internal/infra/http/comment/moderateComment"
 	updatecomment "github.com/arnald/forum/internal/infra/http/comment/updateComment"
+	importcontent "github.com/arnald/forum/internal/infra/http/dataimport/importContent"
 	"github.com/arnald/forum/internal/infra/http/health"
 	getnotifications "github.com/arnald/forum/internal/infra/http/notification/getNotifications"
 	getunreadcount "github.com/arnald/forum/internal/infra/http/notification/getUnreadCount"
 	markallasread "github.com/arnald/forum/internal/infra/http/notification/markAllAsRead"
 	markasread "github.com/arnald/forum/internal/infra/http/notification/markAsRead"
+	notificationpreferences "github.com/arnald/forum/internal/infra/http/notification/notificationPreferences"
 	streamnotification "github.com/arnald/forum/internal/infra/http/notification/streamNotification"
 	oauthlogin "github.com/arnald/forum/internal/infra/http/oauth"
+	createreport "github.com/arnald/forum/internal/infra/http/report/createReport"
+	reportreasons "github.com/arnald/forum/internal/infra/http/report/reportReasons"
+	resolvereports "github.com/arnald/forum/internal/infra/http/report/resolveReports"
+	acceptanswer "github.com/arnald/forum/internal/infra/http/topic/acceptAnswer"
+	bookmarktopic "github.com/arnald/forum/internal/infra/http/topic/bookmarkTopic"
 	createtopic "github.com/arnald/forum/internal/infra/http/topic/createTopic"
 	deletetopic "github.com/arnald/forum/internal/infra/http/topic/deleteTopic"
 	getalltopics "github.com/arnald/forum/internal/infra/http/topic/getAllTopics"
 	gettopic "github.com/arnald/forum/internal/infra/http/topic/getTopic"
+	publishtopic "github.com/arnald/forum/internal/infra/http/topic/publishTopic"
+	restoretopic "github.com/arnald/forum/internal/infra/http/topic/restoreTopic"
+	topicfeed "github.com/arnald/forum/internal/infra/http/topic/topicFeed"
+	topichistory "github.com/arnald/forum/internal/infra/http/topic/topicHistory"
+	topicpermalink "github.com/arnald/forum/internal/infra/http/topic/topicPermalink"
 	updatetopic "github.com/arnald/forum/internal/infra/http/topic/updateTopic"
+	watchtopic "github.com/arnald/forum/internal/infra/http/topic/watchTopic"
+	changeavatar "github.com/arnald/forum/internal/infra/http/user/changeAvatar"
+	changepassword "github.com/arnald/forum/internal/infra/http/user/changePassword"
+	changeusername "github.com/arnald/forum/internal/infra/http/user/changeUsername"
+	deciderolerequest "github.com/arnald/forum/internal/infra/http/user/decideRoleRequest"
+	forgotpassword "github.com/arnald/forum/internal/infra/http/user/forgotPassword"
+	getallusers "github.com/arnald/forum/internal/infra/http/user/getAllUsers"
 	getme "github.com/arnald/forum/internal/infra/http/user/getMe"
 	userLogin "github.com/arnald/forum/internal/infra/http/user/login"
 	"github.com/arnald/forum/internal/infra/http/user/logout"
+	userReauth "github.com/arnald/forum/internal/infra/http/user/reauth"
+	"github.com/arnald/forum/internal/infra/http/user/refresh"
 	userRegister "github.com/arnald/forum/internal/infra/http/user/register"
+	requestrole "github.com/arnald/forum/internal/infra/http/user/requestRole"
+	resetpassword "github.com/arnald/forum/internal/infra/http/user/resetPassword"
+	verifyemail "github.com/arnald/forum/internal/infra/http/user/verifyEmail"
 	castvote "github.com/arnald/forum/internal/infra/http/vote/castVote"
 	deletevote "github.com/arnald/forum/internal/infra/http/vote/deleteVote"
 	getCounts "github.com/arnald/forum/internal/infra/http/vote/getVoteCounts"
@@ -98,12 +133,25 @@ func middlewareChain(handler http.HandlerFunc, middlewares ...func(http.HandlerF
 	return handler
 }
 
+// accountAuth guards a route that changes account settings. Routes listed in
+// SESSION_REAUTH_ROUTES also need a recent login, see RequireRecentAuth.
+func (server *Server) accountAuth(route string) func(http.HandlerFunc) http.HandlerFunc {
+	if slices.Contains(server.config.SessionManager.ReauthRoutes, route) {
+		return server.middleware.Authorization.RequireRecentAuth
+	}
+	return server.middleware.Authorization.Required
+}
+
 func (server *Server) AddHTTPRoutes() {
 	server.router.HandleFunc(apiContext+"/health",
 		middlewareChain(
 			health.NewHandler(server.logger, server.notifications).HealthCheck,
 			server.middleware.Authorization.Optional,
 		))
+	// Probes hit /healthz outside the API prefix, without session handling.
+	server.router.HandleFunc("/healthz",
+		health.NewReadinessHandler(server.logger, server.db).Healthz,
+	)
 
 	// User routes
 	server.router.HandleFunc(apiContext+"/login/email",
@@ -115,17 +163,62 @@ func (server *Server) AddHTTPRoutes() {
 	server.router.HandleFunc(apiContext+"/register",
 		userRegister.NewHandler(server.config, server.appServices, server.sessionManager, server.logger).UserRegister,
 	)
+	server.router.HandleFunc(apiContext+"/password/forgot",
+		forgotpassword.NewHandler(server.config, server.appServices, server.logger).ForgotPassword,
+	)
+	server.router.HandleFunc(apiContext+"/password/reset",
+		resetpassword.NewHandler(server.config, server.appServices, server.sessionManager, server.logger).ResetPassword,
+	)
+	// Asks for the current password itself, so a recent login adds nothing
+	server.router.HandleFunc(apiContext+"/password/change",
+		middlewareChain(
+			changepassword.NewHandler(server.config, server.appServices, server.sessionManager, server.logger).ChangePassword,
+			server.middleware.Authorization.Required,
+		))
+	server.router.HandleFunc(apiContext+"/username/change",
+		middlewareChain(
+			changeusername.NewHandler(server.config, server.appServices, server.logger).ChangeUsername,
+			server.accountAuth("/username/change"),
+		))
+	server.router.HandleFunc(apiContext+"/settings/avatar",
+		middlewareChain(
+			changeavatar.NewHandler(server.config, server.appServices, server.logger).ChangeAvatar,
+			server.accountAuth("/settings/avatar"),
+		))
+	server.router.HandleFunc(apiContext+"/verify-email",
+		verifyemail.NewHandler(server.config, server.appServices, server.logger).VerifyEmail,
+	)
 	server.router.HandleFunc(apiContext+"/logout",
 		middlewareChain(
 			logout.NewHandler(server.sessionManager, server.logger).Logout,
 			server.middleware.Authorization.Required,
 		))
+	// Needs no valid access token: the refresh token cookie is the credential
+	server.router.HandleFunc(apiContext+"/refresh",
+		refresh.NewHandler(server.sessionManager, server.logger).Refresh,
+	)
+	server.router.HandleFunc(apiContext+"/logout-everywhere",
+		middlewareChain(
+			logout.NewHandler(server.sessionManager, server.logger).LogoutEverywhere,
+			server.middleware.Authorization.Required,
+		))
 	// New handler for retrieving current user data from backend
 	server.router.HandleFunc(apiContext+"/me",
 		middlewareChain(
 			getme.NewHandler(server.logger).GetMe,
 			server.middleware.Authorization.Required,
 		))
+	// Confirms the password so routes behind Authorization.RequireRecentAuth open up
+	server.router.HandleFunc(apiContext+"/reauth",
+		middlewareChain(
+			userReauth.NewHandler(server.config, server.appServices, server.sessionManager, server.logger).Reauth,
+			server.middleware.Authorization.Required,
+		))
+	server.router.HandleFunc(apiContext+"/request-role",
+		middlewareChain(
+			requestrole.NewHandler(server.appServices, server.config, server.logger).RequestRole,
+			server.middleware.Authorization.Required,
+		))
 	// OAuth routes
 	server.router.HandleFunc(apiContext+"/auth/github/login",
 		oauthlogin.NewOAuthHandler(
@@ -172,6 +265,7 @@ func (server *Server) AddHTTPRoutes() {
 	server.router.HandleFunc(apiContext+"/topics/create",
 		middlewareChain(
 			createtopic.NewHandler(server.appServices, server.config, server.logger).CreateTopic,
+			server.middleware.UserRateLimit.Limit,
 			server.middleware.Authorization.Required,
 		),
 	)
@@ -187,23 +281,75 @@ func (server *Server) AddHTTPRoutes() {
 			server.middleware.Authorization.Required,
 		),
 	)
+	server.router.HandleFunc(apiContext+"/admin/restore-topic/{id}",
+		middlewareChain(
+			restoretopic.NewHandler(server.appServices, server.config, server.logger).RestoreTopic,
+			server.middleware.Authorization.RequireAdmin,
+		),
+	)
+	server.router.HandleFunc(apiContext+"/publish-topic/{id}",
+		middlewareChain(
+			publishtopic.NewHandler(server.appServices, server.config, server.logger).PublishTopic,
+			server.middleware.Authorization.Required,
+		),
+	)
 	server.router.HandleFunc(apiContext+"/topic",
 		middlewareChain(
 			gettopic.NewHandler(server.appServices, server.config, server.logger).GetTopic,
 			server.middleware.Authorization.Optional,
 		),
 	)
+	server.router.HandleFunc(apiContext+"/posts/{file}",
+		topicpermalink.NewHandler(server.appServices, server.config, server.logger).Permalink,
+	)
+	server.router.HandleFunc(apiContext+"/feed",
+		topicfeed.NewHandler(server.appServices, server.config, server.logger).Feed,
+	)
+	server.router.HandleFunc(apiContext+"/category/{id}/feed",
+		topicfeed.NewHandler(server.appServices, server.config, server.logger).CategoryFeed,
+	)
 	server.router.HandleFunc(apiContext+"/topics/all",
 		middlewareChain(
 			getalltopics.NewHandler(server.appServices, server.config, server.logger).GetAllTopics,
 			server.middleware.Authorization.Optional,
 		),
 	)
+	server.router.HandleFunc(apiContext+"/watch-topic/{id}",
+		middlewareChain(
+			watchtopic.NewHandler(server.appServices, server.config, server.logger).WatchTopic,
+			server.middleware.Authorization.Required,
+		),
+	)
+	server.router.HandleFunc(apiContext+"/unwatch-topic/{id}",
+		middlewareChain(
+			watchtopic.NewHandler(server.appServices, server.config, server.logger).UnwatchTopic,
+			server.middleware.Authorization.Required,
+		),
+	)
+	server.router.HandleFunc(apiContext+"/topic-history/{id}",
+		middlewareChain(
+			topichistory.NewHandler(server.appServices, server.config, server.logger).GetTopicHistory,
+			server.middleware.Authorization.Required,
+		),
+	)
+	server.router.HandleFunc(apiContext+"/bookmark-topic/{id}",
+		middlewareChain(
+			bookmarktopic.NewHandler(server.appServices, server.config, server.logger).BookmarkTopic,
+			server.middleware.Authorization.Required,
+		),
+	)
+	server.router.HandleFunc(apiContext+"/accept-answer/{commentID}",
+		middlewareChain(
+			acceptanswer.NewHandler(server.appServices, server.config, server.logger, server.notifications).AcceptAnswer,
+			server.middleware.Authorization.Required,
+		),
+	)
 
 	// Comment routes
 	server.router.HandleFunc(apiContext+"/comments/create",
 		middlewareChain(
 			createcomment.NewHandler(server.appServices, server.config, server.logger, server.notifications).CreateComment,
+			server.middleware.UserRateLimit.Limit,
 			server.middleware.Authorization.Required,
 		),
 	)
@@ -220,10 +366,58 @@ func (server *Server) AddHTTPRoutes() {
 		),
 	)
 	server.router.HandleFunc(apiContext+"/comments/get",
-		getcomment.NewHandler(server.appServices, server.config, server.logger).GetComment,
+		middlewareChain(
+			getcomment.NewHandler(server.appServices, server.config, server.logger).GetComment,
+			server.middleware.Authorization.Optional,
+		),
+	)
+	server.router.HandleFunc(apiContext+"/comments/pending",
+		middlewareChain(
+			moderatecomment.NewHandler(server.appServices, server.config, server.logger, server.notifications).PendingComments,
+			server.middleware.Authorization.RequireModerator,
+		),
+	)
+	server.router.HandleFunc(apiContext+"/comments/approve",
+		middlewareChain(
+			moderatecomment.NewHandler(server.appServices, server.config, server.logger, server.notifications).ApproveComment,
+			server.middleware.Authorization.RequireModerator,
+		),
+	)
+	server.router.HandleFunc(apiContext+"/comments/reject",
+		middlewareChain(
+			moderatecomment.NewHandler(server.appServices, server.config, server.logger, server.notifications).RejectComment,
+			server.middleware.Authorization.RequireModerator,
+		),
+	)
+	server.router.HandleFunc(apiContext+"/comments/bulk-approve",
+		middlewareChain(
+			moderatecomment.NewHandler(server.appServices, server.config, server.logger, server.notifications).BulkApproveComments,
+			server.middleware.Authorization.RequireModerator,
+		),
+	)
+	server.router.HandleFunc(apiContext+"/comments/bulk-reject",
+		middlewareChain(
+			moderatecomment.NewHandler(server.appServices, server.config, server.logger, server.notifications).BulkRejectComments,
+			server.middleware.Authorization.RequireModerator,
+		),
+	)
+	server.router.HandleFunc(apiContext+"/comments/reports/dismiss",
+		middlewareChain(
+			resolvereports.NewHandler(server.appServices, server.config, server.logger).DismissReports,
+			server.middleware.Authorization.RequireModerator,
+		),
+	)
+	server.router.HandleFunc(apiContext+"/comments/reports/resolve",
+		middlewareChain(
+			resolvereports.NewHandler(server.appServices, server.config, server.logger).ResolveReports,
+			server.middleware.Authorization.RequireModerator,
+		),
 	)
 	server.router.HandleFunc(apiContext+"/comments/topic",
-		getcommentsbytopic.NewHandler(server.appServices, server.config, server.logger).GetCommentsByTopic,
+		middlewareChain(
+			getcommentsbytopic.NewHandler(server.appServices, server.config, server.logger).GetCommentsByTopic,
+			server.middleware.Authorization.Optional,
+		),
 	)
 
 	// Category routes
@@ -251,6 +445,96 @@ func (server *Server) AddHTTPRoutes() {
 			server.middleware.Authorization.Optional,
 		),
 	)
+	server.router.HandleFunc(apiContext+"/admin/archive-category/{id}",
+		middlewareChain(
+			archivecategory.NewHandler(server.appServices, server.config, server.logger).ArchiveCategory,
+			server.middleware.Authorization.RequireAdmin,
+		),
+	)
+	server.router.HandleFunc(apiContext+"/admin/unarchive-category/{id}",
+		middlewareChain(
+			archivecategory.NewHandler(server.appServices, server.config, server.logger).UnarchiveCategory,
+			server.middleware.Authorization.RequireAdmin,
+		),
+	)
+	server.router.HandleFunc(apiContext+"/admin/assign-category-moderator/{id}",
+		middlewareChain(
+			categorymoderators.NewHandler(server.appServices, server.config, server.logger).AssignModerator,
+			server.middleware.Authorization.RequireAdmin,
+		),
+	)
+	server.router.HandleFunc(apiContext+"/admin/unassign-category-moderator/{id}",
+		middlewareChain(
+			categorymoderators.NewHandler(server.appServices, server.config, server.logger).UnassignModerator,
+			server.middleware.Authorization.RequireAdmin,
+		),
+	)
+	// Admin content import
+	server.router.HandleFunc(apiContext+"/admin/import",
+		middlewareChain(
+			importcontent.NewHandler(server.appServices, server.config, server.logger).ImportContent,
+			server.middleware.Authorization.RequireAdmin,
+		),
+	)
+
+	// Report routes
+	server.router.HandleFunc(apiContext+"/report",
+		middlewareChain(
+			createreport.NewHandler(server.appServices, server.config, server.logger).CreateReport,
+			server.middleware.Authorization.Required,
+		),
+	)
+	server.router.HandleFunc(apiContext+"/report-reasons",
+		middlewareChain(
+			reportreasons.NewHandler(server.appServices, server.config, server.logger).GetReportReasons,
+			server.middleware.Authorization.Optional,
+		),
+	)
+	server.router.HandleFunc(apiContext+"/admin/report-reasons/create",
+		middlewareChain(
+			reportreasons.NewHandler(server.appServices, server.config, server.logger).CreateReportReason,
+			server.middleware.Authorization.RequireAdmin,
+		),
+	)
+	server.router.HandleFunc(apiContext+"/admin/retire-report-reason/{id}",
+		middlewareChain(
+			reportreasons.NewHandler(server.appServices, server.config, server.logger).RetireReportReason,
+			server.middleware.Authorization.RequireAdmin,
+		),
+	)
+	server.router.HandleFunc(apiContext+"/admin/restore-report-reason/{id}",
+		middlewareChain(
+			reportreasons.NewHandler(server.appServices, server.config, server.logger).RestoreReportReason,
+			server.middleware.Authorization.RequireAdmin,
+		),
+	)
+	server.router.HandleFunc(apiContext+"/admin/users",
+		middlewareChain(
+			getallusers.NewHandler(server.appServices, server.config, server.logger).GetAllUsers,
+			server.middleware.Authorization.RequireAdmin,
+		),
+	)
+	server.router.HandleFunc(apiContext+"/admin/approve-role-request/{id}",
+		middlewareChain(
+			deciderolerequest.NewHandler(server.appServices, server.config, server.logger, server.notifications).ApproveRoleRequest,
+			server.middleware.Authorization.RequireAdmin,
+		),
+	)
+	server.router.HandleFunc(apiContext+"/admin/reject-role-request/{id}",
+		middlewareChain(
+			deciderolerequest.NewHandler(server.appServices, server.config, server.logger, server.notifications).RejectRoleRequest,
+			server.middleware.Authorization.RequireAdmin,
+		),
+	)
+	server.router.HandleFunc(apiContext+"/admin/audit",
+		middlewareChain(
+			getauditlog.NewHandler(server.appServices, server.config, server.logger).GetAuditLog,
+			server.middleware.Authorization.RequireAdmin,
+		),
+	)
+	server.router.HandleFunc(apiContext+"/categories",
+		categorytree.NewHandler(server.appServices, server.config, server.logger).GetCategoryTree,
+	)
 	server.router.HandleFunc(apiContext+"/categories/all",
 		getallcategories.NewHandler(server.appServices, server.config, server.logger).GetAllCategories,
 	)
@@ -285,6 +569,20 @@ func (server *Server) AddHTTPRoutes() {
 		),
 	)
 
+	server.router.HandleFunc(apiContext+"/user/submissions",
+		middlewareChain(
+			getusersubmissions.NewHandler(server.appServices, server.config, server.logger).GetUserSubmissions,
+			server.middleware.Authorization.Required,
+		),
+	)
+
+	server.router.HandleFunc(apiContext+"/users/{username}",
+		middlewareChain(
+			getuserprofile.NewHandler(server.appServices, server.config, server.logger).GetUserProfile,
+			server.middleware.Authorization.Optional,
+		),
+	)
+
 	// Notifications routes
 
 	server.router.HandleFunc(apiContext+"/notifications/stream", // get
@@ -321,6 +619,13 @@ func (server *Server) AddHTTPRoutes() {
 			server.middleware.Authorization.Required,
 		),
 	)
+
+	server.router.HandleFunc(apiContext+"/notifications/preferences", // get, post
+		middlewareChain(
+			notificationpreferences.NewHandler(server.notifications).Preferences,
+			server.middleware.Authorization.Required,
+		),
+	)
 }
 
 func (server *Server) ListenAndServe() {
@@ -329,12 +634,11 @@ func (server *Server) ListenAndServe() {
 	if server.config.RateLimit.Enabled {
 		wrappedRouter = middleware.NewRateLimiterMiddleware(
 			wrappedRouter,
-			server.config.RateLimit.RequestsLimit,
-			server.config.RateLimit.WindowSeconds,
-			server.config.RateLimit.Cleanup,
+			server.config.RateLimit,
 		)
 		server.logger.PrintInfo("Rate Limit wrapped", nil)
-		log.Printf("  2. Rate Limit middleware (limit: %d req/%ds cleanup: %s)",
+		log.Printf("  2. Rate Limit middleware (backend: %s limit: %d req/%ds cleanup: %s)",
+			server.config.RateLimit.Backend,
 			server.config.RateLimit.RequestsLimit,
 			server.config.RateLimit.WindowSeconds,
 			server.config.RateLimit.Cleanup.String())
@@ -353,18 +657,38 @@ func (server *Server) ListenAndServe() {
 		"environment": server.config.Environment,
 	})
 
-	var err error
-	if server.config.TLSCertFile != "" && server.config.TLSKeyFile != "" {
-		log.Printf("Starting HTTPS server with TLS certificates")
-		err = srv.ListenAndServeTLS(server.config.TLSCertFile, server.config.TLSKeyFile)
-	} else {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	sweepDone := make(chan struct{})
+	go func() {
+		defer close(sweepDone)
+		sessionstore.SweepExpired(ctx, server.sessionManager, server.config.SessionManager.CleanupInterval, func(err error) {
+			server.logger.PrintError(err, nil)
+		})
+	}()
+
+	err := serveUntilDone(ctx, srv, func() error {
+		if server.config.TLSCertFile != "" && server.config.TLSKeyFile != "" {
+			log.Printf("Starting HTTPS server with TLS certificates")
+			return srv.ListenAndServeTLS(server.config.TLSCertFile, server.config.TLSKeyFile)
+		}
 		log.Printf("Starting HTTP server (no TLS)")
-		err = srv.ListenAndServe()
-	}
+		return srv.ListenAndServe()
+	}, server.config.ShutdownTimeout)
 
-	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+	// The database outlives every request and the session sweeper, so it is
+	// only closed once the server has drained or given up waiting.
+	stop()
+	<-sweepDone
+	closeErr := server.db.Close()
+	if closeErr != nil {
+		server.logger.PrintError(closeErr, nil)
+	}
+	if err != nil {
 		server.logger.PrintFatal(err, nil)
 	}
+	server.logger.PrintInfo("Server stopped", nil)
 }
 
 func (server *Server) initSessionManager() {
@@ -372,11 +696,11 @@ func (server *Server) initSessionManager() {
 }
 
 func (server *Server) initNotifications() {
-	server.notifications = notifications.NewNotificationService(server.db)
+	server.notifications = notifications.NewNotificationService(server.db, server.config.Votes.NotifyInterval)
 }
 
 func (server *Server) initMiddleware(sessionManager session.Manager) {
-	server.middleware = middleware.NewMiddleware(sessionManager)
+	server.middleware = middleware.NewMiddleware(sessionManager, server.config.SessionManager.ReauthWindow, server.config.RateLimit)
 }
 
 func (server *Server) initOAuthServices() {
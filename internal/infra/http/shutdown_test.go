@@ -0,0 +1,109 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestServeUntilDone(t *testing.T) {
+	t.Run("in-flight request completes before shutdown returns", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		srv := &http.Server{
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				close(started)
+				<-release
+				w.WriteHeader(http.StatusNoContent)
+			}),
+			ReadHeaderTimeout: time.Second,
+		}
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("net.Listen() error = %v", err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		served := make(chan error, 1)
+		go func() {
+			served <- serveUntilDone(ctx, srv, func() error { return srv.Serve(ln) }, 5*time.Second)
+		}()
+
+		status := make(chan int, 1)
+		go func() {
+			resp, reqErr := http.Get("http://" + ln.Addr().String())
+			if reqErr != nil {
+				status <- 0
+				return
+			}
+			resp.Body.Close()
+			status <- resp.StatusCode
+		}()
+
+		<-started
+		cancel()
+
+		select {
+		case err = <-served:
+			t.Fatalf("serveUntilDone() returned %v while a request was still running", err)
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		close(release)
+		if got := <-status; got != http.StatusNoContent {
+			t.Errorf("in-flight request status = %d, want %d", got, http.StatusNoContent)
+		}
+		if err = <-served; err != nil {
+			t.Errorf("serveUntilDone() error = %v, want nil", err)
+		}
+	})
+
+	t.Run("serve failure is returned without waiting for ctx", func(t *testing.T) {
+		errListen := errors.New("listen failed")
+		err := serveUntilDone(context.Background(), &http.Server{ReadHeaderTimeout: time.Second}, func() error {
+			return errListen
+		}, time.Second)
+		if !errors.Is(err, errListen) {
+			t.Errorf("serveUntilDone() error = %v, want %v", err, errListen)
+		}
+	})
+
+	t.Run("shutdown gives up after the timeout", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+		started := make(chan struct{})
+		srv := &http.Server{
+			Handler: http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+				close(started)
+				<-release
+			}),
+			ReadHeaderTimeout: time.Second,
+		}
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("net.Listen() error = %v", err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		served := make(chan error, 1)
+		go func() {
+			served <- serveUntilDone(ctx, srv, func() error { return srv.Serve(ln) }, 50*time.Millisecond)
+		}()
+		go func() {
+			resp, reqErr := http.Get("http://" + ln.Addr().String())
+			if reqErr == nil {
+				resp.Body.Close()
+			}
+		}()
+
+		<-started
+		cancel()
+		if err = <-served; !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("serveUntilDone() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package acceptanswer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/arnald/forum/internal/app"
+	topicCommands "github.com/arnald/forum/internal/app/topics/commands"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/domain/comment"
+	"github.com/arnald/forum/internal/domain/notification"
+	"github.com/arnald/forum/internal/domain/topic"
+	"github.com/arnald/forum/internal/domain/user"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/middleware"
+	"github.com/arnald/forum/internal/infra/storage/notifications"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/comments"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/topics"
+	"github.com/arnald/forum/internal/pkg/helpers"
+	"github.com/arnald/forum/internal/pkg/validator"
+)
+
+type ResponseModel struct {
+	Message   string `json:"message"`
+	TopicID   int    `json:"topicId"`
+	CommentID int    `json:"commentId"`
+}
+
+type Handler struct {
+	UserServices app.Services
+	Config       *config.ServerConfig
+	Logger       logger.Logger
+	Notification *notifications.NotificationService
+}
+
+func NewHandler(userServices app.Services, config *config.ServerConfig, logger logger.Logger, notifications *notifications.NotificationService) *Handler {
+	return &Handler{
+		UserServices: userServices,
+		Config:       config,
+		Logger:       logger,
+		Notification: notifications,
+	}
+}
+
+func (h *Handler) AcceptAnswer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	if !h.Config.Topics.Questions {
+		helpers.RespondWithError(w, http.StatusNotFound, "Question topics are disabled")
+		return
+	}
+
+	user := middleware.GetUserFromContext(r)
+	if user == nil {
+		h.Logger.PrintError(logger.ErrUserNotFoundInContext, nil)
+		helpers.RespondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserRegister)
+	defer cancel()
+
+	commentID, err := strconv.Atoi(r.PathValue("commentID"))
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		helpers.RespondWithError(w, http.StatusBadRequest, "Invalid comment ID")
+		return
+	}
+
+	val := validator.New()
+	validator.ValidateModerateComment(val, &struct {
+		CommentID int
+	}{
+		CommentID: commentID,
+	})
+
+	if !val.Valid() {
+		h.Logger.PrintError(logger.ErrValidationFailed, val.Errors)
+		helpers.RespondWithError(w, http.StatusBadRequest, val.ToStringErrors())
+		return
+	}
+
+	question, answer, err := h.UserServices.UserServices.Commands.AcceptAnswer.Handle(ctx, topicCommands.AcceptAnswerRequest{
+		User:      user,
+		CommentID: commentID,
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		switch {
+		case errors.Is(err, topicCommands.ErrNotQuestionAuthor):
+			helpers.RespondWithError(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, topicCommands.ErrNotQuestion), errors.Is(err, topicCommands.ErrAnswerNotApproved):
+			helpers.RespondWithError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, comments.ErrCommentNotFound):
+			helpers.RespondWithError(w, http.StatusNotFound, "Comment not found")
+		case errors.Is(err, topics.ErrTopicNotFound):
+			helpers.RespondWithError(w, http.StatusNotFound, "Topic not found")
+		default:
+			helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to accept answer")
+		}
+		return
+	}
+
+	h.notifyAnswerAuthor(ctx, user, question, answer)
+
+	helpers.RespondWithJSON(w, http.StatusOK, nil, ResponseModel{
+		TopicID:   question.ID,
+		CommentID: answer.ID,
+		Message:   "Answer accepted",
+	})
+
+	h.Logger.PrintInfo(
+		"Answer accepted",
+		map[string]string{
+			"user_id":    user.ID,
+			"topic_id":   strconv.Itoa(question.ID),
+			"comment_id": strconv.Itoa(answer.ID),
+		},
+	)
+}
+
+func (h *Handler) notifyAnswerAuthor(ctx context.Context, accepter *user.User, question *topic.Topic, answer *comment.Comment) {
+	if accepter.ID == answer.UserID {
+		return
+	}
+
+	notification := &notification.Notification{
+		ActorID:     accepter.Username,
+		UserID:      answer.UserID,
+		RelatedID:   strconv.Itoa(question.ID),
+		RelatedType: "topic",
+		Type:        notification.NotificationTypeAnswerAccepted,
+		Title:       "Answer accepted",
+		Message:     fmt.Sprintf("Your answer to %s was accepted", question.Title),
+	}
+
+	err := h.Notification.CreateNotification(ctx, notification)
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+	}
+}
@@ -2,22 +2,34 @@ package createtopic
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/arnald/forum/internal/app"
 	topicCommands "github.com/arnald/forum/internal/app/topics/commands"
 	"github.com/arnald/forum/internal/config"
 	"github.com/arnald/forum/internal/infra/logger"
 	"github.com/arnald/forum/internal/infra/middleware"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/topics"
 	"github.com/arnald/forum/internal/pkg/helpers"
 	"github.com/arnald/forum/internal/pkg/validator"
 )
 
 type RequestModel struct {
-	Title       string `json:"title"`
-	Content     string `json:"content"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
+	// Summary is an optional TL;DR; markup is stripped before it is stored.
+	Summary     string `json:"summary"`
 	ImagePath   string `json:"imagePath"`
 	CategoryIDs []int  `json:"categoryIds"`
+	// CanonicalCategoryID is the primary category; 0 picks the first one.
+	CanonicalCategoryID int `json:"canonicalCategoryId"`
+	// IsQuestion is ignored unless question topics are enabled.
+	IsQuestion bool `json:"isQuestion"`
+	// Draft keeps the topic hidden from everyone but its author until it is
+	// published.
+	Draft bool `json:"draft"`
 }
 
 type ResponseModel struct {
@@ -25,6 +37,15 @@ type ResponseModel struct {
 	Message string `json:"message"`
 }
 
+// DuplicateResponseModel answers a double submission with the topic the
+// first one created.
+type DuplicateResponseModel struct {
+	Error   string `json:"error"`
+	URL     string `json:"url"`
+	Status  string `json:"status"`
+	TopicID int    `json:"topicId"`
+}
+
 type Handler struct {
 	UserServices app.Services
 	Config       *config.ServerConfig
@@ -53,6 +74,14 @@ func (h *Handler) CreateTopic(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.Config.Topics.RequireVerifiedEmail && !user.EmailVerified {
+		helpers.RespondWithError(w,
+			http.StatusBadRequest,
+			"Please verify your email address before posting. Check your inbox for the verification link.",
+		)
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserRegister)
 	defer cancel()
 
@@ -71,28 +100,70 @@ func (h *Handler) CreateTopic(w http.ResponseWriter, r *http.Request) {
 	}
 	defer r.Body.Close()
 
+	createRequest := topicCommands.CreateTopicRequest{
+		CategoryIDs:         topicToCreate.CategoryIDs,
+		CanonicalCategoryID: topicToCreate.CanonicalCategoryID,
+		IsQuestion:          topicToCreate.IsQuestion && h.Config.Topics.Questions,
+		Draft:               topicToCreate.Draft,
+		Title:               topicToCreate.Title,
+		Content:             topicToCreate.Content,
+		Summary:             topicToCreate.Summary,
+		ImagePath:           topicToCreate.ImagePath,
+		User:                user,
+		MinCategories:       h.Config.Topics.MinCategories,
+		SummaryMaxLength:    h.Config.Topics.SummaryMaxLength,
+		DuplicateWindow:     h.Config.Topics.DuplicateWindow,
+		TitleQuality: topicCommands.TitleQuality{
+			MaxUppercasePercent: h.Config.Topics.TitleMaxUppercasePercent,
+			MaxPunctuationRun:   h.Config.Topics.TitleMaxPunctuationRun,
+		},
+	}
+
 	v := validator.New()
 
-	validator.ValidateCreateTopic(v, topicAny)
+	validator.ValidateCreateTopic(v, topicAny, h.Config.Limits)
 
-	if !v.Valid() {
-		helpers.RespondWithError(
+	// Report field and category rule failures together so clients can show
+	// every problem at once instead of one per round trip.
+	createRequest.FieldErrors = v.FieldErrors(topicAny)
+
+	topic, err := h.UserServices.UserServices.Commands.CreateTopic.Handle(ctx, createRequest)
+	var validationErr *topicCommands.ValidationError
+	if errors.As(err, &validationErr) {
+		helpers.RespondWithFieldErrors(
 			w,
-			http.StatusBadRequest,
-			v.ToStringErrors(),
+			http.StatusUnprocessableEntity,
+			"Validation failed",
+			validationErr.Fields,
 		)
 
-		h.Logger.PrintError(logger.ErrValidationFailed, v.Errors)
+		h.Logger.PrintError(logger.ErrValidationFailed, validationErr.Fields)
+		return
+	}
+	var duplicateErr *topicCommands.DuplicateTopicError
+	if errors.As(err, &duplicateErr) {
+		existing := duplicateErr.Existing
+		url := h.Config.Topics.PublicURL + "/topic/" + strconv.Itoa(existing.ID)
+		if existing.Slug != "" {
+			url += "/" + existing.Slug
+		}
+		helpers.RespondWithJSON(w, http.StatusConflict, nil, DuplicateResponseModel{
+			Error:   "You just posted this topic",
+			URL:     url,
+			Status:  existing.Status,
+			TopicID: existing.ID,
+		})
+
+		h.Logger.PrintError(err, nil)
 		return
 	}
+	// A category deleted after validation fails the insert.
+	if errors.Is(err, topics.ErrCategoryNotFound) {
+		helpers.RespondWithError(w, http.StatusBadRequest, "Category not found")
 
-	topic, err := h.UserServices.UserServices.Commands.CreateTopic.Handle(ctx, topicCommands.CreateTopicRequest{
-		CategoryIDs: topicToCreate.CategoryIDs,
-		Title:       topicToCreate.Title,
-		Content:     topicToCreate.Content,
-		ImagePath:   topicToCreate.ImagePath,
-		User:        user,
-	})
+		h.Logger.PrintError(err, nil)
+		return
+	}
 	if err != nil {
 		helpers.RespondWithError(w,
 			http.StatusInternalServerError,
@@ -108,6 +179,9 @@ func (h *Handler) CreateTopic(w http.ResponseWriter, r *http.Request) {
 		UserID:  topic.UserID,
 		Message: "Topic created successfully",
 	}
+	if createRequest.Draft {
+		topicResponse.Message = "Draft saved"
+	}
 
 	helpers.RespondWithJSON(
 		w,
@@ -0,0 +1,212 @@
+package createtopic
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arnald/forum/internal/app"
+	topicCommands "github.com/arnald/forum/internal/app/topics/commands"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/domain/session"
+	"github.com/arnald/forum/internal/domain/topic"
+	"github.com/arnald/forum/internal/domain/user"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/middleware"
+	"github.com/arnald/forum/internal/pkg/helpers"
+	testhelpers "github.com/arnald/forum/internal/pkg/testing"
+	"github.com/arnald/forum/internal/pkg/validator"
+)
+
+func TestHandler_CreateTopic(t *testing.T) {
+	t.Run("group: create topic validation summary", func(t *testing.T) {
+		testCases := newCreateTopicHandlerTestCases()
+		for _, tt := range testCases {
+			t.Run(tt.name, runCreateTopicHandlerTest(tt))
+		}
+	})
+}
+
+type createTopicHandlerTestCase struct {
+	wantFields    map[string]string
+	name          string
+	body          string
+	imageRequired []string
+	wantStatus    int
+	// duplicateOf is the id of a topic the same request created moments ago.
+	duplicateOf int
+	unverified  bool
+}
+
+// testContentLimit is the topic content limit the handler is configured with,
+// well under the default.
+const testContentLimit = 40
+
+func newCreateTopicHandlerTestCases() []createTopicHandlerTestCase {
+	return []createTopicHandlerTestCase{
+		{
+			name:       "every failing rule is reported together",
+			body:       `{"title":"Hi","content":"short","imagePath":"notes.txt","categoryIds":[]}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantFields: map[string]string{
+				"title":       "must be at least 5 characters long",
+				"content":     "must be at least 10 characters long",
+				"imagePath":   "must be a valid image file",
+				"categoryIds": "must select at least 1 categories",
+			},
+		},
+		{
+			name:          "unknown category and missing image are reported with field errors",
+			body:          `{"title":"Hi","content":"Long enough content","categoryIds":[1,42]}`,
+			imageRequired: []string{"Gallery"},
+			wantStatus:    http.StatusUnprocessableEntity,
+			wantFields: map[string]string{
+				"title":       "must be at least 5 characters long",
+				"categoryIds": "unknown categories: 42",
+				"imagePath":   "an image is required by the selected category: Gallery",
+			},
+		},
+		{
+			name:       "shouting title is refused with a message for the title",
+			body:       `{"title":"BUY THIS NOW","content":"Long enough content","categoryIds":[1]}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantFields: map[string]string{
+				"title": "please don't write the title in all caps",
+			},
+		},
+		{
+			name:       "unverified account is told to verify first",
+			body:       `{"title":"Valid title","content":"Long enough content","categoryIds":[1]}`,
+			unverified: true,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "valid request creates the topic",
+			body:       `{"title":"Valid title","content":"Long enough content","categoryIds":[1]}`,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "content at the configured limit is accepted",
+			body:       `{"title":"Valid title","content":"` + strings.Repeat("x", testContentLimit) + `","categoryIds":[1]}`,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "content one over the configured limit is refused",
+			body:       `{"title":"Valid title","content":"` + strings.Repeat("x", testContentLimit+1) + `","categoryIds":[1]}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantFields: map[string]string{
+				"content": "must be 40 characters maximum",
+			},
+		},
+		{
+			name:        "double submission points at the first topic",
+			body:        `{"title":"Valid title","content":"Long enough content","categoryIds":[1]}`,
+			duplicateOf: 7,
+			wantStatus:  http.StatusConflict,
+		},
+	}
+}
+
+func runCreateTopicHandlerTest(tt createTopicHandlerTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		repo := &testhelpers.MockRepository{
+			GetExistingCategoryIDsFunc: func(_ context.Context, _ []int) ([]int, error) {
+				return []int{1}, nil
+			},
+			GetCategoriesRequiringImageFunc: func(_ context.Context, _ []int) ([]string, error) {
+				return tt.imageRequired, nil
+			},
+			CreateTopicFunc: func(_ context.Context, _ *topic.Topic) error { return nil },
+			FindRecentDuplicateFunc: func(_ context.Context, _, _, _ string, _ time.Duration) (*topic.Topic, error) {
+				if tt.duplicateOf == 0 {
+					return nil, nil
+				}
+				return &topic.Topic{ID: tt.duplicateOf, Slug: "valid-title", Status: topic.StatusPublished}, nil
+			},
+		}
+		sessions := &testhelpers.MockSessionManager{
+			GetSessionFromSessionTokensFunc: func(_, _ string) (*session.Session, error) {
+				return &session.Session{
+					AccessToken:        "token",
+					Expiry:             time.Now().Add(time.Hour),
+					RefreshTokenExpiry: time.Now().Add(time.Hour),
+				}, nil
+			},
+			GetUserFromSessionFunc: func(_ string) (*user.User, error) {
+				return &user.User{ID: "test-user-id", EmailVerified: !tt.unverified}, nil
+			},
+		}
+
+		services := app.Services{
+			UserServices: app.UserServices{
+				Commands: app.Commands{
+					CreateTopic: topicCommands.NewCreateTopicHandler(repo),
+				},
+			},
+		}
+		cfg := &config.ServerConfig{
+			Timeouts: config.TimeoutsConfig{
+				HandlerTimeouts: config.HandlerTimeoutsConfig{UserRegister: time.Second},
+			},
+			Topics: config.TopicsConfig{
+				MinCategories:            1,
+				RequireVerifiedEmail:     true,
+				DuplicateWindow:          30 * time.Second,
+				PublicURL:                "https://forum.example",
+				TitleMaxUppercasePercent: 70,
+				TitleMaxPunctuationRun:   3,
+			},
+			Limits: validator.ContentLimits{
+				TopicTitle:     validator.MaxTopicTitleLength,
+				TopicContent:   testContentLimit,
+				CommentContent: validator.MaxCommentContentLength,
+			},
+		}
+		handler := NewHandler(services, cfg, logger.New(io.Discard, logger.LevelOff))
+		authorized := middleware.NewAuthorizationMiddleware(sessions, time.Minute).Required(handler.CreateTopic)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/topics/create", bytes.NewBufferString(tt.body))
+		rec := httptest.NewRecorder()
+
+		authorized(rec, req)
+
+		if rec.Code != tt.wantStatus {
+			t.Fatalf("CreateTopic() status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+		}
+		if tt.duplicateOf != 0 {
+			var got DuplicateResponseModel
+			err := json.NewDecoder(rec.Body).Decode(&got)
+			if err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if got.TopicID != tt.duplicateOf || got.URL != "https://forum.example/topic/7/valid-title" {
+				t.Errorf("CreateTopic() duplicate response = %+v, want topic 7 and its URL", got)
+			}
+			return
+		}
+		if tt.wantFields == nil {
+			return
+		}
+
+		var got helpers.FieldErrorsResponse
+		err := json.NewDecoder(rec.Body).Decode(&got)
+		if err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+
+		if len(got.Fields) != len(tt.wantFields) {
+			t.Fatalf("CreateTopic() fields = %v, want %v", got.Fields, tt.wantFields)
+		}
+		for field, want := range tt.wantFields {
+			if got.Fields[field] != want {
+				t.Errorf("CreateTopic() fields[%q] = %q, want %q", field, got.Fields[field], want)
+			}
+		}
+	}
+}
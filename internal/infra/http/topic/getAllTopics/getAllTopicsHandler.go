@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/arnald/forum/internal/app"
 	topicQueries "github.com/arnald/forum/internal/app/topics/queries"
@@ -23,6 +24,17 @@ type ResponseModel struct {
 	Pagination helpers.PaginationParams `json:"pagination"`
 }
 
+// topicSorts maps each named sort to the ordering the repository applies for
+// it; sort values outside this set are rejected by validation.
+var topicSorts = map[string]struct{ orderBy, order string }{
+	"newest":         {orderBy: "created_at", order: "desc"},
+	"oldest":         {orderBy: "created_at", order: "asc"},
+	"most-liked":     {orderBy: "upvote_count", order: "desc"},
+	"most-commented": {orderBy: "comment_count", order: "desc"},
+	"controversial":  {orderBy: "controversy", order: "desc"},
+	"trending":       {orderBy: "trending", order: "desc"},
+}
+
 type Handler struct {
 	UserServices app.Services
 	Config       *config.ServerConfig
@@ -58,9 +70,12 @@ func (h *Handler) GetAllTopics(w http.ResponseWriter, r *http.Request) {
 	pagination := params.GetPagination()
 
 	orderBy := params.GetQueryStringOr("order_by", "created_at")
-	order := params.GetQueryStringOr("order", "desc")
+	order := strings.ToLower(params.GetQueryStringOr("order", "desc"))
 	filter := params.GetQueryStringOr("search", "")
+	sort := params.GetQueryStringOr("sort", "")
 	categoryID := params.GetQueryIntOr("category", 0)
+	before := params.GetQueryIntOr("before", 0)
+	listFilter := params.GetQueryStringOr("filter", "")
 
 	val := validator.New()
 
@@ -68,13 +83,17 @@ func (h *Handler) GetAllTopics(w http.ResponseWriter, r *http.Request) {
 		OrderBy    string
 		Order      string
 		Search     string
+		Sort       string
 		CategoryID int
 	}{
 		OrderBy:    orderBy,
 		Order:      order,
 		Search:     filter,
+		Sort:       sort,
 		CategoryID: categoryID,
 	})
+	val.Check(before >= 0, "before", "must not be negative")
+	val.Check(listFilter == "" || listFilter == "bookmarked" || listFilter == "drafts", "filter", "must be bookmarked or drafts when set")
 
 	if !val.Valid() {
 		h.Logger.PrintError(logger.ErrValidationFailed, val.Errors)
@@ -84,6 +103,39 @@ func (h *Handler) GetAllTopics(w http.ResponseWriter, r *http.Request) {
 		)
 		return
 	}
+
+	bookmarked := listFilter == "bookmarked"
+	if bookmarked && userID == nil {
+		helpers.RespondWithError(w, http.StatusUnauthorized, "Sign in to see your bookmarks")
+		return
+	}
+	drafts := listFilter == "drafts"
+	if drafts && userID == nil {
+		helpers.RespondWithError(w, http.StatusUnauthorized, "Sign in to see your drafts")
+		return
+	}
+
+	preset, ok := topicSorts[sort]
+	if ok {
+		orderBy, order = preset.orderBy, preset.order
+	}
+
+	// With edit bumps enabled, "newest" means most recently created or bumped.
+	if h.Config.Topics.EditBumps && orderBy == "created_at" {
+		orderBy = "bumped_at"
+	}
+
+	// Topic ids grow with creation time, so an id cursor only follows the
+	// newest-first ordering.
+	keyset := orderBy == "created_at" && order == "desc"
+	if before > 0 && !keyset {
+		helpers.RespondWithError(w,
+			http.StatusBadRequest,
+			"before is only supported when listing newest topics first",
+		)
+		return
+	}
+
 	allTopics, err := h.UserServices.UserServices.Queries.GetAllTopics.Handle(ctx, topicQueries.GetAllTopicsRequest{
 		Page:       pagination.Page,
 		Size:       pagination.Limit,
@@ -92,7 +144,14 @@ func (h *Handler) GetAllTopics(w http.ResponseWriter, r *http.Request) {
 		Order:      order,
 		Filter:     filter,
 		CategoryID: categoryID,
+		Before:     before,
 		UserID:     userID,
+		Bookmarked: bookmarked,
+		Drafts:     drafts,
+		Controversy: topic.ControversyWeights{
+			MinVotes:      h.Config.Topics.ControversyMinVotes,
+			BalanceWeight: h.Config.Topics.ControversyBalanceWeight,
+		},
 	})
 	if err != nil {
 		h.Logger.PrintError(err, nil)
@@ -111,19 +170,28 @@ func (h *Handler) GetAllTopics(w http.ResponseWriter, r *http.Request) {
 		"has_prev":    pagination.Page > 1,
 		"next_page":   nil,
 		"prev_page":   nil,
+		"has_more":    allTopics.HasMore,
+		"next_before": nil,
+	}
+
+	if keyset && allTopics.HasMore {
+		paginationMeta["next_before"] = allTopics.NextBefore
 	}
 
 	if pagination.Page < totalPages {
 		paginationMeta["next_page"] = pagination.Page + 1
 	}
+	// A page past the end links back to the last page that has topics.
 	if pagination.Page > 1 {
-		paginationMeta["prev_page"] = pagination.Page - 1
+		paginationMeta["prev_page"] = min(pagination.Page-1, max(totalPages, 1))
 	}
 
 	appliedFilters := map[string]interface{}{
 		"search":   filter,
 		"order_by": orderBy,
 		"order":    order,
+		"sort":     sort,
+		"filter":   listFilter,
 	}
 
 	response := map[string]interface{}{
@@ -0,0 +1,179 @@
+package getalltopics
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/arnald/forum/internal/app"
+	topicQueries "github.com/arnald/forum/internal/app/topics/queries"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/infra/logger"
+)
+
+// recordingQuery answers every listing with no topics and keeps the request.
+type recordingQuery struct {
+	got *topicQueries.GetAllTopicsRequest
+}
+
+func (q recordingQuery) Handle(_ context.Context, req topicQueries.GetAllTopicsRequest) (*topicQueries.GetAllTopicsResponse, error) {
+	*q.got = req
+	return &topicQueries.GetAllTopicsResponse{}, nil
+}
+
+func TestHandler_GetAllTopics_Sort(t *testing.T) {
+	t.Run("group: sort", func(t *testing.T) {
+		testCases := newSortTestCases()
+		for _, tt := range testCases {
+			t.Run(tt.name, runSortTest(tt))
+		}
+	})
+}
+
+type sortTestCase struct {
+	name        string
+	query       string
+	wantOrderBy string
+	wantOrder   string
+	wantStatus  int
+}
+
+func newSortTestCases() []sortTestCase {
+	return []sortTestCase{
+		{name: "default", query: "", wantStatus: http.StatusOK, wantOrderBy: "created_at", wantOrder: "desc"},
+		{name: "newest", query: "sort=newest", wantStatus: http.StatusOK, wantOrderBy: "created_at", wantOrder: "desc"},
+		{name: "oldest", query: "sort=oldest", wantStatus: http.StatusOK, wantOrderBy: "created_at", wantOrder: "asc"},
+		{name: "most liked", query: "sort=most-liked", wantStatus: http.StatusOK, wantOrderBy: "upvote_count", wantOrder: "desc"},
+		{name: "most commented", query: "sort=most-commented", wantStatus: http.StatusOK, wantOrderBy: "comment_count", wantOrder: "desc"},
+		{name: "controversial", query: "sort=controversial", wantStatus: http.StatusOK, wantOrderBy: "controversy", wantOrder: "desc"},
+		{name: "trending", query: "sort=trending", wantStatus: http.StatusOK, wantOrderBy: "trending", wantOrder: "desc"},
+		{name: "sort overrides order", query: "sort=oldest&order_by=title&order=desc", wantStatus: http.StatusOK, wantOrderBy: "created_at", wantOrder: "asc"},
+		{name: "unknown sort is rejected", query: "sort=random", wantStatus: http.StatusBadRequest},
+		{name: "raw sql is rejected", query: "sort=created_at%3B+DROP+TABLE+topics", wantStatus: http.StatusBadRequest},
+		{name: "raw sql order by is rejected", query: "order_by=id%3B+DROP+TABLE+topics", wantStatus: http.StatusBadRequest},
+		{name: "order is case-insensitive", query: "order_by=title&order=ASC", wantStatus: http.StatusOK, wantOrderBy: "title", wantOrder: "asc"},
+		{name: "raw sql order is rejected", query: "order=desc%3B+DROP+TABLE+topics", wantStatus: http.StatusBadRequest},
+	}
+}
+
+func runSortTest(tt sortTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		var got topicQueries.GetAllTopicsRequest
+		services := app.Services{
+			UserServices: app.UserServices{
+				Queries: app.Queries{GetAllTopics: recordingQuery{got: &got}},
+			},
+		}
+		cfg := &config.ServerConfig{
+			Timeouts: config.TimeoutsConfig{
+				HandlerTimeouts: config.HandlerTimeoutsConfig{UserRegister: time.Second},
+			},
+		}
+		h := NewHandler(services, cfg, logger.New(io.Discard, logger.LevelOff))
+
+		rec := httptest.NewRecorder()
+		h.GetAllTopics(rec, httptest.NewRequest(http.MethodGet, "/api/v1/topics/all?"+tt.query, nil))
+
+		if rec.Code != tt.wantStatus {
+			t.Fatalf("GetAllTopics() status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+		}
+		if tt.wantStatus != http.StatusOK {
+			return
+		}
+		if got.OrderBy != tt.wantOrderBy || got.Order != tt.wantOrder {
+			t.Errorf("GetAllTopics() ordered by %s %s, want %s %s", got.OrderBy, got.Order, tt.wantOrderBy, tt.wantOrder)
+		}
+	}
+}
+
+// countQuery answers every listing with no topics out of a fixed total.
+type countQuery struct {
+	count int
+}
+
+func (q countQuery) Handle(_ context.Context, _ topicQueries.GetAllTopicsRequest) (*topicQueries.GetAllTopicsResponse, error) {
+	return &topicQueries.GetAllTopicsResponse{Count: q.count}, nil
+}
+
+func TestHandler_GetAllTopics_Pagination(t *testing.T) {
+	testCases := []struct {
+		name  string
+		query string
+		want  map[string]any
+	}{
+		{
+			name:  "first page",
+			query: "page=1&limit=20",
+			want: map[string]any{
+				"page": 1.0, "limit": 20.0, "total": 45.0, "total_pages": 3.0,
+				"has_next": true, "has_prev": false, "next_page": 2.0, "prev_page": nil,
+			},
+		},
+		{
+			name:  "last partial page",
+			query: "page=3&limit=20",
+			want: map[string]any{
+				"page": 3.0, "limit": 20.0, "total": 45.0, "total_pages": 3.0,
+				"has_next": false, "has_prev": true, "next_page": nil, "prev_page": 2.0,
+			},
+		},
+		{
+			name:  "page past the end links back to the last page",
+			query: "page=9&limit=20",
+			want: map[string]any{
+				"page": 9.0, "total_pages": 3.0,
+				"has_next": false, "has_prev": true, "next_page": nil, "prev_page": 3.0,
+			},
+		},
+		{
+			name:  "invalid page and limit fall back to defaults",
+			query: "page=-2&limit=1000",
+			want: map[string]any{
+				"page": 1.0, "limit": 20.0, "total_pages": 3.0, "has_prev": false, "prev_page": nil,
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			services := app.Services{
+				UserServices: app.UserServices{
+					Queries: app.Queries{GetAllTopics: countQuery{count: 45}},
+				},
+			}
+			cfg := &config.ServerConfig{
+				Timeouts: config.TimeoutsConfig{
+					HandlerTimeouts: config.HandlerTimeoutsConfig{UserRegister: time.Second},
+				},
+			}
+			h := NewHandler(services, cfg, logger.New(io.Discard, logger.LevelOff))
+
+			rec := httptest.NewRecorder()
+			h.GetAllTopics(rec, httptest.NewRequest(http.MethodGet, "/api/v1/topics/all?"+tt.query, nil))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("GetAllTopics() status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+			}
+
+			var body struct {
+				Data struct {
+					Pagination map[string]any `json:"pagination"`
+				} `json:"data"`
+			}
+			err := json.NewDecoder(rec.Body).Decode(&body)
+			if err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			for key, want := range tt.want {
+				if got := body.Data.Pagination[key]; got != want {
+					t.Errorf("pagination[%s] = %v, want %v", key, got, want)
+				}
+			}
+		})
+	}
+}
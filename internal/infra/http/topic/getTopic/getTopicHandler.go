@@ -6,9 +6,11 @@ import (
 	"net/http"
 
 	"github.com/arnald/forum/internal/app"
+	topicCommands "github.com/arnald/forum/internal/app/topics/commands"
 	topicQueries "github.com/arnald/forum/internal/app/topics/queries"
 	"github.com/arnald/forum/internal/config"
 	"github.com/arnald/forum/internal/domain/comment"
+	"github.com/arnald/forum/internal/domain/topic"
 	"github.com/arnald/forum/internal/infra/logger"
 	"github.com/arnald/forum/internal/infra/middleware"
 	"github.com/arnald/forum/internal/infra/storage/sqlite/topics"
@@ -16,23 +18,39 @@ import (
 	"github.com/arnald/forum/internal/pkg/validator"
 )
 
+// DefaultCommentsLimit is how many comments are returned with a topic when
+// the request does not set comments_limit.
+const DefaultCommentsLimit = 50
+
 type ResponseModel struct {
-	UserVote       *int              `json:"userVote"`
-	Content        string            `json:"content"`
-	ImagePath      string            `json:"imagePath"`
-	UserID         string            `json:"userId"`
-	OwnerUsername  string            `json:"ownerUsername"`
-	CreatedAt      string            `json:"createdAt"`
-	UpdatedAt      string            `json:"updatedAt"`
-	Title          string            `json:"title"`
-	CategoryNames  []string          `json:"categoryNames"`
-	CategoryColors []string          `json:"categoryColors"`
-	Comments       []comment.Comment `json:"comments"`
-	CategoryIDs    []int             `json:"categoryIds"`
-	Upvotes        int               `json:"upvotes"`
-	Downvotes      int               `json:"downvotes"`
-	Score          int               `json:"score"`
-	TopicID        int               `json:"topicId"`
+	UserVote            *int              `json:"userVote"`
+	Content             string            `json:"content"`
+	Summary             string            `json:"summary,omitempty"`
+	ImagePath           string            `json:"imagePath"`
+	UserID              string            `json:"userId"`
+	OwnerUsername       string            `json:"ownerUsername"`
+	CreatedAt           string            `json:"createdAt"`
+	UpdatedAt           string            `json:"updatedAt"`
+	Title               string            `json:"title"`
+	Slug                string            `json:"slug"`
+	Status              string            `json:"status"`
+	CategoryNames       []string          `json:"categoryNames"`
+	CategoryColors      []string          `json:"categoryColors"`
+	Comments            []comment.Comment `json:"comments"`
+	CategoryIDs         []int             `json:"categoryIds"`
+	Upvotes             int               `json:"upvotes"`
+	Downvotes           int               `json:"downvotes"`
+	Score               int               `json:"score"`
+	ViewCount           int               `json:"viewCount"`
+	TopicID             int               `json:"topicId"`
+	CanonicalCategoryID int               `json:"canonicalCategoryId"`
+	AcceptedCommentID   int               `json:"acceptedCommentId,omitempty"`
+	CommentCount        int               `json:"commentCount"`
+	CommentsOffset      int               `json:"commentsOffset"`
+	CommentsLimit       int               `json:"commentsLimit"`
+	Removed             bool              `json:"removed,omitempty"`
+	IsQuestion          bool              `json:"isQuestion"`
+	IsEdited            bool              `json:"isEdited"`
 }
 
 type Handler struct {
@@ -75,12 +93,18 @@ func (h *Handler) GetTopic(w http.ResponseWriter, r *http.Request) {
 
 	val := validator.New()
 
+	params := helpers.NewURLParams(r)
 	testStruct := &struct {
-		TopicID int
+		TopicID        int
+		CommentsLimit  int
+		CommentsOffset int
 	}{
-		TopicID: topicID,
+		TopicID:        topicID,
+		CommentsLimit:  params.GetQueryIntOr("comments_limit", DefaultCommentsLimit),
+		CommentsOffset: params.GetQueryIntOr("comments_offset", 0),
 	}
 	validator.ValidateGetTopic(val, testStruct)
+	validator.ValidateCommentPage(val, testStruct)
 
 	if !val.Valid() {
 		h.Logger.PrintError(logger.ErrValidationFailed, val.Errors)
@@ -95,8 +119,10 @@ func (h *Handler) GetTopic(w http.ResponseWriter, r *http.Request) {
 	defer cancel()
 
 	topic, err := h.UserServices.UserServices.Queries.GetTopic.Handle(ctx, topicQueries.GetTopicRequest{
-		TopicID: topicID,
-		UserID:  userID,
+		TopicID:       topicID,
+		UserID:        userID,
+		CommentLimit:  testStruct.CommentsLimit,
+		CommentOffset: testStruct.CommentsOffset,
 	})
 	if err != nil {
 		if errors.Is(err, topics.ErrTopicNotFound) {
@@ -109,24 +135,63 @@ func (h *Handler) GetTopic(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !topic.Removed {
+		h.recordView(ctx, r, topic)
+	}
+
 	response := ResponseModel{
-		TopicID:        topic.ID,
-		CategoryIDs:    topic.CategoryIDs,
-		CategoryNames:  topic.CategoryNames,
-		CategoryColors: topic.CategoryColors,
-		Title:          topic.Title,
-		Content:        topic.Content,
-		ImagePath:      topic.ImagePath,
-		UserID:         topic.UserID,
-		OwnerUsername:  topic.OwnerUsername,
-		CreatedAt:      topic.CreatedAt,
-		UpdatedAt:      topic.UpdatedAt,
-		Comments:       topic.Comments,
-		Upvotes:        topic.UpvoteCount,
-		Downvotes:      topic.DownvoteCount,
-		Score:          topic.VoteScore,
-		UserVote:       topic.UserVote,
+		TopicID:             topic.ID,
+		CategoryIDs:         topic.CategoryIDs,
+		CanonicalCategoryID: topic.CanonicalCategoryID,
+		IsQuestion:          topic.IsQuestion,
+		IsEdited:            topic.IsEdited,
+		AcceptedCommentID:   topic.AcceptedCommentID,
+		CategoryNames:       topic.CategoryNames,
+		CategoryColors:      topic.CategoryColors,
+		Title:               topic.Title,
+		Slug:                topic.Slug,
+		Content:             topic.Content,
+		Summary:             topic.Summary,
+		ImagePath:           topic.ImagePath,
+		UserID:              topic.UserID,
+		OwnerUsername:       topic.OwnerUsername,
+		CreatedAt:           topic.CreatedAt,
+		UpdatedAt:           topic.UpdatedAt,
+		Comments:            topic.Comments,
+		CommentCount:        topic.CommentCount,
+		CommentsOffset:      testStruct.CommentsOffset,
+		CommentsLimit:       testStruct.CommentsLimit,
+		Upvotes:             topic.UpvoteCount,
+		Downvotes:           topic.DownvoteCount,
+		Score:               topic.VoteScore,
+		ViewCount:           topic.ViewCount,
+		UserVote:            topic.UserVote,
+		Removed:             topic.Removed,
+		Status:              topic.Status,
 	}
 
 	helpers.RespondWithJSON(w, http.StatusOK, nil, response)
 }
+
+// recordView counts the request as a view of the topic, keyed on the signed-in
+// user or, for visitors, their IP. A failure only loses the view.
+func (h *Handler) recordView(ctx context.Context, r *http.Request, viewed *topic.Topic) {
+	viewerKey := "ip:" + middleware.GetClientIP(r)
+	user := middleware.GetUserFromContext(r)
+	if user != nil {
+		viewerKey = "user:" + user.ID
+	}
+
+	counted, err := h.UserServices.UserServices.Commands.RecordView.Handle(ctx, topicCommands.RecordTopicViewRequest{
+		TopicID:   viewed.ID,
+		ViewerKey: viewerKey,
+		Window:    h.Config.Topics.ViewWindow,
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		return
+	}
+	if counted {
+		viewed.ViewCount++
+	}
+}
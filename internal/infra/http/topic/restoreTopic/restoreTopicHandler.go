@@ -0,0 +1,104 @@
+package restoretopic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/arnald/forum/internal/app"
+	topicCommands "github.com/arnald/forum/internal/app/topics/commands"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/middleware"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/topics"
+	"github.com/arnald/forum/internal/pkg/helpers"
+	"github.com/arnald/forum/internal/pkg/validator"
+)
+
+type ResponseModel struct {
+	Message string `json:"message"`
+	TopicID int    `json:"topicId"`
+}
+
+type Handler struct {
+	UserServices app.Services
+	Config       *config.ServerConfig
+	Logger       logger.Logger
+}
+
+func NewHandler(userServices app.Services, config *config.ServerConfig, logger logger.Logger) *Handler {
+	return &Handler{
+		UserServices: userServices,
+		Config:       config,
+		Logger:       logger,
+	}
+}
+
+// RestoreTopic brings back a soft-deleted topic. Routes must wrap it in
+// RequireAdmin.
+func (h *Handler) RestoreTopic(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	user := middleware.GetUserFromContext(r)
+	if user == nil {
+		h.Logger.PrintError(logger.ErrUserNotFoundInContext, nil)
+		helpers.RespondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserRegister)
+	defer cancel()
+
+	topicID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		helpers.RespondWithError(w, http.StatusBadRequest, "Invalid topic ID")
+		return
+	}
+
+	val := validator.New()
+	validator.ValidateDeleteTopic(val, &struct {
+		TopicID int
+	}{
+		TopicID: topicID,
+	})
+
+	if !val.Valid() {
+		h.Logger.PrintError(logger.ErrValidationFailed, val.Errors)
+		helpers.RespondWithError(w, http.StatusBadRequest, val.ToStringErrors())
+		return
+	}
+
+	err = h.UserServices.UserServices.Commands.RestoreTopic.Handle(ctx, topicCommands.RestoreTopicRequest{
+		TopicID: topicID,
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		if errors.Is(err, topics.ErrTopicNotFound) {
+			helpers.RespondWithError(w, http.StatusNotFound, "Deleted topic not found")
+			return
+		}
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to restore topic")
+		return
+	}
+
+	helpers.RespondWithJSON(w,
+		http.StatusOK,
+		nil,
+		ResponseModel{
+			TopicID: topicID,
+			Message: "Topic restored successfully",
+		})
+
+	h.Logger.PrintInfo(
+		"Topic restored successfully",
+		map[string]string{
+			"topic_id": strconv.Itoa(topicID),
+			"user_id":  user.ID,
+		})
+}
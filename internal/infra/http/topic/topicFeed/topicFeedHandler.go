@@ -0,0 +1,183 @@
+package topicfeed
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/arnald/forum/internal/app"
+	categoryQueries "github.com/arnald/forum/internal/app/categories/queries"
+	topicQueries "github.com/arnald/forum/internal/app/topics/queries"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/domain/topic"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/categories"
+	"github.com/arnald/forum/internal/pkg/helpers"
+)
+
+const (
+	// FeedSize is how many of the newest topics a feed lists.
+	FeedSize = 20
+
+	feedTitle       = "Forum"
+	feedContentType = "application/rss+xml; charset=utf-8"
+)
+
+// RSS is an RSS 2.0 document. Authors go in Dublin Core's creator element,
+// since the RSS author element is meant for an email address.
+type RSS struct {
+	XMLName xml.Name `xml:"rss"`
+	Version string   `xml:"version,attr"`
+	Channel Channel  `xml:"channel"`
+}
+
+type Channel struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Description string `xml:"description"`
+	Items       []Item `xml:"item"`
+}
+
+type Item struct {
+	Title   string `xml:"title"`
+	Link    string `xml:"link"`
+	GUID    string `xml:"guid"`
+	Author  string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	PubDate string `xml:"pubDate,omitempty"`
+}
+
+type Handler struct {
+	UserServices app.Services
+	Config       *config.ServerConfig
+	Logger       logger.Logger
+}
+
+func NewHandler(userServices app.Services, config *config.ServerConfig, logger logger.Logger) *Handler {
+	return &Handler{
+		UserServices: userServices,
+		Config:       config,
+		Logger:       logger,
+	}
+}
+
+// Feed serves the newest published topics across the forum as RSS.
+func (h *Handler) Feed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserRegister)
+	defer cancel()
+
+	h.serveFeed(ctx, w, Channel{
+		Title:       feedTitle,
+		Link:        h.Config.Topics.PublicURL + "/topics",
+		Description: "Newest topics",
+	}, 0)
+}
+
+// CategoryFeed serves the newest published topics filed under the category
+// in the {id} path segment as RSS.
+func (h *Handler) CategoryFeed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	categoryID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || categoryID < 1 {
+		helpers.RespondWithError(w, http.StatusBadRequest, "Invalid category ID")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserRegister)
+	defer cancel()
+
+	found, err := h.UserServices.UserServices.Queries.GetCategoryByID.Handle(ctx, categoryQueries.GetCategoryByIDRequest{
+		ID: categoryID,
+	})
+	if errors.Is(err, categories.ErrCategoryNotFound) {
+		helpers.RespondWithError(w, http.StatusNotFound, "Category not found")
+		return
+	}
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to get category")
+		return
+	}
+
+	h.serveFeed(ctx, w, Channel{
+		Title:       feedTitle + " - " + found.Name,
+		Link:        h.Config.Topics.PublicURL + "/topics?category=" + strconv.Itoa(categoryID),
+		Description: "Newest topics in " + found.Name,
+	}, categoryID)
+}
+
+// serveFeed fills channel with the newest topics, all of them or those in
+// categoryID, and writes it out. Topics are fetched as an anonymous viewer,
+// so drafts and anything else private never reach a feed.
+func (h *Handler) serveFeed(ctx context.Context, w http.ResponseWriter, channel Channel, categoryID int) {
+	response, err := h.UserServices.UserServices.Queries.GetAllTopics.Handle(ctx, topicQueries.GetAllTopicsRequest{
+		Page:       1,
+		Size:       FeedSize,
+		CategoryID: categoryID,
+		OrderBy:    "created_at",
+		Order:      "desc",
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to get topics")
+		return
+	}
+
+	channel.Items = make([]Item, 0, len(response.Topics))
+	for i := range response.Topics {
+		channel.Items = append(channel.Items, h.newItem(&response.Topics[i]))
+	}
+
+	body, err := xml.MarshalIndent(RSS{Version: "2.0", Channel: channel}, "", "  ")
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to build feed")
+		return
+	}
+
+	w.Header().Set("Content-Type", feedContentType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(xml.Header))
+	_, _ = w.Write(body)
+}
+
+func (h *Handler) newItem(found *topic.Topic) Item {
+	link := h.Config.Topics.PublicURL + "/topic/" + strconv.Itoa(found.ID)
+	if found.Slug != "" {
+		link += "/" + found.Slug
+	}
+
+	return Item{
+		Title:   found.Title,
+		Link:    link,
+		GUID:    link,
+		Author:  found.OwnerUsername,
+		PubDate: pubDate(found.CreatedAt),
+	}
+}
+
+// pubDate converts a topic timestamp, which the repository may already have
+// shortened to the day, to the RFC 822 form RSS expects. Unparseable dates
+// are left out rather than guessed.
+func pubDate(createdAt string) string {
+	for _, layout := range []string{time.RFC3339, "02/01/2006"} {
+		t, err := time.Parse(layout, createdAt)
+		if err == nil {
+			return t.UTC().Format(time.RFC1123Z)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,159 @@
+package topicfeed
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/arnald/forum/internal/app"
+	categoryQueries "github.com/arnald/forum/internal/app/categories/queries"
+	topicQueries "github.com/arnald/forum/internal/app/topics/queries"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/domain/category"
+	"github.com/arnald/forum/internal/domain/topic"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/categories"
+)
+
+const feedCategoryID = 3
+
+// stubTopics lists three topics, one of them filed under feedCategoryID.
+type stubTopics struct{}
+
+func (stubTopics) Handle(_ context.Context, req topicQueries.GetAllTopicsRequest) (*topicQueries.GetAllTopicsResponse, error) {
+	all := []topic.Topic{
+		{ID: 3, Title: "Third", Slug: "third", OwnerUsername: "carol", CreatedAt: "2025-03-04T10:00:00Z", CategoryIDs: []int{feedCategoryID}},
+		{ID: 2, Title: "Second & more", OwnerUsername: "bob", CreatedAt: "03/02/2025", CategoryIDs: []int{1}},
+		{ID: 1, Title: "First", OwnerUsername: "alice", CreatedAt: "01/01/2025", CategoryIDs: []int{1}},
+	}
+
+	if req.CategoryID == 0 {
+		return &topicQueries.GetAllTopicsResponse{Topics: all, Count: len(all)}, nil
+	}
+	filtered := make([]topic.Topic, 0)
+	for _, t := range all {
+		if t.CategoryIDs[0] == req.CategoryID {
+			filtered = append(filtered, t)
+		}
+	}
+	return &topicQueries.GetAllTopicsResponse{Topics: filtered, Count: len(filtered)}, nil
+}
+
+type stubCategory struct{}
+
+func (stubCategory) Handle(_ context.Context, req categoryQueries.GetCategoryByIDRequest) (*category.Category, error) {
+	if req.ID != feedCategoryID {
+		return nil, fmt.Errorf("category with ID %d not found: %w", req.ID, categories.ErrCategoryNotFound)
+	}
+	return &category.Category{ID: feedCategoryID, Name: "Announcements"}, nil
+}
+
+func newTestMux() *http.ServeMux {
+	services := app.Services{
+		UserServices: app.UserServices{
+			Queries: app.Queries{
+				GetAllTopics:    stubTopics{},
+				GetCategoryByID: stubCategory{},
+			},
+		},
+	}
+	cfg := &config.ServerConfig{
+		Timeouts: config.TimeoutsConfig{
+			HandlerTimeouts: config.HandlerTimeoutsConfig{UserRegister: time.Second},
+		},
+		Topics: config.TopicsConfig{PublicURL: "https://forum.example"},
+	}
+
+	h := NewHandler(services, cfg, logger.New(io.Discard, logger.LevelOff))
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/feed", h.Feed)
+	mux.HandleFunc("/api/v1/category/{id}/feed", h.CategoryFeed)
+	return mux
+}
+
+func TestHandler_Feed(t *testing.T) {
+	t.Run("group: feeds", func(t *testing.T) {
+		testCases := newFeedTestCases()
+		for _, tt := range testCases {
+			t.Run(tt.name, runFeedTest(newTestMux(), tt))
+		}
+	})
+}
+
+type feedTestCase struct {
+	name       string
+	path       string
+	wantTitle  string
+	wantLinks  []string
+	wantStatus int
+}
+
+func newFeedTestCases() []feedTestCase {
+	return []feedTestCase{
+		{
+			name:       "site feed lists every topic",
+			path:       "/api/v1/feed",
+			wantStatus: http.StatusOK,
+			wantTitle:  "Forum",
+			wantLinks: []string{
+				"https://forum.example/topic/3/third",
+				"https://forum.example/topic/2",
+				"https://forum.example/topic/1",
+			},
+		},
+		{
+			name:       "category feed lists only its topics",
+			path:       "/api/v1/category/3/feed",
+			wantStatus: http.StatusOK,
+			wantTitle:  "Forum - Announcements",
+			wantLinks:  []string{"https://forum.example/topic/3/third"},
+		},
+		{name: "unknown category is not found", path: "/api/v1/category/9/feed", wantStatus: http.StatusNotFound},
+		{name: "malformed category id", path: "/api/v1/category/abc/feed", wantStatus: http.StatusBadRequest},
+	}
+}
+
+func runFeedTest(mux *http.ServeMux, tt feedTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if rec.Code != tt.wantStatus {
+			t.Fatalf("GET %s status = %d, want %d: %s", tt.path, rec.Code, tt.wantStatus, rec.Body.String())
+		}
+		if tt.wantStatus != http.StatusOK {
+			return
+		}
+		if got := rec.Header().Get("Content-Type"); got != feedContentType {
+			t.Errorf("Content-Type = %q, want %q", got, feedContentType)
+		}
+
+		var feed RSS
+		err := xml.Unmarshal(rec.Body.Bytes(), &feed)
+		if err != nil {
+			t.Fatalf("feed is not valid XML: %v\n%s", err, rec.Body.String())
+		}
+		if feed.Version != "2.0" {
+			t.Errorf("rss version = %q, want 2.0", feed.Version)
+		}
+		if feed.Channel.Title != tt.wantTitle {
+			t.Errorf("channel title = %q, want %q", feed.Channel.Title, tt.wantTitle)
+		}
+		if len(feed.Channel.Items) != len(tt.wantLinks) {
+			t.Fatalf("feed has %d items, want %d", len(feed.Channel.Items), len(tt.wantLinks))
+		}
+		for i, item := range feed.Channel.Items {
+			if item.Link != tt.wantLinks[i] {
+				t.Errorf("item %d link = %q, want %q", i, item.Link, tt.wantLinks[i])
+			}
+			if item.Author == "" || item.PubDate == "" {
+				t.Errorf("item %d = %+v, want an author and a publication date", i, item)
+			}
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package topichistory
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/arnald/forum/internal/app"
+	topicQueries "github.com/arnald/forum/internal/app/topics/queries"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/domain/topic"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/middleware"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/topics"
+	"github.com/arnald/forum/internal/pkg/helpers"
+	"github.com/arnald/forum/internal/pkg/validator"
+)
+
+type RevisionResponse struct {
+	Title          string `json:"title"`
+	Content        string `json:"content"`
+	EditorID       string `json:"editorId"`
+	EditorUsername string `json:"editorUsername"`
+	CreatedAt      string `json:"createdAt"`
+	ID             int    `json:"id"`
+}
+
+type ResponseModel struct {
+	Revisions []RevisionResponse `json:"revisions"`
+	TopicID   int                `json:"topicId"`
+}
+
+type Handler struct {
+	UserServices app.Services
+	Config       *config.ServerConfig
+	Logger       logger.Logger
+}
+
+func NewHandler(userServices app.Services, config *config.ServerConfig, logger logger.Logger) *Handler {
+	return &Handler{
+		UserServices: userServices,
+		Config:       config,
+		Logger:       logger,
+	}
+}
+
+func (h *Handler) GetTopicHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	user := middleware.GetUserFromContext(r)
+	if user == nil {
+		h.Logger.PrintError(logger.ErrUserNotFoundInContext, nil)
+		helpers.RespondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserRegister)
+	defer cancel()
+
+	topicID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		helpers.RespondWithError(w, http.StatusBadRequest, "Invalid topic ID")
+		return
+	}
+
+	val := validator.New()
+	validator.ValidateGetTopic(val, &struct {
+		TopicID int
+	}{
+		TopicID: topicID,
+	})
+
+	if !val.Valid() {
+		h.Logger.PrintError(logger.ErrValidationFailed, val.Errors)
+		helpers.RespondWithError(w, http.StatusBadRequest, val.ToStringErrors())
+		return
+	}
+
+	revisions, err := h.UserServices.UserServices.Queries.GetTopicHistory.Handle(ctx, topicQueries.GetTopicHistoryRequest{
+		User:    user,
+		TopicID: topicID,
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		switch {
+		case errors.Is(err, topics.ErrTopicNotFound):
+			helpers.RespondWithError(w, http.StatusNotFound, "Topic not found")
+		case errors.Is(err, topicQueries.ErrHistoryNotAllowed):
+			helpers.RespondWithError(w, http.StatusForbidden, "Only the topic's author or staff can view its history")
+		default:
+			helpers.RespondWithError(w, http.StatusInternalServerError, "Error getting topic history")
+		}
+		return
+	}
+
+	helpers.RespondWithJSON(w,
+		http.StatusOK,
+		nil,
+		ResponseModel{
+			TopicID:   topicID,
+			Revisions: toRevisionResponses(revisions),
+		})
+}
+
+func toRevisionResponses(revisions []topic.Revision) []RevisionResponse {
+	response := make([]RevisionResponse, 0, len(revisions))
+	for _, rev := range revisions {
+		response = append(response, RevisionResponse{
+			ID:             rev.ID,
+			Title:          rev.Title,
+			Content:        rev.Content,
+			EditorID:       rev.EditorID,
+			EditorUsername: rev.EditorUsername,
+			CreatedAt:      rev.CreatedAt,
+		})
+	}
+	return response
+}
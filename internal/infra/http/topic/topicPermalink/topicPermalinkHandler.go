@@ -0,0 +1,213 @@
+package topicpermalink
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/arnald/forum/internal/app"
+	topicQueries "github.com/arnald/forum/internal/app/topics/queries"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/domain/topic"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/topics"
+	"github.com/arnald/forum/internal/pkg/helpers"
+	"github.com/arnald/forum/internal/pkg/validator"
+)
+
+const permalinkSuffix = ".json"
+
+type CategoryModel struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+	ID    int    `json:"id"`
+}
+
+// ResponseModel is the public, viewer-independent representation of a topic.
+// It leaves out anything personal, like the viewer's own vote, so one cached
+// copy serves everyone.
+type ResponseModel struct {
+	Title             string          `json:"title"`
+	Slug              string          `json:"slug,omitempty"`
+	Content           string          `json:"content"`
+	Summary           string          `json:"summary,omitempty"`
+	ImagePath         string          `json:"imagePath,omitempty"`
+	AuthorID          string          `json:"authorId"`
+	Author            string          `json:"author"`
+	CreatedAt         string          `json:"createdAt"`
+	UpdatedAt         string          `json:"updatedAt"`
+	CanonicalURL      string          `json:"canonicalUrl"`
+	Categories        []CategoryModel `json:"categories"`
+	ID                int             `json:"id"`
+	Upvotes           int             `json:"upvotes"`
+	Downvotes         int             `json:"downvotes"`
+	Score             int             `json:"score"`
+	CommentCount      int             `json:"commentCount"`
+	AcceptedCommentID int             `json:"acceptedCommentId,omitempty"`
+	IsQuestion        bool            `json:"isQuestion"`
+}
+
+type Handler struct {
+	UserServices app.Services
+	Config       *config.ServerConfig
+	Logger       logger.Logger
+}
+
+func NewHandler(userServices app.Services, config *config.ServerConfig, logger logger.Logger) *Handler {
+	return &Handler{
+		UserServices: userServices,
+		Config:       config,
+		Logger:       logger,
+	}
+}
+
+// Permalink serves /posts/{id}.json. The ETag hashes the representation
+// rather than just updated_at: timestamps only keep the day, and votes and
+// new comments change the counts without touching updated_at at all.
+func (h *Handler) Permalink(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	rawID, ok := strings.CutSuffix(r.PathValue("file"), permalinkSuffix)
+	if !ok {
+		helpers.RespondWithError(w, http.StatusNotFound, "Topic not found")
+		return
+	}
+
+	topicID, err := strconv.Atoi(rawID)
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		helpers.RespondWithError(w, http.StatusBadRequest, "Invalid topic ID")
+		return
+	}
+
+	val := validator.New()
+
+	topicIDVal := &struct {
+		TopicID int
+	}{
+		TopicID: topicID,
+	}
+	validator.ValidateGetTopic(val, topicIDVal)
+
+	if !val.Valid() {
+		h.Logger.PrintError(logger.ErrValidationFailed, val.Errors)
+		helpers.RespondWithError(w, http.StatusBadRequest, val.ToStringErrors())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserRegister)
+	defer cancel()
+
+	// Fetched as an anonymous viewer: the permalink is public, so it must
+	// only show what anyone could see.
+	found, err := h.UserServices.UserServices.Queries.GetTopic.Handle(ctx, topicQueries.GetTopicRequest{
+		TopicID: topicID,
+	})
+	if errors.Is(err, topics.ErrTopicNotFound) || (err == nil && found.Removed) {
+		helpers.RespondWithError(w, http.StatusNotFound, "Topic not found")
+		return
+	}
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to get topic")
+		return
+	}
+
+	response := h.newResponse(found)
+
+	etag, err := representationETag(response)
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to get topic")
+		return
+	}
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(h.Config.Topics.PermalinkMaxAge.Seconds())))
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	helpers.RespondWithJSON(w, http.StatusOK, nil, response)
+}
+
+func (h *Handler) newResponse(found *topic.Topic) ResponseModel {
+	categories := make([]CategoryModel, 0, len(found.CategoryIDs))
+	for i, id := range found.CategoryIDs {
+		category := CategoryModel{ID: id}
+		if i < len(found.CategoryNames) {
+			category.Name = found.CategoryNames[i]
+		}
+		if i < len(found.CategoryColors) {
+			category.Color = found.CategoryColors[i]
+		}
+		categories = append(categories, category)
+	}
+
+	return ResponseModel{
+		ID:                found.ID,
+		Title:             found.Title,
+		Slug:              found.Slug,
+		Content:           found.Content,
+		Summary:           found.Summary,
+		ImagePath:         found.ImagePath,
+		AuthorID:          found.UserID,
+		Author:            found.OwnerUsername,
+		CreatedAt:         found.CreatedAt,
+		UpdatedAt:         found.UpdatedAt,
+		CanonicalURL:      canonicalURL(h.Config.Topics.PublicURL, found),
+		Categories:        categories,
+		Upvotes:           found.UpvoteCount,
+		Downvotes:         found.DownvoteCount,
+		Score:             found.VoteScore,
+		CommentCount:      len(found.Comments),
+		IsQuestion:        found.IsQuestion,
+		AcceptedCommentID: found.AcceptedCommentID,
+	}
+}
+
+// representationETag returns a strong ETag for the response body.
+func representationETag(response ResponseModel) (string, error) {
+	body, err := json.Marshal(response)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// etagMatches reports whether an If-None-Match header names etag. GET uses
+// the weak comparison, so a W/ prefix on either side is ignored.
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// canonicalURL points at the topic's page, with its slug when it has one;
+// the page also answers to the bare id.
+func canonicalURL(publicURL string, found *topic.Topic) string {
+	url := publicURL + "/topic/" + strconv.Itoa(found.ID)
+	if found.Slug != "" {
+		url += "/" + found.Slug
+	}
+	return url
+}
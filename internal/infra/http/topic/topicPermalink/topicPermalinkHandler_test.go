@@ -0,0 +1,158 @@
+package topicpermalink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/arnald/forum/internal/app"
+	topicQueries "github.com/arnald/forum/internal/app/topics/queries"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/domain/comment"
+	"github.com/arnald/forum/internal/domain/topic"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/topics"
+	testhelpers "github.com/arnald/forum/internal/pkg/testing"
+)
+
+type stubCommentRepo struct {
+	comment.Repository
+}
+
+func (s *stubCommentRepo) GetCommentsWithVotes(_ context.Context, _ int, _ *string) ([]comment.Comment, error) {
+	return []comment.Comment{{ID: 1}, {ID: 2}}, nil
+}
+
+const (
+	liveTopicID    = 7
+	removedTopicID = 8
+)
+
+func newTestMux() *http.ServeMux {
+	repo := &testhelpers.MockRepository{
+		GetTopicByIDFunc: func(_ context.Context, topicID int, _ *string) (*topic.Topic, error) {
+			if topicID != liveTopicID {
+				return nil, fmt.Errorf("topic with ID %d not found: %w", topicID, topics.ErrTopicNotFound)
+			}
+			return &topic.Topic{
+				ID:            liveTopicID,
+				Title:         "Permalinks",
+				Content:       "Stable JSON for embedding",
+				UserID:        "author-id",
+				OwnerUsername: "author",
+				CategoryIDs:   []int{1},
+				CategoryNames: []string{"General"},
+				UpvoteCount:   3,
+			}, nil
+		},
+		IsTopicRemovedFunc: func(_ context.Context, topicID int, _ *string) (bool, error) {
+			return topicID == removedTopicID, nil
+		},
+	}
+
+	services := app.Services{
+		UserServices: app.UserServices{
+			Queries: app.Queries{
+				GetTopic: topicQueries.NewGetTopicHandler(repo, &stubCommentRepo{}),
+			},
+		},
+	}
+	cfg := &config.ServerConfig{
+		Timeouts: config.TimeoutsConfig{
+			HandlerTimeouts: config.HandlerTimeoutsConfig{UserRegister: time.Second},
+		},
+		Topics: config.TopicsConfig{
+			PublicURL:       "https://forum.example",
+			PermalinkMaxAge: time.Minute,
+		},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/posts/{file}", NewHandler(services, cfg, logger.New(io.Discard, logger.LevelOff)).Permalink)
+	return mux
+}
+
+func serve(mux *http.ServeMux, path, ifNoneMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandler_Permalink(t *testing.T) {
+	mux := newTestMux()
+
+	first := serve(mux, "/api/v1/posts/7.json", "")
+	if first.Code != http.StatusOK {
+		t.Fatalf("Permalink() status = %d, want %d: %s", first.Code, http.StatusOK, first.Body.String())
+	}
+	etag := first.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("Permalink() did not set an ETag")
+	}
+	if got := first.Header().Get("Cache-Control"); got != "public, max-age=60" {
+		t.Errorf("Cache-Control = %q, want %q", got, "public, max-age=60")
+	}
+
+	var body struct {
+		Data ResponseModel `json:"data"`
+	}
+	err := json.Unmarshal(first.Body.Bytes(), &body)
+	if err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Data.CanonicalURL != "https://forum.example/topic/7" {
+		t.Errorf("canonicalUrl = %q, want %q", body.Data.CanonicalURL, "https://forum.example/topic/7")
+	}
+	if body.Data.CommentCount != 2 {
+		t.Errorf("commentCount = %d, want 2", body.Data.CommentCount)
+	}
+
+	t.Run("group: conditional get", func(t *testing.T) {
+		testCases := newConditionalGetTestCases(etag)
+		for _, tt := range testCases {
+			t.Run(tt.name, runConditionalGetTest(mux, tt))
+		}
+	})
+}
+
+type conditionalGetTestCase struct {
+	name        string
+	path        string
+	ifNoneMatch string
+	wantStatus  int
+}
+
+func newConditionalGetTestCases(etag string) []conditionalGetTestCase {
+	return []conditionalGetTestCase{
+		{name: "matching etag is not modified", path: "/api/v1/posts/7.json", ifNoneMatch: etag, wantStatus: http.StatusNotModified},
+		{name: "etag in a list is not modified", path: "/api/v1/posts/7.json", ifNoneMatch: `"stale", W/` + etag, wantStatus: http.StatusNotModified},
+		{name: "stale etag gets the full body", path: "/api/v1/posts/7.json", ifNoneMatch: `"stale"`, wantStatus: http.StatusOK},
+		{name: "removed topic is not found", path: "/api/v1/posts/8.json", wantStatus: http.StatusNotFound},
+		{name: "unknown topic is not found", path: "/api/v1/posts/9.json", wantStatus: http.StatusNotFound},
+		{name: "missing extension is not found", path: "/api/v1/posts/7", wantStatus: http.StatusNotFound},
+	}
+}
+
+func runConditionalGetTest(mux *http.ServeMux, tt conditionalGetTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		rec := serve(mux, tt.path, tt.ifNoneMatch)
+		if rec.Code != tt.wantStatus {
+			t.Fatalf("Permalink(%s) status = %d, want %d: %s", tt.path, rec.Code, tt.wantStatus, rec.Body.String())
+		}
+		if tt.wantStatus == http.StatusNotModified && rec.Body.Len() != 0 {
+			t.Errorf("304 response has a body: %q", rec.Body.String())
+		}
+		if tt.wantStatus == http.StatusNotFound && rec.Header().Get("Content-Type") != "application/json" {
+			t.Errorf("404 Content-Type = %q, want application/json", rec.Header().Get("Content-Type"))
+		}
+	}
+}
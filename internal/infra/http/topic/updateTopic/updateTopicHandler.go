@@ -2,6 +2,8 @@ package updatetopic
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/arnald/forum/internal/app"
@@ -14,11 +16,17 @@ import (
 )
 
 type RequestModel struct {
-	Title       string `json:"title"`
-	Content     string `json:"content"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
+	// Summary is an optional TL;DR; markup is stripped before it is stored.
+	Summary     string `json:"summary"`
 	ImagePath   string `json:"imagePath"`
 	CategoryIDs []int  `json:"categoryIds"`
-	TopicID     int    `json:"topicId"`
+	// CanonicalCategoryID is the primary category; 0 picks the first one.
+	CanonicalCategoryID int `json:"canonicalCategoryId"`
+	// IsQuestion is ignored unless question topics are enabled.
+	IsQuestion bool `json:"isQuestion"`
+	TopicID    int  `json:"topicId"`
 }
 
 type ResponseModel struct {
@@ -74,7 +82,7 @@ func (h *Handler) UpdateTopic(w http.ResponseWriter, r *http.Request) {
 
 	v := validator.New()
 
-	validator.ValidateCreateTopic(v, topicAny)
+	validator.ValidateCreateTopic(v, topicAny, h.Config.Limits)
 
 	if !v.Valid() {
 		helpers.RespondWithError(
@@ -88,13 +96,39 @@ func (h *Handler) UpdateTopic(w http.ResponseWriter, r *http.Request) {
 	}
 
 	topic, err := h.UserServices.UserServices.Commands.UpdateTopic.Handle(ctx, topicCommands.UpdateTopicRequest{
-		CategoryIDs: topicToUpdate.CategoryIDs,
-		TopicID:     topicToUpdate.TopicID,
-		Title:       topicToUpdate.Title,
-		Content:     topicToUpdate.Content,
-		ImagePath:   topicToUpdate.ImagePath,
-		User:        user,
+		CategoryIDs:         topicToUpdate.CategoryIDs,
+		CanonicalCategoryID: topicToUpdate.CanonicalCategoryID,
+		IsQuestion:          topicToUpdate.IsQuestion && h.Config.Topics.Questions,
+		TopicID:             topicToUpdate.TopicID,
+		Title:               topicToUpdate.Title,
+		Content:             topicToUpdate.Content,
+		Summary:             topicToUpdate.Summary,
+		ImagePath:           topicToUpdate.ImagePath,
+		SummaryMaxLength:    h.Config.Topics.SummaryMaxLength,
+		User:                user,
+		Bump: topicCommands.BumpPolicy{
+			Enabled:      h.Config.Topics.EditBumps,
+			MinEditChars: h.Config.Topics.MinBumpEditChars,
+		},
 	})
+	if errors.Is(err, topicCommands.ErrCanonicalNotSelected) {
+		helpers.RespondWithFieldErrors(w, http.StatusUnprocessableEntity, "Validation failed", map[string]string{
+			"canonicalCategoryId": "must be one of the selected categories",
+		})
+
+		h.Logger.PrintError(err, nil)
+
+		return
+	}
+	if errors.Is(err, topicCommands.ErrSummaryTooLong) {
+		helpers.RespondWithFieldErrors(w, http.StatusUnprocessableEntity, "Validation failed", map[string]string{
+			"summary": fmt.Sprintf("must be at most %d characters", h.Config.Topics.SummaryMaxLength),
+		})
+
+		h.Logger.PrintError(err, nil)
+
+		return
+	}
 	if err != nil {
 		helpers.RespondWithError(w,
 			http.StatusInternalServerError,
@@ -0,0 +1,119 @@
+package watchtopic
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/arnald/forum/internal/app"
+	topicCommands "github.com/arnald/forum/internal/app/topics/commands"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/middleware"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/topics"
+	"github.com/arnald/forum/internal/pkg/helpers"
+	"github.com/arnald/forum/internal/pkg/validator"
+)
+
+type ResponseModel struct {
+	Message  string `json:"message"`
+	TopicID  int    `json:"topicId"`
+	Watching bool   `json:"watching"`
+}
+
+type Handler struct {
+	UserServices app.Services
+	Config       *config.ServerConfig
+	Logger       logger.Logger
+}
+
+func NewHandler(userServices app.Services, config *config.ServerConfig, logger logger.Logger) *Handler {
+	return &Handler{
+		UserServices: userServices,
+		Config:       config,
+		Logger:       logger,
+	}
+}
+
+func (h *Handler) WatchTopic(w http.ResponseWriter, r *http.Request) {
+	h.setWatching(w, r, true)
+}
+
+func (h *Handler) UnwatchTopic(w http.ResponseWriter, r *http.Request) {
+	h.setWatching(w, r, false)
+}
+
+func (h *Handler) setWatching(w http.ResponseWriter, r *http.Request, watch bool) {
+	if r.Method != http.MethodPost {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	user := middleware.GetUserFromContext(r)
+	if user == nil {
+		h.Logger.PrintError(logger.ErrUserNotFoundInContext, nil)
+		helpers.RespondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserRegister)
+	defer cancel()
+
+	topicID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		helpers.RespondWithError(w, http.StatusBadRequest, "Invalid topic ID")
+		return
+	}
+
+	val := validator.New()
+	validator.ValidateGetTopic(val, &struct {
+		TopicID int
+	}{
+		TopicID: topicID,
+	})
+
+	if !val.Valid() {
+		h.Logger.PrintError(logger.ErrValidationFailed, val.Errors)
+		helpers.RespondWithError(w, http.StatusBadRequest, val.ToStringErrors())
+		return
+	}
+
+	err = h.UserServices.UserServices.Commands.WatchTopic.Handle(ctx, topicCommands.WatchTopicRequest{
+		UserID:  user.ID,
+		TopicID: topicID,
+		Watch:   watch,
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		if errors.Is(err, topics.ErrTopicNotFound) {
+			helpers.RespondWithError(w, http.StatusNotFound, "Topic not found")
+			return
+		}
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Error updating topic watch")
+		return
+	}
+
+	message := "Topic unwatched"
+	if watch {
+		message = "Topic watched"
+	}
+
+	helpers.RespondWithJSON(w,
+		http.StatusOK,
+		nil,
+		ResponseModel{
+			TopicID:  topicID,
+			Watching: watch,
+			Message:  message,
+		})
+
+	h.Logger.PrintInfo(
+		message,
+		map[string]string{
+			"topic_id": strconv.Itoa(topicID),
+			"user_id":  user.ID,
+		})
+}
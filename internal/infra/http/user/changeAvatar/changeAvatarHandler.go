@@ -0,0 +1,117 @@
+package changeavatar
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/arnald/forum/internal/app"
+	usercommands "github.com/arnald/forum/internal/app/user/commands"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/middleware"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/users"
+	"github.com/arnald/forum/internal/pkg/helpers"
+	"github.com/arnald/forum/internal/pkg/validator"
+)
+
+type RequestModel struct {
+	AvatarURL string `json:"avatarUrl"`
+}
+
+type ResponseModel struct {
+	Message           string `json:"message"`
+	AvatarURL         string `json:"avatarUrl"`
+	PreviousAvatarURL string `json:"previousAvatarUrl"`
+}
+
+type Handler struct {
+	UserServices app.Services
+	Config       *config.ServerConfig
+	Logger       logger.Logger
+}
+
+func NewHandler(config *config.ServerConfig, app app.Services, logger logger.Logger) *Handler {
+	return &Handler{
+		UserServices: app,
+		Config:       config,
+		Logger:       logger,
+	}
+}
+
+// ChangeAvatar sets the signed-in user's avatar to an image the client has
+// already stored under the avatar upload directory. The previous avatar URL
+// is returned so the client can remove the file it no longer needs.
+func (h *Handler) ChangeAvatar(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	user := middleware.GetUserFromContext(r)
+	if user == nil {
+		h.Logger.PrintError(logger.ErrUserNotFoundInContext, nil)
+		helpers.RespondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserRegister)
+	defer cancel()
+
+	var changeRequest RequestModel
+
+	requestAny, err := helpers.ParseBodyRequest(r, &changeRequest)
+	if err != nil {
+		helpers.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		h.Logger.PrintError(err, nil)
+		return
+	}
+	defer r.Body.Close()
+
+	v := validator.New()
+
+	validator.ValidateChangeAvatar(v, requestAny)
+
+	if !v.Valid() {
+		helpers.RespondWithFieldErrors(
+			w,
+			http.StatusBadRequest,
+			v.ToStringErrors(),
+			v.FieldErrors(requestAny),
+		)
+
+		h.Logger.PrintError(logger.ErrValidationFailed, v.Errors)
+		return
+	}
+
+	previous, err := h.UserServices.UserServices.Commands.ChangeAvatar.Handle(ctx, usercommands.ChangeAvatarRequest{
+		UserID:    user.ID,
+		AvatarURL: changeRequest.AvatarURL,
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		if errors.Is(err, usercommands.ErrAvatarNotOwned) || errors.Is(err, users.ErrAvatarInUse) {
+			helpers.RespondWithFieldErrors(w, http.StatusForbidden, err.Error(), map[string]string{
+				"avatarUrl": "must be an avatar you uploaded",
+			})
+			return
+		}
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to change avatar")
+		return
+	}
+
+	helpers.RespondWithJSON(w, http.StatusOK, nil, ResponseModel{
+		AvatarURL:         changeRequest.AvatarURL,
+		PreviousAvatarURL: previous,
+		Message:           "Avatar changed",
+	})
+
+	h.Logger.PrintInfo(
+		"Avatar changed",
+		map[string]string{
+			"userId":    user.ID,
+			"avatarUrl": changeRequest.AvatarURL,
+		},
+	)
+}
@@ -0,0 +1,144 @@
+package changepassword
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/arnald/forum/internal/app"
+	usercommands "github.com/arnald/forum/internal/app/user/commands"
+	userQueries "github.com/arnald/forum/internal/app/user/queries"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/domain/session"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/middleware"
+	"github.com/arnald/forum/internal/pkg/helpers"
+	"github.com/arnald/forum/internal/pkg/validator"
+)
+
+type RequestModel struct {
+	CurrentPassword string `json:"currentPassword"`
+	NewPassword     string `json:"newPassword"`
+	ConfirmPassword string `json:"confirmPassword"`
+}
+
+type ResponseModel struct {
+	Message string `json:"message"`
+}
+
+type Handler struct {
+	UserServices   app.Services
+	SessionManager session.Manager
+	Config         *config.ServerConfig
+	Logger         logger.Logger
+}
+
+func NewHandler(config *config.ServerConfig, app app.Services, sm session.Manager, logger logger.Logger) *Handler {
+	return &Handler{
+		UserServices:   app,
+		SessionManager: sm,
+		Config:         config,
+		Logger:         logger,
+	}
+}
+
+// ChangePassword sets a new password for the signed-in user once they have
+// confirmed their current one, and signs out every other session.
+func (h *Handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	user := middleware.GetUserFromContext(r)
+	currentSession := middleware.GetSessionFromContext(r)
+	if user == nil || currentSession == nil {
+		h.Logger.PrintError(logger.ErrUserNotFoundInContext, nil)
+		helpers.RespondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserLogin)
+	defer cancel()
+
+	var changeRequest RequestModel
+
+	requestAny, err := helpers.ParseBodyRequest(r, &changeRequest)
+	if err != nil {
+		helpers.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		h.Logger.PrintError(err, nil)
+		return
+	}
+	defer r.Body.Close()
+
+	v := validator.New()
+
+	validator.ValidateChangePassword(v, requestAny)
+	v.CheckPasswordStrength("NewPassword", changeRequest.NewPassword, h.Config.Passwords)
+	v.Check(changeRequest.NewPassword == changeRequest.ConfirmPassword, "ConfirmPassword", "passwords do not match")
+
+	if !v.Valid() {
+		helpers.RespondWithFieldErrors(
+			w,
+			http.StatusBadRequest,
+			v.ToStringErrors(),
+			v.FieldErrors(requestAny),
+		)
+
+		h.Logger.PrintError(logger.ErrValidationFailed, v.Errors)
+		return
+	}
+
+	err = h.UserServices.UserServices.Queries.VerifyPassword.Handle(ctx, userQueries.VerifyPasswordRequest{
+		User:     user,
+		Password: changeRequest.CurrentPassword,
+	})
+	switch {
+	case errors.Is(err, userQueries.ErrPasswordNotSet):
+		helpers.RespondWithError(w, http.StatusBadRequest,
+			"This account signs in through GitHub or Google and has no password to change")
+		return
+	case errors.Is(err, userQueries.ErrPasswordMismatch):
+		helpers.RespondWithFieldErrors(w, http.StatusBadRequest, err.Error(), map[string]string{
+			"currentPassword": err.Error(),
+		})
+		return
+	case errors.Is(err, userQueries.ErrAccountLocked):
+		helpers.RespondWithError(w, http.StatusTooManyRequests, err.Error())
+		h.Logger.PrintError(err, nil)
+		return
+	case err != nil:
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to verify password")
+		h.Logger.PrintError(err, nil)
+		return
+	}
+
+	err = h.UserServices.UserServices.Commands.ChangePassword.Handle(ctx, usercommands.ChangePasswordRequest{
+		UserID:   user.ID,
+		Password: changeRequest.NewPassword,
+	})
+	if err != nil {
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to change password")
+		h.Logger.PrintError(err, nil)
+		return
+	}
+
+	err = h.SessionManager.DeleteSessionWhenNewCreated(ctx, currentSession.AccessToken, user.ID)
+	if err != nil {
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Password changed but failed to sign out other sessions")
+		h.Logger.PrintError(err, nil)
+		return
+	}
+
+	helpers.RespondWithJSON(w, http.StatusOK, nil, ResponseModel{
+		Message: "Password changed, other sessions have been signed out",
+	})
+
+	h.Logger.PrintInfo(
+		"Password changed",
+		map[string]string{
+			"userId": user.ID,
+		},
+	)
+}
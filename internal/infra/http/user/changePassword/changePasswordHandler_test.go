@@ -0,0 +1,261 @@
+package changepassword
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/arnald/forum/internal/app"
+	usercommands "github.com/arnald/forum/internal/app/user/commands"
+	userQueries "github.com/arnald/forum/internal/app/user/queries"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/domain/session"
+	"github.com/arnald/forum/internal/domain/user"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/middleware"
+	"github.com/arnald/forum/internal/pkg/helpers"
+	testhelpers "github.com/arnald/forum/internal/pkg/testing"
+	"github.com/arnald/forum/internal/pkg/validator"
+)
+
+func TestHandler_ChangePassword(t *testing.T) {
+	t.Run("group: change password", func(t *testing.T) {
+		testCases := newChangePasswordHandlerTestCases()
+		for _, tt := range testCases {
+			t.Run(tt.name, runChangePasswordHandlerTest(tt))
+		}
+	})
+}
+
+type changePasswordHandlerTestCase struct {
+	wantFields      map[string]string
+	name            string
+	body            string
+	storedPassword  string
+	wantStatus      int
+	wantChanged     bool
+	wantOthersEnded bool
+}
+
+func newChangePasswordHandlerTestCases() []changePasswordHandlerTestCase {
+	return []changePasswordHandlerTestCase{
+		{
+			name:            "valid request changes the password and ends other sessions",
+			body:            `{"currentPassword":"OldPass1!","newPassword":"NewPass1!","confirmPassword":"NewPass1!"}`,
+			storedPassword:  "OldPass1!",
+			wantStatus:      http.StatusOK,
+			wantChanged:     true,
+			wantOthersEnded: true,
+		},
+		{
+			name:           "wrong current password",
+			body:           `{"currentPassword":"Wrong1!x","newPassword":"NewPass1!","confirmPassword":"NewPass1!"}`,
+			storedPassword: "OldPass1!",
+			wantStatus:     http.StatusBadRequest,
+			wantFields:     map[string]string{"currentPassword": userQueries.ErrPasswordMismatch.Error()},
+		},
+		{
+			name:           "confirmation does not match",
+			body:           `{"currentPassword":"OldPass1!","newPassword":"NewPass1!","confirmPassword":"NewPass2!"}`,
+			storedPassword: "OldPass1!",
+			wantStatus:     http.StatusBadRequest,
+			wantFields:     map[string]string{"confirmPassword": "passwords do not match"},
+		},
+		{
+			name:           "new password too weak",
+			body:           `{"currentPassword":"OldPass1!","newPassword":"short","confirmPassword":"short"}`,
+			storedPassword: "OldPass1!",
+			wantStatus:     http.StatusBadRequest,
+			wantFields:     map[string]string{"newPassword": "must be at least 8 characters and include an uppercase letter, a digit and a special character"},
+		},
+		{
+			name:       "oauth account has no password to change",
+			body:       `{"currentPassword":"OldPass1!","newPassword":"NewPass1!","confirmPassword":"NewPass1!"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+}
+
+func runChangePasswordHandlerTest(tt changePasswordHandlerTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		var changed, othersEnded bool
+		repo := &testhelpers.MockRepository{
+			UpdatePasswordFunc: func(_ context.Context, userID, passwordHash string) error {
+				changed = userID == "test-user-id" && passwordHash == "hashed:NewPass1!"
+				return nil
+			},
+		}
+		enc := &testhelpers.MockEncryptionProvider{
+			GenerateFunc: func(password string) (string, error) { return "hashed:" + password, nil },
+			MatchesFunc: func(hashedPassword, plaintextPassword string) error {
+				if hashedPassword != plaintextPassword {
+					return testhelpers.ErrTest
+				}
+				return nil
+			},
+		}
+		sessions := &testhelpers.MockSessionManager{
+			GetSessionFromSessionTokensFunc: func(_, _ string) (*session.Session, error) {
+				return &session.Session{
+					AccessToken:        "token",
+					Expiry:             time.Now().Add(time.Hour),
+					RefreshTokenExpiry: time.Now().Add(time.Hour),
+				}, nil
+			},
+			GetUserFromSessionFunc: func(_ string) (*user.User, error) {
+				return &user.User{ID: "test-user-id", Password: tt.storedPassword}, nil
+			},
+			DeleteSessionWhenNewCreatedFunc: func(_ context.Context, sessionID, userID string) error {
+				othersEnded = sessionID == "token" && userID == "test-user-id"
+				return nil
+			},
+		}
+
+		services := app.Services{
+			UserServices: app.UserServices{
+				Queries: app.Queries{
+					VerifyPassword: userQueries.NewVerifyPasswordHandler(repo, enc),
+				},
+				Commands: app.Commands{
+					ChangePassword: usercommands.NewChangePasswordHandler(repo, enc),
+				},
+			},
+		}
+		cfg := &config.ServerConfig{
+			Timeouts: config.TimeoutsConfig{
+				HandlerTimeouts: config.HandlerTimeoutsConfig{UserLogin: time.Second},
+			},
+			Passwords: validator.PasswordPolicy{MinLength: 8, RequireUpper: true, RequireDigit: true, RequireSpecial: true},
+		}
+		handler := NewHandler(cfg, services, sessions, logger.New(io.Discard, logger.LevelOff))
+		authorized := middleware.NewAuthorizationMiddleware(sessions, time.Minute).Required(handler.ChangePassword)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/password/change", bytes.NewBufferString(tt.body))
+		rec := httptest.NewRecorder()
+
+		authorized(rec, req)
+
+		if rec.Code != tt.wantStatus {
+			t.Fatalf("ChangePassword() status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+		}
+		if changed != tt.wantChanged {
+			t.Errorf("ChangePassword() stored new password = %v, want %v", changed, tt.wantChanged)
+		}
+		if othersEnded != tt.wantOthersEnded {
+			t.Errorf("ChangePassword() ended other sessions = %v, want %v", othersEnded, tt.wantOthersEnded)
+		}
+		if tt.wantFields == nil {
+			return
+		}
+
+		var got helpers.FieldErrorsResponse
+		err := json.NewDecoder(rec.Body).Decode(&got)
+		if err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+
+		if len(got.Fields) != len(tt.wantFields) {
+			t.Fatalf("ChangePassword() fields = %v, want %v", got.Fields, tt.wantFields)
+		}
+		for field, want := range tt.wantFields {
+			if got.Fields[field] != want {
+				t.Errorf("ChangePassword() fields[%q] = %q, want %q", field, got.Fields[field], want)
+			}
+		}
+	}
+}
+
+func TestHandler_ChangePassword_Lockout(t *testing.T) {
+	var changed bool
+	attempts := 0
+	var lockedUntil time.Time
+	repo := &testhelpers.MockRepository{
+		UpdatePasswordFunc: func(_ context.Context, _, _ string) error {
+			changed = true
+			return nil
+		},
+		GetLoginLockoutFunc: func(_ context.Context, _ string) (time.Time, error) {
+			return lockedUntil, nil
+		},
+		RecordFailedLoginFunc: func(_ context.Context, _ string, maxAttempts int, lockFor time.Duration) (time.Time, error) {
+			attempts++
+			if attempts >= maxAttempts {
+				lockedUntil = time.Now().Add(lockFor)
+			}
+			return lockedUntil, nil
+		},
+	}
+	enc := &testhelpers.MockEncryptionProvider{
+		GenerateFunc: func(password string) (string, error) { return "hashed:" + password, nil },
+		MatchesFunc: func(hashedPassword, plaintextPassword string) error {
+			if hashedPassword != plaintextPassword {
+				return testhelpers.ErrTest
+			}
+			return nil
+		},
+	}
+	sessions := &testhelpers.MockSessionManager{
+		GetSessionFromSessionTokensFunc: func(_, _ string) (*session.Session, error) {
+			return &session.Session{
+				AccessToken:        "token",
+				Expiry:             time.Now().Add(time.Hour),
+				RefreshTokenExpiry: time.Now().Add(time.Hour),
+			}, nil
+		},
+		GetUserFromSessionFunc: func(_ string) (*user.User, error) {
+			return &user.User{ID: "test-user-id", Password: "OldPass1!"}, nil
+		},
+	}
+	services := app.Services{
+		UserServices: app.UserServices{
+			Queries: app.Queries{
+				VerifyPassword: userQueries.NewVerifyPasswordHandler(repo, enc),
+			},
+			Commands: app.Commands{
+				ChangePassword: usercommands.NewChangePasswordHandler(repo, enc),
+			},
+		},
+	}
+	cfg := &config.ServerConfig{
+		Timeouts: config.TimeoutsConfig{
+			HandlerTimeouts: config.HandlerTimeoutsConfig{UserLogin: time.Second},
+		},
+		Passwords: validator.PasswordPolicy{MinLength: 8},
+	}
+	handler := NewHandler(cfg, services, sessions, logger.New(io.Discard, logger.LevelOff))
+	authorized := middleware.NewAuthorizationMiddleware(sessions, time.Minute).Required(handler.ChangePassword)
+
+	post := func(current string) int {
+		body := `{"currentPassword":"` + current + `","newPassword":"NewPass1!","confirmPassword":"NewPass1!"}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/password/change", bytes.NewBufferString(body))
+		rec := httptest.NewRecorder()
+		authorized(rec, req)
+		return rec.Code
+	}
+
+	// Keep guessing until the lockout kicks in; it must happen within a
+	// handful of attempts.
+	status := http.StatusBadRequest
+	for range 10 {
+		status = post("Wrong1!x")
+		if status != http.StatusBadRequest {
+			break
+		}
+	}
+	if status != http.StatusTooManyRequests {
+		t.Fatalf("ChangePassword() after repeated wrong passwords status = %d, want %d", status, http.StatusTooManyRequests)
+	}
+
+	status = post("OldPass1!")
+	if status != http.StatusTooManyRequests {
+		t.Errorf("ChangePassword() with the right password while locked status = %d, want %d", status, http.StatusTooManyRequests)
+	}
+	if changed {
+		t.Error("ChangePassword() changed the password of a locked account")
+	}
+}
@@ -0,0 +1,115 @@
+package changeusername
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/arnald/forum/internal/app"
+	usercommands "github.com/arnald/forum/internal/app/user/commands"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/middleware"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/users"
+	"github.com/arnald/forum/internal/pkg/helpers"
+	"github.com/arnald/forum/internal/pkg/validator"
+)
+
+type RequestModel struct {
+	Username string `json:"username"`
+}
+
+type ResponseModel struct {
+	Message  string `json:"message"`
+	Username string `json:"username"`
+}
+
+type Handler struct {
+	UserServices app.Services
+	Config       *config.ServerConfig
+	Logger       logger.Logger
+}
+
+func NewHandler(config *config.ServerConfig, app app.Services, logger logger.Logger) *Handler {
+	return &Handler{
+		UserServices: app,
+		Config:       config,
+		Logger:       logger,
+	}
+}
+
+// ChangeUsername renames the signed-in user. The new name follows the same
+// rules as at registration and must not belong to another account.
+func (h *Handler) ChangeUsername(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	user := middleware.GetUserFromContext(r)
+	if user == nil {
+		h.Logger.PrintError(logger.ErrUserNotFoundInContext, nil)
+		helpers.RespondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserRegister)
+	defer cancel()
+
+	var changeRequest RequestModel
+
+	requestAny, err := helpers.ParseBodyRequest(r, &changeRequest)
+	if err != nil {
+		helpers.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		h.Logger.PrintError(err, nil)
+		return
+	}
+	defer r.Body.Close()
+
+	v := validator.New()
+
+	validator.ValidateChangeUsername(v, requestAny)
+
+	if !v.Valid() {
+		helpers.RespondWithFieldErrors(
+			w,
+			http.StatusBadRequest,
+			v.ToStringErrors(),
+			v.FieldErrors(requestAny),
+		)
+
+		h.Logger.PrintError(logger.ErrValidationFailed, v.Errors)
+		return
+	}
+
+	err = h.UserServices.UserServices.Commands.ChangeUsername.Handle(ctx, usercommands.ChangeUsernameRequest{
+		UserID:   user.ID,
+		Username: changeRequest.Username,
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		if errors.Is(err, users.ErrDuplicateUsername) {
+			helpers.RespondWithFieldErrors(w, http.StatusConflict, err.Error(), map[string]string{
+				"username": "username is already taken",
+			})
+			return
+		}
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to change username")
+		return
+	}
+
+	helpers.RespondWithJSON(w, http.StatusOK, nil, ResponseModel{
+		Username: changeRequest.Username,
+		Message:  "Username changed",
+	})
+
+	h.Logger.PrintInfo(
+		"Username changed",
+		map[string]string{
+			"userId":   user.ID,
+			"oldName":  user.Username,
+			"username": changeRequest.Username,
+		},
+	)
+}
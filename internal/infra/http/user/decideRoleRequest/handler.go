@@ -0,0 +1,165 @@
+package deciderolerequest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/arnald/forum/internal/app"
+	roleRequestCommands "github.com/arnald/forum/internal/app/rolerequests/commands"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/domain/notification"
+	"github.com/arnald/forum/internal/domain/rolerequest"
+	"github.com/arnald/forum/internal/domain/user"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/middleware"
+	"github.com/arnald/forum/internal/infra/storage/notifications"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/rolerequests"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/users"
+	"github.com/arnald/forum/internal/pkg/helpers"
+	"github.com/arnald/forum/internal/pkg/validator"
+)
+
+type ResponseModel struct {
+	Message   string `json:"message"`
+	Status    string `json:"status"`
+	Role      string `json:"role"`
+	RequestID int    `json:"requestId"`
+}
+
+type Handler struct {
+	UserServices app.Services
+	Config       *config.ServerConfig
+	Logger       logger.Logger
+	Notification *notifications.NotificationService
+}
+
+func NewHandler(userServices app.Services, config *config.ServerConfig, logger logger.Logger, notifications *notifications.NotificationService) *Handler {
+	return &Handler{
+		UserServices: userServices,
+		Config:       config,
+		Logger:       logger,
+		Notification: notifications,
+	}
+}
+
+func (h *Handler) ApproveRoleRequest(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, rolerequest.StatusApproved)
+}
+
+func (h *Handler) RejectRoleRequest(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, rolerequest.StatusRejected)
+}
+
+// decide settles the role request named in the path. The admin may explain
+// the decision in the admin_notes form value, which the requester sees.
+func (h *Handler) decide(w http.ResponseWriter, r *http.Request, decision string) {
+	if r.Method != http.MethodPost {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	admin := middleware.GetUserFromContext(r)
+	if admin == nil {
+		h.Logger.PrintError(logger.ErrUserNotFoundInContext, nil)
+		helpers.RespondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserRegister)
+	defer cancel()
+
+	requestID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		helpers.RespondWithError(w, http.StatusBadRequest, "Invalid role request ID")
+		return
+	}
+
+	decisionModel := &struct {
+		AdminNotes string
+		RequestID  int
+	}{
+		AdminNotes: strings.TrimSpace(r.FormValue("admin_notes")),
+		RequestID:  requestID,
+	}
+
+	val := validator.New()
+	validator.ValidateDecideRoleRequest(val, decisionModel)
+	if !val.Valid() {
+		h.Logger.PrintError(logger.ErrValidationFailed, val.Errors)
+		helpers.RespondWithError(w, http.StatusBadRequest, val.ToStringErrors())
+		return
+	}
+
+	decided, err := h.UserServices.UserServices.Commands.DecideRole.Handle(ctx, roleRequestCommands.DecideRoleRequestRequest{
+		Admin:      admin,
+		Decision:   decision,
+		AdminNotes: decisionModel.AdminNotes,
+		RequestID:  requestID,
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		switch {
+		case errors.Is(err, roleRequestCommands.ErrNotAdmin):
+			helpers.RespondWithError(w, http.StatusForbidden, "Admin access required")
+		case errors.Is(err, rolerequests.ErrRoleRequestNotFound):
+			helpers.RespondWithError(w, http.StatusNotFound, "Role request not found")
+		case errors.Is(err, users.ErrUserNotFound):
+			helpers.RespondWithError(w, http.StatusNotFound, "Requesting user no longer exists")
+		case errors.Is(err, roleRequestCommands.ErrRequestNotPending),
+			errors.Is(err, rolerequests.ErrRoleRequestNotPending):
+			helpers.RespondWithError(w, http.StatusConflict, "Role request has already been decided")
+		default:
+			helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to decide role request")
+		}
+		return
+	}
+
+	h.notifyRequester(ctx, admin, decided)
+
+	helpers.RespondWithJSON(w, http.StatusOK, nil, ResponseModel{
+		RequestID: decided.ID,
+		Status:    decided.Status,
+		Role:      decided.RequestedRole,
+		Message:   "Role request " + decided.Status,
+	})
+
+	h.Logger.PrintInfo(
+		"Role request decided",
+		map[string]string{
+			"admin_id":   admin.ID,
+			"user_id":    decided.UserID,
+			"request_id": strconv.Itoa(decided.ID),
+			"status":     decided.Status,
+		},
+	)
+}
+
+func (h *Handler) notifyRequester(ctx context.Context, admin *user.User, decided *rolerequest.RoleRequest) {
+	notification := &notification.Notification{
+		ActorID: admin.Username,
+		UserID:  decided.UserID,
+		Type:    notification.NotificationTypeRoleRequest,
+		Title:   "Role request " + decided.Status,
+		Message: decisionMessage(decided, admin.Username),
+	}
+
+	err := h.Notification.CreateNotification(ctx, notification)
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+	}
+}
+
+// decisionMessage tells the requester how their request went, with the
+// admin's notes when there are any.
+func decisionMessage(decided *rolerequest.RoleRequest, adminName string) string {
+	message := fmt.Sprintf("Your request to become a %s was %s by %s", decided.RequestedRole, decided.Status, adminName)
+	if decided.AdminNotes != "" {
+		message += ". Notes: " + decided.AdminNotes
+	}
+	return message
+}
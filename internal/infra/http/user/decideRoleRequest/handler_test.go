@@ -0,0 +1,39 @@
+package deciderolerequest
+
+import (
+	"testing"
+
+	"github.com/arnald/forum/internal/domain/rolerequest"
+	"github.com/arnald/forum/internal/domain/user"
+)
+
+func TestDecisionMessage(t *testing.T) {
+	testCases := []struct {
+		name    string
+		decided rolerequest.RoleRequest
+		want    string
+	}{
+		{
+			name:    "approval",
+			decided: rolerequest.RoleRequest{RequestedRole: user.RoleModerator, Status: rolerequest.StatusApproved},
+			want:    "Your request to become a moderator was approved by root",
+		},
+		{
+			name: "rejection with notes",
+			decided: rolerequest.RoleRequest{
+				RequestedRole: user.RoleModerator,
+				Status:        rolerequest.StatusRejected,
+				AdminNotes:    "Try again in a few months",
+			},
+			want: "Your request to become a moderator was rejected by root. Notes: Try again in a few months",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decisionMessage(&tt.decided, "root"); got != tt.want {
+				t.Errorf("decisionMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package forgotpassword
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/arnald/forum/internal/app"
+	usercommands "github.com/arnald/forum/internal/app/user/commands"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/pkg/helpers"
+	"github.com/arnald/forum/internal/pkg/validator"
+)
+
+type RequestModel struct {
+	Email string `json:"email"`
+}
+
+type ResponseModel struct {
+	Message string `json:"message"`
+}
+
+type Handler struct {
+	UserServices app.Services
+	Config       *config.ServerConfig
+	Logger       logger.Logger
+}
+
+func NewHandler(config *config.ServerConfig, app app.Services, logger logger.Logger) *Handler {
+	return &Handler{
+		UserServices: app,
+		Config:       config,
+		Logger:       logger,
+	}
+}
+
+// ForgotPassword issues a one-hour reset link for the account behind the
+// email. The reply is the same whether or not the account exists.
+func (h *Handler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserLogin)
+	defer cancel()
+
+	var forgotRequest RequestModel
+
+	requestAny, err := helpers.ParseBodyRequest(r, &forgotRequest)
+	if err != nil {
+		helpers.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		h.Logger.PrintError(err, nil)
+		return
+	}
+	defer r.Body.Close()
+
+	v := validator.New()
+
+	validator.ValidateForgotPassword(v, requestAny)
+
+	if !v.Valid() {
+		helpers.RespondWithFieldErrors(
+			w,
+			http.StatusBadRequest,
+			v.ToStringErrors(),
+			v.FieldErrors(requestAny),
+		)
+
+		h.Logger.PrintError(logger.ErrValidationFailed, v.Errors)
+		return
+	}
+
+	email := helpers.NormalizeEmail(forgotRequest.Email, h.Config.Accounts.StripEmailAliases)
+
+	token, err := h.UserServices.UserServices.Commands.ForgotPassword.Handle(ctx, usercommands.ForgotPasswordRequest{
+		Email: email,
+	})
+	if err != nil {
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to start password reset")
+		h.Logger.PrintError(err, nil)
+		return
+	}
+
+	if token != "" {
+		// There is no mail transport yet, so the link goes to the server log
+		// for the operator to pass on.
+		h.Logger.PrintInfo(
+			"Password reset link issued",
+			map[string]string{
+				"email":    email,
+				"resetUrl": h.Config.Topics.PublicURL + "/reset-password?token=" + url.QueryEscape(token),
+			},
+		)
+	}
+
+	helpers.RespondWithJSON(w, http.StatusAccepted, nil, ResponseModel{
+		Message: "If an account uses that email, a reset link is on its way",
+	})
+}
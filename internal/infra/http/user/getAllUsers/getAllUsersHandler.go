@@ -0,0 +1,81 @@
+package getallusers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/arnald/forum/internal/app"
+	userqueries "github.com/arnald/forum/internal/app/user/queries"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/pkg/helpers"
+)
+
+type ResponseModel struct {
+	Pagination map[string]interface{}          `json:"pagination"`
+	Users      []userqueries.GetAllUsersResult `json:"users"`
+}
+
+type Handler struct {
+	UserServices app.Services
+	Config       *config.ServerConfig
+	Logger       logger.Logger
+}
+
+func NewHandler(userServices app.Services, config *config.ServerConfig, logger logger.Logger) *Handler {
+	return &Handler{
+		UserServices: userServices,
+		Config:       config,
+		Logger:       logger,
+	}
+}
+
+// GetAllUsers lists the registered users, oldest account first, a page at a
+// time. The route is guarded by RequireAdmin.
+func (h *Handler) GetAllUsers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserRegister)
+	defer cancel()
+
+	pagination := helpers.GetPagination(r)
+
+	users, total, err := h.UserServices.UserServices.Queries.GetAllUsers.Handle(ctx, userqueries.GetAllUsersRequest{
+		Limit:  pagination.Limit,
+		Offset: pagination.Offset,
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to get users")
+		return
+	}
+
+	totalPages := (total + pagination.Limit - 1) / pagination.Limit
+
+	paginationMeta := map[string]interface{}{
+		"page":       pagination.Page,
+		"limit":      pagination.Limit,
+		"totalPages": totalPages,
+		"totalItems": total,
+		"has_next":   pagination.Page < totalPages,
+		"has_prev":   pagination.Page > 1,
+		"next_page":  nil,
+		"prev_page":  nil,
+	}
+
+	if pagination.Page < totalPages {
+		paginationMeta["next_page"] = pagination.Page + 1
+	}
+	if pagination.Page > 1 {
+		paginationMeta["prev_page"] = pagination.Page - 1
+	}
+
+	helpers.RespondWithJSON(w, http.StatusOK, nil, ResponseModel{
+		Users:      users,
+		Pagination: paginationMeta,
+	})
+}
@@ -22,6 +22,9 @@ type Response struct {
 	ID       string `json:"id"`
 	Username string `json:"username"`
 	Email    string `json:"email"`
+	// AvatarURL is empty for a user without an avatar.
+	AvatarURL string `json:"avatarUrl"`
+	Role      string `json:"role"`
 }
 
 // GetMe handler retrieves the current user from the session in the context.
@@ -43,6 +46,10 @@ func (h *Handler) GetMe(w http.ResponseWriter, r *http.Request) {
 		ID:       user.ID,
 		Username: user.Username,
 		Email:    user.Email,
+		Role:     user.Role,
+	}
+	if user.AvatarURL != nil {
+		response.AvatarURL = *user.AvatarURL
 	}
 
 	helpers.RespondWithJSON(w, http.StatusOK, nil, response)
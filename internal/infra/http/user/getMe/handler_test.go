@@ -0,0 +1,110 @@
+package getme
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/arnald/forum/internal/domain/session"
+	"github.com/arnald/forum/internal/domain/user"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/middleware"
+	testhelpers "github.com/arnald/forum/internal/pkg/testing"
+)
+
+func TestHandler_GetMe(t *testing.T) {
+	avatar := "/static/images/uploads/avatars/alice.png"
+	alice := &user.User{
+		ID:        "alice-id",
+		Username:  "alice",
+		Email:     "alice@example.com",
+		AvatarURL: &avatar,
+		Role:      user.RoleModerator,
+	}
+
+	testCases := []struct {
+		signedIn   *user.User
+		name       string
+		wantStatus int
+		want       Response
+	}{
+		{
+			name:       "signed-in user gets their profile",
+			signedIn:   alice,
+			wantStatus: http.StatusOK,
+			want: Response{
+				ID:        "alice-id",
+				Username:  "alice",
+				Email:     "alice@example.com",
+				AvatarURL: avatar,
+				Role:      user.RoleModerator,
+			},
+		},
+		{
+			name:       "user without an avatar gets an empty avatar URL",
+			signedIn:   &user.User{ID: "bob-id", Username: "bob", Email: "bob@example.com", Role: user.RoleUser},
+			wantStatus: http.StatusOK,
+			want:       Response{ID: "bob-id", Username: "bob", Email: "bob@example.com", Role: user.RoleUser},
+		},
+		{
+			name:       "request without a session is refused",
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			sessions := &testhelpers.MockSessionManager{}
+			if tt.signedIn != nil {
+				sessions.GetSessionFromSessionTokensFunc = func(_, _ string) (*session.Session, error) {
+					return &session.Session{
+						AccessToken:        "token",
+						Expiry:             time.Now().Add(time.Hour),
+						RefreshTokenExpiry: time.Now().Add(time.Hour),
+					}, nil
+				}
+				sessions.GetUserFromSessionFunc = func(_ string) (*user.User, error) {
+					return tt.signedIn, nil
+				}
+			}
+
+			handler := NewHandler(logger.New(io.Discard, logger.LevelOff))
+			authorized := middleware.NewAuthorizationMiddleware(sessions, time.Minute).Required(handler.GetMe)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/me", nil)
+			rec := httptest.NewRecorder()
+
+			authorized(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("GetMe() status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var got struct {
+				Data Response `json:"data"`
+			}
+			err := json.NewDecoder(rec.Body).Decode(&got)
+			if err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if got.Data != tt.want {
+				t.Errorf("GetMe() = %+v, want %+v", got.Data, tt.want)
+			}
+		})
+	}
+
+	t.Run("handler refuses a request the middleware did not vouch for", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		NewHandler(logger.New(io.Discard, logger.LevelOff)).GetMe(rec, httptest.NewRequest(http.MethodGet, "/api/v1/me", nil))
+
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("GetMe() status = %d, want %d", rec.Code, http.StatusUnauthorized)
+		}
+	})
+}
@@ -2,6 +2,7 @@ package userlogin
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	userQueries "github.com/arnald/forum/internal/app/user/queries"
@@ -13,6 +14,7 @@ import (
 type LoginUserEmailRequestModel struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	Remember bool   `json:"remember"`
 }
 
 func (h Handler) UserLoginEmail(w http.ResponseWriter, r *http.Request) {
@@ -58,9 +60,18 @@ func (h Handler) UserLoginEmail(w http.ResponseWriter, r *http.Request) {
 	}
 
 	user, err := h.UserServices.UserServices.Queries.UserLoginEmail.Handle(ctx, userQueries.UserLoginEmailRequest{
-		Email:    userToLogin.Email,
+		Email:    helpers.NormalizeEmail(userToLogin.Email, h.Config.Accounts.StripEmailAliases),
 		Password: userToLogin.Password,
 	})
+	if errors.Is(err, userQueries.ErrAccountLocked) {
+		helpers.RespondWithError(w,
+			http.StatusTooManyRequests,
+			err.Error(),
+		)
+
+		h.Logger.PrintError(err, nil)
+		return
+	}
 	if err != nil {
 		helpers.RespondWithError(w,
 			http.StatusInternalServerError,
@@ -72,7 +83,20 @@ func (h Handler) UserLoginEmail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	newSession, err := h.SessionManager.CreateSession(ctx, user.ID)
+	newSession, err := h.SessionManager.CreateSessionWithTTL(ctx, user.ID, h.sessionTTL(userToLogin.Remember))
+	if err != nil {
+		helpers.RespondWithError(
+			w,
+			http.StatusInternalServerError,
+			"error creating session",
+		)
+
+		h.Logger.PrintError(err, nil)
+
+		return
+	}
+
+	err = h.SessionManager.ConfirmAuthentication(ctx, newSession.AccessToken)
 	if err != nil {
 		helpers.RespondWithError(
 			w,
@@ -86,10 +110,12 @@ func (h Handler) UserLoginEmail(w http.ResponseWriter, r *http.Request) {
 	}
 
 	loginResponse := LoginResponse{
-		UserID:       user.ID,
-		Username:     user.Username,
-		AccessToken:  newSession.AccessToken,
-		RefreshToken: newSession.RefreshToken,
+		UserID:           user.ID,
+		Username:         user.Username,
+		AccessToken:      newSession.AccessToken,
+		RefreshToken:     newSession.RefreshToken,
+		ExpiresAt:        newSession.Expiry,
+		RefreshExpiresAt: newSession.RefreshTokenExpiry,
 	}
 
 	helpers.RespondWithJSON(
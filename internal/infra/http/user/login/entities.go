@@ -1,6 +1,8 @@
 package userlogin
 
 import (
+	"time"
+
 	"github.com/arnald/forum/internal/app"
 	"github.com/arnald/forum/internal/config"
 	"github.com/arnald/forum/internal/domain/session"
@@ -22,3 +24,12 @@ func NewHandler(config *config.ServerConfig, app app.Services, sm session.Manage
 		Logger:         logger,
 	}
 }
+
+// sessionTTL picks how long a new login session lasts: the long "remember me"
+// expiry when the user asked for it, the default otherwise.
+func (h Handler) sessionTTL(remember bool) time.Duration {
+	if remember {
+		return h.Config.SessionManager.RememberMeExpiry
+	}
+	return h.Config.SessionManager.DefaultExpiry
+}
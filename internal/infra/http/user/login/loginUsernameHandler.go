@@ -2,7 +2,9 @@ package userlogin
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"time"
 
 	userQueries "github.com/arnald/forum/internal/app/user/queries"
 	"github.com/arnald/forum/internal/infra/logger"
@@ -13,13 +15,16 @@ import (
 type LoginUserUsernameRequestModel struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
+	Remember bool   `json:"remember"`
 }
 
 type LoginResponse struct {
-	UserID       string `json:"userId"`
-	Username     string `json:"username"`
-	AccessToken  string `json:"accessToken"`
-	RefreshToken string `json:"refreshToken"`
+	ExpiresAt        time.Time `json:"expiresAt"`
+	RefreshExpiresAt time.Time `json:"refreshExpiresAt"`
+	UserID           string    `json:"userId"`
+	Username         string    `json:"username"`
+	AccessToken      string    `json:"accessToken"`
+	RefreshToken     string    `json:"refreshToken"`
 }
 
 func (h Handler) UserLoginUsername(w http.ResponseWriter, r *http.Request) {
@@ -68,6 +73,15 @@ func (h Handler) UserLoginUsername(w http.ResponseWriter, r *http.Request) {
 		Username: userToLogin.Username,
 		Password: userToLogin.Password,
 	})
+	if errors.Is(err, userQueries.ErrAccountLocked) {
+		helpers.RespondWithError(w,
+			http.StatusTooManyRequests,
+			err.Error(),
+		)
+
+		h.Logger.PrintError(err, nil)
+		return
+	}
 	if err != nil {
 		helpers.RespondWithError(w,
 			http.StatusInternalServerError,
@@ -78,7 +92,19 @@ func (h Handler) UserLoginUsername(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	newSession, err := h.SessionManager.CreateSession(ctx, user.ID)
+	newSession, err := h.SessionManager.CreateSessionWithTTL(ctx, user.ID, h.sessionTTL(userToLogin.Remember))
+	if err != nil {
+		helpers.RespondWithError(
+			w,
+			http.StatusInternalServerError,
+			"error creating session",
+		)
+
+		h.Logger.PrintError(err, nil)
+		return
+	}
+
+	err = h.SessionManager.ConfirmAuthentication(ctx, newSession.AccessToken)
 	if err != nil {
 		helpers.RespondWithError(
 			w,
@@ -91,10 +117,12 @@ func (h Handler) UserLoginUsername(w http.ResponseWriter, r *http.Request) {
 	}
 
 	loginResponse := LoginResponse{
-		UserID:       user.ID,
-		Username:     user.Username,
-		AccessToken:  newSession.AccessToken,
-		RefreshToken: newSession.RefreshToken,
+		UserID:           user.ID,
+		Username:         user.Username,
+		AccessToken:      newSession.AccessToken,
+		RefreshToken:     newSession.RefreshToken,
+		ExpiresAt:        newSession.Expiry,
+		RefreshExpiresAt: newSession.RefreshTokenExpiry,
 	}
 
 	helpers.RespondWithJSON(
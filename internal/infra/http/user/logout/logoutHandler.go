@@ -57,3 +57,35 @@ func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
 		"message": "Logged out successfully",
 	})
 }
+
+// LogoutEverywhere deletes every session the user has, signing them out on
+// all of their devices including the one making the request.
+func (h *Handler) LogoutEverywhere(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	user := middleware.GetUserFromContext(r)
+	if user == nil {
+		helpers.RespondWithError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	err := h.sessionManager.InvalidateUserSessions(r.Context(), user.ID)
+	if err != nil {
+		h.logger.PrintError(err, nil)
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to logout")
+		return
+	}
+
+	h.logger.PrintInfo("User logged out everywhere", map[string]string{
+		"userId": user.ID,
+		"name":   user.Username,
+	})
+
+	helpers.RespondWithJSON(w, http.StatusOK, nil, map[string]string{
+		"message": "Logged out of all sessions",
+	})
+}
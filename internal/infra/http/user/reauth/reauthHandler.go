@@ -0,0 +1,112 @@
+package userreauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/arnald/forum/internal/app"
+	userQueries "github.com/arnald/forum/internal/app/user/queries"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/domain/session"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/middleware"
+	"github.com/arnald/forum/internal/pkg/helpers"
+)
+
+type RequestModel struct {
+	Password string `json:"password"`
+}
+
+type ResponseModel struct {
+	Message string `json:"message"`
+}
+
+type Handler struct {
+	UserServices   app.Services
+	SessionManager session.Manager
+	Config         *config.ServerConfig
+	Logger         logger.Logger
+}
+
+func NewHandler(config *config.ServerConfig, app app.Services, sm session.Manager, logger logger.Logger) *Handler {
+	return &Handler{
+		UserServices:   app,
+		SessionManager: sm,
+		Config:         config,
+		Logger:         logger,
+	}
+}
+
+// Reauth lets a signed-in user re-enter their password so that actions behind
+// RequireRecentAuth are unlocked for the configured window.
+func (h *Handler) Reauth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	user := middleware.GetUserFromContext(r)
+	currentSession := middleware.GetSessionFromContext(r)
+	if user == nil || currentSession == nil {
+		h.Logger.PrintError(logger.ErrUserNotFoundInContext, nil)
+		helpers.RespondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserLogin)
+	defer cancel()
+
+	var reauthRequest RequestModel
+
+	_, err := helpers.ParseBodyRequest(r, &reauthRequest)
+	if err != nil {
+		helpers.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		h.Logger.PrintError(err, nil)
+		return
+	}
+	defer r.Body.Close()
+
+	err = h.UserServices.UserServices.Queries.VerifyPassword.Handle(ctx, userQueries.VerifyPasswordRequest{
+		User:     user,
+		Password: reauthRequest.Password,
+	})
+	switch {
+	case errors.Is(err, userQueries.ErrPasswordNotSet):
+		helpers.RespondWithJSON(w, http.StatusBadRequest, nil, middleware.ReauthRequiredResponse{
+			Error:  err.Error(),
+			Reauth: middleware.ReauthMethodOAuth,
+		})
+		return
+	case errors.Is(err, userQueries.ErrPasswordMismatch):
+		helpers.RespondWithError(w, http.StatusUnauthorized, err.Error())
+		return
+	case errors.Is(err, userQueries.ErrAccountLocked):
+		helpers.RespondWithError(w, http.StatusTooManyRequests, err.Error())
+		h.Logger.PrintError(err, nil)
+		return
+	case err != nil:
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to verify password")
+		h.Logger.PrintError(err, nil)
+		return
+	}
+
+	err = h.SessionManager.ConfirmAuthentication(ctx, currentSession.AccessToken)
+	if err != nil {
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to update session")
+		h.Logger.PrintError(err, nil)
+		return
+	}
+
+	helpers.RespondWithJSON(w, http.StatusOK, nil, ResponseModel{
+		Message: "Identity confirmed",
+	})
+
+	h.Logger.PrintInfo(
+		"User re-authenticated",
+		map[string]string{
+			"userId": user.ID,
+		},
+	)
+}
@@ -0,0 +1,102 @@
+package userreauth
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/arnald/forum/internal/app"
+	userQueries "github.com/arnald/forum/internal/app/user/queries"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/domain/session"
+	"github.com/arnald/forum/internal/domain/user"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/middleware"
+	testhelpers "github.com/arnald/forum/internal/pkg/testing"
+)
+
+func TestHandler_Reauth_Lockout(t *testing.T) {
+	var confirmed bool
+	attempts := 0
+	var lockedUntil time.Time
+	repo := &testhelpers.MockRepository{
+		GetLoginLockoutFunc: func(_ context.Context, _ string) (time.Time, error) {
+			return lockedUntil, nil
+		},
+		RecordFailedLoginFunc: func(_ context.Context, _ string, maxAttempts int, lockFor time.Duration) (time.Time, error) {
+			attempts++
+			if attempts >= maxAttempts {
+				lockedUntil = time.Now().Add(lockFor)
+			}
+			return lockedUntil, nil
+		},
+	}
+	enc := &testhelpers.MockEncryptionProvider{
+		MatchesFunc: func(hashedPassword, plaintextPassword string) error {
+			if hashedPassword != plaintextPassword {
+				return testhelpers.ErrTest
+			}
+			return nil
+		},
+	}
+	sessions := &testhelpers.MockSessionManager{
+		GetSessionFromSessionTokensFunc: func(_, _ string) (*session.Session, error) {
+			return &session.Session{
+				AccessToken:        "token",
+				Expiry:             time.Now().Add(time.Hour),
+				RefreshTokenExpiry: time.Now().Add(time.Hour),
+			}, nil
+		},
+		GetUserFromSessionFunc: func(_ string) (*user.User, error) {
+			return &user.User{ID: "test-user-id", Password: "Secret1!"}, nil
+		},
+		ConfirmAuthenticationFunc: func(_ context.Context, _ string) error {
+			confirmed = true
+			return nil
+		},
+	}
+	services := app.Services{
+		UserServices: app.UserServices{
+			Queries: app.Queries{
+				VerifyPassword: userQueries.NewVerifyPasswordHandler(repo, enc),
+			},
+		},
+	}
+	cfg := &config.ServerConfig{
+		Timeouts: config.TimeoutsConfig{
+			HandlerTimeouts: config.HandlerTimeoutsConfig{UserLogin: time.Second},
+		},
+	}
+	handler := NewHandler(cfg, services, sessions, logger.New(io.Discard, logger.LevelOff))
+	authorized := middleware.NewAuthorizationMiddleware(sessions, time.Minute).Required(handler.Reauth)
+
+	post := func(password string) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/reauth", bytes.NewBufferString(`{"password":"`+password+`"}`))
+		rec := httptest.NewRecorder()
+		authorized(rec, req)
+		return rec.Code
+	}
+
+	status := http.StatusUnauthorized
+	for range 10 {
+		status = post("guess")
+		if status != http.StatusUnauthorized {
+			break
+		}
+	}
+	if status != http.StatusTooManyRequests {
+		t.Fatalf("Reauth() after repeated wrong passwords status = %d, want %d", status, http.StatusTooManyRequests)
+	}
+
+	status = post("Secret1!")
+	if status != http.StatusTooManyRequests {
+		t.Errorf("Reauth() with the right password while locked status = %d, want %d", status, http.StatusTooManyRequests)
+	}
+	if confirmed {
+		t.Error("Reauth() confirmed the session of a locked account")
+	}
+}
@@ -0,0 +1,72 @@
+package refresh
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/arnald/forum/internal/domain/session"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/middleware"
+	"github.com/arnald/forum/internal/infra/storage/sessionstore"
+	"github.com/arnald/forum/internal/pkg/helpers"
+)
+
+type Handler struct {
+	sessionManager session.Manager
+	logger         logger.Logger
+}
+
+func NewHandler(sessionManager session.Manager, logger logger.Logger) *Handler {
+	return &Handler{
+		sessionManager: sessionManager,
+		logger:         logger,
+	}
+}
+
+type ResponseModel struct {
+	ExpiresAt        time.Time `json:"expiresAt"`
+	RefreshExpiresAt time.Time `json:"refreshExpiresAt"`
+	UserID           string    `json:"userId"`
+	AccessToken      string    `json:"accessToken"`
+	RefreshToken     string    `json:"refreshToken"`
+}
+
+// Refresh trades the refresh_token cookie for a new session. The old session
+// ends with it, so the caller must store both new tokens.
+func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	_, refreshToken := middleware.GetTokensFromRequest(r)
+	if refreshToken == "" {
+		helpers.RespondWithError(w, http.StatusUnauthorized, "No refresh token")
+		return
+	}
+
+	rotated, err := h.sessionManager.RotateSession(r.Context(), refreshToken)
+	if err != nil {
+		if errors.Is(err, sessionstore.ErrSessionNotFound) || errors.Is(err, sessionstore.ErrSessionExpired) {
+			helpers.RespondWithError(w, http.StatusUnauthorized, "Refresh token expired or revoked")
+			return
+		}
+		h.logger.PrintError(err, nil)
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to refresh session")
+		return
+	}
+
+	helpers.RespondWithJSON(w, http.StatusOK, nil, ResponseModel{
+		UserID:           rotated.UserID,
+		AccessToken:      rotated.AccessToken,
+		RefreshToken:     rotated.RefreshToken,
+		ExpiresAt:        rotated.Expiry,
+		RefreshExpiresAt: rotated.RefreshTokenExpiry,
+	})
+
+	h.logger.PrintInfo("Session refreshed", map[string]string{
+		"userId": rotated.UserID,
+	})
+}
@@ -0,0 +1,95 @@
+package refresh
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/arnald/forum/internal/domain/session"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/storage/sessionstore"
+	testhelpers "github.com/arnald/forum/internal/pkg/testing"
+)
+
+func TestHandler_Refresh(t *testing.T) {
+	testCases := []struct {
+		rotateErr    error
+		name         string
+		refreshToken string
+		wantStatus   int
+	}{
+		{
+			name:         "valid refresh token is rotated",
+			refreshToken: "old-refresh",
+			wantStatus:   http.StatusOK,
+		},
+		{
+			name:         "revoked refresh token is refused",
+			refreshToken: "old-refresh",
+			rotateErr:    sessionstore.ErrSessionNotFound,
+			wantStatus:   http.StatusUnauthorized,
+		},
+		{
+			name:         "expired refresh token is refused",
+			refreshToken: "old-refresh",
+			rotateErr:    sessionstore.ErrSessionExpired,
+			wantStatus:   http.StatusUnauthorized,
+		},
+		{
+			name:       "missing refresh token is refused",
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			sessions := &testhelpers.MockSessionManager{
+				RotateSessionFunc: func(_ context.Context, refreshToken string) (*session.Session, error) {
+					if refreshToken != tt.refreshToken {
+						t.Errorf("RotateSession() refreshToken = %q, want %q", refreshToken, tt.refreshToken)
+					}
+					if tt.rotateErr != nil {
+						return nil, tt.rotateErr
+					}
+					return &session.Session{
+						UserID:             "alice",
+						AccessToken:        "new-access",
+						RefreshToken:       "new-refresh",
+						Expiry:             time.Now().Add(time.Hour),
+						RefreshTokenExpiry: time.Now().Add(2 * time.Hour),
+					}, nil
+				},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/refresh", nil)
+			if tt.refreshToken != "" {
+				req.AddCookie(&http.Cookie{Name: "refresh_token", Value: tt.refreshToken})
+			}
+			rec := httptest.NewRecorder()
+
+			NewHandler(sessions, logger.New(io.Discard, logger.LevelOff)).Refresh(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("Refresh() status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var got struct {
+				Data ResponseModel `json:"data"`
+			}
+			err := json.NewDecoder(rec.Body).Decode(&got)
+			if err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if got.Data.AccessToken != "new-access" || got.Data.RefreshToken != "new-refresh" || got.Data.UserID != "alice" {
+				t.Errorf("Refresh() = %+v, want the rotated tokens for alice", got.Data)
+			}
+		})
+	}
+}
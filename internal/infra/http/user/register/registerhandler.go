@@ -2,14 +2,16 @@ package userregister
 
 import (
 	"context"
+	"errors"
 	"net/http"
-	"strings"
+	"net/url"
 
 	"github.com/arnald/forum/internal/app"
 	usercommands "github.com/arnald/forum/internal/app/user/commands"
 	"github.com/arnald/forum/internal/config"
 	"github.com/arnald/forum/internal/domain/session"
 	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/users"
 	"github.com/arnald/forum/internal/pkg/helpers"
 	"github.com/arnald/forum/internal/pkg/validator"
 )
@@ -70,12 +72,14 @@ func (h Handler) UserRegister(w http.ResponseWriter, r *http.Request) {
 	v := validator.New()
 
 	validator.ValidateUserRegistration(v, userAny)
+	v.CheckPasswordStrength("Password", userToRegister.Password, h.Config.Passwords)
 
 	if !v.Valid() {
-		helpers.RespondWithError(
+		helpers.RespondWithFieldErrors(
 			w,
 			http.StatusBadRequest,
 			v.ToStringErrors(),
+			v.FieldErrors(userAny),
 		)
 
 		h.Logger.PrintError(logger.ErrValidationFailed, v.Errors)
@@ -85,9 +89,23 @@ func (h Handler) UserRegister(w http.ResponseWriter, r *http.Request) {
 	user, err := h.UserServices.UserServices.Commands.UserRegister.Handle(ctx, usercommands.UserRegisterRequest{
 		Name:     userToRegister.Username,
 		Password: userToRegister.Password,
-		Email:    strings.ToLower(userToRegister.Email),
+		Email:    helpers.NormalizeEmail(userToRegister.Email, h.Config.Accounts.StripEmailAliases),
 	})
 	if err != nil {
+		field, taken := takenField(err)
+		if taken {
+			helpers.RespondWithFieldErrors(
+				w,
+				http.StatusConflict,
+				err.Error(),
+				map[string]string{field: field + " is already taken"},
+			)
+
+			h.Logger.PrintError(err, nil)
+
+			return
+		}
+
 		helpers.RespondWithError(
 			w,
 			http.StatusInternalServerError,
@@ -99,6 +117,8 @@ func (h Handler) UserRegister(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.issueVerificationLink(ctx, user.ID, user.Email)
+
 	userResponse := RegisterUserResponse{
 		UserID:  user.ID,
 		Message: "user registered successfully",
@@ -120,3 +140,38 @@ func (h Handler) UserRegister(w http.ResponseWriter, r *http.Request) {
 		},
 	)
 }
+
+// takenField maps a uniqueness violation to the json field it belongs to.
+func takenField(err error) (string, bool) {
+	switch {
+	case errors.Is(err, users.ErrDuplicateUsername):
+		return "username", true
+	case errors.Is(err, users.ErrDuplicateEmail):
+		return "email", true
+	default:
+		return "", false
+	}
+}
+
+// issueVerificationLink writes a verification token for the new account. A
+// failure here does not undo the registration; the account just stays
+// unverified.
+func (h Handler) issueVerificationLink(ctx context.Context, userID, email string) {
+	token, err := h.UserServices.UserServices.Commands.SendVerify.Handle(ctx, usercommands.SendVerificationEmailRequest{
+		UserID: userID,
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		return
+	}
+
+	// There is no mail transport yet, so the link goes to the server log for
+	// the operator to pass on.
+	h.Logger.PrintInfo(
+		"Email verification link issued",
+		map[string]string{
+			"email":     email,
+			"verifyUrl": h.Config.Topics.PublicURL + "/verify-email?token=" + url.QueryEscape(token),
+		},
+	)
+}
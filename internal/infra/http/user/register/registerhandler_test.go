@@ -0,0 +1,205 @@
+package userregister
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/arnald/forum/internal/app"
+	usercommands "github.com/arnald/forum/internal/app/user/commands"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/domain/user"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/users"
+	"github.com/arnald/forum/internal/pkg/helpers"
+	testhelpers "github.com/arnald/forum/internal/pkg/testing"
+	"github.com/arnald/forum/internal/pkg/validator"
+)
+
+func TestHandler_UserRegister(t *testing.T) {
+	t.Run("group: register field errors", func(t *testing.T) {
+		testCases := newRegisterHandlerTestCases()
+		for _, tt := range testCases {
+			t.Run(tt.name, runRegisterHandlerTest(tt))
+		}
+	})
+}
+
+type registerHandlerTestCase struct {
+	name       string
+	body       string
+	repoErr    error
+	wantStatus int
+	wantFields map[string]string
+	wantVerify bool
+}
+
+func newRegisterHandlerTestCases() []registerHandlerTestCase {
+	validBody := `{"username":"testuser","email":"test@example.com","password":"Password1!"}`
+
+	return []registerHandlerTestCase{
+		{
+			name:       "username already taken",
+			body:       validBody,
+			repoErr:    users.ErrDuplicateUsername,
+			wantStatus: http.StatusConflict,
+			wantFields: map[string]string{"username": "username is already taken"},
+		},
+		{
+			name:       "email already taken",
+			body:       validBody,
+			repoErr:    users.ErrDuplicateEmail,
+			wantStatus: http.StatusConflict,
+			wantFields: map[string]string{"email": "email is already taken"},
+		},
+		{
+			name:       "new account gets a verification token",
+			body:       validBody,
+			wantStatus: http.StatusCreated,
+			wantVerify: true,
+		},
+		{
+			name:       "validation errors keyed by json field",
+			body:       `{"username":"ab","email":"not-an-email","password":"Password1!"}`,
+			wantStatus: http.StatusBadRequest,
+			wantFields: map[string]string{
+				"username": "must be at least 3 characters long",
+				"email":    "invalid email",
+			},
+		},
+		{
+			name:       "weak password names what it is missing",
+			body:       `{"username":"testuser","email":"test@example.com","password":"short"}`,
+			wantStatus: http.StatusBadRequest,
+			wantFields: map[string]string{
+				"password": "must be at least 8 characters and include a digit",
+			},
+		},
+	}
+}
+
+func runRegisterHandlerTest(tt registerHandlerTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		var verifyIssued bool
+		repo := &testhelpers.MockRepository{
+			UserRegisterFunc: func(_ context.Context, _ *user.User) error { return tt.repoErr },
+			CreateVerificationTokenFunc: func(_ context.Context, userID, _ string, _ time.Time) error {
+				verifyIssued = userID == "test-uuid"
+				return nil
+			},
+		}
+		uuid := &testhelpers.MockUUIDProvider{NewUUIDFunc: func() string { return "test-uuid" }}
+		enc := &testhelpers.MockEncryptionProvider{
+			GenerateFunc: func(string) (string, error) { return "hashed_password", nil },
+		}
+
+		services := app.Services{
+			UserServices: app.UserServices{
+				Commands: app.Commands{
+					UserRegister: usercommands.NewUserRegisterHandler(repo, uuid, enc),
+					SendVerify:   usercommands.NewSendVerificationEmailHandler(repo, uuid),
+				},
+			},
+		}
+		cfg := &config.ServerConfig{
+			Timeouts: config.TimeoutsConfig{
+				HandlerTimeouts: config.HandlerTimeoutsConfig{UserRegister: time.Second},
+			},
+			Passwords: validator.PasswordPolicy{MinLength: 8, RequireDigit: true},
+		}
+		handler := NewHandler(cfg, services, &testhelpers.MockSessionManager{}, logger.New(io.Discard, logger.LevelOff))
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/register", bytes.NewBufferString(tt.body))
+		rec := httptest.NewRecorder()
+
+		handler.UserRegister(rec, req)
+
+		if rec.Code != tt.wantStatus {
+			t.Fatalf("UserRegister() status = %d, want %d", rec.Code, tt.wantStatus)
+		}
+		if verifyIssued != tt.wantVerify {
+			t.Errorf("UserRegister() issued verification token = %v, want %v", verifyIssued, tt.wantVerify)
+		}
+		if tt.wantFields == nil {
+			return
+		}
+
+		var got helpers.FieldErrorsResponse
+		err := json.NewDecoder(rec.Body).Decode(&got)
+		if err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+
+		if len(got.Fields) != len(tt.wantFields) {
+			t.Fatalf("UserRegister() fields = %v, want %v", got.Fields, tt.wantFields)
+		}
+		for field, want := range tt.wantFields {
+			if got.Fields[field] != want {
+				t.Errorf("UserRegister() fields[%q] = %q, want %q", field, got.Fields[field], want)
+			}
+		}
+	}
+}
+
+func TestHandler_UserRegister_NormalizesEmail(t *testing.T) {
+	testCases := []struct {
+		name         string
+		email        string
+		wantStored   string
+		stripAliases bool
+	}{
+		{name: "case is folded", email: "John.Doe+forum@Gmail.com", wantStored: "john.doe+forum@gmail.com"},
+		{name: "gmail aliases stripped when configured", email: "John.Doe+forum@Gmail.com", wantStored: "johndoe@gmail.com", stripAliases: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			var stored string
+			repo := &testhelpers.MockRepository{
+				UserRegisterFunc: func(_ context.Context, u *user.User) error {
+					stored = u.Email
+					return nil
+				},
+				CreateVerificationTokenFunc: func(_ context.Context, _, _ string, _ time.Time) error { return nil },
+			}
+			uuid := &testhelpers.MockUUIDProvider{NewUUIDFunc: func() string { return "test-uuid" }}
+			enc := &testhelpers.MockEncryptionProvider{
+				GenerateFunc: func(string) (string, error) { return "hashed_password", nil },
+			}
+
+			services := app.Services{
+				UserServices: app.UserServices{
+					Commands: app.Commands{
+						UserRegister: usercommands.NewUserRegisterHandler(repo, uuid, enc),
+						SendVerify:   usercommands.NewSendVerificationEmailHandler(repo, uuid),
+					},
+				},
+			}
+			cfg := &config.ServerConfig{
+				Timeouts: config.TimeoutsConfig{
+					HandlerTimeouts: config.HandlerTimeoutsConfig{UserRegister: time.Second},
+				},
+				Accounts: config.AccountsConfig{StripEmailAliases: tt.stripAliases},
+			}
+			handler := NewHandler(cfg, services, &testhelpers.MockSessionManager{}, logger.New(io.Discard, logger.LevelOff))
+
+			body := `{"username":"testuser","email":"` + tt.email + `","password":"Password1!"}`
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/register", bytes.NewBufferString(body))
+			rec := httptest.NewRecorder()
+
+			handler.UserRegister(rec, req)
+
+			if rec.Code != http.StatusCreated {
+				t.Fatalf("UserRegister() status = %d, want %d", rec.Code, http.StatusCreated)
+			}
+			if stored != tt.wantStored {
+				t.Errorf("stored email = %q, want %q", stored, tt.wantStored)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package requestrole
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/arnald/forum/internal/app"
+	roleRequestCommands "github.com/arnald/forum/internal/app/rolerequests/commands"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/domain/rolerequest"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/middleware"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/rolerequests"
+	"github.com/arnald/forum/internal/pkg/helpers"
+	"github.com/arnald/forum/internal/pkg/validator"
+)
+
+type RequestModel struct {
+	Reason string `json:"reason"`
+}
+
+type ResponseModel struct {
+	Request *rolerequest.RoleRequest `json:"request"`
+	Message string                   `json:"message"`
+}
+
+type Handler struct {
+	UserServices app.Services
+	Config       *config.ServerConfig
+	Logger       logger.Logger
+}
+
+func NewHandler(userServices app.Services, config *config.ServerConfig, logger logger.Logger) *Handler {
+	return &Handler{
+		UserServices: userServices,
+		Config:       config,
+		Logger:       logger,
+	}
+}
+
+// RequestRole files the signed-in user's request to become a moderator. A
+// user may only have one request waiting for an admin at a time.
+func (h *Handler) RequestRole(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	user := middleware.GetUserFromContext(r)
+	if user == nil {
+		h.Logger.PrintError(logger.ErrUserNotFoundInContext, nil)
+		helpers.RespondWithError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserRegister)
+	defer cancel()
+
+	var requestModel RequestModel
+
+	_, err := helpers.ParseBodyRequest(r, &requestModel)
+	if err != nil {
+		helpers.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	defer r.Body.Close()
+
+	requestModel.Reason = strings.TrimSpace(requestModel.Reason)
+
+	val := validator.New()
+	validator.ValidateRoleRequest(val, &requestModel)
+	if !val.Valid() {
+		h.Logger.PrintError(logger.ErrValidationFailed, val.Errors)
+		helpers.RespondWithError(w, http.StatusBadRequest, val.ToStringErrors())
+		return
+	}
+
+	request, err := h.UserServices.UserServices.Commands.RequestRole.Handle(ctx, roleRequestCommands.RequestRoleRequest{
+		User:   user,
+		Reason: requestModel.Reason,
+	})
+	if err != nil {
+		h.Logger.PrintError(err, nil)
+		switch {
+		case errors.Is(err, roleRequestCommands.ErrAlreadyStaff):
+			helpers.RespondWithError(w, http.StatusBadRequest, "You are already a moderator")
+		case errors.Is(err, rolerequests.ErrPendingRequestExists):
+			helpers.RespondWithError(w, http.StatusConflict, "You already have a request waiting for review")
+		default:
+			helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to request role")
+		}
+		return
+	}
+
+	helpers.RespondWithJSON(w, http.StatusCreated, nil, ResponseModel{
+		Request: request,
+		Message: "Role request submitted",
+	})
+
+	h.Logger.PrintInfo(
+		"Role requested",
+		map[string]string{
+			"user_id":    user.ID,
+			"request_id": strconv.Itoa(request.ID),
+			"role":       request.RequestedRole,
+		})
+}
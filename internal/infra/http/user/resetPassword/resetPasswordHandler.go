@@ -0,0 +1,113 @@
+package resetpassword
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/arnald/forum/internal/app"
+	usercommands "github.com/arnald/forum/internal/app/user/commands"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/domain/session"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/users"
+	"github.com/arnald/forum/internal/pkg/helpers"
+	"github.com/arnald/forum/internal/pkg/validator"
+)
+
+type RequestModel struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+type ResponseModel struct {
+	Message string `json:"message"`
+}
+
+type Handler struct {
+	UserServices   app.Services
+	SessionManager session.Manager
+	Config         *config.ServerConfig
+	Logger         logger.Logger
+}
+
+func NewHandler(config *config.ServerConfig, app app.Services, sm session.Manager, logger logger.Logger) *Handler {
+	return &Handler{
+		UserServices:   app,
+		SessionManager: sm,
+		Config:         config,
+		Logger:         logger,
+	}
+}
+
+// ResetPassword spends a reset token to set a new password and signs the
+// account out of every existing session.
+func (h *Handler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserLogin)
+	defer cancel()
+
+	var resetRequest RequestModel
+
+	requestAny, err := helpers.ParseBodyRequest(r, &resetRequest)
+	if err != nil {
+		helpers.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		h.Logger.PrintError(err, nil)
+		return
+	}
+	defer r.Body.Close()
+
+	v := validator.New()
+
+	validator.ValidateResetPassword(v, requestAny)
+	v.CheckPasswordStrength("Password", resetRequest.Password, h.Config.Passwords)
+
+	if !v.Valid() {
+		helpers.RespondWithFieldErrors(
+			w,
+			http.StatusBadRequest,
+			v.ToStringErrors(),
+			v.FieldErrors(requestAny),
+		)
+
+		h.Logger.PrintError(logger.ErrValidationFailed, v.Errors)
+		return
+	}
+
+	userID, err := h.UserServices.UserServices.Commands.ResetPassword.Handle(ctx, usercommands.ResetPasswordRequest{
+		Token:    resetRequest.Token,
+		Password: resetRequest.Password,
+	})
+	switch {
+	case errors.Is(err, users.ErrResetTokenInvalid):
+		helpers.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to reset password")
+		h.Logger.PrintError(err, nil)
+		return
+	}
+
+	err = h.SessionManager.InvalidateUserSessions(ctx, userID)
+	if err != nil {
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Password changed but failed to sign out other sessions")
+		h.Logger.PrintError(err, nil)
+		return
+	}
+
+	helpers.RespondWithJSON(w, http.StatusOK, nil, ResponseModel{
+		Message: "Password has been reset, please log in",
+	})
+
+	h.Logger.PrintInfo(
+		"Password reset",
+		map[string]string{
+			"userId": userID,
+		},
+	)
+}
@@ -0,0 +1,83 @@
+package verifyemail
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/arnald/forum/internal/app"
+	usercommands "github.com/arnald/forum/internal/app/user/commands"
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/infra/logger"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/users"
+	"github.com/arnald/forum/internal/pkg/helpers"
+)
+
+type RequestModel struct {
+	Token string `json:"token"`
+}
+
+type ResponseModel struct {
+	Message string `json:"message"`
+}
+
+type Handler struct {
+	UserServices app.Services
+	Config       *config.ServerConfig
+	Logger       logger.Logger
+}
+
+func NewHandler(config *config.ServerConfig, app app.Services, logger logger.Logger) *Handler {
+	return &Handler{
+		UserServices: app,
+		Config:       config,
+		Logger:       logger,
+	}
+}
+
+// VerifyEmail spends a verification token and marks its owner's email as
+// verified, which lets them start topics.
+func (h *Handler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.Logger.PrintError(logger.ErrInvalidRequestMethod, nil)
+		helpers.RespondWithError(w, http.StatusMethodNotAllowed, "Invalid request method")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Timeouts.HandlerTimeouts.UserLogin)
+	defer cancel()
+
+	var verifyRequest RequestModel
+
+	_, err := helpers.ParseBodyRequest(r, &verifyRequest)
+	if err != nil || verifyRequest.Token == "" {
+		helpers.RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		h.Logger.PrintError(err, nil)
+		return
+	}
+	defer r.Body.Close()
+
+	userID, err := h.UserServices.UserServices.Commands.VerifyEmail.Handle(ctx, usercommands.VerifyEmailRequest{
+		Token: verifyRequest.Token,
+	})
+	switch {
+	case errors.Is(err, users.ErrVerifyTokenInvalid):
+		helpers.RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		helpers.RespondWithError(w, http.StatusInternalServerError, "Failed to verify email")
+		h.Logger.PrintError(err, nil)
+		return
+	}
+
+	helpers.RespondWithJSON(w, http.StatusOK, nil, ResponseModel{
+		Message: "Email verified",
+	})
+
+	h.Logger.PrintInfo(
+		"Email verified",
+		map[string]string{
+			"userId": userID,
+		},
+	)
+}
@@ -3,6 +3,7 @@ package castvote
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -19,6 +20,7 @@ import (
 	"github.com/arnald/forum/internal/infra/logger"
 	"github.com/arnald/forum/internal/infra/middleware"
 	"github.com/arnald/forum/internal/infra/storage/notifications"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/votes"
 	"github.com/arnald/forum/internal/pkg/helpers"
 )
 
@@ -95,7 +97,23 @@ func (h *Handler) CastVote(w http.ResponseWriter, r *http.Request) {
 		UserID:       user.ID,
 		Target:       target,
 		ReactionType: req.ReactionType,
+		DailyCap:     h.Config.Votes.DailyCap,
+		CapExempt:    user.IsModerator(),
 	})
+	if errors.Is(err, votecommands.ErrDailyVoteCapReached) {
+		h.Logger.PrintError(err, map[string]string{"user_id": user.ID})
+		helpers.RespondWithError(
+			w,
+			http.StatusTooManyRequests,
+			fmt.Sprintf("You have reached the limit of %d votes per day. Please try again later.", h.Config.Votes.DailyCap),
+		)
+		return
+	}
+	if errors.Is(err, votes.ErrTopicNotFound) {
+		h.Logger.PrintError(err, nil)
+		helpers.RespondWithError(w, http.StatusNotFound, "Topic not found")
+		return
+	}
 	if err != nil {
 		h.Logger.PrintError(err, nil)
 		helpers.RespondWithError(
@@ -165,16 +183,13 @@ func (h *Handler) sendVoteNotification(ctx context.Context, username, userID str
 		return
 	}
 
-	var message string
 	var title string
 	var notificationType notification.Type
 	switch req.ReactionType {
 	case 1:
-		message = fmt.Sprintf("%s liked your %s", username, contentType)
 		title = "New like!"
 		notificationType = notification.NotificationTypeLike
 	case -1:
-		message = fmt.Sprintf("%s disliked your %s", username, contentType)
 		title = "New dislike!"
 		notificationType = notification.NotificationTypeDislike
 	}
@@ -184,10 +199,11 @@ func (h *Handler) sendVoteNotification(ctx context.Context, username, userID str
 		RelatedID:   contentID,
 		UserID:      ownerID,
 		ActorID:     userID,
-		Message:     message,
+		ActorName:   username,
 		Title:       title,
 		RelatedType: contentType,
 	}
+	notification.Message = notification.VoteMessage(1)
 
 	err := h.Notifications.CreateNotification(ctx, notification)
 	if err != nil {
@@ -2,20 +2,26 @@ package middleware
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/arnald/forum/internal/domain/session"
 )
 
 type authorization struct {
 	sessionManager session.Manager
+	reauthWindow   time.Duration
 }
 type Authorization interface {
 	Required(next http.HandlerFunc) http.HandlerFunc
 	Optional(next http.HandlerFunc) http.HandlerFunc
+	RequireRecentAuth(next http.HandlerFunc) http.HandlerFunc
+	RequireModerator(next http.HandlerFunc) http.HandlerFunc
+	RequireAdmin(next http.HandlerFunc) http.HandlerFunc
 }
 
-func NewAuthorizationMiddleware(sessionManager session.Manager) Authorization {
+func NewAuthorizationMiddleware(sessionManager session.Manager, reauthWindow time.Duration) Authorization {
 	return authorization{
 		sessionManager: sessionManager,
+		reauthWindow:   reauthWindow,
 	}
 }
@@ -11,7 +11,8 @@ import (
 type Key string
 
 const (
-	userIDKey Key = "user"
+	userIDKey  Key = "user"
+	sessionKey Key = "session"
 )
 
 func CheckTokenExpiration(session *session.Session) (sessionExpired, refreshTokenExpired bool) {
@@ -53,3 +54,17 @@ func GetUserFromContext(r *http.Request) *user.User {
 
 	return user
 }
+
+func GetSessionFromContext(r *http.Request) *session.Session {
+	value := r.Context().Value(sessionKey)
+	if value == nil {
+		return nil
+	}
+
+	session, ok := value.(*session.Session)
+	if !ok {
+		return nil
+	}
+
+	return session
+}
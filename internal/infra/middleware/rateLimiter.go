@@ -6,35 +6,38 @@ import (
 	"strings"
 	"time"
 
+	"github.com/arnald/forum/internal/config"
 	"github.com/arnald/forum/internal/infra/middleware/ratelimiter"
 	"github.com/arnald/forum/internal/pkg/helpers"
 )
 
 type rateLimitMiddleware struct {
-	limiter *ratelimiter.RateLimiter
+	limiter ratelimiter.Limiter
 	handler http.Handler
 }
 
-func NewRateLimiterMiddleware(handler http.Handler, limit int, windowSeconds int64, cleanup time.Duration) http.Handler {
+func NewRateLimiterMiddleware(handler http.Handler, cfg config.RateLimitConfig) http.Handler {
 	return &rateLimitMiddleware{
-		limiter: ratelimiter.NewRateLimiter(limit, windowSeconds, cleanup),
+		limiter: newLimiter(cfg),
 		handler: handler,
 	}
 }
 
+func newLimiter(cfg config.RateLimitConfig) ratelimiter.Limiter {
+	if cfg.Backend == config.RateLimitBackendTokenBucket {
+		return ratelimiter.NewTokenBucketLimiter(cfg.RequestsLimit, cfg.WindowSeconds, cfg.MaxClients, cfg.Cleanup)
+	}
+
+	return ratelimiter.NewRateLimiter(cfg.RequestsLimit, cfg.WindowSeconds, cfg.Cleanup)
+}
+
 func (rl *rateLimitMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	ip := getClientIP(r)
+	ip := GetClientIP(r)
 
 	allowed, remaining, resetTime := rl.limiter.Allow(ip)
-
-	w.Header().Set("X-Rateimit-Limit", strconv.Itoa(rl.limiter.Limit))
-	w.Header().Set("X-Rateimit-Remaining", strconv.Itoa(remaining))
-	w.Header().Set("X-Rateimit-Reset", strconv.FormatInt(resetTime, 10))
+	SetRateLimitHeaders(w, rl.limiter, allowed, remaining, resetTime)
 
 	if !allowed {
-		retryAfter := resetTime - time.Now().Unix()
-		w.Header().Set("Retry-After", strconv.FormatInt(retryAfter, 10))
-
 		helpers.RespondWithError(
 			w,
 			http.StatusTooManyRequests,
@@ -47,7 +50,25 @@ func (rl *rateLimitMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	rl.handler.ServeHTTP(w, r)
 }
 
-func getClientIP(r *http.Request) string {
+// SetRateLimitHeaders reports a limiter decision to the client: its limit,
+// the requests left and the unix time the budget is back in full. A refused
+// request also gets Retry-After, in seconds and never less than one so that
+// clients do not retry straight away. Inner limiters overwrite the headers of
+// outer ones, since theirs is the tighter budget for the route.
+func SetRateLimitHeaders(w http.ResponseWriter, limiter ratelimiter.Limiter, allowed bool, remaining int, resetTime int64) {
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limiter.MaxRequests()))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(resetTime, 10))
+
+	if !allowed {
+		retryAfter := max(resetTime-time.Now().Unix(), 1)
+		w.Header().Set("Retry-After", strconv.FormatInt(retryAfter, 10))
+	}
+}
+
+// GetClientIP returns the address the request came from, preferring the
+// proxy headers the frontend sets.
+func GetClientIP(r *http.Request) string {
 	xff := r.Header.Get("X-Forwarded-For")
 	if xff != "" {
 		ips := strings.Split(xff, ",")
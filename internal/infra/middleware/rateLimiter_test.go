@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/domain/user"
+)
+
+func TestRateLimiterMiddleware_Headers(t *testing.T) {
+	for _, backend := range []string{config.RateLimitBackendSlidingWindow, config.RateLimitBackendTokenBucket} {
+		t.Run(backend, func(t *testing.T) {
+			cfg := config.RateLimitConfig{
+				Enabled:       true,
+				Backend:       backend,
+				RequestsLimit: 2,
+				WindowSeconds: 60,
+				Cleanup:       time.Minute,
+			}
+			next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+			handler := NewRateLimiterMiddleware(next, cfg)
+
+			get := func() *httptest.ResponseRecorder {
+				req := httptest.NewRequest(http.MethodGet, "/api/v1/topics/all", nil)
+				req.Header.Set("X-Forwarded-For", "1.1.1.1")
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+				return rec
+			}
+
+			for i, wantRemaining := range []string{"1", "0"} {
+				rec := get()
+				if rec.Code != http.StatusOK {
+					t.Fatalf("request %d status = %d, want %d", i+1, rec.Code, http.StatusOK)
+				}
+				if got := rec.Header().Get("X-RateLimit-Remaining"); got != wantRemaining {
+					t.Errorf("request %d X-RateLimit-Remaining = %q, want %q", i+1, got, wantRemaining)
+				}
+				if got := rec.Header().Get("X-RateLimit-Limit"); got != "2" {
+					t.Errorf("request %d X-RateLimit-Limit = %q, want %q", i+1, got, "2")
+				}
+				if got := rec.Header().Get("Retry-After"); got != "" {
+					t.Errorf("request %d Retry-After = %q, want none while under budget", i+1, got)
+				}
+			}
+
+			rec := get()
+			if rec.Code != http.StatusTooManyRequests {
+				t.Fatalf("request over budget status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+			}
+			if got := rec.Header().Get("X-RateLimit-Remaining"); got != "0" {
+				t.Errorf("X-RateLimit-Remaining = %q, want %q", got, "0")
+			}
+
+			reset, err := strconv.ParseInt(rec.Header().Get("X-RateLimit-Reset"), 10, 64)
+			if err != nil || reset <= time.Now().Unix() {
+				t.Errorf("X-RateLimit-Reset = %q, want a unix time in the future", rec.Header().Get("X-RateLimit-Reset"))
+			}
+			retryAfter, err := strconv.Atoi(rec.Header().Get("Retry-After"))
+			if err != nil || retryAfter < 1 || retryAfter > 60 {
+				t.Errorf("Retry-After = %q, want between 1 and 60 seconds", rec.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
+
+func TestUserRateLimit_Headers(t *testing.T) {
+	cfg := config.RateLimitConfig{
+		Enabled:           true,
+		RequestsLimit:     100,
+		WindowSeconds:     60,
+		Cleanup:           time.Minute,
+		UserRequestsLimit: 1,
+		UserWindowSeconds: 60,
+	}
+	next := func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusCreated) }
+	handler := NewRateLimiterMiddleware(NewUserRateLimit(cfg).Limit(next), cfg)
+
+	post := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/comments/create", nil)
+		req = req.WithContext(context.WithValue(req.Context(), userIDKey, &user.User{ID: "alice"}))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := post(); rec.Code != http.StatusCreated {
+		t.Fatalf("first post status = %d, want %d", rec.Code, http.StatusCreated)
+	}
+
+	rec := post()
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("second post status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+	// The per-user budget is the one that ran out, so it is the one reported.
+	if got := rec.Header().Get("X-RateLimit-Limit"); got != "1" {
+		t.Errorf("X-RateLimit-Limit = %q, want the per-user limit %q", got, "1")
+	}
+	if got := rec.Header().Get("Retry-After"); got == "" || got == "0" {
+		t.Errorf("Retry-After = %q, want a positive number of seconds", got)
+	}
+}
@@ -0,0 +1,9 @@
+package ratelimiter
+
+// Limiter decides whether a client identified by key may make another request.
+// Allow reports the decision, the requests left and the unix time at which the
+// client is back to its full allowance.
+type Limiter interface {
+	Allow(key string) (allowed bool, remaining int, resetTime int64)
+	MaxRequests() int
+}
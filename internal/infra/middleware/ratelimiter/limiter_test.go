@@ -0,0 +1,134 @@
+package ratelimiter
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketLimiter_Allow(t *testing.T) {
+	t.Run("group: token bucket", func(t *testing.T) {
+		t.Run("allows a burst up to the limit then refuses", func(t *testing.T) {
+			limiter := NewTokenBucketLimiter(3, 60, 10, time.Minute)
+
+			for i := range 3 {
+				allowed, remaining, _ := limiter.Allow("1.1.1.1")
+				if !allowed {
+					t.Fatalf("request %d refused, want allowed", i+1)
+				}
+				if remaining != 2-i {
+					t.Errorf("request %d remaining = %d, want %d", i+1, remaining, 2-i)
+				}
+			}
+
+			allowed, _, resetTime := limiter.Allow("1.1.1.1")
+			if allowed {
+				t.Error("request over the limit allowed, want refused")
+			}
+			if resetTime <= time.Now().Unix() {
+				t.Errorf("resetTime = %d, want a time in the future", resetTime)
+			}
+		})
+
+		t.Run("clients do not share buckets", func(t *testing.T) {
+			limiter := NewTokenBucketLimiter(1, 60, 10, time.Minute)
+
+			allowedFirst, _, _ := limiter.Allow("1.1.1.1")
+			allowedSecond, _, _ := limiter.Allow("2.2.2.2")
+			if !allowedFirst || !allowedSecond {
+				t.Error("separate clients should each get their own allowance")
+			}
+		})
+
+		t.Run("full table sends new clients to the shared overflow bucket", func(t *testing.T) {
+			limiter := NewTokenBucketLimiter(2, 60, 2, time.Minute)
+
+			limiter.Allow("1.1.1.1")
+			limiter.Allow("2.2.2.2")
+
+			for _, ip := range []string{"3.3.3.3", "4.4.4.4"} {
+				allowed, _, _ := limiter.Allow(ip)
+				if !allowed {
+					t.Errorf("new client %s refused while the overflow bucket has tokens", ip)
+				}
+			}
+			allowed, _, _ := limiter.Allow("5.5.5.5")
+			if allowed {
+				t.Error("new client allowed after the overflow bucket ran out, want refused")
+			}
+			if got := limiter.clients.Load(); got != 2 {
+				t.Errorf("tracked clients = %d, want 2", got)
+			}
+
+			allowed, _, _ = limiter.Allow("1.1.1.1")
+			if !allowed {
+				t.Error("tracked client refused because the overflow bucket is empty")
+			}
+		})
+
+		t.Run("prune drops idle buckets", func(t *testing.T) {
+			limiter := NewTokenBucketLimiter(5, 60, 2, time.Minute)
+
+			limiter.Allow("1.1.1.1")
+			limiter.prune(time.Now().Add(2 * time.Minute))
+
+			if got := limiter.clients.Load(); got != 0 {
+				t.Errorf("tracked clients after prune = %d, want 0", got)
+			}
+			allowed, _, _ := limiter.Allow("3.3.3.3")
+			if !allowed {
+				t.Error("new client refused after prune freed space")
+			}
+		})
+
+		t.Run("concurrent requests never exceed the limit", func(t *testing.T) {
+			const limit = 50
+			limiter := NewTokenBucketLimiter(limit, 3600, 10, time.Minute)
+
+			var allowedCount atomic.Int64
+			var wg sync.WaitGroup
+			for range 200 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					allowed, _, _ := limiter.Allow("1.1.1.1")
+					if allowed {
+						allowedCount.Add(1)
+					}
+				}()
+			}
+			wg.Wait()
+
+			if got := allowedCount.Load(); got != limit {
+				t.Errorf("allowed = %d, want %d", got, limit)
+			}
+		})
+	})
+}
+
+func BenchmarkLimiters(b *testing.B) {
+	const clients = 1024
+
+	ips := make([]string, clients)
+	for i := range ips {
+		ips[i] = "10.0." + strconv.Itoa(i/256) + "." + strconv.Itoa(i%256)
+	}
+
+	backends := map[string]Limiter{
+		"sliding_window": NewRateLimiter(1_000_000, 60, time.Minute),
+		"token_bucket":   NewTokenBucketLimiter(1_000_000, 60, clients, time.Minute),
+	}
+
+	for name, limiter := range backends {
+		b.Run(name, func(b *testing.B) {
+			var next atomic.Uint64
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					limiter.Allow(ips[next.Add(1)%clients])
+				}
+			})
+		})
+	}
+}
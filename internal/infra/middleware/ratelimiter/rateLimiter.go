@@ -36,6 +36,10 @@ func NewRateLimiter(limit int, windowSeconds int64, cleanup time.Duration) *Rate
 	return rl
 }
 
+func (rl *RateLimiter) MaxRequests() int {
+	return rl.Limit
+}
+
 func (rl *RateLimiter) Allow(ip string) (bool, int, int64) {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
@@ -0,0 +1,131 @@
+package ratelimiter
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type bucket struct {
+	lastRefill time.Time
+	tokens     float64
+	lastSeen   atomic.Int64
+	mu         sync.Mutex
+}
+
+// TokenBucketLimiter keeps one bucket per client. Buckets hold up to Limit
+// tokens and refill continuously at Limit tokens per window, so short bursts
+// are allowed while the long-run rate stays the same as the sliding window.
+// Once maxClients buckets exist, clients without one share the overflow
+// bucket until cleanup frees room.
+type TokenBucketLimiter struct {
+	overflow        *bucket
+	buckets         sync.Map
+	clients         atomic.Int64
+	Limit           int
+	maxClients      int64
+	refillPerSecond float64
+	windowSize      time.Duration
+	cleanupInterval time.Duration
+}
+
+func NewTokenBucketLimiter(limit int, windowSeconds int64, maxClients int, cleanup time.Duration) *TokenBucketLimiter {
+	tb := &TokenBucketLimiter{
+		overflow:        &bucket{tokens: float64(limit), lastRefill: time.Now()},
+		Limit:           limit,
+		maxClients:      int64(maxClients),
+		refillPerSecond: float64(limit) / float64(windowSeconds),
+		windowSize:      time.Duration(windowSeconds) * time.Second,
+		cleanupInterval: cleanup,
+	}
+
+	go tb.cleanup()
+
+	return tb
+}
+
+func (tb *TokenBucketLimiter) MaxRequests() int {
+	return tb.Limit
+}
+
+func (tb *TokenBucketLimiter) Allow(ip string) (bool, int, int64) {
+	now := time.Now()
+
+	b, ok := tb.getBucket(ip, now)
+	if !ok {
+		return false, 0, now.Add(tb.cleanupInterval).Unix()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastSeen.Store(now.Unix())
+
+	elapsed := now.Sub(b.lastRefill).Seconds()
+	b.tokens = math.Min(float64(tb.Limit), b.tokens+elapsed*tb.refillPerSecond)
+	b.lastRefill = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+
+	missing := float64(tb.Limit) - b.tokens
+	resetTime := now.Add(time.Duration(missing / tb.refillPerSecond * float64(time.Second))).Unix()
+
+	return allowed, int(b.tokens), resetTime
+}
+
+func (tb *TokenBucketLimiter) getBucket(ip string, now time.Time) (*bucket, bool) {
+	existing, ok := tb.buckets.Load(ip)
+	if ok {
+		b, isBucket := existing.(*bucket)
+		return b, isBucket
+	}
+
+	if tb.maxClients > 0 && tb.clients.Load() >= tb.maxClients {
+		// The table is full: share one bucket rather than grow without bound
+		// or lock every new client out.
+		return tb.overflow, true
+	}
+
+	fresh := &bucket{
+		tokens:     float64(tb.Limit),
+		lastRefill: now,
+	}
+	fresh.lastSeen.Store(now.Unix())
+
+	actual, loaded := tb.buckets.LoadOrStore(ip, fresh)
+	if !loaded {
+		tb.clients.Add(1)
+	}
+
+	b, isBucket := actual.(*bucket)
+	return b, isBucket
+}
+
+// cleanup drops buckets that have been idle for a full window. Such a bucket
+// would be full again anyway, so forgetting it does not change any decision.
+func (tb *TokenBucketLimiter) cleanup() {
+	ticker := time.NewTicker(tb.cleanupInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		tb.prune(time.Now())
+	}
+}
+
+func (tb *TokenBucketLimiter) prune(now time.Time) {
+	cutoff := now.Add(-tb.windowSize).Unix()
+
+	tb.buckets.Range(func(key, value any) bool {
+		b, ok := value.(*bucket)
+		if ok && b.lastSeen.Load() <= cutoff {
+			if tb.buckets.CompareAndDelete(key, value) {
+				tb.clients.Add(-1)
+			}
+		}
+		return true
+	})
+}
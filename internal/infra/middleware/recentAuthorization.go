@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/arnald/forum/internal/domain/session"
+	"github.com/arnald/forum/internal/pkg/helpers"
+)
+
+const (
+	ReauthMethodPassword = "password"
+	ReauthMethodOAuth    = "oauth"
+)
+
+// ReauthRequiredResponse tells the client how the user can confirm their
+// identity: by posting their password to /reauth, or, for accounts without a
+// password, by signing in through their OAuth provider again.
+type ReauthRequiredResponse struct {
+	Error  string `json:"error"`
+	Reauth string `json:"reauth"`
+}
+
+// RequireRecentAuth guards sensitive actions such as changing credentials or
+// deleting the account. On top of Required it demands that the session was
+// confirmed within the configured reauthentication window.
+func (a authorization) RequireRecentAuth(next http.HandlerFunc) http.HandlerFunc {
+	return a.Required(func(w http.ResponseWriter, r *http.Request) {
+		session := GetSessionFromContext(r)
+		if session != nil && IsRecentlyAuthenticated(session, a.reauthWindow, time.Now()) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		method := ReauthMethodPassword
+		user := GetUserFromContext(r)
+		if user != nil && user.Password == "" {
+			method = ReauthMethodOAuth
+		}
+
+		helpers.RespondWithJSON(w, http.StatusForbidden, nil, ReauthRequiredResponse{
+			Error:  "Please confirm your identity to continue",
+			Reauth: method,
+		})
+	})
+}
+
+func IsRecentlyAuthenticated(session *session.Session, window time.Duration, now time.Time) bool {
+	if session.AuthenticatedAt.IsZero() {
+		return false
+	}
+	return now.Sub(session.AuthenticatedAt) <= window
+}
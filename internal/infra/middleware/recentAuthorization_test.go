@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/arnald/forum/internal/domain/session"
+	"github.com/arnald/forum/internal/domain/user"
+	testhelpers "github.com/arnald/forum/internal/pkg/testing"
+)
+
+func TestAuthorization_RequireRecentAuth(t *testing.T) {
+	t.Run("group: require recent auth", func(t *testing.T) {
+		testCases := newRequireRecentAuthTestCases()
+		for _, tt := range testCases {
+			t.Run(tt.name, runRequireRecentAuthTest(tt))
+		}
+	})
+}
+
+type requireRecentAuthTestCase struct {
+	authenticatedAt time.Time
+	sessionErr      error
+	name            string
+	passwordHash    string
+	wantReauth      string
+	wantStatus      int
+	wantNextCalled  bool
+}
+
+func newRequireRecentAuthTestCases() []requireRecentAuthTestCase {
+	return []requireRecentAuthTestCase{
+		{
+			name:            "password entered inside the window",
+			authenticatedAt: time.Now().Add(-time.Minute),
+			passwordHash:    "hash",
+			wantStatus:      http.StatusOK,
+			wantNextCalled:  true,
+		},
+		{
+			name:            "password entered before the window",
+			authenticatedAt: time.Now().Add(-time.Hour),
+			passwordHash:    "hash",
+			wantStatus:      http.StatusForbidden,
+			wantReauth:      ReauthMethodPassword,
+		},
+		{
+			name:         "refreshed session was never confirmed",
+			passwordHash: "hash",
+			wantStatus:   http.StatusForbidden,
+			wantReauth:   ReauthMethodPassword,
+		},
+		{
+			name:            "oauth account is sent back to its provider",
+			authenticatedAt: time.Now().Add(-time.Hour),
+			wantStatus:      http.StatusForbidden,
+			wantReauth:      ReauthMethodOAuth,
+		},
+		{
+			name:       "missing session is unauthorized",
+			sessionErr: testhelpers.ErrTest,
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+}
+
+func runRequireRecentAuthTest(tt requireRecentAuthTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		sessions := &testhelpers.MockSessionManager{
+			GetSessionFromSessionTokensFunc: func(_, _ string) (*session.Session, error) {
+				if tt.sessionErr != nil {
+					return nil, tt.sessionErr
+				}
+				return &session.Session{
+					AccessToken:        "token",
+					Expiry:             time.Now().Add(time.Hour),
+					RefreshTokenExpiry: time.Now().Add(time.Hour),
+					AuthenticatedAt:    tt.authenticatedAt,
+				}, nil
+			},
+			GetUserFromSessionFunc: func(_ string) (*user.User, error) {
+				return &user.User{ID: "test-user-id", Password: tt.passwordHash}, nil
+			},
+		}
+
+		nextCalled := false
+		next := func(w http.ResponseWriter, _ *http.Request) {
+			nextCalled = true
+			w.WriteHeader(http.StatusOK)
+		}
+
+		gate := NewAuthorizationMiddleware(sessions, 10*time.Minute).RequireRecentAuth(next)
+
+		rec := httptest.NewRecorder()
+		gate(rec, httptest.NewRequest(http.MethodPost, "/api/v1/account/delete", nil))
+
+		if rec.Code != tt.wantStatus {
+			t.Fatalf("RequireRecentAuth() status = %d, want %d", rec.Code, tt.wantStatus)
+		}
+		if nextCalled != tt.wantNextCalled {
+			t.Errorf("RequireRecentAuth() next called = %v, want %v", nextCalled, tt.wantNextCalled)
+		}
+		if tt.wantReauth == "" {
+			return
+		}
+
+		var got ReauthRequiredResponse
+		err := json.NewDecoder(rec.Body).Decode(&got)
+		if err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if got.Reauth != tt.wantReauth {
+			t.Errorf("RequireRecentAuth() reauth = %q, want %q", got.Reauth, tt.wantReauth)
+		}
+	}
+}
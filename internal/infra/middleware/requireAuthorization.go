@@ -62,6 +62,7 @@ func (a authorization) Required(next http.HandlerFunc) http.HandlerFunc {
 		}
 
 		ctx := context.WithValue(r.Context(), userIDKey, user)
+		ctx = context.WithValue(ctx, sessionKey, session)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
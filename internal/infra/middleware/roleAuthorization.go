@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/arnald/forum/internal/pkg/helpers"
+)
+
+// RequireModerator guards the moderation queue. On top of Required it only
+// lets moderators and admins through.
+func (a authorization) RequireModerator(next http.HandlerFunc) http.HandlerFunc {
+	return a.Required(func(w http.ResponseWriter, r *http.Request) {
+		user := GetUserFromContext(r)
+		if user == nil || !user.IsModerator() {
+			helpers.RespondWithError(w, http.StatusForbidden, "Moderator access required")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireAdmin guards site administration. On top of Required it only lets
+// admins through.
+func (a authorization) RequireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return a.Required(func(w http.ResponseWriter, r *http.Request) {
+		user := GetUserFromContext(r)
+		if user == nil || !user.IsAdmin() {
+			helpers.RespondWithError(w, http.StatusForbidden, "Admin access required")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -1,15 +1,20 @@
 package middleware
 
 import (
+	"time"
+
+	"github.com/arnald/forum/internal/config"
 	"github.com/arnald/forum/internal/domain/session"
 )
 
 type Middleware struct {
 	Authorization Authorization
+	UserRateLimit *UserRateLimit
 }
 
-func NewMiddleware(sessionManager session.Manager) *Middleware {
+func NewMiddleware(sessionManager session.Manager, reauthWindow time.Duration, rateLimit config.RateLimitConfig) *Middleware {
 	return &Middleware{
-		Authorization: NewAuthorizationMiddleware(sessionManager),
+		Authorization: NewAuthorizationMiddleware(sessionManager, reauthWindow),
+		UserRateLimit: NewUserRateLimit(rateLimit),
 	}
 }
@@ -2,14 +2,16 @@ package middleware
 
 import (
 	"testing"
+	"time"
 
+	"github.com/arnald/forum/internal/config"
 	testhelpers "github.com/arnald/forum/internal/pkg/testing"
 )
 
 func TestServices(t *testing.T) {
 	mockSessionManager := &testhelpers.MockSessionManager{}
 
-	middleware := NewMiddleware(mockSessionManager)
+	middleware := NewMiddleware(mockSessionManager, time.Minute, config.RateLimitConfig{})
 
 	auth := middleware.Authorization
 
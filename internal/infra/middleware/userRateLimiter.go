@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/infra/middleware/ratelimiter"
+	"github.com/arnald/forum/internal/pkg/helpers"
+)
+
+// UserRateLimit gives each signed-in user their own budget for posting,
+// layered under the per-IP limiter: users behind one NAT no longer throttle
+// each other, and switching addresses does not reset anyone's budget.
+type UserRateLimit struct {
+	limiter ratelimiter.Limiter
+}
+
+// NewUserRateLimit builds the per-user limiter from cfg. It lets everything
+// through when rate limiting is off or cfg.UserRequestsLimit is 0.
+func NewUserRateLimit(cfg config.RateLimitConfig) *UserRateLimit {
+	if !cfg.Enabled || cfg.UserRequestsLimit <= 0 {
+		return &UserRateLimit{}
+	}
+
+	return &UserRateLimit{
+		limiter: ratelimiter.NewRateLimiter(cfg.UserRequestsLimit, cfg.UserWindowSeconds, cfg.Cleanup),
+	}
+}
+
+// Limit counts POSTs by the user in the request context against their budget
+// and answers 429 once it is spent. It must run inside Authorization.Required.
+func (u *UserRateLimit) Limit(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := GetUserFromContext(r)
+		if u.limiter == nil || user == nil || r.Method != http.MethodPost {
+			next(w, r)
+			return
+		}
+
+		allowed, remaining, resetTime := u.limiter.Allow(user.ID)
+		SetRateLimitHeaders(w, u.limiter, allowed, remaining, resetTime)
+		if !allowed {
+			helpers.RespondWithError(w, http.StatusTooManyRequests, "Posting limit reached, try again later")
+			return
+		}
+
+		next(w, r)
+	}
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/domain/user"
+)
+
+func TestUserRateLimit_Limit(t *testing.T) {
+	cfg := config.RateLimitConfig{
+		Enabled:           true,
+		RequestsLimit:     100,
+		WindowSeconds:     60,
+		Cleanup:           time.Minute,
+		UserRequestsLimit: 2,
+		UserWindowSeconds: 60,
+	}
+
+	// newPoster returns a func that POSTs as u from ip through the IP limiter
+	// and then the per-user one, the way the server layers them.
+	newPoster := func() func(u *user.User, ip string) int {
+		userLimit := NewUserRateLimit(cfg)
+		next := func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusCreated) }
+		handler := NewRateLimiterMiddleware(userLimit.Limit(next), cfg)
+
+		return func(u *user.User, ip string) int {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/comments/create", nil)
+			req.Header.Set("X-Forwarded-For", ip)
+			if u != nil {
+				req = req.WithContext(context.WithValue(req.Context(), userIDKey, u))
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			return rec.Code
+		}
+	}
+
+	alice := &user.User{ID: "alice"}
+	bob := &user.User{ID: "bob"}
+
+	t.Run("user over budget is throttled from a fresh IP", func(t *testing.T) {
+		post := newPoster()
+
+		for i := range 2 {
+			if got := post(alice, "1.1.1.1"); got != http.StatusCreated {
+				t.Fatalf("post %d status = %d, want %d", i+1, got, http.StatusCreated)
+			}
+		}
+		if got := post(alice, "9.9.9.9"); got != http.StatusTooManyRequests {
+			t.Errorf("post from fresh IP status = %d, want %d", got, http.StatusTooManyRequests)
+		}
+	})
+
+	t.Run("users sharing an IP keep separate budgets", func(t *testing.T) {
+		post := newPoster()
+
+		post(alice, "1.1.1.1")
+		post(alice, "1.1.1.1")
+		if got := post(bob, "1.1.1.1"); got != http.StatusCreated {
+			t.Errorf("other user on the same IP status = %d, want %d", got, http.StatusCreated)
+		}
+	})
+
+	t.Run("disabled budget lets posts through", func(t *testing.T) {
+		off := cfg
+		off.UserRequestsLimit = 0
+		userLimit := NewUserRateLimit(off)
+		called := 0
+		handler := userLimit.Limit(func(_ http.ResponseWriter, _ *http.Request) { called++ })
+
+		for range 5 {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req = req.WithContext(context.WithValue(req.Context(), userIDKey, alice))
+			handler(httptest.NewRecorder(), req)
+		}
+		if called != 5 {
+			t.Errorf("handler called %d times, want 5", called)
+		}
+	})
+}
@@ -3,7 +3,9 @@ package notifications
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/arnald/forum/internal/domain/notification"
 )
@@ -51,9 +53,267 @@ func (r *Repo) Create(ctx context.Context, notification *notification.Notificati
 	return nil
 }
 
+// CreateBatch stores several notifications in one transaction, so a burst
+// of fan-out notifications costs a single commit.
+func (r *Repo) CreateBatch(ctx context.Context, notifications []*notification.Notification) (err error) {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				err = fmt.Errorf("transaction rollback failed: %w (original error: %w)", rollbackErr, err)
+			}
+			return
+		}
+		err = tx.Commit()
+		if err != nil {
+			err = fmt.Errorf("transaction commit failed: %w", err)
+		}
+	}()
+
+	stmt, err := tx.PrepareContext(ctx, `
+	INSERT INTO notifications (user_id, type, title, message, related_type, related_id, is_read)
+	VALUES (?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, n := range notifications {
+		result, execErr := stmt.ExecContext(
+			ctx,
+			n.UserID,
+			n.Type,
+			n.Title,
+			n.Message,
+			n.RelatedType,
+			n.RelatedID,
+			n.IsRead,
+		)
+		if execErr != nil {
+			return fmt.Errorf("failed to execute query: %w", execErr)
+		}
+
+		id, idErr := result.LastInsertId()
+		if idErr != nil {
+			return idErr
+		}
+		n.ID = int(id)
+	}
+
+	return nil
+}
+
+// CreateCoalescedBatch stores watched-topic notifications, whose RelatedID is
+// the topic id, without flooding watchers during a burst of comments. While
+// a recipient still has an unread one for the topic created within window,
+// that one has its count bumped and its message refreshed instead of a new
+// row being added. The time of the last new row per (user, topic) is kept on
+// the watch. Only newly created notifications are returned. A window of zero
+// turns coalescing off.
+func (r *Repo) CreateCoalescedBatch(ctx context.Context, notifications []*notification.Notification, window time.Duration) (created []*notification.Notification, err error) {
+	if window <= 0 {
+		return notifications, r.CreateBatch(ctx, notifications)
+	}
+	if len(notifications) == 0 {
+		return nil, nil
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				err = fmt.Errorf("transaction rollback failed: %w (original error: %w)", rollbackErr, err)
+			}
+			return
+		}
+		err = tx.Commit()
+		if err != nil {
+			err = fmt.Errorf("transaction commit failed: %w", err)
+		}
+	}()
+
+	since := fmt.Sprintf("-%d seconds", int(window.Seconds()))
+	created = make([]*notification.Notification, 0, len(notifications))
+
+	for _, n := range notifications {
+		var existingID int
+		findErr := tx.QueryRowContext(ctx, `
+		SELECT n.id
+		FROM notifications n
+		JOIN thread_watches w ON w.user_id = n.user_id AND w.topic_id = n.related_id
+		WHERE n.user_id = ? AND n.type = ? AND n.related_id = ? AND n.is_read = 0
+			AND w.last_notified_at > datetime('now', ?)
+			AND n.created_at >= w.last_notified_at
+		ORDER BY n.id DESC
+		LIMIT 1`,
+			n.UserID,
+			n.Type,
+			n.RelatedID,
+			since,
+		).Scan(&existingID)
+
+		switch {
+		case findErr == nil:
+			bumpErr := tx.QueryRowContext(ctx, `
+			UPDATE notifications
+			SET count = count + 1, message = ?
+			WHERE id = ?
+			RETURNING count`,
+				n.Message,
+				existingID,
+			).Scan(&n.Count)
+			if bumpErr != nil {
+				return nil, fmt.Errorf("failed to coalesce notification: %w", bumpErr)
+			}
+			n.ID = existingID
+			continue
+		case !errors.Is(findErr, sql.ErrNoRows):
+			return nil, fmt.Errorf("failed to look up notification: %w", findErr)
+		}
+
+		result, execErr := tx.ExecContext(ctx, `
+		INSERT INTO notifications (user_id, type, title, message, related_type, related_id, is_read)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			n.UserID,
+			n.Type,
+			n.Title,
+			n.Message,
+			n.RelatedType,
+			n.RelatedID,
+			n.IsRead,
+		)
+		if execErr != nil {
+			return nil, fmt.Errorf("failed to execute query: %w", execErr)
+		}
+
+		id, idErr := result.LastInsertId()
+		if idErr != nil {
+			return nil, idErr
+		}
+		n.ID = int(id)
+		n.Count = 1
+
+		_, execErr = tx.ExecContext(ctx, `
+		UPDATE thread_watches
+		SET last_notified_at = CURRENT_TIMESTAMP
+		WHERE user_id = ? AND topic_id = ?`,
+			n.UserID,
+			n.RelatedID,
+		)
+		if execErr != nil {
+			return nil, fmt.Errorf("failed to record last notification: %w", execErr)
+		}
+
+		created = append(created, n)
+	}
+
+	return created, nil
+}
+
+// CreateFolded stores a like or dislike notification. While the recipient
+// still has an unread one of the same type about the same content, created
+// within window, that one has its count bumped and its message rephrased
+// instead, and false is returned. A window of zero turns folding off.
+func (r *Repo) CreateFolded(ctx context.Context, n *notification.Notification, window time.Duration) (created bool, err error) {
+	if window <= 0 {
+		return true, r.Create(ctx, n)
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				err = fmt.Errorf("transaction rollback failed: %w (original error: %w)", rollbackErr, err)
+			}
+			return
+		}
+		err = tx.Commit()
+		if err != nil {
+			err = fmt.Errorf("transaction commit failed: %w", err)
+		}
+	}()
+
+	var existingID, count int
+	err = tx.QueryRowContext(ctx, `
+	SELECT id, count
+	FROM notifications
+	WHERE user_id = ? AND type = ? AND related_type = ? AND related_id = ? AND is_read = 0
+		AND created_at > datetime('now', ?)
+	ORDER BY id DESC
+	LIMIT 1`,
+		n.UserID,
+		n.Type,
+		n.RelatedType,
+		n.RelatedID,
+		fmt.Sprintf("-%d seconds", int(window.Seconds())),
+	).Scan(&existingID, &count)
+
+	switch {
+	case err == nil:
+		n.ID = existingID
+		n.Count = count + 1
+		n.Message = n.VoteMessage(n.Count)
+		_, err = tx.ExecContext(ctx, `
+		UPDATE notifications
+		SET count = ?, message = ?
+		WHERE id = ?`,
+			n.Count,
+			n.Message,
+			existingID,
+		)
+		if err != nil {
+			return false, fmt.Errorf("failed to fold notification: %w", err)
+		}
+		return false, nil
+	case !errors.Is(err, sql.ErrNoRows):
+		return false, fmt.Errorf("failed to look up notification: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `
+	INSERT INTO notifications (user_id, type, title, message, related_type, related_id, is_read)
+	VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		n.UserID,
+		n.Type,
+		n.Title,
+		n.Message,
+		n.RelatedType,
+		n.RelatedID,
+		n.IsRead,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return false, err
+	}
+	n.ID = int(id)
+	n.Count = 1
+
+	return true, nil
+}
+
 func (r *Repo) GetByUserID(ctx context.Context, userID string, limit int) ([]*notification.Notification, error) {
 	query := `
-	SELECT id, user_id, type, title, message, related_type, related_id, is_read, created_at
+	SELECT id, user_id, type, title, message, related_type, related_id, is_read, count, created_at
 	FROM notifications
 	WHERE user_id = ?
 	ORDER BY created_at DESC
@@ -88,6 +348,7 @@ func (r *Repo) GetByUserID(ctx context.Context, userID string, limit int) ([]*no
 			&n.RelatedType,
 			&n.RelatedID,
 			&n.IsRead,
+			&n.Count,
 			&n.CreatedAt,
 		)
 		if err != nil {
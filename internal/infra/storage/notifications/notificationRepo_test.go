@@ -0,0 +1,215 @@
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/arnald/forum/internal/domain/notification"
+	"github.com/arnald/forum/internal/pkg/path"
+)
+
+const testWindow = 10 * time.Minute
+
+// newTestRepo returns a repository backed by a private in-memory database
+// with the project schema applied and "watcher" watching topic 1.
+func newTestRepo(t *testing.T) *Repo {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	// Every connection to :memory: gets its own database, so keep just one.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	schema, err := os.ReadFile(path.NewResolver().GetPath("db/migrations/schema.sql"))
+	if err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+	_, err = db.Exec(string(schema))
+	if err != nil {
+		t.Fatalf("failed to apply schema: %v", err)
+	}
+
+	_, err = db.Exec(`
+	INSERT INTO users (id, username, email, password_hash) VALUES
+		('owner', 'owner', 'owner@example.com', 'x'),
+		('watcher', 'watcher', 'watcher@example.com', 'x');
+	INSERT INTO topics (id, user_id, title, content) VALUES (1, 'owner', 'Topic', 'Content');
+	INSERT INTO thread_watches (user_id, topic_id) VALUES ('watcher', 1);`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+
+	return NewRepo(db)
+}
+
+func watchedComment(message string) []*notification.Notification {
+	return []*notification.Notification{{
+		UserID:      "watcher",
+		Type:        notification.NotificationTypeWatchedComment,
+		Title:       "New comment on a watched topic",
+		Message:     message,
+		RelatedType: "topic",
+		RelatedID:   "1",
+	}}
+}
+
+func TestRepo_CreateCoalescedBatch(t *testing.T) {
+	t.Run("group: coalescing window", func(t *testing.T) {
+		t.Run("comments inside the window bump one notification", func(t *testing.T) {
+			repo := newTestRepo(t)
+			ctx := context.Background()
+
+			for _, message := range []string{"first", "second", "third"} {
+				_, err := repo.CreateCoalescedBatch(ctx, watchedComment(message), testWindow)
+				if err != nil {
+					t.Fatalf("CreateCoalescedBatch() error = %v", err)
+				}
+			}
+
+			listed, err := repo.GetByUserID(ctx, "watcher", 10)
+			if err != nil {
+				t.Fatalf("GetByUserID() error = %v", err)
+			}
+			if len(listed) != 1 {
+				t.Fatalf("GetByUserID() returned %d notifications, want 1", len(listed))
+			}
+			if listed[0].Count != 3 || listed[0].Message != "third" {
+				t.Errorf("notification count = %d, message = %q, want 3 and %q", listed[0].Count, listed[0].Message, "third")
+			}
+		})
+
+		t.Run("only new notifications are returned", func(t *testing.T) {
+			repo := newTestRepo(t)
+			ctx := context.Background()
+
+			created, err := repo.CreateCoalescedBatch(ctx, watchedComment("first"), testWindow)
+			if err != nil {
+				t.Fatalf("CreateCoalescedBatch() error = %v", err)
+			}
+			if len(created) != 1 {
+				t.Errorf("first call created %d notifications, want 1", len(created))
+			}
+
+			created, err = repo.CreateCoalescedBatch(ctx, watchedComment("second"), testWindow)
+			if err != nil {
+				t.Fatalf("CreateCoalescedBatch() error = %v", err)
+			}
+			if len(created) != 0 {
+				t.Errorf("coalesced call created %d notifications, want 0", len(created))
+			}
+		})
+
+		t.Run("a comment after the window starts a new notification", func(t *testing.T) {
+			repo := newTestRepo(t)
+			ctx := context.Background()
+
+			_, err := repo.CreateCoalescedBatch(ctx, watchedComment("first"), testWindow)
+			if err != nil {
+				t.Fatalf("CreateCoalescedBatch() error = %v", err)
+			}
+			_, err = repo.DB.Exec(`UPDATE thread_watches SET last_notified_at = datetime('now', '-1 hour')`)
+			if err != nil {
+				t.Fatalf("failed to age the watch: %v", err)
+			}
+
+			created, err := repo.CreateCoalescedBatch(ctx, watchedComment("second"), testWindow)
+			if err != nil {
+				t.Fatalf("CreateCoalescedBatch() error = %v", err)
+			}
+			if len(created) != 1 {
+				t.Errorf("CreateCoalescedBatch() created %d notifications, want 1", len(created))
+			}
+		})
+
+		t.Run("a read notification is not bumped", func(t *testing.T) {
+			repo := newTestRepo(t)
+			ctx := context.Background()
+
+			_, err := repo.CreateCoalescedBatch(ctx, watchedComment("first"), testWindow)
+			if err != nil {
+				t.Fatalf("CreateCoalescedBatch() error = %v", err)
+			}
+			err = repo.MarkAllAsRead(ctx, "watcher")
+			if err != nil {
+				t.Fatalf("MarkAllAsRead() error = %v", err)
+			}
+
+			created, err := repo.CreateCoalescedBatch(ctx, watchedComment("second"), testWindow)
+			if err != nil {
+				t.Fatalf("CreateCoalescedBatch() error = %v", err)
+			}
+			if len(created) != 1 {
+				t.Errorf("CreateCoalescedBatch() created %d notifications, want 1", len(created))
+			}
+		})
+
+		t.Run("a zero window never coalesces", func(t *testing.T) {
+			repo := newTestRepo(t)
+			ctx := context.Background()
+
+			for _, message := range []string{"first", "second"} {
+				_, err := repo.CreateCoalescedBatch(ctx, watchedComment(message), 0)
+				if err != nil {
+					t.Fatalf("CreateCoalescedBatch() error = %v", err)
+				}
+			}
+
+			count, err := repo.GetUnreadCount(ctx, "watcher")
+			if err != nil {
+				t.Fatalf("GetUnreadCount() error = %v", err)
+			}
+			if count != 2 {
+				t.Errorf("GetUnreadCount() = %d, want 2", count)
+			}
+		})
+	})
+}
+
+func TestRepo_GetUnreadCount(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	unread := func() int {
+		t.Helper()
+		count, err := repo.GetUnreadCount(ctx, "watcher")
+		if err != nil {
+			t.Fatalf("GetUnreadCount() error = %v", err)
+		}
+		return count
+	}
+
+	if got := unread(); got != 0 {
+		t.Errorf("GetUnreadCount() with no notifications = %d, want 0", got)
+	}
+
+	batch := append(watchedComment("first"), watchedComment("second")...)
+	batch = append(batch, &notification.Notification{
+		UserID:  "owner",
+		Type:    notification.NotificationTypeReply,
+		Title:   "Reply",
+		Message: "someone else's notification",
+	})
+	err := repo.CreateBatch(ctx, batch)
+	if err != nil {
+		t.Fatalf("CreateBatch() error = %v", err)
+	}
+	if got := unread(); got != 2 {
+		t.Errorf("GetUnreadCount() = %d, want 2", got)
+	}
+
+	err = repo.MarkAllAsRead(ctx, "watcher")
+	if err != nil {
+		t.Fatalf("MarkAllAsRead() error = %v", err)
+	}
+	if got := unread(); got != 0 {
+		t.Errorf("GetUnreadCount() after MarkAllAsRead() = %d, want 0", got)
+	}
+}
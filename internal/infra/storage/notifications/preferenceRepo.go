@@ -0,0 +1,89 @@
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/arnald/forum/internal/domain/notification"
+)
+
+// IsEnabled reports whether the user receives notifications of the given
+// type. Types the user never touched are on.
+func (r *Repo) IsEnabled(ctx context.Context, userID string, notificationType notification.Type) (bool, error) {
+	query := `
+	SELECT enabled
+	FROM notification_preferences
+	WHERE user_id = ? AND type = ?`
+
+	var enabled bool
+	err := r.DB.QueryRowContext(ctx, query, userID, notificationType).Scan(&enabled)
+	if errors.Is(err, sql.ErrNoRows) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to query preference: %w", err)
+	}
+
+	return enabled, nil
+}
+
+// GetPreferences returns the user's setting for every optional type, in the
+// order of notification.OptionalTypes.
+func (r *Repo) GetPreferences(ctx context.Context, userID string) ([]notification.Preference, error) {
+	query := `
+	SELECT type, enabled
+	FROM notification_preferences
+	WHERE user_id = ?`
+
+	rows, err := r.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query preferences: %w", err)
+	}
+	defer rows.Close()
+
+	stored := make(map[notification.Type]bool)
+	for rows.Next() {
+		var (
+			notificationType notification.Type
+			enabled          bool
+		)
+		err = rows.Scan(&notificationType, &enabled)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan preference: %w", err)
+		}
+		stored[notificationType] = enabled
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("error iterating preferences: %w", err)
+	}
+
+	preferences := make([]notification.Preference, 0, len(notification.OptionalTypes))
+	for _, notificationType := range notification.OptionalTypes {
+		enabled, ok := stored[notificationType]
+		preferences = append(preferences, notification.Preference{
+			Type:    notificationType,
+			Enabled: enabled || !ok,
+		})
+	}
+
+	return preferences, nil
+}
+
+// SetPreference switches one notification type on or off for the user.
+func (r *Repo) SetPreference(ctx context.Context, userID string, preference notification.Preference) error {
+	query := `
+	INSERT INTO notification_preferences (user_id, type, enabled)
+	VALUES (?, ?, ?)
+	ON CONFLICT (user_id, type) DO UPDATE SET enabled = excluded.enabled`
+
+	_, err := r.DB.ExecContext(ctx, query, userID, preference.Type, preference.Enabled)
+	if err != nil {
+		return fmt.Errorf("failed to save preference: %w", err)
+	}
+
+	return nil
+}
@@ -3,23 +3,33 @@ package notifications
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"sync"
+	"time"
 
 	"github.com/arnald/forum/internal/domain/notification"
 )
 
 const ChannelCapacity int = 10
 
+// ErrNotOptional is returned when a user tries to switch off a notification
+// type that always gets delivered.
+var ErrNotOptional = errors.New("notification type cannot be switched off")
+
 type NotificationService struct {
 	repo    notification.Repository
 	clients map[string][]chan *notification.Notification
-	mu      sync.RWMutex
+	// voteWindow is how long an unread like or dislike notification keeps
+	// absorbing further votes on the same content.
+	voteWindow time.Duration
+	mu         sync.RWMutex
 }
 
-func NewNotificationService(db *sql.DB) *NotificationService {
+func NewNotificationService(db *sql.DB, voteWindow time.Duration) *NotificationService {
 	return &NotificationService{
-		repo:    NewRepo(db),
-		clients: make(map[string][]chan *notification.Notification),
+		repo:       NewRepo(db),
+		clients:    make(map[string][]chan *notification.Notification),
+		voteWindow: voteWindow,
 	}
 }
 
@@ -51,8 +61,28 @@ func (s *NotificationService) UnregisterClient(userID string, ch chan *notificat
 	}
 }
 
+// CreateNotification stores and pushes the notification, unless its
+// recipient switched its type off. Likes and dislikes fold into a recent
+// unread one about the same content; a folded one is not pushed again.
 func (s *NotificationService) CreateNotification(ctx context.Context, notification *notification.Notification) error {
-	err := s.repo.Create(ctx, notification)
+	enabled, err := s.repo.IsEnabled(ctx, notification.UserID, notification.Type)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return nil
+	}
+
+	if notification.Type.Folds() {
+		created, foldErr := s.repo.CreateFolded(ctx, notification, s.voteWindow)
+		if foldErr != nil || !created {
+			return foldErr
+		}
+		s.broadcastToUser(notification.UserID, notification)
+		return nil
+	}
+
+	err = s.repo.Create(ctx, notification)
 	if err != nil {
 		return err
 	}
@@ -62,6 +92,53 @@ func (s *NotificationService) CreateNotification(ctx context.Context, notificati
 	return nil
 }
 
+// CreateNotifications stores a batch of notifications together and then
+// pushes each one to its recipient.
+func (s *NotificationService) CreateNotifications(ctx context.Context, notifications []*notification.Notification) error {
+	notifications, err := s.enabledOnly(ctx, notifications)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.CreateBatch(ctx, notifications)
+	if err != nil {
+		return err
+	}
+
+	for _, n := range notifications {
+		s.broadcastToUser(n.UserID, n)
+	}
+
+	return nil
+}
+
+// CreateCoalescedNotifications stores watched-topic notifications, folding
+// any that land within window of an unread one for the same topic into it.
+// Only the new ones are pushed; a coalesced one is already on screen.
+func (s *NotificationService) CreateCoalescedNotifications(ctx context.Context, notifications []*notification.Notification, window time.Duration) error {
+	notifications, err := s.enabledOnly(ctx, notifications)
+	if err != nil {
+		return err
+	}
+
+	created, err := s.repo.CreateCoalescedBatch(ctx, notifications, window)
+	if err != nil {
+		return err
+	}
+
+	for _, n := range created {
+		s.broadcastToUser(n.UserID, n)
+	}
+
+	return nil
+}
+
+// Deliver pushes a notification that was stored elsewhere, such as alongside
+// a moderation decision, to its recipient's open streams.
+func (s *NotificationService) Deliver(notification *notification.Notification) {
+	s.broadcastToUser(notification.UserID, notification)
+}
+
 func (s *NotificationService) GetNotifications(ctx context.Context, userID string, limit int) ([]*notification.Notification, error) {
 	return s.repo.GetByUserID(ctx, userID, limit)
 }
@@ -78,6 +155,36 @@ func (s *NotificationService) MarkAllAsRead(ctx context.Context, userID string)
 	return s.repo.MarkAllAsRead(ctx, userID)
 }
 
+// GetPreferences lists which optional notification types the user receives.
+func (s *NotificationService) GetPreferences(ctx context.Context, userID string) ([]notification.Preference, error) {
+	return s.repo.GetPreferences(ctx, userID)
+}
+
+// SetPreference switches an optional notification type on or off for the
+// user.
+func (s *NotificationService) SetPreference(ctx context.Context, userID string, preference notification.Preference) error {
+	if !preference.Type.Optional() {
+		return ErrNotOptional
+	}
+	return s.repo.SetPreference(ctx, userID, preference)
+}
+
+// enabledOnly drops the notifications whose recipients switched their type
+// off.
+func (s *NotificationService) enabledOnly(ctx context.Context, notifications []*notification.Notification) ([]*notification.Notification, error) {
+	kept := make([]*notification.Notification, 0, len(notifications))
+	for _, n := range notifications {
+		enabled, err := s.repo.IsEnabled(ctx, n.UserID, n.Type)
+		if err != nil {
+			return nil, err
+		}
+		if enabled {
+			kept = append(kept, n)
+		}
+	}
+	return kept, nil
+}
+
 func (s *NotificationService) broadcastToUser(userID string, notification *notification.Notification) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -0,0 +1,150 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/arnald/forum/internal/domain/notification"
+)
+
+func TestNotificationService_Preferences(t *testing.T) {
+	repo := newTestRepo(t)
+	service := NewNotificationService(repo.DB, 0)
+	ctx := context.Background()
+
+	err := service.SetPreference(ctx, "owner", notification.Preference{Type: notification.NotificationTypeLike, Enabled: false})
+	if err != nil {
+		t.Fatalf("SetPreference() error = %v", err)
+	}
+
+	newNotification := func(notificationType notification.Type) *notification.Notification {
+		return &notification.Notification{
+			UserID:  "owner",
+			Type:    notificationType,
+			Title:   string(notificationType),
+			Message: "message",
+		}
+	}
+
+	err = service.CreateNotification(ctx, newNotification(notification.NotificationTypeLike))
+	if err != nil {
+		t.Fatalf("CreateNotification(like) error = %v", err)
+	}
+	err = service.CreateNotification(ctx, newNotification(notification.NotificationTypeReply))
+	if err != nil {
+		t.Fatalf("CreateNotification(reply) error = %v", err)
+	}
+	err = service.CreateNotifications(ctx, []*notification.Notification{
+		newNotification(notification.NotificationTypeLike),
+		newNotification(notification.NotificationTypeDislike),
+	})
+	if err != nil {
+		t.Fatalf("CreateNotifications() error = %v", err)
+	}
+
+	stored, err := service.GetNotifications(ctx, "owner", 10)
+	if err != nil {
+		t.Fatalf("GetNotifications() error = %v", err)
+	}
+	got := make(map[notification.Type]int)
+	for _, n := range stored {
+		got[n.Type]++
+	}
+	if got[notification.NotificationTypeLike] != 0 {
+		t.Errorf("stored %d like notifications, want none once likes are off", got[notification.NotificationTypeLike])
+	}
+	if got[notification.NotificationTypeReply] != 1 || got[notification.NotificationTypeDislike] != 1 {
+		t.Errorf("stored types = %v, want one reply and one dislike", got)
+	}
+
+	preferences, err := service.GetPreferences(ctx, "owner")
+	if err != nil {
+		t.Fatalf("GetPreferences() error = %v", err)
+	}
+	if len(preferences) != len(notification.OptionalTypes) {
+		t.Fatalf("GetPreferences() returned %d preferences, want %d", len(preferences), len(notification.OptionalTypes))
+	}
+	for _, p := range preferences {
+		if want := p.Type != notification.NotificationTypeLike; p.Enabled != want {
+			t.Errorf("preference %s enabled = %v, want %v", p.Type, p.Enabled, want)
+		}
+	}
+
+	err = service.SetPreference(ctx, "owner", notification.Preference{Type: notification.NotificationTypeModeration, Enabled: false})
+	if !errors.Is(err, ErrNotOptional) {
+		t.Errorf("SetPreference(moderation) error = %v, want %v", err, ErrNotOptional)
+	}
+}
+
+func TestNotificationService_FoldsVotes(t *testing.T) {
+	repo := newTestRepo(t)
+	service := NewNotificationService(repo.DB, testWindow)
+	ctx := context.Background()
+
+	like := func(actor string) error {
+		n := &notification.Notification{
+			UserID:      "owner",
+			Type:        notification.NotificationTypeLike,
+			Title:       "New like!",
+			ActorName:   actor,
+			RelatedType: "topic",
+			RelatedID:   "1",
+		}
+		n.Message = n.VoteMessage(1)
+		return service.CreateNotification(ctx, n)
+	}
+
+	for _, actor := range []string{"alice", "bob", "carol"} {
+		err := like(actor)
+		if err != nil {
+			t.Fatalf("CreateNotification(%s) error = %v", actor, err)
+		}
+	}
+
+	stored, err := service.GetNotifications(ctx, "owner", 10)
+	if err != nil {
+		t.Fatalf("GetNotifications() error = %v", err)
+	}
+	if len(stored) != 1 {
+		t.Fatalf("stored %d notifications, want 1 folded one", len(stored))
+	}
+	if stored[0].Count != 3 || stored[0].Message != "carol and 2 others liked your topic" {
+		t.Errorf("folded notification = %d/%q, want 3/%q", stored[0].Count, stored[0].Message, "carol and 2 others liked your topic")
+	}
+
+	// Once read, the next like starts a fresh notification.
+	err = service.MarkAllAsRead(ctx, "owner")
+	if err != nil {
+		t.Fatalf("MarkAllAsRead() error = %v", err)
+	}
+	err = like("dave")
+	if err != nil {
+		t.Fatalf("CreateNotification(dave) error = %v", err)
+	}
+	unread, err := service.GetUnreadCount(ctx, "owner")
+	if err != nil {
+		t.Fatalf("GetUnreadCount() error = %v", err)
+	}
+	if unread != 1 {
+		t.Errorf("GetUnreadCount() = %d, want 1", unread)
+	}
+}
+
+func TestNotification_VoteMessage(t *testing.T) {
+	n := &notification.Notification{Type: notification.NotificationTypeDislike, ActorName: "alice", RelatedType: "comment"}
+
+	tests := []struct {
+		want  string
+		count int
+	}{
+		{count: 1, want: "alice disliked your comment"},
+		{count: 2, want: "alice and 1 other disliked your comment"},
+		{count: 5, want: "alice and 4 others disliked your comment"},
+	}
+	for _, tt := range tests {
+		if got := n.VoteMessage(tt.count); got != tt.want {
+			t.Errorf("VoteMessage(%d) = %q, want %q", tt.count, got, tt.want)
+		}
+	}
+}
@@ -44,9 +44,21 @@ type tokenGenerator interface {
 }
 
 func (sm *Manager) CreateSession(ctx context.Context, userID string) (*session.Session, error) {
+	return sm.CreateSessionWithTTL(ctx, userID, sm.sessionConfig.DefaultExpiry)
+}
+
+// CreateSessionWithTTL starts a session that lasts ttl instead of the default
+// expiry, as used for "remember me" logins.
+func (sm *Manager) CreateSessionWithTTL(ctx context.Context, userID string, ttl time.Duration) (*session.Session, error) {
+	return sm.insertSession(ctx, userID, ttl, sql.NullTime{})
+}
+
+// insertSession starts a session lasting ttl. A valid authenticatedAt is
+// stored as the time the owner last confirmed their identity.
+func (sm *Manager) insertSession(ctx context.Context, userID string, ttl time.Duration, authenticatedAt sql.NullTime) (*session.Session, error) {
 	query := `
-	INSERT INTO sessions (token, user_id, expires_at, refresh_token, refresh_token_expires_at)
-	VALUES (?, ?, ?, ?, ?)`
+	INSERT INTO sessions (token, user_id, created_at, expires_at, refresh_token, refresh_token_expires_at, authenticated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?)`
 
 	stmt, err := sm.db.PrepareContext(ctx, query)
 	if err != nil {
@@ -57,23 +69,28 @@ func (sm *Manager) CreateSession(ctx context.Context, userID string) (*session.S
 	newSessionToken := sm.tokenGenerator.NewUUID()
 	newrefreshToken := sm.tokenGenerator.NewUUID()
 
-	expiry := time.Now().Add(sm.sessionConfig.DefaultExpiry)
+	// created_at is written alongside expires_at, in the same clock, so a
+	// rotation can read the session's lifetime back.
+	now := time.Now()
+	expiry := now.Add(ttl)
 	refreshExpiry := expiry.Add(sm.sessionConfig.RefreshTokenExpiry)
 
 	_, err = stmt.ExecContext(
 		ctx,
 		newSessionToken,
 		userID,
+		now.Format(SQLDateTime),
 		expiry.Format(SQLDateTime),
 
 		newrefreshToken,
 		refreshExpiry.Format(SQLDateTime),
+		sqlDateTimeOrNull(authenticatedAt),
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	err = sm.DeleteSessionWhenNewCreated(ctx, newSessionToken, userID)
+	err = sm.enforceSessionLimit(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -84,11 +101,19 @@ func (sm *Manager) CreateSession(ctx context.Context, userID string) (*session.S
 		Expiry:             expiry,
 		RefreshToken:       newrefreshToken,
 		RefreshTokenExpiry: refreshExpiry,
+		AuthenticatedAt:    authenticatedAt.Time,
 	}
 
 	return session, nil
 }
 
+func sqlDateTimeOrNull(t sql.NullTime) interface{} {
+	if !t.Valid {
+		return nil
+	}
+	return t.Time.Format(SQLDateTime)
+}
+
 func (sm *Manager) GetSession(sessionID string) (*session.Session, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
 	defer cancel()
@@ -125,7 +150,7 @@ func (sm *Manager) GetSessionFromSessionTokens(sessionToken, refreshToken string
 	defer cancel()
 
 	query := `
-	SELECT token, user_id, expires_at, refresh_token, refresh_token_expires_at
+	SELECT token, user_id, expires_at, refresh_token, refresh_token_expires_at, authenticated_at
 	FROM sessions
 	WHERE token = ? AND refresh_token = ?`
 
@@ -138,9 +163,10 @@ func (sm *Manager) GetSessionFromSessionTokens(sessionToken, refreshToken string
 	row := stmt.QueryRowContext(ctx, sessionToken, refreshToken)
 
 	var session session.Session
+	var authenticatedAt sql.NullTime
 
 	err = row.Scan(&session.AccessToken, &session.UserID, &session.Expiry,
-		&session.RefreshToken, &session.RefreshTokenExpiry)
+		&session.RefreshToken, &session.RefreshTokenExpiry, &authenticatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrSessionNotFound
@@ -148,9 +174,82 @@ func (sm *Manager) GetSessionFromSessionTokens(sessionToken, refreshToken string
 		return nil, err
 	}
 
+	if authenticatedAt.Valid {
+		session.AuthenticatedAt = authenticatedAt.Time
+	}
+
 	return &session, nil
 }
 
+// ConfirmAuthentication records that the session owner has just entered their
+// password or signed in through their OAuth provider.
+func (sm *Manager) ConfirmAuthentication(ctx context.Context, sessionID string) error {
+	query := `UPDATE sessions SET authenticated_at = ? WHERE token = ?`
+
+	stmt, err := sm.db.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("prepare failed: %w", err)
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, time.Now().Format(SQLDateTime), sessionID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// RotateSession trades a refresh token for a new session. The old session is
+// deleted as it is claimed, so its refresh token works only once even when
+// two refreshes race. The new session lasts as long as the old one did, so a
+// "remember me" login stays remembered, and keeps its authentication time: a
+// refresh neither confirms nor forgets a recent login.
+func (sm *Manager) RotateSession(ctx context.Context, refreshToken string) (*session.Session, error) {
+	query := `
+	DELETE FROM sessions
+	WHERE refresh_token = ?
+	RETURNING user_id, refresh_token_expires_at >= ?, authenticated_at,
+		CAST((julianday(expires_at) - julianday(created_at)) * 86400 AS INTEGER)`
+
+	stmt, err := sm.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("prepare failed: %w", err)
+	}
+	defer stmt.Close()
+
+	var userID string
+	var usable bool
+	var authenticatedAt sql.NullTime
+	var lifetimeSeconds sql.NullInt64
+	err = stmt.QueryRowContext(ctx, refreshToken, time.Now().Format(SQLDateTime)).
+		Scan(&userID, &usable, &authenticatedAt, &lifetimeSeconds)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, err
+	}
+	if !usable {
+		return nil, ErrSessionExpired
+	}
+
+	ttl := sm.sessionConfig.DefaultExpiry
+	if lifetimeSeconds.Int64 > 0 {
+		ttl = time.Duration(lifetimeSeconds.Int64) * time.Second
+	}
+
+	return sm.insertSession(ctx, userID, ttl, authenticatedAt)
+}
+
 func (sm *Manager) GetUserFromSession(sessionID string) (*user.User, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
 	defer cancel()
@@ -162,7 +261,9 @@ func (sm *Manager) GetUserFromSession(sessionID string) (*user.User, error) {
         u.username,
         u.created_at,
         u.avatar_url,
-        u.password_hash
+        u.password_hash,
+        u.role,
+        u.email_verified
     FROM users u
     INNER JOIN sessions s ON s.user_id = u.id
     WHERE s.token = ?
@@ -185,6 +286,8 @@ func (sm *Manager) GetUserFromSession(sessionID string) (*user.User, error) {
 		&User.CreatedAt,
 		&User.AvatarURL,
 		&User.Password,
+		&User.Role,
+		&User.EmailVerified,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -225,6 +328,94 @@ func (sm *Manager) DeleteSessionWhenNewCreated(ctx context.Context, sessionID st
 	return err
 }
 
+// enforceSessionLimit drops the user's oldest sessions until no more than
+// MaxSessionsPerUser remain. A non-positive limit leaves them all in place.
+func (sm *Manager) enforceSessionLimit(ctx context.Context, userID string) error {
+	if sm.sessionConfig.MaxSessionsPerUser <= 0 {
+		return nil
+	}
+
+	// created_at only has second precision, so rowid breaks ties between
+	// sessions started in the same second.
+	query := `
+	DELETE FROM sessions
+	WHERE user_id = ?
+	  AND rowid NOT IN (
+		SELECT rowid FROM sessions
+		WHERE user_id = ?
+		ORDER BY created_at DESC, rowid DESC
+		LIMIT ?
+	  )`
+
+	stmt, err := sm.db.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("prepare failed: %w", err)
+	}
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, userID, userID, sm.sessionConfig.MaxSessionsPerUser)
+	return err
+}
+
+// InvalidateUserSessions signs the user out everywhere by dropping all of
+// their sessions.
+func (sm *Manager) InvalidateUserSessions(ctx context.Context, userID string) error {
+	query := `DELETE FROM sessions WHERE user_id = ?`
+
+	stmt, err := sm.db.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("prepare failed: %w", err)
+	}
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, userID)
+	return err
+}
+
+// DeleteExpiredSessions removes the sessions that can no longer be used or
+// refreshed and reports how many there were.
+func (sm *Manager) DeleteExpiredSessions(ctx context.Context) (int64, error) {
+	query := `DELETE FROM sessions WHERE expires_at < ? AND refresh_token_expires_at < ?`
+
+	stmt, err := sm.db.PrepareContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("prepare failed: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now().Format(SQLDateTime)
+	result, err := stmt.ExecContext(ctx, now, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// SweepExpired calls DeleteExpiredSessions on manager every interval until
+// ctx is done. Failures go to onError and the next sweep still runs. A
+// non-positive interval turns sweeping off.
+func SweepExpired(ctx context.Context, manager session.Manager, interval time.Duration, onError func(error)) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, err := manager.DeleteExpiredSessions(ctx)
+			if err != nil && ctx.Err() == nil {
+				onError(err)
+			}
+		}
+	}
+}
+
 func (sm *Manager) NewSessionCookie(token string) *http.Cookie {
 	return &http.Cookie{
 		Name:     sm.sessionConfig.CookieName,
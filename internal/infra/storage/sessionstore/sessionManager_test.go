@@ -0,0 +1,282 @@
+package sessionstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/arnald/forum/internal/config"
+	"github.com/arnald/forum/internal/pkg/path"
+)
+
+const testMaxSessions = 5
+
+// newTestManager returns a session manager backed by a private in-memory
+// database with the project schema applied and two users, "alice" and "bob".
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	// Every connection to :memory: gets its own database, so keep just one.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	schema, err := os.ReadFile(path.NewResolver().GetPath("db/migrations/schema.sql"))
+	if err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+	_, err = db.Exec(string(schema))
+	if err != nil {
+		t.Fatalf("failed to apply schema: %v", err)
+	}
+
+	_, err = db.Exec(`
+	INSERT INTO users (id, email, username, password_hash) VALUES
+		('alice', 'alice@example.com', 'alice', 'hash'),
+		('bob', 'bob@example.com', 'bob', 'hash')`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+
+	manager, ok := NewSessionManager(db, config.SessionManagerConfig{
+		DefaultExpiry:      24 * time.Hour,
+		RefreshTokenExpiry: time.Minute,
+		MaxSessionsPerUser: testMaxSessions,
+	}).(*Manager)
+	if !ok {
+		t.Fatal("NewSessionManager() did not return a *Manager")
+	}
+	return manager
+}
+
+func storedExpiry(t *testing.T, sm *Manager, token string) time.Time {
+	t.Helper()
+
+	var expiresAt time.Time
+	err := sm.db.QueryRow(`SELECT expires_at FROM sessions WHERE token = ?`, token).Scan(&expiresAt)
+	if err != nil {
+		t.Fatalf("failed to read expires_at: %v", err)
+	}
+	return expiresAt
+}
+
+func TestManager_CreateSessionWithTTL(t *testing.T) {
+	ctx := context.Background()
+	sm := newTestManager(t)
+
+	regular, err := sm.CreateSession(ctx, "alice")
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	remembered, err := sm.CreateSessionWithTTL(ctx, "bob", 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("CreateSessionWithTTL() error = %v", err)
+	}
+
+	regularExpiry := storedExpiry(t, sm, regular.AccessToken)
+	rememberedExpiry := storedExpiry(t, sm, remembered.AccessToken)
+
+	// Stored times are truncated to the second, so allow a little slack.
+	want := 29 * 24 * time.Hour
+	if got := rememberedExpiry.Sub(regularExpiry); got < want-time.Minute || got > want+time.Minute {
+		t.Errorf("remembered session outlives the regular one by %v, want %v", got, want)
+	}
+}
+
+func TestSweepExpired(t *testing.T) {
+	sm := newTestManager(t)
+
+	live, err := sm.CreateSession(context.Background(), "alice")
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	past := time.Now().Add(-time.Hour).Format(SQLDateTime)
+	_, err = sm.db.Exec(`
+	INSERT INTO sessions (token, user_id, expires_at, refresh_token, refresh_token_expires_at)
+	VALUES ('expired', 'bob', ?, 'expired-refresh', ?)`, past, past)
+	if err != nil {
+		t.Fatalf("failed to insert expired session: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		SweepExpired(ctx, sm, 10*time.Millisecond, func(err error) {
+			t.Errorf("SweepExpired() error = %v", err)
+		})
+	}()
+
+	countSessions := func(token string) int {
+		var n int
+		err := sm.db.QueryRow(`SELECT COUNT(*) FROM sessions WHERE token = ?`, token).Scan(&n)
+		if err != nil {
+			t.Fatalf("failed to count sessions: %v", err)
+		}
+		return n
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for countSessions("expired") != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("SweepExpired() did not remove the expired session")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("SweepExpired() did not return after its context was cancelled")
+	}
+
+	if countSessions(live.AccessToken) != 1 {
+		t.Error("SweepExpired() removed a session that has not expired")
+	}
+}
+
+func countUserSessions(t *testing.T, sm *Manager, userID string) int {
+	t.Helper()
+
+	var n int
+	err := sm.db.QueryRow(`SELECT COUNT(*) FROM sessions WHERE user_id = ?`, userID).Scan(&n)
+	if err != nil {
+		t.Fatalf("failed to count sessions: %v", err)
+	}
+	return n
+}
+
+func TestManager_SessionLimit(t *testing.T) {
+	ctx := context.Background()
+	sm := newTestManager(t)
+
+	var tokens []string
+	for i := range testMaxSessions + 3 {
+		s, err := sm.CreateSession(ctx, "alice")
+		if err != nil {
+			t.Fatalf("CreateSession() #%d error = %v", i+1, err)
+		}
+		tokens = append(tokens, s.AccessToken)
+
+		if got := countUserSessions(t, sm, "alice"); got > testMaxSessions {
+			t.Fatalf("after %d logins alice has %d sessions, want at most %d", i+1, got, testMaxSessions)
+		}
+
+		if i == testMaxSessions {
+			_, err = sm.GetSession(tokens[0])
+			if !errors.Is(err, ErrSessionNotFound) {
+				t.Errorf("GetSession(first) after the sixth login error = %v, want %v", err, ErrSessionNotFound)
+			}
+			_, err = sm.GetSession(tokens[1])
+			if err != nil {
+				t.Errorf("GetSession(second) after the sixth login error = %v, want nil", err)
+			}
+		}
+	}
+
+	for _, token := range tokens[len(tokens)-testMaxSessions:] {
+		_, err := sm.GetSession(token)
+		if err != nil {
+			t.Errorf("GetSession(%q) error = %v, want the newest sessions kept", token, err)
+		}
+	}
+
+	_, err := sm.CreateSession(ctx, "bob")
+	if err != nil {
+		t.Fatalf("CreateSession(bob) error = %v", err)
+	}
+	if got := countUserSessions(t, sm, "alice"); got != testMaxSessions {
+		t.Errorf("bob's login left alice with %d sessions, want %d", got, testMaxSessions)
+	}
+}
+
+func TestManager_RotateSession(t *testing.T) {
+	ctx := context.Background()
+	sm := newTestManager(t)
+
+	old, err := sm.CreateSession(ctx, "alice")
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	rotated, err := sm.RotateSession(ctx, old.RefreshToken)
+	if err != nil {
+		t.Fatalf("RotateSession() error = %v", err)
+	}
+	if rotated.UserID != "alice" || rotated.AccessToken == old.AccessToken || rotated.RefreshToken == old.RefreshToken {
+		t.Errorf("RotateSession() = %+v, want fresh tokens for alice", rotated)
+	}
+	if countUserSessions(t, sm, "alice") != 1 {
+		t.Errorf("alice has %d sessions after rotation, want 1", countUserSessions(t, sm, "alice"))
+	}
+
+	err = sm.ConfirmAuthentication(ctx, rotated.AccessToken)
+	if err != nil {
+		t.Fatalf("ConfirmAuthentication() error = %v", err)
+	}
+	confirmed, err := sm.GetSessionFromSessionTokens(rotated.AccessToken, rotated.RefreshToken)
+	if err != nil {
+		t.Fatalf("GetSessionFromSessionTokens() error = %v", err)
+	}
+	again, err := sm.RotateSession(ctx, rotated.RefreshToken)
+	if err != nil {
+		t.Fatalf("RotateSession() error = %v", err)
+	}
+	carried, err := sm.GetSessionFromSessionTokens(again.AccessToken, again.RefreshToken)
+	if err != nil {
+		t.Fatalf("GetSessionFromSessionTokens() error = %v", err)
+	}
+	if confirmed.AuthenticatedAt.IsZero() || !carried.AuthenticatedAt.Equal(confirmed.AuthenticatedAt) {
+		t.Errorf("rotated AuthenticatedAt = %v, want it carried over from %v", carried.AuthenticatedAt, confirmed.AuthenticatedAt)
+	}
+
+	_, err = sm.RotateSession(ctx, old.RefreshToken)
+	if !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("reusing a rotated refresh token error = %v, want %v", err, ErrSessionNotFound)
+	}
+
+	past := time.Now().Add(-time.Hour).Format(SQLDateTime)
+	_, err = sm.db.Exec(`
+	INSERT INTO sessions (token, user_id, expires_at, refresh_token, refresh_token_expires_at)
+	VALUES ('stale', 'bob', ?, 'stale-refresh', ?)`, past, past)
+	if err != nil {
+		t.Fatalf("failed to insert expired session: %v", err)
+	}
+
+	_, err = sm.RotateSession(ctx, "stale-refresh")
+	if !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("expired refresh token error = %v, want %v", err, ErrSessionExpired)
+	}
+	if countUserSessions(t, sm, "bob") != 0 {
+		t.Error("RotateSession() kept a session whose refresh token had expired")
+	}
+}
+
+func TestManager_RotateSession_KeepsLifetime(t *testing.T) {
+	ctx := context.Background()
+	sm := newTestManager(t)
+
+	remembered, err := sm.CreateSessionWithTTL(ctx, "alice", 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("CreateSessionWithTTL() error = %v", err)
+	}
+
+	rotated, err := sm.RotateSession(ctx, remembered.RefreshToken)
+	if err != nil {
+		t.Fatalf("RotateSession() error = %v", err)
+	}
+
+	// The rotated session must outlive the 24h default by far.
+	if lifetime := time.Until(rotated.Expiry); lifetime < 29*24*time.Hour {
+		t.Errorf("rotated session lasts %v, want the 30 day remember-me lifetime", lifetime)
+	}
+}
@@ -63,7 +63,7 @@ func (r *Repo) getCreatedTopics(ctx context.Context, userID string) ([]activity.
 	query := `
         SELECT id, title, created_at
         FROM topics
-        WHERE user_id = ?
+        WHERE user_id = ? AND deleted_at IS NULL AND status = 'published'
         ORDER BY created_at DESC
         LIMIT 50`
 
@@ -105,7 +105,8 @@ func (r *Repo) getVotedTopics(ctx context.Context, userID string, reactionType i
         SELECT t.id, t.title, v.created_at
         FROM topics t
         INNER JOIN votes v ON t.id = v.topic_id
-        WHERE v.user_id = ? 
+        WHERE t.deleted_at IS NULL
+        AND v.user_id = ? 
         AND v.reaction_type = ? 
         AND v.comment_id IS NULL
         ORDER BY v.created_at DESC
@@ -149,7 +150,7 @@ func (r *Repo) getVotedComments(ctx context.Context, userID string, reactionType
         SELECT c.id, c.topic_id, t.title, v.created_at
         FROM comments c
         INNER JOIN votes v ON c.id = v.comment_id
-        INNER JOIN topics t ON c.topic_id = t.id
+        INNER JOIN topics t ON c.topic_id = t.id AND t.deleted_at IS NULL
         WHERE v.user_id = ? 
         AND v.reaction_type = ?
         ORDER BY v.created_at DESC
@@ -192,7 +193,7 @@ func (r *Repo) getUserComments(ctx context.Context, userID string) ([]activity.C
 	query := `
         SELECT c.id, c.content, c.topic_id, t.title, c.created_at
         FROM comments c
-        INNER JOIN topics t ON c.topic_id = t.id
+        INNER JOIN topics t ON c.topic_id = t.id AND t.deleted_at IS NULL
         WHERE c.user_id = ?
         ORDER BY c.created_at DESC
         LIMIT 50`
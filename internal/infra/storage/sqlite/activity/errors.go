@@ -0,0 +1,5 @@
+package activities
+
+import "errors"
+
+var ErrUnknownFeedType = errors.New("unknown activity feed type")
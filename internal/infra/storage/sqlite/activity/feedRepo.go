@@ -0,0 +1,133 @@
+package activities
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arnald/forum/internal/domain/activity"
+)
+
+// feedSource is the query behind one feed type. Every source selects kind,
+// sort_id, topic_id, topic_title, comment_id, content, actor_name and
+// created_at, and takes the user's ID userParams times.
+type feedSource struct {
+	query      string
+	userParams int
+}
+
+var feedSources = map[string]feedSource{
+	activity.FeedPosts: {
+		query: `
+        SELECT 'topic' AS kind, t.id AS sort_id, t.id AS topic_id, t.title AS topic_title,
+            0 AS comment_id, '' AS content, '' AS actor_name, t.created_at AS created_at
+        FROM topics t
+        WHERE t.user_id = ? AND t.deleted_at IS NULL AND t.status = 'published'`,
+		userParams: 1,
+	},
+	activity.FeedComments: {
+		query: `
+        SELECT 'comment' AS kind, c.id AS sort_id, c.topic_id AS topic_id, t.title AS topic_title,
+            c.id AS comment_id, c.content AS content, '' AS actor_name, c.created_at AS created_at
+        FROM comments c
+        INNER JOIN topics t ON c.topic_id = t.id AND t.deleted_at IS NULL
+        WHERE c.user_id = ?`,
+		userParams: 1,
+	},
+	activity.FeedLikes: {
+		query: `
+        SELECT 'topic_like' AS kind, v.id AS sort_id, t.id AS topic_id, t.title AS topic_title,
+            0 AS comment_id, '' AS content, '' AS actor_name, v.created_at AS created_at
+        FROM votes v
+        INNER JOIN topics t ON v.topic_id = t.id AND t.deleted_at IS NULL
+        WHERE v.user_id = ? AND v.reaction_type = 1 AND v.comment_id IS NULL
+        UNION ALL
+        SELECT 'comment_like', v.id, c.topic_id, t.title, c.id, c.content, '', v.created_at
+        FROM votes v
+        INNER JOIN comments c ON v.comment_id = c.id
+        INNER JOIN topics t ON c.topic_id = t.id AND t.deleted_at IS NULL
+        WHERE v.user_id = ? AND v.reaction_type = 1`,
+		userParams: 2,
+	},
+	// Likes on the user's topics and comments, and approved comments left on
+	// their topics or in reply to their comments, by anyone but themselves.
+	activity.FeedReceived: {
+		query: `
+        SELECT 'topic_like' AS kind, v.id AS sort_id, t.id AS topic_id, t.title AS topic_title,
+            0 AS comment_id, '' AS content, COALESCE(u.username, '') AS actor_name, v.created_at AS created_at
+        FROM votes v
+        INNER JOIN topics t ON v.topic_id = t.id AND t.deleted_at IS NULL
+        LEFT JOIN users u ON v.user_id = u.id
+        WHERE t.user_id = ? AND v.user_id != ? AND v.reaction_type = 1 AND v.comment_id IS NULL
+        UNION ALL
+        SELECT 'comment_like', v.id, c.topic_id, t.title, c.id, c.content, COALESCE(u.username, ''), v.created_at
+        FROM votes v
+        INNER JOIN comments c ON v.comment_id = c.id
+        INNER JOIN topics t ON c.topic_id = t.id AND t.deleted_at IS NULL
+        LEFT JOIN users u ON v.user_id = u.id
+        WHERE c.user_id = ? AND v.user_id != ? AND v.reaction_type = 1
+        UNION ALL
+        SELECT 'comment', c.id, c.topic_id, t.title, c.id, c.content, COALESCE(u.username, ''), c.created_at
+        FROM comments c
+        INNER JOIN topics t ON c.topic_id = t.id AND t.deleted_at IS NULL
+        LEFT JOIN comments p ON c.parent_id = p.id
+        LEFT JOIN users u ON c.user_id = u.id
+        WHERE (t.user_id = ? OR p.user_id = ?) AND c.user_id != ? AND c.status = 'approved'`,
+		userParams: 7,
+	},
+}
+
+func (r *Repo) GetActivityFeed(ctx context.Context, userID, feedType string, limit, offset int) ([]activity.FeedItem, int, error) {
+	source, ok := feedSources[feedType]
+	if !ok {
+		return nil, 0, fmt.Errorf("feed type %q: %w", feedType, ErrUnknownFeedType)
+	}
+
+	args := make([]interface{}, 0, source.userParams+2)
+	for range source.userParams {
+		args = append(args, userID)
+	}
+
+	var total int
+	err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM (`+source.query+`)`, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count %s feed: %w", feedType, err)
+	}
+
+	query := `
+        SELECT kind, topic_id, topic_title, comment_id, content, actor_name, created_at
+        FROM (` + source.query + `)
+        ORDER BY created_at DESC, sort_id DESC, kind
+        LIMIT ? OFFSET ?`
+
+	rows, err := r.DB.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query %s feed: %w", feedType, err)
+	}
+	defer rows.Close()
+
+	items := make([]activity.FeedItem, 0, limit)
+	for rows.Next() {
+		var item activity.FeedItem
+		var createdAt string
+		err = rows.Scan(&item.Kind, &item.TopicID, &item.TopicTitle, &item.CommentID, &item.Content, &item.ActorName, &createdAt)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan feed item: %w", err)
+		}
+
+		item.CreatedAt = createdAt
+		t, parseErr := time.Parse(time.RFC3339, createdAt)
+		if parseErr == nil {
+			item.CreatedAt = t.Format("Jan 2, 2006 3:04 PM")
+		}
+
+		items = append(items, item)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, 0, fmt.Errorf("feed rows iteration failed: %w", err)
+	}
+
+	return items, total, nil
+}
@@ -0,0 +1,168 @@
+package activities
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/arnald/forum/internal/domain/activity"
+)
+
+// seedFeed adds, on top of seedContent, bob's approved reply to alice and a
+// pending comment of his, alice's answer to bob and alice liking her own
+// topic. Of these only bob's reply reaches alice's received feed.
+func seedFeed(t *testing.T, repo *Repo) {
+	t.Helper()
+	seedContent(t, repo)
+
+	_, err := repo.DB.Exec(`
+	INSERT INTO comments (id, user_id, topic_id, parent_id, content, status) VALUES
+		(10, 'bob', 1, 1, 'bob replies', 'approved'),
+		(11, 'bob', 2, NULL, 'bob waits', 'pending'),
+		(12, 'alice', 1, 10, 'alice answers', 'approved');
+	INSERT INTO votes (user_id, topic_id, comment_id, reaction_type) VALUES
+		('alice', 2, NULL, 1)`)
+	if err != nil {
+		t.Fatalf("failed to seed feed: %v", err)
+	}
+}
+
+type feedEntry struct {
+	kind      string
+	actor     string
+	topicID   int
+	commentID int
+}
+
+func feedEntries(items []activity.FeedItem) []feedEntry {
+	entries := make([]feedEntry, 0, len(items))
+	for _, item := range items {
+		entries = append(entries, feedEntry{kind: item.Kind, actor: item.ActorName, topicID: item.TopicID, commentID: item.CommentID})
+	}
+	return entries
+}
+
+func TestRepo_GetActivityFeed(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	seedFeed(t, repo)
+
+	testCases := []struct {
+		name     string
+		userID   string
+		feedType string
+		want     []feedEntry
+	}{
+		{
+			name:     "posts",
+			userID:   "alice",
+			feedType: activity.FeedPosts,
+			want: []feedEntry{
+				{kind: activity.ItemTopic, topicID: 2},
+				{kind: activity.ItemTopic, topicID: 1},
+			},
+		},
+		{
+			name:     "comments include every status",
+			userID:   "alice",
+			feedType: activity.FeedComments,
+			want: []feedEntry{
+				{kind: activity.ItemComment, topicID: 1, commentID: 12},
+				{kind: activity.ItemComment, topicID: 2, commentID: 3},
+				{kind: activity.ItemComment, topicID: 1, commentID: 2},
+				{kind: activity.ItemComment, topicID: 1, commentID: 1},
+			},
+		},
+		{
+			name:     "likes leave out dislikes",
+			userID:   "bob",
+			feedType: activity.FeedLikes,
+			want: []feedEntry{
+				{kind: activity.ItemCommentLike, topicID: 2, commentID: 3},
+				{kind: activity.ItemCommentLike, topicID: 1, commentID: 2},
+				{kind: activity.ItemCommentLike, topicID: 1, commentID: 1},
+				{kind: activity.ItemTopicLike, topicID: 1},
+			},
+		},
+		{
+			name:     "received leaves out the user's own and pending content",
+			userID:   "alice",
+			feedType: activity.FeedReceived,
+			want: []feedEntry{
+				{kind: activity.ItemComment, actor: "bob", topicID: 1, commentID: 10},
+				{kind: activity.ItemCommentLike, actor: "bob", topicID: 2, commentID: 3},
+				{kind: activity.ItemCommentLike, actor: "bob", topicID: 1, commentID: 2},
+				{kind: activity.ItemCommentLike, actor: "bob", topicID: 1, commentID: 1},
+				{kind: activity.ItemTopicLike, actor: "bob", topicID: 1},
+			},
+		},
+		{
+			name:     "received counts replies to the user's comments",
+			userID:   "bob",
+			feedType: activity.FeedReceived,
+			want: []feedEntry{
+				{kind: activity.ItemComment, actor: "alice", topicID: 1, commentID: 12},
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			items, total, err := repo.GetActivityFeed(ctx, tt.userID, tt.feedType, 20, 0)
+			if err != nil {
+				t.Fatalf("GetActivityFeed() error = %v", err)
+			}
+			if got := feedEntries(items); !slices.Equal(got, tt.want) {
+				t.Errorf("GetActivityFeed() = %+v, want %+v", got, tt.want)
+			}
+			if total != len(tt.want) {
+				t.Errorf("GetActivityFeed() total = %d, want %d", total, len(tt.want))
+			}
+			for _, item := range items {
+				_, parseErr := time.Parse("Jan 2, 2006 3:04 PM", item.CreatedAt)
+				if parseErr != nil {
+					t.Errorf("item CreatedAt = %q, want a formatted date", item.CreatedAt)
+				}
+			}
+		})
+	}
+}
+
+func TestRepo_GetActivityFeed_Pagination(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	seedFeed(t, repo)
+
+	var pages [][]int
+	for offset := 0; offset <= 4; offset += 2 {
+		items, total, err := repo.GetActivityFeed(ctx, "alice", activity.FeedComments, 2, offset)
+		if err != nil {
+			t.Fatalf("GetActivityFeed(offset %d) error = %v", offset, err)
+		}
+		if total != 4 {
+			t.Errorf("GetActivityFeed(offset %d) total = %d, want 4", offset, total)
+		}
+
+		ids := make([]int, 0, len(items))
+		for _, item := range items {
+			ids = append(ids, item.CommentID)
+		}
+		pages = append(pages, ids)
+	}
+
+	want := [][]int{{12, 3}, {2, 1}, {}}
+	if !slices.EqualFunc(pages, want, slices.Equal) {
+		t.Errorf("pages = %v, want %v", pages, want)
+	}
+}
+
+func TestRepo_GetActivityFeed_UnknownType(t *testing.T) {
+	repo := newTestRepo(t)
+
+	_, _, err := repo.GetActivityFeed(context.Background(), "alice", "everything", 20, 0)
+	if !errors.Is(err, ErrUnknownFeedType) {
+		t.Errorf("GetActivityFeed() error = %v, want %v", err, ErrUnknownFeedType)
+	}
+}
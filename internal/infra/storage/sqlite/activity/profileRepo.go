@@ -0,0 +1,118 @@
+package activities
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arnald/forum/internal/domain/activity"
+)
+
+// profileCommentsLimit caps the recent comments listed on a profile page.
+const profileCommentsLimit = 20
+
+// GetUserProfile gathers a user's topics, recent comments and stats. Held
+// comments are only listed when includePending is set, which callers do for
+// the profile's owner.
+func (r *Repo) GetUserProfile(ctx context.Context, userID string, includePending bool) (*activity.Profile, error) {
+	topics, err := r.getCreatedTopics(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get created topics: %w", err)
+	}
+
+	comments, err := r.getProfileComments(ctx, userID, includePending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile comments: %w", err)
+	}
+
+	stats, err := r.GetUserStats(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &activity.Profile{
+		Topics:   topics,
+		Comments: comments,
+		Stats:    *stats,
+	}, nil
+}
+
+// GetUserStats counts the user's topics and approved comments, and the
+// upvotes those have received. Held and rejected comments are left out so
+// the totals match what any visitor can see.
+func (r *Repo) GetUserStats(ctx context.Context, userID string) (*activity.Stats, error) {
+	query := `
+        SELECT
+            (SELECT COUNT(*) FROM topics WHERE user_id = ? AND deleted_at IS NULL AND status = 'published'),
+            (SELECT COUNT(*)
+             FROM comments c
+             INNER JOIN topics t ON c.topic_id = t.id AND t.deleted_at IS NULL
+             WHERE c.user_id = ? AND c.status = 'approved'),
+            (SELECT COUNT(*)
+             FROM votes v
+             LEFT JOIN topics t ON v.topic_id = t.id AND v.comment_id IS NULL AND t.deleted_at IS NULL
+             LEFT JOIN comments c ON v.comment_id = c.id
+             LEFT JOIN topics ct ON c.topic_id = ct.id
+             WHERE v.reaction_type = 1
+             AND (t.user_id = ? OR (c.user_id = ? AND c.status = 'approved' AND ct.deleted_at IS NULL)))`
+
+	var stats activity.Stats
+	err := r.DB.QueryRowContext(ctx, query, userID, userID, userID, userID).Scan(
+		&stats.Posts,
+		&stats.Comments,
+		&stats.LikesReceived,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user stats: %w", err)
+	}
+
+	return &stats, nil
+}
+
+func (r *Repo) getProfileComments(ctx context.Context, userID string, includePending bool) ([]activity.CommentActivity, error) {
+	query := `
+        SELECT c.id, c.content, c.topic_id, t.title, c.status, c.created_at
+        FROM comments c
+        INNER JOIN topics t ON c.topic_id = t.id AND t.deleted_at IS NULL
+        WHERE c.user_id = ?`
+	if includePending {
+		query += ` AND c.status IN ('approved', 'pending')`
+	} else {
+		query += ` AND c.status = 'approved'`
+	}
+	query += `
+        ORDER BY c.created_at DESC, c.id DESC
+        LIMIT ?`
+
+	rows, err := r.DB.QueryContext(ctx, query, userID, profileCommentsLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := make([]activity.CommentActivity, 0)
+	for rows.Next() {
+		var comment activity.CommentActivity
+		var createdAt string
+		rowsErr := rows.Scan(&comment.ID, &comment.Content, &comment.TopicID, &comment.TopicTitle, &comment.Status, &createdAt)
+		if rowsErr != nil {
+			return nil, rowsErr
+		}
+
+		t, parseErr := time.Parse(time.RFC3339, createdAt)
+		if parseErr == nil {
+			comment.CreatedAt = t.Format("Jan 2, 2006 3:04 PM")
+		} else {
+			comment.CreatedAt = createdAt
+		}
+
+		comments = append(comments, comment)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
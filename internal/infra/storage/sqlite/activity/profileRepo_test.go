@@ -0,0 +1,150 @@
+package activities
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/arnald/forum/internal/domain/activity"
+	"github.com/arnald/forum/internal/pkg/path"
+)
+
+// newTestRepo returns a repository backed by a private in-memory database
+// with the project schema applied and two users, "alice" and "bob".
+func newTestRepo(t *testing.T) *Repo {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	// Every connection to :memory: gets its own database, so keep just one.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	schema, err := os.ReadFile(path.NewResolver().GetPath("db/migrations/schema.sql"))
+	if err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+	_, err = db.Exec(string(schema))
+	if err != nil {
+		t.Fatalf("failed to apply schema: %v", err)
+	}
+
+	_, err = db.Exec(`
+	INSERT INTO users (id, email, username, password_hash) VALUES
+		('alice', 'alice@example.com', 'alice', 'hash'),
+		('bob', 'bob@example.com', 'bob', 'hash')`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+
+	return NewRepo(db)
+}
+
+// seedContent gives alice two topics and three comments (approved, pending
+// and rejected), and has bob upvote one topic, downvote the other and upvote
+// every comment.
+func seedContent(t *testing.T, repo *Repo) {
+	t.Helper()
+
+	_, err := repo.DB.Exec(`
+	INSERT INTO topics (id, user_id, title, content) VALUES
+		(1, 'alice', 'First', 'body'),
+		(2, 'alice', 'Second', 'body');
+	INSERT INTO comments (id, user_id, topic_id, content, status) VALUES
+		(1, 'alice', 1, 'approved comment', 'approved'),
+		(2, 'alice', 1, 'pending comment', 'pending'),
+		(3, 'alice', 2, 'rejected comment', 'rejected');
+	INSERT INTO votes (user_id, topic_id, comment_id, reaction_type) VALUES
+		('bob', 1, NULL, 1),
+		('bob', 2, NULL, -1),
+		('bob', NULL, 1, 1),
+		('bob', NULL, 2, 1),
+		('bob', NULL, 3, 1)`)
+	if err != nil {
+		t.Fatalf("failed to seed content: %v", err)
+	}
+}
+
+func TestRepo_GetUserStats(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("user with no content", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		got, err := repo.GetUserStats(ctx, "alice")
+		if err != nil {
+			t.Fatalf("GetUserStats() error = %v", err)
+		}
+		if *got != (activity.Stats{}) {
+			t.Errorf("GetUserStats() = %+v, want all zero", *got)
+		}
+	})
+
+	t.Run("counts only approved content", func(t *testing.T) {
+		repo := newTestRepo(t)
+		seedContent(t, repo)
+
+		got, err := repo.GetUserStats(ctx, "alice")
+		if err != nil {
+			t.Fatalf("GetUserStats() error = %v", err)
+		}
+		want := activity.Stats{Posts: 2, Comments: 1, LikesReceived: 2}
+		if *got != want {
+			t.Errorf("GetUserStats() = %+v, want %+v", *got, want)
+		}
+	})
+}
+
+func TestRepo_GetUserProfile(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("user with no content", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		got, err := repo.GetUserProfile(ctx, "alice", false)
+		if err != nil {
+			t.Fatalf("GetUserProfile() error = %v", err)
+		}
+		if len(got.Topics) != 0 || len(got.Comments) != 0 {
+			t.Errorf("GetUserProfile() = %d topics, %d comments, want none", len(got.Topics), len(got.Comments))
+		}
+	})
+
+	t.Run("visitors see approved comments only", func(t *testing.T) {
+		repo := newTestRepo(t)
+		seedContent(t, repo)
+
+		got, err := repo.GetUserProfile(ctx, "alice", false)
+		if err != nil {
+			t.Fatalf("GetUserProfile() error = %v", err)
+		}
+		if len(got.Topics) != 2 {
+			t.Errorf("GetUserProfile() topics = %d, want 2", len(got.Topics))
+		}
+		if len(got.Comments) != 1 || got.Comments[0].ID != 1 {
+			t.Errorf("GetUserProfile() comments = %+v, want only comment 1", got.Comments)
+		}
+	})
+
+	t.Run("owner also sees pending comments", func(t *testing.T) {
+		repo := newTestRepo(t)
+		seedContent(t, repo)
+
+		got, err := repo.GetUserProfile(ctx, "alice", true)
+		if err != nil {
+			t.Fatalf("GetUserProfile() error = %v", err)
+		}
+		statuses := map[int]string{}
+		for _, c := range got.Comments {
+			statuses[c.ID] = c.Status
+		}
+		if len(statuses) != 2 || statuses[1] != "approved" || statuses[2] != "pending" {
+			t.Errorf("GetUserProfile() comment statuses = %v, want approved 1 and pending 2", statuses)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package activities
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arnald/forum/internal/domain/activity"
+	"github.com/arnald/forum/internal/domain/comment"
+)
+
+// GetUserSubmissions returns every comment userID has posted on a live topic,
+// newest first, grouped by moderation status.
+func (r *Repo) GetUserSubmissions(ctx context.Context, userID string) (*activity.Submissions, error) {
+	query := `
+        SELECT c.id, c.content, c.topic_id, t.title, c.status, c.rejection_reason, c.created_at
+        FROM comments c
+        INNER JOIN topics t ON c.topic_id = t.id AND t.deleted_at IS NULL
+        WHERE c.user_id = ?
+        ORDER BY c.created_at DESC, c.id DESC`
+
+	rows, err := r.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query submissions: %w", err)
+	}
+	defer rows.Close()
+
+	submissions := &activity.Submissions{
+		Pending:  make([]activity.CommentActivity, 0),
+		Approved: make([]activity.CommentActivity, 0),
+		Rejected: make([]activity.CommentActivity, 0),
+	}
+	for rows.Next() {
+		var submitted activity.CommentActivity
+		var createdAt string
+		err = rows.Scan(&submitted.ID, &submitted.Content, &submitted.TopicID, &submitted.TopicTitle, &submitted.Status, &submitted.RejectionReason, &createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan submission: %w", err)
+		}
+
+		submitted.CreatedAt = createdAt
+		t, parseErr := time.Parse(time.RFC3339, createdAt)
+		if parseErr == nil {
+			submitted.CreatedAt = t.Format("Jan 2, 2006 3:04 PM")
+		}
+
+		switch submitted.Status {
+		case comment.StatusPending:
+			submissions.Pending = append(submissions.Pending, submitted)
+		case comment.StatusRejected:
+			submissions.Rejected = append(submissions.Rejected, submitted)
+		default:
+			submissions.Approved = append(submissions.Approved, submitted)
+		}
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("submission rows iteration failed: %w", err)
+	}
+
+	return submissions, nil
+}
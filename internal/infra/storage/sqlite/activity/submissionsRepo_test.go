@@ -0,0 +1,61 @@
+package activities
+
+import (
+	"context"
+	"testing"
+
+	"github.com/arnald/forum/internal/domain/activity"
+)
+
+func TestRepo_GetUserSubmissions(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	seedContent(t, repo)
+
+	// bob's comments, whatever their status, must never show up for alice.
+	_, err := repo.DB.Exec(`
+	INSERT INTO comments (id, user_id, topic_id, content, status) VALUES
+		(10, 'bob', 1, 'bob approved', 'approved'),
+		(11, 'bob', 1, 'bob pending', 'pending'),
+		(12, 'bob', 2, 'bob rejected', 'rejected')`)
+	if err != nil {
+		t.Fatalf("failed to seed bob's comments: %v", err)
+	}
+
+	got, err := repo.GetUserSubmissions(ctx, "alice")
+	if err != nil {
+		t.Fatalf("GetUserSubmissions() error = %v", err)
+	}
+
+	groups := []struct {
+		name  string
+		items []activity.CommentActivity
+		want  int
+	}{
+		{name: "pending", items: got.Pending, want: 2},
+		{name: "approved", items: got.Approved, want: 1},
+		{name: "rejected", items: got.Rejected, want: 3},
+	}
+	for _, g := range groups {
+		if len(g.items) != 1 || g.items[0].ID != g.want {
+			t.Errorf("%s = %+v, want only alice's comment %d", g.name, g.items, g.want)
+			continue
+		}
+		if g.items[0].Status != g.name {
+			t.Errorf("%s comment Status = %q, want %q", g.name, g.items[0].Status, g.name)
+		}
+	}
+
+	got, err = repo.GetUserSubmissions(ctx, "bob")
+	if err != nil {
+		t.Fatalf("GetUserSubmissions() error = %v", err)
+	}
+	for _, c := range append(append(got.Pending, got.Approved...), got.Rejected...) {
+		if c.ID < 10 {
+			t.Errorf("bob's submissions include alice's comment %d", c.ID)
+		}
+	}
+	if len(got.Pending)+len(got.Approved)+len(got.Rejected) != 3 {
+		t.Errorf("bob's submissions = %+v, want his three comments", got)
+	}
+}
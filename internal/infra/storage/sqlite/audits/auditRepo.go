@@ -0,0 +1,100 @@
+package audits
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/arnald/forum/internal/domain/audit"
+)
+
+type Repo struct {
+	DB *sql.DB
+}
+
+func NewRepo(db *sql.DB) *Repo {
+	return &Repo{
+		DB: db,
+	}
+}
+
+// LogAudit records a staff action and fills in the entry's ID and CreatedAt.
+func (r *Repo) LogAudit(ctx context.Context, entry *audit.Entry) error {
+	query := `
+	INSERT INTO audit_log (admin_id, action, target_type, target_id, details)
+	VALUES (?, ?, ?, ?, ?)
+	RETURNING id, created_at`
+
+	stmt, err := r.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("prepare failed: %w", err)
+	}
+	defer stmt.Close()
+
+	err = stmt.QueryRowContext(ctx,
+		entry.AdminID,
+		entry.Action,
+		entry.TargetType,
+		entry.TargetID,
+		entry.Details,
+	).Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repo) GetAuditLog(ctx context.Context, limit, offset int) ([]audit.Entry, int, error) {
+	var total int
+	err := r.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM audit_log").Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit entries: %w", err)
+	}
+
+	query := `
+	SELECT a.id, a.admin_id, COALESCE(u.username, ''), a.action,
+		a.target_type, a.target_id, a.details, a.created_at
+	FROM audit_log a
+	LEFT JOIN users u ON u.id = a.admin_id
+	ORDER BY a.id DESC
+	LIMIT ? OFFSET ?`
+
+	stmt, err := r.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, 0, fmt.Errorf("prepare failed: %w", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]audit.Entry, 0)
+	for rows.Next() {
+		var entry audit.Entry
+		err = rows.Scan(
+			&entry.ID,
+			&entry.AdminID,
+			&entry.AdminUsername,
+			&entry.Action,
+			&entry.TargetType,
+			&entry.TargetID,
+			&entry.Details,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan audit entries failed: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, 0, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return entries, total, nil
+}
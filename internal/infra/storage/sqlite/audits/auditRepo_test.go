@@ -0,0 +1,94 @@
+package audits
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/arnald/forum/internal/domain/audit"
+	"github.com/arnald/forum/internal/pkg/path"
+)
+
+// newTestRepo returns a repository backed by a private in-memory database
+// with the project schema applied and one admin, "root".
+func newTestRepo(t *testing.T) *Repo {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	// Every connection to :memory: gets its own database, so keep just one.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	schema, err := os.ReadFile(path.NewResolver().GetPath("db/migrations/schema.sql"))
+	if err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+	_, err = db.Exec(string(schema))
+	if err != nil {
+		t.Fatalf("failed to apply schema: %v", err)
+	}
+
+	_, err = db.Exec(`
+	INSERT INTO users (id, email, username, password_hash, role)
+	VALUES ('root', 'root@example.com', 'root', 'hash', 'admin')`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+
+	return NewRepo(db)
+}
+
+func TestRepo_AuditLog(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	for _, entry := range []*audit.Entry{
+		{AdminID: "root", Action: audit.ActionApproveComment, TargetType: audit.TargetComment, TargetID: "1"},
+		{AdminID: "root", Action: audit.ActionRejectComment, TargetType: audit.TargetComment, TargetID: "2"},
+		{AdminID: "gone", Action: audit.ActionDeleteCategory, TargetType: audit.TargetCategory, TargetID: "3", Details: "News"},
+	} {
+		err := repo.LogAudit(ctx, entry)
+		if err != nil {
+			t.Fatalf("LogAudit() error = %v", err)
+		}
+		if entry.ID == 0 || entry.CreatedAt == "" {
+			t.Errorf("LogAudit() left ID = %d, CreatedAt = %q, want both set", entry.ID, entry.CreatedAt)
+		}
+	}
+
+	page, total, err := repo.GetAuditLog(ctx, 2, 0)
+	if err != nil {
+		t.Fatalf("GetAuditLog() error = %v", err)
+	}
+	if total != 3 {
+		t.Errorf("GetAuditLog() total = %d, want 3", total)
+	}
+	if len(page) != 2 {
+		t.Fatalf("GetAuditLog() returned %d entries, want 2", len(page))
+	}
+
+	newest := page[0]
+	if newest.Action != audit.ActionDeleteCategory || newest.TargetID != "3" || newest.Details != "News" {
+		t.Errorf("GetAuditLog() newest = %+v, want the category deletion", newest)
+	}
+	if newest.AdminUsername != "" {
+		t.Errorf("GetAuditLog() AdminUsername = %q for a missing account, want empty", newest.AdminUsername)
+	}
+	if page[1].AdminUsername != "root" {
+		t.Errorf("GetAuditLog() AdminUsername = %q, want %q", page[1].AdminUsername, "root")
+	}
+
+	rest, _, err := repo.GetAuditLog(ctx, 2, 2)
+	if err != nil {
+		t.Fatalf("GetAuditLog() second page error = %v", err)
+	}
+	if len(rest) != 1 || rest[0].Action != audit.ActionApproveComment {
+		t.Errorf("GetAuditLog() second page = %+v, want only the approval", rest)
+	}
+}
@@ -21,9 +21,10 @@ func NewRepo(db *sql.DB) *Repo {
 }
 
 func (r *Repo) CreateCategory(ctx context.Context, category *category.Category) error {
+	// A category created without a color gets the schema's neutral default.
 	query := `
-	INSERT INTO categories (name, description, created_by)
-	VALUES (?,?,?)`
+	INSERT INTO categories (name, description, color, requires_image, created_by)
+	VALUES (?, ?, COALESCE(NULLIF(?, ''), '#CCCCCC'), ?, ?)`
 
 	stmt, err := r.DB.PrepareContext(ctx, query)
 	if err != nil {
@@ -35,6 +36,8 @@ func (r *Repo) CreateCategory(ctx context.Context, category *category.Category)
 		ctx,
 		category.Name,
 		category.Description,
+		category.Color,
+		category.RequiresImage,
 		category.CreatedBy,
 	)
 	if err != nil {
@@ -52,10 +55,11 @@ func (r *Repo) CreateCategory(ctx context.Context, category *category.Category)
 
 func (r *Repo) GetAllCategories(ctx context.Context, page, size int, orderBy, order, filter string) ([]category.Category, error) {
 	query := `
-	SELECT c.id, c.name, c.description, c.slug, c.color, c.image_path, c.created_at, c.created_by, COUNT(DISTINCT tc.topic_id) as topic_count
+	SELECT c.id, c.name, c.description, c.slug, c.color, c.image_path, c.requires_image, c.created_at, c.created_by, COUNT(DISTINCT t.id) as topic_count
 	FROM categories c
 	LEFT JOIN topic_categories tc ON c.id = tc.category_id
-	WHERE 1=1
+	LEFT JOIN topics t ON tc.topic_id = t.id AND t.deleted_at IS NULL AND t.status = 'published'
+	WHERE c.archived = 0
 	`
 	args := make([]interface{}, 0)
 
@@ -91,6 +95,7 @@ func (r *Repo) GetAllCategories(ctx context.Context, page, size int, orderBy, or
 			&category.Slug,
 			&category.Color,
 			&category.ImagePath,
+			&category.RequiresImage,
 			&category.CreatedAt,
 			&category.CreatedBy,
 			&category.TopicCount,
@@ -110,7 +115,10 @@ func (r *Repo) GetAllCategories(ctx context.Context, page, size int, orderBy, or
 	return categories, nil
 }
 
-func (r *Repo) PopulateCategoriesWithTopics(ctx context.Context, categories []category.Category) ([]category.Category, error) {
+// PopulateCategoriesWithTopics attaches each category's topics. With
+// canonicalOnly set, a topic filed under several categories is attached to
+// its canonical category only.
+func (r *Repo) PopulateCategoriesWithTopics(ctx context.Context, categories []category.Category, canonicalOnly bool) ([]category.Category, error) {
 	if len(categories) == 0 {
 		return categories, nil
 	}
@@ -132,9 +140,13 @@ func (r *Repo) PopulateCategoriesWithTopics(ctx context.Context, categories []ca
         SELECT t.id, t.title, tc.category_id, t.created_at 
         FROM topics t
         INNER JOIN topic_categories tc ON t.id = tc.topic_id
-        WHERE tc.category_id IN (`)
+        WHERE t.deleted_at IS NULL AND t.status = 'published' AND tc.category_id IN (`)
 	queryBuilder.WriteString(strings.Join(placeholders, ","))
-	queryBuilder.WriteString(") ORDER BY t.created_at DESC")
+	queryBuilder.WriteString(")")
+	if canonicalOnly {
+		queryBuilder.WriteString(" AND (t.canonical_category_id IS NULL OR t.canonical_category_id = tc.category_id)")
+	}
+	queryBuilder.WriteString(" ORDER BY t.created_at DESC")
 	query := queryBuilder.String()
 
 	stmt, err := r.DB.PrepareContext(ctx, query)
@@ -194,7 +206,7 @@ func (r *Repo) GetTotalCategoriesCount(ctx context.Context, filter string) (int,
 	countQuery := `
 	SELECT COUNT(*)
 	FROM categories c
-	WHERE 1=1
+	WHERE c.archived = 0
 	`
 
 	args := make([]interface{}, 0)
@@ -215,7 +227,7 @@ func (r *Repo) GetTotalCategoriesCount(ctx context.Context, filter string) (int,
 
 func (r *Repo) GetCategoryByID(ctx context.Context, id int) (*category.Category, error) {
 	query := `
-	SELECT id, name, description, created_by, created_at
+	SELECT id, name, description, COALESCE(color, ''), requires_image, archived, created_by, created_at
 	FROM categories
 	WHERE id = ?
 	`
@@ -231,6 +243,9 @@ func (r *Repo) GetCategoryByID(ctx context.Context, id int) (*category.Category,
 		&category.ID,
 		&category.Name,
 		&category.Description,
+		&category.Color,
+		&category.RequiresImage,
+		&category.Archived,
 		&category.CreatedBy,
 		&category.CreatedAt)
 	if err != nil {
@@ -242,19 +257,45 @@ func (r *Repo) GetCategoryByID(ctx context.Context, id int) (*category.Category,
 	return &category, nil
 }
 
-func (r *Repo) DeleteCategory(ctx context.Context, id int, userID string) error {
-	query := `
-	DELETE FROM categories
-	WHERE id = ? AND created_by = ?
-	`
-
-	stmt, err := r.DB.PrepareContext(ctx, query)
+// DeleteCategory removes the category for good; the cascade also strips it
+// from every topic filed under it. Unless force is set, a category that still
+// has topics is left alone and ErrCategoryHasTopics returned, so topics are
+// not uncategorised by accident. SetCategoryArchived hides a category without
+// losing those associations.
+func (r *Repo) DeleteCategory(ctx context.Context, id int, userID string, force bool) (err error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("prepare failed: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	defer stmt.Close()
 
-	result, err := stmt.ExecContext(ctx, id, userID)
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				err = fmt.Errorf("transaction rollback failed: %w (original error: %w)", rollbackErr, err)
+			}
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	if !force {
+		var topicCount int
+		err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM topic_categories tc
+		INNER JOIN categories c ON c.id = tc.category_id
+		WHERE c.id = ? AND c.created_by = ?`, id, userID).Scan(&topicCount)
+		if err != nil {
+			return fmt.Errorf("failed to count category topics: %w", err)
+		}
+		if topicCount > 0 {
+			return fmt.Errorf("category with ID %d has %d topics: %w", id, topicCount, ErrCategoryHasTopics)
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, `
+	DELETE FROM categories
+	WHERE id = ? AND created_by = ?`, id, userID)
 	if err != nil {
 		return fmt.Errorf("exec failed: %w", err)
 	}
@@ -272,7 +313,7 @@ func (r *Repo) DeleteCategory(ctx context.Context, id int, userID string) error
 func (r *Repo) UpdateCategory(ctx context.Context, category *category.Category) error {
 	query := `
 	UPDATE categories
-	SET name = ?, description = ?
+	SET name = ?, description = ?, requires_image = ?
 	WHERE id = ?
 	`
 
@@ -285,6 +326,7 @@ func (r *Repo) UpdateCategory(ctx context.Context, category *category.Category)
 	result, err := stmt.ExecContext(ctx,
 		category.Name,
 		category.Description,
+		category.RequiresImage,
 		category.ID,
 	)
 	if err != nil {
@@ -306,8 +348,9 @@ func (r *Repo) UpdateCategory(ctx context.Context, category *category.Category)
 
 func (r *Repo) GetAllCategorieNamesAndIDs(ctx context.Context) ([]category.Category, error) {
 	query := `
-	SELECT id, name, color
-	FROM categories`
+	SELECT id, name, color, requires_image
+	FROM categories
+	WHERE archived = 0`
 
 	stmt, err := r.DB.PrepareContext(ctx, query)
 	if err != nil {
@@ -330,6 +373,7 @@ func (r *Repo) GetAllCategorieNamesAndIDs(ctx context.Context) ([]category.Categ
 			&category.ID,
 			&category.Name,
 			&category.Color,
+			&category.RequiresImage,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scan categories failed: %w", err)
@@ -345,3 +389,136 @@ func (r *Repo) GetAllCategorieNamesAndIDs(ctx context.Context) ([]category.Categ
 
 	return categories, nil
 }
+
+// GetCategoriesWithCounts lists every open category with the number of
+// published topics filed under it, including categories that have none.
+func (r *Repo) GetCategoriesWithCounts(ctx context.Context) ([]category.Category, error) {
+	query := `
+	SELECT c.id, c.name, c.color, c.requires_image, COUNT(DISTINCT t.id) as topic_count
+	FROM categories c
+	LEFT JOIN topic_categories tc ON c.id = tc.category_id
+	LEFT JOIN topics t ON tc.topic_id = t.id AND t.deleted_at IS NULL AND t.status = 'published'
+	WHERE c.archived = 0
+	GROUP BY c.id
+	ORDER BY c.id`
+
+	rows, err := r.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query category counts: %w", err)
+	}
+	defer rows.Close()
+
+	categories := make([]category.Category, 0)
+	for rows.Next() {
+		var category category.Category
+		err = rows.Scan(
+			&category.ID,
+			&category.Name,
+			&category.Color,
+			&category.RequiresImage,
+			&category.TopicCount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan category counts failed: %w", err)
+		}
+
+		categories = append(categories, category)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("category rows iteration failed: %w", err)
+	}
+
+	return categories, nil
+}
+
+func (r *Repo) SetCategoryArchived(ctx context.Context, id int, archived bool) error {
+	query := `
+	UPDATE categories
+	SET archived = ?
+	WHERE id = ?
+	`
+
+	stmt, err := r.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("prepare failed: %w", err)
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, archived, id)
+	if err != nil {
+		return fmt.Errorf("exec failed: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("retrieving rows affected failed: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("category with ID %d not found: %w", id, ErrCategoryNotFound)
+	}
+	return nil
+}
+
+func (r *Repo) AssignModerator(ctx context.Context, categoryID int, userID string) error {
+	var categoryExists, userExists bool
+	err := r.DB.QueryRowContext(ctx, `
+	SELECT
+		EXISTS (SELECT 1 FROM categories WHERE id = ?),
+		EXISTS (SELECT 1 FROM users WHERE id = ?)`, categoryID, userID).Scan(&categoryExists, &userExists)
+	if err != nil {
+		return fmt.Errorf("failed to look up category and user: %w", err)
+	}
+	if !categoryExists {
+		return fmt.Errorf("category with ID %d not found: %w", categoryID, ErrCategoryNotFound)
+	}
+	if !userExists {
+		return fmt.Errorf("user with ID %s not found: %w", userID, ErrUserNotFound)
+	}
+
+	query := `
+	INSERT INTO category_moderators (category_id, user_id)
+	VALUES (?, ?)
+	ON CONFLICT DO NOTHING
+	`
+
+	stmt, err := r.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("prepare failed: %w", err)
+	}
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, categoryID, userID)
+	if err != nil {
+		return fmt.Errorf("exec failed: %w", err)
+	}
+	return nil
+}
+
+func (r *Repo) UnassignModerator(ctx context.Context, categoryID int, userID string) error {
+	query := `
+	DELETE FROM category_moderators
+	WHERE category_id = ? AND user_id = ?
+	`
+
+	stmt, err := r.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("prepare failed: %w", err)
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, categoryID, userID)
+	if err != nil {
+		return fmt.Errorf("exec failed: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("retrieving rows affected failed: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("user %s in category %d: %w", userID, categoryID, ErrModeratorNotAssigned)
+	}
+	return nil
+}
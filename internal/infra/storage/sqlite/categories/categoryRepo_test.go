@@ -0,0 +1,278 @@
+package categories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/arnald/forum/internal/domain/category"
+	"github.com/arnald/forum/internal/pkg/path"
+)
+
+// newTestRepo returns a repository backed by a private in-memory database
+// with the project schema applied and two categories, "Open" and "Old".
+func newTestRepo(t *testing.T) *Repo {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	// Every connection to :memory: gets its own database, so keep just one.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	schema, err := os.ReadFile(path.NewResolver().GetPath("db/migrations/schema.sql"))
+	if err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+	_, err = db.Exec(string(schema))
+	if err != nil {
+		t.Fatalf("failed to apply schema: %v", err)
+	}
+
+	_, err = db.Exec(`
+	INSERT INTO users (id, email, username) VALUES ('admin', 'admin@example.com', 'admin');
+	INSERT INTO categories (id, name, description, created_by) VALUES
+		(1, 'Open', '', 'admin'), (2, 'Old', '', 'admin');`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+
+	return NewRepo(db)
+}
+
+func TestRepo_SetCategoryArchived(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	err := repo.SetCategoryArchived(ctx, 2, true)
+	if err != nil {
+		t.Fatalf("SetCategoryArchived() error = %v", err)
+	}
+
+	listed, err := repo.GetAllCategories(ctx, 1, 10, "id", "asc", "")
+	if err != nil {
+		t.Fatalf("GetAllCategories() error = %v", err)
+	}
+	if len(listed) != 1 || listed[0].Name != "Open" {
+		t.Errorf("GetAllCategories() = %v, want only the open category", listed)
+	}
+
+	names, err := repo.GetAllCategorieNamesAndIDs(ctx)
+	if err != nil {
+		t.Fatalf("GetAllCategorieNamesAndIDs() error = %v", err)
+	}
+	if len(names) != 1 {
+		t.Errorf("GetAllCategorieNamesAndIDs() returned %d categories, want 1", len(names))
+	}
+
+	count, err := repo.GetTotalCategoriesCount(ctx, "")
+	if err != nil {
+		t.Fatalf("GetTotalCategoriesCount() error = %v", err)
+	}
+	if count != 1 {
+		t.Errorf("GetTotalCategoriesCount() = %d, want 1", count)
+	}
+
+	archived, err := repo.GetCategoryByID(ctx, 2)
+	if err != nil {
+		t.Fatalf("GetCategoryByID() error = %v", err)
+	}
+	if !archived.Archived {
+		t.Error("GetCategoryByID() Archived = false, want true")
+	}
+
+	err = repo.SetCategoryArchived(ctx, 2, false)
+	if err != nil {
+		t.Fatalf("SetCategoryArchived() error = %v", err)
+	}
+	count, err = repo.GetTotalCategoriesCount(ctx, "")
+	if err != nil {
+		t.Fatalf("GetTotalCategoriesCount() error = %v", err)
+	}
+	if count != 2 {
+		t.Errorf("GetTotalCategoriesCount() after unarchiving = %d, want 2", count)
+	}
+
+	err = repo.SetCategoryArchived(ctx, 99, true)
+	if !errors.Is(err, ErrCategoryNotFound) {
+		t.Errorf("SetCategoryArchived() unknown id error = %v, want %v", err, ErrCategoryNotFound)
+	}
+}
+
+func TestRepo_GetCategoriesWithCounts(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	// Only published, undeleted topics count, and a topic filed under two
+	// categories counts for both.
+	_, err := repo.DB.Exec(`
+	INSERT INTO categories (id, name, description, created_by) VALUES (3, 'Empty', '', 'admin');
+	INSERT INTO users (id, email, username) VALUES ('author', 'author@example.com', 'author');
+	INSERT INTO topics (id, user_id, title, content, status, deleted_at) VALUES
+		(1, 'author', 'Published', 'content', 'published', NULL),
+		(2, 'author', 'Draft', 'content', 'draft', NULL),
+		(3, 'author', 'Deleted', 'content', 'published', CURRENT_TIMESTAMP),
+		(4, 'author', 'Both', 'content', 'published', NULL);
+	INSERT INTO topic_categories (topic_id, category_id) VALUES (1, 1), (2, 1), (3, 1), (4, 1), (4, 2);`)
+	if err != nil {
+		t.Fatalf("failed to seed topics: %v", err)
+	}
+
+	got, err := repo.GetCategoriesWithCounts(ctx)
+	if err != nil {
+		t.Fatalf("GetCategoriesWithCounts() error = %v", err)
+	}
+
+	want := map[string]int{"Open": 2, "Old": 1, "Empty": 0}
+	if len(got) != len(want) {
+		t.Fatalf("GetCategoriesWithCounts() returned %d categories, want %d", len(got), len(want))
+	}
+	for _, c := range got {
+		if c.TopicCount != want[c.Name] {
+			t.Errorf("category %q TopicCount = %d, want %d", c.Name, c.TopicCount, want[c.Name])
+		}
+	}
+}
+
+func TestRepo_DeleteCategory_WithTopics(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.DB.Exec(`
+	INSERT INTO users (id, email, username) VALUES ('author', 'author@example.com', 'author');
+	INSERT INTO topics (id, user_id, title, content) VALUES (1, 'author', 'Filed', 'content');
+	INSERT INTO topic_categories (topic_id, category_id) VALUES (1, 1);`)
+	if err != nil {
+		t.Fatalf("failed to seed topics: %v", err)
+	}
+
+	err = repo.DeleteCategory(ctx, 1, "admin", false)
+	if !errors.Is(err, ErrCategoryHasTopics) {
+		t.Fatalf("DeleteCategory() error = %v, want %v", err, ErrCategoryHasTopics)
+	}
+	_, err = repo.GetCategoryByID(ctx, 1)
+	if err != nil {
+		t.Fatalf("blocked delete removed the category: %v", err)
+	}
+
+	err = repo.DeleteCategory(ctx, 2, "admin", false)
+	if err != nil {
+		t.Fatalf("DeleteCategory() of an empty category error = %v", err)
+	}
+
+	err = repo.DeleteCategory(ctx, 1, "admin", true)
+	if err != nil {
+		t.Fatalf("forced DeleteCategory() error = %v", err)
+	}
+	_, err = repo.GetCategoryByID(ctx, 1)
+	if !errors.Is(err, ErrCategoryNotFound) {
+		t.Errorf("GetCategoryByID() after forced delete error = %v, want %v", err, ErrCategoryNotFound)
+	}
+
+	var filed int
+	err = repo.DB.QueryRow(`SELECT COUNT(*) FROM topic_categories WHERE topic_id = 1`).Scan(&filed)
+	if err != nil {
+		t.Fatalf("failed to count topic categories: %v", err)
+	}
+	if filed != 0 {
+		t.Errorf("topic still filed under %d categories, want the cascade to clear it", filed)
+	}
+}
+
+func TestRepo_DeleteCategory_NotFound(t *testing.T) {
+	repo := newTestRepo(t)
+
+	err := repo.DeleteCategory(context.Background(), 1, "someone-else", false)
+	if !errors.Is(err, ErrCategoryNotFound) {
+		t.Errorf("DeleteCategory() by a non-owner error = %v, want %v", err, ErrCategoryNotFound)
+	}
+}
+
+func TestRepo_CreateCategory_Color(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		color     string
+		wantColor string
+		wantID    int
+	}{
+		{name: "with a color", color: "#FA6400", wantColor: "#FA6400", wantID: 3},
+		{name: "without a color", color: "", wantColor: "#CCCCCC", wantID: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := repo.CreateCategory(ctx, &category.Category{
+				Name:      "Category " + tt.name,
+				Color:     tt.color,
+				CreatedBy: "admin",
+			})
+			if err != nil {
+				t.Fatalf("CreateCategory() error = %v", err)
+			}
+
+			got, err := repo.GetCategoryByID(ctx, tt.wantID)
+			if err != nil {
+				t.Fatalf("GetCategoryByID() error = %v", err)
+			}
+			if got.Color != tt.wantColor {
+				t.Errorf("Color = %q, want %q", got.Color, tt.wantColor)
+			}
+		})
+	}
+}
+
+func TestRepo_CategoryModerators(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	assigned := func() int {
+		t.Helper()
+		var count int
+		err := repo.DB.QueryRow(`SELECT COUNT(*) FROM category_moderators WHERE category_id = 1 AND user_id = 'admin'`).Scan(&count)
+		if err != nil {
+			t.Fatalf("failed to count moderators: %v", err)
+		}
+		return count
+	}
+
+	for range 2 {
+		err := repo.AssignModerator(ctx, 1, "admin")
+		if err != nil {
+			t.Fatalf("AssignModerator() error = %v", err)
+		}
+	}
+	if got := assigned(); got != 1 {
+		t.Errorf("after assigning twice, %d assignments, want 1", got)
+	}
+
+	err := repo.AssignModerator(ctx, 99, "admin")
+	if !errors.Is(err, ErrCategoryNotFound) {
+		t.Errorf("AssignModerator() on a missing category error = %v, want %v", err, ErrCategoryNotFound)
+	}
+	err = repo.AssignModerator(ctx, 1, "nobody")
+	if !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("AssignModerator() for a missing user error = %v, want %v", err, ErrUserNotFound)
+	}
+
+	err = repo.UnassignModerator(ctx, 1, "admin")
+	if err != nil {
+		t.Fatalf("UnassignModerator() error = %v", err)
+	}
+	if got := assigned(); got != 0 {
+		t.Errorf("after unassigning, %d assignments, want 0", got)
+	}
+
+	err = repo.UnassignModerator(ctx, 1, "admin")
+	if !errors.Is(err, ErrModeratorNotAssigned) {
+		t.Errorf("UnassignModerator() again error = %v, want %v", err, ErrModeratorNotAssigned)
+	}
+}
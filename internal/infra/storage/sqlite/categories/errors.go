@@ -5,5 +5,7 @@ import "errors"
 var (
 	ErrCategoryAlreadyExists = errors.New("category already exists")
 	ErrCategoryNotFound      = errors.New("category not found")
+	ErrCategoryHasTopics     = errors.New("category still has topics")
 	ErrUserNotFound          = errors.New("user not found")
+	ErrModeratorNotAssigned  = errors.New("user is not a moderator of this category")
 )
@@ -5,11 +5,21 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/arnald/forum/internal/domain/audit"
 	"github.com/arnald/forum/internal/domain/comment"
+	"github.com/arnald/forum/internal/domain/notification"
 )
 
+// editedExpr flags comments updated more than a minute after they were
+// posted. A new row starts with equal timestamps, and a typo fixed straight
+// after posting is not worth marking.
+const editedExpr = "COALESCE((julianday(c.updated_at) - julianday(c.created_at)) * 86400 > 60, 0)"
+
 type Repo struct {
 	DB *sql.DB
 }
@@ -22,8 +32,8 @@ func NewRepo(db *sql.DB) *Repo {
 
 func (r *Repo) CreateComment(ctx context.Context, comment *comment.Comment) error {
 	query := `
-	INSERT INTO comments (user_id, topic_id, content)
-	VALUES (?, ?, ?)`
+	INSERT INTO comments (user_id, topic_id, parent_id, content, status)
+	VALUES (?, ?, ?, ?, COALESCE(NULLIF(?, ''), 'approved'))`
 
 	stmt, err := r.DB.PrepareContext(ctx, query)
 	if err != nil {
@@ -35,7 +45,9 @@ func (r *Repo) CreateComment(ctx context.Context, comment *comment.Comment) erro
 		ctx,
 		comment.UserID,
 		comment.TopicID,
+		comment.ParentID,
 		comment.Content,
+		comment.Status,
 	)
 	if err != nil {
 		switch {
@@ -102,7 +114,7 @@ func (r *Repo) UpdateComment(ctx context.Context, comment *comment.Comment) (err
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("comment with ID %d %w", comment.ID, ErrFailedToUpdate)
+		return missingOrNotOwned(tx.QueryRowContext(ctx, commentExistsQuery, comment.ID), comment.ID)
 	}
 
 	return nil
@@ -130,16 +142,34 @@ func (r *Repo) DeleteComment(ctx context.Context, userID string, commentID int)
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("comment with ID %d not found or user not authorized: %w", commentID, ErrCommentNotFound)
+		return missingOrNotOwned(r.DB.QueryRowContext(ctx, commentExistsQuery, commentID), commentID)
 	}
 
 	return nil
 }
 
+const commentExistsQuery = `SELECT 1 FROM comments WHERE id = ?`
+
+// missingOrNotOwned explains why an update or delete scoped to the author
+// matched no rows, given the result of commentExistsQuery.
+func missingOrNotOwned(row *sql.Row, commentID int) error {
+	var exists int
+	err := row.Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("comment with ID %d: %w", commentID, ErrCommentNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up comment: %w", err)
+	}
+
+	return fmt.Errorf("comment with ID %d: %w", commentID, ErrNotCommentAuthor)
+}
+
 func (r *Repo) GetCommentByID(ctx context.Context, commentID int) (*comment.Comment, error) {
 	query := `
 	SELECT 
-		c.id, c.user_id, c.topic_id, c.content, c.created_at, c.updated_at, u.username
+		c.id, c.user_id, c.topic_id, c.parent_id, c.content, c.status, COALESCE(c.moderated_by, ''),
+		c.rejection_reason, c.created_at, c.updated_at, ` + editedExpr + `, c.report_count, c.collapsed, COALESCE(u.username, '')
 	FROM comments c
 	LEFT JOIN users u ON c.user_id = u.id
 	WHERE c.id = ?`
@@ -151,13 +181,21 @@ func (r *Repo) GetCommentByID(ctx context.Context, commentID int) (*comment.Comm
 	defer stmt.Close()
 
 	comment := &comment.Comment{}
+	var parentID sql.NullInt64
 	err = stmt.QueryRowContext(ctx, commentID).Scan(
 		&comment.ID,
 		&comment.UserID,
 		&comment.TopicID,
+		&parentID,
 		&comment.Content,
+		&comment.Status,
+		&comment.ModeratedBy,
+		&comment.RejectionReason,
 		&comment.CreatedAt,
 		&comment.UpdatedAt,
+		&comment.IsEdited,
+		&comment.ReportCount,
+		&comment.Collapsed,
 		&comment.OwnerUsername,
 	)
 	if err != nil {
@@ -167,6 +205,8 @@ func (r *Repo) GetCommentByID(ctx context.Context, commentID int) (*comment.Comm
 		return nil, fmt.Errorf("failed to query comment: %w", err)
 	}
 
+	comment.ParentID = nullIntToPtr(parentID)
+
 	// Format Dates
 	if comment.CreatedAt != "" {
 		t, parseErr := time.Parse(time.RFC3339, comment.CreatedAt)
@@ -188,10 +228,10 @@ func (r *Repo) GetCommentByID(ctx context.Context, commentID int) (*comment.Comm
 func (r *Repo) GetCommentsByTopicID(ctx context.Context, topicID int) ([]comment.Comment, error) {
 	query := `
 	SELECT 
-		c.id, c.user_id, c.topic_id, c.content, c.created_at, c.updated_at, u.username
+		c.id, c.user_id, c.topic_id, c.parent_id, c.content, c.created_at, c.updated_at, ` + editedExpr + `, u.username
 	FROM comments c
 	LEFT JOIN users u ON c.user_id = u.id
-	WHERE c.topic_id = ?
+	WHERE c.topic_id = ? AND c.status = 'approved'
 	ORDER BY c.created_at ASC`
 
 	stmt, err := r.DB.PrepareContext(ctx, query)
@@ -209,19 +249,24 @@ func (r *Repo) GetCommentsByTopicID(ctx context.Context, topicID int) ([]comment
 	comments := make([]comment.Comment, 0)
 	for rows.Next() {
 		var c comment.Comment
+		var parentID sql.NullInt64
 		err = rows.Scan(
 			&c.ID,
 			&c.UserID,
 			&c.TopicID,
+			&parentID,
 			&c.Content,
 			&c.CreatedAt,
 			&c.UpdatedAt,
+			&c.IsEdited,
 			&c.OwnerUsername,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
 
+		c.ParentID = nullIntToPtr(parentID)
+
 		// Format Dates
 		if c.CreatedAt != "" {
 			t, parseErr := time.Parse(time.RFC3339, c.CreatedAt)
@@ -249,9 +294,38 @@ func (r *Repo) GetCommentsByTopicID(ctx context.Context, topicID int) ([]comment
 }
 
 func (r *Repo) GetCommentsWithVotes(ctx context.Context, topicID int, userID *string) ([]comment.Comment, error) {
+	return r.GetCommentsWithVotesPage(ctx, topicID, userID, 0, 0)
+}
+
+// GetCommentsWithVotesPage returns up to limit of the comments visible to
+// userID, oldest first, skipping the first offset. A limit of zero or less
+// returns every comment after offset. Ties on created_at are broken by id so
+// that a comment never appears on two pages.
+func (r *Repo) GetCommentsWithVotesPage(ctx context.Context, topicID int, userID *string, limit, offset int) ([]comment.Comment, error) {
+	return r.queryCommentsWithVotes(ctx, topicID, 0, userID, limit, offset)
+}
+
+// GetCommentWithVotes returns a single comment of the topic, as
+// GetCommentsWithVotes would list it for userID.
+func (r *Repo) GetCommentWithVotes(ctx context.Context, topicID, commentID int, userID *string) (*comment.Comment, error) {
+	comments, err := r.queryCommentsWithVotes(ctx, topicID, commentID, userID, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(comments) == 0 {
+		return nil, fmt.Errorf("comment with ID %d not found: %w", commentID, ErrCommentNotFound)
+	}
+
+	return &comments[0], nil
+}
+
+// queryCommentsWithVotes backs GetCommentsWithVotesPage and, with a non-zero
+// commentID, GetCommentWithVotes.
+func (r *Repo) queryCommentsWithVotes(ctx context.Context, topicID, commentID int, userID *string, limit, offset int) ([]comment.Comment, error) {
 	query := `
 	SELECT
-		c.id, c.user_id, c.topic_id, c.content, c.created_at, c.updated_at,
+		c.id, c.user_id, c.topic_id, c.parent_id, c.content, c.status, c.created_at, c.updated_at,
+		` + editedExpr + `, c.report_count, c.collapsed,
 		u.username,
 		COALESCE(vote_counts.upvotes, 0) as upvote_count,
 		COALESCE(vote_counts.downvotes,0) as downvote_count,
@@ -262,6 +336,8 @@ func (r *Repo) GetCommentsWithVotes(ctx context.Context, topicID int, userID *st
 		user_vote.reaction_type`
 	}
 
+	// Votes are tallied in the same statement, and only for this topic's
+	// comments, so the cost does not grow with comments elsewhere.
 	query += `
 	FROM comments c
 	LEFT JOIN users u ON c.user_id = u.id
@@ -272,7 +348,7 @@ func (r *Repo) GetCommentsWithVotes(ctx context.Context, topicID int, userID *st
 			COUNT(CASE WHEN reaction_type = -1 THEN 1 END) as downvotes,
 			(COUNT(CASE WHEN reaction_type = 1 THEN 1 END) - COUNT(CASE WHEN reaction_type = -1 THEN 1 END)) as score
 			FROM votes
-			WHERE comment_id IS NOT NULL
+			WHERE comment_id IN (SELECT id FROM comments WHERE topic_id = ?)
 			GROUP BY comment_id
 		) vote_counts ON c.id = vote_counts.comment_id`
 
@@ -282,13 +358,38 @@ func (r *Repo) GetCommentsWithVotes(ctx context.Context, topicID int, userID *st
 		AND user_vote.user_id = ?`
 	}
 
-	query += ` WHERE c.topic_id = ? ORDER BY c.created_at ASC`
+	// Comments waiting for moderation are only shown to their author.
+	query += ` WHERE c.topic_id = ?`
+	if userID != nil {
+		query += ` AND (c.status = 'approved' OR c.user_id = ?)`
+	} else {
+		query += ` AND c.status = 'approved'`
+	}
+	if commentID > 0 {
+		query += ` AND c.id = ?`
+	}
+	query += ` ORDER BY c.created_at ASC, c.id ASC`
 
-	args := make([]interface{}, 0)
+	args := []interface{}{topicID}
 	if userID != nil {
 		args = append(args, *userID)
 	}
 	args = append(args, topicID)
+	if userID != nil {
+		args = append(args, *userID)
+	}
+	if commentID > 0 {
+		args = append(args, commentID)
+	}
+
+	// SQLite only accepts OFFSET after a LIMIT; -1 means no limit.
+	if limit > 0 || offset > 0 {
+		if limit <= 0 {
+			limit = -1
+		}
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, limit, max(offset, 0))
+	}
 
 	stmt, err := r.DB.PrepareContext(ctx, query)
 	if err != nil {
@@ -306,14 +407,20 @@ func (r *Repo) GetCommentsWithVotes(ctx context.Context, topicID int, userID *st
 	for rows.Next() {
 		var commentResult comment.Comment
 		var userVote sql.NullInt32
+		var parentID sql.NullInt64
 
 		scanFields := []interface{}{
 			&commentResult.ID,
 			&commentResult.UserID,
 			&commentResult.TopicID,
+			&parentID,
 			&commentResult.Content,
+			&commentResult.Status,
 			&commentResult.CreatedAt,
 			&commentResult.UpdatedAt,
+			&commentResult.IsEdited,
+			&commentResult.ReportCount,
+			&commentResult.Collapsed,
 			&commentResult.OwnerUsername,
 			&commentResult.UpvoteCount,
 			&commentResult.DownvoteCount,
@@ -329,6 +436,8 @@ func (r *Repo) GetCommentsWithVotes(ctx context.Context, topicID int, userID *st
 			return nil, fmt.Errorf("failed to scan comment: %w", err)
 		}
 
+		commentResult.ParentID = nullIntToPtr(parentID)
+
 		// Format Dates
 		if commentResult.CreatedAt != "" {
 			t, parseErr := time.Parse(time.RFC3339, commentResult.CreatedAt)
@@ -359,3 +468,333 @@ func (r *Repo) GetCommentsWithVotes(ctx context.Context, topicID int, userID *st
 
 	return comments, nil
 }
+
+// CountVisibleComments counts the comments GetCommentsWithVotes would return
+// for the same topic and viewer.
+func (r *Repo) CountVisibleComments(ctx context.Context, topicID int, userID *string) (int, error) {
+	query := `SELECT COUNT(*) FROM comments WHERE topic_id = ?`
+	args := []interface{}{topicID}
+	if userID != nil {
+		query += ` AND (status = 'approved' OR user_id = ?)`
+		args = append(args, *userID)
+	} else {
+		query += ` AND status = 'approved'`
+	}
+
+	var count int
+	err := r.DB.QueryRowContext(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count comments: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetPendingComments returns the moderation queue, oldest first.
+// PendingCommentsQuery reads the moderation queue. It takes the moderator ID
+// twice, empty for the whole queue. It is exported so the index tests can
+// check the plan of the query the repository actually runs.
+const PendingCommentsQuery = `
+	SELECT
+		c.id, c.user_id, c.topic_id, c.parent_id, c.content, c.status, c.created_at, c.updated_at,
+		` + editedExpr + `, u.username
+	FROM comments c
+	INNER JOIN topics t ON c.topic_id = t.id AND t.deleted_at IS NULL
+	LEFT JOIN users u ON c.user_id = u.id
+	WHERE c.status = 'pending' AND (? = '' OR EXISTS (
+		SELECT 1 FROM topic_categories tc
+		INNER JOIN category_moderators cm ON cm.category_id = tc.category_id
+		WHERE tc.topic_id = c.topic_id AND cm.user_id = ?
+	))
+	ORDER BY c.created_at ASC, c.id ASC`
+
+func (r *Repo) GetPendingComments(ctx context.Context, moderatorID string) ([]comment.Comment, error) {
+	stmt, err := r.DB.PrepareContext(ctx, PendingCommentsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("prepare failed: %w", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, moderatorID, moderatorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := make([]comment.Comment, 0)
+	for rows.Next() {
+		var c comment.Comment
+		var parentID sql.NullInt64
+		err = rows.Scan(
+			&c.ID,
+			&c.UserID,
+			&c.TopicID,
+			&parentID,
+			&c.Content,
+			&c.Status,
+			&c.CreatedAt,
+			&c.UpdatedAt,
+			&c.IsEdited,
+			&c.OwnerUsername,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		c.ParentID = nullIntToPtr(parentID)
+		comments = append(comments, c)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return comments, nil
+}
+
+// SetCommentStatus settles a pending comment and records which moderator did
+// it and, for a rejection, why. Comments that are not pending are reported as
+// not found so a decision cannot be made twice.
+// SetCommentStatus settles the comment and stores the author's notice in one
+// transaction, so a decision is never recorded without its notice or the
+// other way round.
+func (r *Repo) SetCommentStatus(ctx context.Context, commentID int, status, moderatorID, reason string, notice *notification.Notification) (err error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				err = fmt.Errorf("transaction rollback failed: %w (original error: %w)", rollbackErr, err)
+			}
+			return
+		}
+		commitErr := tx.Commit()
+		if commitErr != nil {
+			err = fmt.Errorf("transaction commit failed: %w", commitErr)
+		}
+	}()
+
+	query := `
+	UPDATE comments
+	SET status = ?, moderated_by = ?, rejection_reason = ?
+	WHERE id = ? AND status = 'pending'
+	RETURNING user_id, topic_id`
+
+	var authorID string
+	var topicID int
+	err = tx.QueryRowContext(ctx, query, status, moderatorID, reason, commentID).Scan(&authorID, &topicID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("pending comment with ID %d not found: %w", commentID, ErrCommentNotFound)
+		}
+		return fmt.Errorf("failed to update comment status: %w", err)
+	}
+
+	if notice == nil {
+		return nil
+	}
+
+	notice.UserID = authorID
+	notice.RelatedID = strconv.Itoa(topicID)
+
+	// Selecting the recipient from users leaves the notice out, rather than
+	// failing the decision, when the author's account no longer exists.
+	noticeQuery := `
+	INSERT INTO notifications (user_id, type, title, message, related_type, related_id, is_read)
+	SELECT id, ?, ?, ?, ?, ?, 0 FROM users WHERE id = ?`
+
+	result, err := tx.ExecContext(ctx, noticeQuery,
+		notice.Type, notice.Title, notice.Message, notice.RelatedType, notice.RelatedID, authorID)
+	if err != nil {
+		return fmt.Errorf("failed to store moderation notice: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return nil
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get notice id: %w", err)
+	}
+	notice.ID = int(id)
+
+	return nil
+}
+
+func (r *Repo) SetCommentsStatus(ctx context.Context, commentIDs []int, status, moderatorID string, auditEntry func(settled *comment.Comment) *audit.Entry) (moderated []int, err error) {
+	if len(commentIDs) == 0 {
+		return []int{}, nil
+	}
+
+	placeholders := make([]string, len(commentIDs))
+	args := make([]interface{}, 0, len(commentIDs)+2)
+	args = append(args, status, moderatorID)
+	for i, id := range commentIDs {
+		placeholders[i] = "?"
+		args = append(args, id)
+	}
+
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				err = fmt.Errorf("transaction rollback failed: %w (original error: %w)", rollbackErr, err)
+			}
+			return
+		}
+		commitErr := tx.Commit()
+		if commitErr != nil {
+			err = fmt.Errorf("transaction commit failed: %w", commitErr)
+		}
+	}()
+
+	// A single UPDATE settles the whole selection at once, and the audit
+	// entries share its transaction, so a failure part way through leaves
+	// every comment pending and nothing logged.
+	query := `
+	UPDATE comments
+	SET status = ?, moderated_by = ?
+	WHERE status = 'pending' AND id IN (` + strings.Join(placeholders, ",") + `)
+	RETURNING id, user_id, topic_id`
+
+	settled, err := scanSettledComments(ctx, tx, query, args, status, moderatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(settled, func(a, b *comment.Comment) int { return a.ID - b.ID })
+
+	moderated = make([]int, 0, len(settled))
+	for _, c := range settled {
+		if auditEntry != nil {
+			err = insertAuditEntry(ctx, tx, auditEntry(c))
+			if err != nil {
+				return nil, err
+			}
+		}
+		moderated = append(moderated, c.ID)
+	}
+
+	return moderated, nil
+}
+
+func scanSettledComments(ctx context.Context, tx *sql.Tx, query string, args []interface{}, status, moderatorID string) ([]*comment.Comment, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update comment statuses: %w", err)
+	}
+	defer rows.Close()
+
+	var settled []*comment.Comment
+	for rows.Next() {
+		c := &comment.Comment{Status: status, ModeratedBy: moderatorID}
+		err = rows.Scan(&c.ID, &c.UserID, &c.TopicID)
+		if err != nil {
+			return nil, fmt.Errorf("scan moderated comment failed: %w", err)
+		}
+		settled = append(settled, c)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return settled, nil
+}
+
+func insertAuditEntry(ctx context.Context, tx *sql.Tx, entry *audit.Entry) error {
+	query := `
+	INSERT INTO audit_log (admin_id, action, target_type, target_id, details)
+	VALUES (?, ?, ?, ?, ?)
+	RETURNING id, created_at`
+
+	err := tx.QueryRowContext(ctx, query,
+		entry.AdminID,
+		entry.Action,
+		entry.TargetType,
+		entry.TargetID,
+		entry.Details,
+	).Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repo) CommentsOutsideModeration(ctx context.Context, moderatorID string, commentIDs []int) ([]int, error) {
+	if len(commentIDs) == 0 {
+		return []int{}, nil
+	}
+
+	placeholders := make([]string, len(commentIDs))
+	args := make([]interface{}, 0, len(commentIDs)+1)
+	args = append(args, moderatorID)
+	for i, id := range commentIDs {
+		placeholders[i] = "?"
+		args = append(args, id)
+	}
+
+	query := `
+	SELECT c.id
+	FROM comments c
+	WHERE NOT EXISTS (
+		SELECT 1 FROM topic_categories tc
+		INNER JOIN category_moderators cm ON cm.category_id = tc.category_id
+		WHERE tc.topic_id = c.topic_id AND cm.user_id = ?
+	) AND c.id IN (` + strings.Join(placeholders, ",") + `)
+	ORDER BY c.id`
+
+	stmt, err := r.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("prepare failed: %w", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check moderation scope: %w", err)
+	}
+	defer rows.Close()
+
+	outside := make([]int, 0)
+	for rows.Next() {
+		var id int
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, fmt.Errorf("scan comment id failed: %w", err)
+		}
+		outside = append(outside, id)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return outside, nil
+}
+
+func nullIntToPtr(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+	v := int(value.Int64)
+	return &v
+}
@@ -0,0 +1,770 @@
+package comments
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/arnald/forum/internal/domain/audit"
+	"github.com/arnald/forum/internal/domain/comment"
+	"github.com/arnald/forum/internal/domain/notification"
+	"github.com/arnald/forum/internal/pkg/path"
+)
+
+// newTestRepo returns a repository backed by a private in-memory database
+// with the project schema applied, one topic and two users.
+func newTestRepo(t testing.TB) *Repo {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	// Every connection to :memory: gets its own database, so keep just one.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	schema, err := os.ReadFile(path.NewResolver().GetPath("db/migrations/schema.sql"))
+	if err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+	_, err = db.Exec(string(schema))
+	if err != nil {
+		t.Fatalf("failed to apply schema: %v", err)
+	}
+
+	_, err = db.Exec(`
+	INSERT INTO users (id, email, username) VALUES
+		('author', 'author@example.com', 'author'),
+		('reader', 'reader@example.com', 'reader');
+	INSERT INTO topics (id, user_id, title, content) VALUES (1, 'reader', 'Topic', 'content');`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+
+	return NewRepo(db)
+}
+
+func TestRepo_PendingCommentVisibility(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	held := &comment.Comment{UserID: "author", TopicID: 1, Content: "held", Status: comment.StatusPending}
+	err := repo.CreateComment(ctx, held)
+	if err != nil {
+		t.Fatalf("CreateComment() error = %v", err)
+	}
+
+	author, reader := "author", "reader"
+	visible := func(viewer *string) int {
+		t.Helper()
+		got, listErr := repo.GetCommentsWithVotes(ctx, 1, viewer)
+		if listErr != nil {
+			t.Fatalf("GetCommentsWithVotes() error = %v", listErr)
+		}
+		return len(got)
+	}
+
+	if n := visible(&author); n != 1 {
+		t.Errorf("author sees %d comments, want their pending one", n)
+	}
+	if n := visible(&reader); n != 0 {
+		t.Errorf("reader sees %d comments, want the pending one hidden", n)
+	}
+	if n := visible(nil); n != 0 {
+		t.Errorf("guest sees %d comments, want the pending one hidden", n)
+	}
+
+	pending, err := repo.GetPendingComments(ctx, "")
+	if err != nil {
+		t.Fatalf("GetPendingComments() error = %v", err)
+	}
+	if len(pending) != 1 || pending[0].ID != held.ID {
+		t.Fatalf("GetPendingComments() = %v, want the held comment", pending)
+	}
+
+	err = repo.SetCommentStatus(ctx, held.ID, comment.StatusApproved, reader, "", nil)
+	if err != nil {
+		t.Fatalf("SetCommentStatus() error = %v", err)
+	}
+	if n := visible(&reader); n != 1 {
+		t.Errorf("reader sees %d comments after approval, want 1", n)
+	}
+
+	err = repo.SetCommentStatus(ctx, held.ID, comment.StatusRejected, reader, "", nil)
+	if !errors.Is(err, ErrCommentNotFound) {
+		t.Errorf("SetCommentStatus() on a settled comment error = %v, want %v", err, ErrCommentNotFound)
+	}
+}
+
+func TestRepo_CreateComment_DefaultsToApproved(t *testing.T) {
+	repo := newTestRepo(t)
+
+	created := &comment.Comment{UserID: "author", TopicID: 1, Content: "hello"}
+	err := repo.CreateComment(context.Background(), created)
+	if err != nil {
+		t.Fatalf("CreateComment() error = %v", err)
+	}
+
+	got, err := repo.GetCommentByID(context.Background(), created.ID)
+	if err != nil {
+		t.Fatalf("GetCommentByID() error = %v", err)
+	}
+	if got.Status != comment.StatusApproved {
+		t.Errorf("Status = %q, want %q", got.Status, comment.StatusApproved)
+	}
+}
+
+func TestRepo_SetCommentStatus_RecordsModerator(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	held := &comment.Comment{UserID: "author", TopicID: 1, Content: "hello", Status: comment.StatusPending}
+	err := repo.CreateComment(ctx, held)
+	if err != nil {
+		t.Fatalf("CreateComment() error = %v", err)
+	}
+
+	err = repo.SetCommentStatus(ctx, held.ID, comment.StatusRejected, "reader", "", nil)
+	if err != nil {
+		t.Fatalf("SetCommentStatus() error = %v", err)
+	}
+
+	got, err := repo.GetCommentByID(ctx, held.ID)
+	if err != nil {
+		t.Fatalf("GetCommentByID() error = %v", err)
+	}
+	if got.ModeratedBy != "reader" {
+		t.Errorf("ModeratedBy = %q, want the real moderator id %q", got.ModeratedBy, "reader")
+	}
+}
+
+func TestRepo_SetCommentStatus_RejectionReason(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	held := &comment.Comment{UserID: "author", TopicID: 1, Content: "buy now", Status: comment.StatusPending}
+	err := repo.CreateComment(ctx, held)
+	if err != nil {
+		t.Fatalf("CreateComment() error = %v", err)
+	}
+
+	err = repo.SetCommentStatus(ctx, held.ID, comment.StatusRejected, "reader", "Spam or advertising", nil)
+	if err != nil {
+		t.Fatalf("SetCommentStatus() error = %v", err)
+	}
+
+	got, err := repo.GetCommentByID(ctx, held.ID)
+	if err != nil {
+		t.Fatalf("GetCommentByID() error = %v", err)
+	}
+	if got.RejectionReason != "Spam or advertising" {
+		t.Errorf("RejectionReason = %q, want %q", got.RejectionReason, "Spam or advertising")
+	}
+}
+
+func TestRepo_SetCommentStatus_Notice(t *testing.T) {
+	ctx := context.Background()
+
+	settle := func(t *testing.T, repo *Repo) (*notification.Notification, int) {
+		t.Helper()
+
+		held := &comment.Comment{UserID: "author", TopicID: 1, Content: "hello", Status: comment.StatusPending}
+		err := repo.CreateComment(ctx, held)
+		if err != nil {
+			t.Fatalf("CreateComment() error = %v", err)
+		}
+
+		notice := &notification.Notification{
+			Type:    notification.NotificationTypeModeration,
+			Title:   "Comment approved",
+			Message: "Your comment was approved by reader",
+		}
+		return notice, held.ID
+	}
+
+	countNotices := func(t *testing.T, repo *Repo) int {
+		t.Helper()
+		var n int
+		err := repo.DB.QueryRow(`SELECT COUNT(*) FROM notifications`).Scan(&n)
+		if err != nil {
+			t.Fatalf("failed to count notifications: %v", err)
+		}
+		return n
+	}
+
+	t.Run("stores the notice with the decision", func(t *testing.T) {
+		repo := newTestRepo(t)
+		notice, commentID := settle(t, repo)
+
+		err := repo.SetCommentStatus(ctx, commentID, comment.StatusApproved, "reader", "", notice)
+		if err != nil {
+			t.Fatalf("SetCommentStatus() error = %v", err)
+		}
+		if notice.ID == 0 || notice.UserID != "author" || notice.RelatedID != "1" {
+			t.Errorf("notice = %+v, want it stored for author about topic 1", notice)
+		}
+		if n := countNotices(t, repo); n != 1 {
+			t.Errorf("stored %d notifications, want 1", n)
+		}
+	})
+
+	t.Run("skips the notice when the author is gone", func(t *testing.T) {
+		repo := newTestRepo(t)
+		notice, commentID := settle(t, repo)
+
+		// With foreign keys on, deleting the author takes the comment too;
+		// switch them off to leave the comment behind its author.
+		_, err := repo.DB.Exec(`PRAGMA foreign_keys = OFF; DELETE FROM users WHERE id = 'author'`)
+		if err != nil {
+			t.Fatalf("failed to delete author: %v", err)
+		}
+
+		err = repo.SetCommentStatus(ctx, commentID, comment.StatusApproved, "reader", "", notice)
+		if err != nil {
+			t.Fatalf("SetCommentStatus() error = %v", err)
+		}
+		if notice.ID != 0 {
+			t.Errorf("notice ID = %d, want 0 for a missing author", notice.ID)
+		}
+		if n := countNotices(t, repo); n != 0 {
+			t.Errorf("stored %d notifications, want none", n)
+		}
+
+		got, err := repo.GetCommentByID(ctx, commentID)
+		if err != nil {
+			t.Fatalf("GetCommentByID() error = %v", err)
+		}
+		if got.Status != comment.StatusApproved {
+			t.Errorf("Status = %q, want the approval to stand", got.Status)
+		}
+	})
+}
+
+func TestRepo_GetCommentsWithVotes_Counts(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.DB.Exec(`
+	INSERT INTO topics (id, user_id, title, content) VALUES (2, 'reader', 'Other', 'content');
+	INSERT INTO comments (id, user_id, topic_id, content, created_at) VALUES
+		(1, 'author', 1, 'first', '2024-01-01T10:00:00Z'),
+		(2, 'author', 1, 'second', '2024-01-01T11:00:00Z'),
+		(3, 'author', 2, 'elsewhere', '2024-01-01T09:00:00Z');
+	INSERT INTO votes (user_id, comment_id, reaction_type) VALUES
+		('author', 1, 1), ('reader', 1, -1), ('reader', 3, 1);`)
+	if err != nil {
+		t.Fatalf("failed to seed comments: %v", err)
+	}
+
+	reader := "reader"
+	got, err := repo.GetCommentsWithVotes(ctx, 1, &reader)
+	if err != nil {
+		t.Fatalf("GetCommentsWithVotes() error = %v", err)
+	}
+	if len(got) != 2 || got[0].ID != 1 || got[1].ID != 2 {
+		t.Fatalf("GetCommentsWithVotes() = %v, want comments 1 and 2 oldest first", got)
+	}
+	if got[0].UpvoteCount != 1 || got[0].DownvoteCount != 1 || got[0].VoteScore != 0 {
+		t.Errorf("comment 1 counts = %d/%d/%d, want 1/1/0", got[0].UpvoteCount, got[0].DownvoteCount, got[0].VoteScore)
+	}
+	if got[0].UserVote == nil || *got[0].UserVote != -1 {
+		t.Errorf("comment 1 UserVote = %v, want -1", got[0].UserVote)
+	}
+	if got[1].UpvoteCount != 0 || got[1].DownvoteCount != 0 || got[1].UserVote != nil {
+		t.Errorf("comment 2 = %d/%d, vote %v, want no votes", got[1].UpvoteCount, got[1].DownvoteCount, got[1].UserVote)
+	}
+}
+
+func TestRepo_CommentOwnership(t *testing.T) {
+	ctx := context.Background()
+
+	newComment := func(t *testing.T, repo *Repo) *comment.Comment {
+		t.Helper()
+
+		c := &comment.Comment{UserID: "author", TopicID: 1, Content: "original"}
+		err := repo.CreateComment(ctx, c)
+		if err != nil {
+			t.Fatalf("CreateComment() error = %v", err)
+		}
+		return c
+	}
+
+	assertContent := func(t *testing.T, repo *Repo, commentID int, want string) {
+		t.Helper()
+
+		got, err := repo.GetCommentByID(ctx, commentID)
+		if err != nil {
+			t.Fatalf("GetCommentByID() error = %v", err)
+		}
+		if got.Content != want {
+			t.Errorf("content = %q, want %q", got.Content, want)
+		}
+	}
+
+	t.Run("owner can edit and delete", func(t *testing.T) {
+		repo := newTestRepo(t)
+		c := newComment(t, repo)
+
+		err := repo.UpdateComment(ctx, &comment.Comment{ID: c.ID, UserID: "author", Content: "edited"})
+		if err != nil {
+			t.Fatalf("UpdateComment() error = %v", err)
+		}
+		assertContent(t, repo, c.ID, "edited")
+
+		err = repo.DeleteComment(ctx, "author", c.ID)
+		if err != nil {
+			t.Fatalf("DeleteComment() error = %v", err)
+		}
+		_, err = repo.GetCommentByID(ctx, c.ID)
+		if !errors.Is(err, ErrCommentNotFound) {
+			t.Errorf("GetCommentByID() after delete error = %v, want %v", err, ErrCommentNotFound)
+		}
+	})
+
+	t.Run("non-owner can neither edit nor delete", func(t *testing.T) {
+		repo := newTestRepo(t)
+		c := newComment(t, repo)
+
+		err := repo.UpdateComment(ctx, &comment.Comment{ID: c.ID, UserID: "reader", Content: "hijacked"})
+		if !errors.Is(err, ErrNotCommentAuthor) {
+			t.Errorf("UpdateComment() error = %v, want %v", err, ErrNotCommentAuthor)
+		}
+
+		err = repo.DeleteComment(ctx, "reader", c.ID)
+		if !errors.Is(err, ErrNotCommentAuthor) {
+			t.Errorf("DeleteComment() error = %v, want %v", err, ErrNotCommentAuthor)
+		}
+		assertContent(t, repo, c.ID, "original")
+	})
+
+	t.Run("missing comment is not found", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		err := repo.UpdateComment(ctx, &comment.Comment{ID: 99, UserID: "author", Content: "edited"})
+		if !errors.Is(err, ErrCommentNotFound) {
+			t.Errorf("UpdateComment() error = %v, want %v", err, ErrCommentNotFound)
+		}
+
+		err = repo.DeleteComment(ctx, "author", 99)
+		if !errors.Is(err, ErrCommentNotFound) {
+			t.Errorf("DeleteComment() error = %v, want %v", err, ErrCommentNotFound)
+		}
+	})
+}
+
+func TestRepo_CommentIsEdited(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	fresh := &comment.Comment{UserID: "author", TopicID: 1, Content: "just posted"}
+	err := repo.CreateComment(ctx, fresh)
+	if err != nil {
+		t.Fatalf("CreateComment() error = %v", err)
+	}
+
+	// A typo fixed straight after posting still counts as the original.
+	fixed := &comment.Comment{UserID: "author", TopicID: 1, Content: "posted with a typo"}
+	err = repo.CreateComment(ctx, fixed)
+	if err != nil {
+		t.Fatalf("CreateComment() error = %v", err)
+	}
+	fixed.Content = "posted without a typo"
+	err = repo.UpdateComment(ctx, fixed)
+	if err != nil {
+		t.Fatalf("UpdateComment() error = %v", err)
+	}
+
+	_, err = repo.DB.Exec(`
+	INSERT INTO comments (id, user_id, topic_id, content, created_at, updated_at) VALUES
+		(10, 'author', 1, 'edited later', '2024-01-01 10:00:00', '2024-01-01 10:05:00');`)
+	if err != nil {
+		t.Fatalf("failed to seed edited comment: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		id   int
+		want bool
+	}{
+		{name: "freshly created", id: fresh.ID, want: false},
+		{name: "updated within the grace period", id: fixed.ID, want: false},
+		{name: "updated minutes later", id: 10, want: true},
+	}
+
+	listed, err := repo.GetCommentsWithVotes(ctx, 1, nil)
+	if err != nil {
+		t.Fatalf("GetCommentsWithVotes() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, getErr := repo.GetCommentByID(ctx, tt.id)
+			if getErr != nil {
+				t.Fatalf("GetCommentByID() error = %v", getErr)
+			}
+			if got.IsEdited != tt.want {
+				t.Errorf("GetCommentByID().IsEdited = %v, want %v", got.IsEdited, tt.want)
+			}
+
+			i := slices.IndexFunc(listed, func(c comment.Comment) bool { return c.ID == tt.id })
+			if i < 0 {
+				t.Fatalf("comment %d missing from GetCommentsWithVotes()", tt.id)
+			}
+			if listed[i].IsEdited != tt.want {
+				t.Errorf("GetCommentsWithVotes() IsEdited = %v, want %v", listed[i].IsEdited, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepo_GetCommentsWithVotesPage(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	// Comments 3 and 4 share a timestamp, so only the id keeps their order
+	// stable across pages. Comment 6 is still waiting for moderation.
+	_, err := repo.DB.Exec(`
+	INSERT INTO comments (id, user_id, topic_id, content, status, created_at) VALUES
+		(4, 'author', 1, 'fourth', 'approved', '2024-01-01T12:00:00Z'),
+		(1, 'author', 1, 'first', 'approved', '2024-01-01T09:00:00Z'),
+		(3, 'author', 1, 'third', 'approved', '2024-01-01T12:00:00Z'),
+		(2, 'author', 1, 'second', 'approved', '2024-01-01T10:00:00Z'),
+		(5, 'author', 1, 'fifth', 'approved', '2024-01-01T13:00:00Z'),
+		(6, 'author', 1, 'sixth', 'pending', '2024-01-01T14:00:00Z');`)
+	if err != nil {
+		t.Fatalf("failed to seed comments: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		want   []int
+		limit  int
+		offset int
+	}{
+		{name: "first page", limit: 2, offset: 0, want: []int{1, 2}},
+		{name: "page splitting a tie", limit: 2, offset: 2, want: []int{3, 4}},
+		{name: "last partial page", limit: 2, offset: 4, want: []int{5}},
+		{name: "fewer comments than page size", limit: 50, offset: 0, want: []int{1, 2, 3, 4, 5}},
+		{name: "offset past the end", limit: 2, offset: 10, want: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, pageErr := repo.GetCommentsWithVotesPage(ctx, 1, nil, tt.limit, tt.offset)
+			if pageErr != nil {
+				t.Fatalf("GetCommentsWithVotesPage() error = %v", pageErr)
+			}
+
+			ids := make([]int, 0, len(got))
+			for _, c := range got {
+				ids = append(ids, c.ID)
+			}
+			if !slices.Equal(ids, tt.want) {
+				t.Errorf("GetCommentsWithVotesPage(%d, %d) = %v, want %v", tt.limit, tt.offset, ids, tt.want)
+			}
+		})
+	}
+
+	count, err := repo.CountVisibleComments(ctx, 1, nil)
+	if err != nil {
+		t.Fatalf("CountVisibleComments() error = %v", err)
+	}
+	if count != 5 {
+		t.Errorf("CountVisibleComments() = %d, want 5 approved comments", count)
+	}
+
+	author := "author"
+	count, err = repo.CountVisibleComments(ctx, 1, &author)
+	if err != nil {
+		t.Fatalf("CountVisibleComments() error = %v", err)
+	}
+	if count != 6 {
+		t.Errorf("CountVisibleComments(author) = %d, want 6 including their pending comment", count)
+	}
+}
+
+// BenchmarkRepo_GetCommentsWithVotes reads topics of growing size. Counts
+// come from the one statement, so time per comment should stay flat.
+func BenchmarkRepo_GetCommentsWithVotes(b *testing.B) {
+	for _, size := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("comments=%d", size), func(b *testing.B) {
+			repo := newTestRepo(b)
+			ctx := context.Background()
+			for i := range size {
+				c := &comment.Comment{UserID: "author", TopicID: 1, Content: "comment"}
+				err := repo.CreateComment(ctx, c)
+				if err != nil {
+					b.Fatalf("CreateComment() error = %v", err)
+				}
+				if i%2 == 0 {
+					_, err = repo.DB.Exec(`INSERT INTO votes (user_id, comment_id, reaction_type) VALUES ('reader', ?, 1)`, c.ID)
+					if err != nil {
+						b.Fatalf("failed to seed vote: %v", err)
+					}
+				}
+			}
+
+			reader := "reader"
+			b.ResetTimer()
+			for range b.N {
+				_, err := repo.GetCommentsWithVotes(ctx, 1, &reader)
+				if err != nil {
+					b.Fatalf("GetCommentsWithVotes() error = %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestRepo_SetCommentsStatus_SkipsSettled(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	create := func(status string) int {
+		t.Helper()
+		c := &comment.Comment{UserID: "author", TopicID: 1, Content: "hello", Status: status}
+		err := repo.CreateComment(ctx, c)
+		if err != nil {
+			t.Fatalf("CreateComment() error = %v", err)
+		}
+		return c.ID
+	}
+
+	first := create(comment.StatusPending)
+	published := create(comment.StatusApproved)
+	second := create(comment.StatusPending)
+	untouched := create(comment.StatusPending)
+
+	got, err := repo.SetCommentsStatus(ctx, []int{second, published, 9999, first}, comment.StatusApproved, "reader", nil)
+	if err != nil {
+		t.Fatalf("SetCommentsStatus() error = %v", err)
+	}
+	if want := []int{first, second}; !slices.Equal(got, want) {
+		t.Errorf("SetCommentsStatus() = %v, want %v", got, want)
+	}
+
+	for id, want := range map[int]string{
+		first:     comment.StatusApproved,
+		second:    comment.StatusApproved,
+		published: comment.StatusApproved,
+		untouched: comment.StatusPending,
+	} {
+		c, getErr := repo.GetCommentByID(ctx, id)
+		if getErr != nil {
+			t.Fatalf("GetCommentByID(%d) error = %v", id, getErr)
+		}
+		if c.Status != want {
+			t.Errorf("comment %d Status = %q, want %q", id, c.Status, want)
+		}
+	}
+
+	got, err = repo.SetCommentsStatus(ctx, []int{first, second}, comment.StatusRejected, "reader", nil)
+	if err != nil {
+		t.Fatalf("SetCommentsStatus() again error = %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("SetCommentsStatus() on settled comments = %v, want none", got)
+	}
+}
+
+func TestRepo_SetCommentsStatus_Audit(t *testing.T) {
+	ctx := context.Background()
+	entryFor := func(settled *comment.Comment) *audit.Entry {
+		return &audit.Entry{
+			AdminID:    settled.ModeratedBy,
+			Action:     audit.ActionApproveComment,
+			TargetType: audit.TargetComment,
+			TargetID:   strconv.Itoa(settled.ID),
+			Details:    fmt.Sprintf("topic %d by %s", settled.TopicID, settled.UserID),
+		}
+	}
+	createPending := func(t *testing.T, repo *Repo) int {
+		t.Helper()
+		c := &comment.Comment{UserID: "author", TopicID: 1, Content: "hello", Status: comment.StatusPending}
+		err := repo.CreateComment(ctx, c)
+		if err != nil {
+			t.Fatalf("CreateComment() error = %v", err)
+		}
+		return c.ID
+	}
+
+	t.Run("entries are written with the decision", func(t *testing.T) {
+		repo := newTestRepo(t)
+		first, second := createPending(t, repo), createPending(t, repo)
+
+		_, err := repo.SetCommentsStatus(ctx, []int{second, first}, comment.StatusApproved, "reader", entryFor)
+		if err != nil {
+			t.Fatalf("SetCommentsStatus() error = %v", err)
+		}
+
+		rows, err := repo.DB.QueryContext(ctx, "SELECT admin_id, target_id, details FROM audit_log ORDER BY id")
+		if err != nil {
+			t.Fatalf("query audit_log: %v", err)
+		}
+		defer rows.Close()
+
+		var got []string
+		for rows.Next() {
+			var adminID, targetID, details string
+			err = rows.Scan(&adminID, &targetID, &details)
+			if err != nil {
+				t.Fatalf("scan audit_log: %v", err)
+			}
+			got = append(got, adminID+" "+targetID+" "+details)
+		}
+		want := []string{
+			fmt.Sprintf("reader %d topic 1 by author", first),
+			fmt.Sprintf("reader %d topic 1 by author", second),
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("audit_log = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("a failed entry leaves every comment pending", func(t *testing.T) {
+		repo := newTestRepo(t)
+		first, second := createPending(t, repo), createPending(t, repo)
+
+		_, err := repo.DB.ExecContext(ctx, "DROP TABLE audit_log")
+		if err != nil {
+			t.Fatalf("drop audit_log: %v", err)
+		}
+
+		_, err = repo.SetCommentsStatus(ctx, []int{first, second}, comment.StatusApproved, "reader", entryFor)
+		if err == nil {
+			t.Fatal("SetCommentsStatus() error = nil, want the audit failure")
+		}
+
+		for _, id := range []int{first, second} {
+			c, getErr := repo.GetCommentByID(ctx, id)
+			if getErr != nil {
+				t.Fatalf("GetCommentByID(%d) error = %v", id, getErr)
+			}
+			if c.Status != comment.StatusPending {
+				t.Errorf("comment %d Status = %q, want %q", id, c.Status, comment.StatusPending)
+			}
+		}
+	})
+}
+
+func TestRepo_CommentsOutsideModeration(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.DB.Exec(`
+	INSERT INTO topics (id, user_id, title, content) VALUES (2, 'reader', 'Other', 'content');
+	INSERT INTO categories (id, name, description, created_by) VALUES
+		(1, 'Mine', '', 'reader'), (2, 'Theirs', '', 'reader');
+	INSERT INTO topic_categories (topic_id, category_id) VALUES (1, 1), (2, 2);
+	INSERT INTO category_moderators (category_id, user_id) VALUES (1, 'reader');
+	INSERT INTO comments (id, user_id, topic_id, content, status) VALUES
+		(10, 'author', 1, 'in scope', 'pending'),
+		(11, 'author', 2, 'out of scope', 'pending');`)
+	if err != nil {
+		t.Fatalf("failed to seed comments: %v", err)
+	}
+
+	got, err := repo.CommentsOutsideModeration(ctx, "reader", []int{11, 10, 9999})
+	if err != nil {
+		t.Fatalf("CommentsOutsideModeration() error = %v", err)
+	}
+	if want := []int{11}; !slices.Equal(got, want) {
+		t.Errorf("CommentsOutsideModeration(reader) = %v, want %v", got, want)
+	}
+
+	got, err = repo.CommentsOutsideModeration(ctx, "author", []int{10, 11})
+	if err != nil {
+		t.Fatalf("CommentsOutsideModeration() error = %v", err)
+	}
+	if want := []int{10, 11}; !slices.Equal(got, want) {
+		t.Errorf("CommentsOutsideModeration(author) = %v, want %v", got, want)
+	}
+}
+
+func TestRepo_GetPendingComments_Scope(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.DB.Exec(`
+	INSERT INTO topics (id, user_id, title, content) VALUES (2, 'reader', 'Other', 'content');
+	INSERT INTO categories (id, name, description, created_by) VALUES
+		(1, 'Mine', '', 'reader'), (2, 'Theirs', '', 'reader');
+	INSERT INTO topic_categories (topic_id, category_id) VALUES (1, 1), (2, 2);
+	INSERT INTO category_moderators (category_id, user_id) VALUES (1, 'reader');
+	INSERT INTO comments (id, user_id, topic_id, content, status) VALUES
+		(10, 'author', 1, 'in scope', 'pending'),
+		(11, 'author', 2, 'out of scope', 'pending');`)
+	if err != nil {
+		t.Fatalf("failed to seed comments: %v", err)
+	}
+
+	pendingIDs := func(moderatorID string) []int {
+		t.Helper()
+		pending, getErr := repo.GetPendingComments(ctx, moderatorID)
+		if getErr != nil {
+			t.Fatalf("GetPendingComments(%q) error = %v", moderatorID, getErr)
+		}
+		ids := make([]int, 0, len(pending))
+		for _, c := range pending {
+			ids = append(ids, c.ID)
+		}
+		return ids
+	}
+
+	if got, want := pendingIDs("reader"), []int{10}; !slices.Equal(got, want) {
+		t.Errorf("queue for reader = %v, want %v", got, want)
+	}
+	if got := pendingIDs("author"); len(got) != 0 {
+		t.Errorf("queue for a moderator without categories = %v, want none", got)
+	}
+	if got, want := pendingIDs(""), []int{10, 11}; !slices.Equal(got, want) {
+		t.Errorf("unscoped queue = %v, want %v", got, want)
+	}
+}
+
+func TestRepo_GetCommentWithVotes(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.DB.Exec(`
+	INSERT INTO comments (id, user_id, topic_id, content, status) VALUES
+		(1, 'author', 1, 'approved', 'approved'),
+		(2, 'author', 1, 'pending', 'pending');`)
+	if err != nil {
+		t.Fatalf("failed to seed comments: %v", err)
+	}
+	author := "author"
+
+	tests := []struct {
+		wantErr   error
+		userID    *string
+		name      string
+		topicID   int
+		commentID int
+	}{
+		{name: "approved comment", topicID: 1, commentID: 1},
+		{name: "pending comment to its author", topicID: 1, commentID: 2, userID: &author},
+		{name: "pending comment to a visitor", topicID: 1, commentID: 2, wantErr: ErrCommentNotFound},
+		{name: "comment of another topic", topicID: 2, commentID: 1, wantErr: ErrCommentNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, getErr := repo.GetCommentWithVotes(ctx, tt.topicID, tt.commentID, tt.userID)
+			if !errors.Is(getErr, tt.wantErr) {
+				t.Fatalf("GetCommentWithVotes() error = %v, want %v", getErr, tt.wantErr)
+			}
+			if tt.wantErr == nil && got.ID != tt.commentID {
+				t.Errorf("GetCommentWithVotes() ID = %d, want %d", got.ID, tt.commentID)
+			}
+		})
+	}
+}
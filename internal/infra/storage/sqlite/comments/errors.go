@@ -3,8 +3,8 @@ package comments
 import "errors"
 
 var (
-	ErrCommentNotFound = errors.New("comment not found")
-	ErrFailedToUpdate  = errors.New("failed to update comment, user not found or not authorized")
+	ErrCommentNotFound  = errors.New("comment not found")
+	ErrNotCommentAuthor = errors.New("comment belongs to another user")
 )
 
 // ErrTopicNotFound   = errors.New("topic not found")
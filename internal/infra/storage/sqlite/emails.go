@@ -0,0 +1,102 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/arnald/forum/internal/pkg/helpers"
+)
+
+type storedEmail struct {
+	userID string
+	email  string
+}
+
+// normalizeStoredEmails rewrites every stored email into the form
+// helpers.NormalizeEmail gives new ones, so lookups can compare with a plain
+// "=" against the unique index. It runs on every migration, which also picks
+// up a change to ACCOUNT_STRIP_EMAIL_ALIASES.
+//
+// An address that is already normalized stays with its account. Otherwise,
+// when several accounts normalize to the same address, the oldest one takes
+// it and the rest keep their stored email; those are logged so an admin can
+// merge them, and they can still sign in by username.
+func normalizeStoredEmails(ctx context.Context, db *sql.DB, stripAliases bool) (err error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				err = fmt.Errorf("transaction rollback failed: %w (original error: %w)", rollbackErr, err)
+			}
+			return
+		}
+		commitErr := tx.Commit()
+		if commitErr != nil {
+			err = fmt.Errorf("transaction commit failed: %w", commitErr)
+		}
+	}()
+
+	stored, err := loadStoredEmails(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	owners := make(map[string]string, len(stored))
+	for _, s := range stored {
+		if helpers.NormalizeEmail(s.email, stripAliases) == s.email {
+			owners[s.email] = s.userID
+		}
+	}
+
+	for _, s := range stored {
+		normalized := helpers.NormalizeEmail(s.email, stripAliases)
+		if normalized == s.email {
+			continue
+		}
+		if owner, taken := owners[normalized]; taken {
+			log.Printf("Email migration: user %s keeps %q, %q belongs to user %s", s.userID, s.email, normalized, owner)
+			continue
+		}
+
+		_, err = tx.ExecContext(ctx, `UPDATE users SET email = ? WHERE id = ?`, normalized, s.userID)
+		if err != nil {
+			return fmt.Errorf("failed to normalize email of user %s: %w", s.userID, err)
+		}
+		owners[normalized] = s.userID
+	}
+
+	return nil
+}
+
+// loadStoredEmails lists every account's email, oldest account first.
+func loadStoredEmails(ctx context.Context, tx *sql.Tx) ([]storedEmail, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT id, email FROM users ORDER BY created_at ASC, id ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list emails: %w", err)
+	}
+	defer rows.Close()
+
+	stored := make([]storedEmail, 0)
+	for rows.Next() {
+		var s storedEmail
+		err = rows.Scan(&s.userID, &s.email)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan email: %w", err)
+		}
+		stored = append(stored, s)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("error iterating emails: %w", err)
+	}
+
+	return stored, nil
+}
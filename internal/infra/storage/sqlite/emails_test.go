@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNormalizeStoredEmails(t *testing.T) {
+	db := newMigratedDB(t)
+	ctx := context.Background()
+
+	_, err := db.Exec(`
+	INSERT INTO users (id, email, username, created_at) VALUES
+		('john', 'John@Example.com', 'john', '2024-01-01 00:00:00'),
+		('john2', 'JOHN@example.com ', 'john2', '2024-01-02 00:00:00'),
+		('jane', 'Jane@Example.com', 'jane', '2024-01-01 00:00:00'),
+		('jane2', 'jane@example.com', 'jane2', '2024-01-02 00:00:00'),
+		('alias', 'J.Doe+forum@Gmail.com', 'alias', '2024-01-01 00:00:00')`)
+	if err != nil {
+		t.Fatalf("failed to seed users: %v", err)
+	}
+
+	err = normalizeStoredEmails(ctx, db, true)
+	if err != nil {
+		t.Fatalf("normalizeStoredEmails() error = %v", err)
+	}
+
+	want := map[string]string{
+		// The oldest account takes the shared address.
+		"john":  "john@example.com",
+		"john2": "JOHN@example.com ",
+		// An account already holding the normalized address keeps it.
+		"jane":  "Jane@Example.com",
+		"jane2": "jane@example.com",
+		"alias": "jdoe@gmail.com",
+	}
+	for id, wantEmail := range want {
+		var got string
+		err = db.QueryRow(`SELECT email FROM users WHERE id = ?`, id).Scan(&got)
+		if err != nil {
+			t.Fatalf("failed to read email of %s: %v", id, err)
+		}
+		if got != wantEmail {
+			t.Errorf("email of %s = %q, want %q", id, got, wantEmail)
+		}
+	}
+
+	err = normalizeStoredEmails(ctx, db, true)
+	if err != nil {
+		t.Errorf("normalizeStoredEmails() second run error = %v", err)
+	}
+}
@@ -0,0 +1,223 @@
+package imports
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/arnald/forum/internal/domain/dataimport"
+)
+
+type Repo struct {
+	DB *sql.DB
+}
+
+func NewRepo(db *sql.DB) *Repo {
+	return &Repo{
+		DB: db,
+	}
+}
+
+// Import inserts users, categories, topics and comments in that order, each
+// under a fresh id. An insert that fails is recorded against its entity and
+// anything that depends on it is rejected too, so one run reports every
+// problem. If anything failed the whole transaction is rolled back.
+func (r *Repo) Import(ctx context.Context, doc *dataimport.Document, importedBy string, summary *dataimport.Summary) (err error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil || summary.HasFailures() {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				err = fmt.Errorf("transaction rollback failed: %w (original error: %v)", rollbackErr, err)
+			}
+			return
+		}
+		err = tx.Commit()
+		if err != nil {
+			err = fmt.Errorf("transaction commit failed: %w", err)
+			return
+		}
+		summary.Committed = true
+	}()
+
+	users := importUsers(ctx, tx, doc.Users, &summary.Users)
+	categories := importCategories(ctx, tx, doc.Categories, importedBy, &summary.Categories)
+	topics := importTopics(ctx, tx, doc.Topics, users, categories, &summary.Topics)
+	importComments(ctx, tx, doc.Comments, users, topics, &summary.Comments)
+
+	return nil
+}
+
+func importUsers(ctx context.Context, tx *sql.Tx, users []dataimport.User, summary *dataimport.EntitySummary) map[string]string {
+	ids := make(map[string]string, len(users))
+	for _, u := range users {
+		_, err := tx.ExecContext(ctx, `
+		INSERT INTO users (id, username, email, password_hash, created_at, updated_at)
+		VALUES (?, ?, ?, '', COALESCE(NULLIF(?, ''), CURRENT_TIMESTAMP), COALESCE(NULLIF(?, ''), CURRENT_TIMESTAMP))`,
+			u.NewID,
+			u.Username,
+			u.Email,
+			u.CreatedAt,
+			u.CreatedAt,
+		)
+		if err != nil {
+			summary.Fail(u.ID, err.Error())
+			continue
+		}
+		ids[u.ID] = u.NewID
+		summary.Imported++
+	}
+	return ids
+}
+
+func importCategories(ctx context.Context, tx *sql.Tx, categories []dataimport.Category, importedBy string, summary *dataimport.EntitySummary) map[string]int64 {
+	ids := make(map[string]int64, len(categories))
+	for _, c := range categories {
+		result, err := tx.ExecContext(ctx, `
+		INSERT INTO categories (name, description, created_by, created_at)
+		VALUES (?, ?, ?, COALESCE(NULLIF(?, ''), CURRENT_TIMESTAMP))`,
+			c.Name,
+			c.Description,
+			importedBy,
+			c.CreatedAt,
+		)
+		if err != nil {
+			summary.Fail(c.ID, err.Error())
+			continue
+		}
+		id, err := result.LastInsertId()
+		if err != nil {
+			summary.Fail(c.ID, err.Error())
+			continue
+		}
+		ids[c.ID] = id
+		summary.Imported++
+	}
+	return ids
+}
+
+func importTopics(ctx context.Context, tx *sql.Tx, topics []dataimport.Topic, users map[string]string, categories map[string]int64, summary *dataimport.EntitySummary) map[string]int64 {
+	ids := make(map[string]int64, len(topics))
+	for _, t := range topics {
+		userID, ok := users[t.UserID]
+		if !ok {
+			summary.Fail(t.ID, fmt.Sprintf("author %q was not imported", t.UserID))
+			continue
+		}
+
+		categoryIDs := make([]int64, 0, len(t.CategoryIDs))
+		for _, sourceID := range t.CategoryIDs {
+			categoryID, found := categories[sourceID]
+			if !found {
+				break
+			}
+			categoryIDs = append(categoryIDs, categoryID)
+		}
+		if len(categoryIDs) != len(t.CategoryIDs) {
+			summary.Fail(t.ID, "one of its categories was not imported")
+			continue
+		}
+
+		var canonical any
+		if len(categoryIDs) > 0 {
+			canonical = categoryIDs[0]
+		}
+
+		result, err := tx.ExecContext(ctx, `
+		INSERT INTO topics (user_id, title, content, canonical_category_id, created_at, updated_at, bumped_at)
+		VALUES (?, ?, ?, ?, COALESCE(NULLIF(?, ''), CURRENT_TIMESTAMP), COALESCE(NULLIF(?, ''), CURRENT_TIMESTAMP), COALESCE(NULLIF(?, ''), CURRENT_TIMESTAMP))`,
+			userID,
+			t.Title,
+			t.Content,
+			canonical,
+			t.CreatedAt,
+			t.CreatedAt,
+			t.CreatedAt,
+		)
+		if err != nil {
+			summary.Fail(t.ID, err.Error())
+			continue
+		}
+		id, err := result.LastInsertId()
+		if err != nil {
+			summary.Fail(t.ID, err.Error())
+			continue
+		}
+
+		err = linkCategories(ctx, tx, id, categoryIDs)
+		if err != nil {
+			summary.Fail(t.ID, err.Error())
+			continue
+		}
+
+		ids[t.ID] = id
+		summary.Imported++
+	}
+	return ids
+}
+
+func linkCategories(ctx context.Context, tx *sql.Tx, topicID int64, categoryIDs []int64) error {
+	for _, categoryID := range categoryIDs {
+		_, err := tx.ExecContext(ctx, `
+		INSERT OR IGNORE INTO topic_categories (topic_id, category_id)
+		VALUES (?, ?)`,
+			topicID,
+			categoryID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to link category: %w", err)
+		}
+	}
+	return nil
+}
+
+func importComments(ctx context.Context, tx *sql.Tx, comments []dataimport.Comment, users map[string]string, topics map[string]int64, summary *dataimport.EntitySummary) {
+	ids := make(map[string]int64, len(comments))
+	for _, c := range comments {
+		userID, ok := users[c.UserID]
+		if !ok {
+			summary.Fail(c.ID, fmt.Sprintf("author %q was not imported", c.UserID))
+			continue
+		}
+		topicID, ok := topics[c.TopicID]
+		if !ok {
+			summary.Fail(c.ID, fmt.Sprintf("topic %q was not imported", c.TopicID))
+			continue
+		}
+
+		var parentID any
+		if c.ParentID != "" {
+			id, found := ids[c.ParentID]
+			if !found {
+				summary.Fail(c.ID, fmt.Sprintf("parent comment %q was not imported", c.ParentID))
+				continue
+			}
+			parentID = id
+		}
+
+		result, err := tx.ExecContext(ctx, `
+		INSERT INTO comments (user_id, topic_id, parent_id, content, created_at, updated_at)
+		VALUES (?, ?, ?, ?, COALESCE(NULLIF(?, ''), CURRENT_TIMESTAMP), COALESCE(NULLIF(?, ''), CURRENT_TIMESTAMP))`,
+			userID,
+			topicID,
+			parentID,
+			c.Content,
+			c.CreatedAt,
+			c.CreatedAt,
+		)
+		if err != nil {
+			summary.Fail(c.ID, err.Error())
+			continue
+		}
+		id, err := result.LastInsertId()
+		if err != nil {
+			summary.Fail(c.ID, err.Error())
+			continue
+		}
+		ids[c.ID] = id
+		summary.Imported++
+	}
+}
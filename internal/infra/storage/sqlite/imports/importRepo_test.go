@@ -0,0 +1,139 @@
+package imports
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/arnald/forum/internal/domain/dataimport"
+	"github.com/arnald/forum/internal/pkg/path"
+)
+
+// newTestRepo returns a repository backed by a private in-memory database
+// with the project schema applied and an "admin" user to import as.
+func newTestRepo(t *testing.T) *Repo {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	// Every connection to :memory: gets its own database, so keep just one.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	schema, err := os.ReadFile(path.NewResolver().GetPath("db/migrations/schema.sql"))
+	if err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+	_, err = db.Exec(string(schema))
+	if err != nil {
+		t.Fatalf("failed to apply schema: %v", err)
+	}
+
+	_, err = db.Exec(`INSERT INTO users (id, username, email, password_hash, role)
+		VALUES ('admin', 'admin', 'admin@example.com', 'x', 'admin')`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+
+	return NewRepo(db)
+}
+
+func testDocument() *dataimport.Document {
+	return &dataimport.Document{
+		Users: []dataimport.User{
+			{ID: "u1", NewID: "new-u1", Username: "alice", Email: "alice@example.com", CreatedAt: "2019-03-01 10:00:00"},
+		},
+		Categories: []dataimport.Category{
+			{ID: "c1", Name: "Imported"},
+		},
+		Topics: []dataimport.Topic{
+			{ID: "t1", UserID: "u1", Title: "Old topic", Content: "From the old forum", CategoryIDs: []string{"c1"}, CreatedAt: "2019-03-02 10:00:00"},
+		},
+		Comments: []dataimport.Comment{
+			{ID: "m1", TopicID: "t1", UserID: "u1", Content: "First"},
+			{ID: "m2", TopicID: "t1", UserID: "u1", ParentID: "m1", Content: "Reply"},
+		},
+	}
+}
+
+func countRows(t *testing.T, db *sql.DB, table string) int {
+	t.Helper()
+
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&count)
+	if err != nil {
+		t.Fatalf("failed to count %s: %v", table, err)
+	}
+	return count
+}
+
+func TestRepo_Import(t *testing.T) {
+	t.Run("group: content import", func(t *testing.T) {
+		t.Run("a clean document is committed with remapped ids", func(t *testing.T) {
+			repo := newTestRepo(t)
+			summary := &dataimport.Summary{}
+
+			err := repo.Import(context.Background(), testDocument(), "admin", summary)
+			if err != nil {
+				t.Fatalf("Import() error = %v", err)
+			}
+			if !summary.Committed || summary.Comments.Imported != 2 {
+				t.Fatalf("Import() summary = %+v, want committed with 2 comments", summary)
+			}
+
+			var author, createdAt string
+			err = repo.DB.QueryRow(`SELECT user_id, created_at FROM topics WHERE title = 'Old topic'`).Scan(&author, &createdAt)
+			if err != nil {
+				t.Fatalf("failed to read imported topic: %v", err)
+			}
+			if author != "new-u1" {
+				t.Errorf("topic author = %q, want %q", author, "new-u1")
+			}
+			if createdAt != "2019-03-02T10:00:00Z" {
+				t.Errorf("topic created_at = %q, want the source date", createdAt)
+			}
+
+			var orphans int
+			err = repo.DB.QueryRow(`SELECT COUNT(*) FROM comments c
+				WHERE c.parent_id IS NOT NULL
+				AND NOT EXISTS (SELECT 1 FROM comments p WHERE p.id = c.parent_id)`).Scan(&orphans)
+			if err != nil {
+				t.Fatalf("failed to check reply parents: %v", err)
+			}
+			if orphans != 0 {
+				t.Errorf("found %d replies with a missing parent", orphans)
+			}
+		})
+
+		t.Run("one failure rolls everything back", func(t *testing.T) {
+			repo := newTestRepo(t)
+			doc := testDocument()
+			// Clashes with the existing admin account.
+			doc.Users = append(doc.Users, dataimport.User{ID: "u2", NewID: "new-u2", Username: "admin", Email: "other@example.com"})
+			summary := &dataimport.Summary{}
+
+			err := repo.Import(context.Background(), doc, "admin", summary)
+			if err != nil {
+				t.Fatalf("Import() error = %v", err)
+			}
+			if summary.Committed {
+				t.Fatal("Import() committed a document with a failing user")
+			}
+			if summary.Users.Failed != 1 || summary.Users.Failures[0].SourceID != "u2" {
+				t.Errorf("Import() user failures = %+v, want u2", summary.Users.Failures)
+			}
+
+			for table, want := range map[string]int{"users": 1, "categories": 0, "topics": 0, "comments": 0} {
+				got := countRows(t, repo.DB, table)
+				if got != want {
+					t.Errorf("%s has %d rows after rollback, want %d", table, got, want)
+				}
+			}
+		})
+	})
+}
@@ -0,0 +1,130 @@
+package sqlite
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/arnald/forum/internal/infra/storage/sqlite/comments"
+)
+
+func newMigratedDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	// Every connection to :memory: gets its own database, so keep just one.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	err = migrateDB(db)
+	if err != nil {
+		t.Fatalf("migrateDB() error = %v", err)
+	}
+	return db
+}
+
+// queryPlan returns the EXPLAIN QUERY PLAN details for query, one step per
+// line.
+func queryPlan(t *testing.T, db *sql.DB, query string, args ...any) string {
+	t.Helper()
+
+	rows, err := db.Query("EXPLAIN QUERY PLAN "+query, args...)
+	if err != nil {
+		t.Fatalf("EXPLAIN QUERY PLAN failed: %v", err)
+	}
+	defer rows.Close()
+
+	var steps []string
+	for rows.Next() {
+		var id, parent, unused int
+		var detail string
+		err = rows.Scan(&id, &parent, &unused, &detail)
+		if err != nil {
+			t.Fatalf("failed to scan plan: %v", err)
+		}
+		steps = append(steps, detail)
+	}
+	if err = rows.Err(); err != nil {
+		t.Fatalf("plan rows error: %v", err)
+	}
+	return strings.Join(steps, "\n")
+}
+
+func TestHotQueriesUseIndexes(t *testing.T) {
+	db := newMigratedDB(t)
+
+	t.Run("group: query plans", func(t *testing.T) {
+		testCases := newQueryPlanTestCases()
+		for _, tt := range testCases {
+			t.Run(tt.name, runQueryPlanTest(db, tt))
+		}
+	})
+}
+
+type queryPlanTestCase struct {
+	name      string
+	query     string
+	wantIndex string
+	args      []any
+	// sorted is set when the index must also supply the ORDER BY.
+	sorted bool
+}
+
+// The queries other than the pending queue mirror the repositories' own;
+// keep them in step when those change.
+func newQueryPlanTestCases() []queryPlanTestCase {
+	return []queryPlanTestCase{
+		{
+			name:      "pending comment queue",
+			query:     comments.PendingCommentsQuery,
+			args:      []any{"", ""},
+			wantIndex: "idx_comments_status_created",
+			sorted:    true,
+		},
+		{
+			name:      "pending comment queue for one moderator",
+			query:     comments.PendingCommentsQuery,
+			args:      []any{"mod-id", "mod-id"},
+			wantIndex: "idx_comments_status_created",
+			sorted:    true,
+		},
+		{
+			name:      "unread notification count",
+			query:     `SELECT COUNT(*) FROM notifications WHERE user_id = ? AND is_read = 0`,
+			args:      []any{"user-id"},
+			wantIndex: "idx_notifications_user_unread",
+		},
+		{
+			name:      "approved comments per topic",
+			query:     `SELECT COUNT(*) FROM comments cm WHERE cm.topic_id = ? AND cm.status = 'approved'`,
+			args:      []any{1},
+			wantIndex: "idx_comments_topic_status",
+		},
+		{
+			name: "comment vote totals",
+			query: `
+			SELECT
+				COUNT(CASE WHEN reaction_type = 1 THEN 1 END) as upvotes,
+				COUNT(CASE WHEN reaction_type = -1 THEN 1 END) as downvotes
+			FROM votes
+			WHERE comment_id = ? AND topic_id IS NULL`,
+			args:      []any{1},
+			wantIndex: "idx_votes_comment_reaction",
+		},
+	}
+}
+
+func runQueryPlanTest(db *sql.DB, tt queryPlanTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		plan := queryPlan(t, db, tt.query, tt.args...)
+		if !strings.Contains(plan, tt.wantIndex) {
+			t.Errorf("plan does not use %s:\n%s", tt.wantIndex, plan)
+		}
+		if tt.sorted && strings.Contains(plan, "TEMP B-TREE") {
+			t.Errorf("plan sorts in a temporary b-tree:\n%s", plan)
+		}
+	}
+}
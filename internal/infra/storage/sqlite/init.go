@@ -38,6 +38,11 @@ func InitializeDB(cfg config.ServerConfig) (*sql.DB, error) {
 		if err != nil {
 			return nil, fmt.Errorf("migration failed: %w", err)
 		}
+
+		err = normalizeStoredEmails(context.TODO(), db, cfg.Accounts.StripEmailAliases)
+		if err != nil {
+			return nil, fmt.Errorf("email migration failed: %w", err)
+		}
 	}
 
 	if cfg.Database.SeedOnStart && cfg.Database.MigrateOnStart {
@@ -47,38 +52,174 @@ func InitializeDB(cfg config.ServerConfig) (*sql.DB, error) {
 		}
 	}
 
+	if cfg.Database.Driver == "sqlite3" {
+		err = checkpointWAL(context.TODO(), db)
+		if err != nil {
+			log.Printf("Checkpoint warning: %v", err)
+		}
+	}
+
 	return db, nil
 }
 
 func OpenDB(cfg config.ServerConfig) (*sql.DB, *sql.DB, error) {
-	db, err := sql.Open(cfg.Database.Driver, cfg.Database.Path+"?"+cfg.Database.Pragma)
+	if cfg.Database.Driver != "sqlite3" {
+		db, err := sql.Open(cfg.Database.Driver, cfg.Database.Path+"?"+cfg.Database.Pragma)
+		if err != nil {
+			return nil, nil, err
+		}
+		return db, nil, nil
+	}
+
+	db, err := sql.Open(cfg.Database.Driver, sqliteDSN(cfg.Database.Path, cfg.Database.Pragma))
 	if err != nil {
 		return nil, nil, err
 	}
+	// Zero would mean no limit; SQLite has a single writer either way.
+	db.SetMaxOpenConns(max(cfg.Database.OpenConn, 1))
 
-	if cfg.Database.Driver == "sqlite3" {
-		db.SetMaxOpenConns(cfg.Database.OpenConn)
+	err = checkForeignKeys(context.TODO(), db)
+	if err != nil {
+		_ = db.Close()
+		return nil, nil, err
 	}
 	return db, nil, nil
 }
 
+// columnMigration describes a column added to a table after it was first
+// created. CREATE TABLE IF NOT EXISTS leaves existing tables untouched, so
+// every column added to schema.sql later on must also be listed here.
+// backfill, when set, runs once right after the column is added.
+type columnMigration struct {
+	table      string
+	column     string
+	definition string
+	backfill   string
+}
+
+var columnMigrations = []columnMigration{
+	{table: "comments", column: "parent_id", definition: "INTEGER REFERENCES comments(id) ON DELETE CASCADE"},
+	{table: "categories", column: "requires_image", definition: "BOOLEAN NOT NULL DEFAULT 0"},
+	{table: "topics", column: "bumped_at", definition: "DATETIME"},
+	{table: "sessions", column: "authenticated_at", definition: "DATETIME"},
+	{table: "categories", column: "archived", definition: "BOOLEAN NOT NULL DEFAULT 0"},
+	{table: "users", column: "role", definition: "TEXT NOT NULL DEFAULT 'user'"},
+	{table: "comments", column: "status", definition: "TEXT NOT NULL DEFAULT 'approved'"},
+	{table: "comments", column: "moderated_by", definition: "TEXT REFERENCES users(id) ON DELETE SET NULL"},
+	{
+		table:      "topics",
+		column:     "canonical_category_id",
+		definition: "INTEGER REFERENCES categories(id) ON DELETE SET NULL",
+		backfill: `UPDATE topics SET canonical_category_id = (
+			SELECT MIN(category_id) FROM topic_categories WHERE topic_id = topics.id
+		)`,
+	},
+	{table: "topics", column: "is_question", definition: "BOOLEAN NOT NULL DEFAULT 0"},
+	{table: "topics", column: "accepted_comment_id", definition: "INTEGER REFERENCES comments(id) ON DELETE SET NULL"},
+	{table: "thread_watches", column: "last_notified_at", definition: "DATETIME"},
+	{table: "notifications", column: "count", definition: "INTEGER NOT NULL DEFAULT 1"},
+	{table: "topics", column: "summary", definition: "TEXT NOT NULL DEFAULT ''"},
+	{table: "comments", column: "report_count", definition: "INTEGER NOT NULL DEFAULT 0"},
+	{table: "comments", column: "collapsed", definition: "BOOLEAN NOT NULL DEFAULT 0"},
+	{
+		table:      "users",
+		column:     "email_verified",
+		definition: "BOOLEAN NOT NULL DEFAULT 0",
+		// Accounts from before verification existed keep posting.
+		backfill: `UPDATE users SET email_verified = 1`,
+	},
+	{table: "topics", column: "deleted_at", definition: "DATETIME"},
+	{table: "topics", column: "view_count", definition: "INTEGER NOT NULL DEFAULT 0"},
+	{
+		table:      "topics",
+		column:     "upvote_count",
+		definition: "INTEGER NOT NULL DEFAULT 0",
+		backfill: `UPDATE topics SET upvote_count = (
+			SELECT COUNT(*) FROM votes
+			WHERE votes.topic_id = topics.id AND votes.comment_id IS NULL AND votes.reaction_type = 1
+		)`,
+	},
+	{
+		table:      "topics",
+		column:     "downvote_count",
+		definition: "INTEGER NOT NULL DEFAULT 0",
+		backfill: `UPDATE topics SET downvote_count = (
+			SELECT COUNT(*) FROM votes
+			WHERE votes.topic_id = topics.id AND votes.comment_id IS NULL AND votes.reaction_type = -1
+		)`,
+	},
+	// Topics from before slugs existed keep an empty one until their next
+	// edit; their links fall back to the bare id.
+	{table: "topics", column: "slug", definition: "TEXT NOT NULL DEFAULT ''"},
+	{table: "topics", column: "status", definition: "TEXT NOT NULL DEFAULT 'published'"},
+	{table: "comments", column: "rejection_reason", definition: "TEXT NOT NULL DEFAULT ''"},
+}
+
 func migrateDB(db *sql.DB) error {
 	resolver := path.NewResolver()
-	migrationFiles := []string{
-		resolver.GetPath("db/migrations/schema.sql"),
-		resolver.GetPath("db/migrations/indexes.sql"),
+
+	err := execSQLFile(db, resolver.GetPath("db/migrations/schema.sql"))
+	if err != nil {
+		return err
 	}
 
-	for _, file := range migrationFiles {
-		err := execSQLFile(db, file)
+	err = addMissingColumns(db)
+	if err != nil {
+		return err
+	}
+
+	return execSQLFile(db, resolver.GetPath("db/migrations/indexes.sql"))
+}
+
+func addMissingColumns(db *sql.DB) error {
+	ctx := context.TODO()
+
+	for _, migration := range columnMigrations {
+		exists, err := columnExists(ctx, db, migration.table, migration.column)
 		if err != nil {
 			return err
 		}
+		if exists {
+			continue
+		}
+
+		_, err = db.ExecContext(ctx, fmt.Sprintf(
+			"ALTER TABLE %s ADD COLUMN %s %s",
+			migration.table,
+			migration.column,
+			migration.definition,
+		))
+		if err != nil {
+			return fmt.Errorf("failed to add column %s.%s: %w", migration.table, migration.column, err)
+		}
+
+		if migration.backfill == "" {
+			continue
+		}
+		_, err = db.ExecContext(ctx, migration.backfill)
+		if err != nil {
+			return fmt.Errorf("failed to backfill column %s.%s: %w", migration.table, migration.column, err)
+		}
 	}
 
 	return nil
 }
 
+func columnExists(ctx context.Context, db *sql.DB, table, column string) (bool, error) {
+	var count int
+	err := db.QueryRowContext(
+		ctx,
+		"SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?",
+		table,
+		column,
+	).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+
+	return count > 0, nil
+}
+
 func execSQLFile(db *sql.DB, path string) error {
 	ctx := context.TODO()
 
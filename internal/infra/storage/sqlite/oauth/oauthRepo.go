@@ -5,9 +5,11 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/arnald/forum/internal/domain/oauth"
 	"github.com/arnald/forum/internal/domain/user"
+	"github.com/arnald/forum/internal/pkg/validator"
 )
 
 type Repo struct {
@@ -50,6 +52,44 @@ func (r *Repo) GetUserByProviderID(ctx context.Context, provider oauth.Provider,
 	return &u, nil
 }
 
+// GetUserByEmail finds the local account registered with email, so a first
+// provider login can be linked to it instead of failing on the duplicate.
+// EmailVerified is only set when the owner followed a verification link:
+// accounts that predate verification were marked verified by the column
+// migration without ever proving they own the address.
+func (r *Repo) GetUserByEmail(ctx context.Context, email string) (*user.User, error) {
+	query := `
+	SELECT id, username, email, COALESCE(password_hash, ''), created_at,
+		EXISTS (
+			SELECT 1 FROM email_verification_tokens evt
+			WHERE evt.user_id = users.id AND evt.used_at IS NOT NULL
+		)
+	FROM users
+	WHERE email = ?
+	`
+
+	var u user.User
+	err := r.db.QueryRowContext(ctx, query, email).Scan(
+		&u.ID,
+		&u.Username,
+		&u.Email,
+		&u.Password,
+		&u.CreatedAt,
+		&u.EmailVerified,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// CreateOAuthUser creates a local account for oauthUser. The provider's name
+// is untrusted, so the username is sanitized to the local username rules and
+// given a numeric suffix if another account already has it; oauthUser's
+// Username is updated to the name actually stored.
 func (r *Repo) CreateOAuthUser(ctx context.Context, oauthUser *oauth.User) (userResult *user.User, err error) {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -69,9 +109,14 @@ func (r *Repo) CreateOAuthUser(ctx context.Context, oauthUser *oauth.User) (user
 		}
 	}()
 
+	oauthUser.Username, err = availableUsername(ctx, tx, validator.SanitizeUsername(oauthUser.Username))
+	if err != nil {
+		return nil, err
+	}
+
 	insertUserQuery := `
-        INSERT INTO users (id, username, email, password_hash)
-        VALUES (?, ?, ?, '')
+        INSERT INTO users (id, username, email, password_hash, email_verified)
+        VALUES (?, ?, ?, '', 1)
     `
 
 	_, err = tx.ExecContext(ctx, insertUserQuery,
@@ -107,14 +152,35 @@ func (r *Repo) CreateOAuthUser(ctx context.Context, oauthUser *oauth.User) (user
 	}, nil
 }
 
+// availableUsername returns base, or base with the lowest numeric suffix from
+// 2 up that no account uses, shortened as needed to stay within
+// validator.MaxUsernameLength.
+func availableUsername(ctx context.Context, tx *sql.Tx, base string) (string, error) {
+	candidate := base
+	for n := 2; ; n++ {
+		var taken bool
+		err := tx.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM users WHERE username = ?)`, candidate).Scan(&taken)
+		if err != nil {
+			return "", fmt.Errorf("failed to check username: %w", err)
+		}
+		if !taken {
+			return candidate, nil
+		}
+
+		suffix := strconv.Itoa(n)
+		candidate = base[:min(len(base), validator.MaxUsernameLength-len(suffix))] + suffix
+	}
+}
+
 func (r *Repo) LinkOAuthProvider(ctx context.Context, userID string, oauthUser *oauth.User) error {
 	query := `
-	INSERT INTO oath_providers (user_id, provider, provider_user_id, email, username, avatar_url, created_at, updated_at)
-	VALUES (?, ?, ?, ?, ?, ?, ?, ?,)
+	INSERT INTO oauth_providers (user_id, provider, provider_user_id, email, username, avatar_url)
+	VALUES (?, ?, ?, ?, ?, ?)
 	ON CONFLICT(provider, provider_user_id) DO UPDATE SET
-		email = excluded.username,
+		email = excluded.email,
+		username = excluded.username,
 		avatar_url = excluded.avatar_url,
-		updated_at = excluded.updated_at
+		updated_at = CURRENT_TIMESTAMP
 	`
 
 	stmt, err := r.db.PrepareContext(ctx, query)
@@ -126,7 +192,7 @@ func (r *Repo) LinkOAuthProvider(ctx context.Context, userID string, oauthUser *
 	_, err = stmt.ExecContext(ctx,
 		userID,
 		string(oauthUser.Provider),
-		oauthUser.Provider,
+		oauthUser.ProviderID,
 		oauthUser.Email,
 		oauthUser.Username,
 		oauthUser.AvatarURL,
@@ -0,0 +1,151 @@
+package oauthrepo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/arnald/forum/internal/domain/oauth"
+	"github.com/arnald/forum/internal/pkg/path"
+	"github.com/arnald/forum/internal/pkg/validator"
+)
+
+// newTestRepo returns a repository backed by a private in-memory database
+// with the project schema applied.
+func newTestRepo(t *testing.T) *Repo {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	// Every connection to :memory: gets its own database, so keep just one.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	schema, err := os.ReadFile(path.NewResolver().GetPath("db/migrations/schema.sql"))
+	if err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+	_, err = db.Exec(string(schema))
+	if err != nil {
+		t.Fatalf("failed to apply schema: %v", err)
+	}
+
+	return NewOAuthRepository(db)
+}
+
+func TestRepo_CreateOAuthUser_Username(t *testing.T) {
+	ctx := context.Background()
+	longName := strings.Repeat("Long", 20)
+
+	testCases := []struct {
+		name     string
+		existing []string
+		provided string
+		want     string
+	}{
+		{
+			name:     "markup in the display name is stripped",
+			provided: "<img src=x onerror=alert(1)>Mallory <b>Evil</b>",
+			want:     "Mallory_Evil",
+		},
+		{
+			name:     "over-long display name is cut to the limit",
+			provided: longName,
+			want:     longName[:validator.MaxUsernameLength],
+		},
+		{
+			name:     "a taken name gets a numeric suffix",
+			existing: []string{"Jane_Doe", "Jane_Doe2"},
+			provided: "Jane Doe",
+			want:     "Jane_Doe3",
+		},
+		{
+			name:     "the suffix still fits a name at the limit",
+			existing: []string{longName[:validator.MaxUsernameLength]},
+			provided: longName,
+			want:     longName[:validator.MaxUsernameLength-1] + "2",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestRepo(t)
+			for i, name := range tt.existing {
+				_, err := repo.db.Exec(`INSERT INTO users (id, email, username) VALUES (?, ?, ?)`,
+					"existing-"+name, fmt.Sprintf("existing%d@example.com", i), name)
+				if err != nil {
+					t.Fatalf("failed to seed user %q: %v", name, err)
+				}
+			}
+
+			created, err := repo.CreateOAuthUser(ctx, &oauth.User{
+				UserID:     "new-user",
+				ProviderID: "google-1",
+				Provider:   "google",
+				Email:      "new@example.com",
+				Username:   tt.provided,
+			})
+			if err != nil {
+				t.Fatalf("CreateOAuthUser() error = %v", err)
+			}
+			if created.Username != tt.want {
+				t.Errorf("CreateOAuthUser() username = %q, want %q", created.Username, tt.want)
+			}
+
+			var stored string
+			err = repo.db.QueryRow(`SELECT username FROM users WHERE id = 'new-user'`).Scan(&stored)
+			if err != nil {
+				t.Fatalf("failed to read username: %v", err)
+			}
+			if stored != tt.want {
+				t.Errorf("stored username = %q, want %q", stored, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepo_GetUserByEmail_Verified(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+
+	// Both accounts carry email_verified = 1, as the column migration left
+	// every pre-existing account, but only one followed a verification link.
+	_, err := repo.db.Exec(`
+		INSERT INTO users (id, email, username, email_verified) VALUES
+			('legacy', 'legacy@example.com', 'legacy', 1),
+			('proven', 'proven@example.com', 'proven', 1);
+		INSERT INTO email_verification_tokens (token_hash, user_id, expires_at, used_at) VALUES
+			('unused', 'legacy', '2099-01-01 00:00:00', NULL),
+			('used', 'proven', '2099-01-01 00:00:00', '2024-01-01 00:00:00');
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed users: %v", err)
+	}
+
+	testCases := []struct {
+		email string
+		want  bool
+	}{
+		{email: "legacy@example.com", want: false},
+		{email: "proven@example.com", want: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.email, func(t *testing.T) {
+			found, err := repo.GetUserByEmail(ctx, tt.email)
+			if err != nil {
+				t.Fatalf("GetUserByEmail() error = %v", err)
+			}
+			if found.EmailVerified != tt.want {
+				t.Errorf("GetUserByEmail() EmailVerified = %v, want %v", found.EmailVerified, tt.want)
+			}
+		})
+	}
+}
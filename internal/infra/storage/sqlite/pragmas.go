@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// defaultBusyTimeout is how long, in milliseconds, a connection waits on a
+// locked database before giving up, unless DB_PRAGMA says otherwise.
+const defaultBusyTimeout = "5000"
+
+var ErrForeignKeysOff = errors.New("sqlite foreign keys are not enforced")
+
+// sqliteDSN adds the connection settings the schema depends on to the
+// configured pragma string. Foreign keys are switched on for each new
+// connection by the driver, so they have to be part of the DSN rather than a
+// PRAGMA run once after opening; without them every ON DELETE CASCADE in the
+// schema silently does nothing. They stay on whatever DB_PRAGMA says.
+func sqliteDSN(path, pragma string) string {
+	params := make([]string, 0, 3)
+	for _, param := range strings.Split(pragma, "&") {
+		key, _, _ := strings.Cut(param, "=")
+		if param == "" || key == "_foreign_keys" || key == "_fk" {
+			continue
+		}
+		params = append(params, param)
+	}
+	params = append(params, "_foreign_keys=on")
+
+	query, err := url.ParseQuery(pragma)
+	if err != nil || (query.Get("_busy_timeout") == "" && query.Get("_timeout") == "") {
+		params = append(params, "_busy_timeout="+defaultBusyTimeout)
+	}
+
+	return path + "?" + strings.Join(params, "&")
+}
+
+// checkForeignKeys fails unless the connection actually enforces foreign
+// keys, which a driver built without them would quietly ignore.
+func checkForeignKeys(ctx context.Context, db *sql.DB) error {
+	var enabled bool
+	err := db.QueryRowContext(ctx, "PRAGMA foreign_keys").Scan(&enabled)
+	if err != nil {
+		return fmt.Errorf("failed to read foreign_keys pragma: %w", err)
+	}
+	if !enabled {
+		return ErrForeignKeysOff
+	}
+	return nil
+}
+
+// checkpointWAL folds the write-ahead log back into the database file and
+// truncates it, so a log left over from the previous run does not keep
+// growing. It does nothing outside WAL mode.
+func checkpointWAL(ctx context.Context, db *sql.DB) error {
+	var mode string
+	err := db.QueryRowContext(ctx, "PRAGMA journal_mode").Scan(&mode)
+	if err != nil {
+		return fmt.Errorf("failed to read journal_mode pragma: %w", err)
+	}
+	if !strings.EqualFold(mode, "wal") {
+		return nil
+	}
+
+	_, err = db.ExecContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)")
+	if err != nil {
+		return fmt.Errorf("wal checkpoint failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/arnald/forum/internal/config"
+)
+
+func TestSqliteDSN(t *testing.T) {
+	t.Run("group: dsn", func(t *testing.T) {
+		testCases := newSqliteDSNTestCases()
+		for _, tt := range testCases {
+			t.Run(tt.name, runSqliteDSNTest(tt))
+		}
+	})
+}
+
+type sqliteDSNTestCase struct {
+	name   string
+	pragma string
+	want   string
+}
+
+func newSqliteDSNTestCases() []sqliteDSNTestCase {
+	return []sqliteDSNTestCase{
+		{name: "empty pragma", pragma: "", want: "forum.db?_foreign_keys=on&_busy_timeout=5000"},
+		{name: "default pragma", pragma: "_foreign_keys=on&_journal_mode=WAL", want: "forum.db?_journal_mode=WAL&_foreign_keys=on&_busy_timeout=5000"},
+		{name: "foreign keys cannot be turned off", pragma: "_fk=off&_journal_mode=WAL", want: "forum.db?_journal_mode=WAL&_foreign_keys=on&_busy_timeout=5000"},
+		{name: "configured busy timeout is kept", pragma: "_busy_timeout=100", want: "forum.db?_busy_timeout=100&_foreign_keys=on"},
+	}
+}
+
+func runSqliteDSNTest(tt sqliteDSNTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		if got := sqliteDSN("forum.db", tt.pragma); got != tt.want {
+			t.Errorf("sqliteDSN(%q) = %q, want %q", tt.pragma, got, tt.want)
+		}
+	}
+}
+
+func TestOpenDB_CascadesDeletes(t *testing.T) {
+	ctx := context.Background()
+
+	// The pragma leaves foreign keys out; OpenDB must switch them on anyway.
+	cfg := config.ServerConfig{
+		Database: config.DatabaseConfig{
+			Driver:   "sqlite3",
+			Path:     filepath.Join(t.TempDir(), "forum.db"),
+			Pragma:   "_journal_mode=WAL",
+			OpenConn: 2,
+		},
+	}
+
+	db, _, err := OpenDB(cfg)
+	if err != nil {
+		t.Fatalf("OpenDB() error = %v", err)
+	}
+	defer db.Close()
+
+	err = migrateDB(db)
+	if err != nil {
+		t.Fatalf("migrateDB() error = %v", err)
+	}
+
+	_, err = db.ExecContext(ctx, `
+	INSERT INTO users (id, email, username) VALUES ('alice', 'alice@example.com', 'alice');
+	INSERT INTO topics (id, user_id, title, content) VALUES (1, 'alice', 'A topic', 'Some content');
+	INSERT INTO comments (user_id, topic_id, content) VALUES ('alice', 1, 'First'), ('alice', 1, 'Second');`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+
+	_, err = db.ExecContext(ctx, `DELETE FROM topics WHERE id = 1`)
+	if err != nil {
+		t.Fatalf("failed to delete topic: %v", err)
+	}
+
+	var remaining int
+	err = db.QueryRowContext(ctx, `SELECT COUNT(*) FROM comments WHERE topic_id = 1`).Scan(&remaining)
+	if err != nil {
+		t.Fatalf("failed to count comments: %v", err)
+	}
+	if remaining != 0 {
+		t.Errorf("comments left after deleting their topic = %d, want 0", remaining)
+	}
+
+	err = checkpointWAL(ctx, db)
+	if err != nil {
+		t.Errorf("checkpointWAL() error = %v", err)
+	}
+}
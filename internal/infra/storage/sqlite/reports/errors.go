@@ -0,0 +1,13 @@
+package reports
+
+import "errors"
+
+var (
+	ErrReasonAlreadyExists = errors.New("report reason already exists")
+	ErrReasonNotFound      = errors.New("report reason not found")
+	ErrCommentNotFound     = errors.New("comment not found")
+	ErrTopicNotFound       = errors.New("topic not found")
+	ErrDuplicateReport     = errors.New("content already has a pending report from this user")
+	ErrSelfReport          = errors.New("users cannot report their own content")
+	ErrNoPendingReports    = errors.New("no pending reports")
+)
@@ -0,0 +1,141 @@
+package reports
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/arnald/forum/internal/domain/report"
+)
+
+type Repo struct {
+	DB *sql.DB
+}
+
+func NewRepo(db *sql.DB) *Repo {
+	return &Repo{
+		DB: db,
+	}
+}
+
+// GetReasons lists the report reasons in the order they were added. Retired
+// reasons are only included when includeRetired is set.
+func (r *Repo) GetReasons(ctx context.Context, includeRetired bool) ([]report.Reason, error) {
+	query := `
+	SELECT id, label, active, created_at
+	FROM report_reasons`
+	if !includeRetired {
+		query += " WHERE active = 1"
+	}
+	query += " ORDER BY id ASC"
+
+	stmt, err := r.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("prepare failed: %w", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	reasons := make([]report.Reason, 0)
+	for rows.Next() {
+		var reason report.Reason
+		err = rows.Scan(
+			&reason.ID,
+			&reason.Label,
+			&reason.Active,
+			&reason.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan report reasons failed: %w", err)
+		}
+		reasons = append(reasons, reason)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return reasons, nil
+}
+
+func (r *Repo) CreateReason(ctx context.Context, label string) (*report.Reason, error) {
+	query := `
+	INSERT INTO report_reasons (label)
+	VALUES (?)
+	RETURNING id, label, active, created_at`
+
+	stmt, err := r.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("prepare failed: %w", err)
+	}
+	defer stmt.Close()
+
+	var reason report.Reason
+	err = stmt.QueryRowContext(ctx, label).Scan(
+		&reason.ID,
+		&reason.Label,
+		&reason.Active,
+		&reason.CreatedAt,
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed: report_reasons.label") {
+			return nil, fmt.Errorf("report reason %q already exists: %w", label, ErrReasonAlreadyExists)
+		}
+		return nil, fmt.Errorf("failed to create report reason: %w", err)
+	}
+
+	return &reason, nil
+}
+
+// SetReasonActive retires a reason, or brings it back when active is true.
+// Reasons are never deleted so reports filed under them stay readable.
+func (r *Repo) SetReasonActive(ctx context.Context, id int, active bool) error {
+	query := `
+	UPDATE report_reasons
+	SET active = ?
+	WHERE id = ?`
+
+	stmt, err := r.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("prepare failed: %w", err)
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, active, id)
+	if err != nil {
+		return fmt.Errorf("exec failed: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("retrieving rows affected failed: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("report reason with ID %d not found: %w", id, ErrReasonNotFound)
+	}
+	return nil
+}
+
+// IsActiveReason reports whether label is one of the reasons currently on
+// offer, which is what new reports are validated against.
+func (r *Repo) IsActiveReason(ctx context.Context, label string) (bool, error) {
+	query := `
+	SELECT EXISTS (
+		SELECT 1 FROM report_reasons WHERE label = ? AND active = 1
+	)`
+
+	var exists bool
+	err := r.DB.QueryRowContext(ctx, query, label).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check report reason: %w", err)
+	}
+
+	return exists, nil
+}
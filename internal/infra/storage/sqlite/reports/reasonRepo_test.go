@@ -0,0 +1,98 @@
+package reports
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/arnald/forum/internal/pkg/path"
+)
+
+// newTestRepo returns a repository backed by a private in-memory database
+// with the project schema, and so the default reasons, applied.
+func newTestRepo(t *testing.T) *Repo {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	// Every connection to :memory: gets its own database, so keep just one.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	schema, err := os.ReadFile(path.NewResolver().GetPath("db/migrations/schema.sql"))
+	if err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+	_, err = db.Exec(string(schema))
+	if err != nil {
+		t.Fatalf("failed to apply schema: %v", err)
+	}
+
+	return NewRepo(db)
+}
+
+func TestRepo_ReportReasons(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	defaults, err := repo.GetReasons(ctx, false)
+	if err != nil {
+		t.Fatalf("GetReasons() error = %v", err)
+	}
+	if len(defaults) == 0 {
+		t.Fatal("GetReasons() returned no default reasons")
+	}
+
+	created, err := repo.CreateReason(ctx, "Impersonation")
+	if err != nil {
+		t.Fatalf("CreateReason() error = %v", err)
+	}
+	if !created.Active {
+		t.Error("CreateReason() Active = false, want true")
+	}
+
+	_, err = repo.CreateReason(ctx, "Impersonation")
+	if !errors.Is(err, ErrReasonAlreadyExists) {
+		t.Errorf("CreateReason() duplicate error = %v, want %v", err, ErrReasonAlreadyExists)
+	}
+
+	err = repo.SetReasonActive(ctx, created.ID, false)
+	if err != nil {
+		t.Fatalf("SetReasonActive() error = %v", err)
+	}
+
+	active, err := repo.IsActiveReason(ctx, "Impersonation")
+	if err != nil {
+		t.Fatalf("IsActiveReason() error = %v", err)
+	}
+	if active {
+		t.Error("IsActiveReason() = true for a retired reason, want false")
+	}
+
+	listed, err := repo.GetReasons(ctx, false)
+	if err != nil {
+		t.Fatalf("GetReasons() error = %v", err)
+	}
+	if len(listed) != len(defaults) {
+		t.Errorf("GetReasons() returned %d active reasons, want %d", len(listed), len(defaults))
+	}
+
+	all, err := repo.GetReasons(ctx, true)
+	if err != nil {
+		t.Fatalf("GetReasons(includeRetired) error = %v", err)
+	}
+	if len(all) != len(defaults)+1 {
+		t.Errorf("GetReasons(includeRetired) returned %d reasons, want %d", len(all), len(defaults)+1)
+	}
+
+	err = repo.SetReasonActive(ctx, 9999, false)
+	if !errors.Is(err, ErrReasonNotFound) {
+		t.Errorf("SetReasonActive() unknown id error = %v, want %v", err, ErrReasonNotFound)
+	}
+}
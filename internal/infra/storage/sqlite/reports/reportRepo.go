@@ -0,0 +1,241 @@
+package reports
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/arnald/forum/internal/domain/report"
+)
+
+// CreateCommentReport bumps the comment's pending report count, collapsing it
+// when the count reaches collapseThreshold, and files the report in the same
+// transaction so the count never drifts from the reports table. Reporting
+// one's own comment, or reporting it again while the first report is
+// pending, is refused before anything is counted.
+func (r *Repo) CreateCommentReport(ctx context.Context, rep *report.Report, collapseThreshold int) (collapsed bool, err error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				err = fmt.Errorf("transaction rollback failed: %w (original error: %w)", rollbackErr, err)
+			}
+			return
+		}
+		commitErr := tx.Commit()
+		if commitErr != nil {
+			err = fmt.Errorf("transaction commit failed: %w", commitErr)
+		}
+	}()
+
+	var authorID string
+	err = tx.QueryRowContext(ctx, "SELECT user_id FROM comments WHERE id = ?", rep.CommentID).Scan(&authorID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, ErrCommentNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get comment author: %w", err)
+	}
+	if authorID == rep.ReporterID {
+		return false, ErrSelfReport
+	}
+
+	var pending bool
+	err = tx.QueryRowContext(ctx, `
+	SELECT EXISTS(
+		SELECT 1 FROM reports
+		WHERE reporter_id = ? AND comment_id = ? AND status = 'pending'
+	)`,
+		rep.ReporterID,
+		rep.CommentID,
+	).Scan(&pending)
+	if err != nil {
+		return false, fmt.Errorf("failed to check pending reports: %w", err)
+	}
+	if pending {
+		return false, ErrDuplicateReport
+	}
+
+	result, err := tx.ExecContext(ctx, `
+	UPDATE comments
+	SET report_count = report_count + 1,
+		collapsed = CASE WHEN ? > 0 AND report_count + 1 >= ? THEN 1 ELSE collapsed END
+	WHERE id = ?`,
+		collapseThreshold,
+		collapseThreshold,
+		rep.CommentID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to count report: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return false, ErrCommentNotFound
+	}
+
+	err = tx.QueryRowContext(ctx, "SELECT collapsed FROM comments WHERE id = ?", rep.CommentID).Scan(&collapsed)
+	if err != nil {
+		return false, fmt.Errorf("failed to read collapsed flag: %w", err)
+	}
+
+	result, err = tx.ExecContext(ctx, `
+	INSERT INTO reports (reporter_id, comment_id, reason, description)
+	VALUES (?, ?, ?, ?)`,
+		rep.ReporterID,
+		rep.CommentID,
+		rep.Reason,
+		rep.Description,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to insert report: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return false, fmt.Errorf("failed to get report id: %w", err)
+	}
+	rep.ID = int(id)
+	rep.Status = report.StatusPending
+
+	return collapsed, nil
+}
+
+// CreateTopicReport files a pending report against a live topic, refusing the
+// same self-reports and duplicates CreateCommentReport does.
+func (r *Repo) CreateTopicReport(ctx context.Context, rep *report.Report) (err error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				err = fmt.Errorf("transaction rollback failed: %w (original error: %w)", rollbackErr, err)
+			}
+			return
+		}
+		commitErr := tx.Commit()
+		if commitErr != nil {
+			err = fmt.Errorf("transaction commit failed: %w", commitErr)
+		}
+	}()
+
+	var authorID string
+	err = tx.QueryRowContext(ctx, "SELECT user_id FROM topics WHERE id = ? AND deleted_at IS NULL", rep.TopicID).Scan(&authorID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrTopicNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get topic author: %w", err)
+	}
+	if authorID == rep.ReporterID {
+		return ErrSelfReport
+	}
+
+	var pending bool
+	err = tx.QueryRowContext(ctx, `
+	SELECT EXISTS(
+		SELECT 1 FROM reports
+		WHERE reporter_id = ? AND topic_id = ? AND status = 'pending'
+	)`,
+		rep.ReporterID,
+		rep.TopicID,
+	).Scan(&pending)
+	if err != nil {
+		return fmt.Errorf("failed to check pending reports: %w", err)
+	}
+	if pending {
+		return ErrDuplicateReport
+	}
+
+	result, err := tx.ExecContext(ctx, `
+	INSERT INTO reports (reporter_id, topic_id, reason, description)
+	VALUES (?, ?, ?, ?)`,
+		rep.ReporterID,
+		rep.TopicID,
+		rep.Reason,
+		rep.Description,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert report: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get report id: %w", err)
+	}
+	rep.ID = int(id)
+	rep.Status = report.StatusPending
+
+	return nil
+}
+
+// ResolveCommentReports closes the comment's pending reports and resets its
+// count. Only a dismissal uncollapses it; upheld reports leave the comment
+// hidden behind the warning.
+func (r *Repo) ResolveCommentReports(ctx context.Context, commentID int, status, moderatorID string) (err error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				err = fmt.Errorf("transaction rollback failed: %w (original error: %w)", rollbackErr, err)
+			}
+			return
+		}
+		commitErr := tx.Commit()
+		if commitErr != nil {
+			err = fmt.Errorf("transaction commit failed: %w", commitErr)
+		}
+	}()
+
+	result, err := tx.ExecContext(ctx, `
+	UPDATE reports
+	SET status = ?, resolved_by = ?, resolved_at = CURRENT_TIMESTAMP
+	WHERE comment_id = ? AND status = 'pending'`,
+		status,
+		moderatorID,
+		commentID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to resolve reports: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNoPendingReports
+	}
+
+	_, err = tx.ExecContext(ctx, `
+	UPDATE comments
+	SET report_count = 0,
+		collapsed = CASE WHEN ? = 'dismissed' THEN 0 ELSE collapsed END
+	WHERE id = ?`,
+		status,
+		commentID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to reset report count: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,238 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/arnald/forum/internal/domain/report"
+)
+
+const collapseThreshold = 3
+
+// seedComment inserts an author, a topic and one comment on it, plus enough
+// reporters to cross collapseThreshold, and returns the comment's id.
+func seedComment(t *testing.T, repo *Repo) int {
+	t.Helper()
+
+	for i := range collapseThreshold + 1 {
+		id := fmt.Sprintf("user-%d", i)
+		_, err := repo.DB.Exec(`INSERT INTO users (id, email, username) VALUES (?, ?, ?)`,
+			id, id+"@example.com", id)
+		if err != nil {
+			t.Fatalf("failed to seed user: %v", err)
+		}
+	}
+
+	_, err := repo.DB.Exec(`INSERT INTO topics (id, user_id, title, content) VALUES (1, 'user-0', 'Topic', 'Topic content')`)
+	if err != nil {
+		t.Fatalf("failed to seed topic: %v", err)
+	}
+	_, err = repo.DB.Exec(`INSERT INTO comments (id, user_id, topic_id, content) VALUES (1, 'user-0', 1, 'A comment')`)
+	if err != nil {
+		t.Fatalf("failed to seed comment: %v", err)
+	}
+
+	return 1
+}
+
+func fileReports(t *testing.T, repo *Repo, commentID, count int) bool {
+	t.Helper()
+
+	var collapsed bool
+	for i := 1; i <= count; i++ {
+		var err error
+		collapsed, err = repo.CreateCommentReport(context.Background(), &report.Report{
+			ReporterID: fmt.Sprintf("user-%d", i),
+			CommentID:  commentID,
+			Reason:     "Spam",
+		}, collapseThreshold)
+		if err != nil {
+			t.Fatalf("CreateCommentReport() error = %v", err)
+		}
+	}
+	return collapsed
+}
+
+func commentState(t *testing.T, repo *Repo, commentID int) (int, bool) {
+	t.Helper()
+
+	var count int
+	var collapsed bool
+	err := repo.DB.QueryRow("SELECT report_count, collapsed FROM comments WHERE id = ?", commentID).
+		Scan(&count, &collapsed)
+	if err != nil {
+		t.Fatalf("failed to read comment: %v", err)
+	}
+	return count, collapsed
+}
+
+func TestRepo_CommentReportThreshold(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("below the threshold the comment stays visible", func(t *testing.T) {
+		repo := newTestRepo(t)
+		commentID := seedComment(t, repo)
+
+		if fileReports(t, repo, commentID, collapseThreshold-1) {
+			t.Error("CreateCommentReport() collapsed the comment before the threshold")
+		}
+		count, _ := commentState(t, repo, commentID)
+		if count != collapseThreshold-1 {
+			t.Errorf("report_count = %d, want %d", count, collapseThreshold-1)
+		}
+	})
+
+	t.Run("crossing the threshold collapses and dismissal restores", func(t *testing.T) {
+		repo := newTestRepo(t)
+		commentID := seedComment(t, repo)
+
+		if !fileReports(t, repo, commentID, collapseThreshold) {
+			t.Fatal("CreateCommentReport() did not collapse the comment at the threshold")
+		}
+
+		err := repo.ResolveCommentReports(ctx, commentID, report.StatusDismissed, "user-0")
+		if err != nil {
+			t.Fatalf("ResolveCommentReports() error = %v", err)
+		}
+		count, collapsed := commentState(t, repo, commentID)
+		if collapsed || count != 0 {
+			t.Errorf("after dismissal report_count = %d, collapsed = %v; want 0, false", count, collapsed)
+		}
+
+		err = repo.ResolveCommentReports(ctx, commentID, report.StatusDismissed, "user-0")
+		if !errors.Is(err, ErrNoPendingReports) {
+			t.Errorf("ResolveCommentReports() again error = %v, want %v", err, ErrNoPendingReports)
+		}
+	})
+
+	t.Run("upheld reports keep the comment collapsed", func(t *testing.T) {
+		repo := newTestRepo(t)
+		commentID := seedComment(t, repo)
+		fileReports(t, repo, commentID, collapseThreshold)
+
+		err := repo.ResolveCommentReports(ctx, commentID, report.StatusResolved, "user-0")
+		if err != nil {
+			t.Fatalf("ResolveCommentReports() error = %v", err)
+		}
+		_, collapsed := commentState(t, repo, commentID)
+		if !collapsed {
+			t.Error("upholding reports uncollapsed the comment")
+		}
+	})
+
+	t.Run("a zero threshold never collapses", func(t *testing.T) {
+		repo := newTestRepo(t)
+		commentID := seedComment(t, repo)
+
+		collapsed, err := repo.CreateCommentReport(ctx, &report.Report{
+			ReporterID: "user-1",
+			CommentID:  commentID,
+			Reason:     "Spam",
+		}, 0)
+		if err != nil {
+			t.Fatalf("CreateCommentReport() error = %v", err)
+		}
+		if collapsed {
+			t.Error("CreateCommentReport() collapsed with threshold 0")
+		}
+	})
+
+	t.Run("unknown comment", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		_, err := repo.CreateCommentReport(ctx, &report.Report{CommentID: 42, Reason: "Spam"}, collapseThreshold)
+		if !errors.Is(err, ErrCommentNotFound) {
+			t.Errorf("CreateCommentReport() error = %v, want %v", err, ErrCommentNotFound)
+		}
+	})
+}
+
+func TestRepo_ReportGuards(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("authors cannot report their own comment", func(t *testing.T) {
+		repo := newTestRepo(t)
+		commentID := seedComment(t, repo)
+
+		_, err := repo.CreateCommentReport(ctx, &report.Report{
+			ReporterID: "user-0",
+			CommentID:  commentID,
+			Reason:     "Spam",
+		}, collapseThreshold)
+		if !errors.Is(err, ErrSelfReport) {
+			t.Fatalf("CreateCommentReport() error = %v, want %v", err, ErrSelfReport)
+		}
+		count, _ := commentState(t, repo, commentID)
+		if count != 0 {
+			t.Errorf("report_count = %d, want 0", count)
+		}
+	})
+
+	t.Run("a second pending comment report is refused until the first is settled", func(t *testing.T) {
+		repo := newTestRepo(t)
+		commentID := seedComment(t, repo)
+		fileReports(t, repo, commentID, 1)
+
+		_, err := repo.CreateCommentReport(ctx, &report.Report{
+			ReporterID: "user-1",
+			CommentID:  commentID,
+			Reason:     "Spam",
+		}, collapseThreshold)
+		if !errors.Is(err, ErrDuplicateReport) {
+			t.Fatalf("CreateCommentReport() again error = %v, want %v", err, ErrDuplicateReport)
+		}
+		count, _ := commentState(t, repo, commentID)
+		if count != 1 {
+			t.Errorf("report_count = %d, want 1", count)
+		}
+
+		err = repo.ResolveCommentReports(ctx, commentID, report.StatusDismissed, "user-0")
+		if err != nil {
+			t.Fatalf("ResolveCommentReports() error = %v", err)
+		}
+		fileReports(t, repo, commentID, 1)
+	})
+
+	t.Run("topic reports are guarded the same way", func(t *testing.T) {
+		repo := newTestRepo(t)
+		seedComment(t, repo)
+
+		rep := &report.Report{ReporterID: "user-1", TopicID: 1, Reason: "Spam"}
+		err := repo.CreateTopicReport(ctx, rep)
+		if err != nil {
+			t.Fatalf("CreateTopicReport() error = %v", err)
+		}
+		if rep.ID == 0 || rep.Status != report.StatusPending {
+			t.Errorf("CreateTopicReport() report = %+v, want a pending report with an id", rep)
+		}
+
+		err = repo.CreateTopicReport(ctx, &report.Report{ReporterID: "user-1", TopicID: 1, Reason: "Spam"})
+		if !errors.Is(err, ErrDuplicateReport) {
+			t.Errorf("CreateTopicReport() again error = %v, want %v", err, ErrDuplicateReport)
+		}
+
+		err = repo.CreateTopicReport(ctx, &report.Report{ReporterID: "user-0", TopicID: 1, Reason: "Spam"})
+		if !errors.Is(err, ErrSelfReport) {
+			t.Errorf("CreateTopicReport() by the author error = %v, want %v", err, ErrSelfReport)
+		}
+	})
+
+	t.Run("deleted or unknown topics cannot be reported", func(t *testing.T) {
+		repo := newTestRepo(t)
+		seedComment(t, repo)
+
+		_, err := repo.DB.Exec(`UPDATE topics SET deleted_at = CURRENT_TIMESTAMP WHERE id = 1`)
+		if err != nil {
+			t.Fatalf("failed to delete topic: %v", err)
+		}
+
+		for _, topicID := range []int{1, 42} {
+			err = repo.CreateTopicReport(ctx, &report.Report{ReporterID: "user-1", TopicID: topicID, Reason: "Spam"})
+			if !errors.Is(err, ErrTopicNotFound) {
+				t.Errorf("CreateTopicReport(%d) error = %v, want %v", topicID, err, ErrTopicNotFound)
+			}
+		}
+	})
+}
@@ -4,17 +4,25 @@ import (
 	"database/sql"
 
 	"github.com/arnald/forum/internal/domain/activity"
+	"github.com/arnald/forum/internal/domain/audit"
 	"github.com/arnald/forum/internal/domain/category"
 	"github.com/arnald/forum/internal/domain/comment"
+	"github.com/arnald/forum/internal/domain/dataimport"
 	"github.com/arnald/forum/internal/domain/notification"
 	"github.com/arnald/forum/internal/domain/oauth"
+	"github.com/arnald/forum/internal/domain/report"
+	"github.com/arnald/forum/internal/domain/rolerequest"
 	"github.com/arnald/forum/internal/domain/topic"
 	"github.com/arnald/forum/internal/domain/user"
 	"github.com/arnald/forum/internal/domain/vote"
 	activities "github.com/arnald/forum/internal/infra/storage/sqlite/activity"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/audits"
 	"github.com/arnald/forum/internal/infra/storage/sqlite/categories"
 	"github.com/arnald/forum/internal/infra/storage/sqlite/comments"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/imports"
 	oauthrepo "github.com/arnald/forum/internal/infra/storage/sqlite/oauth"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/reports"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/rolerequests"
 	"github.com/arnald/forum/internal/infra/storage/sqlite/topics"
 	"github.com/arnald/forum/internal/infra/storage/sqlite/users"
 	"github.com/arnald/forum/internal/infra/storage/sqlite/votes"
@@ -29,16 +37,24 @@ type Repositories struct {
 	NotificationRepo notification.Repository
 	OauthRepo        oauth.Repository
 	ActivityRepo     activity.Repository
+	ReportRepo       report.Repository
+	ImportRepo       dataimport.Repository
+	AuditRepo        audit.Repository
+	RoleRequestRepo  rolerequest.Repository
 }
 
 func NewRepositories(db *sql.DB) *Repositories {
 	return &Repositories{
-		UserRepo:     users.NewRepo(db),
-		CategoryRepo: categories.NewRepo(db),
-		TopicRepo:    topics.NewRepo(db),
-		CommentRepo:  comments.NewRepo(db),
-		VoteRepo:     votes.NewRepo(db),
-		OauthRepo:    oauthrepo.NewOAuthRepository(db),
-		ActivityRepo: activities.NewRepo(db),
+		UserRepo:        users.NewRepo(db),
+		CategoryRepo:    categories.NewRepo(db),
+		TopicRepo:       topics.NewRepo(db),
+		CommentRepo:     comments.NewRepo(db),
+		VoteRepo:        votes.NewRepo(db),
+		OauthRepo:       oauthrepo.NewOAuthRepository(db),
+		ActivityRepo:    activities.NewRepo(db),
+		ReportRepo:      reports.NewRepo(db),
+		ImportRepo:      imports.NewRepo(db),
+		AuditRepo:       audits.NewRepo(db),
+		RoleRequestRepo: rolerequests.NewRepo(db),
 	}
 }
@@ -0,0 +1,9 @@
+package rolerequests
+
+import "errors"
+
+var (
+	ErrRoleRequestNotFound   = errors.New("role request not found")
+	ErrPendingRequestExists  = errors.New("user already has a pending role request")
+	ErrRoleRequestNotPending = errors.New("role request has already been decided")
+)
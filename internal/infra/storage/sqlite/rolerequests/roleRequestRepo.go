@@ -0,0 +1,101 @@
+package rolerequests
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/arnald/forum/internal/domain/rolerequest"
+)
+
+type Repo struct {
+	DB *sql.DB
+}
+
+func NewRepo(db *sql.DB) *Repo {
+	return &Repo{
+		DB: db,
+	}
+}
+
+func (r *Repo) CreateRoleRequest(ctx context.Context, request *rolerequest.RoleRequest) error {
+	query := `
+	INSERT INTO role_requests (user_id, requested_role, reason)
+	VALUES (?, ?, ?)
+	RETURNING id, status, created_at`
+
+	stmt, err := r.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("prepare failed: %w", err)
+	}
+	defer stmt.Close()
+
+	err = stmt.QueryRowContext(ctx, request.UserID, request.RequestedRole, request.Reason).Scan(
+		&request.ID,
+		&request.Status,
+		&request.CreatedAt,
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed: role_requests.user_id") {
+			return fmt.Errorf("user %s: %w", request.UserID, ErrPendingRequestExists)
+		}
+		return fmt.Errorf("failed to create role request: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repo) GetRoleRequestByID(ctx context.Context, id int) (*rolerequest.RoleRequest, error) {
+	query := `
+	SELECT id, user_id, requested_role, reason, status, COALESCE(admin_id, ''),
+	       admin_notes, created_at, COALESCE(reviewed_at, '')
+	FROM role_requests
+	WHERE id = ?`
+
+	var request rolerequest.RoleRequest
+	err := r.DB.QueryRowContext(ctx, query, id).Scan(
+		&request.ID,
+		&request.UserID,
+		&request.RequestedRole,
+		&request.Reason,
+		&request.Status,
+		&request.AdminID,
+		&request.AdminNotes,
+		&request.CreatedAt,
+		&request.ReviewedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("role request with ID %d not found: %w", id, ErrRoleRequestNotFound)
+		}
+		return nil, fmt.Errorf("failed to get role request: %w", err)
+	}
+
+	return &request, nil
+}
+
+// DecideRoleRequest only touches a request that is still pending, so two
+// admins deciding the same request at once cannot both succeed.
+func (r *Repo) DecideRoleRequest(ctx context.Context, id int, status, adminID, adminNotes string) error {
+	query := `
+	UPDATE role_requests
+	SET status = ?, admin_id = ?, admin_notes = ?, reviewed_at = CURRENT_TIMESTAMP
+	WHERE id = ? AND status = ?`
+
+	result, err := r.DB.ExecContext(ctx, query, status, adminID, adminNotes, id, rolerequest.StatusPending)
+	if err != nil {
+		return fmt.Errorf("failed to decide role request: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("retrieving rows affected failed: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("role request with ID %d: %w", id, ErrRoleRequestNotPending)
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package rolerequests
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/arnald/forum/internal/domain/rolerequest"
+	"github.com/arnald/forum/internal/domain/user"
+	"github.com/arnald/forum/internal/pkg/path"
+)
+
+// newTestRepo returns a repository backed by a private in-memory database
+// with the project schema applied and two users, "alice" and the admin
+// "root".
+func newTestRepo(t *testing.T) *Repo {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	// Every connection to :memory: gets its own database, so keep just one.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	schema, err := os.ReadFile(path.NewResolver().GetPath("db/migrations/schema.sql"))
+	if err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+	_, err = db.Exec(string(schema))
+	if err != nil {
+		t.Fatalf("failed to apply schema: %v", err)
+	}
+
+	_, err = db.Exec(`INSERT INTO users (id, email, username, password_hash, role) VALUES
+		('alice', 'alice@example.com', 'alice', 'hash', 'user'),
+		('root', 'root@example.com', 'root', 'hash', 'admin')`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+
+	return NewRepo(db)
+}
+
+func TestRepo_RoleRequestLifecycle(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	request := &rolerequest.RoleRequest{
+		UserID:        "alice",
+		RequestedRole: user.RoleModerator,
+		Reason:        "I have been helping newcomers for a year",
+	}
+	err := repo.CreateRoleRequest(ctx, request)
+	if err != nil {
+		t.Fatalf("CreateRoleRequest() error = %v", err)
+	}
+	if request.ID == 0 || request.Status != rolerequest.StatusPending {
+		t.Fatalf("CreateRoleRequest() = id %d status %q, want a pending request", request.ID, request.Status)
+	}
+
+	err = repo.CreateRoleRequest(ctx, &rolerequest.RoleRequest{
+		UserID:        "alice",
+		RequestedRole: user.RoleModerator,
+		Reason:        "Asking again",
+	})
+	if !errors.Is(err, ErrPendingRequestExists) {
+		t.Errorf("second CreateRoleRequest() error = %v, want ErrPendingRequestExists", err)
+	}
+
+	err = repo.DecideRoleRequest(ctx, request.ID, rolerequest.StatusApproved, "root", "Welcome aboard")
+	if err != nil {
+		t.Fatalf("DecideRoleRequest() error = %v", err)
+	}
+
+	decided, err := repo.GetRoleRequestByID(ctx, request.ID)
+	if err != nil {
+		t.Fatalf("GetRoleRequestByID() error = %v", err)
+	}
+	if decided.Status != rolerequest.StatusApproved || decided.AdminID != "root" || decided.AdminNotes != "Welcome aboard" {
+		t.Errorf("GetRoleRequestByID() = %+v, want approved by root with notes", decided)
+	}
+	if decided.ReviewedAt == "" {
+		t.Error("GetRoleRequestByID() ReviewedAt is empty after the decision")
+	}
+
+	err = repo.DecideRoleRequest(ctx, request.ID, rolerequest.StatusRejected, "root", "")
+	if !errors.Is(err, ErrRoleRequestNotPending) {
+		t.Errorf("deciding twice error = %v, want ErrRoleRequestNotPending", err)
+	}
+
+	// Once the first request is decided the user may ask again.
+	err = repo.CreateRoleRequest(ctx, &rolerequest.RoleRequest{
+		UserID:        "alice",
+		RequestedRole: user.RoleModerator,
+		Reason:        "Asking again",
+	})
+	if err != nil {
+		t.Errorf("CreateRoleRequest() after a decision error = %v", err)
+	}
+
+	_, err = repo.GetRoleRequestByID(ctx, 999)
+	if !errors.Is(err, ErrRoleRequestNotFound) {
+		t.Errorf("GetRoleRequestByID(999) error = %v, want ErrRoleRequestNotFound", err)
+	}
+}
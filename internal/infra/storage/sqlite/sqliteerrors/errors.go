@@ -0,0 +1,35 @@
+package sqliteerrors
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+var (
+	ErrConstraint        = errors.New("sqlite constrain error")
+	ErrUnknownConstraint = errors.New("sqlite unknow constraint error")
+	ErrInvalidReference  = errors.New("referenced record does not exist")
+	ErrCheckConstraint   = errors.New("value violates a check constraint")
+)
+
+// MapSQLiteError classifies a constraint failure by SQLite's extended result
+// code, still wrapping the driver error. Other errors are returned as is.
+func MapSQLiteError(err error) error {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		if sqliteErr.Code == sqlite3.ErrConstraint {
+			switch {
+			case errors.Is(sqliteErr.ExtendedCode, sqlite3.ErrConstraintForeignKey):
+				return fmt.Errorf("%w: %w", ErrInvalidReference, sqliteErr)
+			case errors.Is(sqliteErr.ExtendedCode, sqlite3.ErrConstraintCheck):
+				return fmt.Errorf("%w: %w", ErrCheckConstraint, sqliteErr)
+			default:
+				return fmt.Errorf("%w: %w", ErrConstraint, sqliteErr)
+			}
+		}
+		return fmt.Errorf("%w: %w: %s ", ErrUnknownConstraint, sqliteErr.Code, sqliteErr.Error())
+	}
+	return err
+}
@@ -0,0 +1,74 @@
+package topics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/arnald/forum/internal/domain/topic"
+)
+
+// ToggleBookmark saves the topic for the user, or removes the bookmark if
+// they already have one, and reports whether the topic is now bookmarked.
+// Deleted topics can be unbookmarked but not bookmarked.
+func (r Repo) ToggleBookmark(ctx context.Context, userID string, topicID int) (bookmarked bool, err error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				err = fmt.Errorf("transaction rollback failed: %w (original error: %w)", rollbackErr, err)
+			}
+			return
+		}
+		err = tx.Commit()
+		if err != nil {
+			err = fmt.Errorf("transaction commit failed: %w", err)
+		}
+	}()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM bookmarks WHERE user_id = ? AND topic_id = ?`, userID, topicID)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove bookmark: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected > 0 {
+		return false, nil
+	}
+
+	var exists int
+	err = tx.QueryRowContext(ctx, `SELECT 1 FROM topics WHERE id = ? AND deleted_at IS NULL AND status = 'published'`, topicID).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, fmt.Errorf("topic with ID %d not found: %w", topicID, ErrTopicNotFound)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to query topic: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO bookmarks (user_id, topic_id) VALUES (?, ?)`, userID, topicID)
+	if err != nil {
+		return false, fmt.Errorf("failed to add bookmark: %w", err)
+	}
+
+	return true, nil
+}
+
+// GetBookmarkedTopics lists the topics the user bookmarked, most recently
+// saved first. Deleted topics are left out.
+func (r Repo) GetBookmarkedTopics(ctx context.Context, userID string) ([]topic.Topic, error) {
+	query := topicListSelect(true) + `
+    INNER JOIN bookmarks b ON t.id = b.topic_id AND b.user_id = ?
+    WHERE t.deleted_at IS NULL AND t.status = 'published'` +
+		topicListGroupBy(true) + `, b.created_at
+    ORDER BY b.created_at DESC, t.id DESC`
+
+	return r.queryTopicList(ctx, query, true, userID, userID)
+}
@@ -0,0 +1,78 @@
+package topics
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRepo_Bookmarks(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.DB.Exec(`
+	INSERT INTO users (id, email, username) VALUES ('reader', 'reader@example.com', 'reader');
+	INSERT INTO topics (id, user_id, title, content) VALUES
+		(1, 'reader', 'First', 'content'),
+		(2, 'reader', 'Second', 'content'),
+		(3, 'reader', 'Gone', 'content');
+	UPDATE topics SET deleted_at = CURRENT_TIMESTAMP WHERE id = 3;`)
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	listed := func(t *testing.T) []int {
+		t.Helper()
+		got, listErr := repo.GetBookmarkedTopics(ctx, "reader")
+		if listErr != nil {
+			t.Fatalf("GetBookmarkedTopics() error = %v", listErr)
+		}
+		ids := make([]int, 0, len(got))
+		for _, topic := range got {
+			ids = append(ids, topic.ID)
+		}
+		return ids
+	}
+
+	t.Run("toggle on", func(t *testing.T) {
+		for _, topicID := range []int{1, 2} {
+			bookmarked, toggleErr := repo.ToggleBookmark(ctx, "reader", topicID)
+			if toggleErr != nil || !bookmarked {
+				t.Fatalf("ToggleBookmark(%d) = %v, %v, want true, nil", topicID, bookmarked, toggleErr)
+			}
+		}
+		// Both land in the same second, so the newer id breaks the tie.
+		if got := listed(t); len(got) != 2 || got[0] != 2 || got[1] != 1 {
+			t.Errorf("GetBookmarkedTopics() ids = %v, want [2 1]", got)
+		}
+	})
+
+	t.Run("toggle off", func(t *testing.T) {
+		bookmarked, toggleErr := repo.ToggleBookmark(ctx, "reader", 2)
+		if toggleErr != nil || bookmarked {
+			t.Fatalf("ToggleBookmark(2) = %v, %v, want false, nil", bookmarked, toggleErr)
+		}
+		if got := listed(t); len(got) != 1 || got[0] != 1 {
+			t.Errorf("GetBookmarkedTopics() ids = %v, want [1]", got)
+		}
+	})
+
+	t.Run("missing and deleted topics", func(t *testing.T) {
+		for _, topicID := range []int{3, 99} {
+			_, toggleErr := repo.ToggleBookmark(ctx, "reader", topicID)
+			if !errors.Is(toggleErr, ErrTopicNotFound) {
+				t.Errorf("ToggleBookmark(%d) error = %v, want %v", topicID, toggleErr, ErrTopicNotFound)
+			}
+		}
+	})
+
+	t.Run("deleted topics drop out of the list", func(t *testing.T) {
+		err := repo.DeleteTopic(ctx, "reader", 1)
+		if err != nil {
+			t.Fatalf("DeleteTopic() error = %v", err)
+		}
+		if got := listed(t); len(got) != 0 {
+			t.Errorf("GetBookmarkedTopics() ids = %v, want none", got)
+		}
+	})
+}
@@ -0,0 +1,42 @@
+package topics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/arnald/forum/internal/domain/topic"
+)
+
+// FindRecentDuplicate returns the newest topic userID created within window
+// with exactly this title and content, or nil when there is none. Deleted
+// topics do not count.
+func (r Repo) FindRecentDuplicate(ctx context.Context, userID, title, content string, window time.Duration) (*topic.Topic, error) {
+	found := &topic.Topic{
+		UserID:  userID,
+		Title:   title,
+		Content: content,
+	}
+
+	err := r.DB.QueryRowContext(ctx, `
+	SELECT id, slug, status, created_at
+	FROM topics
+	WHERE user_id = ? AND title = ? AND content = ? AND deleted_at IS NULL AND created_at >= ?
+	ORDER BY id DESC
+	LIMIT 1`,
+		userID,
+		title,
+		content,
+		time.Now().UTC().Add(-window).Format(time.DateTime),
+	).Scan(&found.ID, &found.Slug, &found.Status, &found.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up duplicate topic: %w", err)
+	}
+
+	return found, nil
+}
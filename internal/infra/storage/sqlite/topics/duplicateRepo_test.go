@@ -0,0 +1,65 @@
+package topics
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRepo_FindRecentDuplicate(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.DB.Exec(`
+	INSERT INTO users (id, email, username) VALUES
+		('author', 'author@example.com', 'author'),
+		('other', 'other@example.com', 'other');
+	INSERT INTO topics (id, user_id, title, slug, content) VALUES
+		(1, 'author', 'Hello', 'hello', 'Same words'),
+		(2, 'author', 'Old news', 'old-news', 'Posted a while ago');
+	UPDATE topics SET created_at = datetime('now', '-1 minute') WHERE id = 2;`)
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	window := 30 * time.Second
+	lookups := []struct {
+		name    string
+		userID  string
+		title   string
+		content string
+		wantID  int
+	}{
+		{name: "same topic within the window", userID: "author", title: "Hello", content: "Same words", wantID: 1},
+		{name: "repost after the window", userID: "author", title: "Old news", content: "Posted a while ago", wantID: 0},
+		{name: "different content", userID: "author", title: "Hello", content: "Other words", wantID: 0},
+		{name: "another author", userID: "other", title: "Hello", content: "Same words", wantID: 0},
+	}
+	for _, l := range lookups {
+		found, findErr := repo.FindRecentDuplicate(ctx, l.userID, l.title, l.content, window)
+		if findErr != nil {
+			t.Fatalf("%s: FindRecentDuplicate() error = %v", l.name, findErr)
+		}
+		gotID := 0
+		if found != nil {
+			gotID = found.ID
+		}
+		if gotID != l.wantID {
+			t.Errorf("%s: FindRecentDuplicate() id = %d, want %d", l.name, gotID, l.wantID)
+		}
+	}
+
+	found, err := repo.FindRecentDuplicate(ctx, "author", "Hello", "Same words", window)
+	if err != nil || found == nil || found.Slug != "hello" {
+		t.Fatalf("FindRecentDuplicate() = %+v, %v, want topic 1 with its slug", found, err)
+	}
+
+	err = repo.DeleteTopic(ctx, "author", 1)
+	if err != nil {
+		t.Fatalf("DeleteTopic() error = %v", err)
+	}
+	found, err = repo.FindRecentDuplicate(ctx, "author", "Hello", "Same words", window)
+	if err != nil || found != nil {
+		t.Errorf("FindRecentDuplicate() after delete = %+v, %v, want nil", found, err)
+	}
+}
@@ -5,4 +5,11 @@ import "errors"
 var (
 	ErrTopicNotFound = errors.New("topic not found")
 	ErrUserNotFound  = errors.New("user not found")
+	// ErrCategoryNotFound is returned when a topic is filed under a
+	// category that no longer exists.
+	ErrCategoryNotFound = errors.New("category not found")
+	// ErrInvalidOrderBy and ErrInvalidOrder reject topic list orderings
+	// outside the supported columns and directions.
+	ErrInvalidOrderBy = errors.New("invalid topic order by field")
+	ErrInvalidOrder   = errors.New("invalid topic sort order")
 )
@@ -0,0 +1,66 @@
+package topics
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/arnald/forum/internal/domain/topic"
+)
+
+// recordRevision saves the topic's current title and content before editorID
+// edits it. A topic the editor may not edit is left for the update that
+// follows to reject, which rolls this insert back with it.
+func (r Repo) recordRevision(ctx context.Context, tx *sql.Tx, topicID int, editorID string) error {
+	query := `
+	INSERT INTO topic_revisions (topic_id, title, content, editor_id)
+	SELECT id, title, content, ?
+	FROM topics
+	WHERE id = ? AND user_id = ? AND deleted_at IS NULL`
+
+	_, err := tx.ExecContext(ctx, query, editorID, topicID, editorID)
+	if err != nil {
+		return fmt.Errorf("failed to record revision: %w", err)
+	}
+
+	return nil
+}
+
+// GetTopicRevisions returns the topic's earlier versions, newest first.
+func (r Repo) GetTopicRevisions(ctx context.Context, topicID int) ([]topic.Revision, error) {
+	query := `
+	SELECT tr.id, tr.topic_id, tr.title, tr.content, tr.editor_id, COALESCE(u.username, ''), tr.created_at
+	FROM topic_revisions tr
+	LEFT JOIN users u ON u.id = tr.editor_id
+	WHERE tr.topic_id = ?
+	ORDER BY tr.id DESC`
+
+	stmt, err := r.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("prepare failed: %w", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, topicID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := make([]topic.Revision, 0)
+	for rows.Next() {
+		var rev topic.Revision
+		err = rows.Scan(&rev.ID, &rev.TopicID, &rev.Title, &rev.Content, &rev.EditorID, &rev.EditorUsername, &rev.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan revision: %w", err)
+		}
+		revisions = append(revisions, rev)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("error iterating revisions: %w", err)
+	}
+
+	return revisions, nil
+}
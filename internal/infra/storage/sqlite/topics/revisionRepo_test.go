@@ -0,0 +1,69 @@
+package topics
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/arnald/forum/internal/domain/topic"
+)
+
+func TestRepo_TopicRevisions(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.DB.Exec(`
+	INSERT INTO users (id, email, username) VALUES
+		('author', 'author@example.com', 'author'),
+		('other', 'other@example.com', 'other');
+	INSERT INTO topics (id, user_id, title, content) VALUES (1, 'author', 'First title', 'first content');`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+
+	edit := func(userID, title, content string) error {
+		return repo.UpdateTopic(ctx, &topic.Topic{
+			ID:      1,
+			UserID:  userID,
+			Title:   title,
+			Content: content,
+		})
+	}
+
+	err = edit("author", "Second title", "second content")
+	if err != nil {
+		t.Fatalf("first UpdateTopic() error = %v", err)
+	}
+	err = edit("author", "Third title", "third content")
+	if err != nil {
+		t.Fatalf("second UpdateTopic() error = %v", err)
+	}
+
+	// A rejected edit must not leave a revision behind.
+	err = edit("other", "Hijacked", "hijacked")
+	if !errors.Is(err, ErrTopicNotFound) {
+		t.Fatalf("UpdateTopic() by another user error = %v, want %v", err, ErrTopicNotFound)
+	}
+
+	revisions, err := repo.GetTopicRevisions(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetTopicRevisions() error = %v", err)
+	}
+	if len(revisions) != 2 {
+		t.Fatalf("GetTopicRevisions() returned %d revisions, want 2", len(revisions))
+	}
+	if revisions[0].Title != "Second title" || revisions[1].Title != "First title" {
+		t.Errorf("revision titles = %q, %q, want newest first", revisions[0].Title, revisions[1].Title)
+	}
+	if revisions[1].Content != "first content" || revisions[1].EditorUsername != "author" {
+		t.Errorf("oldest revision = %+v, want the original content edited by author", revisions[1])
+	}
+
+	current, err := repo.GetTopicByID(ctx, 1, nil)
+	if err != nil {
+		t.Fatalf("GetTopicByID() error = %v", err)
+	}
+	if current.Title != "Third title" || current.Content != "third content" {
+		t.Errorf("current topic = %q/%q, want the latest edit", current.Title, current.Content)
+	}
+}
@@ -11,6 +11,8 @@ import (
 
 	"github.com/arnald/forum/internal/domain/comment"
 	"github.com/arnald/forum/internal/domain/topic"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/sqliteerrors"
+	"github.com/arnald/forum/internal/pkg/helpers"
 )
 
 type Repo struct {
@@ -42,9 +44,14 @@ func (r Repo) CreateTopic(ctx context.Context, topic *topic.Topic) error {
 		}
 	}()
 
+	topic.Slug, err = r.uniqueSlug(ctx, tx, topic.Slug, 0)
+	if err != nil {
+		return err
+	}
+
 	query := `
-	INSERT INTO topics (user_id, title, content, image_path)
-	VALUES (?, ?, ?, ?)`
+	INSERT INTO topics (user_id, title, slug, content, summary, image_path, canonical_category_id, is_question, status)
+	VALUES (?, ?, ?, ?, ?, ?, NULLIF(?, 0), ?, COALESCE(NULLIF(?, ''), 'published'))`
 
 	stmt, err := tx.PrepareContext(ctx, query)
 	if err != nil {
@@ -56,15 +63,20 @@ func (r Repo) CreateTopic(ctx context.Context, topic *topic.Topic) error {
 		ctx,
 		topic.UserID,
 		topic.Title,
+		topic.Slug,
 		topic.Content,
+		topic.Summary,
 		topic.ImagePath,
+		topic.CanonicalCategoryID,
+		topic.IsQuestion,
+		topic.Status,
 	)
 	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
 			return fmt.Errorf("user with ID %s not found: %w", topic.UserID, ErrUserNotFound)
 		default:
-			return fmt.Errorf("failed to create topic: %w", err)
+			return r.mapCreateTopicError(ctx, tx, topic.CanonicalCategoryID, err)
 		}
 	}
 
@@ -85,13 +97,41 @@ func (r Repo) CreateTopic(ctx context.Context, topic *topic.Topic) error {
 	for _, categoryID := range topic.CategoryIDs {
 		_, err = categoryStmt.ExecContext(ctx, topicID, categoryID)
 		if err != nil {
-			return fmt.Errorf("failed to insert category %d for topic: %w", categoryID, err)
+			mapped := sqliteerrors.MapSQLiteError(err)
+			if errors.Is(mapped, sqliteerrors.ErrInvalidReference) {
+				return fmt.Errorf("category %d: %w: %w", categoryID, ErrCategoryNotFound, mapped)
+			}
+			return fmt.Errorf("failed to insert category %d for topic: %w", categoryID, mapped)
 		}
 	}
 
 	return nil
 }
 
+// mapCreateTopicError tells a missing canonical category apart from the
+// topic's other references, since SQLite does not say which foreign key
+// failed.
+func (r Repo) mapCreateTopicError(ctx context.Context, tx *sql.Tx, canonicalCategoryID int, err error) error {
+	mapped := sqliteerrors.MapSQLiteError(err)
+	if !errors.Is(mapped, sqliteerrors.ErrInvalidReference) || canonicalCategoryID == 0 {
+		return fmt.Errorf("failed to create topic: %w", mapped)
+	}
+
+	var exists bool
+	existsErr := tx.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM categories WHERE id = ?)`,
+		canonicalCategoryID,
+	).Scan(&exists)
+	if existsErr != nil {
+		return fmt.Errorf("failed to check category %d: %w", canonicalCategoryID, existsErr)
+	}
+	if !exists {
+		return fmt.Errorf("category %d: %w: %w", canonicalCategoryID, ErrCategoryNotFound, mapped)
+	}
+
+	return fmt.Errorf("failed to create topic: %w", mapped)
+}
+
 func (r Repo) UpdateTopic(ctx context.Context, topic *topic.Topic) error {
 	tx, err := r.DB.BeginTx(ctx, nil)
 	if err != nil {
@@ -111,11 +151,24 @@ func (r Repo) UpdateTopic(ctx context.Context, topic *topic.Topic) error {
 		}
 	}()
 
+	err = r.recordRevision(ctx, tx, topic.ID, topic.UserID)
+	if err != nil {
+		return err
+	}
+
+	topic.Slug, err = r.uniqueSlug(ctx, tx, topic.Slug, topic.ID)
+	if err != nil {
+		return err
+	}
+
 	// Update topic fields
 	query := `
 	UPDATE topics 
-	SET title = ?, content = ?, image_path = ?, updated_at = CURRENT_TIMESTAMP
-	WHERE id = ? AND user_id = ?`
+	SET title = ?, slug = ?, content = ?, summary = ?, image_path = ?, updated_at = CURRENT_TIMESTAMP,
+		bumped_at = COALESCE(NULLIF(?, ''), bumped_at),
+		canonical_category_id = NULLIF(?, 0),
+		is_question = ?
+	WHERE id = ? AND user_id = ? AND deleted_at IS NULL`
 
 	updateStmt, err := tx.PrepareContext(ctx, query)
 	if err != nil {
@@ -125,8 +178,13 @@ func (r Repo) UpdateTopic(ctx context.Context, topic *topic.Topic) error {
 
 	result, err := updateStmt.ExecContext(ctx,
 		topic.Title,
+		topic.Slug,
 		topic.Content,
+		topic.Summary,
 		topic.ImagePath,
+		topic.BumpedAt,
+		topic.CanonicalCategoryID,
+		topic.IsQuestion,
 		topic.ID,
 		topic.UserID,
 	)
@@ -151,10 +209,13 @@ func (r Repo) UpdateTopic(ctx context.Context, topic *topic.Topic) error {
 	return nil
 }
 
+// DeleteTopic soft-deletes the user's topic: it drops out of every listing but
+// keeps its comments and votes so an admin can restore it.
 func (r Repo) DeleteTopic(ctx context.Context, userID string, topicID int) error {
 	query := `
-	DELETE FROM topics
-	WHERE id = ? AND user_id = ?`
+	UPDATE topics
+	SET deleted_at = CURRENT_TIMESTAMP
+	WHERE id = ? AND user_id = ? AND deleted_at IS NULL`
 
 	stmt, err := r.DB.PrepareContext(ctx, query)
 	if err != nil {
@@ -179,17 +240,83 @@ func (r Repo) DeleteTopic(ctx context.Context, userID string, topicID int) error
 	return nil
 }
 
+// RestoreTopic brings back a soft-deleted topic along with its comments and
+// votes. Topics that are not deleted are reported as not found.
+func (r Repo) RestoreTopic(ctx context.Context, topicID int) error {
+	query := `
+	UPDATE topics
+	SET deleted_at = NULL
+	WHERE id = ? AND deleted_at IS NOT NULL`
+
+	stmt, err := r.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("prepare failed: %w", err)
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, topicID)
+	if err != nil {
+		return fmt.Errorf("failed to restore topic: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("deleted topic with ID %d not found: %w", topicID, ErrTopicNotFound)
+	}
+
+	return nil
+}
+
+// PublishTopic makes the user's draft visible to everyone. Topics that are
+// not the user's draft are reported as not found.
+func (r Repo) PublishTopic(ctx context.Context, userID string, topicID int) error {
+	query := `
+	UPDATE topics
+	SET status = 'published'
+	WHERE id = ? AND user_id = ? AND status = 'draft' AND deleted_at IS NULL`
+
+	stmt, err := r.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("prepare failed: %w", err)
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, topicID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to publish topic: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("draft with ID %d not found for user: %w", topicID, ErrTopicNotFound)
+	}
+
+	return nil
+}
+
 func (r Repo) GetTopicByID(ctx context.Context, topicID int, userID *string) (*topic.Topic, error) {
 	query := `
 	SELECT
-		t.id, t.user_id, t.title, t.content, t.image_path, t.created_at, t.updated_at,
+		t.id, t.user_id, t.title, t.slug, t.content, t.summary, t.status, t.image_path, t.created_at, t.updated_at,
+		` + topicEdited + ` as is_edited,
+		COALESCE(t.canonical_category_id, 0) as canonical_category_id,
+		t.is_question, COALESCE(t.accepted_comment_id, 0) as accepted_comment_id,
+		t.view_count,
 		u.username,
 		GROUP_CONCAT(DISTINCT c.id) as category_ids,
 		GROUP_CONCAT(DISTINCT c.name) as category_names,
 		GROUP_CONCAT(DISTINCT c.color) as category_colors,
-		COALESCE(vote_counts.upvotes, 0) as upvote_count,
-		COALESCE(vote_counts.downvotes, 0) as downvote_count,
-		COALESCE(vote_counts.score, 0) as vote_score`
+		t.upvote_count,
+		t.downvote_count,
+		t.upvote_count - t.downvote_count as vote_score`
 
 	if userID != nil {
 		query += `,
@@ -200,17 +327,7 @@ func (r Repo) GetTopicByID(ctx context.Context, topicID int, userID *string) (*t
 	FROM topics t
 	LEFT JOIN users u ON t.user_id = u.id
 	LEFT JOIN topic_categories tc ON t.id = tc.topic_id
-	LEFT JOIN categories c ON tc.category_id = c.id
-	LEFT JOIN (
-		SELECT
-			topic_id,
-			COUNT(CASE WHEN reaction_type = 1 THEN 1 END) as upvotes,
-			COUNT(CASE WHEN reaction_type = -1 THEN 1 END) as downvotes,
-			(COUNT(CASE WHEN reaction_type = 1 THEN 1 END) - COUNT(CASE WHEN reaction_type = -1 THEN 1 END)) as score
-			FROM votes
-			WHERE comment_id IS NULL
-			GROUP BY topic_id
-	) vote_counts ON t.id = vote_counts.topic_id`
+	LEFT JOIN categories c ON tc.category_id = c.id`
 
 	if userID != nil {
 		query += `
@@ -219,8 +336,14 @@ func (r Repo) GetTopicByID(ctx context.Context, topicID int, userID *string) (*t
 			AND user_vote.comment_id IS NULL`
 	}
 
-	query += ` WHERE t.id = ?`
-	query += ` GROUP BY t.id, t.user_id, t.title, t.content, t.image_path, t.created_at, t.updated_at, u.username, vote_counts.upvotes, vote_counts.downvotes, vote_counts.score`
+	// Drafts are only found for their author.
+	query += ` WHERE t.id = ? AND t.deleted_at IS NULL`
+	if userID != nil {
+		query += ` AND (t.status = 'published' OR t.user_id = ?)`
+	} else {
+		query += ` AND t.status = 'published'`
+	}
+	query += ` GROUP BY t.id, t.user_id, t.title, t.content, t.image_path, t.created_at, t.updated_at, u.username`
 
 	if userID != nil {
 		query += `, user_vote.reaction_type`
@@ -232,6 +355,9 @@ func (r Repo) GetTopicByID(ctx context.Context, topicID int, userID *string) (*t
 	}
 
 	args = append(args, topicID)
+	if userID != nil {
+		args = append(args, *userID)
+	}
 
 	var topicResult topic.Topic
 	var userVote sql.NullInt32
@@ -241,10 +367,18 @@ func (r Repo) GetTopicByID(ctx context.Context, topicID int, userID *string) (*t
 		&topicResult.ID,
 		&topicResult.UserID,
 		&topicResult.Title,
+		&topicResult.Slug,
 		&topicResult.Content,
+		&topicResult.Summary,
+		&topicResult.Status,
 		&topicResult.ImagePath,
 		&topicResult.CreatedAt,
 		&topicResult.UpdatedAt,
+		&topicResult.IsEdited,
+		&topicResult.CanonicalCategoryID,
+		&topicResult.IsQuestion,
+		&topicResult.AcceptedCommentID,
+		&topicResult.ViewCount,
 		&topicResult.OwnerUsername,
 		&categoryIDs,
 		&categoryNames,
@@ -311,7 +445,7 @@ func (r Repo) GetTotalTopicsCount(ctx context.Context, filter string, categoryID
 	}
 
 	countQuery += `
-    WHERE 1=1`
+    WHERE t.deleted_at IS NULL AND t.status = 'published'`
 
 	if filter != "" {
 		countQuery += " AND (t.title LIKE ? OR t.content LIKE ?)"
@@ -333,19 +467,179 @@ func (r Repo) GetTotalTopicsCount(ctx context.Context, filter string, categoryID
 	return totalCount, nil
 }
 
-func (r Repo) GetAllTopics(ctx context.Context, page, size, categoryID int, orderBy, order, filter string, userID *string) ([]topic.Topic, error) {
+// GetCategoriesRequiringImage returns the names of the given categories that
+// only accept topics with an image.
+func (r Repo) GetCategoriesRequiringImage(ctx context.Context, categoryIDs []int) ([]string, error) {
+	if len(categoryIDs) == 0 {
+		return []string{}, nil
+	}
+
+	placeholders := make([]string, len(categoryIDs))
+	args := make([]interface{}, len(categoryIDs))
+	for i, id := range categoryIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	query := `
+	SELECT name
+	FROM categories
+	WHERE requires_image = 1 AND id IN (` + strings.Join(placeholders, ",") + `)`
+
+	stmt, err := r.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("prepare failed: %w", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query categories: %w", err)
+	}
+	defer rows.Close()
+
+	names := make([]string, 0)
+	for rows.Next() {
+		var name string
+		err = rows.Scan(&name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+		names = append(names, name)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("error iterating categories: %w", err)
+	}
+
+	return names, nil
+}
+
+// GetExistingCategoryIDs returns the subset of the given ids that belong to an
+// existing category.
+func (r Repo) GetExistingCategoryIDs(ctx context.Context, categoryIDs []int) ([]int, error) {
+	if len(categoryIDs) == 0 {
+		return []int{}, nil
+	}
+
+	placeholders := make([]string, len(categoryIDs))
+	args := make([]interface{}, len(categoryIDs))
+	for i, id := range categoryIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	query := `
+	SELECT id
+	FROM categories
+	WHERE archived = 0 AND id IN (` + strings.Join(placeholders, ",") + `)`
+
+	stmt, err := r.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("prepare failed: %w", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query categories: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]int, 0, len(categoryIDs))
+	for rows.Next() {
+		var id int
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("error iterating categories: %w", err)
+	}
+
+	return ids, nil
+}
+
+// IsTopicRemoved reports whether the topic is soft-deleted and was visible to
+// userID before that: published, or a draft of their own. Drafts hidden from
+// userID stay unknown to them even once deleted. A nil userID is a guest.
+func (r Repo) IsTopicRemoved(ctx context.Context, topicID int, userID *string) (bool, error) {
+	query := `
+	SELECT EXISTS (
+		SELECT 1 FROM topics
+		WHERE id = ? AND deleted_at IS NOT NULL
+			AND (status = 'published' OR user_id = ?)
+	)`
+
+	var removed bool
+	err := r.DB.QueryRowContext(ctx, query, topicID, userID).Scan(&removed)
+	if err != nil {
+		return false, fmt.Errorf("failed to check topic removal: %w", err)
+	}
+
+	return removed, nil
+}
+
+// IsTopicVisible reports whether userID may see the topic: it is not deleted
+// and is either published or a draft of their own. A nil userID is a guest.
+func (r Repo) IsTopicVisible(ctx context.Context, topicID int, userID *string) (bool, error) {
+	query := `
+	SELECT EXISTS (
+		SELECT 1 FROM topics
+		WHERE id = ? AND deleted_at IS NULL
+			AND (status = 'published' OR user_id = ?)
+	)`
+
+	var visible bool
+	err := r.DB.QueryRowContext(ctx, query, topicID, userID).Scan(&visible)
+	if err != nil {
+		return false, fmt.Errorf("failed to check topic visibility: %w", err)
+	}
+
+	return visible, nil
+}
+
+// sortDirections maps the accepted order values to their SQL keyword.
+var sortDirections = map[string]string{
+	"asc":  "ASC",
+	"desc": "DESC",
+}
+
+// approvedCommentCount and topicAgeHours are ORDER BY terms for a topic t.
+const (
+	approvedCommentCount = "(SELECT COUNT(*) FROM comments cm WHERE cm.topic_id = t.id AND cm.status = 'approved')"
+	topicAgeHours        = "MAX((julianday('now') - julianday(t.created_at)) * 24, 0)"
+)
+
+// topicEdited flags a topic t updated more than a minute after it was
+// posted, which leaves out the equal timestamps of a new row.
+const topicEdited = "COALESCE((julianday(t.updated_at) - julianday(t.created_at)) * 86400 > 60, 0)"
+
+// topicListSelect is the column list and joins shared by topic listings.
+// Approved comments are counted for all listed topics in one grouped join.
+// With withUserVote set it also selects the viewer's vote, whose user id must
+// be the first query argument.
+func topicListSelect(withUserVote bool) string {
 	query := `
     SELECT 
-        t.id, t.user_id, t.title, t.content, t.image_path, t.created_at, t.updated_at,
+        t.id, t.user_id, t.title, t.slug, t.content, t.summary, t.status, t.image_path, t.created_at, t.updated_at,
+        ` + topicEdited + ` as is_edited,
+        COALESCE(t.canonical_category_id, 0) as canonical_category_id,
         u.username,
         GROUP_CONCAT(DISTINCT c.id) as category_ids,
         GROUP_CONCAT(DISTINCT c.name) as category_names,
         GROUP_CONCAT(DISTINCT c.color) as category_colors,
-        COALESCE(vote_counts.upvotes, 0) as upvote_count,
-        COALESCE(vote_counts.downvotes, 0) as downvote_count,
-        COALESCE(vote_counts.score, 0) as vote_score`
+        t.upvote_count,
+        t.downvote_count,
+        t.upvote_count - t.downvote_count as vote_score,
+        COALESCE(cc.comment_count, 0) as comment_count`
 
-	if userID != nil {
+	if withUserVote {
 		query += `,
         user_votes.reaction_type as user_vote`
 	}
@@ -356,24 +650,35 @@ func (r Repo) GetAllTopics(ctx context.Context, page, size, categoryID int, orde
     LEFT JOIN topic_categories tc ON t.id = tc.topic_id
     LEFT JOIN categories c ON tc.category_id = c.id
     LEFT JOIN (
-        SELECT
-            topic_id,
-            COUNT(CASE WHEN reaction_type = 1 THEN 1 END) as upvotes,
-            COUNT(CASE WHEN reaction_type = -1 THEN 1 END) as downvotes,
-            (COUNT(CASE WHEN reaction_type = 1 THEN 1 END) - COUNT(CASE WHEN reaction_type = -1 THEN 1 END)) as score
-            FROM votes
-            WHERE comment_id IS NULL
-            GROUP BY topic_id
-        ) vote_counts ON t.id = vote_counts.topic_id`
+        SELECT topic_id, COUNT(*) as comment_count
+        FROM comments
+        WHERE status = 'approved'
+        GROUP BY topic_id
+    ) cc ON cc.topic_id = t.id`
 
-	if userID != nil {
+	if withUserVote {
 		query += `
         LEFT JOIN votes user_votes ON t.id = user_votes.topic_id
             AND user_votes.user_id = ?
             AND user_votes.comment_id IS NULL`
 	}
 
-	query += ` WHERE 1=1`
+	return query
+}
+
+// topicListGroupBy groups the rows of topicListSelect; GROUP BY is essential
+// when using GROUP_CONCAT.
+func topicListGroupBy(withUserVote bool) string {
+	groupBy := " GROUP BY t.id, t.user_id, t.title, t.content, t.image_path, t.created_at, t.updated_at, u.username, cc.comment_count"
+	if withUserVote {
+		groupBy += ", user_votes.reaction_type"
+	}
+	return groupBy
+}
+
+func (r Repo) GetAllTopics(ctx context.Context, page, size, beforeID, categoryID int, orderBy, order, filter string, userID *string, controversy topic.ControversyWeights) ([]topic.Topic, error) {
+	query := topicListSelect(userID != nil)
+	query += ` WHERE t.deleted_at IS NULL AND t.status = 'published'`
 
 	args := make([]interface{}, 0)
 
@@ -392,23 +697,74 @@ func (r Repo) GetAllTopics(ctx context.Context, page, size, categoryID int, orde
 		args = append(args, categoryID)
 	}
 
-	// GROUP BY is essential when using GROUP_CONCAT
-	query += " GROUP BY t.id, t.user_id, t.title, t.content, t.image_path, t.created_at, t.updated_at, u.username, vote_counts.upvotes, vote_counts.downvotes, vote_counts.score"
-
-	if userID != nil {
-		query += ", user_votes.reaction_type"
-	}
+	// A cursor replaces the offset: the page starts just below the last id
+	// the caller has already seen.
+	if beforeID > 0 {
+		query += " AND t.id < ?"
+		args = append(args, beforeID)
+		page = 1
+	}
+
+	query += topicListGroupBy(userID != nil)
+
+	// orderBy and order are spliced into the SQL, so anything not listed
+	// here is refused rather than passed through.
+	direction, ok := sortDirections[strings.ToLower(order)]
+	if !ok {
+		return nil, ErrInvalidOrder
+	}
+
+	var orderByClause string
+	switch orderBy {
+	case "created_at", "updated_at", "title", "upvote_count":
+		orderByClause = "t." + orderBy
+	case "vote_score":
+		orderByClause = "(t.upvote_count - t.downvote_count)"
+	case "bumped_at":
+		orderByClause = "COALESCE(t.bumped_at, t.created_at)"
+	case "comment_count":
+		orderByClause = approvedCommentCount
+	case "trending":
+		// Net votes plus comments, decayed by age in hours. The bundled
+		// SQLite has no pow(), so the gravity is a fixed square.
+		orderByClause = `(t.upvote_count - t.downvote_count + ` + approvedCommentCount + `) * 1.0
+            / ((` + topicAgeHours + ` + 2) * (` + topicAgeHours + ` + 2))`
+	case "controversy":
+		// The opposing-vote count scaled by how close the split is to even;
+		// one-sided or barely voted topics score zero.
+		orderByClause = `CASE
+            WHEN t.upvote_count > 0 AND t.downvote_count > 0
+                AND t.upvote_count + t.downvote_count >= ?
+            THEN MIN(t.upvote_count, t.downvote_count)
+                * ((1.0 - ?) + ? * MIN(t.upvote_count, t.downvote_count) * 1.0
+                    / MAX(t.upvote_count, t.downvote_count))
+            ELSE 0
+        END`
+		args = append(args, controversy.MinVotes, controversy.BalanceWeight, controversy.BalanceWeight)
+	default:
+		return nil, ErrInvalidOrderBy
+	}
+
+	query += " ORDER BY " + orderByClause + " " + direction + ", t.id " + direction + " LIMIT ? OFFSET ?"
+	offset := (page - 1) * size
+	args = append(args, size, offset)
 
-	orderByClause := "t." + orderBy
+	return r.queryTopicList(ctx, query, userID != nil, args...)
+}
 
-	if orderBy == "vote_score" {
-		orderByClause = "vote_counts.score"
-	}
+// GetDraftTopics lists the user's unpublished topics, most recently edited
+// first.
+func (r Repo) GetDraftTopics(ctx context.Context, userID string) ([]topic.Topic, error) {
+	query := topicListSelect(true) + `
+    WHERE t.deleted_at IS NULL AND t.status = 'draft' AND t.user_id = ?` +
+		topicListGroupBy(true) + `
+    ORDER BY t.updated_at DESC, t.id DESC`
 
-	query += " ORDER BY " + orderByClause + " " + order + " LIMIT ? OFFSET ?"
-	offset := (page - 1) * size
-	args = append(args, size, offset)
+	return r.queryTopicList(ctx, query, true, userID, userID)
+}
 
+// queryTopicList runs a query built on topicListSelect and scans its rows.
+func (r Repo) queryTopicList(ctx context.Context, query string, withUserVote bool, args ...interface{}) ([]topic.Topic, error) {
 	stmt, err := r.DB.PrepareContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("prepare failed: %w", err)
@@ -431,10 +787,15 @@ func (r Repo) GetAllTopics(ctx context.Context, page, size, categoryID int, orde
 			&topic.ID,
 			&topic.UserID,
 			&topic.Title,
+			&topic.Slug,
 			&topic.Content,
+			&topic.Summary,
+			&topic.Status,
 			&topic.ImagePath,
 			&topic.CreatedAt,
 			&topic.UpdatedAt,
+			&topic.IsEdited,
+			&topic.CanonicalCategoryID,
 			&topic.OwnerUsername,
 			&categoryIDs,
 			&categoryNames,
@@ -442,9 +803,10 @@ func (r Repo) GetAllTopics(ctx context.Context, page, size, categoryID int, orde
 			&topic.UpvoteCount,
 			&topic.DownvoteCount,
 			&topic.VoteScore,
+			&topic.CommentCount,
 		}
 
-		if userID != nil {
+		if withUserVote {
 			scanFields = append(scanFields, &userVote)
 		}
 
@@ -470,7 +832,7 @@ func (r Repo) GetAllTopics(ctx context.Context, page, size, categoryID int, orde
 			}
 		}
 
-		if userID != nil && userVote.Valid {
+		if withUserVote && userVote.Valid {
 			vote := int(userVote.Int32)
 			topic.UserVote = &vote
 		}
@@ -521,10 +883,13 @@ func parseCategoryData(t *topic.Topic, categoryIDs, categoryNames, categoryColor
 
 // syncTopicCategories handles all category synchronization logic.
 func (r Repo) syncTopicCategories(ctx context.Context, tx *sql.Tx, topicID int, newCategoryIDs []int) error {
-	// Get existing categories
+	// Get existing categories. Archived ones are not offered on the edit
+	// form, so they are left alone rather than dropped.
 	existingCategoryIDs := make([]int, 0)
 	rows, err := tx.QueryContext(ctx,
-		"SELECT category_id FROM topic_categories WHERE topic_id = ?",
+		`SELECT tc.category_id FROM topic_categories tc
+		JOIN categories c ON c.id = tc.category_id
+		WHERE tc.topic_id = ? AND c.archived = 0`,
 		topicID)
 	if err != nil {
 		return fmt.Errorf("failed to get existing categories: %w", err)
@@ -571,7 +936,7 @@ func (r Repo) syncTopicCategories(ctx context.Context, tx *sql.Tx, topicID int,
 	// Insert new categories
 	if len(newCategoryIDs) > 0 {
 		insertStmt, err := tx.PrepareContext(ctx,
-			"INSERT INTO topic_categories (topic_id, category_id) VALUES (?, ?)")
+			"INSERT OR IGNORE INTO topic_categories (topic_id, category_id) VALUES (?, ?)")
 		if err != nil {
 			return fmt.Errorf("failed to prepare insert statement: %w", err)
 		}
@@ -589,3 +954,70 @@ func (r Repo) syncTopicCategories(ctx context.Context, tx *sql.Tx, topicID int,
 
 	return nil
 }
+
+// SetAcceptedAnswer records the comment accepted as the answer to a question
+// topic. Topics that are not questions are reported as not found.
+func (r Repo) SetAcceptedAnswer(ctx context.Context, topicID, commentID int) error {
+	query := `
+	UPDATE topics
+	SET accepted_comment_id = ?
+	WHERE id = ? AND is_question = 1 AND deleted_at IS NULL`
+
+	stmt, err := r.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("prepare failed: %w", err)
+	}
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, commentID, topicID)
+	if err != nil {
+		return fmt.Errorf("failed to accept answer: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("question topic with ID %d not found: %w", topicID, ErrTopicNotFound)
+	}
+
+	return nil
+}
+
+// uniqueSlug returns base, suffixed with a number when another topic than
+// exceptID already uses it. An empty base stays empty.
+func (r Repo) uniqueSlug(ctx context.Context, tx *sql.Tx, base string, exceptID int) (string, error) {
+	if base == "" {
+		return "", nil
+	}
+
+	// Slugs only hold letters, digits and hyphens, so base needs no escaping
+	// inside the LIKE pattern.
+	rows, err := tx.QueryContext(ctx, `
+	SELECT slug FROM topics
+	WHERE (slug = ? OR slug LIKE ? || '-%') AND id != ?`,
+		base, base, exceptID)
+	if err != nil {
+		return "", fmt.Errorf("failed to query slugs: %w", err)
+	}
+	defer rows.Close()
+
+	taken := make([]string, 0)
+	for rows.Next() {
+		var slug string
+		err = rows.Scan(&slug)
+		if err != nil {
+			return "", fmt.Errorf("failed to scan slug: %w", err)
+		}
+		taken = append(taken, slug)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return "", fmt.Errorf("error iterating slugs: %w", err)
+	}
+
+	return helpers.UniqueSlug(base, taken), nil
+}
@@ -0,0 +1,798 @@
+package topics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"slices"
+	"strconv"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/arnald/forum/internal/domain/topic"
+	"github.com/arnald/forum/internal/infra/storage/sqlite/sqliteerrors"
+	"github.com/arnald/forum/internal/pkg/path"
+)
+
+// newTestRepo returns a repository backed by a private in-memory database
+// with the project schema applied.
+func newTestRepo(t *testing.T) Repo {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	// Every connection to :memory: gets its own database, so keep just one.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	schema, err := os.ReadFile(path.NewResolver().GetPath("db/migrations/schema.sql"))
+	if err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+	_, err = db.Exec(string(schema))
+	if err != nil {
+		t.Fatalf("failed to apply schema: %v", err)
+	}
+
+	return Repo{DB: db}
+}
+
+// seedVotedTopic inserts a topic with the given vote split, creating one voter
+// per vote.
+func seedVotedTopic(t *testing.T, db *sql.DB, title string, upvotes, downvotes int) {
+	t.Helper()
+
+	result, err := db.Exec(`INSERT INTO topics (user_id, title, content) VALUES ('author', ?, 'content')`, title)
+	if err != nil {
+		t.Fatalf("failed to insert topic: %v", err)
+	}
+	topicID, _ := result.LastInsertId()
+
+	for i := range upvotes + downvotes {
+		voterID := title + "-voter-" + strconv.Itoa(i)
+		_, err = db.Exec(`INSERT INTO users (id, email, username) VALUES (?, ?, ?)`,
+			voterID, voterID+"@example.com", voterID)
+		if err != nil {
+			t.Fatalf("failed to insert voter: %v", err)
+		}
+
+		reaction := 1
+		if i >= upvotes {
+			reaction = -1
+		}
+		_, err = db.Exec(`INSERT INTO votes (user_id, topic_id, reaction_type) VALUES (?, ?, ?)`,
+			voterID, topicID, reaction)
+		if err != nil {
+			t.Fatalf("failed to insert vote: %v", err)
+		}
+		_, err = db.Exec(`UPDATE topics SET upvote_count = upvote_count + ?, downvote_count = downvote_count + ? WHERE id = ?`,
+			max(reaction, 0), max(-reaction, 0), topicID)
+		if err != nil {
+			t.Fatalf("failed to count vote: %v", err)
+		}
+	}
+}
+
+func TestRepo_GetAllTopics_Controversy(t *testing.T) {
+	repo := newTestRepo(t)
+
+	_, err := repo.DB.Exec(`INSERT INTO users (id, email, username) VALUES ('author', 'author@example.com', 'author')`)
+	if err != nil {
+		t.Fatalf("failed to insert author: %v", err)
+	}
+
+	seedVotedTopic(t, repo.DB, "lopsided", 18, 2)
+	seedVotedTopic(t, repo.DB, "split", 10, 10)
+	seedVotedTopic(t, repo.DB, "barely voted", 1, 1)
+
+	weights := topic.ControversyWeights{MinVotes: 4, BalanceWeight: 1}
+	got, err := repo.GetAllTopics(context.Background(), 1, 10, 0, 0, "controversy", "desc", "", nil, weights)
+	if err != nil {
+		t.Fatalf("GetAllTopics() error = %v", err)
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("GetAllTopics() returned %d topics, want 3", len(got))
+	}
+	if got[0].Title != "split" || got[1].Title != "lopsided" {
+		t.Errorf("GetAllTopics() order = [%s, %s, %s], want split before lopsided",
+			got[0].Title, got[1].Title, got[2].Title)
+	}
+	if got[2].Title != "barely voted" {
+		t.Errorf("GetAllTopics() last = %s, want the topic below MinVotes", got[2].Title)
+	}
+}
+
+func TestRepo_GetAllTopics_RejectsUnknownOrdering(t *testing.T) {
+	repo := newTestRepo(t)
+
+	_, err := repo.DB.Exec(`
+	INSERT INTO users (id, email, username) VALUES ('author', 'author@example.com', 'author');
+	INSERT INTO topics (user_id, title, content) VALUES ('author', 'kept', 'content');`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+
+	testCases := []struct {
+		wantErr error
+		name    string
+		orderBy string
+		order   string
+	}{
+		{name: "valid ordering", orderBy: "title", order: "asc"},
+		{name: "vote score", orderBy: "vote_score", order: "desc"},
+		{name: "injected order by", orderBy: "id; DROP TABLE topics; --", order: "desc", wantErr: ErrInvalidOrderBy},
+		{name: "unknown column", orderBy: "password_hash", order: "desc", wantErr: ErrInvalidOrderBy},
+		{name: "injected order", orderBy: "created_at", order: "desc; DROP TABLE topics; --", wantErr: ErrInvalidOrder},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := repo.GetAllTopics(context.Background(), 1, 10, 0, 0, tt.orderBy, tt.order, "", nil, topic.ControversyWeights{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetAllTopics() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	var count int
+	err = repo.DB.QueryRow(`SELECT COUNT(*) FROM topics`).Scan(&count)
+	if err != nil {
+		t.Fatalf("topics table is gone: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("topics count = %d, want 1", count)
+	}
+}
+
+func TestRepo_GetAllTopics_BeforeCursor(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	// Identical timestamps leave the id as the only thing ordering the pages.
+	_, err := repo.DB.Exec(`
+	INSERT INTO users (id, email, username) VALUES ('author', 'author@example.com', 'author');
+	INSERT INTO topics (id, user_id, title, content, created_at) VALUES
+		(1, 'author', 'one', 'content', '2024-01-01 10:00:00'),
+		(2, 'author', 'two', 'content', '2024-01-01 10:00:00'),
+		(3, 'author', 'three', 'content', '2024-01-01 10:00:00'),
+		(4, 'author', 'four', 'content', '2024-01-01 10:00:00'),
+		(5, 'author', 'five', 'content', '2024-01-01 10:00:00');`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+
+	pages := []struct {
+		name     string
+		wantIDs  []int
+		beforeID int
+	}{
+		{name: "first page", beforeID: 0, wantIDs: []int{5, 4}},
+		{name: "middle page", beforeID: 4, wantIDs: []int{3, 2}},
+		{name: "final page", beforeID: 2, wantIDs: []int{1}},
+	}
+
+	for _, p := range pages {
+		got, err := repo.GetAllTopics(ctx, 1, 2, p.beforeID, 0, "created_at", "desc", "", nil, topic.ControversyWeights{})
+		if err != nil {
+			t.Fatalf("%s: GetAllTopics() error = %v", p.name, err)
+		}
+
+		ids := make([]int, 0, len(got))
+		for _, tp := range got {
+			ids = append(ids, tp.ID)
+		}
+		if !slices.Equal(ids, p.wantIDs) {
+			t.Errorf("%s: GetAllTopics() ids = %v, want %v", p.name, ids, p.wantIDs)
+		}
+	}
+}
+
+func TestRepo_UpdateTopic_KeepsArchivedCategories(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.DB.Exec(`
+	INSERT INTO users (id, email, username) VALUES ('author', 'author@example.com', 'author');
+	INSERT INTO categories (id, name, created_by, archived) VALUES
+		(1, 'Open', 'author', 0), (2, 'Old', 'author', 1), (3, 'New', 'author', 0);
+	INSERT INTO topics (id, user_id, title, content) VALUES (1, 'author', 'Title', 'content');
+	INSERT INTO topic_categories (topic_id, category_id) VALUES (1, 1), (1, 2);`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+
+	// The edit form only offers open categories, so the archived one is absent.
+	err = repo.UpdateTopic(ctx, &topic.Topic{
+		ID:          1,
+		UserID:      "author",
+		Title:       "Title",
+		Content:     "content",
+		CategoryIDs: []int{3},
+	})
+	if err != nil {
+		t.Fatalf("UpdateTopic() error = %v", err)
+	}
+
+	got, err := repo.GetTopicByID(ctx, 1, nil)
+	if err != nil {
+		t.Fatalf("GetTopicByID() error = %v", err)
+	}
+	if len(got.CategoryIDs) != 2 || !slices.Contains(got.CategoryIDs, 2) || !slices.Contains(got.CategoryIDs, 3) {
+		t.Errorf("CategoryIDs = %v, want the archived 2 kept and 3 added", got.CategoryIDs)
+	}
+
+	existing, err := repo.GetExistingCategoryIDs(ctx, []int{1, 2})
+	if err != nil {
+		t.Fatalf("GetExistingCategoryIDs() error = %v", err)
+	}
+	if !slices.Equal(existing, []int{1}) {
+		t.Errorf("GetExistingCategoryIDs() = %v, want archived categories excluded", existing)
+	}
+}
+
+func TestRepo_SetAcceptedAnswer(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.DB.Exec(`INSERT INTO users (id, email, username) VALUES ('author', 'author@example.com', 'author')`)
+	if err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+
+	question := &topic.Topic{UserID: "author", Title: "How?", Content: "content", IsQuestion: true}
+	err = repo.CreateTopic(ctx, question)
+	if err != nil {
+		t.Fatalf("CreateTopic() error = %v", err)
+	}
+	_, err = repo.DB.Exec(`
+	INSERT INTO topics (id, user_id, title, content) VALUES (2, 'author', 'Chat', 'content');
+	INSERT INTO comments (id, user_id, topic_id, content) VALUES (5, 'author', 1, 'Like this.');`)
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	err = repo.SetAcceptedAnswer(ctx, 1, 5)
+	if err != nil {
+		t.Fatalf("SetAcceptedAnswer() error = %v", err)
+	}
+
+	got, err := repo.GetTopicByID(ctx, 1, nil)
+	if err != nil {
+		t.Fatalf("GetTopicByID() error = %v", err)
+	}
+	if !got.IsQuestion || got.AcceptedCommentID != 5 {
+		t.Errorf("GetTopicByID() IsQuestion = %v, AcceptedCommentID = %d, want true and 5", got.IsQuestion, got.AcceptedCommentID)
+	}
+
+	err = repo.SetAcceptedAnswer(ctx, 2, 5)
+	if !errors.Is(err, ErrTopicNotFound) {
+		t.Errorf("SetAcceptedAnswer() on a non-question error = %v, want %v", err, ErrTopicNotFound)
+	}
+}
+
+func TestRepo_DeleteTopic_SoftDeletesAndRestores(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.DB.Exec(`
+	INSERT INTO users (id, email, username) VALUES ('author', 'author@example.com', 'author');
+	INSERT INTO topics (id, user_id, title, content) VALUES (1, 'author', 'Kept', 'content'), (2, 'author', 'Removed', 'content');
+	INSERT INTO comments (id, user_id, topic_id, content) VALUES (1, 'author', 2, 'First'), (2, 'author', 2, 'Second');
+	INSERT INTO votes (user_id, topic_id, comment_id, reaction_type) VALUES ('author', 2, NULL, 1);
+	UPDATE topics SET upvote_count = 1 WHERE id = 2;`)
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	err = repo.DeleteTopic(ctx, "author", 2)
+	if err != nil {
+		t.Fatalf("DeleteTopic() error = %v", err)
+	}
+
+	_, err = repo.GetTopicByID(ctx, 2, nil)
+	if !errors.Is(err, ErrTopicNotFound) {
+		t.Errorf("GetTopicByID() on a deleted topic error = %v, want %v", err, ErrTopicNotFound)
+	}
+	listed, err := repo.GetAllTopics(ctx, 1, 10, 0, 0, "created_at", "DESC", "", nil, topic.ControversyWeights{})
+	if err != nil {
+		t.Fatalf("GetAllTopics() error = %v", err)
+	}
+	if len(listed) != 1 || listed[0].ID != 1 {
+		t.Errorf("GetAllTopics() = %d topics, want only topic 1", len(listed))
+	}
+	count, err := repo.GetTotalTopicsCount(ctx, "", 0)
+	if err != nil {
+		t.Fatalf("GetTotalTopicsCount() error = %v", err)
+	}
+	if count != 1 {
+		t.Errorf("GetTotalTopicsCount() = %d, want 1", count)
+	}
+	err = repo.DeleteTopic(ctx, "author", 2)
+	if !errors.Is(err, ErrTopicNotFound) {
+		t.Errorf("DeleteTopic() twice error = %v, want %v", err, ErrTopicNotFound)
+	}
+
+	err = repo.RestoreTopic(ctx, 2)
+	if err != nil {
+		t.Fatalf("RestoreTopic() error = %v", err)
+	}
+
+	restored, err := repo.GetTopicByID(ctx, 2, nil)
+	if err != nil {
+		t.Fatalf("GetTopicByID() after restore error = %v", err)
+	}
+	if restored.UpvoteCount != 1 {
+		t.Errorf("GetTopicByID() UpvoteCount = %d, want the vote kept", restored.UpvoteCount)
+	}
+	var comments int
+	err = repo.DB.QueryRow(`SELECT COUNT(*) FROM comments WHERE topic_id = 2`).Scan(&comments)
+	if err != nil {
+		t.Fatalf("failed to count comments: %v", err)
+	}
+	if comments != 2 {
+		t.Errorf("comments after restore = %d, want 2", comments)
+	}
+
+	err = repo.RestoreTopic(ctx, 1)
+	if !errors.Is(err, ErrTopicNotFound) {
+		t.Errorf("RestoreTopic() on a live topic error = %v, want %v", err, ErrTopicNotFound)
+	}
+}
+
+func TestRepo_CreateTopic_MissingCategory(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.DB.Exec(`
+	INSERT INTO users (id, email, username) VALUES ('author', 'author@example.com', 'author');
+	INSERT INTO categories (id, name, created_by) VALUES (1, 'go', 'author');`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+
+	testCases := []struct {
+		wantErr     error
+		name        string
+		userID      string
+		categoryIDs []int
+		canonical   int
+	}{
+		{
+			name:        "missing canonical category",
+			userID:      "author",
+			categoryIDs: []int{99},
+			canonical:   99,
+			wantErr:     ErrCategoryNotFound,
+		},
+		{
+			name:        "missing secondary category",
+			userID:      "author",
+			categoryIDs: []int{1, 99},
+			canonical:   1,
+			wantErr:     ErrCategoryNotFound,
+		},
+		{
+			name:        "missing author is not a missing category",
+			userID:      "nobody",
+			categoryIDs: []int{1},
+			canonical:   1,
+			wantErr:     sqliteerrors.ErrInvalidReference,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := repo.CreateTopic(ctx, &topic.Topic{
+				UserID:              tt.userID,
+				Title:               tt.name,
+				Slug:                "topic",
+				Content:             "content",
+				CategoryIDs:         tt.categoryIDs,
+				CanonicalCategoryID: tt.canonical,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CreateTopic() error = %v, want %v", err, tt.wantErr)
+			}
+			if !errors.Is(tt.wantErr, ErrCategoryNotFound) && errors.Is(err, ErrCategoryNotFound) {
+				t.Errorf("CreateTopic() error = %v, must not report a missing category", err)
+			}
+		})
+	}
+
+	var count int
+	err = repo.DB.QueryRow(`SELECT COUNT(*) FROM topics`).Scan(&count)
+	if err != nil {
+		t.Fatalf("failed to count topics: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("topics count = %d, want the failed creates rolled back", count)
+	}
+}
+
+func TestRepo_TopicSlugs(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.DB.Exec(`INSERT INTO users (id, email, username) VALUES ('author', 'author@example.com', 'author');`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+
+	first := &topic.Topic{UserID: "author", Title: "Hello World", Slug: "hello-world", Content: "content"}
+	second := &topic.Topic{UserID: "author", Title: "Hello, world!", Slug: "hello-world", Content: "content"}
+	for _, tp := range []*topic.Topic{first, second} {
+		err = repo.CreateTopic(ctx, tp)
+		if err != nil {
+			t.Fatalf("CreateTopic() error = %v", err)
+		}
+	}
+	if first.Slug != "hello-world" || second.Slug != "hello-world-2" {
+		t.Errorf("slugs = %q, %q, want hello-world, hello-world-2", first.Slug, second.Slug)
+	}
+
+	// Saving a topic under its own slug must not count as a collision.
+	err = repo.UpdateTopic(ctx, &topic.Topic{ID: 1, UserID: "author", Title: "Hello World", Slug: "hello-world", Content: "content"})
+	if err != nil {
+		t.Fatalf("UpdateTopic() error = %v", err)
+	}
+
+	got, err := repo.GetTopicByID(ctx, 1, nil)
+	if err != nil {
+		t.Fatalf("GetTopicByID() error = %v", err)
+	}
+	if got.Slug != "hello-world" {
+		t.Errorf("Slug after an unchanged save = %q, want hello-world", got.Slug)
+	}
+}
+
+func TestRepo_UserVote(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.DB.Exec(`
+	INSERT INTO users (id, email, username) VALUES
+		('author', 'author@example.com', 'author'),
+		('viewer', 'viewer@example.com', 'viewer');
+	INSERT INTO topics (id, user_id, title, content) VALUES
+		(1, 'author', 'liked', 'content'),
+		(2, 'author', 'disliked', 'content'),
+		(3, 'author', 'unvoted', 'content');
+	INSERT INTO votes (user_id, topic_id, reaction_type) VALUES
+		('viewer', 1, 1), ('viewer', 2, -1), ('author', 3, 1);`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+
+	viewer := "viewer"
+	liked, disliked := 1, -1
+	want := map[int]*int{1: &liked, 2: &disliked, 3: nil}
+
+	listed, err := repo.GetAllTopics(ctx, 1, 10, 0, 0, "created_at", "desc", "", &viewer, topic.ControversyWeights{})
+	if err != nil {
+		t.Fatalf("GetAllTopics() error = %v", err)
+	}
+	if len(listed) != len(want) {
+		t.Fatalf("GetAllTopics() returned %d topics, want %d", len(listed), len(want))
+	}
+	for _, tp := range listed {
+		assertUserVote(t, "GetAllTopics()", tp, want[tp.ID])
+	}
+
+	for id, vote := range want {
+		got, getErr := repo.GetTopicByID(ctx, id, &viewer)
+		if getErr != nil {
+			t.Fatalf("GetTopicByID(%d) error = %v", id, getErr)
+		}
+		assertUserVote(t, "GetTopicByID()", *got, vote)
+	}
+
+	guest, err := repo.GetTopicByID(ctx, 1, nil)
+	if err != nil {
+		t.Fatalf("GetTopicByID() for a guest error = %v", err)
+	}
+	assertUserVote(t, "GetTopicByID() for a guest", *guest, nil)
+}
+
+func assertUserVote(t *testing.T, call string, got topic.Topic, want *int) {
+	t.Helper()
+
+	switch {
+	case want == nil && got.UserVote != nil:
+		t.Errorf("%s topic %d UserVote = %d, want nil", call, got.ID, *got.UserVote)
+	case want != nil && (got.UserVote == nil || *got.UserVote != *want):
+		t.Errorf("%s topic %d UserVote = %v, want %d", call, got.ID, got.UserVote, *want)
+	}
+}
+
+func TestRepo_GetAllTopics_LikesAndComments(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	// Topic 1 is the most liked, topic 2 the most commented once the pending
+	// comment on topic 3 is left out.
+	_, err := repo.DB.Exec(`
+	INSERT INTO users (id, email, username) VALUES ('author', 'author@example.com', 'author');
+	INSERT INTO topics (id, user_id, title, content, upvote_count) VALUES
+		(1, 'author', 'liked', 'content', 5),
+		(2, 'author', 'busy', 'content', 1),
+		(3, 'author', 'quiet', 'content', 0);
+	INSERT INTO comments (user_id, topic_id, content, status) VALUES
+		('author', 2, 'one', 'approved'),
+		('author', 2, 'two', 'approved'),
+		('author', 3, 'three', 'approved'),
+		('author', 3, 'held', 'pending'),
+		('author', 3, 'held again', 'pending');`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+
+	orders := []struct {
+		orderBy string
+		wantIDs []int
+	}{
+		{orderBy: "upvote_count", wantIDs: []int{1, 2, 3}},
+		{orderBy: "comment_count", wantIDs: []int{2, 3, 1}},
+	}
+
+	for _, o := range orders {
+		got, listErr := repo.GetAllTopics(ctx, 1, 10, 0, 0, o.orderBy, "desc", "", nil, topic.ControversyWeights{})
+		if listErr != nil {
+			t.Fatalf("GetAllTopics(%s) error = %v", o.orderBy, listErr)
+		}
+
+		ids := make([]int, 0, len(got))
+		for _, tp := range got {
+			ids = append(ids, tp.ID)
+		}
+		if !slices.Equal(ids, o.wantIDs) {
+			t.Errorf("GetAllTopics(%s) ids = %v, want %v", o.orderBy, ids, o.wantIDs)
+		}
+	}
+}
+
+func TestRepo_GetAllTopics_CommentCount(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	// Topic 1 sits in two categories so a count taken across the category
+	// join would double; its pending comment is not counted.
+	_, err := repo.DB.Exec(`
+	INSERT INTO users (id, email, username) VALUES ('author', 'author@example.com', 'author');
+	INSERT INTO categories (id, name, created_by) VALUES (1, 'go', 'author'), (2, 'sql', 'author');
+	INSERT INTO topics (id, user_id, title, content) VALUES
+		(1, 'author', 'discussed', 'content'),
+		(2, 'author', 'silent', 'content');
+	INSERT INTO topic_categories (topic_id, category_id) VALUES (1, 1), (1, 2);
+	INSERT INTO comments (user_id, topic_id, content, status) VALUES
+		('author', 1, 'one', 'approved'),
+		('author', 1, 'two', 'approved'),
+		('author', 1, 'three', 'approved'),
+		('author', 1, 'held', 'pending');`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+
+	want := map[int]int{1: 3, 2: 0}
+	viewer := "author"
+	for _, userID := range []*string{nil, &viewer} {
+		got, listErr := repo.GetAllTopics(ctx, 1, 10, 0, 0, "created_at", "desc", "", userID, topic.ControversyWeights{})
+		if listErr != nil {
+			t.Fatalf("GetAllTopics() error = %v", listErr)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("GetAllTopics() returned %d topics, want %d", len(got), len(want))
+		}
+		for _, tp := range got {
+			if tp.CommentCount != want[tp.ID] {
+				t.Errorf("topic %d CommentCount = %d, want %d", tp.ID, tp.CommentCount, want[tp.ID])
+			}
+		}
+	}
+}
+
+func TestRepo_GetAllTopics_Trending(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	// Three days old, topic 1 needs far more than topic 2's votes and
+	// comment to stay ahead; the fresh, unvoted topic 3 scores zero.
+	_, err := repo.DB.Exec(`
+	INSERT INTO users (id, email, username) VALUES ('author', 'author@example.com', 'author');
+	INSERT INTO topics (id, user_id, title, content, upvote_count, created_at) VALUES
+		(1, 'author', 'old favourite', 'content', 40, datetime('now', '-72 hours')),
+		(2, 'author', 'new and liked', 'content', 4, datetime('now', '-2 hours')),
+		(3, 'author', 'brand new', 'content', 0, datetime('now'));
+	INSERT INTO comments (user_id, topic_id, content) VALUES ('author', 2, 'first');`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+
+	got, err := repo.GetAllTopics(ctx, 1, 10, 0, 0, "trending", "desc", "", nil, topic.ControversyWeights{})
+	if err != nil {
+		t.Fatalf("GetAllTopics() error = %v", err)
+	}
+
+	ids := make([]int, 0, len(got))
+	for _, tp := range got {
+		ids = append(ids, tp.ID)
+	}
+	if want := []int{2, 1, 3}; !slices.Equal(ids, want) {
+		t.Errorf("GetAllTopics() trending ids = %v, want %v", ids, want)
+	}
+}
+
+func TestRepo_Drafts(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.DB.Exec(`
+	INSERT INTO users (id, email, username) VALUES
+		('author', 'author@example.com', 'author'),
+		('reader', 'reader@example.com', 'reader');`)
+	if err != nil {
+		t.Fatalf("failed to seed users: %v", err)
+	}
+	for _, created := range []*topic.Topic{
+		{UserID: "author", Title: "Published", Content: "content"},
+		{UserID: "author", Title: "Draft", Content: "content", Status: topic.StatusDraft},
+	} {
+		err = repo.CreateTopic(ctx, created)
+		if err != nil {
+			t.Fatalf("CreateTopic(%q) error = %v", created.Title, err)
+		}
+	}
+	const draftID = 2
+
+	author, reader := "author", "reader"
+	listed := func(viewer *string) []int {
+		t.Helper()
+		got, listErr := repo.GetAllTopics(ctx, 1, 10, 0, 0, "created_at", "desc", "", viewer, topic.ControversyWeights{})
+		if listErr != nil {
+			t.Fatalf("GetAllTopics() error = %v", listErr)
+		}
+		ids := make([]int, 0, len(got))
+		for _, tp := range got {
+			ids = append(ids, tp.ID)
+		}
+		return ids
+	}
+
+	for name, viewer := range map[string]*string{"guest": nil, "author": &author} {
+		if ids := listed(viewer); !slices.Equal(ids, []int{1}) {
+			t.Errorf("GetAllTopics() for %s = %v, want only the published topic", name, ids)
+		}
+	}
+	count, err := repo.GetTotalTopicsCount(ctx, "", 0)
+	if err != nil || count != 1 {
+		t.Errorf("GetTotalTopicsCount() = %d, %v, want 1", count, err)
+	}
+
+	got, err := repo.GetTopicByID(ctx, draftID, &author)
+	if err != nil {
+		t.Fatalf("GetTopicByID() for the author error = %v", err)
+	}
+	if got.Status != topic.StatusDraft {
+		t.Errorf("Status = %q, want %q", got.Status, topic.StatusDraft)
+	}
+	for name, viewer := range map[string]*string{"guest": nil, "reader": &reader} {
+		_, err = repo.GetTopicByID(ctx, draftID, viewer)
+		if !errors.Is(err, ErrTopicNotFound) {
+			t.Errorf("GetTopicByID() for %s error = %v, want %v", name, err, ErrTopicNotFound)
+		}
+	}
+
+	drafts, err := repo.GetDraftTopics(ctx, author)
+	if err != nil || len(drafts) != 1 || drafts[0].ID != draftID {
+		t.Errorf("GetDraftTopics(author) = %v, %v, want the draft", drafts, err)
+	}
+	drafts, err = repo.GetDraftTopics(ctx, reader)
+	if err != nil || len(drafts) != 0 {
+		t.Errorf("GetDraftTopics(reader) = %v, %v, want none", drafts, err)
+	}
+
+	err = repo.PublishTopic(ctx, reader, draftID)
+	if !errors.Is(err, ErrTopicNotFound) {
+		t.Errorf("PublishTopic() by another user error = %v, want %v", err, ErrTopicNotFound)
+	}
+	err = repo.PublishTopic(ctx, author, draftID)
+	if err != nil {
+		t.Fatalf("PublishTopic() error = %v", err)
+	}
+	if ids := listed(nil); !slices.Equal(ids, []int{2, 1}) {
+		t.Errorf("GetAllTopics() after publishing = %v, want [2 1]", ids)
+	}
+	err = repo.PublishTopic(ctx, author, draftID)
+	if !errors.Is(err, ErrTopicNotFound) {
+		t.Errorf("PublishTopic() twice error = %v, want %v", err, ErrTopicNotFound)
+	}
+}
+
+func TestRepo_IsTopicRemoved(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.DB.Exec(`
+	INSERT INTO users (id, email, username) VALUES ('author', 'author@example.com', 'author');
+	INSERT INTO topics (id, user_id, title, content, status, deleted_at) VALUES
+		(1, 'author', 'Published', 'content', 'published', NULL),
+		(2, 'author', 'Deleted', 'content', 'published', CURRENT_TIMESTAMP),
+		(3, 'author', 'Deleted draft', 'content', 'draft', CURRENT_TIMESTAMP),
+		(4, 'author', 'Draft', 'content', 'draft', NULL);`)
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	author, reader := "author", "reader"
+	testCases := []struct {
+		viewer  *string
+		name    string
+		topicID int
+		want    bool
+	}{
+		{name: "live topic", topicID: 1},
+		{name: "deleted topic for a guest", topicID: 2, want: true},
+		{name: "deleted draft for its author", viewer: &author, topicID: 3, want: true},
+		{name: "deleted draft for another user", viewer: &reader, topicID: 3},
+		{name: "live draft for another user", viewer: &reader, topicID: 4},
+		{name: "missing topic", topicID: 9},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, remErr := repo.IsTopicRemoved(ctx, tt.topicID, tt.viewer)
+			if remErr != nil {
+				t.Fatalf("IsTopicRemoved() error = %v", remErr)
+			}
+			if got != tt.want {
+				t.Errorf("IsTopicRemoved() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepo_IsTopicVisible(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.DB.Exec(`
+	INSERT INTO users (id, email, username) VALUES ('author', 'author@example.com', 'author');
+	INSERT INTO topics (id, user_id, title, content, status, deleted_at) VALUES
+		(1, 'author', 'Published', 'content', 'published', NULL),
+		(2, 'author', 'Draft', 'content', 'draft', NULL),
+		(3, 'author', 'Deleted', 'content', 'published', CURRENT_TIMESTAMP);`)
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	author, reader := "author", "reader"
+	testCases := []struct {
+		viewer  *string
+		name    string
+		topicID int
+		want    bool
+	}{
+		{name: "published for a guest", topicID: 1, want: true},
+		{name: "draft for its author", viewer: &author, topicID: 2, want: true},
+		{name: "draft for another user", viewer: &reader, topicID: 2},
+		{name: "draft for a guest", topicID: 2},
+		{name: "deleted for its author", viewer: &author, topicID: 3},
+		{name: "missing topic", topicID: 9},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, visErr := repo.IsTopicVisible(ctx, tt.topicID, tt.viewer)
+			if visErr != nil {
+				t.Fatalf("IsTopicVisible() error = %v", visErr)
+			}
+			if got != tt.want {
+				t.Errorf("IsTopicVisible() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
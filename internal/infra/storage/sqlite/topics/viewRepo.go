@@ -0,0 +1,59 @@
+package topics
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// IncrementTopicView counts a view of the topic unless the same viewer was
+// already counted within window, and reports whether it did.
+func (r Repo) IncrementTopicView(ctx context.Context, topicID int, viewerKey string, window time.Duration) (counted bool, err error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				err = fmt.Errorf("transaction rollback failed: %w (original error: %w)", rollbackErr, err)
+			}
+			return
+		}
+		err = tx.Commit()
+		if err != nil {
+			err = fmt.Errorf("transaction commit failed: %w", err)
+		}
+	}()
+
+	now := time.Now().UTC()
+	result, err := tx.ExecContext(ctx, `
+	INSERT INTO topic_views (topic_id, viewer_key, viewed_at)
+	SELECT id, ?, ? FROM topics WHERE id = ? AND deleted_at IS NULL
+	ON CONFLICT(topic_id, viewer_key) DO UPDATE SET viewed_at = excluded.viewed_at
+	WHERE topic_views.viewed_at <= ?`,
+		viewerKey,
+		now.Format(time.DateTime),
+		topicID,
+		now.Add(-window).Format(time.DateTime),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to record topic view: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE topics SET view_count = view_count + 1 WHERE id = ?`, topicID)
+	if err != nil {
+		return false, fmt.Errorf("failed to increment view count: %w", err)
+	}
+
+	return true, nil
+}
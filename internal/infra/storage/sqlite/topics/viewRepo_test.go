@@ -0,0 +1,54 @@
+package topics
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRepo_IncrementTopicView(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.DB.Exec(`
+	INSERT INTO users (id, email, username) VALUES ('author', 'author@example.com', 'author');
+	INSERT INTO topics (id, user_id, title, content) VALUES (1, 'author', 'Viewed', 'content');`)
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	views := []struct {
+		viewer      string
+		window      time.Duration
+		wantCounted bool
+	}{
+		{viewer: "user:alice", window: time.Hour, wantCounted: true},
+		{viewer: "user:alice", window: time.Hour, wantCounted: false},
+		{viewer: "user:alice", window: time.Hour, wantCounted: false},
+		{viewer: "ip:10.0.0.1", window: time.Hour, wantCounted: true},
+		// A zero window never debounces, like a view after the window ran out.
+		{viewer: "user:alice", window: 0, wantCounted: true},
+	}
+	for i, v := range views {
+		counted, viewErr := repo.IncrementTopicView(ctx, 1, v.viewer, v.window)
+		if viewErr != nil {
+			t.Fatalf("view %d: IncrementTopicView() error = %v", i, viewErr)
+		}
+		if counted != v.wantCounted {
+			t.Errorf("view %d by %s: IncrementTopicView() = %v, want %v", i, v.viewer, counted, v.wantCounted)
+		}
+	}
+
+	got, err := repo.GetTopicByID(ctx, 1, nil)
+	if err != nil {
+		t.Fatalf("GetTopicByID() error = %v", err)
+	}
+	if got.ViewCount != 3 {
+		t.Errorf("GetTopicByID() ViewCount = %d, want 3", got.ViewCount)
+	}
+
+	counted, err := repo.IncrementTopicView(ctx, 99, "user:alice", time.Hour)
+	if err != nil || counted {
+		t.Errorf("IncrementTopicView() on a missing topic = %v, %v, want false, nil", counted, err)
+	}
+}
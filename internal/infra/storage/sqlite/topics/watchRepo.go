@@ -0,0 +1,96 @@
+package topics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// WatchTopic subscribes the user to new comments on the topic. Watching a
+// topic twice is a no-op.
+func (r Repo) WatchTopic(ctx context.Context, userID string, topicID int) error {
+	var exists int
+	err := r.DB.QueryRowContext(ctx, "SELECT 1 FROM topics WHERE id = ? AND deleted_at IS NULL AND status = 'published'", topicID).Scan(&exists)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("topic with ID %d not found: %w", topicID, ErrTopicNotFound)
+		}
+		return fmt.Errorf("failed to query topic: %w", err)
+	}
+
+	query := `
+	INSERT OR IGNORE INTO thread_watches (user_id, topic_id)
+	VALUES (?, ?)`
+
+	stmt, err := r.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("prepare failed: %w", err)
+	}
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, userID, topicID)
+	if err != nil {
+		return fmt.Errorf("failed to watch topic: %w", err)
+	}
+
+	return nil
+}
+
+// UnwatchTopic removes the user's subscription, if there is one.
+func (r Repo) UnwatchTopic(ctx context.Context, userID string, topicID int) error {
+	query := `
+	DELETE FROM thread_watches
+	WHERE user_id = ? AND topic_id = ?`
+
+	stmt, err := r.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("prepare failed: %w", err)
+	}
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, userID, topicID)
+	if err != nil {
+		return fmt.Errorf("failed to unwatch topic: %w", err)
+	}
+
+	return nil
+}
+
+// GetTopicWatchers returns the ids of the users watching the topic.
+func (r Repo) GetTopicWatchers(ctx context.Context, topicID int) ([]string, error) {
+	query := `
+	SELECT user_id
+	FROM thread_watches
+	WHERE topic_id = ?
+	ORDER BY created_at ASC`
+
+	stmt, err := r.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("prepare failed: %w", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, topicID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query watchers: %w", err)
+	}
+	defer rows.Close()
+
+	watchers := make([]string, 0)
+	for rows.Next() {
+		var userID string
+		err = rows.Scan(&userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan watcher: %w", err)
+		}
+		watchers = append(watchers, userID)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("error iterating watchers: %w", err)
+	}
+
+	return watchers, nil
+}
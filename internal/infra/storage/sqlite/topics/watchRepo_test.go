@@ -0,0 +1,55 @@
+package topics
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestRepo_TopicWatches(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.DB.Exec(`
+	INSERT INTO users (id, email, username) VALUES
+		('author', 'author@example.com', 'author'),
+		('watcher', 'watcher@example.com', 'watcher');
+	INSERT INTO topics (id, user_id, title, content) VALUES (1, 'author', 'Topic', 'content');`)
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	watchers := func() []string {
+		t.Helper()
+		got, listErr := repo.GetTopicWatchers(ctx, 1)
+		if listErr != nil {
+			t.Fatalf("GetTopicWatchers() error = %v", listErr)
+		}
+		return got
+	}
+
+	// Watching twice must not fail or duplicate the watcher.
+	for range 2 {
+		err = repo.WatchTopic(ctx, "watcher", 1)
+		if err != nil {
+			t.Fatalf("WatchTopic() error = %v", err)
+		}
+	}
+	if got := watchers(); !slices.Equal(got, []string{"watcher"}) {
+		t.Errorf("GetTopicWatchers() = %v, want [watcher]", got)
+	}
+
+	err = repo.UnwatchTopic(ctx, "watcher", 1)
+	if err != nil {
+		t.Fatalf("UnwatchTopic() error = %v", err)
+	}
+	if got := watchers(); len(got) != 0 {
+		t.Errorf("GetTopicWatchers() after unwatch = %v, want none", got)
+	}
+
+	err = repo.WatchTopic(ctx, "watcher", 99)
+	if !errors.Is(err, ErrTopicNotFound) {
+		t.Errorf("WatchTopic() on a missing topic error = %v, want %v", err, ErrTopicNotFound)
+	}
+}
@@ -0,0 +1,84 @@
+package users
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// UpdateAvatar points the user's avatar at avatarURL, which no other user may
+// hold, and returns the URL it replaced so the caller can clean up the old
+// file. It returns an empty string if the user had no avatar or someone else
+// still shows the old one.
+func (r Repo) UpdateAvatar(ctx context.Context, userID, avatarURL string) (previous string, err error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				err = fmt.Errorf("transaction rollback failed: %w (original error: %w)", rollbackErr, err)
+			}
+			return
+		}
+		commitErr := tx.Commit()
+		if commitErr != nil {
+			err = fmt.Errorf("transaction commit failed: %w", commitErr)
+		}
+	}()
+
+	err = tx.QueryRowContext(ctx,
+		`SELECT COALESCE(avatar_url, '') FROM users WHERE id = ?`,
+		userID,
+	).Scan(&previous)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrUserNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get current avatar: %w", err)
+	}
+
+	var taken bool
+	err = tx.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM users WHERE avatar_url = ? AND id != ?)`,
+		avatarURL,
+		userID,
+	).Scan(&taken)
+	if err != nil {
+		return "", fmt.Errorf("failed to check avatar owner: %w", err)
+	}
+	if taken {
+		return "", ErrAvatarInUse
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE users SET avatar_url = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`,
+		avatarURL,
+		userID,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to update avatar: %w", err)
+	}
+
+	if previous == "" {
+		return "", nil
+	}
+
+	var shared bool
+	err = tx.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM users WHERE avatar_url = ?)`,
+		previous,
+	).Scan(&shared)
+	if err != nil {
+		return "", fmt.Errorf("failed to check previous avatar: %w", err)
+	}
+	if shared {
+		return "", nil
+	}
+
+	return previous, nil
+}
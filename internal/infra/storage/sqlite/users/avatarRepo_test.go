@@ -0,0 +1,89 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRepo_UpdateAvatar(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("returns the avatar it replaced", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		previous, err := repo.UpdateAvatar(ctx, "alice", "/static/images/uploads/avatars/first.png")
+		if err != nil {
+			t.Fatalf("UpdateAvatar() error = %v", err)
+		}
+		if previous != "" {
+			t.Errorf("UpdateAvatar() previous = %q, want empty for a user without an avatar", previous)
+		}
+
+		previous, err = repo.UpdateAvatar(ctx, "alice", "/static/images/uploads/avatars/second.png")
+		if err != nil {
+			t.Fatalf("UpdateAvatar() error = %v", err)
+		}
+		if previous != "/static/images/uploads/avatars/first.png" {
+			t.Errorf("UpdateAvatar() previous = %q, want the first avatar", previous)
+		}
+
+		var current string
+		err = repo.DB.QueryRow(`SELECT avatar_url FROM users WHERE id = 'alice'`).Scan(&current)
+		if err != nil {
+			t.Fatalf("failed to read avatar: %v", err)
+		}
+		if current != "/static/images/uploads/avatars/second.png" {
+			t.Errorf("avatar_url = %q, want the second avatar", current)
+		}
+	})
+
+	t.Run("another user's avatar cannot be claimed", func(t *testing.T) {
+		repo := newTestRepo(t)
+		_, err := repo.DB.Exec(`INSERT INTO users (id, email, username) VALUES ('bob', 'bob@example.com', 'bob')`)
+		if err != nil {
+			t.Fatalf("failed to seed bob: %v", err)
+		}
+
+		const alicesAvatar = "/static/images/uploads/avatars/alice_first.png"
+		_, err = repo.UpdateAvatar(ctx, "alice", alicesAvatar)
+		if err != nil {
+			t.Fatalf("UpdateAvatar() error = %v", err)
+		}
+
+		_, err = repo.UpdateAvatar(ctx, "bob", alicesAvatar)
+		if !errors.Is(err, ErrAvatarInUse) {
+			t.Errorf("UpdateAvatar() for bob error = %v, want ErrAvatarInUse", err)
+		}
+	})
+
+	t.Run("an avatar someone else shows is not handed back for removal", func(t *testing.T) {
+		repo := newTestRepo(t)
+		// Before avatars were tied to their uploader two accounts could end
+		// up sharing one.
+		_, err := repo.DB.Exec(`
+		INSERT INTO users (id, email, username, avatar_url) VALUES
+			('bob', 'bob@example.com', 'bob', '/static/images/uploads/avatars/shared.png');
+		UPDATE users SET avatar_url = '/static/images/uploads/avatars/shared.png' WHERE id = 'alice'`)
+		if err != nil {
+			t.Fatalf("failed to seed shared avatar: %v", err)
+		}
+
+		previous, err := repo.UpdateAvatar(ctx, "alice", "/static/images/uploads/avatars/alice_new.png")
+		if err != nil {
+			t.Fatalf("UpdateAvatar() error = %v", err)
+		}
+		if previous != "" {
+			t.Errorf("UpdateAvatar() previous = %q, want empty while bob still shows it", previous)
+		}
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		_, err := repo.UpdateAvatar(ctx, "nobody", "/static/images/uploads/avatars/x.png")
+		if !errors.Is(err, ErrUserNotFound) {
+			t.Errorf("UpdateAvatar() error = %v, want ErrUserNotFound", err)
+		}
+	})
+}
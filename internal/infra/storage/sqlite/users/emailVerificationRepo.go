@@ -0,0 +1,80 @@
+package users
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CreateEmailVerificationToken stores the hash of a freshly issued
+// verification token.
+func (r Repo) CreateEmailVerificationToken(ctx context.Context, userID, tokenHash string, expiresAt time.Time) error {
+	_, err := r.DB.ExecContext(ctx, `
+	INSERT INTO email_verification_tokens (token_hash, user_id, expires_at)
+	VALUES (?, ?, ?)`,
+		tokenHash,
+		userID,
+		expiresAt.UTC().Format(time.DateTime),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store email verification token: %w", err)
+	}
+
+	return nil
+}
+
+// ConsumeEmailVerificationToken marks the token owner's email as verified and
+// spends every outstanding verification token they hold.
+func (r Repo) ConsumeEmailVerificationToken(ctx context.Context, tokenHash string) (userID string, err error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				err = fmt.Errorf("transaction rollback failed: %w (original error: %w)", rollbackErr, err)
+			}
+			return
+		}
+		commitErr := tx.Commit()
+		if commitErr != nil {
+			err = fmt.Errorf("transaction commit failed: %w", commitErr)
+		}
+	}()
+
+	now := time.Now().UTC().Format(time.DateTime)
+
+	err = tx.QueryRowContext(ctx, `
+	SELECT user_id FROM email_verification_tokens
+	WHERE token_hash = ? AND used_at IS NULL AND expires_at > ?`,
+		tokenHash,
+		now,
+	).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrVerifyTokenInvalid
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up email verification token: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE email_verification_tokens SET used_at = ? WHERE user_id = ? AND used_at IS NULL`,
+		now,
+		userID,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to spend email verification tokens: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE users SET email_verified = 1 WHERE id = ?`, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to mark email verified: %w", err)
+	}
+
+	return userID, nil
+}
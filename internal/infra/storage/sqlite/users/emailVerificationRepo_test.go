@@ -0,0 +1,79 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func emailVerified(t *testing.T, repo *Repo) bool {
+	t.Helper()
+
+	var verified bool
+	err := repo.DB.QueryRow(`SELECT email_verified FROM users WHERE id = 'alice'`).Scan(&verified)
+	if err != nil {
+		t.Fatalf("failed to read email_verified: %v", err)
+	}
+	return verified
+}
+
+func TestRepo_ConsumeEmailVerificationToken(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("valid token verifies the email", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		err := repo.CreateEmailVerificationToken(ctx, "alice", "hash-1", time.Now().Add(time.Hour))
+		if err != nil {
+			t.Fatalf("CreateEmailVerificationToken() error = %v", err)
+		}
+
+		userID, err := repo.ConsumeEmailVerificationToken(ctx, "hash-1")
+		if err != nil {
+			t.Fatalf("ConsumeEmailVerificationToken() error = %v", err)
+		}
+		if userID != "alice" {
+			t.Errorf("ConsumeEmailVerificationToken() userID = %q, want alice", userID)
+		}
+		if !emailVerified(t, repo) {
+			t.Error("email_verified = false, want true")
+		}
+	})
+
+	t.Run("reused token is rejected", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		err := repo.CreateEmailVerificationToken(ctx, "alice", "hash-1", time.Now().Add(time.Hour))
+		if err != nil {
+			t.Fatalf("CreateEmailVerificationToken() error = %v", err)
+		}
+
+		_, err = repo.ConsumeEmailVerificationToken(ctx, "hash-1")
+		if err != nil {
+			t.Fatalf("first ConsumeEmailVerificationToken() error = %v", err)
+		}
+
+		_, err = repo.ConsumeEmailVerificationToken(ctx, "hash-1")
+		if !errors.Is(err, ErrVerifyTokenInvalid) {
+			t.Errorf("second ConsumeEmailVerificationToken() error = %v, want %v", err, ErrVerifyTokenInvalid)
+		}
+	})
+
+	t.Run("expired token is rejected", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		err := repo.CreateEmailVerificationToken(ctx, "alice", "hash-1", time.Now().Add(-time.Minute))
+		if err != nil {
+			t.Fatalf("CreateEmailVerificationToken() error = %v", err)
+		}
+
+		_, err = repo.ConsumeEmailVerificationToken(ctx, "hash-1")
+		if !errors.Is(err, ErrVerifyTokenInvalid) {
+			t.Errorf("ConsumeEmailVerificationToken() error = %v, want %v", err, ErrVerifyTokenInvalid)
+		}
+		if emailVerified(t, repo) {
+			t.Error("email_verified = true, want false")
+		}
+	})
+}
@@ -2,42 +2,41 @@ package users
 
 import (
 	"errors"
-	"fmt"
 	"strings"
 
 	"github.com/mattn/go-sqlite3"
+
+	"github.com/arnald/forum/internal/infra/storage/sqlite/sqliteerrors"
 )
 
 var (
 	ErrDuplicateEmail        = errors.New("email already exists")
 	ErrDuplicateUsername     = errors.New("username already exists")
-	ErrConstraint            = errors.New("sqlite constrain error")
-	ErrUnknownConstraint     = errors.New("sqlite unknow constraint error")
 	ErrInvalidEmail          = errors.New("invalid email format")
 	ErrUserNotFound          = errors.New("user not found")
 	ErrTopicNotFound         = errors.New("topic not found")
 	ErrCategoryAlreadyExists = errors.New("category already exists")
 	ErrCategoryNotFound      = errors.New("category not found")
+	ErrResetTokenInvalid     = errors.New("reset link is invalid or has expired")
+	ErrVerifyTokenInvalid    = errors.New("verification link is invalid or has expired")
+	ErrAvatarInUse           = errors.New("avatar belongs to another user")
 )
 
-func MapSQLiteError(err error) error {
+// mapUserError recognises the users table's unique and email checks and
+// leaves every other failure to sqliteerrors.MapSQLiteError.
+func mapUserError(err error) error {
 	var sqliteErr sqlite3.Error
-	if errors.As(err, &sqliteErr) {
-		if sqliteErr.Code == sqlite3.ErrConstraint {
-			msg := err.Error()
+	if errors.As(err, &sqliteErr) && sqliteErr.Code == sqlite3.ErrConstraint {
+		msg := err.Error()
 
-			switch {
-			case strings.Contains(msg, "users.email"):
-				return ErrDuplicateEmail
-			case strings.Contains(msg, "users.username"):
-				return ErrDuplicateUsername
-			case strings.Contains(msg, "email LIKE"):
-				return ErrInvalidEmail
-			default:
-				return fmt.Errorf("%w: %w", ErrConstraint, sqliteErr)
-			}
+		switch {
+		case strings.Contains(msg, "users.email"):
+			return ErrDuplicateEmail
+		case strings.Contains(msg, "users.username"):
+			return ErrDuplicateUsername
+		case strings.Contains(msg, "email LIKE"):
+			return ErrInvalidEmail
 		}
-		return fmt.Errorf("%w: %w: %s ", ErrUnknownConstraint, sqliteErr.Code, sqliteErr.Error())
 	}
-	return err
+	return sqliteerrors.MapSQLiteError(err)
 }
@@ -0,0 +1,53 @@
+package users
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/arnald/forum/internal/infra/storage/sqlite/sqliteerrors"
+)
+
+func TestMapUserError(t *testing.T) {
+	repo := newTestRepo(t)
+
+	testCases := []struct {
+		wantErr error
+		name    string
+		query   string
+	}{
+		{
+			name:    "duplicate email",
+			query:   `INSERT INTO users (id, email, username) VALUES ('bob', 'alice@example.com', 'bob')`,
+			wantErr: ErrDuplicateEmail,
+		},
+		{
+			name:    "malformed email",
+			query:   `INSERT INTO users (id, email, username) VALUES ('bob', 'not-an-email', 'bob')`,
+			wantErr: ErrInvalidEmail,
+		},
+		{
+			name:    "foreign key to a missing user",
+			query:   `INSERT INTO username_history (user_id, old_username) VALUES ('nobody', 'ghost')`,
+			wantErr: sqliteerrors.ErrInvalidReference,
+		},
+		{
+			name:    "check constraint",
+			query:   `INSERT INTO votes (user_id, topic_id, reaction_type) VALUES ('alice', NULL, 5)`,
+			wantErr: sqliteerrors.ErrCheckConstraint,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := repo.DB.Exec(tt.query)
+			if err == nil {
+				t.Fatal("query succeeded, want a constraint violation")
+			}
+
+			got := mapUserError(err)
+			if !errors.Is(got, tt.wantErr) {
+				t.Errorf("mapUserError() = %v, want %v", got, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package users
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// GetLoginLockout returns when the user's login lock ends, or the zero time
+// if they have never been locked out.
+func (r Repo) GetLoginLockout(ctx context.Context, userID string) (time.Time, error) {
+	var lockedUntil sql.NullTime
+	err := r.DB.QueryRowContext(ctx,
+		`SELECT locked_until FROM failed_logins WHERE user_id = ?`,
+		userID,
+	).Scan(&lockedUntil)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get login lockout: %w", err)
+	}
+
+	return lockedUntil.Time, nil
+}
+
+// RecordFailedLogin counts a failed login for the user. The attempt that
+// reaches maxAttempts locks the account for lockFor and starts the count
+// again; the returned time is when the current lock, if any, ends.
+func (r Repo) RecordFailedLogin(ctx context.Context, userID string, maxAttempts int, lockFor time.Duration) (time.Time, error) {
+	lockedUntil := time.Now().Add(lockFor).UTC().Format(time.DateTime)
+
+	var stored sql.NullTime
+	err := r.DB.QueryRowContext(ctx, `
+	INSERT INTO failed_logins (user_id, attempts, locked_until)
+	VALUES (?, CASE WHEN 1 >= ? THEN 0 ELSE 1 END, CASE WHEN 1 >= ? THEN ? END)
+	ON CONFLICT(user_id) DO UPDATE SET
+		attempts = CASE WHEN attempts + 1 >= ? THEN 0 ELSE attempts + 1 END,
+		locked_until = CASE WHEN attempts + 1 >= ? THEN ? ELSE locked_until END
+	RETURNING locked_until`,
+		userID, maxAttempts, maxAttempts, lockedUntil,
+		maxAttempts, maxAttempts, lockedUntil,
+	).Scan(&stored)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to record failed login: %w", err)
+	}
+
+	return stored.Time, nil
+}
+
+// ResetFailedLogins clears the user's failed login count after a successful
+// login.
+func (r Repo) ResetFailedLogins(ctx context.Context, userID string) error {
+	_, err := r.DB.ExecContext(ctx, `DELETE FROM failed_logins WHERE user_id = ?`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to reset failed logins: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package users
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRepo_RecordFailedLogin(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("locks on the final allowed attempt", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		for attempt := 1; attempt < 3; attempt++ {
+			lockedUntil, err := repo.RecordFailedLogin(ctx, "alice", 3, time.Minute)
+			if err != nil {
+				t.Fatalf("RecordFailedLogin() error = %v", err)
+			}
+			if !lockedUntil.IsZero() {
+				t.Fatalf("attempt %d locked until %v, want no lock", attempt, lockedUntil)
+			}
+		}
+
+		lockedUntil, err := repo.RecordFailedLogin(ctx, "alice", 3, time.Minute)
+		if err != nil {
+			t.Fatalf("RecordFailedLogin() error = %v", err)
+		}
+		if !lockedUntil.After(time.Now()) {
+			t.Fatalf("third attempt locked until %v, want a lock in the future", lockedUntil)
+		}
+
+		got, err := repo.GetLoginLockout(ctx, "alice")
+		if err != nil {
+			t.Fatalf("GetLoginLockout() error = %v", err)
+		}
+		if !got.Equal(lockedUntil) {
+			t.Errorf("GetLoginLockout() = %v, want %v", got, lockedUntil)
+		}
+	})
+
+	t.Run("reset clears the count", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		for range 2 {
+			_, err := repo.RecordFailedLogin(ctx, "alice", 3, time.Minute)
+			if err != nil {
+				t.Fatalf("RecordFailedLogin() error = %v", err)
+			}
+		}
+
+		err := repo.ResetFailedLogins(ctx, "alice")
+		if err != nil {
+			t.Fatalf("ResetFailedLogins() error = %v", err)
+		}
+
+		lockedUntil, err := repo.RecordFailedLogin(ctx, "alice", 3, time.Minute)
+		if err != nil {
+			t.Fatalf("RecordFailedLogin() error = %v", err)
+		}
+		if !lockedUntil.IsZero() {
+			t.Errorf("first attempt after reset locked until %v, want no lock", lockedUntil)
+		}
+	})
+
+	t.Run("user without failures has no lockout", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		got, err := repo.GetLoginLockout(ctx, "alice")
+		if err != nil {
+			t.Fatalf("GetLoginLockout() error = %v", err)
+		}
+		if !got.IsZero() {
+			t.Errorf("GetLoginLockout() = %v, want zero", got)
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package users
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CreatePasswordResetToken stores the hash of a freshly issued reset token.
+func (r Repo) CreatePasswordResetToken(ctx context.Context, userID, tokenHash string, expiresAt time.Time) error {
+	_, err := r.DB.ExecContext(ctx, `
+	INSERT INTO password_reset_tokens (token_hash, user_id, expires_at)
+	VALUES (?, ?, ?)`,
+		tokenHash,
+		userID,
+		expiresAt.UTC().Format(time.DateTime),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store password reset token: %w", err)
+	}
+
+	return nil
+}
+
+// ConsumePasswordResetToken sets the new password hash for the token's owner
+// and spends every outstanding token they hold, so a reset link works once.
+func (r Repo) ConsumePasswordResetToken(ctx context.Context, tokenHash, passwordHash string) (userID string, err error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				err = fmt.Errorf("transaction rollback failed: %w (original error: %w)", rollbackErr, err)
+			}
+			return
+		}
+		commitErr := tx.Commit()
+		if commitErr != nil {
+			err = fmt.Errorf("transaction commit failed: %w", commitErr)
+		}
+	}()
+
+	now := time.Now().UTC().Format(time.DateTime)
+
+	err = tx.QueryRowContext(ctx, `
+	SELECT user_id FROM password_reset_tokens
+	WHERE token_hash = ? AND used_at IS NULL AND expires_at > ?`,
+		tokenHash,
+		now,
+	).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrResetTokenInvalid
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up password reset token: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE password_reset_tokens SET used_at = ? WHERE user_id = ? AND used_at IS NULL`,
+		now,
+		userID,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to spend password reset tokens: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE users SET password_hash = ? WHERE id = ?`, passwordHash, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to update password: %w", err)
+	}
+
+	return userID, nil
+}
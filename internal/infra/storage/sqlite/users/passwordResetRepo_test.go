@@ -0,0 +1,135 @@
+package users
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/arnald/forum/internal/pkg/path"
+)
+
+// newTestRepo returns a repository backed by a private in-memory database
+// with the project schema applied and one user, "alice".
+func newTestRepo(t *testing.T) *Repo {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	// Every connection to :memory: gets its own database, so keep just one.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	schema, err := os.ReadFile(path.NewResolver().GetPath("db/migrations/schema.sql"))
+	if err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+	_, err = db.Exec(string(schema))
+	if err != nil {
+		t.Fatalf("failed to apply schema: %v", err)
+	}
+
+	_, err = db.Exec(`INSERT INTO users (id, email, username, password_hash) VALUES ('alice', 'alice@example.com', 'alice', 'old-hash')`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+
+	return NewRepo(db)
+}
+
+func passwordHash(t *testing.T, repo *Repo) string {
+	t.Helper()
+
+	var hash string
+	err := repo.DB.QueryRow(`SELECT password_hash FROM users WHERE id = 'alice'`).Scan(&hash)
+	if err != nil {
+		t.Fatalf("failed to read password hash: %v", err)
+	}
+	return hash
+}
+
+func TestRepo_ConsumePasswordResetToken(t *testing.T) {
+	ctx := context.Background()
+	inAnHour := time.Now().Add(time.Hour)
+
+	t.Run("valid token sets the password once", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		err := repo.CreatePasswordResetToken(ctx, "alice", "hash-1", inAnHour)
+		if err != nil {
+			t.Fatalf("CreatePasswordResetToken() error = %v", err)
+		}
+
+		userID, err := repo.ConsumePasswordResetToken(ctx, "hash-1", "new-hash")
+		if err != nil {
+			t.Fatalf("ConsumePasswordResetToken() error = %v", err)
+		}
+		if userID != "alice" {
+			t.Errorf("ConsumePasswordResetToken() userID = %q, want alice", userID)
+		}
+		if got := passwordHash(t, repo); got != "new-hash" {
+			t.Errorf("password_hash = %q, want new-hash", got)
+		}
+
+		_, err = repo.ConsumePasswordResetToken(ctx, "hash-1", "other-hash")
+		if !errors.Is(err, ErrResetTokenInvalid) {
+			t.Errorf("second ConsumePasswordResetToken() error = %v, want %v", err, ErrResetTokenInvalid)
+		}
+		if got := passwordHash(t, repo); got != "new-hash" {
+			t.Errorf("password_hash = %q after reuse, want new-hash", got)
+		}
+	})
+
+	t.Run("consuming one token spends the others", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		for _, hash := range []string{"hash-1", "hash-2"} {
+			err := repo.CreatePasswordResetToken(ctx, "alice", hash, inAnHour)
+			if err != nil {
+				t.Fatalf("CreatePasswordResetToken() error = %v", err)
+			}
+		}
+
+		_, err := repo.ConsumePasswordResetToken(ctx, "hash-2", "new-hash")
+		if err != nil {
+			t.Fatalf("ConsumePasswordResetToken() error = %v", err)
+		}
+
+		_, err = repo.ConsumePasswordResetToken(ctx, "hash-1", "other-hash")
+		if !errors.Is(err, ErrResetTokenInvalid) {
+			t.Errorf("older token error = %v, want %v", err, ErrResetTokenInvalid)
+		}
+	})
+
+	t.Run("expired token is rejected", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		err := repo.CreatePasswordResetToken(ctx, "alice", "hash-1", time.Now().Add(-time.Minute))
+		if err != nil {
+			t.Fatalf("CreatePasswordResetToken() error = %v", err)
+		}
+
+		_, err = repo.ConsumePasswordResetToken(ctx, "hash-1", "new-hash")
+		if !errors.Is(err, ErrResetTokenInvalid) {
+			t.Errorf("ConsumePasswordResetToken() error = %v, want %v", err, ErrResetTokenInvalid)
+		}
+		if got := passwordHash(t, repo); got != "old-hash" {
+			t.Errorf("password_hash = %q, want old-hash kept", got)
+		}
+	})
+
+	t.Run("unknown token is rejected", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		_, err := repo.ConsumePasswordResetToken(ctx, "missing", "new-hash")
+		if !errors.Is(err, ErrResetTokenInvalid) {
+			t.Errorf("ConsumePasswordResetToken() error = %v, want %v", err, ErrResetTokenInvalid)
+		}
+	})
+}
@@ -19,11 +19,59 @@ func NewRepo(db *sql.DB) *Repo {
 	}
 }
 
-// TODO: retrieves all users from the repository.
-func (r Repo) GetAll(_ context.Context) ([]user.User, error) {
-	return nil, nil
+func (r Repo) GetAll(ctx context.Context, limit, offset int) ([]user.User, int, error) {
+	var total int
+	err := r.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM users").Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	query := `
+	SELECT id, username, email, role, created_at, avatar_url
+	FROM users
+	ORDER BY created_at ASC, id ASC
+	LIMIT ? OFFSET ?`
+
+	stmt, err := r.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, 0, fmt.Errorf("prepare failed: %w", err)
+	}
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]user.User, 0)
+	for rows.Next() {
+		var u user.User
+		err = rows.Scan(
+			&u.ID,
+			&u.Username,
+			&u.Email,
+			&u.Role,
+			&u.CreatedAt,
+			&u.AvatarURL,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan users failed: %w", err)
+		}
+		users = append(users, u)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, 0, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return users, total, nil
 }
 
+// UserRegister creates the account. Emails are stored normalized, so the
+// unique index alone refuses a taken address, including between two
+// registrations racing for it.
 func (r Repo) UserRegister(ctx context.Context, user *user.User) error {
 	query := `
 	INSERT INTO users (username, password_hash, email, id)
@@ -44,7 +92,7 @@ func (r Repo) UserRegister(ctx context.Context, user *user.User) error {
 		user.ID,
 	)
 
-	mapErr := MapSQLiteError(err)
+	mapErr := mapUserError(err)
 	if mapErr != nil {
 		return mapErr
 	}
@@ -81,7 +129,7 @@ func (r Repo) GetUserByIdentifier(ctx context.Context, identifier string) (*user
 
 func (r Repo) GetUserByEmail(ctx context.Context, email string) (*user.User, error) {
 	query := `
-	SELECT id, username, password_hash
+	SELECT id, username, COALESCE(password_hash, '')
 	FROM users
 	WHERE email = ?
 	`
@@ -129,3 +177,46 @@ func (r Repo) GetUserByUsername(ctx context.Context, username string) (*user.Use
 
 	return &user, nil
 }
+
+// UpdatePassword replaces the user's password hash.
+func (r Repo) UpdatePassword(ctx context.Context, userID, passwordHash string) error {
+	result, err := r.DB.ExecContext(ctx,
+		`UPDATE users SET password_hash = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`,
+		passwordHash,
+		userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r Repo) UpdateUserRole(ctx context.Context, userID, role string) error {
+	result, err := r.DB.ExecContext(ctx,
+		`UPDATE users SET role = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`,
+		role,
+		userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update role: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update role: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/arnald/forum/internal/domain/user"
+)
+
+func TestRepo_GetAll(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("pages through users oldest first", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		_, err := repo.DB.Exec(`
+		UPDATE users SET created_at = '2024-01-02 00:00:00' WHERE id = 'alice';
+		INSERT INTO users (id, email, username, role, created_at, avatar_url) VALUES
+			('bob', 'bob@example.com', 'bob', 'admin', '2024-01-01 00:00:00', '/avatars/bob.png'),
+			('carol', 'carol@example.com', 'carol', 'user', '2024-01-03 00:00:00', NULL)`)
+		if err != nil {
+			t.Fatalf("failed to seed users: %v", err)
+		}
+
+		page, total, err := repo.GetAll(ctx, 2, 0)
+		if err != nil {
+			t.Fatalf("GetAll() error = %v", err)
+		}
+		if total != 3 {
+			t.Errorf("GetAll() total = %d, want 3", total)
+		}
+		if len(page) != 2 || page[0].ID != "bob" || page[1].ID != "alice" {
+			t.Fatalf("GetAll() first page = %+v, want bob then alice", page)
+		}
+
+		bob := page[0]
+		if bob.Username != "bob" || bob.Email != "bob@example.com" || bob.Role != "admin" {
+			t.Errorf("GetAll() bob = %+v, want username, email and role filled in", bob)
+		}
+		if bob.AvatarURL == nil || *bob.AvatarURL != "/avatars/bob.png" {
+			t.Errorf("GetAll() bob AvatarURL = %v, want /avatars/bob.png", bob.AvatarURL)
+		}
+		if bob.CreatedAt.IsZero() {
+			t.Error("GetAll() bob CreatedAt is zero")
+		}
+		if bob.Password != "" {
+			t.Error("GetAll() loaded a password hash")
+		}
+
+		rest, _, err := repo.GetAll(ctx, 2, 2)
+		if err != nil {
+			t.Fatalf("GetAll() second page error = %v", err)
+		}
+		if len(rest) != 1 || rest[0].ID != "carol" || rest[0].AvatarURL != nil {
+			t.Errorf("GetAll() second page = %+v, want only carol without an avatar", rest)
+		}
+	})
+
+	t.Run("empty table gives an empty slice", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		_, err := repo.DB.Exec(`DELETE FROM users`)
+		if err != nil {
+			t.Fatalf("failed to clear users: %v", err)
+		}
+
+		got, total, err := repo.GetAll(ctx, 20, 0)
+		if err != nil {
+			t.Fatalf("GetAll() error = %v", err)
+		}
+		if got == nil || len(got) != 0 || total != 0 {
+			t.Errorf("GetAll() = %#v, %d, want an empty non-nil slice and 0", got, total)
+		}
+	})
+}
+
+func TestRepo_UpdateUserRole(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+
+	err := repo.UpdateUserRole(ctx, "alice", user.RoleModerator)
+	if err != nil {
+		t.Fatalf("UpdateUserRole() error = %v", err)
+	}
+
+	var role string
+	err = repo.DB.QueryRow(`SELECT role FROM users WHERE id = 'alice'`).Scan(&role)
+	if err != nil {
+		t.Fatalf("failed to read role: %v", err)
+	}
+	if role != user.RoleModerator {
+		t.Errorf("role = %q, want %q", role, user.RoleModerator)
+	}
+
+	err = repo.UpdateUserRole(ctx, "nobody", user.RoleModerator)
+	if !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("UpdateUserRole() error = %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestRepo_UserRegister_DuplicateEmail(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepo(t)
+
+	const racers = 8
+	errs := make(chan error, racers)
+	var wg sync.WaitGroup
+	for i := range racers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id := "racer-" + strconv.Itoa(i)
+			errs <- repo.UserRegister(ctx, &user.User{ID: id, Username: id, Email: "john@example.com", Password: "hash"})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	registered := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			registered++
+		case !errors.Is(err, ErrDuplicateEmail):
+			t.Errorf("UserRegister() error = %v, want ErrDuplicateEmail", err)
+		}
+	}
+	if registered != 1 {
+		t.Errorf("%d registrations succeeded, want exactly 1", registered)
+	}
+
+	found, err := repo.GetUserByEmail(ctx, "john@example.com")
+	if err != nil {
+		t.Fatalf("GetUserByEmail() error = %v", err)
+	}
+	if found.ID == "" {
+		t.Error("GetUserByEmail() found no account for the registered email")
+	}
+}
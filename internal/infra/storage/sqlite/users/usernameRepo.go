@@ -0,0 +1,117 @@
+package users
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+const usernameExistsQuery = `SELECT EXISTS(SELECT 1 FROM users WHERE username = ?)`
+
+// UsernameExists reports whether an account currently goes by username.
+func (r Repo) UsernameExists(ctx context.Context, username string) (bool, error) {
+	var exists bool
+	err := r.DB.QueryRowContext(ctx, usernameExistsQuery, username).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check username: %w", err)
+	}
+
+	return exists, nil
+}
+
+// UpdateUsername renames the user and records the name they are leaving in
+// username_history. Choosing the current name again changes nothing.
+func (r Repo) UpdateUsername(ctx context.Context, userID, newName string) (err error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				err = fmt.Errorf("transaction rollback failed: %w (original error: %w)", rollbackErr, err)
+			}
+			return
+		}
+		commitErr := tx.Commit()
+		if commitErr != nil {
+			err = fmt.Errorf("transaction commit failed: %w", commitErr)
+		}
+	}()
+
+	var oldName string
+	err = tx.QueryRowContext(ctx, `SELECT username FROM users WHERE id = ?`, userID).Scan(&oldName)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get current username: %w", err)
+	}
+	if oldName == newName {
+		return nil
+	}
+
+	var taken bool
+	err = tx.QueryRowContext(ctx, usernameExistsQuery, newName).Scan(&taken)
+	if err != nil {
+		return fmt.Errorf("failed to check username: %w", err)
+	}
+	if taken {
+		return ErrDuplicateUsername
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO username_history (user_id, old_username) VALUES (?, ?)`,
+		userID,
+		oldName,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record username history: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE users SET username = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`,
+		newName,
+		userID,
+	)
+	if err != nil {
+		return mapUserError(err)
+	}
+
+	return nil
+}
+
+// GetUsernameHistory lists the names the user went by before, most recent
+// first.
+func (r Repo) GetUsernameHistory(ctx context.Context, userID string) ([]string, error) {
+	rows, err := r.DB.QueryContext(ctx, `
+	SELECT old_username FROM username_history
+	WHERE user_id = ?
+	ORDER BY id DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	names := make([]string, 0)
+	for rows.Next() {
+		var name string
+		err = rows.Scan(&name)
+		if err != nil {
+			return nil, fmt.Errorf("scan username history failed: %w", err)
+		}
+		names = append(names, name)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return names, nil
+}
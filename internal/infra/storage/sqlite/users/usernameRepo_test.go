@@ -0,0 +1,105 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func currentUsername(t *testing.T, repo *Repo, userID string) string {
+	t.Helper()
+
+	var name string
+	err := repo.DB.QueryRow(`SELECT username FROM users WHERE id = ?`, userID).Scan(&name)
+	if err != nil {
+		t.Fatalf("failed to read username: %v", err)
+	}
+	return name
+}
+
+func TestRepo_UpdateUsername(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("records each name left behind", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		for _, name := range []string{"alicia", "ally"} {
+			err := repo.UpdateUsername(ctx, "alice", name)
+			if err != nil {
+				t.Fatalf("UpdateUsername(%q) error = %v", name, err)
+			}
+		}
+
+		if got := currentUsername(t, repo, "alice"); got != "ally" {
+			t.Errorf("username = %q, want %q", got, "ally")
+		}
+
+		history, err := repo.GetUsernameHistory(ctx, "alice")
+		if err != nil {
+			t.Fatalf("GetUsernameHistory() error = %v", err)
+		}
+		if want := []string{"alicia", "alice"}; !slices.Equal(history, want) {
+			t.Errorf("GetUsernameHistory() = %v, want %v", history, want)
+		}
+
+		exists, err := repo.UsernameExists(ctx, "alice")
+		if err != nil {
+			t.Fatalf("UsernameExists() error = %v", err)
+		}
+		if exists {
+			t.Error("UsernameExists(old name) = true, want it free again")
+		}
+	})
+
+	t.Run("rejects a name another account holds", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		_, err := repo.DB.Exec(`INSERT INTO users (id, email, username) VALUES ('bob', 'bob@example.com', 'bob')`)
+		if err != nil {
+			t.Fatalf("failed to seed bob: %v", err)
+		}
+
+		err = repo.UpdateUsername(ctx, "alice", "bob")
+		if !errors.Is(err, ErrDuplicateUsername) {
+			t.Fatalf("UpdateUsername() error = %v, want %v", err, ErrDuplicateUsername)
+		}
+
+		if got := currentUsername(t, repo, "alice"); got != "alice" {
+			t.Errorf("username = %q after a refused rename, want %q", got, "alice")
+		}
+		history, err := repo.GetUsernameHistory(ctx, "alice")
+		if err != nil {
+			t.Fatalf("GetUsernameHistory() error = %v", err)
+		}
+		if len(history) != 0 {
+			t.Errorf("GetUsernameHistory() = %v after a refused rename, want none", history)
+		}
+	})
+
+	t.Run("keeping the same name records nothing", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		err := repo.UpdateUsername(ctx, "alice", "alice")
+		if err != nil {
+			t.Fatalf("UpdateUsername() error = %v", err)
+		}
+
+		history, err := repo.GetUsernameHistory(ctx, "alice")
+		if err != nil {
+			t.Fatalf("GetUsernameHistory() error = %v", err)
+		}
+		if len(history) != 0 {
+			t.Errorf("GetUsernameHistory() = %v, want none", history)
+		}
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		err := repo.UpdateUsername(ctx, "nobody", "someone")
+		if !errors.Is(err, ErrUserNotFound) {
+			t.Errorf("UpdateUsername() error = %v, want %v", err, ErrUserNotFound)
+		}
+	})
+}
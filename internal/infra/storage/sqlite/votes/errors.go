@@ -5,4 +5,7 @@ import "errors"
 var (
 	ErrVoteNotFound      = errors.New("vote not found")
 	ErrInvalidVoteTarget = errors.New("invalid vote target: exactly one of topicID or commentID must be provided")
+	// ErrTopicNotFound is returned when the voted topic, or the topic of the
+	// voted comment, is deleted or a draft the voter does not own.
+	ErrTopicNotFound = errors.New("topic not found")
 )
@@ -3,8 +3,10 @@ package votes
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/arnald/forum/internal/domain/vote"
 )
@@ -17,68 +19,162 @@ func NewRepo(db *sql.DB) *Repo {
 	return &Repo{DB: db}
 }
 
-func (r *Repo) CastVote(ctx context.Context, userID string, target vote.Target, reactionType int) error {
-	var query string
-	var args []interface{}
+// CastVote adds, switches or, when the same reaction is cast again, removes
+// the user's vote on target. Topic votes also move the topic's stored
+// upvote_count and downvote_count in the same transaction.
+func (r *Repo) CastVote(ctx context.Context, userID string, target vote.Target, reactionType int) (err error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
 
-	if target.CommentID != nil {
-		// Check if vote exists with same reaction type
-		var existingReaction sql.NullInt32
-		checkQuery := `SELECT reaction_type FROM votes WHERE user_id = ? AND comment_id = ? AND topic_id IS NULL`
-		err := r.DB.QueryRowContext(ctx, checkQuery, userID, *target.CommentID).Scan(&existingReaction)
-
-		if err == nil && existingReaction.Valid && int(existingReaction.Int32) == reactionType {
-			// Same vote - delete it (toggle off)
-			deleteQuery := `DELETE FROM votes WHERE user_id = ? AND comment_id = ? AND topic_id IS NULL`
-			_, err := r.DB.ExecContext(ctx, deleteQuery, userID, *target.CommentID)
-			return err
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				err = fmt.Errorf("transaction rollback failed: %w (original error: %w)", rollbackErr, err)
+			}
+			return
 		}
+		commitErr := tx.Commit()
+		if commitErr != nil {
+			err = fmt.Errorf("transaction commit failed: %w", commitErr)
+		}
+	}()
 
-		// Different vote or no vote - insert/update
-		query = `
-		INSERT INTO votes (user_id, topic_id, comment_id, reaction_type)
-		VALUES (?, NULL, ?, ?)
-		ON CONFLICT (user_id, comment_id) DO UPDATE SET
-			reaction_type = EXCLUDED.reaction_type,
-			created_at = CURRENT_TIMESTAMP`
-		args = []interface{}{userID, *target.CommentID, reactionType}
-	} else {
-		// Check if vote exists with same reaction type
-		var existingReaction sql.NullInt32
-		checkQuery := `SELECT reaction_type FROM votes WHERE user_id = ? AND topic_id = ? AND comment_id IS NULL`
-		err := r.DB.QueryRowContext(ctx, checkQuery, userID, *target.TopicID).Scan(&existingReaction)
-
-		if err == nil && existingReaction.Valid && int(existingReaction.Int32) == reactionType {
-			// Same vote - delete it (toggle off)
-			deleteQuery := `DELETE FROM votes WHERE user_id = ? AND topic_id = ? AND comment_id IS NULL`
-			_, err := r.DB.ExecContext(ctx, deleteQuery, userID, *target.TopicID)
+	// Clearing the current vote is the first statement so the transaction
+	// takes the write lock before reading anything; concurrent casts by the
+	// same user then wait their turn instead of failing as locked.
+	previous, err := removeVote(ctx, tx, userID, target)
+	if err != nil {
+		return err
+	}
+
+	visible, err := targetTopicVisible(ctx, tx, userID, target)
+	if err != nil {
+		return err
+	}
+	if !visible {
+		return ErrTopicNotFound
+	}
+
+	// Casting the same reaction again toggles the vote off.
+	next := reactionType
+	if previous == reactionType {
+		next = 0
+	}
+
+	if target.CommentID == nil {
+		err = adjustTopicVoteCounts(ctx, tx, *target.TopicID, previous, next)
+		if err != nil {
 			return err
 		}
+	}
 
-		// Different vote or no vote - insert/update
-		query = `
-		INSERT INTO votes (user_id, topic_id, comment_id, reaction_type)
-		VALUES (?, ?, NULL, ?)
-		ON CONFLICT (user_id, topic_id) DO UPDATE SET
-			reaction_type = EXCLUDED.reaction_type,
-			created_at = CURRENT_TIMESTAMP`
-		args = []interface{}{userID, target.TopicID, reactionType}
+	if next == 0 {
+		return nil
 	}
 
-	stmt, err := r.DB.PrepareContext(ctx, query)
-	if err != nil {
-		return fmt.Errorf("failed to prepare query for casting vote: %w", err)
+	var topicID, commentID interface{}
+	if target.CommentID != nil {
+		commentID = *target.CommentID
+	} else {
+		topicID = *target.TopicID
 	}
-	defer stmt.Close()
 
-	_, err = stmt.ExecContext(ctx, args...)
+	_, err = tx.ExecContext(ctx, `
+	INSERT INTO votes (user_id, topic_id, comment_id, reaction_type)
+	VALUES (?, ?, ?, ?)`,
+		userID,
+		topicID,
+		commentID,
+		next,
+	)
 	if err != nil {
 		return fmt.Errorf("failed to cast vote: %w", err)
 	}
 
+	_, err = tx.ExecContext(ctx, `INSERT INTO vote_casts (user_id) VALUES (?)`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to record vote cast: %w", err)
+	}
+
+	return nil
+}
+
+// removeVote deletes the user's vote on target and returns its reaction, or 0
+// when there was none.
+func removeVote(ctx context.Context, tx *sql.Tx, userID string, target vote.Target) (int, error) {
+	query := `DELETE FROM votes WHERE user_id = ? AND topic_id = ? AND comment_id IS NULL RETURNING reaction_type`
+	args := []interface{}{userID, target.TopicID}
+	if target.CommentID != nil {
+		query = `DELETE FROM votes WHERE user_id = ? AND comment_id = ? AND topic_id IS NULL RETURNING reaction_type`
+		args = []interface{}{userID, *target.CommentID}
+	}
+
+	var previous int
+	err := tx.QueryRowContext(ctx, query, args...).Scan(&previous)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear existing vote: %w", err)
+	}
+
+	return previous, nil
+}
+
+// targetTopicVisible reports whether the topic behind target, the comment's
+// topic for a comment vote, is neither deleted nor someone else's draft.
+func targetTopicVisible(ctx context.Context, tx *sql.Tx, userID string, target vote.Target) (bool, error) {
+	query := `
+	SELECT EXISTS (
+		SELECT 1 FROM topics t
+		WHERE t.id = COALESCE(?, (SELECT topic_id FROM comments WHERE id = ?))
+			AND t.deleted_at IS NULL
+			AND (t.status = 'published' OR t.user_id = ?)
+	)`
+
+	var visible bool
+	err := tx.QueryRowContext(ctx, query, target.TopicID, target.CommentID, userID).Scan(&visible)
+	if err != nil {
+		return false, fmt.Errorf("failed to check topic visibility: %w", err)
+	}
+
+	return visible, nil
+}
+
+// adjustTopicVoteCounts moves the topic's stored counters from the user's
+// previous reaction to next, where 0 stands for no vote.
+func adjustTopicVoteCounts(ctx context.Context, tx *sql.Tx, topicID, previous, next int) error {
+	upvotes := reactionCount(next, 1) - reactionCount(previous, 1)
+	downvotes := reactionCount(next, -1) - reactionCount(previous, -1)
+	if upvotes == 0 && downvotes == 0 {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, `
+	UPDATE topics
+	SET upvote_count = upvote_count + ?, downvote_count = downvote_count + ?
+	WHERE id = ?`,
+		upvotes,
+		downvotes,
+		topicID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update topic vote counts: %w", err)
+	}
+
 	return nil
 }
 
+func reactionCount(reaction, want int) int {
+	if reaction == want {
+		return 1
+	}
+	return 0
+}
+
 // func (r *Repo) CastVote(ctx context.Context, userID string, target vote.Target, reactionType int) error {
 // 	var query string
 // 	var args []interface{}
@@ -115,11 +211,32 @@ func (r *Repo) CastVote(ctx context.Context, userID string, target vote.Target,
 // 	return nil
 // }
 
-func (r *Repo) DeleteVote(ctx context.Context, userID string, topicID *int, commentID *int) error {
+// DeleteVote removes the user's vote on a topic or a comment, moving the
+// topic's stored counters down with it.
+func (r *Repo) DeleteVote(ctx context.Context, userID string, topicID *int, commentID *int) (err error) {
 	if (topicID == nil && commentID == nil) || (topicID != nil && commentID != nil) {
 		return ErrInvalidVoteTarget
 	}
 
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				err = fmt.Errorf("transaction rollback failed: %w (original error: %w)", rollbackErr, err)
+			}
+			return
+		}
+		commitErr := tx.Commit()
+		if commitErr != nil {
+			err = fmt.Errorf("transaction commit failed: %w", commitErr)
+		}
+	}()
+
 	var builder strings.Builder
 	var args []interface{}
 
@@ -133,19 +250,19 @@ func (r *Repo) DeleteVote(ctx context.Context, userID string, topicID *int, comm
 		builder.WriteString(" AND topic_id = ? AND comment_id IS NULL")
 		args = append(args, *topicID)
 	}
+	builder.WriteString(" RETURNING reaction_type")
 
-	result, err := r.DB.ExecContext(ctx, builder.String(), args...)
-	if err != nil {
-		return fmt.Errorf("failed to delete vote: %w", err)
+	var removed int
+	err = tx.QueryRowContext(ctx, builder.String(), args...).Scan(&removed)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrVoteNotFound
 	}
-
-	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("failed to get affected rows: %w", err)
+		return fmt.Errorf("failed to delete vote: %w", err)
 	}
 
-	if rowsAffected == 0 {
-		return ErrVoteNotFound
+	if topicID != nil {
+		return adjustTopicVoteCounts(ctx, tx, *topicID, removed, 0)
 	}
 
 	return nil
@@ -156,12 +273,7 @@ func (r *Repo) GetCounts(ctx context.Context, target vote.Target) (*vote.Counts,
 	var args []interface{}
 
 	if target.CommentID == nil {
-		query = `
-		SELECT
-			COUNT(CASE WHEN reaction_type = 1 THEN 1 END) as upvotes,
-			COUNT(CASE WHEN reaction_type = -1 THEN 1 END) as downvotes
-		FROM votes
-		WHERE topic_id = ? AND comment_id IS NULL`
+		query = `SELECT upvote_count, downvote_count FROM topics WHERE id = ?`
 		args = []interface{}{target.TopicID}
 	} else {
 		query = `
@@ -184,6 +296,10 @@ func (r *Repo) GetCounts(ctx context.Context, target vote.Target) (*vote.Counts,
 		&counts.Upvotes,
 		&counts.DownVotes,
 	)
+	if errors.Is(err, sql.ErrNoRows) {
+		// An unknown topic simply has no votes.
+		return &counts, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get vote counts: %w", err)
 	}
@@ -192,3 +308,37 @@ func (r *Repo) GetCounts(ctx context.Context, target vote.Target) (*vote.Counts,
 
 	return &counts, nil
 }
+
+func (r *Repo) GetUserReaction(ctx context.Context, userID string, target vote.Target) (int, error) {
+	query := `SELECT reaction_type FROM votes WHERE user_id = ? AND topic_id = ? AND comment_id IS NULL`
+	args := []interface{}{userID, target.TopicID}
+	if target.CommentID != nil {
+		query = `SELECT reaction_type FROM votes WHERE user_id = ? AND comment_id = ? AND topic_id IS NULL`
+		args = []interface{}{userID, *target.CommentID}
+	}
+
+	var reaction int
+	err := r.DB.QueryRowContext(ctx, query, args...).Scan(&reaction)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get user reaction: %w", err)
+	}
+
+	return reaction, nil
+}
+
+func (r *Repo) CountCastsSince(ctx context.Context, userID string, since time.Time) (int, error) {
+	var count int
+	err := r.DB.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM vote_casts WHERE user_id = ? AND created_at > ?`,
+		userID,
+		since.UTC().Format(time.DateTime),
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count vote casts: %w", err)
+	}
+
+	return count, nil
+}
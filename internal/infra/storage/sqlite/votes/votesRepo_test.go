@@ -0,0 +1,316 @@
+package votes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/arnald/forum/internal/domain/vote"
+	"github.com/arnald/forum/internal/pkg/path"
+)
+
+func newTestRepo(t *testing.T) *Repo {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	// Every connection to :memory: gets its own database, so keep just one.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	return seedTestRepo(t, db)
+}
+
+// newFileTestRepo is newTestRepo on a database file in SQLite's default
+// rollback-journal mode, so several connections contend for its locks.
+func newFileTestRepo(t *testing.T) *Repo {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "forum.db")+"?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return seedTestRepo(t, db)
+}
+
+// seedTestRepo applies the project schema to db and adds two users and one
+// topic.
+func seedTestRepo(t *testing.T, db *sql.DB) *Repo {
+	t.Helper()
+
+	schema, err := os.ReadFile(path.NewResolver().GetPath("db/migrations/schema.sql"))
+	if err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+	_, err = db.Exec(string(schema))
+	if err != nil {
+		t.Fatalf("failed to apply schema: %v", err)
+	}
+
+	_, err = db.Exec(`
+	INSERT INTO users (id, email, username) VALUES
+		('alice', 'alice@example.com', 'alice'),
+		('bob', 'bob@example.com', 'bob');
+	INSERT INTO topics (id, user_id, title, content) VALUES (1, 'alice', 'Topic', 'content');`)
+	if err != nil {
+		t.Fatalf("failed to seed database: %v", err)
+	}
+
+	return NewRepo(db)
+}
+
+// assertTopicCounts checks the stored counters against both the expected
+// values and the topic's rows in votes.
+func assertTopicCounts(t *testing.T, repo *Repo, wantUp, wantDown int) {
+	t.Helper()
+
+	var storedUp, storedDown, rowsUp, rowsDown int
+	err := repo.DB.QueryRow(`
+	SELECT t.upvote_count, t.downvote_count,
+		(SELECT COUNT(*) FROM votes WHERE topic_id = t.id AND comment_id IS NULL AND reaction_type = 1),
+		(SELECT COUNT(*) FROM votes WHERE topic_id = t.id AND comment_id IS NULL AND reaction_type = -1)
+	FROM topics t WHERE t.id = 1`).Scan(&storedUp, &storedDown, &rowsUp, &rowsDown)
+	if err != nil {
+		t.Fatalf("failed to read counts: %v", err)
+	}
+
+	if storedUp != wantUp || storedDown != wantDown {
+		t.Errorf("stored counts = %d up, %d down; want %d up, %d down", storedUp, storedDown, wantUp, wantDown)
+	}
+	if storedUp != rowsUp || storedDown != rowsDown {
+		t.Errorf("stored counts = %d up, %d down; votes rows = %d up, %d down", storedUp, storedDown, rowsUp, rowsDown)
+	}
+}
+
+func TestRepo_CastVote_TopicCounts(t *testing.T) {
+	ctx := context.Background()
+	topicID := 1
+	target := vote.Target{TopicID: &topicID}
+
+	t.Run("add, switch and toggle off", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		err := repo.CastVote(ctx, "bob", target, 1)
+		if err != nil {
+			t.Fatalf("CastVote(up) error = %v", err)
+		}
+		assertTopicCounts(t, repo, 1, 0)
+
+		err = repo.CastVote(ctx, "bob", target, -1)
+		if err != nil {
+			t.Fatalf("CastVote(down) error = %v", err)
+		}
+		assertTopicCounts(t, repo, 0, 1)
+
+		err = repo.CastVote(ctx, "bob", target, -1)
+		if err != nil {
+			t.Fatalf("CastVote(down again) error = %v", err)
+		}
+		assertTopicCounts(t, repo, 0, 0)
+	})
+
+	t.Run("votes from several users add up", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		for _, userID := range []string{"alice", "bob"} {
+			err := repo.CastVote(ctx, userID, target, 1)
+			if err != nil {
+				t.Fatalf("CastVote(%s) error = %v", userID, err)
+			}
+		}
+		assertTopicCounts(t, repo, 2, 0)
+
+		counts, err := repo.GetCounts(ctx, target)
+		if err != nil {
+			t.Fatalf("GetCounts() error = %v", err)
+		}
+		if counts.Upvotes != 2 || counts.DownVotes != 0 || counts.Score != 2 {
+			t.Errorf("GetCounts() = %+v, want 2 up, 0 down, score 2", counts)
+		}
+	})
+
+	t.Run("deleting a vote lowers its counter", func(t *testing.T) {
+		repo := newTestRepo(t)
+
+		err := repo.CastVote(ctx, "bob", target, -1)
+		if err != nil {
+			t.Fatalf("CastVote() error = %v", err)
+		}
+
+		err = repo.DeleteVote(ctx, "bob", &topicID, nil)
+		if err != nil {
+			t.Fatalf("DeleteVote() error = %v", err)
+		}
+		assertTopicCounts(t, repo, 0, 0)
+
+		err = repo.DeleteVote(ctx, "bob", &topicID, nil)
+		if !errors.Is(err, ErrVoteNotFound) {
+			t.Errorf("DeleteVote() again error = %v, want %v", err, ErrVoteNotFound)
+		}
+		assertTopicCounts(t, repo, 0, 0)
+	})
+}
+
+func TestRepo_CastVote_Comment(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	commentID := 1
+	target := vote.Target{CommentID: &commentID}
+
+	_, err := repo.DB.Exec(`INSERT INTO comments (id, user_id, topic_id, content) VALUES (1, 'alice', 1, 'comment')`)
+	if err != nil {
+		t.Fatalf("failed to seed comment: %v", err)
+	}
+
+	assertReaction := func(t *testing.T, want, wantUp, wantDown int) {
+		t.Helper()
+
+		reaction, err := repo.GetUserReaction(ctx, "bob", target)
+		if err != nil {
+			t.Fatalf("GetUserReaction() error = %v", err)
+		}
+		if reaction != want {
+			t.Errorf("GetUserReaction() = %d, want %d", reaction, want)
+		}
+
+		counts, err := repo.GetCounts(ctx, target)
+		if err != nil {
+			t.Fatalf("GetCounts() error = %v", err)
+		}
+		if counts.Upvotes != wantUp || counts.DownVotes != wantDown {
+			t.Errorf("GetCounts() = %+v, want %d up, %d down", counts, wantUp, wantDown)
+		}
+	}
+
+	err = repo.CastVote(ctx, "bob", target, 1)
+	if err != nil {
+		t.Fatalf("CastVote(up) error = %v", err)
+	}
+	assertReaction(t, 1, 1, 0)
+
+	err = repo.CastVote(ctx, "bob", target, -1)
+	if err != nil {
+		t.Fatalf("CastVote(down) error = %v", err)
+	}
+	assertReaction(t, -1, 0, 1)
+
+	err = repo.DeleteVote(ctx, "bob", nil, &commentID)
+	if err != nil {
+		t.Fatalf("DeleteVote() error = %v", err)
+	}
+	assertReaction(t, 0, 0, 0)
+
+	// The comment's votes never touch the topic's counters.
+	assertTopicCounts(t, repo, 0, 0)
+}
+
+func TestRepo_CastVote_Concurrent(t *testing.T) {
+	repo := newFileTestRepo(t)
+	ctx := context.Background()
+	topicID := 1
+	target := vote.Target{TopicID: &topicID}
+
+	// Rapid clicks on like and dislike from the same user race each other
+	// on separate connections.
+	const clicks = 20
+	errs := make(chan error, clicks)
+	var wg sync.WaitGroup
+	for i := range clicks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reaction := 1
+			if i%2 == 1 {
+				reaction = -1
+			}
+			errs <- repo.CastVote(ctx, "bob", target, reaction)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("CastVote() error = %v", err)
+		}
+	}
+
+	var rows int
+	err := repo.DB.QueryRow(`SELECT COUNT(*) FROM votes WHERE user_id = 'bob' AND topic_id = 1`).Scan(&rows)
+	if err != nil {
+		t.Fatalf("failed to count votes: %v", err)
+	}
+	if rows > 1 {
+		t.Errorf("bob has %d votes on the topic, want at most 1", rows)
+	}
+
+	var up, down int
+	err = repo.DB.QueryRow(`SELECT upvote_count, downvote_count FROM topics WHERE id = 1`).Scan(&up, &down)
+	if err != nil {
+		t.Fatalf("failed to read counts: %v", err)
+	}
+	if up+down != rows {
+		t.Errorf("stored counts = %d up, %d down; want them to match bob's %d vote rows", up, down, rows)
+	}
+}
+
+func TestRepo_CastVote_HiddenTopic(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	_, err := repo.DB.Exec(`
+	INSERT INTO topics (id, user_id, title, content, status, deleted_at) VALUES
+		(2, 'alice', 'Draft', 'content', 'draft', NULL),
+		(3, 'alice', 'Deleted', 'content', 'published', CURRENT_TIMESTAMP);
+	INSERT INTO comments (id, user_id, topic_id, content) VALUES (1, 'alice', 2, 'on draft'), (2, 'alice', 3, 'on deleted');`)
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	draftID, deletedID := 2, 3
+	onDraft, onDeleted := 1, 2
+	testCases := []struct {
+		wantErr error
+		name    string
+		userID  string
+		target  vote.Target
+	}{
+		{name: "draft topic by another user", userID: "bob", target: vote.Target{TopicID: &draftID}, wantErr: ErrTopicNotFound},
+		{name: "comment on another user's draft", userID: "bob", target: vote.Target{CommentID: &onDraft}, wantErr: ErrTopicNotFound},
+		{name: "deleted topic", userID: "alice", target: vote.Target{TopicID: &deletedID}, wantErr: ErrTopicNotFound},
+		{name: "comment on a deleted topic", userID: "bob", target: vote.Target{CommentID: &onDeleted}, wantErr: ErrTopicNotFound},
+		{name: "own draft topic", userID: "alice", target: vote.Target{TopicID: &draftID}},
+		{name: "comment on own draft", userID: "alice", target: vote.Target{CommentID: &onDraft}},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			castErr := repo.CastVote(ctx, tt.userID, tt.target, 1)
+			if !errors.Is(castErr, tt.wantErr) {
+				t.Fatalf("CastVote() error = %v, want %v", castErr, tt.wantErr)
+			}
+
+			reaction, reactionErr := repo.GetUserReaction(ctx, tt.userID, tt.target)
+			if reactionErr != nil {
+				t.Fatalf("GetUserReaction() error = %v", reactionErr)
+			}
+			want := 1
+			if tt.wantErr != nil {
+				want = 0
+			}
+			if reaction != want {
+				t.Errorf("GetUserReaction() = %d, want %d", reaction, want)
+			}
+		})
+	}
+}
@@ -91,3 +91,15 @@ func GetEnvInt(s string, envMap map[string]string, defaultValue int) int {
 	}
 	return i
 }
+
+func GetEnvFloat(key string, envMap map[string]string, defaultValue float64) float64 {
+	strVal := GetEnv(key, envMap, "")
+	if strVal == "" {
+		return defaultValue
+	}
+	f, err := strconv.ParseFloat(strVal, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return f
+}
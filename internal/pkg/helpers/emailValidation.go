@@ -3,6 +3,8 @@ package helpers
 import (
 	"errors"
 	"regexp"
+	"slices"
+	"strings"
 )
 
 var (
@@ -25,3 +27,27 @@ func ValidateEmail(s string) error {
 
 	return nil
 }
+
+// gmailDomains are the domains Gmail delivers for. It ignores dots in the
+// local part and anything from a '+' on.
+var gmailDomains = []string{"gmail.com", "googlemail.com"}
+
+// NormalizeEmail returns the form an email is stored and looked up in: trimmed
+// and lowercased, since mailbox providers treat case as insignificant. With
+// stripAliases set, a Gmail address also loses its dots and +tag and is put
+// under gmail.com, so every alias of one mailbox maps to the same account.
+func NormalizeEmail(email string, stripAliases bool) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if !stripAliases {
+		return email
+	}
+
+	local, domain, found := strings.Cut(email, "@")
+	if !found || !slices.Contains(gmailDomains, domain) {
+		return email
+	}
+
+	local, _, _ = strings.Cut(local, "+")
+	local = strings.ReplaceAll(local, ".", "")
+	return local + "@" + gmailDomains[0]
+}
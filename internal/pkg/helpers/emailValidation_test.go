@@ -0,0 +1,27 @@
+package helpers
+
+import "testing"
+
+func TestNormalizeEmail(t *testing.T) {
+	tests := []struct {
+		name         string
+		email        string
+		want         string
+		stripAliases bool
+	}{
+		{name: "case is folded", email: "John.Doe@Example.COM", want: "john.doe@example.com"},
+		{name: "surrounding space is trimmed", email: "  jane@example.com ", want: "jane@example.com"},
+		{name: "gmail aliases are kept by default", email: "J.Doe+forum@Gmail.com", want: "j.doe+forum@gmail.com"},
+		{name: "gmail aliases are stripped when enabled", email: "J.Doe+forum@Gmail.com", want: "jdoe@gmail.com", stripAliases: true},
+		{name: "googlemail maps to gmail", email: "j.doe@googlemail.com", want: "jdoe@gmail.com", stripAliases: true},
+		{name: "other domains keep dots and tags", email: "j.doe+forum@example.com", want: "j.doe+forum@example.com", stripAliases: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeEmail(tt.email, tt.stripAliases); got != tt.want {
+				t.Errorf("NormalizeEmail(%q, %v) = %q, want %q", tt.email, tt.stripAliases, got, tt.want)
+			}
+		})
+	}
+}
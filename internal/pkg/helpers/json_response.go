@@ -19,10 +19,24 @@ type Info struct {
 	PrevPage     int `json:"prevPage,omitzero"`
 }
 
+type FieldErrorsResponse struct {
+	Fields map[string]string `json:"fields"`
+	Error  string            `json:"error"`
+}
+
 func RespondWithError(w http.ResponseWriter, code int, msg string) {
 	RespondWithJSON(w, code, nil, map[string]string{"error": msg})
 }
 
+// RespondWithFieldErrors sends an error body that keeps a summary message and
+// the per-field messages, e.g. {"error":"...","fields":{"email":"..."}}.
+func RespondWithFieldErrors(w http.ResponseWriter, code int, msg string, fields map[string]string) {
+	RespondWithJSON(w, code, nil, FieldErrorsResponse{
+		Error:  msg,
+		Fields: fields,
+	})
+}
+
 func RespondWithJSON(w http.ResponseWriter, code int, info *Info, payload any) {
 	var jsonData []byte
 	var err error
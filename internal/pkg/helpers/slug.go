@@ -0,0 +1,51 @@
+package helpers
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxSlugLength keeps slugs readable in URLs; longer titles are cut at the
+// last word that fits.
+const maxSlugLength = 80
+
+// Slugify turns a title into a lowercase, hyphen-separated URL segment.
+// Letters and digits from any script are kept; everything else separates
+// words. A title with nothing usable yields "".
+func Slugify(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for _, word := range words {
+		if b.Len() > 0 && b.Len()+1+len(word) > maxSlugLength {
+			break
+		}
+		if b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		b.WriteString(word)
+	}
+	return b.String()
+}
+
+// UniqueSlug returns base, or base with the lowest numeric suffix ("-2",
+// "-3", ...) that is not among taken.
+func UniqueSlug(base string, taken []string) string {
+	used := make(map[string]bool, len(taken))
+	for _, slug := range taken {
+		used[slug] = true
+	}
+	if !used[base] {
+		return base
+	}
+
+	for n := 2; ; n++ {
+		candidate := base + "-" + strconv.Itoa(n)
+		if !used[candidate] {
+			return candidate
+		}
+	}
+}
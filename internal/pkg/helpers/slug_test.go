@@ -0,0 +1,61 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{name: "plain words", title: "Hello World", want: "hello-world"},
+		{name: "punctuation separates words", title: "Go: why, & how?!", want: "go-why-how"},
+		{name: "surrounding noise is dropped", title: "  --Release notes--  ", want: "release-notes"},
+		{name: "digits are kept", title: "Top 10 tips for 2025", want: "top-10-tips-for-2025"},
+		{name: "accented letters are kept", title: "Café Crème", want: "café-crème"},
+		{name: "other scripts are kept", title: "Привет мир", want: "привет-мир"},
+		{name: "apostrophes split words", title: "Don't panic", want: "don-t-panic"},
+		{name: "nothing usable", title: "!!! ???", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Slugify(tt.title); got != tt.want {
+				t.Errorf("Slugify(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("long titles are cut at a word boundary", func(t *testing.T) {
+		got := Slugify(strings.Repeat("word ", 40))
+		if len(got) > maxSlugLength || strings.HasSuffix(got, "-") || !strings.HasSuffix(got, "word") {
+			t.Errorf("Slugify() = %q (%d bytes), want whole words within %d bytes", got, len(got), maxSlugLength)
+		}
+	})
+}
+
+func TestUniqueSlug(t *testing.T) {
+	tests := []struct {
+		name  string
+		base  string
+		want  string
+		taken []string
+	}{
+		{name: "free slug is kept", base: "hello", want: "hello"},
+		{name: "unrelated slugs do not collide", base: "hello", taken: []string{"hello-world"}, want: "hello"},
+		{name: "first collision gets -2", base: "hello", taken: []string{"hello"}, want: "hello-2"},
+		{name: "suffixes keep counting", base: "hello", taken: []string{"hello", "hello-2", "hello-3"}, want: "hello-4"},
+		{name: "gaps are reused", base: "hello", taken: []string{"hello", "hello-3"}, want: "hello-2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UniqueSlug(tt.base, tt.taken); got != tt.want {
+				t.Errorf("UniqueSlug(%q, %v) = %q, want %q", tt.base, tt.taken, got, tt.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"errors"
+	"io"
 	"sync"
 	"time"
 )
@@ -18,15 +19,26 @@ var (
 	ErrStateExpired  = errors.New("state expired")
 )
 
+// pendingState remembers which provider a state was issued for, so one
+// StateManager can serve every provider without a Google state passing a
+// GitHub callback.
+type pendingState struct {
+	provider  string
+	createdAt int64
+}
+
 type StateManager struct {
-	states map[string]int64
+	// random is the source of state tokens; tests swap it for a failing one.
+	random io.Reader
+	states map[string]pendingState
 	mu     sync.RWMutex
 	ttl    time.Duration
 }
 
 func NewStateManager(ttl time.Duration) *StateManager {
 	sm := &StateManager{
-		states: make(map[string]int64),
+		random: rand.Reader,
+		states: make(map[string]pendingState),
 		ttl:    ttl,
 	}
 
@@ -35,9 +47,10 @@ func NewStateManager(ttl time.Duration) *StateManager {
 	return sm
 }
 
-func (sm *StateManager) Generate() (string, error) {
+// Generate issues a single-use state for a login with the named provider.
+func (sm *StateManager) Generate(provider string) (string, error) {
 	b := make([]byte, bufferSize)
-	_, err := rand.Read(b)
+	_, err := io.ReadFull(sm.random, b)
 	if err != nil {
 		return "", err
 	}
@@ -45,22 +58,24 @@ func (sm *StateManager) Generate() (string, error) {
 	state := base64.URLEncoding.EncodeToString(b)
 
 	sm.mu.Lock()
-	sm.states[state] = time.Now().Unix()
+	sm.states[state] = pendingState{provider: provider, createdAt: time.Now().Unix()}
 	sm.mu.Unlock()
 
 	return state, nil
 }
 
-func (sm *StateManager) Verify(state string) error {
+// Verify consumes state if it was issued for provider. A state issued for a
+// different provider is reported as not found and left for its own callback.
+func (sm *StateManager) Verify(provider, state string) error {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
-	createdAt, exists := sm.states[state]
-	if !exists {
+	pending, exists := sm.states[state]
+	if !exists || pending.provider != provider {
 		return ErrStateNotFound
 	}
 
-	if time.Now().Unix()-createdAt > int64(sm.ttl.Seconds()) {
+	if time.Now().Unix()-pending.createdAt > int64(sm.ttl.Seconds()) {
 		delete(sm.states, state)
 		return ErrStateExpired
 	}
@@ -76,8 +91,8 @@ func (sm *StateManager) cleanup() {
 	for range ticker.C {
 		now := time.Now().Unix()
 		sm.mu.Lock()
-		for state, createdAt := range sm.states {
-			if now-createdAt > int64(sm.ttl.Seconds()) {
+		for state, pending := range sm.states {
+			if now-pending.createdAt > int64(sm.ttl.Seconds()) {
 				delete(sm.states, state)
 			}
 		}
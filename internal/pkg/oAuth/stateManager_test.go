@@ -0,0 +1,89 @@
+package oauth
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var errRandom = errors.New("random source failed")
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errRandom
+}
+
+func TestStateManager_Generate(t *testing.T) {
+	t.Run("failing random source", func(t *testing.T) {
+		sm := NewStateManager(time.Minute)
+		sm.random = failingReader{}
+
+		state, err := sm.Generate("github")
+		if !errors.Is(err, errRandom) {
+			t.Fatalf("Generate() error = %v, want %v", err, errRandom)
+		}
+		if state != "" {
+			t.Errorf("Generate() state = %q, want empty", state)
+		}
+		if len(sm.states) != 0 {
+			t.Errorf("Generate() stored %d states, want none", len(sm.states))
+		}
+		if err := sm.Verify("github", ""); !errors.Is(err, ErrStateNotFound) {
+			t.Errorf("Verify(\"\") error = %v, want %v", err, ErrStateNotFound)
+		}
+	})
+
+	t.Run("generated state verifies once", func(t *testing.T) {
+		sm := NewStateManager(time.Minute)
+
+		state, err := sm.Generate("github")
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if err := sm.Verify("github", state); err != nil {
+			t.Errorf("Verify() error = %v", err)
+		}
+		if err := sm.Verify("github", state); !errors.Is(err, ErrStateNotFound) {
+			t.Errorf("second Verify() error = %v, want %v", err, ErrStateNotFound)
+		}
+	})
+}
+
+func TestStateManager_ConcurrentProviders(t *testing.T) {
+	t.Run("interleaved logins each validate their own state", func(t *testing.T) {
+		sm := NewStateManager(time.Minute)
+
+		googleState, err := sm.Generate("google")
+		if err != nil {
+			t.Fatalf("Generate(google) error = %v", err)
+		}
+		githubState, err := sm.Generate("github")
+		if err != nil {
+			t.Fatalf("Generate(github) error = %v", err)
+		}
+
+		if err := sm.Verify("google", googleState); err != nil {
+			t.Errorf("Verify(google) error = %v", err)
+		}
+		if err := sm.Verify("github", githubState); err != nil {
+			t.Errorf("Verify(github) error = %v", err)
+		}
+	})
+
+	t.Run("state is rejected by another provider's callback", func(t *testing.T) {
+		sm := NewStateManager(time.Minute)
+
+		googleState, err := sm.Generate("google")
+		if err != nil {
+			t.Fatalf("Generate(google) error = %v", err)
+		}
+
+		if err := sm.Verify("github", googleState); !errors.Is(err, ErrStateNotFound) {
+			t.Errorf("Verify(github, google state) error = %v, want %v", err, ErrStateNotFound)
+		}
+		if err := sm.Verify("google", googleState); err != nil {
+			t.Errorf("Verify(google) after a cross-provider attempt error = %v", err)
+		}
+	})
+}
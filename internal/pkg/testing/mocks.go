@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/arnald/forum/internal/domain/session"
 	"github.com/arnald/forum/internal/domain/topic"
@@ -13,16 +14,45 @@ import (
 var ErrTest = errors.New("test error")
 
 type MockRepository struct {
-	UserRegisterFunc        func(ctx context.Context, user *user.User) error
-	GetUserByEmailFunc      func(ctx context.Context, email string) (*user.User, error)
-	GetUserByUsernameFunc   func(ctx context.Context, username string) (*user.User, error)
-	GetAllFunc              func(ctx context.Context) ([]user.User, error)
-	CreateTopicFunc         func(ctx context.Context, topic *topic.Topic) error
-	UpdateTopicFunc         func(ctx context.Context, topic *topic.Topic) error
-	DeleteTopicFunc         func(ctx context.Context, userID string, topicID int) error
-	GetTopicByIDFunc        func(ctx context.Context, topicID int, userID *string) (*topic.Topic, error)
-	GetAllTopicsFunc        func(ctx context.Context, page, size, categoryID int, orderBy, order, filter string, userID *string) ([]topic.Topic, error)
-	GetTotalTopicsCountFunc func(ctx context.Context, filter string, categoryID int) (int, error)
+	UserRegisterFunc                func(ctx context.Context, user *user.User) error
+	GetUserByEmailFunc              func(ctx context.Context, email string) (*user.User, error)
+	GetUserByUsernameFunc           func(ctx context.Context, username string) (*user.User, error)
+	UsernameExistsFunc              func(ctx context.Context, username string) (bool, error)
+	UpdateUsernameFunc              func(ctx context.Context, userID, newName string) error
+	GetUsernameHistoryFunc          func(ctx context.Context, userID string) ([]string, error)
+	GetAllFunc                      func(ctx context.Context, limit, offset int) ([]user.User, int, error)
+	UpdatePasswordFunc              func(ctx context.Context, userID, passwordHash string) error
+	UpdateUserRoleFunc              func(ctx context.Context, userID, role string) error
+	UpdateAvatarFunc                func(ctx context.Context, userID, avatarURL string) (string, error)
+	CreatePasswordResetTokenFunc    func(ctx context.Context, userID, tokenHash string, expiresAt time.Time) error
+	ConsumePasswordResetTokenFunc   func(ctx context.Context, tokenHash, passwordHash string) (string, error)
+	CreateVerificationTokenFunc     func(ctx context.Context, userID, tokenHash string, expiresAt time.Time) error
+	ConsumeVerificationTokenFunc    func(ctx context.Context, tokenHash string) (string, error)
+	GetLoginLockoutFunc             func(ctx context.Context, userID string) (time.Time, error)
+	RecordFailedLoginFunc           func(ctx context.Context, userID string, maxAttempts int, lockFor time.Duration) (time.Time, error)
+	ResetFailedLoginsFunc           func(ctx context.Context, userID string) error
+	CreateTopicFunc                 func(ctx context.Context, topic *topic.Topic) error
+	UpdateTopicFunc                 func(ctx context.Context, topic *topic.Topic) error
+	DeleteTopicFunc                 func(ctx context.Context, userID string, topicID int) error
+	GetTopicByIDFunc                func(ctx context.Context, topicID int, userID *string) (*topic.Topic, error)
+	GetAllTopicsFunc                func(ctx context.Context, page, size, beforeID, categoryID int, orderBy, order, filter string, userID *string, controversy topic.ControversyWeights) ([]topic.Topic, error)
+	GetTotalTopicsCountFunc         func(ctx context.Context, filter string, categoryID int) (int, error)
+	GetCategoriesRequiringImageFunc func(ctx context.Context, categoryIDs []int) ([]string, error)
+	GetExistingCategoryIDsFunc      func(ctx context.Context, categoryIDs []int) ([]int, error)
+	IsTopicRemovedFunc              func(ctx context.Context, topicID int, userID *string) (bool, error)
+	IsTopicVisibleFunc              func(ctx context.Context, topicID int, userID *string) (bool, error)
+	FindRecentDuplicateFunc         func(ctx context.Context, userID, title, content string, window time.Duration) (*topic.Topic, error)
+	WatchTopicFunc                  func(ctx context.Context, userID string, topicID int) error
+	UnwatchTopicFunc                func(ctx context.Context, userID string, topicID int) error
+	GetTopicWatchersFunc            func(ctx context.Context, topicID int) ([]string, error)
+	SetAcceptedAnswerFunc           func(ctx context.Context, topicID, commentID int) error
+	RestoreTopicFunc                func(ctx context.Context, topicID int) error
+	IncrementTopicViewFunc          func(ctx context.Context, topicID int, viewerKey string, window time.Duration) (bool, error)
+	ToggleBookmarkFunc              func(ctx context.Context, userID string, topicID int) (bool, error)
+	GetBookmarkedTopicsFunc         func(ctx context.Context, userID string) ([]topic.Topic, error)
+	GetTopicRevisionsFunc           func(ctx context.Context, topicID int) ([]topic.Revision, error)
+	PublishTopicFunc                func(ctx context.Context, userID string, topicID int) error
+	GetDraftTopicsFunc              func(ctx context.Context, userID string) ([]topic.Topic, error)
 }
 
 func (m *MockRepository) UserRegister(ctx context.Context, user *user.User) error {
@@ -36,6 +66,27 @@ func (m *MockRepository) GetUserByEmail(ctx context.Context, email string) (*use
 	return nil, ErrTest
 }
 
+func (m *MockRepository) UsernameExists(ctx context.Context, username string) (bool, error) {
+	if m.UsernameExistsFunc != nil {
+		return m.UsernameExistsFunc(ctx, username)
+	}
+	return false, ErrTest
+}
+
+func (m *MockRepository) UpdateUsername(ctx context.Context, userID, newName string) error {
+	if m.UpdateUsernameFunc != nil {
+		return m.UpdateUsernameFunc(ctx, userID, newName)
+	}
+	return ErrTest
+}
+
+func (m *MockRepository) GetUsernameHistory(ctx context.Context, userID string) ([]string, error) {
+	if m.GetUsernameHistoryFunc != nil {
+		return m.GetUsernameHistoryFunc(ctx, userID)
+	}
+	return nil, ErrTest
+}
+
 func (m *MockRepository) GetUserByUsername(ctx context.Context, username string) (*user.User, error) {
 	if m.GetUserByUsernameFunc != nil {
 		return m.GetUserByUsernameFunc(ctx, username)
@@ -43,11 +94,83 @@ func (m *MockRepository) GetUserByUsername(ctx context.Context, username string)
 	return nil, ErrTest
 }
 
-func (m *MockRepository) GetAll(ctx context.Context) ([]user.User, error) {
+func (m *MockRepository) GetAll(ctx context.Context, limit, offset int) ([]user.User, int, error) {
 	if m.GetAllFunc != nil {
-		return m.GetAllFunc(ctx)
+		return m.GetAllFunc(ctx, limit, offset)
 	}
-	return nil, ErrTest
+	return nil, 0, ErrTest
+}
+
+func (m *MockRepository) UpdatePassword(ctx context.Context, userID, passwordHash string) error {
+	if m.UpdatePasswordFunc != nil {
+		return m.UpdatePasswordFunc(ctx, userID, passwordHash)
+	}
+	return ErrTest
+}
+
+func (m *MockRepository) UpdateUserRole(ctx context.Context, userID, role string) error {
+	if m.UpdateUserRoleFunc != nil {
+		return m.UpdateUserRoleFunc(ctx, userID, role)
+	}
+	return ErrTest
+}
+
+func (m *MockRepository) UpdateAvatar(ctx context.Context, userID, avatarURL string) (string, error) {
+	if m.UpdateAvatarFunc != nil {
+		return m.UpdateAvatarFunc(ctx, userID, avatarURL)
+	}
+	return "", ErrTest
+}
+
+func (m *MockRepository) CreatePasswordResetToken(ctx context.Context, userID, tokenHash string, expiresAt time.Time) error {
+	if m.CreatePasswordResetTokenFunc != nil {
+		return m.CreatePasswordResetTokenFunc(ctx, userID, tokenHash, expiresAt)
+	}
+	return ErrTest
+}
+
+func (m *MockRepository) ConsumePasswordResetToken(ctx context.Context, tokenHash, passwordHash string) (string, error) {
+	if m.ConsumePasswordResetTokenFunc != nil {
+		return m.ConsumePasswordResetTokenFunc(ctx, tokenHash, passwordHash)
+	}
+	return "", ErrTest
+}
+
+func (m *MockRepository) CreateEmailVerificationToken(ctx context.Context, userID, tokenHash string, expiresAt time.Time) error {
+	if m.CreateVerificationTokenFunc != nil {
+		return m.CreateVerificationTokenFunc(ctx, userID, tokenHash, expiresAt)
+	}
+	return ErrTest
+}
+
+func (m *MockRepository) ConsumeEmailVerificationToken(ctx context.Context, tokenHash string) (string, error) {
+	if m.ConsumeVerificationTokenFunc != nil {
+		return m.ConsumeVerificationTokenFunc(ctx, tokenHash)
+	}
+	return "", ErrTest
+}
+
+// The login lockout methods default to an account that is never locked, so
+// login tests only stub them when they exercise the lockout.
+func (m *MockRepository) GetLoginLockout(ctx context.Context, userID string) (time.Time, error) {
+	if m.GetLoginLockoutFunc != nil {
+		return m.GetLoginLockoutFunc(ctx, userID)
+	}
+	return time.Time{}, nil
+}
+
+func (m *MockRepository) RecordFailedLogin(ctx context.Context, userID string, maxAttempts int, lockFor time.Duration) (time.Time, error) {
+	if m.RecordFailedLoginFunc != nil {
+		return m.RecordFailedLoginFunc(ctx, userID, maxAttempts, lockFor)
+	}
+	return time.Time{}, nil
+}
+
+func (m *MockRepository) ResetFailedLogins(ctx context.Context, userID string) error {
+	if m.ResetFailedLoginsFunc != nil {
+		return m.ResetFailedLoginsFunc(ctx, userID)
+	}
+	return nil
 }
 
 func (m *MockRepository) CreateTopic(ctx context.Context, topic *topic.Topic) error {
@@ -71,6 +194,48 @@ func (m *MockRepository) DeleteTopic(ctx context.Context, userID string, topicID
 	return ErrTest
 }
 
+func (m *MockRepository) RestoreTopic(ctx context.Context, topicID int) error {
+	if m.RestoreTopicFunc != nil {
+		return m.RestoreTopicFunc(ctx, topicID)
+	}
+	return ErrTest
+}
+
+func (m *MockRepository) PublishTopic(ctx context.Context, userID string, topicID int) error {
+	if m.PublishTopicFunc != nil {
+		return m.PublishTopicFunc(ctx, userID, topicID)
+	}
+	return ErrTest
+}
+
+func (m *MockRepository) GetDraftTopics(ctx context.Context, userID string) ([]topic.Topic, error) {
+	if m.GetDraftTopicsFunc != nil {
+		return m.GetDraftTopicsFunc(ctx, userID)
+	}
+	return nil, ErrTest
+}
+
+func (m *MockRepository) IncrementTopicView(ctx context.Context, topicID int, viewerKey string, window time.Duration) (bool, error) {
+	if m.IncrementTopicViewFunc != nil {
+		return m.IncrementTopicViewFunc(ctx, topicID, viewerKey, window)
+	}
+	return false, ErrTest
+}
+
+func (m *MockRepository) ToggleBookmark(ctx context.Context, userID string, topicID int) (bool, error) {
+	if m.ToggleBookmarkFunc != nil {
+		return m.ToggleBookmarkFunc(ctx, userID, topicID)
+	}
+	return false, ErrTest
+}
+
+func (m *MockRepository) GetBookmarkedTopics(ctx context.Context, userID string) ([]topic.Topic, error) {
+	if m.GetBookmarkedTopicsFunc != nil {
+		return m.GetBookmarkedTopicsFunc(ctx, userID)
+	}
+	return nil, ErrTest
+}
+
 func (m *MockRepository) GetTopicByID(ctx context.Context, topicID int, userID *string) (*topic.Topic, error) {
 	if m.GetTopicByIDFunc != nil {
 		return m.GetTopicByIDFunc(ctx, topicID, userID)
@@ -78,9 +243,9 @@ func (m *MockRepository) GetTopicByID(ctx context.Context, topicID int, userID *
 	return nil, ErrTest
 }
 
-func (m *MockRepository) GetAllTopics(ctx context.Context, page, size, categoryID int, orderBy, order, filter string, userID *string) ([]topic.Topic, error) {
+func (m *MockRepository) GetAllTopics(ctx context.Context, page, size, beforeID, categoryID int, orderBy, order, filter string, userID *string, controversy topic.ControversyWeights) ([]topic.Topic, error) {
 	if m.GetAllTopicsFunc != nil {
-		return m.GetAllTopicsFunc(ctx, page, size, categoryID, orderBy, order, filter, userID)
+		return m.GetAllTopicsFunc(ctx, page, size, beforeID, categoryID, orderBy, order, filter, userID, controversy)
 	}
 	return nil, ErrTest
 }
@@ -92,6 +257,76 @@ func (m *MockRepository) GetTotalTopicsCount(ctx context.Context, filter string,
 	return 0, ErrTest
 }
 
+func (m *MockRepository) GetCategoriesRequiringImage(ctx context.Context, categoryIDs []int) ([]string, error) {
+	if m.GetCategoriesRequiringImageFunc != nil {
+		return m.GetCategoriesRequiringImageFunc(ctx, categoryIDs)
+	}
+	return nil, ErrTest
+}
+
+func (m *MockRepository) GetExistingCategoryIDs(ctx context.Context, categoryIDs []int) ([]int, error) {
+	if m.GetExistingCategoryIDsFunc != nil {
+		return m.GetExistingCategoryIDsFunc(ctx, categoryIDs)
+	}
+	return nil, ErrTest
+}
+
+func (m *MockRepository) IsTopicRemoved(ctx context.Context, topicID int, userID *string) (bool, error) {
+	if m.IsTopicRemovedFunc != nil {
+		return m.IsTopicRemovedFunc(ctx, topicID, userID)
+	}
+	return false, ErrTest
+}
+
+func (m *MockRepository) IsTopicVisible(ctx context.Context, topicID int, userID *string) (bool, error) {
+	if m.IsTopicVisibleFunc != nil {
+		return m.IsTopicVisibleFunc(ctx, topicID, userID)
+	}
+	return true, nil
+}
+
+func (m *MockRepository) FindRecentDuplicate(ctx context.Context, userID, title, content string, window time.Duration) (*topic.Topic, error) {
+	if m.FindRecentDuplicateFunc != nil {
+		return m.FindRecentDuplicateFunc(ctx, userID, title, content, window)
+	}
+	return nil, ErrTest
+}
+
+func (m *MockRepository) WatchTopic(ctx context.Context, userID string, topicID int) error {
+	if m.WatchTopicFunc != nil {
+		return m.WatchTopicFunc(ctx, userID, topicID)
+	}
+	return ErrTest
+}
+
+func (m *MockRepository) UnwatchTopic(ctx context.Context, userID string, topicID int) error {
+	if m.UnwatchTopicFunc != nil {
+		return m.UnwatchTopicFunc(ctx, userID, topicID)
+	}
+	return ErrTest
+}
+
+func (m *MockRepository) GetTopicWatchers(ctx context.Context, topicID int) ([]string, error) {
+	if m.GetTopicWatchersFunc != nil {
+		return m.GetTopicWatchersFunc(ctx, topicID)
+	}
+	return nil, ErrTest
+}
+
+func (m *MockRepository) GetTopicRevisions(ctx context.Context, topicID int) ([]topic.Revision, error) {
+	if m.GetTopicRevisionsFunc != nil {
+		return m.GetTopicRevisionsFunc(ctx, topicID)
+	}
+	return nil, ErrTest
+}
+
+func (m *MockRepository) SetAcceptedAnswer(ctx context.Context, topicID, commentID int) error {
+	if m.SetAcceptedAnswerFunc != nil {
+		return m.SetAcceptedAnswerFunc(ctx, topicID, commentID)
+	}
+	return ErrTest
+}
+
 type MockUUIDProvider struct {
 	NewUUIDFunc func() string
 }
@@ -123,7 +358,11 @@ type MockSessionManager struct {
 	NewSessionCookieFunc            func(token string) *http.Cookie
 	GetUserFromSessionFunc          func(sessionID string) (*user.User, error)
 	GetSessionFromSessionTokensFunc func(sessionToken, refreshToken string) (*session.Session, error)
+	RotateSessionFunc               func(ctx context.Context, refreshToken string) (*session.Session, error)
 	DeleteSessionWhenNewCreatedFunc func(ctx context.Context, sessionID string, userID string) error
+	ConfirmAuthenticationFunc       func(ctx context.Context, sessionID string) error
+	InvalidateUserSessionsFunc      func(ctx context.Context, userID string) error
+	DeleteExpiredSessionsFunc       func(ctx context.Context) (int64, error)
 }
 
 func (m *MockSessionManager) GetSession(sessionID string) (*session.Session, error) {
@@ -147,6 +386,10 @@ func (m *MockSessionManager) CreateSession(_ context.Context, userID string) (*s
 	return nil, ErrTest
 }
 
+func (m *MockSessionManager) CreateSessionWithTTL(ctx context.Context, userID string, _ time.Duration) (*session.Session, error) {
+	return m.CreateSession(ctx, userID)
+}
+
 func (m *MockSessionManager) ValidateSession(sessionID string) error {
 	if m.GetSessionFunc != nil {
 		_, err := m.GetSessionFunc(sessionID)
@@ -184,9 +427,37 @@ func (m *MockSessionManager) GetSessionFromSessionTokens(sessionToken, refreshTo
 	return nil, ErrTest
 }
 
+func (m *MockSessionManager) RotateSession(ctx context.Context, refreshToken string) (*session.Session, error) {
+	if m.RotateSessionFunc != nil {
+		return m.RotateSessionFunc(ctx, refreshToken)
+	}
+	return nil, ErrTest
+}
+
 func (m *MockSessionManager) DeleteSessionWhenNewCreated(ctx context.Context, sessionID string, userID string) error {
 	if m.DeleteSessionWhenNewCreatedFunc != nil {
 		return m.DeleteSessionWhenNewCreatedFunc(ctx, sessionID, userID)
 	}
 	return ErrTest
 }
+
+func (m *MockSessionManager) ConfirmAuthentication(ctx context.Context, sessionID string) error {
+	if m.ConfirmAuthenticationFunc != nil {
+		return m.ConfirmAuthenticationFunc(ctx, sessionID)
+	}
+	return ErrTest
+}
+
+func (m *MockSessionManager) InvalidateUserSessions(ctx context.Context, userID string) error {
+	if m.InvalidateUserSessionsFunc != nil {
+		return m.InvalidateUserSessionsFunc(ctx, userID)
+	}
+	return ErrTest
+}
+
+func (m *MockSessionManager) DeleteExpiredSessions(ctx context.Context) (int64, error) {
+	if m.DeleteExpiredSessionsFunc != nil {
+		return m.DeleteExpiredSessionsFunc(ctx)
+	}
+	return 0, ErrTest
+}
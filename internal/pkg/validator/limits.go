@@ -0,0 +1,18 @@
+package validator
+
+// ContentLimits caps the length of text users write. Each limit defaults to
+// the matching Max*Length constant.
+type ContentLimits struct {
+	TopicTitle     int
+	TopicContent   int
+	CommentContent int
+}
+
+// DefaultContentLimits returns the limits used when none are configured.
+func DefaultContentLimits() ContentLimits {
+	return ContentLimits{
+		TopicTitle:     MaxTopicTitleLength,
+		TopicContent:   MaxTopicContentLength,
+		CommentContent: MaxCommentContentLength,
+	}
+}
@@ -0,0 +1,80 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestContentLimits_Comments(t *testing.T) {
+	limits := ContentLimits{CommentContent: 20}
+
+	testCases := []struct {
+		name     string
+		validate func(*Validator, any, ContentLimits)
+		content  string
+		wantErr  string
+	}{
+		{
+			name:     "create at the limit",
+			validate: ValidateCreateComment,
+			content:  strings.Repeat("x", 20),
+		},
+		{
+			name:     "create one over the limit",
+			validate: ValidateCreateComment,
+			content:  strings.Repeat("x", 21),
+			wantErr:  "must be 20 characters maximum",
+		},
+		{
+			name:     "edit at the limit",
+			validate: ValidateUpdateComment,
+			content:  strings.Repeat("x", 20),
+		},
+		{
+			name:     "edit one over the limit",
+			validate: ValidateUpdateComment,
+			content:  strings.Repeat("x", 21),
+			wantErr:  "must be 20 characters maximum",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New()
+			tt.validate(v, &struct {
+				Content   string
+				TopicID   int
+				CommentID int
+				ParentID  int
+			}{
+				Content:   tt.content,
+				TopicID:   1,
+				CommentID: 1,
+			}, limits)
+
+			if got := v.Errors["Content"]; got != tt.wantErr {
+				t.Errorf("Content error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestContentLimits_TopicTitle(t *testing.T) {
+	limits := DefaultContentLimits()
+
+	for _, extra := range []int{0, 1} {
+		v := New()
+		ValidateCreateTopic(v, &struct {
+			Title     string
+			Content   string
+			ImagePath string
+		}{
+			Title:   strings.Repeat("x", MaxTopicTitleLength+extra),
+			Content: "Long enough content",
+		}, limits)
+
+		if _, failed := v.Errors["Title"]; failed != (extra > 0) {
+			t.Errorf("title of %d characters: error = %q", MaxTopicTitleLength+extra, v.Errors["Title"])
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package validator
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// PasswordPolicy is the strength every new password must meet: at least
+// MinLength characters and, for each Require flag set, one character of that
+// class. Special means anything that is not a letter or a digit.
+type PasswordPolicy struct {
+	MinLength      int
+	RequireLower   bool
+	RequireUpper   bool
+	RequireDigit   bool
+	RequireSpecial bool
+}
+
+// WeakPasswordError lists the requirements a password falls short of, e.g.
+// "be at least 8 characters" and "include a digit".
+type WeakPasswordError struct {
+	Requirements []string
+}
+
+func (e *WeakPasswordError) Error() string {
+	return "password must " + joinWithAnd(e.Requirements)
+}
+
+// ValidatePasswordStrength returns a *WeakPasswordError naming everything pw
+// is missing under policy, or nil when it is strong enough.
+func ValidatePasswordStrength(pw string, policy PasswordPolicy) error {
+	requirements := unmetRequirements(pw, policy)
+	if len(requirements) == 0 {
+		return nil
+	}
+	return &WeakPasswordError{Requirements: requirements}
+}
+
+// CheckPasswordStrength records the policy failures of pw under key, worded
+// like the other field errors ("must be at least 8 characters ...").
+func (v *Validator) CheckPasswordStrength(key, pw string, policy PasswordPolicy) {
+	requirements := unmetRequirements(pw, policy)
+	v.Check(len(requirements) == 0, key, "must "+joinWithAnd(requirements))
+}
+
+func unmetRequirements(pw string, policy PasswordPolicy) []string {
+	var requirements []string
+	if len([]rune(pw)) < policy.MinLength {
+		requirements = append(requirements, "be at least "+strconv.Itoa(policy.MinLength)+" characters")
+	}
+
+	var hasLower, hasUpper, hasDigit, hasSpecial bool
+	for _, c := range pw {
+		switch {
+		case unicode.IsLower(c):
+			hasLower = true
+		case unicode.IsUpper(c):
+			hasUpper = true
+		case unicode.IsDigit(c):
+			hasDigit = true
+		default:
+			hasSpecial = true
+		}
+	}
+
+	var missing []string
+	if policy.RequireLower && !hasLower {
+		missing = append(missing, "a lowercase letter")
+	}
+	if policy.RequireUpper && !hasUpper {
+		missing = append(missing, "an uppercase letter")
+	}
+	if policy.RequireDigit && !hasDigit {
+		missing = append(missing, "a digit")
+	}
+	if policy.RequireSpecial && !hasSpecial {
+		missing = append(missing, "a special character")
+	}
+	if len(missing) > 0 {
+		requirements = append(requirements, "include "+joinWithAnd(missing))
+	}
+
+	return requirements
+}
+
+// joinWithAnd joins items as "a", "a and b" or "a, b and c".
+func joinWithAnd(items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
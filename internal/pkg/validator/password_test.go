@@ -0,0 +1,83 @@
+package validator
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidatePasswordStrength(t *testing.T) {
+	t.Run("group: password policy", func(t *testing.T) {
+		testCases := newPasswordStrengthTestCases()
+		for _, tt := range testCases {
+			t.Run(tt.name, runPasswordStrengthTest(tt))
+		}
+	})
+}
+
+type passwordStrengthTestCase struct {
+	name     string
+	password string
+	wantErr  string
+	policy   PasswordPolicy
+}
+
+func newPasswordStrengthTestCases() []passwordStrengthTestCase {
+	strict := PasswordPolicy{MinLength: 8, RequireLower: true, RequireUpper: true, RequireDigit: true, RequireSpecial: true}
+
+	return []passwordStrengthTestCase{
+		{
+			name:     "too short and missing a digit",
+			password: "Pass!",
+			policy:   PasswordPolicy{MinLength: 8, RequireDigit: true},
+			wantErr:  "password must be at least 8 characters and include a digit",
+		},
+		{
+			name:     "long enough but missing several classes",
+			password: "alllowercase",
+			policy:   strict,
+			wantErr:  "password must include an uppercase letter, a digit and a special character",
+		},
+		{
+			name:     "only too short",
+			password: "Pa1!",
+			policy:   strict,
+			wantErr:  "password must be at least 8 characters",
+		},
+		{
+			name:     "length counts characters, not bytes",
+			password: "Ünïcödé",
+			policy:   PasswordPolicy{MinLength: 8},
+			wantErr:  "password must be at least 8 characters",
+		},
+		{
+			name:     "strong password passes",
+			password: "Correct-Horse-9",
+			policy:   strict,
+		},
+		{
+			name:     "relaxed policy accepts a plain password",
+			password: "plainpassword",
+			policy:   PasswordPolicy{MinLength: 8},
+		},
+	}
+}
+
+func runPasswordStrengthTest(tt passwordStrengthTestCase) func(*testing.T) {
+	return func(t *testing.T) {
+		err := ValidatePasswordStrength(tt.password, tt.policy)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("ValidatePasswordStrength() error = %v, want nil", err)
+			}
+			return
+		}
+
+		var weak *WeakPasswordError
+		if !errors.As(err, &weak) {
+			t.Fatalf("ValidatePasswordStrength() error = %v, want a *WeakPasswordError", err)
+		}
+		if err.Error() != tt.wantErr {
+			t.Errorf("ValidatePasswordStrength() error = %q, want %q", err.Error(), tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package validator
+
+import (
+	"regexp"
+	"strings"
+)
+
+// UsernameRX is the character set usernames are drawn from: ASCII letters,
+// digits and underscores, with dots and hyphens after the first character.
+// It is also what an @mention can reach.
+var UsernameRX = regexp.MustCompile(`^\w[\w.-]*$`)
+
+// InvalidUsername is the message for a username outside UsernameRX.
+const InvalidUsername = "may only contain letters, digits, underscores, dots and hyphens, and must start with a letter, digit or underscore"
+
+// fallbackUsername stands in for a name with too little left after
+// sanitizing.
+const fallbackUsername = "user"
+
+var markupRX = regexp.MustCompile(`<[^>]*>`)
+
+func validUsername(value any) (bool, string) {
+	str, ok := value.(string)
+	if !ok {
+		return false, InvalidType
+	}
+	return Matches(str, UsernameRX), InvalidUsername
+}
+
+// SanitizeUsername turns a display name from an outside source, such as an
+// OAuth provider, into a username that passes the registration rules: markup
+// and control characters are dropped, runs of whitespace become a single
+// underscore, anything else outside UsernameRX is removed and the result is
+// cut to MaxUsernameLength. Names too short to keep are padded from
+// "user".
+func SanitizeUsername(name string) string {
+	name = markupRX.ReplaceAllString(name, "")
+
+	var b strings.Builder
+	pendingSpace := false
+	for _, r := range name {
+		switch {
+		case isUsernameChar(r):
+			if pendingSpace && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			pendingSpace = false
+			b.WriteRune(r)
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			pendingSpace = true
+		}
+	}
+
+	sanitized := strings.TrimLeft(b.String(), ".-")
+	if len(sanitized) > MaxUsernameLength {
+		sanitized = sanitized[:MaxUsernameLength]
+	}
+	sanitized = strings.TrimRight(sanitized, ".-")
+
+	if len(sanitized) < MinUsernameLength {
+		sanitized = fallbackUsername + sanitized
+	}
+	return sanitized
+}
+
+func isUsernameChar(r rune) bool {
+	return r >= 'a' && r <= 'z' ||
+		r >= 'A' && r <= 'Z' ||
+		r >= '0' && r <= '9' ||
+		r == '_' || r == '.' || r == '-'
+}
@@ -0,0 +1,37 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizeUsername(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "plain name is kept", input: "alice_g", want: "alice_g"},
+		{name: "spaces become one underscore", input: "  Alice   Smith ", want: "Alice_Smith"},
+		{name: "markup is stripped", input: "<script>alert('x')</script>Bob<b>", want: "alertxBob"},
+		{name: "angle brackets without a tag are dropped", input: "a<b c>d < e", want: "ad_e"},
+		{name: "control characters are dropped", input: "eve\x00\x1b[31m", want: "eve31m"},
+		{name: "leading and trailing dots are trimmed", input: "..dot.name--", want: "dot.name"},
+		{name: "non-ASCII letters are dropped", input: "Zoë Ågren", want: "Zo_gren"},
+		{name: "too short is padded", input: "Al", want: "userAl"},
+		{name: "nothing usable", input: "<>!!", want: "user"},
+		{name: "over-long name is cut", input: strings.Repeat("x", MaxUsernameLength+20), want: strings.Repeat("x", MaxUsernameLength)},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SanitizeUsername(tt.input)
+			if got != tt.want {
+				t.Errorf("SanitizeUsername(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+			if !UsernameRX.MatchString(got) || len(got) < MinUsernameLength || len(got) > MaxUsernameLength {
+				t.Errorf("SanitizeUsername(%q) = %q, which registration would refuse", tt.input, got)
+			}
+		})
+	}
+}
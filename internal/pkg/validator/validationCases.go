@@ -14,6 +14,12 @@ const (
 	MaxCategoryNameLength   = 50
 	MinCommentContentLength = 1
 	MaxCommentContentLength = 1000
+	MinReportReasonLength   = 3
+	MaxReportReasonLength   = 50
+	MaxReportDescription    = 500
+	MinRoleRequestReason    = 10
+	MaxRoleRequestReason    = 500
+	MaxRoleRequestNotes     = 500
 )
 
 func ValidateUserRegistration(v *Validator, data any) {
@@ -24,6 +30,7 @@ func ValidateUserRegistration(v *Validator, data any) {
 				required,
 				minLength(MinUsernameLength),
 				maxLength(MaxUsernameLength),
+				validUsername,
 			},
 		},
 		{
@@ -37,12 +44,7 @@ func ValidateUserRegistration(v *Validator, data any) {
 			Field: "Password",
 			Rules: []func(any) (bool, string){
 				required,
-				minLength(MinPasswordLength),
 				maxLength(MaxPasswordLength),
-				hasLower,
-				hasUpper,
-				hasDigit,
-				hasSpecial,
 			},
 		},
 	}
@@ -72,6 +74,93 @@ func ValidateUserLoginEmail(v *Validator, data any) {
 	ValidateStruct(v, data, rules)
 }
 
+func ValidateForgotPassword(v *Validator, data any) {
+	rules := []ValidationRule{
+		{
+			Field: "Email",
+			Rules: []func(any) (bool, string){
+				required,
+				validEmail,
+			},
+		},
+	}
+
+	ValidateStruct(v, data, rules)
+}
+
+func ValidateResetPassword(v *Validator, data any) {
+	rules := []ValidationRule{
+		{
+			Field: "Token",
+			Rules: []func(any) (bool, string){
+				required,
+			},
+		},
+		{
+			Field: "Password",
+			Rules: []func(any) (bool, string){
+				required,
+				maxLength(MaxPasswordLength),
+			},
+		},
+	}
+
+	ValidateStruct(v, data, rules)
+}
+
+func ValidateChangePassword(v *Validator, data any) {
+	rules := []ValidationRule{
+		{
+			Field: "CurrentPassword",
+			Rules: []func(any) (bool, string){
+				required,
+			},
+		},
+		{
+			Field: "NewPassword",
+			Rules: []func(any) (bool, string){
+				required,
+				maxLength(MaxPasswordLength),
+			},
+		},
+	}
+
+	ValidateStruct(v, data, rules)
+}
+
+// ValidateChangeUsername applies the same rules to a new username as
+// registration does.
+func ValidateChangeUsername(v *Validator, data any) {
+	rules := []ValidationRule{
+		{
+			Field: "Username",
+			Rules: []func(any) (bool, string){
+				required,
+				minLength(MinUsernameLength),
+				maxLength(MaxUsernameLength),
+				validUsername,
+			},
+		},
+	}
+
+	ValidateStruct(v, data, rules)
+}
+
+func ValidateChangeAvatar(v *Validator, data any) {
+	rules := []ValidationRule{
+		{
+			Field: "AvatarURL",
+			Rules: []func(any) (bool, string){
+				required,
+				validAvatarPath,
+				validImagePath,
+			},
+		},
+	}
+
+	ValidateStruct(v, data, rules)
+}
+
 func ValidateUserLoginUsername(v *Validator, data any) {
 	rules := []ValidationRule{
 		{
@@ -95,14 +184,14 @@ func ValidateUserLoginUsername(v *Validator, data any) {
 	ValidateStruct(v, data, rules)
 }
 
-func ValidateCreateTopic(v *Validator, data any) {
+func ValidateCreateTopic(v *Validator, data any, limits ContentLimits) {
 	rules := []ValidationRule{
 		{
 			Field: "Title",
 			Rules: []func(any) (bool, string){
 				required,
 				minLength(MinTopicTitleLength),
-				maxLength(MaxTopicTitleLength),
+				maxLength(limits.TopicTitle),
 			},
 		},
 		{
@@ -110,7 +199,7 @@ func ValidateCreateTopic(v *Validator, data any) {
 			Rules: []func(any) (bool, string){
 				required,
 				minLength(MinTopicContentLength),
-				maxLength(MaxTopicContentLength),
+				maxLength(limits.TopicContent),
 			},
 		},
 		{
@@ -140,6 +229,18 @@ func ValidateGetAllTopics(v *Validator, data any) {
 				optional(validOrderBy),
 			},
 		},
+		{
+			Field: "Order",
+			Rules: []func(any) (bool, string){
+				optional(validSortOrder),
+			},
+		},
+		{
+			Field: "Sort",
+			Rules: []func(any) (bool, string){
+				optional(validTopicSort),
+			},
+		},
 		{
 			Field: "Page",
 			Rules: []func(any) (bool, string){
@@ -160,6 +261,28 @@ func ValidateGetAllTopics(v *Validator, data any) {
 	ValidateStruct(v, data, rules)
 }
 
+// ValidateCommentPage checks the CommentsLimit and CommentsOffset used to
+// load a topic's comments a page at a time.
+func ValidateCommentPage(v *Validator, data any) {
+	rules := []ValidationRule{
+		{
+			Field: "CommentsLimit",
+			Rules: []func(any) (bool, string){
+				isPositiveInt,
+				maxInt(MaxPageSize),
+			},
+		},
+		{
+			Field: "CommentsOffset",
+			Rules: []func(any) (bool, string){
+				isNonNegativeInt,
+			},
+		},
+	}
+
+	ValidateStruct(v, data, rules)
+}
+
 func ValidateGetTopic(v *Validator, data any) {
 	rules := []ValidationRule{
 		{
@@ -202,6 +325,26 @@ func ValidateDeleteCategory(v *Validator, data any) {
 	ValidateStruct(v, data, rules)
 }
 
+func ValidateSetCategoryModerator(v *Validator, data any) {
+	rules := []ValidationRule{
+		{
+			Field: "UserID",
+			Rules: []func(any) (bool, string){
+				required,
+			},
+		},
+		{
+			Field: "CategoryID",
+			Rules: []func(any) (bool, string){
+				required,
+				isPositiveInt,
+			},
+		},
+	}
+
+	ValidateStruct(v, data, rules)
+}
+
 func ValidateGetCategoryByID(v *Validator, data any) {
 	rules := []ValidationRule{
 		{
@@ -226,6 +369,93 @@ func ValidateCreateCategory(v *Validator, data any) {
 				maxLength(MaxCategoryNameLength),
 			},
 		},
+		{
+			Field: "Color",
+			Rules: []func(any) (bool, string){
+				validHexColor,
+			},
+		},
+	}
+
+	ValidateStruct(v, data, rules)
+}
+
+func ValidateReportReason(v *Validator, data any) {
+	rules := []ValidationRule{
+		{
+			Field: "Label",
+			Rules: []func(any) (bool, string){
+				required,
+				minLength(MinReportReasonLength),
+				maxLength(MaxReportReasonLength),
+			},
+		},
+	}
+
+	ValidateStruct(v, data, rules)
+}
+
+func ValidateCreateReport(v *Validator, data any) {
+	rules := []ValidationRule{
+		{
+			Field: "TopicID",
+			Rules: []func(any) (bool, string){
+				optionalPositiveInt,
+			},
+		},
+		{
+			Field: "CommentID",
+			Rules: []func(any) (bool, string){
+				optionalPositiveInt,
+			},
+		},
+		{
+			Field: "Reason",
+			Rules: []func(any) (bool, string){
+				required,
+				maxLength(MaxReportReasonLength),
+			},
+		},
+		{
+			Field: "Description",
+			Rules: []func(any) (bool, string){
+				maxLength(MaxReportDescription),
+			},
+		},
+	}
+
+	ValidateStruct(v, data, rules)
+}
+
+func ValidateRoleRequest(v *Validator, data any) {
+	rules := []ValidationRule{
+		{
+			Field: "Reason",
+			Rules: []func(any) (bool, string){
+				required,
+				minLength(MinRoleRequestReason),
+				maxLength(MaxRoleRequestReason),
+			},
+		},
+	}
+
+	ValidateStruct(v, data, rules)
+}
+
+func ValidateDecideRoleRequest(v *Validator, data any) {
+	rules := []ValidationRule{
+		{
+			Field: "RequestID",
+			Rules: []func(any) (bool, string){
+				isPositiveInt,
+			},
+		},
+		{
+			Field: "AdminNotes",
+			Rules: []func(any) (bool, string){
+				maxLength(MaxRoleRequestNotes),
+			},
+		},
 	}
 
 	ValidateStruct(v, data, rules)
@@ -255,6 +485,12 @@ func ValidateGetCommentsByTopic(v *Validator, data any) {
 				isPositiveInt,
 			},
 		},
+		{
+			Field: "Order",
+			Rules: []func(any) (bool, string){
+				validCommentOrder,
+			},
+		},
 	}
 
 	ValidateStruct(v, data, rules)
@@ -274,7 +510,7 @@ func ValidateGetComment(v *Validator, data any) {
 	ValidateStruct(v, data, rules)
 }
 
-func ValidateUpdateComment(v *Validator, data any) {
+func ValidateUpdateComment(v *Validator, data any, limits ContentLimits) {
 	rules := []ValidationRule{
 		{
 			Field: "CommentID",
@@ -288,7 +524,7 @@ func ValidateUpdateComment(v *Validator, data any) {
 			Rules: []func(any) (bool, string){
 				required,
 				minLength(MinCommentContentLength),
-				maxLength(MaxCommentContentLength),
+				maxLength(limits.CommentContent),
 			},
 		},
 	}
@@ -310,7 +546,21 @@ func ValidateDeleteComment(v *Validator, data any) {
 	ValidateStruct(v, data, rules)
 }
 
-func ValidateCreateComment(v *Validator, data any) {
+func ValidateModerateComment(v *Validator, data any) {
+	rules := []ValidationRule{
+		{
+			Field: "CommentID",
+			Rules: []func(any) (bool, string){
+				required,
+				isPositiveInt,
+			},
+		},
+	}
+
+	ValidateStruct(v, data, rules)
+}
+
+func ValidateCreateComment(v *Validator, data any, limits ContentLimits) {
 	rules := []ValidationRule{
 		{
 			Field: "TopicID",
@@ -324,7 +574,13 @@ func ValidateCreateComment(v *Validator, data any) {
 			Rules: []func(any) (bool, string){
 				required,
 				minLength(MinCommentContentLength),
-				maxLength(MaxCommentContentLength),
+				maxLength(limits.CommentContent),
+			},
+		},
+		{
+			Field: "ParentID",
+			Rules: []func(any) (bool, string){
+				optionalPositiveInt,
 			},
 		},
 	}
@@ -7,7 +7,6 @@ import (
 	"regexp"
 	"slices"
 	"strings"
-	"unicode"
 )
 
 const (
@@ -15,6 +14,10 @@ const (
 	InvalidEmail = "invalid email"
 )
 
+// AvatarPathPrefix is where the client stores uploaded avatars; an avatar
+// URL must point inside it.
+const AvatarPathPrefix = "/static/images/uploads/avatars/"
+
 var EmailRX = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+\\/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
 
 type Validator struct {
@@ -139,6 +142,14 @@ func isPositiveInt(value any) (bool, string) {
 	return num > 0, "must be a positive integer"
 }
 
+func isNonNegativeInt(value any) (bool, string) {
+	num, ok := value.(int)
+	if !ok {
+		return false, InvalidType
+	}
+	return num >= 0, "must not be negative"
+}
+
 func maxInt(limit int) func(any) (bool, string) {
 	return func(value any) (bool, string) {
 		num, ok := value.(int)
@@ -149,72 +160,55 @@ func maxInt(limit int) func(any) (bool, string) {
 	}
 }
 
-func hasLower(value any) (bool, string) {
+func validEmail(value any) (bool, string) {
 	str, ok := value.(string)
 	if !ok {
 		return false, InvalidType
 	}
-	for _, c := range str {
-		if unicode.IsLower(c) {
-			return true, ""
-		}
-	}
-	return false, "must contain at least one lowercase letter"
+	return Matches(str, EmailRX), InvalidEmail
 }
 
-func hasUpper(value any) (bool, string) {
-	str, ok := value.(string)
-	if !ok {
-		return false, InvalidType
-	}
-	for _, c := range str {
-		if unicode.IsUpper(c) {
-			return true, ""
-		}
+func (v *Validator) ToStringErrors() string {
+	strError := ""
+	for key, value := range v.Errors {
+		strError += key + ": " + value + " "
 	}
-	return false, "must contain at least one uppercase letter"
+	return strings.TrimSpace(strError)
 }
 
-func hasDigit(value any) (bool, string) {
-	str, ok := value.(string)
-	if !ok {
-		return false, InvalidType
+// FieldErrors returns the collected errors keyed by the json name of the
+// validated struct field, so clients can attach each message to its input.
+func (v *Validator) FieldErrors(data any) map[string]string {
+	fields := make(map[string]string, len(v.Errors))
+
+	typ := reflect.TypeOf(data)
+	for typ != nil && typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
 	}
-	for _, c := range str {
-		if unicode.IsDigit(c) {
-			return true, ""
-		}
+
+	for key, message := range v.Errors {
+		fields[jsonFieldName(typ, key)] = message
 	}
-	return false, "must contain at least one digit"
+
+	return fields
 }
 
-func hasSpecial(value any) (bool, string) {
-	str, ok := value.(string)
-	if !ok {
-		return false, InvalidType
-	}
-	for _, c := range str {
-		if !unicode.IsLower(c) && !unicode.IsUpper(c) && !unicode.IsDigit(c) {
-			return true, ""
-		}
+func jsonFieldName(typ reflect.Type, fieldName string) string {
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return fieldName
 	}
-	return false, "must contain at least one special character"
-}
 
-func validEmail(value any) (bool, string) {
-	str, ok := value.(string)
+	field, ok := typ.FieldByName(fieldName)
 	if !ok {
-		return false, InvalidType
+		return fieldName
 	}
-	return Matches(str, EmailRX), InvalidEmail
-}
 
-func (v *Validator) ToStringErrors() string {
-	strError := ""
-	for key, value := range v.Errors {
-		strError += key + ": " + value + " "
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return fieldName
 	}
-	return strings.TrimSpace(strError)
+
+	return name
 }
 
 func validImagePath(value any) (bool, string) {
@@ -233,6 +227,42 @@ func validImagePath(value any) (bool, string) {
 	return validImageExtensions[ext], "must be a valid image file"
 }
 
+// validAvatarPath accepts an image directly under AvatarPathPrefix.
+func validAvatarPath(value any) (bool, string) {
+	str, ok := value.(string)
+	if !ok {
+		return false, InvalidType
+	}
+	name, found := strings.CutPrefix(str, AvatarPathPrefix)
+	if !found || name == "" || strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+		return false, "must be an uploaded avatar"
+	}
+	return true, ""
+}
+
+// validHexColor accepts an empty value or a CSS hex color of three or six
+// digits, with or without the leading '#'.
+func validHexColor(value any) (bool, string) {
+	str, ok := value.(string)
+	if !ok {
+		return false, InvalidType
+	}
+	if str == "" {
+		return true, ""
+	}
+
+	hex := strings.TrimPrefix(str, "#")
+	if len(hex) != 3 && len(hex) != 6 {
+		return false, "must be a hex color such as #FA6400"
+	}
+	for _, r := range hex {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return false, "must be a hex color such as #FA6400"
+		}
+	}
+	return true, ""
+}
+
 // var validCategories = map[string]bool{
 // 	"General Discussion": true,
 // 	"Feedback":           true,
@@ -266,3 +296,56 @@ func validOrderBy(value any) (bool, string) {
 	}
 	return orderByWhitelist[str], "must be a valid order by field"
 }
+
+func validSortOrder(value any) (bool, string) {
+	str, ok := value.(string)
+	if !ok {
+		return false, InvalidType
+	}
+	return str == "asc" || str == "desc", "must be asc or desc"
+}
+
+func validTopicSort(value any) (bool, string) {
+	topicSortWhitelist := map[string]bool{
+		"newest":         true,
+		"oldest":         true,
+		"most-liked":     true,
+		"most-commented": true,
+		"controversial":  true,
+		"trending":       true,
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		return false, InvalidType
+	}
+	return topicSortWhitelist[str], "must be a valid sort"
+}
+
+func validCommentOrder(value any) (bool, string) {
+	commentOrderWhitelist := map[string]bool{
+		"top":    true,
+		"newest": true,
+		"oldest": true,
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		return false, InvalidType
+	}
+	if str == "" {
+		return true, ""
+	}
+	return commentOrderWhitelist[str], "must be one of top, newest, oldest"
+}
+
+func optionalPositiveInt(value any) (bool, string) {
+	num, ok := value.(*int)
+	if !ok {
+		return false, InvalidType
+	}
+	if num == nil {
+		return true, ""
+	}
+	return *num > 0, "must be a positive integer"
+}